@@ -111,6 +111,9 @@ var typePrefixesByPackage = map[string]string{
 	"debrid":             "Debrid_",
 	"debrid_client":      "DebridClient_",
 	"report":             "Report_",
+	"viewingstats":       "ViewingStats_",
+	"trackprefs":         "Trackprefs_",
+	"watchparty":         "WatchParty_",
 }
 
 func getTypePrefix(packageName string) string {
@@ -337,6 +340,10 @@ func goStructFromStruct(path string, info os.FileInfo, genDecl *ast.GenDecl, nam
 			}
 			continue
 		}
+		// Skip fields that are not serialized
+		if jsonFieldIgnored(field) {
+			continue
+		}
 		// Get fields comments
 		comments := make([]string, 0)
 		if field.Comment != nil && field.Comment.List != nil && len(field.Comment.List) > 0 {
@@ -658,6 +665,14 @@ func jsonFieldName(field *ast.Field) string {
 	return field.Names[0].Name
 }
 
+func jsonFieldIgnored(field *ast.Field) bool {
+	if field.Tag != nil {
+		tag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value[1:len(field.Tag.Value)-1], "\\\"", "\""))
+		return tag.Get("json") == "-"
+	}
+	return false
+}
+
 func jsonFieldOmitEmpty(field *ast.Field) bool {
 	if field.Tag != nil {
 		tag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value[1:len(field.Tag.Value)-1], "\\\"", "\""))
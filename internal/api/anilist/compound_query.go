@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/goccy/go-json"
 	"seanime/internal/util"
+	"slices"
 	"strconv"
 )
 
@@ -113,3 +114,74 @@ fragment baseAnime on Media {
 		episode
 	}
 }`
+
+// fetchMediaTagsChunkSize is the number of media fetched in a single compound query
+const fetchMediaTagsChunkSize = 50
+
+// FetchMediaTags returns the names of the tags of the given media, by media ID.
+// The tags are not part of the anime collection, so they are fetched separately.
+func FetchMediaTags(ids []int) (ret map[int][]string, err error) {
+	ret = make(map[int][]string)
+
+	for chunk := range slices.Chunk(ids, fetchMediaTagsChunkSize) {
+		var query string
+		for _, id := range chunk {
+			query += fmt.Sprintf(`
+		t%d: Media(id: %d) {
+			tags { name }
+		}
+		`, id, id)
+		}
+
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"query":     fmt.Sprintf(CompoundMediaTagsDocument, query),
+			"variables": nil,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := customQuery(requestBody, util.NewLogger())
+		if err != nil {
+			return nil, err
+		}
+
+		var res map[string]*struct {
+			Tags []*struct {
+				Name string `json:"name"`
+			} `json:"tags"`
+		}
+
+		dataB, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(dataB, &res)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range res {
+			id, err := strconv.Atoi(k[1:])
+			if err != nil {
+				return nil, err
+			}
+			tags := make([]string, 0)
+			if v != nil {
+				for _, tag := range v.Tags {
+					if tag != nil {
+						tags = append(tags, tag.Name)
+					}
+				}
+			}
+			ret[id] = tags
+		}
+	}
+
+	return ret, nil
+}
+
+const CompoundMediaTagsDocument = `query CompoundMediaTags {
+%s
+}`
@@ -0,0 +1,279 @@
+package continuity
+
+import (
+	"fmt"
+	"seanime/internal/database/models"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"slices"
+	"time"
+)
+
+const (
+	MaxEpisodeWatchHistoryItems   = 1000
+	EpisodeWatchHistoryBucketName = "episode_watch_history"
+	// WatchLogSessionGap is the time after which watching an episode again creates a new watch log entry.
+	WatchLogSessionGap = 30 * time.Minute
+	// MinResumeRatio is the completion ratio under which an episode is not resumed.
+	MinResumeRatio = 0.05
+)
+
+type (
+	// EpisodeWatchHistoryItem is the last known state of an episode, stored in the file cache.
+	// Unlike WatchHistoryItem, there is one item per episode and items are not removed once the episode is completed.
+	EpisodeWatchHistoryItem struct {
+		Kind          Kind   `json:"kind"`
+		Filepath      string `json:"filepath"`
+		MediaId       int    `json:"mediaId"`
+		EpisodeNumber int    `json:"episodeNumber"`
+		// The current playback time in seconds.
+		CurrentTime float64 `json:"currentTime"`
+		// The duration of the episode in seconds.
+		Duration float64 `json:"duration"`
+		// Whether the episode has been watched to the end at least once.
+		Completed bool `json:"completed"`
+		// Timestamp of when the episode was first watched.
+		TimeAdded time.Time `json:"timeAdded"`
+		// Timestamp of when the episode was last watched.
+		LastWatched time.Time `json:"lastWatched"`
+	}
+
+	EpisodeWatchHistoryItemResponse struct {
+		Item  *EpisodeWatchHistoryItem `json:"item"`
+		Found bool                     `json:"found"`
+	}
+
+	GetWatchLogOptions struct {
+		// Only return the sessions of this media, 0 for all media.
+		MediaId int `json:"mediaId"`
+		// Only return the sessions after this time.
+		From *time.Time `json:"from,omitempty"`
+		// Only return the sessions before this time.
+		To    *time.Time `json:"to,omitempty"`
+		Limit int        `json:"limit"`
+	}
+)
+
+// CanResume returns true if the playback of the episode was stopped midway.
+func (i *EpisodeWatchHistoryItem) CanResume() bool {
+	if i == nil || i.Duration <= 0 {
+		return false
+	}
+	ratio := i.CurrentTime / i.Duration
+	return ratio >= MinResumeRatio && ratio < IgnoreRatioThreshold
+}
+
+func (i *EpisodeWatchHistoryItem) toWatchHistoryItem() *WatchHistoryItem {
+	return &WatchHistoryItem{
+		Kind:          i.Kind,
+		Filepath:      i.Filepath,
+		MediaId:       i.MediaId,
+		EpisodeNumber: i.EpisodeNumber,
+		CurrentTime:   i.CurrentTime,
+		Duration:      i.Duration,
+		TimeAdded:     i.TimeAdded,
+		TimeUpdated:   i.LastWatched,
+	}
+}
+
+func episodeWatchHistoryKey(mediaId int, episodeNumber int) string {
+	return fmt.Sprintf("%d_%d", mediaId, episodeNumber)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// GetEpisodeWatchHistoryItem returns the resume position of an episode.
+// Nothing is returned if the episode was not stopped midway.
+func (m *Manager) GetEpisodeWatchHistoryItem(mediaId int, episodeNumber int) *EpisodeWatchHistoryItemResponse {
+	defer util.HandlePanicInModuleThen("continuity/GetEpisodeWatchHistoryItem", func() {})
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	i, found := m.getEpisodeWatchHistory(mediaId, episodeNumber)
+	if !found || !i.CanResume() {
+		return &EpisodeWatchHistoryItemResponse{
+			Item:  nil,
+			Found: false,
+		}
+	}
+
+	return &EpisodeWatchHistoryItemResponse{
+		Item:  i,
+		Found: true,
+	}
+}
+
+// GetRecentlyWatched returns the last watched episodes, most recent first.
+func (m *Manager) GetRecentlyWatched(limit int) []*EpisodeWatchHistoryItem {
+	defer util.HandlePanicInModuleThen("continuity/GetRecentlyWatched", func() {})
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := m.getEpisodeWatchHistoryItems()
+	if limit > 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+
+	return ret
+}
+
+// GetResumeList returns the episodes that were stopped midway, most recent first.
+func (m *Manager) GetResumeList() []*EpisodeWatchHistoryItem {
+	defer util.HandlePanicInModuleThen("continuity/GetResumeList", func() {})
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ret := make([]*EpisodeWatchHistoryItem, 0)
+	for _, i := range m.getEpisodeWatchHistoryItems() {
+		if i.CanResume() {
+			ret = append(ret, i)
+		}
+	}
+
+	return ret
+}
+
+// GetWatchLog returns the viewing sessions, most recent first.
+func (m *Manager) GetWatchLog(opts *GetWatchLogOptions) ([]*models.WatchLogEntry, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("continuity: Database not set")
+	}
+
+	var from, to time.Time
+	if opts.From != nil {
+		from = *opts.From
+	}
+	if opts.To != nil {
+		to = *opts.To
+	}
+
+	return m.db.GetWatchLogEntries(opts.MediaId, from, to, opts.Limit)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// updateEpisodeWatchHistory saves the state of the episode and logs the viewing session.
+// The caller should hold the lock.
+func (m *Manager) updateEpisodeWatchHistory(opts *UpdateWatchHistoryItemOptions) error {
+	if opts.MediaId == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	completed := opts.Duration > 0 && opts.CurrentTime/opts.Duration >= IgnoreRatioThreshold
+
+	key := episodeWatchHistoryKey(opts.MediaId, opts.EpisodeNumber)
+
+	var i *EpisodeWatchHistoryItem
+	found, _ := m.fileCacher.Get(*m.episodeWatchHistoryFileCacheBucket, key, &i)
+	if !found || i == nil {
+		i = &EpisodeWatchHistoryItem{
+			MediaId:       opts.MediaId,
+			EpisodeNumber: opts.EpisodeNumber,
+			TimeAdded:     now,
+		}
+	}
+	i.Kind = opts.Kind
+	if opts.Filepath != "" {
+		i.Filepath = opts.Filepath
+	}
+	i.CurrentTime = opts.CurrentTime
+	i.Duration = opts.Duration
+	i.Completed = i.Completed || completed
+	i.LastWatched = now
+
+	err := m.fileCacher.Set(*m.episodeWatchHistoryFileCacheBucket, key, i)
+	if err != nil {
+		return fmt.Errorf("continuity: Failed to save episode watch history item: %w", err)
+	}
+
+	if !found {
+		_ = m.trimEpisodeWatchHistoryItems()
+	}
+
+	m.logWatchSession(&LogWatchProgressOptions{
+		MediaId:       opts.MediaId,
+		EpisodeNumber: opts.EpisodeNumber,
+		Kind:          opts.Kind,
+		CurrentTime:   opts.CurrentTime,
+		Duration:      opts.Duration,
+	}, true)
+
+	return nil
+}
+
+func (m *Manager) getEpisodeWatchHistory(mediaId int, episodeNumber int) (ret *EpisodeWatchHistoryItem, exists bool) {
+	defer util.HandlePanicInModuleThen("continuity/getEpisodeWatchHistory", func() {
+		ret = nil
+		exists = false
+	})
+
+	exists, _ = m.fileCacher.Get(*m.episodeWatchHistoryFileCacheBucket, episodeWatchHistoryKey(mediaId, episodeNumber), &ret)
+	return ret, exists && ret != nil
+}
+
+// getEpisodeWatchHistoryItems returns all the episodes, most recently watched first.
+func (m *Manager) getEpisodeWatchHistoryItems() []*EpisodeWatchHistoryItem {
+	items, err := filecache.GetAll[*EpisodeWatchHistoryItem](m.fileCacher, *m.episodeWatchHistoryFileCacheBucket)
+	if err != nil {
+		m.logger.Error().Err(err).Msg("continuity: Failed to get episode watch history")
+		return make([]*EpisodeWatchHistoryItem, 0)
+	}
+
+	ret := make([]*EpisodeWatchHistoryItem, 0, len(items))
+	for _, i := range items {
+		if i != nil {
+			ret = append(ret, i)
+		}
+	}
+	slices.SortFunc(ret, func(a, b *EpisodeWatchHistoryItem) int {
+		return b.LastWatched.Compare(a.LastWatched)
+	})
+
+	return ret
+}
+
+// getResumableEpisode returns the position of the episode to resume from.
+// Items saved before the history was kept per episode are used if the episode numbers match.
+func (m *Manager) getResumableEpisode(mediaId int, episodeNumber int) (*WatchHistoryItem, bool) {
+	if i, found := m.getEpisodeWatchHistory(mediaId, episodeNumber); found {
+		if !i.CanResume() {
+			return nil, false
+		}
+		return i.toWatchHistoryItem(), true
+	}
+
+	i, found := m.getWatchHistory(mediaId)
+	if !found || i.EpisodeNumber != episodeNumber {
+		return nil, false
+	}
+	return i, true
+}
+
+// removes the least recently watched EpisodeWatchHistoryItem from the file cache.
+func (m *Manager) trimEpisodeWatchHistoryItems() error {
+	defer util.HandlePanicInModuleThen("continuity/trimEpisodeWatchHistoryItems", func() {})
+
+	items, err := filecache.GetAll[*EpisodeWatchHistoryItem](m.fileCacher, *m.episodeWatchHistoryFileCacheBucket)
+	if err != nil {
+		return fmt.Errorf("continuity: Failed to get episode watch history items: %w", err)
+	}
+
+	if len(items) > MaxEpisodeWatchHistoryItems {
+		var oldestKey string
+		for key := range items {
+			if oldestKey == "" || items[key].LastWatched.Before(items[oldestKey].LastWatched) {
+				oldestKey = key
+			}
+		}
+		err = m.fileCacher.Delete(*m.episodeWatchHistoryFileCacheBucket, oldestKey)
+		if err != nil {
+			return fmt.Errorf("continuity: Failed to remove oldest episode watch history item: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package continuity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"seanime/internal/database/db"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"testing"
+)
+
+func TestEpisodeWatchHistory(t *testing.T) {
+	logger := util.NewLogger()
+	tempDir := t.TempDir()
+
+	database, err := db.NewDatabase(tempDir, "seanime-test", logger)
+	require.NoError(t, err)
+
+	cacher, err := filecache.NewCacher(filepath.Join(tempDir, "cache"))
+	require.NoError(t, err)
+
+	manager := NewManager(&NewManagerOptions{
+		FileCacher: cacher,
+		Logger:     logger,
+		Database:   database,
+	})
+
+	update := func(mediaId int, episode int, currentTime float64) {
+		err := manager.UpdateWatchHistoryItem(&UpdateWatchHistoryItemOptions{
+			MediaId:       mediaId,
+			EpisodeNumber: episode,
+			CurrentTime:   currentTime,
+			Duration:      100,
+			Kind:          OnlinestreamKind,
+		})
+		require.NoError(t, err)
+	}
+
+	update(1, 3, 40)
+	update(1, 4, 20)
+	update(2, 1, 95)
+	update(2, 2, 1)
+
+	// Episode 3 can still be resumed after episode 4 was opened
+	res := manager.GetEpisodeWatchHistoryItem(1, 3)
+	require.True(t, res.Found)
+	assert.Equal(t, 40., res.Item.CurrentTime)
+	assert.Equal(t, OnlinestreamKind, res.Item.Kind)
+
+	// Completed and barely started episodes are not resumed
+	assert.False(t, manager.GetEpisodeWatchHistoryItem(2, 1).Found)
+	assert.False(t, manager.GetEpisodeWatchHistoryItem(2, 2).Found)
+	assert.False(t, manager.GetEpisodeWatchHistoryItem(3, 1).Found)
+
+	recent := manager.GetRecentlyWatched(3)
+	require.Len(t, recent, 3)
+	assert.Equal(t, [2]int{2, 2}, [2]int{recent[0].MediaId, recent[0].EpisodeNumber})
+	assert.Equal(t, [2]int{2, 1}, [2]int{recent[1].MediaId, recent[1].EpisodeNumber})
+	assert.True(t, recent[1].Completed)
+
+	resume := manager.GetResumeList()
+	require.Len(t, resume, 2)
+	assert.Equal(t, 4, resume[0].EpisodeNumber)
+	assert.Equal(t, 3, resume[1].EpisodeNumber)
+
+	// Updates of the same session do not create new log entries
+	update(1, 3, 60)
+	log, err := manager.GetWatchLog(&GetWatchLogOptions{MediaId: 1})
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	assert.Equal(t, 3, log[1].EpisodeNumber)
+	assert.Equal(t, 60., log[1].Position)
+
+	log, err = manager.GetWatchLog(&GetWatchLogOptions{Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, log, 3)
+}
@@ -26,7 +26,7 @@ type (
 	// WatchHistoryItem are stored in the file cache.
 	// The history is used to resume playback from the last known position.
 	// Item.MediaId and Item.EpisodeNumber are used to identify the media and episode.
-	// Only one Item per MediaId should exist in the history.
+	// Only one Item per MediaId should exist in the history, the state of each episode is kept in EpisodeWatchHistoryItem.
 	WatchHistoryItem struct {
 		Kind Kind `json:"kind"`
 		// Used for MediastreamKind and ExternalPlayerKind.
@@ -133,6 +133,11 @@ func (m *Manager) UpdateWatchHistoryItem(opts *UpdateWatchHistoryItemOptions) (e
 		return fmt.Errorf("continuity: Failed to save watch history item: %w", err)
 	}
 
+	err = m.updateEpisodeWatchHistory(opts)
+	if err != nil {
+		return err
+	}
+
 	// If the item was added, check if we need to remove the oldest item
 	if added {
 		_ = m.trimWatchHistoryItems()
@@ -144,7 +149,7 @@ func (m *Manager) UpdateWatchHistoryItem(opts *UpdateWatchHistoryItemOptions) (e
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // GetExternalPlayerEpisodeWatchHistoryItem is called before launching the external player to get the last known position.
-// Unlike GetWatchHistoryItem, this checks if the episode numbers match.
+// Unlike GetWatchHistoryItem, this returns the position of the episode being played.
 func (m *Manager) GetExternalPlayerEpisodeWatchHistoryItem(path string, isStream bool, episode, mediaId int) (ret *WatchHistoryItemResponse) {
 	defer util.HandlePanicInModuleThen("continuity/GetExternalPlayerEpisodeWatchHistoryItem", func() {})
 
@@ -195,11 +200,11 @@ func (m *Manager) GetExternalPlayerEpisodeWatchHistoryItem(path string, isStream
 			return
 		}
 
-		i, found := m.getWatchHistory(mediaId)
-		if !found || i.EpisodeNumber != episode {
+		i, found := m.getResumableEpisode(mediaId, episode)
+		if !found {
 			m.logger.Trace().
 				Interface("item", i).
-				Msg("continuity: No watch history item found or episode number does not match")
+				Msg("continuity: No watch history item found for the episode")
 			return
 		}
 
@@ -257,11 +262,11 @@ func (m *Manager) GetExternalPlayerEpisodeWatchHistoryItem(path string, isStream
 			return
 		}
 
-		i, found := m.getWatchHistory(lf.MediaId)
-		if !found || i.EpisodeNumber != lf.GetEpisodeNumber() {
+		i, found := m.getResumableEpisode(lf.MediaId, lf.GetEpisodeNumber())
+		if !found {
 			m.logger.Trace().
 				Interface("item", i).
-				Msg("continuity: No watch history item found or episode number does not match")
+				Msg("continuity: No watch history item found for the episode")
 			return
 		}
 
@@ -322,6 +327,15 @@ func (m *Manager) UpdateExternalPlayerEpisodeWatchHistoryItem(currentTime, durat
 	// Save the i
 	_ = m.fileCacher.Set(*m.watchHistoryFileCacheBucket, strconv.Itoa(opts.MediaId), i)
 
+	_ = m.updateEpisodeWatchHistory(&UpdateWatchHistoryItemOptions{
+		CurrentTime:   currentTime,
+		Duration:      duration,
+		MediaId:       opts.MediaId,
+		EpisodeNumber: opts.EpisodeNumber,
+		Filepath:      opts.Filepath,
+		Kind:          ExternalPlayerKind,
+	})
+
 	// If the item was added, check if we need to remove the oldest item
 	if added {
 		_ = m.trimWatchHistoryItems()
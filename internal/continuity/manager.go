@@ -21,9 +21,15 @@ type (
 		fileCacher                  *filecache.Cacher
 		db                          *db.Database
 		watchHistoryFileCacheBucket *filecache.Bucket
+		// Bucket for EpisodeWatchHistoryItem
+		episodeWatchHistoryFileCacheBucket *filecache.Bucket
 
 		externalPlayerEpisodeDetails mo.Option[*ExternalPlayerEpisodeDetails]
 
+		// Watch log entries of the episodes being watched, by episodeWatchHistoryKey
+		watchLogSessions map[string]*watchLogSession
+		watchLogMu       sync.Mutex
+
 		logger   *zerolog.Logger
 		settings *Settings
 		mu       sync.RWMutex
@@ -55,16 +61,19 @@ type (
 // NewManager creates a new Manager, it should be initialized once.
 func NewManager(opts *NewManagerOptions) *Manager {
 	watchHistoryFileCacheBucket := filecache.NewBucket(WatchHistoryBucketName, time.Hour*24*99999)
+	episodeWatchHistoryFileCacheBucket := filecache.NewBucket(EpisodeWatchHistoryBucketName, time.Hour*24*99999)
 
 	ret := &Manager{
-		fileCacher:                  opts.FileCacher,
-		logger:                      opts.Logger,
-		db:                          opts.Database,
-		watchHistoryFileCacheBucket: &watchHistoryFileCacheBucket,
+		fileCacher:                         opts.FileCacher,
+		logger:                             opts.Logger,
+		db:                                 opts.Database,
+		watchHistoryFileCacheBucket:        &watchHistoryFileCacheBucket,
+		episodeWatchHistoryFileCacheBucket: &episodeWatchHistoryFileCacheBucket,
 		settings: &Settings{
 			WatchContinuityEnabled: false,
 		},
 		externalPlayerEpisodeDetails: mo.None[*ExternalPlayerEpisodeDetails](),
+		watchLogSessions:             make(map[string]*watchLogSession),
 	}
 
 	ret.logger.Info().Msg("continuity: Initialized manager")
@@ -0,0 +1,134 @@
+package continuity
+
+import (
+	"seanime/internal/database/models"
+	"time"
+)
+
+const (
+	// watchLogSaveInterval is the minimum time between two saves of the watch log entry of an episode being watched.
+	watchLogSaveInterval = 30 * time.Second
+	// maxPlaybackRate is used to tell playback from seeking, progress faster than this is not counted as watched.
+	maxPlaybackRate = 2.
+)
+
+type (
+	// LogWatchProgressOptions is the progress of an episode being played, reported periodically by the players.
+	LogWatchProgressOptions struct {
+		MediaId       int  `json:"mediaId"`
+		EpisodeNumber int  `json:"episodeNumber"`
+		Kind          Kind `json:"kind,omitempty"`
+		// Where the episode is played from, see viewingstats.Source.
+		Source      string  `json:"source"`
+		CurrentTime float64 `json:"currentTime"` // In seconds
+		Duration    float64 `json:"duration"`    // In seconds
+	}
+
+	// watchLogSession is the watch log entry of an episode being watched.
+	watchLogSession struct {
+		entry      *models.WatchLogEntry
+		lastUpdate time.Time
+		lastSave   time.Time
+	}
+)
+
+// LogWatchProgress adds the time played since the last progress to the watch log entry of the episode.
+// Unlike UpdateWatchHistoryItem, it does not depend on the watch continuity setting and does not change the resume position.
+func (m *Manager) LogWatchProgress(opts *LogWatchProgressOptions) {
+	if m == nil || opts == nil {
+		return
+	}
+	m.logWatchSession(opts, false)
+}
+
+// EndWatchSession saves the watch log entry of the episode, it is called when the player is closed.
+func (m *Manager) EndWatchSession(mediaId int, episodeNumber int) {
+	if m == nil || m.db == nil {
+		return
+	}
+
+	m.watchLogMu.Lock()
+	defer m.watchLogMu.Unlock()
+
+	key := episodeWatchHistoryKey(mediaId, episodeNumber)
+	if s, ok := m.watchLogSessions[key]; ok {
+		m.saveWatchLogSession(s, time.Now())
+		delete(m.watchLogSessions, key)
+	}
+}
+
+// logWatchSession updates the current viewing session of the episode, or creates a new one.
+// The entry is saved periodically unless save is true.
+func (m *Manager) logWatchSession(opts *LogWatchProgressOptions, save bool) {
+	if m.db == nil || opts.MediaId == 0 {
+		return
+	}
+
+	m.watchLogMu.Lock()
+	defer m.watchLogMu.Unlock()
+
+	now := time.Now()
+	m.endStaleWatchLogSessions(now)
+
+	key := episodeWatchHistoryKey(opts.MediaId, opts.EpisodeNumber)
+	s, ok := m.watchLogSessions[key]
+	if !ok {
+		entry, err := m.db.GetLatestWatchLogEntry(opts.MediaId, opts.EpisodeNumber)
+		if err != nil {
+			return
+		}
+		// The episode is watched again
+		if entry == nil || now.Sub(entry.UpdatedAt) > WatchLogSessionGap {
+			entry = &models.WatchLogEntry{
+				MediaId:       opts.MediaId,
+				EpisodeNumber: opts.EpisodeNumber,
+				Position:      opts.CurrentTime,
+			}
+		}
+		s = &watchLogSession{entry: entry, lastUpdate: entry.UpdatedAt}
+		m.watchLogSessions[key] = s
+	}
+
+	// Only count the progress that could have been played since the last update, the rest is seeking
+	played := opts.CurrentTime - s.entry.Position
+	if played > 0 && played <= now.Sub(s.lastUpdate).Seconds()*maxPlaybackRate+1 {
+		s.entry.WatchedSeconds += played
+	}
+	s.lastUpdate = now
+
+	if opts.Kind != "" {
+		s.entry.Kind = string(opts.Kind)
+	}
+	if opts.Source != "" {
+		s.entry.Source = opts.Source
+	}
+	s.entry.Position = opts.CurrentTime
+	if opts.Duration > 0 {
+		s.entry.Duration = opts.Duration
+	}
+
+	completed := opts.Duration > 0 && opts.CurrentTime/opts.Duration >= IgnoreRatioThreshold
+	if completed && !s.entry.Completed {
+		s.entry.Completed = true
+		save = true
+	}
+
+	if save || s.entry.ID == 0 || now.Sub(s.lastSave) >= watchLogSaveInterval {
+		m.saveWatchLogSession(s, now)
+	}
+}
+
+// endStaleWatchLogSessions saves and forgets the sessions that are not updated anymore.
+func (m *Manager) endStaleWatchLogSessions(now time.Time) {
+	for key, s := range m.watchLogSessions {
+		if now.Sub(s.lastUpdate) > WatchLogSessionGap {
+			m.saveWatchLogSession(s, now)
+			delete(m.watchLogSessions, key)
+		}
+	}
+}
+
+func (m *Manager) saveWatchLogSession(s *watchLogSession, now time.Time) {
+	s.lastSave = now
+	_ = m.db.SaveWatchLogEntry(s.entry)
+}
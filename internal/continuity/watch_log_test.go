@@ -0,0 +1,74 @@
+package continuity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"seanime/internal/database/db"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"testing"
+	"time"
+)
+
+func TestLogWatchProgress(t *testing.T) {
+	logger := util.NewLogger()
+	tempDir := t.TempDir()
+
+	database, err := db.NewDatabase(tempDir, "seanime-test", logger)
+	require.NoError(t, err)
+
+	cacher, err := filecache.NewCacher(filepath.Join(tempDir, "cache"))
+	require.NoError(t, err)
+
+	manager := NewManager(&NewManagerOptions{
+		FileCacher: cacher,
+		Logger:     logger,
+		Database:   database,
+	})
+
+	record := func(currentTime float64) {
+		manager.LogWatchProgress(&LogWatchProgressOptions{
+			MediaId:       21,
+			EpisodeNumber: 1071,
+			Kind:          ExternalPlayerKind,
+			Source:        "localfile",
+			CurrentTime:   currentTime,
+			Duration:      1440,
+		})
+	}
+
+	record(100)
+	time.Sleep(100 * time.Millisecond)
+	record(101)
+	// Seeking is not counted
+	record(1000)
+	time.Sleep(100 * time.Millisecond)
+	record(1001)
+
+	manager.EndWatchSession(21, 1071)
+
+	log, err := manager.GetWatchLog(&GetWatchLogOptions{MediaId: 21})
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Equal(t, 2., log[0].WatchedSeconds)
+	assert.Equal(t, 1001., log[0].Position)
+	assert.Equal(t, "localfile", log[0].Source)
+	assert.False(t, log[0].Completed)
+
+	// The watch history updates are logged in the same entry
+	err = manager.UpdateWatchHistoryItem(&UpdateWatchHistoryItemOptions{
+		MediaId:       21,
+		EpisodeNumber: 1071,
+		CurrentTime:   1400,
+		Duration:      1440,
+		Kind:          ExternalPlayerKind,
+	})
+	require.NoError(t, err)
+
+	log, err = manager.GetWatchLog(&GetWatchLogOptions{MediaId: 21})
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Equal(t, "localfile", log[0].Source)
+	assert.True(t, log[0].Completed)
+}
@@ -92,5 +92,8 @@ func (a *App) RefreshMangaCollection() (*anilist.MangaCollection, error) {
 
 	a.SyncManager.SetMangaCollection(mc)
 
+	// Save the collection to MangaAutoDownloader
+	a.MangaAutoDownloader.SetMangaCollection(mc)
+
 	return mc, nil
 }
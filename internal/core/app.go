@@ -12,6 +12,7 @@ import (
 	"seanime/internal/database/models"
 	debrid_client "seanime/internal/debrid/client"
 	discordrpc_presence "seanime/internal/discordrpc/presence"
+	"seanime/internal/dlnaserver"
 	"seanime/internal/doh"
 	"seanime/internal/events"
 	"seanime/internal/extension_playground"
@@ -23,12 +24,19 @@ import (
 	"seanime/internal/library/fillermanager"
 	"seanime/internal/library/playbackmanager"
 	"seanime/internal/library/scanner"
+	"seanime/internal/library/viewingstats"
 	"seanime/internal/manga"
+	manga_autodownloader "seanime/internal/manga/autodownloader"
+	"seanime/internal/mediaplayers/dlna"
+	"seanime/internal/mediaplayers/kodi"
 	"seanime/internal/mediaplayers/mediaplayer"
 	"seanime/internal/mediaplayers/mpchc"
 	"seanime/internal/mediaplayers/mpv"
 	"seanime/internal/mediaplayers/vlc"
 	"seanime/internal/mediastream"
+	"seanime/internal/mediastream/skipdetect"
+	"seanime/internal/mediastream/streamextract"
+	"seanime/internal/mediastream/trackprefs"
 	"seanime/internal/onlinestream"
 	"seanime/internal/platforms/anilist_platform"
 	"seanime/internal/platforms/local_platform"
@@ -42,6 +50,7 @@ import (
 	"seanime/internal/updater"
 	"seanime/internal/util"
 	"seanime/internal/util/filecache"
+	"seanime/internal/watchparty"
 	"sync"
 
 	"github.com/rs/zerolog"
@@ -69,6 +78,8 @@ type (
 			VLC   *vlc.VLC
 			MpcHc *mpchc.MpcHc
 			Mpv   *mpv.Mpv
+			Kodi  *kodi.Kodi
+			Dlna  *dlna.Renderer
 		}
 		MediaPlayerRepository   *mediaplayer.Repository
 		Version                 string
@@ -82,11 +93,18 @@ type (
 		MetadataProvider        metadata.Provider
 		DiscordPresence         *discordrpc_presence.Presence
 		MangaDownloader         *manga.Downloader
+		MangaAutoDownloader     *manga_autodownloader.AutoDownloader
 		ContinuityManager       *continuity.Manager
 		Cleanups                []func()
 		OnFlushLogs             func()
 		MediastreamRepository   *mediastream.Repository
 		TorrentstreamRepository *torrentstream.Repository
+		StreamExtractor         *streamextract.Manager
+		SkipDetector            *skipdetect.Detector
+		TrackPreferences        *trackprefs.Manager
+		DlnaServer              *dlnaserver.Server
+		WatchPartyManager       *watchparty.Manager
+		ViewingStats            *viewingstats.Manager
 		FeatureFlags            FeatureFlags
 		SecondarySettings       struct {
 			Mediastream   *models.MediastreamSettings
@@ -296,11 +314,18 @@ func NewApp(configOpts *ConfigOptions, selfupdater *updater.SelfUpdater) *App {
 		TorrentRepository:             nil, // Initialized in App.initModulesOnce
 		FillerManager:                 nil, // Initialized in App.initModulesOnce
 		MangaDownloader:               nil, // Initialized in App.initModulesOnce
+		MangaAutoDownloader:           nil, // Initialized in App.initModulesOnce
 		PlaybackManager:               nil, // Initialized in App.initModulesOnce
 		AutoDownloader:                nil, // Initialized in App.initModulesOnce
 		AutoScanner:                   nil, // Initialized in App.initModulesOnce
 		MediastreamRepository:         nil, // Initialized in App.initModulesOnce
 		TorrentstreamRepository:       nil, // Initialized in App.initModulesOnce
+		StreamExtractor:               nil, // Initialized in App.initModulesOnce
+		SkipDetector:                  nil, // Initialized in App.initModulesOnce
+		TrackPreferences:              nil, // Initialized in App.initModulesOnce
+		DlnaServer:                    nil, // Initialized in App.initModulesOnce
+		WatchPartyManager:             nil, // Initialized in App.initModulesOnce
+		ViewingStats:                  nil, // Initialized in App.initModulesOnce
 		ContinuityManager:             nil, // Initialized in App.initModulesOnce
 		DebridClientRepository:        nil, // Initialized in App.initModulesOnce
 		TorrentClientRepository:       nil, // Initialized in App.InitOrRefreshModules
@@ -7,17 +7,26 @@ import (
 	"seanime/internal/database/models"
 	debrid_client "seanime/internal/debrid/client"
 	discordrpc_presence "seanime/internal/discordrpc/presence"
+	"seanime/internal/dlnaserver"
 	"seanime/internal/events"
+	"seanime/internal/library/anime"
 	"seanime/internal/library/autodownloader"
 	"seanime/internal/library/autoscanner"
 	"seanime/internal/library/fillermanager"
 	"seanime/internal/library/playbackmanager"
+	"seanime/internal/library/viewingstats"
 	"seanime/internal/manga"
+	manga_autodownloader "seanime/internal/manga/autodownloader"
+	"seanime/internal/mediaplayers/dlna"
+	"seanime/internal/mediaplayers/kodi"
 	"seanime/internal/mediaplayers/mediaplayer"
 	"seanime/internal/mediaplayers/mpchc"
 	"seanime/internal/mediaplayers/mpv"
 	"seanime/internal/mediaplayers/vlc"
 	"seanime/internal/mediastream"
+	"seanime/internal/mediastream/skipdetect"
+	"seanime/internal/mediastream/streamextract"
+	"seanime/internal/mediastream/trackprefs"
 	"seanime/internal/notifier"
 	"seanime/internal/plugin"
 	"seanime/internal/torrent_clients/qbittorrent"
@@ -25,6 +34,7 @@ import (
 	"seanime/internal/torrent_clients/transmission"
 	"seanime/internal/torrents/torrent"
 	"seanime/internal/torrentstream"
+	"seanime/internal/watchparty"
 
 	"github.com/cli/browser"
 )
@@ -80,6 +90,39 @@ func (a *App) initModulesOnce() {
 		Database:   a.Database,
 	})
 
+	// +---------------------+
+	// |    Skip Detector    |
+	// +---------------------+
+
+	// Detects the intro and outro of local files
+	a.SkipDetector = skipdetect.NewDetector(&skipdetect.NewDetectorOptions{
+		Logger:         a.Logger,
+		Database:       a.Database,
+		WSEventManager: a.WSEventManager,
+		FileCacher:     a.FileCacher,
+	})
+
+	// +---------------------+
+	// |  Track Preferences  |
+	// +---------------------+
+
+	// Selects the preferred audio and subtitle tracks in the players
+	a.TrackPreferences = trackprefs.NewManager(&trackprefs.NewManagerOptions{
+		Logger:     a.Logger,
+		Database:   a.Database,
+		FileCacher: a.FileCacher,
+	})
+
+	// +---------------------+
+	// |    Viewing Stats    |
+	// +---------------------+
+
+	// Computes the local viewing statistics from the watch log
+	a.ViewingStats = viewingstats.NewManager(&viewingstats.NewManagerOptions{
+		Database: a.Database,
+		Logger:   a.Logger,
+	})
+
 	// +---------------------+
 	// |   Playback Manager  |
 	// +---------------------+
@@ -93,9 +136,32 @@ func (a *App) initModulesOnce() {
 		DiscordPresence:   a.DiscordPresence,
 		IsOffline:         a.IsOffline(),
 		ContinuityManager: a.ContinuityManager,
+		SkipDetector:      a.SkipDetector,
+		FillerManager:     a.FillerManager,
+		RefreshAnimeCollectionFunc: func() {
+			_, _ = a.RefreshAnimeCollection()
+		},
+	})
+
+	// +---------------------+
+	// |     DLNA Server     |
+	// +---------------------+
+
+	// Lets devices on the local network browse the library
+	a.DlnaServer = dlnaserver.NewServer(&dlnaserver.NewServerOptions{
+		Logger:            a.Logger,
+		Database:          a.Database,
+		Platform:          a.AnilistPlatform,
+		MetadataProvider:  a.MetadataProvider,
+		ContinuityManager: a.ContinuityManager,
 		RefreshAnimeCollectionFunc: func() {
 			_, _ = a.RefreshAnimeCollection()
 		},
+		Port: a.Config.Server.Port,
+	})
+
+	a.AddCleanupFunction(func() {
+		a.DlnaServer.Shutdown()
 	})
 
 	// +---------------------+
@@ -107,6 +173,16 @@ func (a *App) initModulesOnce() {
 		MetadataProvider: a.MetadataProvider,
 	})
 
+	// +---------------------+
+	// |  Stream Extractor   |
+	// +---------------------+
+
+	// Extracts subtitles and fonts from torrent and debrid streams
+	a.StreamExtractor = streamextract.NewManager(&streamextract.NewManagerOptions{
+		CacheDir: a.Config.Cache.Dir,
+		Logger:   a.Logger,
+	})
+
 	// +---------------------+
 	// |  Debrid Client Repo |
 	// +---------------------+
@@ -119,6 +195,7 @@ func (a *App) initModulesOnce() {
 		Platform:          a.AnilistPlatform,
 		PlaybackManager:   a.PlaybackManager,
 		TorrentRepository: a.TorrentRepository,
+		StreamExtractor:   a.StreamExtractor,
 	})
 
 	// +---------------------+
@@ -176,15 +253,35 @@ func (a *App) initModulesOnce() {
 	}
 
 	// +---------------------+
-	// |    Media Stream     |
+	// | Manga AutoDownloader|
 	// +---------------------+
 
-	a.MediastreamRepository = mediastream.NewRepository(&mediastream.NewRepositoryOptions{
+	a.MangaAutoDownloader = manga_autodownloader.New(&manga_autodownloader.NewAutoDownloaderOptions{
 		Logger:         a.Logger,
+		Database:       a.Database,
+		Repository:     a.MangaRepository,
+		Downloader:     a.MangaDownloader,
 		WSEventManager: a.WSEventManager,
-		FileCacher:     a.FileCacher,
 	})
 
+	if !a.IsOffline() {
+		// This is run in a goroutine
+		a.MangaAutoDownloader.Start()
+	}
+
+	// +---------------------+
+	// |    Media Stream     |
+	// +---------------------+
+
+	a.MediastreamRepository = mediastream.NewRepository(&mediastream.NewRepositoryOptions{
+		Logger:           a.Logger,
+		WSEventManager:   a.WSEventManager,
+		FileCacher:       a.FileCacher,
+		Database:         a.Database,
+		TrackPreferences: a.TrackPreferences,
+	})
+	a.AutoScanner.SetMediastreamRepository(a.MediastreamRepository)
+
 	a.AddCleanupFunction(func() {
 		a.MediastreamRepository.OnCleanup()
 	})
@@ -203,6 +300,42 @@ func (a *App) initModulesOnce() {
 		PlaybackManager:    a.PlaybackManager,
 		WSEventManager:     a.WSEventManager,
 		Database:           a.Database,
+		StreamExtractor:    a.StreamExtractor,
+		FillerManager:      a.FillerManager,
+		ContinuityManager:  a.ContinuityManager,
+	})
+
+	// Playlist items that are not in the library are streamed
+	a.PlaybackManager.SetPlaylistStreamer(anime.PlaylistItemSourceTorrentStream, a.TorrentstreamRepository)
+	a.PlaybackManager.SetPlaylistStreamer(anime.PlaylistItemSourceDebridStream, a.DebridClientRepository)
+	// The stream episode collection is also used for debrid streaming
+	a.PlaybackManager.SetStreamEpisodeCollectionLoader(func(mediaId int) ([]*anime.Episode, error) {
+		ec, err := a.TorrentstreamRepository.NewEpisodeCollection(mediaId)
+		if err != nil {
+			return nil, err
+		}
+		return ec.Episodes, nil
+	})
+
+	// +---------------------+
+	// |     Watch Party     |
+	// +---------------------+
+
+	a.WatchPartyManager = watchparty.NewManager(&watchparty.NewManagerOptions{
+		Logger:         a.Logger,
+		WSEventManager: a.WSEventManager,
+		Player: watchparty.NewLocalPlayer(&watchparty.NewLocalPlayerOptions{
+			Logger:                  a.Logger,
+			WSEventManager:          a.WSEventManager,
+			Database:                a.Database,
+			PlaybackManager:         a.PlaybackManager,
+			TorrentstreamRepository: a.TorrentstreamRepository,
+		}),
+		Port: a.Config.Server.Port,
+	})
+
+	a.AddCleanupFunction(func() {
+		a.WatchPartyManager.Shutdown()
 	})
 
 	plugin.GlobalAppContext.SetModulesPartial(plugin.AppContextModules{
@@ -286,6 +419,21 @@ func (a *App) InitOrRefreshModules() {
 			Logger: a.Logger,
 		}
 		a.MediaPlayer.Mpv = mpv.New(a.Logger, settings.MediaPlayer.MpvSocket, settings.MediaPlayer.MpvPath)
+		a.MediaPlayer.Kodi = &kodi.Kodi{
+			Host:         settings.MediaPlayer.KodiHost,
+			Port:         settings.MediaPlayer.KodiPort,
+			WsPort:       settings.MediaPlayer.KodiWsPort,
+			Username:     settings.MediaPlayer.KodiUsername,
+			Password:     settings.MediaPlayer.KodiPassword,
+			PathMappings: kodi.ParsePathMappings(settings.MediaPlayer.KodiPathMappings),
+			Logger:       a.Logger,
+		}
+		a.MediaPlayer.Dlna = &dlna.Renderer{
+			Location:      settings.MediaPlayer.DlnaRendererLocation,
+			ServerAddress: settings.MediaPlayer.DlnaServerAddress,
+			ServerPort:    a.Config.Server.Port,
+			Logger:        a.Logger,
+		}
 
 		// Set media player repository
 		a.MediaPlayerRepository = mediaplayer.NewRepository(&mediaplayer.NewRepositoryOptions{
@@ -294,13 +442,19 @@ func (a *App) InitOrRefreshModules() {
 			VLC:               a.MediaPlayer.VLC,
 			MpcHc:             a.MediaPlayer.MpcHc,
 			Mpv:               a.MediaPlayer.Mpv, // Socket
+			Kodi:              a.MediaPlayer.Kodi,
+			Dlna:              a.MediaPlayer.Dlna,
 			WSEventManager:    a.WSEventManager,
 			ContinuityManager: a.ContinuityManager,
+			SkipDetector:      a.SkipDetector,
+			TrackPreferences:  a.TrackPreferences,
 		})
 
 		a.PlaybackManager.SetMediaPlayerRepository(a.MediaPlayerRepository)
 		a.PlaybackManager.SetSettings(&playbackmanager.Settings{
 			AutoPlayNextEpisode: a.Settings.Library.AutoPlayNextEpisode,
+			AutoSkipIntro:       a.Settings.Library.AutoSkipIntro,
+			AutoSkipOutro:       a.Settings.Library.AutoSkipOutro,
 		})
 
 		a.TorrentstreamRepository.SetMediaPlayerRepository(a.MediaPlayerRepository)
@@ -377,6 +531,11 @@ func (a *App) InitOrRefreshModules() {
 		a.AutoDownloader.SetSettings(settings.AutoDownloader, settings.Library.TorrentProvider)
 	}
 
+	// Update Manga Auto Downloader - This runs in a goroutine
+	if settings.Manga != nil {
+		a.MangaAutoDownloader.SetSettings(settings.Manga)
+	}
+
 	// +---------------------+
 	// |   Library Watcher   |
 	// +---------------------+
@@ -406,6 +565,28 @@ func (a *App) InitOrRefreshModules() {
 		})
 	}
 
+	// +---------------------+
+	// |       Filler        |
+	// +---------------------+
+
+	if settings.Library != nil {
+		a.FillerManager.SetSettings(&fillermanager.Settings{
+			SkipFillerEpisodes:         settings.Library.SkipFillerEpisodes,
+			MarkSkippedFillerAsWatched: settings.Library.MarkSkippedFillerAsWatched,
+		})
+	}
+
+	// +---------------------+
+	// |     DLNA Server     |
+	// +---------------------+
+
+	if settings.Library != nil {
+		a.DlnaServer.SetSettings(&dlnaserver.Settings{
+			Enabled:      settings.Library.EnableDlnaServer,
+			FriendlyName: settings.Library.DlnaServerName,
+		})
+	}
+
 	runtime.GC()
 
 	a.Logger.Info().Msg("app: Refreshed modules")
@@ -426,10 +607,12 @@ func (a *App) InitOrRefreshMediastreamSettings() {
 			BaseModel: models.BaseModel{
 				ID: 1,
 			},
-			TranscodeEnabled:    false,
-			TranscodeHwAccel:    "cpu",
-			TranscodePreset:     "fast",
-			PreTranscodeEnabled: false,
+			TranscodeEnabled:     false,
+			TranscodeHwAccel:     "cpu",
+			TranscodePreset:      "fast",
+			PreTranscodeEnabled:  false,
+			PreTranscodeQuality:  "medium",
+			PreTranscodeNiceness: 10,
 		})
 		if err != nil {
 			a.Logger.Error().Err(err).Msg("app: Failed to initialize mediastream module")
@@ -438,6 +621,8 @@ func (a *App) InitOrRefreshMediastreamSettings() {
 	}
 
 	a.MediastreamRepository.InitializeModules(settings, a.Config.Cache.Dir, a.Config.Cache.TranscodeDir)
+	a.SkipDetector.SetSettings(settings.FfmpegPath, settings.FfprobePath)
+	a.TrackPreferences.SetSettings(settings.FfprobePath)
 
 	// Cleanup cache
 	go func() {
@@ -467,19 +652,22 @@ func (a *App) InitOrRefreshTorrentstreamSettings() {
 			BaseModel: models.BaseModel{
 				ID: 1,
 			},
-			Enabled:             false,
-			AutoSelect:          true,
-			PreferredResolution: "",
-			DisableIPV6:         false,
-			DownloadDir:         "",
-			AddToLibrary:        false,
-			TorrentClientHost:   "",
-			TorrentClientPort:   43213,
-			StreamingServerHost: "0.0.0.0",
-			StreamingServerPort: 43214,
-			IncludeInLibrary:    false,
-			StreamUrlAddress:    "",
-			SlowSeeding:         false,
+			Enabled:              false,
+			AutoSelect:           true,
+			PreferredResolution:  "",
+			DisableIPV6:          false,
+			DownloadDir:          "",
+			AddToLibrary:         false,
+			TorrentClientHost:    "",
+			TorrentClientPort:    43213,
+			StreamingServerHost:  "0.0.0.0",
+			StreamingServerPort:  43214,
+			IncludeInLibrary:     false,
+			StreamUrlAddress:     "",
+			SlowSeeding:          false,
+			MaxConcurrentStreams: torrentstream.DefaultMaxConcurrentStreams,
+			PrebufferNextEpisode: false,
+			PrebufferThreshold:   torrentstream.DefaultPrebufferThreshold,
 		})
 		if err != nil {
 			a.Logger.Error().Err(err).Msg("app: Failed to initialize mediastream module")
@@ -77,6 +77,8 @@ func migrateTables(db *gorm.DB) error {
 		&models.Mal{},
 		&models.ScanSummary{},
 		&models.AutoDownloaderRule{},
+		&models.AutoDownloaderRuleTemplate{},
+		&models.MangaAutoDownloaderRule{},
 		&models.AutoDownloaderItem{},
 		&models.SilencedMediaEntry{},
 		&models.Theme{},
@@ -84,13 +86,19 @@ func migrateTables(db *gorm.DB) error {
 		&models.ChapterDownloadQueueItem{},
 		&models.TorrentstreamSettings{},
 		&models.TorrentstreamHistory{},
+		&models.TorrentstreamSession{},
 		&models.MediastreamSettings{},
+		&models.MediastreamOptimizationItem{},
+		&models.EpisodeSkipMarkers{},
+		&models.TrackPreferences{},
 		&models.MediaFiller{},
+		&models.FillerPreference{},
 		&models.MangaMapping{},
 		&models.OnlinestreamMapping{},
 		&models.DebridSettings{},
 		&models.DebridTorrentItem{},
 		&models.PluginData{},
+		&models.WatchLogEntry{},
 		//&models.MangaChapterContainer{},
 	)
 	if err != nil {
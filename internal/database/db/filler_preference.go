@@ -0,0 +1,40 @@
+package db
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"seanime/internal/database/models"
+)
+
+// GetFillerPreference returns the filler preference of a media, or nil if the library settings apply.
+func (db *Database) GetFillerPreference(mediaId int) (*models.FillerPreference, error) {
+	var res models.FillerPreference
+	err := db.gormdb.Where("media_id = ?", mediaId).First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		db.Logger.Error().Err(err).Msg("db: Failed to get filler preference")
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// UpsertFillerPreference inserts the filler preference of a media or replaces the existing one.
+func (db *Database) UpsertFillerPreference(pref *models.FillerPreference) error {
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "skip_filler", "mark_as_watched"}),
+	}).Create(pref).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save filler preference")
+		return err
+	}
+	return nil
+}
+
+func (db *Database) DeleteFillerPreference(mediaId int) error {
+	return db.gormdb.Where("media_id = ?", mediaId).Delete(&models.FillerPreference{}).Error
+}
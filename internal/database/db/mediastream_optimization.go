@@ -0,0 +1,91 @@
+package db
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"seanime/internal/database/models"
+)
+
+func (db *Database) GetMediastreamOptimizationItems() ([]*models.MediastreamOptimizationItem, error) {
+	var res []*models.MediastreamOptimizationItem
+	err := db.gormdb.Order("id ASC").Find(&res).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to get mediastream optimization queue")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetNextMediastreamOptimizationItem returns the oldest pending item, or nil if there is none.
+func (db *Database) GetNextMediastreamOptimizationItem() (*models.MediastreamOptimizationItem, error) {
+	var res models.MediastreamOptimizationItem
+	err := db.gormdb.Where("status = ?", "pending").Order("id ASC").First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		db.Logger.Error().Err(err).Msg("db: Failed to get next mediastream optimization item")
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// InsertMediastreamOptimizationItem adds a file to the queue.
+// If the file is already in the queue for the same task, it is queued again unless it is running.
+func (db *Database) InsertMediastreamOptimizationItem(item *models.MediastreamOptimizationItem) error {
+	if item.Filepath == "" {
+		return errors.New("filepath is empty")
+	}
+	if item.Task == "" {
+		return errors.New("task is empty")
+	}
+
+	var existingItem models.MediastreamOptimizationItem
+	err := db.gormdb.Where("filepath = ? AND task = ?", item.Filepath, item.Task).First(&existingItem).Error
+	if err == nil {
+		if existingItem.Status == "running" {
+			return nil
+		}
+		return db.gormdb.Model(&existingItem).Updates(map[string]interface{}{
+			"media_id": item.MediaId,
+			"quality":  item.Quality,
+			"status":   "pending",
+			"progress": 0,
+			"error":    "",
+		}).Error
+	}
+
+	err = db.gormdb.Create(item).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to insert mediastream optimization item")
+		return err
+	}
+	return nil
+}
+
+func (db *Database) UpdateMediastreamOptimizationItemStatus(id uint, status string, progress float64, errStr string) error {
+	return db.gormdb.Model(&models.MediastreamOptimizationItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":   status,
+		"progress": progress,
+		"error":    errStr,
+	}).Error
+}
+
+// ResetRunningMediastreamOptimizationItems queues again the items that were interrupted, e.g. by a restart.
+func (db *Database) ResetRunningMediastreamOptimizationItems() error {
+	return db.gormdb.Model(&models.MediastreamOptimizationItem{}).Where("status = ?", "running").Updates(map[string]interface{}{
+		"status":   "pending",
+		"progress": 0,
+	}).Error
+}
+
+func (db *Database) DeleteMediastreamOptimizationItem(id uint) error {
+	return db.gormdb.Delete(&models.MediastreamOptimizationItem{}, id).Error
+}
+
+// DeleteFinishedMediastreamOptimizationItems removes the items that are done or failed.
+func (db *Database) DeleteFinishedMediastreamOptimizationItems() error {
+	return db.gormdb.Where("status IN ?", []string{"done", "failed"}).Delete(&models.MediastreamOptimizationItem{}).Error
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"seanime/internal/database/models"
+)
+
+// GetEpisodeSkipMarkers returns the skip markers of a file, or nil if the file has not been analyzed.
+func (db *Database) GetEpisodeSkipMarkers(filepath string) (*models.EpisodeSkipMarkers, error) {
+	var res models.EpisodeSkipMarkers
+	err := db.gormdb.Where("filepath = ?", filepath).First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		db.Logger.Error().Err(err).Msg("db: Failed to get episode skip markers")
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (db *Database) GetEpisodeSkipMarkersByMediaId(mediaId int) ([]*models.EpisodeSkipMarkers, error) {
+	var res []*models.EpisodeSkipMarkers
+	err := db.gormdb.Where("media_id = ?", mediaId).Order("episode_number ASC").Find(&res).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to get episode skip markers")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpsertEpisodeSkipMarkers inserts the skip markers of a file or replaces the existing ones.
+func (db *Database) UpsertEpisodeSkipMarkers(markers *models.EpisodeSkipMarkers) error {
+	if markers.Filepath == "" {
+		return errors.New("filepath is empty")
+	}
+
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "filepath"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "media_id", "episode_number", "intro_start", "intro_end", "outro_start", "outro_end", "intro_source", "outro_source"}),
+	}).Create(markers).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save episode skip markers")
+		return err
+	}
+	return nil
+}
+
+func (db *Database) DeleteEpisodeSkipMarkersByMediaId(mediaId int) error {
+	return db.gormdb.Where("media_id = ?", mediaId).Delete(&models.EpisodeSkipMarkers{}).Error
+}
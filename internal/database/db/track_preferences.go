@@ -0,0 +1,52 @@
+package db
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"seanime/internal/database/models"
+)
+
+// GetTrackPreferences returns the track preferences of a media, or nil if none are set.
+// A mediaId of 0 returns the default preferences.
+func (db *Database) GetTrackPreferences(mediaId int) (*models.TrackPreferences, error) {
+	var res models.TrackPreferences
+	err := db.gormdb.Where("media_id = ?", mediaId).First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		db.Logger.Error().Err(err).Msg("db: Failed to get track preferences")
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (db *Database) GetAllTrackPreferences() ([]*models.TrackPreferences, error) {
+	var res []*models.TrackPreferences
+	err := db.gormdb.Order("media_id ASC").Find(&res).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to get track preferences")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpsertTrackPreferences inserts the track preferences of a media or replaces the existing ones.
+func (db *Database) UpsertTrackPreferences(prefs *models.TrackPreferences) error {
+	err := db.gormdb.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "audio_language", "subtitle_language", "subtitle_title_pattern", "subtitle_delay"}),
+	}).Create(prefs).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save track preferences")
+		return err
+	}
+	return nil
+}
+
+func (db *Database) DeleteTrackPreferences(mediaId int) error {
+	return db.gormdb.Where("media_id = ?", mediaId).Delete(&models.TrackPreferences{}).Error
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"seanime/internal/database/models"
+	"time"
+)
+
+// GetLatestWatchLogEntry returns the last viewing session of an episode, or nil if the episode was never watched.
+func (db *Database) GetLatestWatchLogEntry(mediaId int, episodeNumber int) (*models.WatchLogEntry, error) {
+	var res models.WatchLogEntry
+	err := db.gormdb.Where("media_id = ? AND episode_number = ?", mediaId, episodeNumber).Order("updated_at DESC").First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		db.Logger.Error().Err(err).Msg("db: Failed to get watch log entry")
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (db *Database) SaveWatchLogEntry(entry *models.WatchLogEntry) error {
+	err := db.gormdb.Save(entry).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to save watch log entry")
+		return err
+	}
+	return nil
+}
+
+// GetWatchLogEntries returns the viewing sessions, most recent first.
+// A mediaId of 0 returns the sessions of all media, zero times and limit are ignored.
+func (db *Database) GetWatchLogEntries(mediaId int, from time.Time, to time.Time, limit int) ([]*models.WatchLogEntry, error) {
+	query := db.gormdb.Model(&models.WatchLogEntry{})
+	if mediaId != 0 {
+		query = query.Where("media_id = ?", mediaId)
+	}
+	if !from.IsZero() {
+		query = query.Where("updated_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at <= ?", to)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var res []*models.WatchLogEntry
+	err := query.Order("created_at DESC").Find(&res).Error
+	if err != nil {
+		db.Logger.Error().Err(err).Msg("db: Failed to get watch log entries")
+		return nil, err
+	}
+
+	return res, nil
+}
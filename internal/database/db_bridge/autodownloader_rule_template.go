@@ -0,0 +1,83 @@
+package db_bridge
+
+import (
+	"github.com/goccy/go-json"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
+)
+
+func GetAutoDownloaderRuleTemplates(db *db.Database) ([]*anime.AutoDownloaderRuleTemplate, error) {
+
+	var res []*models.AutoDownloaderRuleTemplate
+	err := db.Gorm().Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the data
+	templates := make([]*anime.AutoDownloaderRuleTemplate, 0, len(res))
+	for _, r := range res {
+		var tmpl anime.AutoDownloaderRuleTemplate
+		if err := json.Unmarshal(r.Value, &tmpl); err != nil {
+			return nil, err
+		}
+		tmpl.DbID = r.ID
+		templates = append(templates, &tmpl)
+	}
+
+	return templates, nil
+}
+
+func GetAutoDownloaderRuleTemplate(db *db.Database, id uint) (*anime.AutoDownloaderRuleTemplate, error) {
+	var res models.AutoDownloaderRuleTemplate
+	err := db.Gorm().First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the data
+	var tmpl anime.AutoDownloaderRuleTemplate
+	if err := json.Unmarshal(res.Value, &tmpl); err != nil {
+		return nil, err
+	}
+	tmpl.DbID = res.ID
+
+	return &tmpl, nil
+}
+
+func InsertAutoDownloaderRuleTemplate(db *db.Database, tmpl *anime.AutoDownloaderRuleTemplate) error {
+
+	// Marshal the data
+	bytes, err := json.Marshal(tmpl)
+	if err != nil {
+		return err
+	}
+
+	// Save the data
+	m := &models.AutoDownloaderRuleTemplate{
+		Value: bytes,
+	}
+	if err := db.Gorm().Create(m).Error; err != nil {
+		return err
+	}
+	tmpl.DbID = m.ID
+
+	return nil
+}
+
+func UpdateAutoDownloaderRuleTemplate(db *db.Database, id uint, tmpl *anime.AutoDownloaderRuleTemplate) error {
+
+	// Marshal the data
+	bytes, err := json.Marshal(tmpl)
+	if err != nil {
+		return err
+	}
+
+	// Save the data
+	return db.Gorm().Model(&models.AutoDownloaderRuleTemplate{}).Where("id = ?", id).Update("value", bytes).Error
+}
+
+func DeleteAutoDownloaderRuleTemplate(db *db.Database, id uint) error {
+	return db.Gorm().Delete(&models.AutoDownloaderRuleTemplate{}, id).Error
+}
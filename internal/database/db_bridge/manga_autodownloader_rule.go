@@ -0,0 +1,100 @@
+package db_bridge
+
+import (
+	"github.com/goccy/go-json"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+	"seanime/internal/manga"
+)
+
+func GetMangaAutoDownloaderRules(db *db.Database) ([]*manga.AutoDownloaderRule, error) {
+
+	var res []*models.MangaAutoDownloaderRule
+	err := db.Gorm().Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the data
+	rules := make([]*manga.AutoDownloaderRule, 0, len(res))
+	for _, r := range res {
+		var rule manga.AutoDownloaderRule
+		if err := json.Unmarshal(r.Value, &rule); err != nil {
+			return nil, err
+		}
+		rule.DbID = r.ID
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+func GetMangaAutoDownloaderRule(db *db.Database, id uint) (*manga.AutoDownloaderRule, error) {
+	var res models.MangaAutoDownloaderRule
+	err := db.Gorm().First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the data
+	var rule manga.AutoDownloaderRule
+	if err := json.Unmarshal(res.Value, &rule); err != nil {
+		return nil, err
+	}
+	rule.DbID = res.ID
+
+	return &rule, nil
+}
+
+func GetMangaAutoDownloaderRulesByMediaId(db *db.Database, mediaId int) (ret []*manga.AutoDownloaderRule) {
+	ret = make([]*manga.AutoDownloaderRule, 0)
+
+	rules, err := GetMangaAutoDownloaderRules(db)
+	if err != nil {
+		return
+	}
+
+	for _, rule := range rules {
+		if rule.MediaId == mediaId {
+			ret = append(ret, rule)
+		}
+	}
+
+	return
+}
+
+func InsertMangaAutoDownloaderRule(db *db.Database, rule *manga.AutoDownloaderRule) error {
+
+	// Marshal the data
+	bytes, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	// Save the data
+	m := &models.MangaAutoDownloaderRule{
+		Value: bytes,
+	}
+	if err := db.Gorm().Create(m).Error; err != nil {
+		return err
+	}
+	rule.DbID = m.ID
+
+	return nil
+}
+
+func UpdateMangaAutoDownloaderRule(db *db.Database, id uint, rule *manga.AutoDownloaderRule) error {
+
+	// Marshal the data
+	bytes, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	// Save the data
+	return db.Gorm().Model(&models.MangaAutoDownloaderRule{}).Where("id = ?", id).Update("value", bytes).Error
+}
+
+func DeleteMangaAutoDownloaderRule(db *db.Database, id uint) error {
+	return db.Gorm().Delete(&models.MangaAutoDownloaderRule{}, id).Error
+}
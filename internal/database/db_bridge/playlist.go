@@ -16,11 +16,7 @@ func GetPlaylists(db *db.Database) ([]*anime.Playlist, error) {
 
 	playlists := make([]*anime.Playlist, 0)
 	for _, p := range res {
-		var localFiles []*anime.LocalFile
-		if err := json.Unmarshal(p.Value, &localFiles); err == nil {
-			playlist := anime.NewPlaylist(p.Name)
-			playlist.SetLocalFiles(localFiles)
-			playlist.DbId = p.ID
+		if playlist, err := playlistFromEntry(p); err == nil {
 			playlists = append(playlists, playlist)
 		}
 	}
@@ -28,14 +24,10 @@ func GetPlaylists(db *db.Database) ([]*anime.Playlist, error) {
 }
 
 func SavePlaylist(db *db.Database, playlist *anime.Playlist) error {
-	data, err := json.Marshal(playlist.LocalFiles)
-	if err != nil {
+	playlistEntry := &models.PlaylistEntry{}
+	if err := setPlaylistEntryValues(playlistEntry, playlist); err != nil {
 		return err
 	}
-	playlistEntry := &models.PlaylistEntry{
-		Name:  playlist.Name,
-		Value: data,
-	}
 
 	return db.Gorm().Save(playlistEntry).Error
 }
@@ -45,11 +37,6 @@ func DeletePlaylist(db *db.Database, id uint) error {
 }
 
 func UpdatePlaylist(db *db.Database, playlist *anime.Playlist) error {
-	data, err := json.Marshal(playlist.LocalFiles)
-	if err != nil {
-		return err
-	}
-
 	// Get the playlist entry
 	playlistEntry := &models.PlaylistEntry{}
 	if err := db.Gorm().Where("id = ?", playlist.DbId).First(playlistEntry).Error; err != nil {
@@ -57,8 +44,9 @@ func UpdatePlaylist(db *db.Database, playlist *anime.Playlist) error {
 	}
 
 	// Update the playlist entry
-	playlistEntry.Name = playlist.Name
-	playlistEntry.Value = data
+	if err := setPlaylistEntryValues(playlistEntry, playlist); err != nil {
+		return err
+	}
 
 	return db.Gorm().Save(playlistEntry).Error
 }
@@ -69,14 +57,63 @@ func GetPlaylist(db *db.Database, id uint) (*anime.Playlist, error) {
 		return nil, err
 	}
 
+	return playlistFromEntry(playlistEntry)
+}
+
+func setPlaylistEntryValues(playlistEntry *models.PlaylistEntry, playlist *anime.Playlist) error {
+	data, err := json.Marshal(playlist.LocalFiles)
+	if err != nil {
+		return err
+	}
+
+	var items []byte
+	if len(playlist.Items) > 0 {
+		items, err = json.Marshal(playlist.Items)
+		if err != nil {
+			return err
+		}
+	}
+
+	var rules []byte
+	if playlist.IsSmart() {
+		rules, err = json.Marshal(playlist.Rules)
+		if err != nil {
+			return err
+		}
+	}
+
+	playlistEntry.Name = playlist.Name
+	playlistEntry.Value = data
+	playlistEntry.Items = items
+	playlistEntry.Rules = rules
+	return nil
+}
+
+func playlistFromEntry(playlistEntry *models.PlaylistEntry) (*anime.Playlist, error) {
 	var localFiles []*anime.LocalFile
 	if err := json.Unmarshal(playlistEntry.Value, &localFiles); err != nil {
 		return nil, err
 	}
 
 	playlist := anime.NewPlaylist(playlistEntry.Name)
-	playlist.SetLocalFiles(localFiles)
+	if localFiles != nil {
+		playlist.SetLocalFiles(localFiles)
+	}
 	playlist.DbId = playlistEntry.ID
 
+	if len(playlistEntry.Items) > 0 {
+		if err := json.Unmarshal(playlistEntry.Items, &playlist.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(playlistEntry.Rules) > 0 {
+		var rules anime.SmartPlaylistRules
+		if err := json.Unmarshal(playlistEntry.Rules, &rules); err != nil {
+			return nil, err
+		}
+		playlist.Rules = &rules
+	}
+
 	return playlist, nil
 }
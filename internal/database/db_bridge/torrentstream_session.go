@@ -0,0 +1,39 @@
+package db_bridge
+
+import (
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+)
+
+func GetTorrentstreamSessions(db *db.Database) ([]*models.TorrentstreamSession, error) {
+	var sessions []*models.TorrentstreamSession
+	if err := db.Gorm().Order("updated_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func GetTorrentstreamSession(db *db.Database, sessionId string) (*models.TorrentstreamSession, error) {
+	var session models.TorrentstreamSession
+	if err := db.Gorm().Where("session_id = ?", sessionId).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func UpsertTorrentstreamSession(db *db.Database, sessionId string, value []byte) error {
+	var session models.TorrentstreamSession
+	if err := db.Gorm().Where("session_id = ?", sessionId).First(&session).Error; err == nil {
+		session.Value = value
+		return db.Gorm().Save(&session).Error
+	}
+
+	return db.Gorm().Create(&models.TorrentstreamSession{
+		SessionId: sessionId,
+		Value:     value,
+	}).Error
+}
+
+func DeleteTorrentstreamSession(db *db.Database, sessionId string) error {
+	return db.Gorm().Where("session_id = ?", sessionId).Delete(&models.TorrentstreamSession{}).Error
+}
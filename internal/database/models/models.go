@@ -81,6 +81,15 @@ type LibrarySettings struct {
 	// v2.6+
 	ScannerMatchingThreshold float64 `gorm:"column:scanner_matching_threshold" json:"scannerMatchingThreshold"`
 	ScannerMatchingAlgorithm string  `gorm:"column:scanner_matching_algorithm" json:"scannerMatchingAlgorithm"`
+	// Skip markers
+	AutoSkipIntro bool `gorm:"column:auto_skip_intro" json:"autoSkipIntro"`
+	AutoSkipOutro bool `gorm:"column:auto_skip_outro" json:"autoSkipOutro"`
+	// DLNA server
+	EnableDlnaServer bool   `gorm:"column:enable_dlna_server" json:"enableDlnaServer"`
+	DlnaServerName   string `gorm:"column:dlna_server_name" json:"dlnaServerName"` // Name shown by the devices, "Seanime" if empty
+	// Filler episodes, can be overridden for each media
+	SkipFillerEpisodes         bool `gorm:"column:skip_filler_episodes" json:"skipFillerEpisodes"`                   // Skip filler episodes when playing the next episode, in playlists and random play
+	MarkSkippedFillerAsWatched bool `gorm:"column:mark_skipped_filler_as_watched" json:"markSkippedFillerAsWatched"` // Update the progress on the platform when fillers are skipped
 }
 
 func (o *LibrarySettings) GetLibraryPaths() (ret []string) {
@@ -112,10 +121,14 @@ func (o LibraryPaths) Value() (driver.Value, error) {
 type MangaSettings struct {
 	DefaultProvider    string `gorm:"column:default_manga_provider" json:"defaultMangaProvider"`
 	AutoUpdateProgress bool   `gorm:"column:manga_auto_update_progress" json:"mangaAutoUpdateProgress"`
+	// Check for new chapters using the manga auto downloader rules
+	AutoDownloaderEnabled bool `gorm:"column:manga_auto_downloader_enabled" json:"mangaAutoDownloaderEnabled"`
+	// Interval in minutes between checks (default: 60)
+	AutoDownloaderInterval int `gorm:"column:manga_auto_downloader_interval" json:"mangaAutoDownloaderInterval"`
 }
 
 type MediaPlayerSettings struct {
-	Default     string `gorm:"column:default_player" json:"defaultPlayer"` // "vlc" or "mpc-hc"
+	Default     string `gorm:"column:default_player" json:"defaultPlayer"` // "vlc", "mpc-hc", "mpv", "kodi" or "dlna"
 	Host        string `gorm:"column:player_host" json:"host"`
 	VlcUsername string `gorm:"column:vlc_username" json:"vlcUsername"`
 	VlcPassword string `gorm:"column:vlc_password" json:"vlcPassword"`
@@ -125,6 +138,16 @@ type MediaPlayerSettings struct {
 	MpcPath     string `gorm:"column:mpc_path" json:"mpcPath"`
 	MpvSocket   string `gorm:"column:mpv_socket" json:"mpvSocket"`
 	MpvPath     string `gorm:"column:mpv_path" json:"mpvPath"`
+	// Kodi
+	KodiHost         string `gorm:"column:kodi_host" json:"kodiHost"`
+	KodiPort         int    `gorm:"column:kodi_port" json:"kodiPort"`      // Port of the web server
+	KodiWsPort       int    `gorm:"column:kodi_ws_port" json:"kodiWsPort"` // Port of the JSON-RPC notifications
+	KodiUsername     string `gorm:"column:kodi_username" json:"kodiUsername"`
+	KodiPassword     string `gorm:"column:kodi_password" json:"kodiPassword"`
+	KodiPathMappings string `gorm:"column:kodi_path_mappings" json:"kodiPathMappings"` // One "local=remote" directory mapping per line
+	// DLNA
+	DlnaRendererLocation string `gorm:"column:dlna_renderer_location" json:"dlnaRendererLocation"` // URL of the device description of the renderer
+	DlnaServerAddress    string `gorm:"column:dlna_server_address" json:"dlnaServerAddress"`       // Address of Seanime as seen by the renderer, detected if empty
 }
 
 type TorrentSettings struct {
@@ -196,6 +219,16 @@ type AutoDownloaderRule struct {
 	Value []byte `gorm:"column:value" json:"value"`
 }
 
+type AutoDownloaderRuleTemplate struct {
+	BaseModel
+	Value []byte `gorm:"column:value" json:"value"`
+}
+
+type MangaAutoDownloaderRule struct {
+	BaseModel
+	Value []byte `gorm:"column:value" json:"value"`
+}
+
 type AutoDownloaderItem struct {
 	BaseModel
 	RuleID      uint   `gorm:"column:rule_id" json:"ruleId"`
@@ -216,6 +249,16 @@ type AutoDownloaderSettings struct {
 	EnableEnhancedQueries bool   `gorm:"column:auto_downloader_enable_enhanced_queries" json:"enableEnhancedQueries"`
 	EnableSeasonCheck     bool   `gorm:"column:auto_downloader_enable_season_check" json:"enableSeasonCheck"`
 	UseDebrid             bool   `gorm:"column:auto_downloader_use_debrid" json:"useDebrid"`
+	// Check rules more frequently right after an episode airs, based on the AniList airing schedule
+	UseAiringSchedule bool `gorm:"column:auto_downloader_use_airing_schedule" json:"useAiringSchedule"`
+	// Interval in minutes used right after an episode airs (default: 2)
+	BurstInterval int `gorm:"column:auto_downloader_burst_interval" json:"burstInterval"`
+	// How long in minutes the burst interval is used after an episode airs (default: 120)
+	BurstWindow int `gorm:"column:auto_downloader_burst_window" json:"burstWindow"`
+	// Create and disable rules automatically based on the AniList collection
+	SyncRules bool `gorm:"column:auto_downloader_sync_rules" json:"syncRules"`
+	// Template used to create rules when syncing with the collection
+	SyncRulesTemplateId uint `gorm:"column:auto_downloader_sync_rules_template_id" json:"syncRulesTemplateId"`
 }
 
 // +---------------------+
@@ -286,6 +329,10 @@ type PlaylistEntry struct {
 	BaseModel
 	Name  string `gorm:"column:name" json:"name"`
 	Value []byte `gorm:"column:value" json:"value"`
+	// Items holds the marshaled episode references of playlists that are not limited to local files
+	Items []byte `gorm:"column:items" json:"items"`
+	// Rules holds the marshaled rules of smart playlists, empty for static playlists
+	Rules []byte `gorm:"column:rules" json:"rules"`
 }
 
 // +------------------------+
@@ -321,10 +368,63 @@ type MediastreamSettings struct {
 	FfprobePath                   string `gorm:"column:ffprobe_path" json:"ffprobePath"`
 	// v2.2+
 	TranscodeHwAccelCustomSettings string `gorm:"column:transcode_hw_accel_custom_settings" json:"transcodeHwAccelCustomSettings"`
+	// Pre-transcoding queue
+	PreTranscodeQuality      string `gorm:"column:pre_transcode_quality" json:"preTranscodeQuality"`            // "low", "medium", "high" or "max"
+	PreTranscodeNiceness     int    `gorm:"column:pre_transcode_niceness" json:"preTranscodeNiceness"`          // Scheduling priority of ffmpeg, 0 (normal) to 19 (lowest)
+	PreTranscodeWindowStart  string `gorm:"column:pre_transcode_window_start" json:"preTranscodeWindowStart"`   // "HH:MM", the queue only runs during the window, empty for no restriction
+	PreTranscodeWindowEnd    string `gorm:"column:pre_transcode_window_end" json:"preTranscodeWindowEnd"`       // "HH:MM"
+	PreTranscodeNewDownloads bool   `gorm:"column:pre_transcode_new_downloads" json:"preTranscodeNewDownloads"` // Queue the files added to the library by a scan
 
 	//TranscodeTempDir              string `gorm:"column:transcode_temp_dir" json:"transcodeTempDir"` // DEPRECATED
 }
 
+// MediastreamOptimizationItem is a file in the pre-transcoding queue.
+// A file can be queued once per task.
+type MediastreamOptimizationItem struct {
+	BaseModel
+	Filepath string  `gorm:"column:filepath;uniqueIndex:idx_mediastream_optimization_item" json:"filepath"`
+	Task     string  `gorm:"column:task;uniqueIndex:idx_mediastream_optimization_item;default:optimize" json:"task"` // "optimize" or "trickplay"
+	MediaId  int     `gorm:"column:media_id" json:"mediaId"`
+	Quality  string  `gorm:"column:quality" json:"quality"`
+	Status   string  `gorm:"column:status" json:"status"`     // "pending", "running", "done" or "failed"
+	Progress float64 `gorm:"column:progress" json:"progress"` // 0 to 1
+	Error    string  `gorm:"column:error" json:"error"`
+}
+
+// EpisodeSkipMarkers stores the intro and outro timestamps of a local file, in seconds.
+// A segment is absent if its end is 0.
+type EpisodeSkipMarkers struct {
+	BaseModel
+	Filepath      string  `gorm:"column:filepath;uniqueIndex" json:"filepath"`
+	MediaId       int     `gorm:"column:media_id;index" json:"mediaId"`
+	EpisodeNumber int     `gorm:"column:episode_number" json:"episodeNumber"`
+	IntroStart    float64 `gorm:"column:intro_start" json:"introStart"`
+	IntroEnd      float64 `gorm:"column:intro_end" json:"introEnd"`
+	OutroStart    float64 `gorm:"column:outro_start" json:"outroStart"`
+	OutroEnd      float64 `gorm:"column:outro_end" json:"outroEnd"`
+	IntroSource   string  `gorm:"column:intro_source" json:"introSource"` // "fingerprint", "chapters" or empty
+	OutroSource   string  `gorm:"column:outro_source" json:"outroSource"`
+}
+
+func (m *EpisodeSkipMarkers) HasIntro() bool {
+	return m != nil && m.IntroEnd > m.IntroStart
+}
+
+func (m *EpisodeSkipMarkers) HasOutro() bool {
+	return m != nil && m.OutroEnd > m.OutroStart
+}
+
+// TrackPreferences stores the audio and subtitle tracks to select when playing the episodes of a media.
+// The preferences with a MediaId of 0 are the defaults, used for the fields a media does not set.
+type TrackPreferences struct {
+	BaseModel
+	MediaId              int     `gorm:"column:media_id;uniqueIndex" json:"mediaId"`
+	AudioLanguage        string  `gorm:"column:audio_language" json:"audioLanguage"`                // Comma-separated language codes, by priority, e.g. "ja,en"
+	SubtitleLanguage     string  `gorm:"column:subtitle_language" json:"subtitleLanguage"`          // Comma-separated language codes, by priority
+	SubtitleTitlePattern string  `gorm:"column:subtitle_title_pattern" json:"subtitleTitlePattern"` // Case-insensitive regular expression matched against the track names, e.g. "full|dialogue"
+	SubtitleDelay        float64 `gorm:"column:subtitle_delay" json:"subtitleDelay"`                // In seconds
+}
+
 // +---------------------+
 // |    TorrentStream    |
 // +---------------------+
@@ -347,6 +447,18 @@ type TorrentstreamSettings struct {
 	StreamUrlAddress string `gorm:"column:stream_url_address" json:"streamUrlAddress"`
 	// v2.7+
 	SlowSeeding bool `gorm:"column:slow_seeding" json:"slowSeeding"`
+	// Maximum number of torrents that can be streamed at the same time by different clients
+	MaxConcurrentStreams int `gorm:"column:max_concurrent_streams" json:"maxConcurrentStreams"`
+	// Download the beginning of the next episode once the playback progress reaches the threshold
+	PrebufferNextEpisode bool `gorm:"column:prebuffer_next_episode" json:"prebufferNextEpisode"`
+	PrebufferThreshold   int  `gorm:"column:prebuffer_threshold" json:"prebufferThreshold"` // Percentage
+	// Retention policy of the torrents kept after their stream stopped
+	CacheMaxSize       int  `gorm:"column:cache_max_size" json:"cacheMaxSize"` // In GB, 0 means no limit
+	CacheMaxAge        int  `gorm:"column:cache_max_age" json:"cacheMaxAge"`   // In hours, 0 means no limit
+	CacheKeepInLibrary bool `gorm:"column:cache_keep_in_library" json:"cacheKeepInLibrary"`
+	// Seeding goals, the torrents stop seeding once one of them is reached
+	SeedRatio    float64 `gorm:"column:seed_ratio" json:"seedRatio"`       // 0 means no ratio goal
+	SeedDuration int     `gorm:"column:seed_duration" json:"seedDuration"` // In minutes, 0 means no duration goal
 }
 
 type TorrentstreamHistory struct {
@@ -355,6 +467,13 @@ type TorrentstreamHistory struct {
 	Torrent []byte `gorm:"column:torrent" json:"torrent"`
 }
 
+// TorrentstreamSession is an active torrent stream, persisted so that it can be resumed after a restart.
+type TorrentstreamSession struct {
+	BaseModel
+	SessionId string `gorm:"column:session_id;uniqueIndex" json:"sessionId"`
+	Value     []byte `gorm:"column:value" json:"value"` // JSON-encoded torrentstream.PersistedSession
+}
+
 // +---------------------+
 // |        Filler       |
 // +---------------------+
@@ -368,6 +487,14 @@ type MediaFiller struct {
 	Data          []byte    `gorm:"column:data" json:"data"`
 }
 
+// FillerPreference overrides the "skip filler" library settings for a media.
+type FillerPreference struct {
+	BaseModel
+	MediaId       int  `gorm:"column:media_id;uniqueIndex" json:"mediaId"`
+	SkipFiller    bool `gorm:"column:skip_filler" json:"skipFiller"`
+	MarkAsWatched bool `gorm:"column:mark_as_watched" json:"markAsWatched"` // Update the progress on the platform when fillers are skipped
+}
+
 // +---------------------+
 // |        Manga        |
 // +---------------------+
@@ -430,3 +557,22 @@ type PluginData struct {
 	PluginID string `gorm:"column:plugin_id;index" json:"pluginId"`
 	Data     []byte `gorm:"column:data" json:"data"`
 }
+
+// +---------------------+
+// |     Continuity      |
+// +---------------------+
+
+// WatchLogEntry is a viewing session of an episode.
+// The entry is updated while the episode is being watched, a new entry is created when the episode is watched again later.
+// It is also used for the local viewing statistics, CreatedAt is the start of the session.
+type WatchLogEntry struct {
+	BaseModel
+	MediaId        int     `gorm:"column:media_id;index" json:"mediaId"`
+	EpisodeNumber  int     `gorm:"column:episode_number" json:"episodeNumber"`
+	Kind           string  `gorm:"column:kind" json:"kind"`
+	Source         string  `gorm:"column:source" json:"source"`                  // e.g. "localfile", "torrentstream", empty if unknown
+	Position       float64 `gorm:"column:position" json:"position"`              // Last known playback position, in seconds
+	Duration       float64 `gorm:"column:duration" json:"duration"`              // In seconds
+	WatchedSeconds float64 `gorm:"column:watched_seconds" json:"watchedSeconds"` // Time spent playing, seeking is not counted
+	Completed      bool    `gorm:"column:completed" json:"completed"`
+}
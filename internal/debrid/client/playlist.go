@@ -0,0 +1,22 @@
+package debrid_client
+
+import (
+	"seanime/internal/library/anime"
+)
+
+// CanStreamPlaylistItem implements playbackmanager.PlaylistStreamer.
+func (r *Repository) CanStreamPlaylistItem() bool {
+	return r.HasProvider()
+}
+
+// StreamPlaylistItem implements playbackmanager.PlaylistStreamer.
+// The best torrent is selected automatically and the stream is played with the media player.
+func (r *Repository) StreamPlaylistItem(item *anime.PlaylistItem, aniDBEpisode string) error {
+	return r.StartStream(&StartStreamOptions{
+		MediaId:       item.MediaId,
+		EpisodeNumber: item.EpisodeNumber,
+		AniDBEpisode:  aniDBEpisode,
+		AutoSelect:    true,
+		PlaybackType:  PlaybackTypeDefault,
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"seanime/internal/debrid/torbox"
 	"seanime/internal/events"
 	"seanime/internal/library/playbackmanager"
+	"seanime/internal/mediastream/streamextract"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/torrents/torrent"
 	"seanime/internal/util/result"
@@ -40,6 +41,7 @@ type (
 		completeAnimeCache *anilist.CompleteAnimeCache
 		metadataProvider   metadata.Provider
 		platform           platform.Platform
+		streamExtractor    *streamextract.Manager
 	}
 
 	NewRepositoryOptions struct {
@@ -51,6 +53,7 @@ type (
 		PlaybackManager   *playbackmanager.PlaybackManager
 		MetadataProvider  metadata.Provider
 		Platform          platform.Platform
+		StreamExtractor   *streamextract.Manager
 	}
 )
 
@@ -68,6 +71,7 @@ func NewRepository(opts *NewRepositoryOptions) (ret *Repository) {
 		playbackManager:    opts.PlaybackManager,
 		metadataProvider:   opts.MetadataProvider,
 		completeAnimeCache: anilist.NewCompleteAnimeCache(),
+		streamExtractor:    opts.StreamExtractor,
 		ctxMap:             result.NewResultMap[string, context.CancelFunc](),
 	}
 
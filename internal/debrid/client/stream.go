@@ -10,15 +10,20 @@ import (
 	hibiketorrent "seanime/internal/extension/hibike/torrent"
 	"seanime/internal/hook"
 	"seanime/internal/library/playbackmanager"
+	"seanime/internal/library/viewingstats"
+	"seanime/internal/mediastream/streamextract"
 	"seanime/internal/util"
 	"strconv"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type (
 	StreamManager struct {
 		repository            *Repository
 		currentTorrentItemId  string
+		currentExtractionId   string
 		downloadCtxCancelFunc context.CancelFunc
 	}
 
@@ -30,6 +35,8 @@ type (
 		Status      StreamStatus `json:"status"`
 		TorrentName string       `json:"torrentName"`
 		Message     string       `json:"message"`
+		// ID used to fetch the subtitles and fonts extracted from the stream, set once the stream has started
+		ExtractionId string `json:"extractionId,omitempty"`
 	}
 
 	StartStreamOptions struct {
@@ -72,6 +79,12 @@ const (
 	PlaybackTypeExternalPlayer StreamPlaybackType = "externalPlayerLink"
 )
 
+// newStreamExtractionId returns the ID used to fetch the subtitles and fonts extracted from a stream.
+// Each stream has its own ID so that the files of a previous stream are never served for the current one.
+func newStreamExtractionId() string {
+	return "debridstream-" + uuid.NewString()
+}
+
 // startStream is called by the client to start streaming a torrent
 func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 	defer util.HandlePanicInModuleWithError("debrid/client/StartStream", &err)
@@ -186,6 +199,9 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 
 	// Save the current torrent item id
 	s.currentTorrentItemId = torrentItemId
+	s.stopExtraction()
+	extractionId := newStreamExtractionId()
+	s.currentExtractionId = extractionId
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	s.downloadCtxCancelFunc = cancelCtx
 
@@ -338,6 +354,12 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 			return
 		}
 
+		// Extract the subtitles and fonts so that the web player can display styled subtitles
+		// Files that are not Matroska files are ignored by the extractor
+		if s.repository.streamExtractor != nil {
+			s.repository.streamExtractor.Start(extractionId, streamextract.NewHTTPRangeReaderFunc(streamUrl))
+		}
+
 		switch playbackType {
 		case PlaybackTypeDefault:
 			//
@@ -347,9 +369,10 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 			// Sends the stream to the media player
 			// DEVNOTE: Events are handled by the torrentstream.Repository module
 			err = s.repository.playbackManager.StartStreamingUsingMediaPlayer(windowTitle, &playbackmanager.StartPlayingOptions{
-				Payload:   streamUrl,
-				UserAgent: opts.UserAgent,
-				ClientId:  opts.ClientId,
+				Payload:      streamUrl,
+				UserAgent:    opts.UserAgent,
+				ClientId:     opts.ClientId,
+				StreamSource: viewingstats.SourceDebridStream,
 			}, media, aniDbEpisode)
 			if err != nil {
 				// Failed to start the stream, we'll drop the torrents and stop the server
@@ -389,21 +412,33 @@ func (s *StreamManager) startStream(opts *StartStreamOptions) (err error) {
 	}(ctx)
 
 	s.repository.wsEventManager.SendEvent(events.DebridStreamState, StreamState{
-		Status:      StreamStatusStarted,
-		TorrentName: selectedTorrent.Name,
-		Message:     "Stream started",
+		Status:       StreamStatusStarted,
+		TorrentName:  selectedTorrent.Name,
+		Message:      "Stream started",
+		ExtractionId: extractionId,
 	})
 	s.repository.logger.Info().Msg("debridstream: Stream started")
 
 	return nil
 }
 
+// stopExtraction stops the extraction of the current stream and removes the extracted files.
+func (s *StreamManager) stopExtraction() {
+	if s.repository.streamExtractor == nil || s.currentExtractionId == "" {
+		return
+	}
+	s.repository.streamExtractor.Stop(s.currentExtractionId)
+	s.currentExtractionId = ""
+}
+
 func (s *StreamManager) cancelStream(opts *CancelStreamOptions) {
 	if s.downloadCtxCancelFunc != nil {
 		s.downloadCtxCancelFunc()
 		s.downloadCtxCancelFunc = nil
 	}
 
+	s.stopExtraction()
+
 	if opts.RemoveTorrent && s.currentTorrentItemId != "" {
 		// Remove the torrent from the debrid service
 		provider, err := s.repository.GetProvider()
@@ -0,0 +1,213 @@
+package dlnaserver
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"path/filepath"
+	"seanime/internal/continuity"
+	"seanime/internal/mediaplayers/dlna"
+	"seanime/internal/util"
+	"strconv"
+	"strings"
+)
+
+// dlnaFlags allow seeking with byte ranges and streaming.
+const dlnaFlags = "DLNA.ORG_OP=01;DLNA.ORG_CI=0;DLNA.ORG_FLAGS=01700000000000000000000000000000"
+
+// completionThreshold is the ratio from which a bookmarked episode counts as watched.
+const completionThreshold = 0.8
+
+func (s *Server) handleContentDirectory(w http.ResponseWriter, r *http.Request, action string, args map[string]string) {
+	switch action {
+	case "Browse":
+		res, err := s.browse(r.Host, args)
+		if err != nil {
+			writeSoapFault(w, err)
+			return
+		}
+		writeSoapResponse(w, ContentDirectoryServiceType, action, res)
+	case "GetSystemUpdateID":
+		writeSoapResponse(w, ContentDirectoryServiceType, action, []soapArg{{"Id", strconv.FormatUint(uint64(s.getTree().updateId), 10)}})
+	case "GetSearchCapabilities":
+		writeSoapResponse(w, ContentDirectoryServiceType, action, []soapArg{{"SearchCaps", ""}})
+	case "GetSortCapabilities":
+		writeSoapResponse(w, ContentDirectoryServiceType, action, []soapArg{{"SortCaps", ""}})
+	case "X_SetBookmark":
+		if err := s.setBookmark(args); err != nil {
+			writeSoapFault(w, err)
+			return
+		}
+		writeSoapResponse(w, ContentDirectoryServiceType, action, nil)
+	default:
+		writeSoapFault(w, &upnpError{errInvalidAction, "Invalid action"})
+	}
+}
+
+func (s *Server) browse(host string, args map[string]string) ([]soapArg, *upnpError) {
+	t := s.getTree()
+
+	o, found := t.objects[args["ObjectID"]]
+	if !found {
+		return nil, &upnpError{errNoSuchObject, "No such object"}
+	}
+
+	var objects []*object
+	switch args["BrowseFlag"] {
+	case "BrowseMetadata":
+		objects = []*object{o}
+	case "BrowseDirectChildren":
+		objects = o.Children
+	default:
+		return nil, &upnpError{errInvalidArgs, "Invalid BrowseFlag"}
+	}
+	total := len(objects)
+
+	// Paging, RequestedCount 0 returns all the objects
+	start, _ := strconv.Atoi(args["StartingIndex"])
+	count, _ := strconv.Atoi(args["RequestedCount"])
+	start = min(max(start, 0), total)
+	end := total
+	if count > 0 {
+		end = min(start+count, total)
+	}
+	objects = objects[start:end]
+
+	var result strings.Builder
+	result.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/" xmlns:dlna="urn:schemas-dlna-org:metadata-1-0/" xmlns:sec="http://www.sec.co.kr/">`)
+	for _, obj := range objects {
+		s.writeDidlObject(&result, host, obj)
+	}
+	result.WriteString(`</DIDL-Lite>`)
+
+	return []soapArg{
+		{"Result", result.String()},
+		{"NumberReturned", strconv.Itoa(len(objects))},
+		{"TotalMatches", strconv.Itoa(total)},
+		{"UpdateID", strconv.FormatUint(uint64(t.updateId), 10)},
+	}, nil
+}
+
+func (s *Server) writeDidlObject(b *strings.Builder, host string, o *object) {
+	if o.isContainer() {
+		fmt.Fprintf(b, `<container id="%s" parentID="%s" restricted="1" searchable="0" childCount="%d">`,
+			html.EscapeString(o.Id), html.EscapeString(o.ParentId), len(o.Children))
+		fmt.Fprintf(b, `<dc:title>%s</dc:title>`, html.EscapeString(o.Title))
+		b.WriteString(`<upnp:class>object.container.storageFolder</upnp:class>`)
+		b.WriteString(`</container>`)
+		return
+	}
+
+	fmt.Fprintf(b, `<item id="%s" parentID="%s" restricted="1">`, html.EscapeString(o.Id), html.EscapeString(o.ParentId))
+	fmt.Fprintf(b, `<dc:title>%s</dc:title>`, html.EscapeString(o.Title))
+	b.WriteString(`<upnp:class>object.item.videoItem</upnp:class>`)
+	if o.AlbumArt != "" {
+		fmt.Fprintf(b, `<upnp:albumArtURI>%s</upnp:albumArtURI>`, html.EscapeString(o.AlbumArt))
+	}
+	// Samsung TVs resume from the bookmark
+	if position := s.getBookmark(o); position > 0 {
+		fmt.Fprintf(b, `<sec:dcmInfo>BM=%d</sec:dcmInfo>`, position)
+	}
+
+	// The file is served by the direct play handler of the main server
+	fileUrl := fmt.Sprintf("http://%s/api/v1/mediastream/file/%s", host, util.Base64EncodeStr(o.Path))
+	fmt.Fprintf(b, `<res protocolInfo="http-get:*:%s:%s"`, dlna.MimeType(filepath.Ext(o.Path)), dlnaFlags)
+	if o.Duration > 0 {
+		fmt.Fprintf(b, ` duration="%s"`, formatDuration(o.Duration))
+	}
+	fmt.Fprintf(b, `>%s</res>`, html.EscapeString(fileUrl))
+	b.WriteString(`</item>`)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// getBookmark returns the last known position of the episode, in seconds.
+func (s *Server) getBookmark(o *object) int {
+	if s.continuityManager == nil || o.MediaId == 0 {
+		return 0
+	}
+	res := s.continuityManager.GetWatchHistoryItem(o.MediaId)
+	if res == nil || !res.Found || res.Item == nil {
+		return 0
+	}
+	if res.Item.Filepath != o.Path && (o.EpisodeNumber == 0 || res.Item.EpisodeNumber != o.EpisodeNumber) {
+		return 0
+	}
+	return int(res.Item.CurrentTime)
+}
+
+// setBookmark saves the playback position reported by the renderer in the watch history,
+// and updates the progress on AniList if the episode has been watched.
+func (s *Server) setBookmark(args map[string]string) *upnpError {
+	o, found := s.getTree().objects[args["ObjectID"]]
+	if !found || o.isContainer() {
+		return &upnpError{errNoSuchObject, "No such object"}
+	}
+
+	position, err := strconv.ParseFloat(args["PosSecond"], 64)
+	if err != nil {
+		return &upnpError{errInvalidArgs, "Invalid PosSecond"}
+	}
+
+	s.logger.Debug().Str("path", o.Path).Float64("position", position).Msg("dlna server: Bookmark received")
+
+	if s.continuityManager != nil && s.continuityManager.GetSettings().WatchContinuityEnabled {
+		err := s.continuityManager.UpdateWatchHistoryItem(&continuity.UpdateWatchHistoryItemOptions{
+			CurrentTime:   position,
+			Duration:      o.Duration,
+			MediaId:       o.MediaId,
+			EpisodeNumber: o.EpisodeNumber,
+			Filepath:      o.Path,
+			Kind:          continuity.ExternalPlayerKind,
+		})
+		if err != nil {
+			s.logger.Error().Err(err).Msg("dlna server: Failed to update the watch history")
+			return &upnpError{errActionFailed, "Could not save the bookmark"}
+		}
+	}
+
+	if o.EpisodeNumber > 0 && o.Duration > 0 && position/o.Duration >= completionThreshold {
+		go s.updateProgress(o)
+	}
+
+	return nil
+}
+
+func (s *Server) updateProgress(o *object) {
+	defer util.HandlePanicInModuleThen("dlnaserver/updateProgress", func() {})
+
+	if s.platform == nil {
+		return
+	}
+
+	animeCollection, err := s.platform.GetAnimeCollection(false)
+	if err != nil {
+		return
+	}
+	entry, found := animeCollection.GetListEntryFromAnimeId(o.MediaId)
+	if !found {
+		return
+	}
+	if entry.GetProgress() != nil && *entry.GetProgress() >= o.EpisodeNumber {
+		return
+	}
+
+	totalEpisodes := entry.GetMedia().GetTotalEpisodeCount()
+	if err := s.platform.UpdateEntryProgress(o.MediaId, o.EpisodeNumber, &totalEpisodes); err != nil {
+		s.logger.Error().Err(err).Msg("dlna server: Failed to update the progress on AniList")
+		return
+	}
+
+	s.logger.Info().Int("mediaId", o.MediaId).Int("episode", o.EpisodeNumber).Msg("dlna server: Updated progress on AniList")
+
+	if s.refreshAnimeCollectionFunc != nil {
+		s.refreshAnimeCollectionFunc()
+	}
+}
+
+// formatDuration formats seconds as "H:MM:SS.000".
+func formatDuration(seconds float64) string {
+	sec := int(math.Round(seconds))
+	return fmt.Sprintf("%d:%02d:%02d.000", sec/3600, sec/60%60, sec%60)
+}
@@ -0,0 +1,267 @@
+package dlnaserver
+
+import (
+	"net/http"
+	"os"
+	"seanime/internal/api/metadata"
+	"seanime/internal/continuity"
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/library/anime"
+	"seanime/internal/platforms/platform"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// https://upnp.org/specs/av/UPnP-av-ContentDirectory-v1-Service.pdf
+
+const (
+	// BasePath is the path of the server on the main server.
+	BasePath = "/api/v1/dlna"
+
+	MediaServerDeviceType        = "urn:schemas-upnp-org:device:MediaServer:1"
+	ContentDirectoryServiceType  = "urn:schemas-upnp-org:service:ContentDirectory:1"
+	ConnectionManagerServiceType = "urn:schemas-upnp-org:service:ConnectionManager:1"
+
+	// treeTTL is the duration after which the tree is rebuilt from the library.
+	treeTTL = 30 * time.Second
+)
+
+type (
+	// Server is a UPnP MediaServer that lets devices on the local network, e.g. TVs and consoles, browse the anime library.
+	// It is served by the main server under BasePath and announced with SSDP.
+	// Files are streamed by the direct play handler of the main server.
+	Server struct {
+		logger                     *zerolog.Logger
+		database                   *db.Database
+		platform                   platform.Platform
+		metadataProvider           metadata.Provider
+		continuityManager          *continuity.Manager
+		refreshAnimeCollectionFunc func()
+		port                       int    // Port of the main server
+		udn                        string // Unique device name, stable across restarts
+
+		mu       sync.Mutex
+		settings *Settings
+		ssdp     *ssdpAdvertiser
+		tree     *tree
+		treeTime time.Time
+	}
+
+	Settings struct {
+		Enabled      bool
+		FriendlyName string // Name shown by the devices
+	}
+
+	NewServerOptions struct {
+		Logger                     *zerolog.Logger
+		Database                   *db.Database
+		Platform                   platform.Platform
+		MetadataProvider           metadata.Provider
+		ContinuityManager          *continuity.Manager
+		RefreshAnimeCollectionFunc func()
+		Port                       int
+	}
+)
+
+func NewServer(opts *NewServerOptions) *Server {
+	hostname, _ := os.Hostname()
+	return &Server{
+		logger:                     opts.Logger,
+		database:                   opts.Database,
+		platform:                   opts.Platform,
+		metadataProvider:           opts.MetadataProvider,
+		continuityManager:          opts.ContinuityManager,
+		refreshAnimeCollectionFunc: opts.RefreshAnimeCollectionFunc,
+		port:                       opts.Port,
+		udn:                        "uuid:" + uuid.NewSHA1(uuid.NameSpaceOID, []byte("seanime-dlna-server-"+hostname)).String(),
+		settings:                   &Settings{},
+	}
+}
+
+// SetSettings starts or stops the announcements of the server.
+func (s *Server) SetSettings(settings *Settings) {
+	if s == nil || settings == nil {
+		return
+	}
+
+	if settings.FriendlyName == "" {
+		settings.FriendlyName = "Seanime"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.settings
+	s.settings = settings
+
+	if s.ssdp != nil && (!settings.Enabled || previous.FriendlyName != settings.FriendlyName) {
+		s.ssdp.stop()
+		s.ssdp = nil
+	}
+
+	if settings.Enabled && s.ssdp == nil {
+		ssdp := newSsdpAdvertiser(s.logger, s.udn, s.port)
+		if err := ssdp.start(); err != nil {
+			s.logger.Error().Err(err).Msg("dlna server: Failed to start SSDP announcements")
+			return
+		}
+		s.ssdp = ssdp
+		s.logger.Info().Str("name", settings.FriendlyName).Msg("dlna server: Started")
+	}
+}
+
+func (s *Server) getSettings() *Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings
+}
+
+// Shutdown stops the announcements, it is called when the app exits.
+func (s *Server) Shutdown() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ssdp != nil {
+		s.ssdp.stop()
+		s.ssdp = nil
+	}
+}
+
+// getTree returns the tree of the library, rebuilt when it is older than treeTTL.
+func (s *Server) getTree() *tree {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tree != nil && time.Since(s.treeTime) < treeTTL {
+		return s.tree
+	}
+
+	t, err := s.buildTree()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("dlna server: Failed to build the library tree")
+		if s.tree != nil {
+			return s.tree
+		}
+		t = newTree(nil, nil)
+	}
+
+	t.updateId = uint32(time.Now().Unix())
+	s.tree = t
+	s.treeTime = time.Now()
+	return t
+}
+
+func (s *Server) buildTree() (*tree, error) {
+	animeCollection, err := s.platform.GetAnimeCollection(false)
+	if err != nil {
+		return nil, err
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(s.database)
+	if err != nil {
+		return nil, err
+	}
+
+	if animeCollection == nil {
+		return newTree(nil, lfs), nil
+	}
+
+	libraryCollection, err := anime.NewLibraryCollection(&anime.NewLibraryCollectionOptions{
+		AnimeCollection:  animeCollection,
+		Platform:         s.platform,
+		LocalFiles:       lfs,
+		MetadataProvider: s.metadataProvider,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newTree(libraryCollection, lfs), nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ServeHTTP serves the descriptions and the control endpoints of the services.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.getSettings().Enabled {
+		http.NotFound(w, r)
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, BasePath)
+
+	switch {
+	case p == "/description.xml":
+		writeXml(w, s.deviceDescription())
+	case p == "/ContentDirectory.xml":
+		writeXml(w, contentDirectorySCPD)
+	case p == "/ConnectionManager.xml":
+		writeXml(w, connectionManagerSCPD)
+	case strings.HasPrefix(p, "/event/"):
+		// Events are not sent, subscriptions are accepted so that clients do not give up
+		if r.Method == "SUBSCRIBE" {
+			w.Header().Set("SID", "uuid:"+uuid.NewString())
+			w.Header().Set("TIMEOUT", "Second-1800")
+		}
+		w.WriteHeader(http.StatusOK)
+	case p == "/control/ContentDirectory" || p == "/control/ConnectionManager":
+		s.handleControl(w, r, p)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleControl(w http.ResponseWriter, r *http.Request, p string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	serviceType, action, ok := parseAction(r)
+	if !ok {
+		writeSoapFault(w, &upnpError{errInvalidAction, "Invalid action"})
+		return
+	}
+	args, err := parseArguments(r)
+	if err != nil {
+		writeSoapFault(w, &upnpError{errInvalidArgs, "Invalid arguments"})
+		return
+	}
+
+	s.logger.Trace().Str("action", action).Interface("args", args).Msg("dlna server: Action received")
+
+	switch {
+	case p == "/control/ContentDirectory" && serviceType == ContentDirectoryServiceType:
+		s.handleContentDirectory(w, r, action, args)
+	case p == "/control/ConnectionManager" && serviceType == ConnectionManagerServiceType:
+		s.handleConnectionManager(w, action)
+	default:
+		writeSoapFault(w, &upnpError{errInvalidAction, "Invalid action"})
+	}
+}
+
+func (s *Server) handleConnectionManager(w http.ResponseWriter, action string) {
+	switch action {
+	case "GetProtocolInfo":
+		writeSoapResponse(w, ConnectionManagerServiceType, action, []soapArg{
+			{"Source", "http-get:*:video/x-matroska:*,http-get:*:video/mp4:*,http-get:*:video/x-msvideo:*,http-get:*:video/webm:*"},
+			{"Sink", ""},
+		})
+	case "GetCurrentConnectionIDs":
+		writeSoapResponse(w, ConnectionManagerServiceType, action, []soapArg{{"ConnectionIDs", "0"}})
+	default:
+		writeSoapFault(w, &upnpError{errInvalidAction, "Invalid action"})
+	}
+}
+
+func writeXml(w http.ResponseWriter, content string) {
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	_, _ = w.Write([]byte(content))
+}
@@ -0,0 +1,281 @@
+package dlnaserver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"seanime/internal/api/anilist"
+	"seanime/internal/continuity"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalFile(path string, mediaId int, episode int, t anime.LocalFileType) *anime.LocalFile {
+	return &anime.LocalFile{
+		Path:    path,
+		MediaId: mediaId,
+		Metadata: &anime.LocalFileMetadata{
+			Episode:      episode,
+			AniDBEpisode: fmt.Sprintf("S%d", episode),
+			Type:         t,
+		},
+	}
+}
+
+func newTestMedia(id int, title string, season anilist.MediaSeason, year int) *anilist.BaseAnime {
+	return &anilist.BaseAnime{
+		ID:         id,
+		Title:      &anilist.BaseAnime_Title{UserPreferred: lo.ToPtr(title)},
+		Season:     &season,
+		SeasonYear: &year,
+		Duration:   lo.ToPtr(24),
+	}
+}
+
+func newTestServer(t *testing.T) *Server {
+	lfs := []*anime.LocalFile{
+		newTestLocalFile("/anime/Frieren/Frieren - 02.mkv", 154587, 2, anime.LocalFileTypeMain),
+		newTestLocalFile("/anime/Frieren/Frieren - 01.mkv", 154587, 1, anime.LocalFileTypeMain),
+		newTestLocalFile("/anime/Frieren/Frieren - OVA.mkv", 154587, 1, anime.LocalFileTypeSpecial),
+		newTestLocalFile("/anime/Bocchi/Bocchi - 01.mkv", 130003, 1, anime.LocalFileTypeMain),
+		newTestLocalFile("/anime/Unmatched/file.mkv", 0, 1, anime.LocalFileTypeMain),
+	}
+
+	frieren := newTestMedia(154587, "Sousou no Frieren", anilist.MediaSeasonFall, 2023)
+	bocchi := newTestMedia(130003, "Bocchi the Rock!", anilist.MediaSeasonFall, 2022)
+	lc := &anime.LibraryCollection{
+		Lists: []*anime.LibraryCollectionList{
+			{
+				Status:  anilist.MediaListStatusCurrent,
+				Entries: []*anime.LibraryCollectionEntry{{Media: frieren, MediaId: frieren.ID}},
+			},
+			{
+				Status:  anilist.MediaListStatusCompleted,
+				Entries: []*anime.LibraryCollectionEntry{{Media: bocchi, MediaId: bocchi.ID}},
+			},
+		},
+	}
+
+	fileCacher, err := filecache.NewCacher(t.TempDir())
+	require.NoError(t, err)
+	continuityManager := continuity.NewManager(&continuity.NewManagerOptions{
+		FileCacher: fileCacher,
+		Logger:     util.NewLogger(),
+	})
+	continuityManager.SetSettings(&continuity.Settings{WatchContinuityEnabled: true})
+
+	s := NewServer(&NewServerOptions{
+		Logger:            util.NewLogger(),
+		ContinuityManager: continuityManager,
+		Port:              43211,
+	})
+	s.settings = &Settings{Enabled: true, FriendlyName: "Seanime"}
+	s.tree = newTree(lc, lfs)
+	s.tree.updateId = 1
+	s.treeTime = time.Now().Add(time.Hour) // Never rebuilt during the test
+
+	return s
+}
+
+type didlLite struct {
+	Containers []struct {
+		Id         string `xml:"id,attr"`
+		ParentId   string `xml:"parentID,attr"`
+		ChildCount int    `xml:"childCount,attr"`
+		Title      string `xml:"title"`
+	} `xml:"container"`
+	Items []struct {
+		Id      string `xml:"id,attr"`
+		Title   string `xml:"title"`
+		DcmInfo string `xml:"dcmInfo"`
+		Res     struct {
+			ProtocolInfo string `xml:"protocolInfo,attr"`
+			Duration     string `xml:"duration,attr"`
+			Url          string `xml:",chardata"`
+		} `xml:"res"`
+	} `xml:"item"`
+}
+
+func callAction(t *testing.T, s *Server, serviceType string, path string, action string, args map[string]string) (int, map[string]string) {
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, action, serviceType)
+	for k, v := range args {
+		fmt.Fprintf(&body, "<%s>", k)
+		_ = xml.EscapeText(&body, []byte(v))
+		fmt.Fprintf(&body, "</%s>", k)
+	}
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	req := httptest.NewRequest(http.MethodPost, BasePath+path, strings.NewReader(body.String()))
+	req.Host = "192.168.1.10:43211"
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, serviceType, action))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	res, err := parseArguments(httptest.NewRequest(http.MethodPost, "/", rec.Body))
+	require.NoError(t, err)
+	return rec.Code, res
+}
+
+func browse(t *testing.T, s *Server, objectId string, flag string) (*didlLite, map[string]string) {
+	code, res := callAction(t, s, ContentDirectoryServiceType, "/control/ContentDirectory", "Browse", map[string]string{
+		"ObjectID":       objectId,
+		"BrowseFlag":     flag,
+		"Filter":         "*",
+		"StartingIndex":  "0",
+		"RequestedCount": "0",
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	var didl didlLite
+	require.NoError(t, xml.Unmarshal([]byte(res["Result"]), &didl))
+	return &didl, res
+}
+
+func TestServer_Browse(t *testing.T) {
+	s := newTestServer(t)
+
+	didl, res := browse(t, s, "0", "BrowseDirectChildren")
+	assert.Equal(t, "3", res["TotalMatches"])
+	require.Len(t, didl.Containers, 3)
+	assert.Equal(t, "Currently Watching", didl.Containers[0].Title)
+	assert.Equal(t, 1, didl.Containers[0].ChildCount)
+	assert.Equal(t, "By Title", didl.Containers[1].Title)
+	assert.Equal(t, 2, didl.Containers[1].ChildCount)
+
+	// Titles are sorted, unmatched files are not listed
+	didl, _ = browse(t, s, "titles", "BrowseDirectChildren")
+	require.Len(t, didl.Containers, 2)
+	assert.Equal(t, "Bocchi the Rock!", didl.Containers[0].Title)
+	assert.Equal(t, "Sousou no Frieren", didl.Containers[1].Title)
+	assert.Equal(t, "titles/154587", didl.Containers[1].Id)
+
+	// Seasons, newest first
+	didl, _ = browse(t, s, "seasons", "BrowseDirectChildren")
+	require.Len(t, didl.Containers, 2)
+	assert.Equal(t, "Fall 2023", didl.Containers[0].Title)
+	assert.Equal(t, "Fall 2022", didl.Containers[1].Title)
+
+	// Episodes, then specials
+	didl, _ = browse(t, s, "watching/154587", "BrowseDirectChildren")
+	require.Len(t, didl.Items, 3)
+	assert.Equal(t, "Episode 1", didl.Items[0].Title)
+	assert.Equal(t, "Episode 2", didl.Items[1].Title)
+	assert.Equal(t, "Special S1", didl.Items[2].Title)
+	assert.Equal(t, "http://192.168.1.10:43211/api/v1/mediastream/file/"+util.Base64EncodeStr("/anime/Frieren/Frieren - 01.mkv"), didl.Items[0].Res.Url)
+	assert.True(t, strings.HasPrefix(didl.Items[0].Res.ProtocolInfo, "http-get:*:video/x-matroska:DLNA.ORG_OP=01"))
+	assert.Equal(t, "0:24:00.000", didl.Items[0].Res.Duration)
+
+	// Metadata of an item
+	didl, res = browse(t, s, "watching/154587/1", "BrowseMetadata")
+	assert.Equal(t, "1", res["TotalMatches"])
+	require.Len(t, didl.Items, 1)
+	assert.Equal(t, "Episode 2", didl.Items[0].Title)
+}
+
+func TestServer_BrowsePaging(t *testing.T) {
+	s := newTestServer(t)
+
+	code, res := callAction(t, s, ContentDirectoryServiceType, "/control/ContentDirectory", "Browse", map[string]string{
+		"ObjectID":       "titles/154587",
+		"BrowseFlag":     "BrowseDirectChildren",
+		"StartingIndex":  "1",
+		"RequestedCount": "1",
+	})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "1", res["NumberReturned"])
+	assert.Equal(t, "3", res["TotalMatches"])
+	assert.Contains(t, res["Result"], "Episode 2")
+}
+
+func TestServer_BrowseUnknownObject(t *testing.T) {
+	s := newTestServer(t)
+
+	code, _ := callAction(t, s, ContentDirectoryServiceType, "/control/ContentDirectory", "Browse", map[string]string{
+		"ObjectID":   "titles/1",
+		"BrowseFlag": "BrowseDirectChildren",
+	})
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
+
+func TestServer_SetBookmark(t *testing.T) {
+	s := newTestServer(t)
+
+	code, _ := callAction(t, s, ContentDirectoryServiceType, "/control/ContentDirectory", "X_SetBookmark", map[string]string{
+		"CategoryType": "1",
+		"RID":          "0",
+		"ObjectID":     "titles/154587/1",
+		"PosSecond":    "600",
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	res := s.continuityManager.GetWatchHistoryItem(154587)
+	require.True(t, res.Found)
+	assert.Equal(t, 2, res.Item.EpisodeNumber)
+	assert.Equal(t, 600.0, res.Item.CurrentTime)
+	assert.Equal(t, 1440.0, res.Item.Duration)
+
+	// The bookmark is sent to the renderer, in every container
+	didl, _ := browse(t, s, "seasons/2023-4/154587", "BrowseDirectChildren")
+	require.Len(t, didl.Items, 3)
+	assert.Empty(t, didl.Items[0].DcmInfo)
+	assert.Equal(t, "BM=600", didl.Items[1].DcmInfo)
+}
+
+func TestServer_Disabled(t *testing.T) {
+	s := newTestServer(t)
+	s.settings = &Settings{Enabled: false}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, BasePath+"/description.xml", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_DeviceDescription(t *testing.T) {
+	s := newTestServer(t)
+	s.settings.FriendlyName = "Seanime & Co"
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, BasePath+"/description.xml", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var desc struct {
+		Device struct {
+			DeviceType   string `xml:"deviceType"`
+			FriendlyName string `xml:"friendlyName"`
+			UDN          string `xml:"UDN"`
+			Services     []struct {
+				ServiceType string `xml:"serviceType"`
+				ControlURL  string `xml:"controlURL"`
+			} `xml:"serviceList>service"`
+		} `xml:"device"`
+	}
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &desc))
+	assert.Equal(t, MediaServerDeviceType, desc.Device.DeviceType)
+	assert.Equal(t, "Seanime & Co", desc.Device.FriendlyName)
+	assert.Equal(t, s.udn, desc.Device.UDN)
+	require.Len(t, desc.Device.Services, 2)
+	assert.Equal(t, BasePath+"/control/ContentDirectory", desc.Device.Services[0].ControlURL)
+}
+
+func TestSsdpAdvertiser_SearchTargets(t *testing.T) {
+	a := newSsdpAdvertiser(util.NewLogger(), "uuid:test", 43211)
+
+	assert.Len(t, a.searchTargets("ssdp:all"), 5)
+	assert.Equal(t, []string{MediaServerDeviceType}, a.searchTargets(MediaServerDeviceType))
+	assert.Equal(t, []string{"uuid:test"}, a.searchTargets("uuid:test"))
+	assert.Empty(t, a.searchTargets("urn:schemas-upnp-org:device:MediaRenderer:1"))
+
+	assert.Equal(t, "uuid:test::"+MediaServerDeviceType, a.usn(MediaServerDeviceType))
+	assert.Equal(t, "uuid:test", a.usn("uuid:test"))
+}
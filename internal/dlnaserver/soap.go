@@ -0,0 +1,101 @@
+package dlnaserver
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// UPnP error codes
+const (
+	errInvalidAction = 401
+	errInvalidArgs   = 402
+	errActionFailed  = 501
+	errNoSuchObject  = 701
+)
+
+type (
+	upnpError struct {
+		Code        int
+		Description string
+	}
+
+	// soapArg is an output argument of an action, arguments must be sent in the order of the specification.
+	soapArg struct {
+		Name  string
+		Value string
+	}
+)
+
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Description, e.Code)
+}
+
+// parseAction returns the service type and the name of the action of a control request.
+func parseAction(r *http.Request) (serviceType string, action string, ok bool) {
+	header := strings.Trim(r.Header.Get("SOAPAction"), `" `)
+	serviceType, action, ok = strings.Cut(header, "#")
+	return
+}
+
+// parseArguments returns the input arguments of the action.
+func parseArguments(r *http.Request) (map[string]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope struct {
+		Body struct {
+			Action struct {
+				Args []struct {
+					XMLName xml.Name
+					Value   string `xml:",chardata"`
+				} `xml:",any"`
+			} `xml:",any"`
+		} `xml:"Body"`
+	}
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]string, len(envelope.Body.Action.Args))
+	for _, arg := range envelope.Body.Action.Args {
+		ret[arg.XMLName.Local] = arg.Value
+	}
+	return ret, nil
+}
+
+func writeSoapResponse(w http.ResponseWriter, serviceType string, action string, args []soapArg) {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%sResponse xmlns:u="%s">`, action, serviceType)
+	for _, arg := range args {
+		fmt.Fprintf(&body, "<%s>", arg.Name)
+		_ = xml.EscapeText(&body, []byte(arg.Value))
+		fmt.Fprintf(&body, "</%s>", arg.Name)
+	}
+	fmt.Fprintf(&body, `</u:%sResponse></s:Body></s:Envelope>`, action)
+
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.Header().Set("EXT", "")
+	_, _ = w.Write(body.Bytes())
+}
+
+func writeSoapFault(w http.ResponseWriter, err *upnpError) {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	body.WriteString(`<s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>`)
+	fmt.Fprintf(&body, `<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>%d</errorCode><errorDescription>`, err.Code)
+	_ = xml.EscapeText(&body, []byte(err.Description))
+	body.WriteString(`</errorDescription></UPnPError></detail></s:Fault></s:Body></s:Envelope>`)
+
+	w.Header().Set("Content-Type", `text/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write(body.Bytes())
+}
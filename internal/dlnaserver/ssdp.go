@@ -0,0 +1,219 @@
+package dlnaserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	ssdpMaxAge         = 1800
+	ssdpNotifyInterval = 15 * time.Minute
+)
+
+var ssdpGroupAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+type (
+	// ssdpAdvertiser announces the server on the local network and answers the searches of the devices.
+	ssdpAdvertiser struct {
+		logger *zerolog.Logger
+		udn    string
+		port   int
+
+		conn *net.UDPConn
+		done chan struct{}
+		wg   sync.WaitGroup
+	}
+)
+
+func newSsdpAdvertiser(logger *zerolog.Logger, udn string, port int) *ssdpAdvertiser {
+	return &ssdpAdvertiser{
+		logger: logger,
+		udn:    udn,
+		port:   port,
+		done:   make(chan struct{}),
+	}
+}
+
+func (a *ssdpAdvertiser) start() error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, ssdpGroupAddr)
+	if err != nil {
+		return err
+	}
+	a.conn = conn
+
+	a.wg.Add(2)
+	go a.listen()
+	go a.notifyLoop()
+	return nil
+}
+
+func (a *ssdpAdvertiser) stop() {
+	close(a.done)
+	_ = a.conn.Close()
+	a.wg.Wait()
+	a.notify("ssdp:byebye")
+}
+
+// targets returns the notification types of the device and its services.
+func (a *ssdpAdvertiser) targets() []string {
+	return []string{"upnp:rootdevice", a.udn, MediaServerDeviceType, ContentDirectoryServiceType, ConnectionManagerServiceType}
+}
+
+func (a *ssdpAdvertiser) usn(target string) string {
+	if target == a.udn {
+		return a.udn
+	}
+	return a.udn + "::" + target
+}
+
+func (a *ssdpAdvertiser) location(ip net.IP) string {
+	return fmt.Sprintf("http://%s%s/description.xml", net.JoinHostPort(ip.String(), fmt.Sprint(a.port)), BasePath)
+}
+
+func serverHeader() string {
+	return fmt.Sprintf("%s/1.0 UPnP/1.0 Seanime/1.0", runtime.GOOS)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (a *ssdpAdvertiser) listen() {
+	defer a.wg.Done()
+
+	buf := make([]byte, 4096)
+	for {
+		n, from, err := a.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-a.done:
+				return
+			default:
+				continue
+			}
+		}
+
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buf[:n])))
+		if err != nil || req.Method != "M-SEARCH" || req.Header.Get("MAN") != `"ssdp:discover"` {
+			continue
+		}
+
+		targets := a.searchTargets(req.Header.Get("ST"))
+		if len(targets) > 0 {
+			a.logger.Trace().Str("from", from.String()).Str("st", req.Header.Get("ST")).Msg("dlna server: Answering search")
+		}
+		for _, target := range targets {
+			a.respond(from, target)
+		}
+	}
+}
+
+// searchTargets returns the targets that match the search target of an M-SEARCH request.
+func (a *ssdpAdvertiser) searchTargets(st string) []string {
+	if st == "ssdp:all" {
+		return a.targets()
+	}
+	for _, target := range a.targets() {
+		if target == st {
+			return []string{target}
+		}
+	}
+	return nil
+}
+
+func (a *ssdpAdvertiser) respond(to *net.UDPAddr, target string) {
+	ip, err := localIpFor(to.IP)
+	if err != nil {
+		return
+	}
+
+	msg := "HTTP/1.1 200 OK\r\n" +
+		fmt.Sprintf("CACHE-CONTROL: max-age=%d\r\n", ssdpMaxAge) +
+		"EXT:\r\n" +
+		"LOCATION: " + a.location(ip) + "\r\n" +
+		"SERVER: " + serverHeader() + "\r\n" +
+		"ST: " + target + "\r\n" +
+		"USN: " + a.usn(target) + "\r\n\r\n"
+
+	_, _ = a.conn.WriteToUDP([]byte(msg), to)
+}
+
+func (a *ssdpAdvertiser) notifyLoop() {
+	defer a.wg.Done()
+
+	a.notify("ssdp:alive")
+
+	ticker := time.NewTicker(ssdpNotifyInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+			a.notify("ssdp:alive")
+		}
+	}
+}
+
+// notify sends the announcements on each interface, with the address of the interface as location.
+func (a *ssdpAdvertiser) notify(nts string) {
+	for _, ip := range multicastIps() {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: ip})
+		if err != nil {
+			continue
+		}
+		for _, target := range a.targets() {
+			msg := "NOTIFY * HTTP/1.1\r\n" +
+				"HOST: 239.255.255.250:1900\r\n" +
+				fmt.Sprintf("CACHE-CONTROL: max-age=%d\r\n", ssdpMaxAge) +
+				"LOCATION: " + a.location(ip) + "\r\n" +
+				"NT: " + target + "\r\n" +
+				"NTS: " + nts + "\r\n" +
+				"SERVER: " + serverHeader() + "\r\n" +
+				"USN: " + a.usn(target) + "\r\n\r\n"
+			_, _ = conn.WriteToUDP([]byte(msg), ssdpGroupAddr)
+		}
+		_ = conn.Close()
+	}
+}
+
+// multicastIps returns the IPv4 addresses of the interfaces that can send multicast packets.
+func multicastIps() []net.IP {
+	ret := make([]net.IP, 0)
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ret
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagMulticast == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				ret = append(ret, ipNet.IP.To4())
+			}
+		}
+	}
+	return ret
+}
+
+// localIpFor returns the address of the interface used to reach ip.
+func localIpFor(ip net.IP) (net.IP, error) {
+	// No packet is sent, this only selects the outbound interface
+	conn, err := net.Dial("udp4", net.JoinHostPort(ip.String(), "1900"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
@@ -0,0 +1,212 @@
+package dlnaserver
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	rootId     = "0"
+	watchingId = "watching"
+	titlesId   = "titles"
+	seasonsId  = "seasons"
+)
+
+type (
+	// object is a container or an item of the ContentDirectory.
+	// Object IDs are paths from the root, e.g. "titles/21/3", so the same episode has a different ID in each container.
+	object struct {
+		Id       string
+		ParentId string
+		Title    string
+		Children []*object // Nil for items
+
+		// Items
+		Path          string  // Path of the local file
+		MediaId       int     //
+		EpisodeNumber int     // Progress number of the episode, 0 for specials and NC files
+		Duration      float64 // In seconds, from AniList, 0 if unknown
+		AlbumArt      string  // URL of the cover image
+	}
+
+	// tree is a snapshot of the library, browsed by the renderers.
+	tree struct {
+		objects  map[string]*object
+		updateId uint32 // SystemUpdateID, changes when the tree is rebuilt
+	}
+)
+
+func (o *object) isContainer() bool {
+	return o.Children != nil
+}
+
+// newTree creates the ContentDirectory tree from the library collection.
+//
+//	0
+//	├── Currently Watching → series → episodes
+//	├── By Title → series → episodes
+//	└── By Season → season → series → episodes
+func newTree(lc *anime.LibraryCollection, lfs []*anime.LocalFile) *tree {
+	t := &tree{objects: make(map[string]*object)}
+
+	root := t.addContainer("-1", rootId, "Seanime")
+	watching := t.addContainer(rootId, watchingId, "Currently Watching")
+	titles := t.addContainer(rootId, titlesId, "By Title")
+	seasons := t.addContainer(rootId, seasonsId, "By Season")
+	root.Children = append(root.Children, watching, titles, seasons)
+
+	if lc == nil {
+		return t
+	}
+
+	wrapper := anime.NewLocalFileWrapper(lfs)
+
+	entries := make([]*anime.LibraryCollectionEntry, 0)
+	watchingEntries := make(map[int]struct{})
+	for _, list := range lc.Lists {
+		for _, entry := range list.Entries {
+			if entry.Media == nil {
+				continue
+			}
+			if _, found := wrapper.GetLocalEntryById(entry.MediaId); !found {
+				continue
+			}
+			entries = append(entries, entry)
+			if list.Status == anilist.MediaListStatusCurrent || list.Status == anilist.MediaListStatusRepeating {
+				watchingEntries[entry.MediaId] = struct{}{}
+			}
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b *anime.LibraryCollectionEntry) int {
+		return cmp.Compare(strings.ToLower(a.Media.GetPreferredTitle()), strings.ToLower(b.Media.GetPreferredTitle()))
+	})
+
+	seasonContainers := make(map[string]*object)
+	for _, entry := range entries {
+		localEntry, _ := wrapper.GetLocalEntryById(entry.MediaId)
+
+		if _, ok := watchingEntries[entry.MediaId]; ok {
+			watching.Children = append(watching.Children, t.addSeries(watching, entry, localEntry))
+		}
+
+		titles.Children = append(titles.Children, t.addSeries(titles, entry, localEntry))
+
+		key, title := seasonOf(entry.Media)
+		season, ok := seasonContainers[key]
+		if !ok {
+			season = t.addContainer(seasonsId, seasonsId+"/"+key, title)
+			seasonContainers[key] = season
+			seasons.Children = append(seasons.Children, season)
+		}
+		season.Children = append(season.Children, t.addSeries(season, entry, localEntry))
+	}
+
+	// Newest seasons first
+	slices.SortStableFunc(seasons.Children, func(a, b *object) int {
+		return cmp.Compare(b.Id, a.Id)
+	})
+
+	return t
+}
+
+func (t *tree) addContainer(parentId string, id string, title string) *object {
+	o := &object{
+		Id:       id,
+		ParentId: parentId,
+		Title:    title,
+		Children: make([]*object, 0),
+	}
+	t.objects[id] = o
+	return o
+}
+
+// addSeries adds the container of the episodes of the entry.
+func (t *tree) addSeries(parent *object, entry *anime.LibraryCollectionEntry, localEntry *anime.LocalFileWrapperEntry) *object {
+	series := t.addContainer(parent.Id, parent.Id+"/"+strconv.Itoa(entry.MediaId), entry.Media.GetPreferredTitle())
+
+	var duration float64
+	if entry.Media.Duration != nil {
+		duration = float64(*entry.Media.Duration * 60)
+	}
+
+	lfs := slices.Clone(localEntry.GetLocalFiles())
+	slices.SortStableFunc(lfs, func(a, b *anime.LocalFile) int {
+		if c := cmp.Compare(typeOrder(a), typeOrder(b)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.GetEpisodeNumber(), b.GetEpisodeNumber()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.GetPath(), b.GetPath())
+	})
+
+	for i, lf := range lfs {
+		item := &object{
+			Id:       fmt.Sprintf("%s/%d", series.Id, i),
+			ParentId: series.Id,
+			Title:    episodeTitle(lf),
+			Path:     lf.GetPath(),
+			MediaId:  entry.MediaId,
+			Duration: duration,
+			AlbumArt: entry.Media.GetCoverImageSafe(),
+		}
+		if lf.IsMain() {
+			item.EpisodeNumber = localEntry.GetProgressNumber(lf)
+		}
+		t.objects[item.Id] = item
+		series.Children = append(series.Children, item)
+	}
+
+	return series
+}
+
+func typeOrder(lf *anime.LocalFile) int {
+	switch lf.GetType() {
+	case anime.LocalFileTypeMain:
+		return 0
+	case anime.LocalFileTypeSpecial:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func episodeTitle(lf *anime.LocalFile) string {
+	switch lf.GetType() {
+	case anime.LocalFileTypeMain:
+		return fmt.Sprintf("Episode %d", lf.GetEpisodeNumber())
+	case anime.LocalFileTypeSpecial:
+		if ep := lf.GetAniDBEpisode(); ep != "" {
+			return "Special " + ep
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(lf.GetPath()), filepath.Ext(lf.GetPath()))
+}
+
+// seasonOf returns the sortable key of the season, e.g. "2024-4", and its title, e.g. "Fall 2024".
+func seasonOf(media *anilist.BaseAnime) (key string, title string) {
+	if media.SeasonYear == nil {
+		return "0000-0", "Unknown Season"
+	}
+	year := *media.SeasonYear
+	if media.Season == nil {
+		return fmt.Sprintf("%d-0", year), strconv.Itoa(year)
+	}
+	switch *media.Season {
+	case anilist.MediaSeasonWinter:
+		return fmt.Sprintf("%d-1", year), fmt.Sprintf("Winter %d", year)
+	case anilist.MediaSeasonSpring:
+		return fmt.Sprintf("%d-2", year), fmt.Sprintf("Spring %d", year)
+	case anilist.MediaSeasonSummer:
+		return fmt.Sprintf("%d-3", year), fmt.Sprintf("Summer %d", year)
+	default:
+		return fmt.Sprintf("%d-4", year), fmt.Sprintf("Fall %d", year)
+	}
+}
@@ -3,6 +3,7 @@ package events
 
 const (
 	AddUnknownMediaEndpoint                            = "ANIME-COLLECTION-add-unknown-media"
+	AnalyzeSkipMarkersEndpoint                         = "MEDIASTREAM-analyze-skip-markers"
 	AnilistListAnimeEndpoint                           = "ANILIST-anilist-list-anime"
 	AnilistListMangaEndpoint                           = "MANGA-anilist-list-manga"
 	AnilistListMissedSequelsEndpoint                   = "ANILIST-anilist-list-missed-sequels"
@@ -13,6 +14,8 @@ const (
 	ClearAllChapterDownloadQueueEndpoint               = "MANGA-DOWNLOAD-clear-all-chapter-download-queue"
 	ClearFileCacheMediastreamVideoFilesEndpoint        = "FILECACHE-clear-file-cache-mediastream-video-files"
 	CreateAutoDownloaderRuleEndpoint                   = "AUTO-DOWNLOADER-create-auto-downloader-rule"
+	CreateAutoDownloaderRuleTemplateEndpoint           = "AUTO-DOWNLOADER-create-auto-downloader-rule-template"
+	CreateMangaAutoDownloaderRuleEndpoint              = "MANGA-AUTO-DOWNLOADER-create-manga-auto-downloader-rule"
 	CreatePlaylistEndpoint                             = "PLAYLIST-create-playlist"
 	DebridAddTorrentsEndpoint                          = "DEBRID-debrid-add-torrents"
 	DebridCancelDownloadEndpoint                       = "DEBRID-debrid-cancel-download"
@@ -26,11 +29,17 @@ const (
 	DeleteAnilistListEntryEndpoint                     = "ANILIST-delete-anilist-list-entry"
 	DeleteAutoDownloaderItemEndpoint                   = "AUTO-DOWNLOADER-delete-auto-downloader-item"
 	DeleteAutoDownloaderRuleEndpoint                   = "AUTO-DOWNLOADER-delete-auto-downloader-rule"
+	DeleteAutoDownloaderRuleTemplateEndpoint           = "AUTO-DOWNLOADER-delete-auto-downloader-rule-template"
+	DeleteFillerPreferenceEndpoint                     = "METADATA-delete-filler-preference"
 	DeleteLocalFilesEndpoint                           = "LOCALFILES-delete-local-files"
 	DeleteLogsEndpoint                                 = "STATUS-delete-logs"
+	DeleteMangaAutoDownloaderRuleEndpoint              = "MANGA-AUTO-DOWNLOADER-delete-manga-auto-downloader-rule"
 	DeleteMangaDownloadedChaptersEndpoint              = "MANGA-DOWNLOAD-delete-manga-downloaded-chapters"
 	DeletePlaylistEndpoint                             = "PLAYLIST-delete-playlist"
+	DeleteTrackPreferencesEndpoint                     = "TRACK-PREFERENCES-delete-track-preferences"
 	DirectorySelectorEndpoint                          = "DIRECTORY-SELECTOR-directory-selector"
+	DiscoverDlnaRenderersEndpoint                      = "MEDIAPLAYER-discover-dlna-renderers"
+	DismissTorrentstreamResumableSessionEndpoint       = "TORRENTSTREAM-dismiss-torrentstream-resumable-session"
 	DownloadIssueReportEndpoint                        = "REPORT-download-issue-report"
 	DownloadMangaChaptersEndpoint                      = "MANGA-DOWNLOAD-download-manga-chapters"
 	DownloadReleaseEndpoint                            = "DOWNLOAD-download-release"
@@ -39,10 +48,12 @@ const (
 	EditMALListEntryProgressEndpoint                   = "MAL-edit-mal-list-entry-progress"
 	EmptyMangaEntryCacheEndpoint                       = "MANGA-empty-manga-entry-cache"
 	EmptyTVDBEpisodesEndpoint                          = "METADATA-empty-tvdb-episodes"
+	ExportViewingStatsYearlyRecapEndpoint              = "VIEWING-STATS-export-viewing-stats-yearly-recap"
 	FetchAnimeEntrySuggestionsEndpoint                 = "ANIME-ENTRIES-fetch-anime-entry-suggestions"
 	FetchExternalExtensionDataEndpoint                 = "EXTENSIONS-fetch-external-extension-data"
 	GetActiveTorrentListEndpoint                       = "TORRENT-CLIENT-get-active-torrent-list"
 	GetAllExtensionsEndpoint                           = "EXTENSIONS-get-all-extensions"
+	GetAllTrackPreferencesEndpoint                     = "TRACK-PREFERENCES-get-all-track-preferences"
 	GetAniListStatsEndpoint                            = "ANILIST-get-ani-list-stats"
 	GetAnilistAnimeDetailsEndpoint                     = "ANILIST-get-anilist-anime-details"
 	GetAnilistMangaCollectionEndpoint                  = "MANGA-get-anilist-manga-collection"
@@ -52,22 +63,31 @@ const (
 	GetAnimeEntrySilenceStatusEndpoint                 = "ANIME-ENTRIES-get-anime-entry-silence-status"
 	GetAutoDownloaderItemsEndpoint                     = "AUTO-DOWNLOADER-get-auto-downloader-items"
 	GetAutoDownloaderRuleEndpoint                      = "AUTO-DOWNLOADER-get-auto-downloader-rule"
+	GetAutoDownloaderRuleSchedulesEndpoint             = "AUTO-DOWNLOADER-get-auto-downloader-rule-schedules"
+	GetAutoDownloaderRuleTemplatesEndpoint             = "AUTO-DOWNLOADER-get-auto-downloader-rule-templates"
 	GetAutoDownloaderRulesEndpoint                     = "AUTO-DOWNLOADER-get-auto-downloader-rules"
 	GetAutoDownloaderRulesByAnimeEndpoint              = "AUTO-DOWNLOADER-get-auto-downloader-rules-by-anime"
 	GetChangelogEndpoint                               = "RELEASES-get-changelog"
+	GetContinuityEpisodeWatchHistoryItemEndpoint       = "CONTINUITY-get-continuity-episode-watch-history-item"
+	GetContinuityRecentlyWatchedEndpoint               = "CONTINUITY-get-continuity-recently-watched"
+	GetContinuityResumeListEndpoint                    = "CONTINUITY-get-continuity-resume-list"
 	GetContinuityWatchHistoryEndpoint                  = "CONTINUITY-get-continuity-watch-history"
 	GetContinuityWatchHistoryItemEndpoint              = "CONTINUITY-get-continuity-watch-history-item"
+	GetContinuityWatchLogEndpoint                      = "CONTINUITY-get-continuity-watch-log"
 	GetDebridSettingsEndpoint                          = "DEBRID-get-debrid-settings"
 	GetDocsEndpoint                                    = "DOCS-get-docs"
 	GetExtensionPayloadEndpoint                        = "EXTENSIONS-get-extension-payload"
 	GetExtensionUserConfigEndpoint                     = "EXTENSIONS-get-extension-user-config"
 	GetFileCacheMediastreamVideoFilesTotalSizeEndpoint = "FILECACHE-get-file-cache-mediastream-video-files-total-size"
 	GetFileCacheTotalSizeEndpoint                      = "FILECACHE-get-file-cache-total-size"
+	GetFillerPreferenceEndpoint                        = "METADATA-get-filler-preference"
 	GetLatestLogContentEndpoint                        = "STATUS-get-latest-log-content"
 	GetLatestUpdateEndpoint                            = "RELEASES-get-latest-update"
 	GetLibraryCollectionEndpoint                       = "ANIME-COLLECTION-get-library-collection"
 	GetLocalFilesEndpoint                              = "LOCALFILES-get-local-files"
 	GetLogFilenamesEndpoint                            = "STATUS-get-log-filenames"
+	GetMangaAutoDownloaderRulesEndpoint                = "MANGA-AUTO-DOWNLOADER-get-manga-auto-downloader-rules"
+	GetMangaAutoDownloaderRulesByMangaEndpoint         = "MANGA-AUTO-DOWNLOADER-get-manga-auto-downloader-rules-by-manga"
 	GetMangaCollectionEndpoint                         = "MANGA-get-manga-collection"
 	GetMangaDownloadDataEndpoint                       = "MANGA-DOWNLOAD-get-manga-download-data"
 	GetMangaDownloadQueueEndpoint                      = "MANGA-DOWNLOAD-get-manga-download-queue"
@@ -79,7 +99,10 @@ const (
 	GetMangaEntryPagesEndpoint                         = "MANGA-get-manga-entry-pages"
 	GetMangaLatestChapterNumbersMapEndpoint            = "MANGA-get-manga-latest-chapter-numbers-map"
 	GetMangaMappingEndpoint                            = "MANGA-get-manga-mapping"
+	GetMediaSkipMarkersEndpoint                        = "MEDIASTREAM-get-media-skip-markers"
+	GetMediastreamOptimizationQueueEndpoint            = "MEDIASTREAM-get-mediastream-optimization-queue"
 	GetMediastreamSettingsEndpoint                     = "MEDIASTREAM-get-mediastream-settings"
+	GetMediastreamStreamTracksEndpoint                 = "MEDIASTREAM-get-mediastream-stream-tracks"
 	GetMissingEpisodesEndpoint                         = "ANIME-ENTRIES-get-missing-episodes"
 	GetOnlineStreamEpisodeListEndpoint                 = "ONLINESTREAM-get-online-stream-episode-list"
 	GetOnlineStreamEpisodeSourceEndpoint               = "ONLINESTREAM-get-online-stream-episode-source"
@@ -91,22 +114,34 @@ const (
 	GetRawAnimeCollectionEndpoint                      = "ANILIST-get-raw-anime-collection"
 	GetScanSummariesEndpoint                           = "SCAN-SUMMARY-get-scan-summaries"
 	GetSettingsEndpoint                                = "SETTINGS-get-settings"
+	GetSkipMarkersEndpoint                             = "MEDIASTREAM-get-skip-markers"
 	GetStatusEndpoint                                  = "STATUS-get-status"
 	GetThemeEndpoint                                   = "THEME-get-theme"
 	GetTorrentstreamBatchHistoryEndpoint               = "TORRENTSTREAM-get-torrentstream-batch-history"
+	GetTorrentstreamCacheEndpoint                      = "TORRENTSTREAM-get-torrentstream-cache"
 	GetTorrentstreamEpisodeCollectionEndpoint          = "TORRENTSTREAM-get-torrentstream-episode-collection"
+	GetTorrentstreamResumableSessionsEndpoint          = "TORRENTSTREAM-get-torrentstream-resumable-sessions"
+	GetTorrentstreamSessionsEndpoint                   = "TORRENTSTREAM-get-torrentstream-sessions"
 	GetTorrentstreamSettingsEndpoint                   = "TORRENTSTREAM-get-torrentstream-settings"
 	GetTorrentstreamTorrentFilePreviewsEndpoint        = "TORRENTSTREAM-get-torrentstream-torrent-file-previews"
+	GetTrackPreferencesEndpoint                        = "TRACK-PREFERENCES-get-track-preferences"
+	GetViewingStatsEndpoint                            = "VIEWING-STATS-get-viewing-stats"
+	GetViewingStatsYearlyRecapEndpoint                 = "VIEWING-STATS-get-viewing-stats-yearly-recap"
+	GetWatchPartyEndpoint                              = "WATCH-PARTY-get-watch-party"
 	GettingStartedEndpoint                             = "SETTINGS-getting-started"
 	GrantPluginPermissionsEndpoint                     = "EXTENSIONS-grant-plugin-permissions"
+	HostWatchPartyEndpoint                             = "WATCH-PARTY-host-watch-party"
 	ImportLocalFilesEndpoint                           = "LOCALFILES-import-local-files"
 	InstallExternalExtensionEndpoint                   = "EXTENSIONS-install-external-extension"
 	InstallLatestUpdateEndpoint                        = "RELEASES-install-latest-update"
+	JoinWatchPartyEndpoint                             = "WATCH-PARTY-join-watch-party"
+	LeaveWatchPartyEndpoint                            = "WATCH-PARTY-leave-watch-party"
 	ListAnimeTorrentProviderExtensionsEndpoint         = "EXTENSIONS-list-anime-torrent-provider-extensions"
 	ListDevelopmentModeExtensionsEndpoint              = "EXTENSIONS-list-development-mode-extensions"
 	ListExtensionDataEndpoint                          = "EXTENSIONS-list-extension-data"
 	ListMangaProviderExtensionsEndpoint                = "EXTENSIONS-list-manga-provider-extensions"
 	ListOnlinestreamProviderExtensionsEndpoint         = "EXTENSIONS-list-onlinestream-provider-extensions"
+	LoadMediastreamStreamTracksEndpoint                = "MEDIASTREAM-load-mediastream-stream-tracks"
 	LocalFileBulkActionEndpoint                        = "LOCALFILES-local-file-bulk-action"
 	LoginEndpoint                                      = "AUTH-login"
 	LogoutEndpoint                                     = "AUTH-logout"
@@ -114,6 +149,9 @@ const (
 	MALLogoutEndpoint                                  = "MAL-mal-logout"
 	MangaManualMappingEndpoint                         = "MANGA-manga-manual-mapping"
 	MangaManualSearchEndpoint                          = "MANGA-manga-manual-search"
+	MediastreamEnqueueOptimizationEndpoint             = "MEDIASTREAM-mediastream-enqueue-optimization"
+	MediastreamGenerateTrickplayEndpoint               = "MEDIASTREAM-mediastream-generate-trickplay"
+	MediastreamRemoveOptimizationItemEndpoint          = "MEDIASTREAM-mediastream-remove-optimization-item"
 	MediastreamShutdownTranscodeStreamEndpoint         = "MEDIASTREAM-mediastream-shutdown-transcode-stream"
 	OnlineStreamEmptyCacheEndpoint                     = "ONLINESTREAM-online-stream-empty-cache"
 	OnlinestreamManualMappingEndpoint                  = "ONLINESTREAM-onlinestream-manual-mapping"
@@ -134,6 +172,7 @@ const (
 	PopulateFillerDataEndpoint                         = "METADATA-populate-filler-data"
 	PopulateTVDBEpisodesEndpoint                       = "METADATA-populate-tvdb-episodes"
 	PreloadMediastreamMediaContainerEndpoint           = "MEDIASTREAM-preload-mediastream-media-container"
+	RecordViewingProgressEndpoint                      = "VIEWING-STATS-record-viewing-progress"
 	RefetchMangaChapterContainersEndpoint              = "MANGA-refetch-manga-chapter-containers"
 	ReloadExternalExtensionEndpoint                    = "EXTENSIONS-reload-external-extension"
 	ReloadExternalExtensionsEndpoint                   = "EXTENSIONS-reload-external-extensions"
@@ -142,17 +181,22 @@ const (
 	RemoveFillerDataEndpoint                           = "METADATA-remove-filler-data"
 	RemoveMangaMappingEndpoint                         = "MANGA-remove-manga-mapping"
 	RemoveOnlinestreamMappingEndpoint                  = "ONLINESTREAM-remove-onlinestream-mapping"
+	RemoveTorrentstreamCacheEntryEndpoint              = "TORRENTSTREAM-remove-torrentstream-cache-entry"
 	RequestMediastreamMediaContainerEndpoint           = "MEDIASTREAM-request-mediastream-media-container"
 	ResetErroredChapterDownloadQueueEndpoint           = "MANGA-DOWNLOAD-reset-errored-chapter-download-queue"
+	ResolveSmartPlaylistEndpoint                       = "PLAYLIST-resolve-smart-playlist"
 	RunAutoDownloaderEndpoint                          = "AUTO-DOWNLOADER-run-auto-downloader"
 	RunExtensionPlaygroundCodeEndpoint                 = "EXTENSIONS-run-extension-playground-code"
+	RunMangaAutoDownloaderEndpoint                     = "MANGA-AUTO-DOWNLOADER-run-manga-auto-downloader"
 	SaveAutoDownloaderSettingsEndpoint                 = "SETTINGS-save-auto-downloader-settings"
 	SaveDebridSettingsEndpoint                         = "DEBRID-save-debrid-settings"
 	SaveExtensionUserConfigEndpoint                    = "EXTENSIONS-save-extension-user-config"
+	SaveFillerPreferenceEndpoint                       = "METADATA-save-filler-preference"
 	SaveIssueReportEndpoint                            = "REPORT-save-issue-report"
 	SaveMediastreamSettingsEndpoint                    = "MEDIASTREAM-save-mediastream-settings"
 	SaveSettingsEndpoint                               = "SETTINGS-save-settings"
 	SaveTorrentstreamSettingsEndpoint                  = "TORRENTSTREAM-save-torrentstream-settings"
+	SaveTrackPreferencesEndpoint                       = "TRACK-PREFERENCES-save-track-preferences"
 	ScanLocalFilesEndpoint                             = "SCAN-scan-local-files"
 	SearchTorrentEndpoint                              = "TORRENT-SEARCH-search-torrent"
 	SetDiscordAnimeActivityEndpoint                    = "DISCORD-set-discord-anime-activity"
@@ -177,17 +221,25 @@ const (
 	TorrentClientAddMagnetFromRuleEndpoint             = "TORRENT-CLIENT-torrent-client-add-magnet-from-rule"
 	TorrentClientDownloadEndpoint                      = "TORRENT-CLIENT-torrent-client-download"
 	TorrentstreamDropTorrentEndpoint                   = "TORRENTSTREAM-torrentstream-drop-torrent"
+	TorrentstreamResumeStreamEndpoint                  = "TORRENTSTREAM-torrentstream-resume-stream"
 	TorrentstreamStartStreamEndpoint                   = "TORRENTSTREAM-torrentstream-start-stream"
 	TorrentstreamStopStreamEndpoint                    = "TORRENTSTREAM-torrentstream-stop-stream"
+	TransferWatchPartyHostEndpoint                     = "WATCH-PARTY-transfer-watch-party-host"
 	UninstallExternalExtensionEndpoint                 = "EXTENSIONS-uninstall-external-extension"
 	UpdateAnimeEntryProgressEndpoint                   = "ANIME-ENTRIES-update-anime-entry-progress"
 	UpdateAnimeEntryRepeatEndpoint                     = "ANIME-ENTRIES-update-anime-entry-repeat"
 	UpdateAutoDownloaderRuleEndpoint                   = "AUTO-DOWNLOADER-update-auto-downloader-rule"
+	UpdateAutoDownloaderRuleTemplateEndpoint           = "AUTO-DOWNLOADER-update-auto-downloader-rule-template"
 	UpdateContinuityWatchHistoryItemEndpoint           = "CONTINUITY-update-continuity-watch-history-item"
 	UpdateExtensionCodeEndpoint                        = "EXTENSIONS-update-extension-code"
 	UpdateLocalFileDataEndpoint                        = "LOCALFILES-update-local-file-data"
 	UpdateLocalFilesEndpoint                           = "LOCALFILES-update-local-files"
+	UpdateMangaAutoDownloaderRuleEndpoint              = "MANGA-AUTO-DOWNLOADER-update-manga-auto-downloader-rule"
 	UpdateMangaProgressEndpoint                        = "MANGA-update-manga-progress"
 	UpdatePlaylistEndpoint                             = "PLAYLIST-update-playlist"
 	UpdateThemeEndpoint                                = "THEME-update-theme"
+	WatchPartyPauseEndpoint                            = "WATCH-PARTY-watch-party-pause"
+	WatchPartyPlayEndpoint                             = "WATCH-PARTY-watch-party-play"
+	WatchPartyResumeEndpoint                           = "WATCH-PARTY-watch-party-resume"
+	WatchPartySeekEndpoint                             = "WATCH-PARTY-watch-party-seek"
 )
@@ -3,7 +3,8 @@ package events
 type WebsocketClientEventType string
 
 const (
-	PluginEvent WebsocketClientEventType = "plugin"
+	PluginEvent                 WebsocketClientEventType = "plugin"
+	WatchPartyPlayerStatusEvent WebsocketClientEventType = "watch-party-player-status" // Status of the online stream player during a watch party
 )
 
 type WebsocketClientEvent struct {
@@ -32,6 +33,7 @@ const (
 	PlaybackManagerProgressPlaybackState       = "playback-manager-progress-playback-state"        // Dispatches the current playback state
 	PlaybackManagerProgressUpdated             = "playback-manager-progress-updated"               // Signals that the progress has been updated
 	PlaybackManagerPlaylistState               = "playback-manager-playlist-state"                 // Dispatches the current playlist state
+	PlaybackManagerPlaylistPlayOnlineStream    = "playback-manager-playlist-play-online-stream"    // The client should play an online stream episode of the playlist, then request the next item
 	PlaybackManagerManualTrackingPlaybackState = "playback-manager-manual-tracking-playback-state" // Dispatches the current playback state
 	PlaybackManagerManualTrackingStopped       = "playback-manager-manual-tracking-stopped"        // The manual tracking has been stopped
 
@@ -48,7 +50,9 @@ const (
 	ChapterDownloadQueueUpdated = "chapter-download-queue-updated"
 	OfflineSnapshotCreated      = "offline-snapshot-created"
 
-	MediastreamShutdownStream = "mediastream-shutdown-stream"
+	MediastreamShutdownStream       = "mediastream-shutdown-stream"
+	MediastreamOptimizationProgress = "mediastream-optimization-progress" // Progress of an item in the pre-transcoding queue
+	SkipMarkersAnalyzed             = "skip-markers-analyzed"             // Intro/outro markers of a media have been detected
 
 	ExtensionsReloaded = "extensions-reloaded"
 	PluginUnloaded     = "plugin-unloaded"
@@ -59,6 +63,10 @@ const (
 	SyncLocalFinished   = "sync-local-finished"
 	SyncAnilistFinished = "sync-anilist-finished"
 
+	WatchPartyUpdated          = "watch-party-updated"            // The members or the playback state of the watch party have changed
+	WatchPartyPlayOnlineStream = "watch-party-play-online-stream" // The client should play an online stream episode of the watch party
+	WatchPartyPlayerCommand    = "watch-party-player-command"     // The client should pause, resume or seek the online stream player
+
 	DebridDownloadProgress = "debrid-download-progress"
 	DebridStreamState      = "debrid-stream-state"
 
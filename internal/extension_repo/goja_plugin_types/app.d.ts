@@ -523,6 +523,76 @@ declare namespace $app {
         rule?: Anime_AutoDownloaderRule;
     }
 
+    /**
+     * @event MangaAutoDownloaderRunStartedEvent
+     * @file internal/manga/autodownloader/hook_events.go
+     * @description
+     * MangaAutoDownloaderRunStartedEvent is triggered when the manga autodownloader starts checking for new chapters.
+     * Prevent default to abort the run.
+     */
+    function onMangaAutoDownloaderRunStarted(cb: (event: MangaAutoDownloaderRunStartedEvent) => void): void;
+
+    interface MangaAutoDownloaderRunStartedEvent {
+        next(): void;
+
+        preventDefault(): void;
+
+        rules?: Array<Manga_AutoDownloaderRule>;
+    }
+
+    /**
+     * @event MangaAutoDownloaderMatchVerifiedEvent
+     * @file internal/manga/autodownloader/hook_events.go
+     * @description
+     * MangaAutoDownloaderMatchVerifiedEvent is triggered when a chapter is verified to follow a rule.
+     * Prevent default to skip the chapter.
+     */
+    function onMangaAutoDownloaderMatchVerified(cb: (event: MangaAutoDownloaderMatchVerifiedEvent) => void): void;
+
+    interface MangaAutoDownloaderMatchVerifiedEvent {
+        next(): void;
+
+        preventDefault(): void;
+
+        chapter?: HibikeManga_ChapterDetails;
+        rule?: Manga_AutoDownloaderRule;
+        listEntry?: AL_MangaListEntry;
+        matchFound: boolean;
+    }
+
+    /**
+     * @event MangaAutoDownloaderBeforeDownloadChapterEvent
+     * @file internal/manga/autodownloader/hook_events.go
+     * @description
+     * MangaAutoDownloaderBeforeDownloadChapterEvent is triggered when the manga autodownloader is about to queue a chapter.
+     * Prevent default to abort the download.
+     */
+    function onMangaAutoDownloaderBeforeDownloadChapter(cb: (event: MangaAutoDownloaderBeforeDownloadChapterEvent) => void): void;
+
+    interface MangaAutoDownloaderBeforeDownloadChapterEvent {
+        next(): void;
+
+        preventDefault(): void;
+
+        chapter?: HibikeManga_ChapterDetails;
+        rule?: Manga_AutoDownloaderRule;
+    }
+
+    /**
+     * @event MangaAutoDownloaderAfterDownloadChapterEvent
+     * @file internal/manga/autodownloader/hook_events.go
+     * @description
+     * MangaAutoDownloaderAfterDownloadChapterEvent is triggered when the manga autodownloader has queued a chapter.
+     */
+    function onMangaAutoDownloaderAfterDownloadChapter(cb: (event: MangaAutoDownloaderAfterDownloadChapterEvent) => void): void;
+
+    interface MangaAutoDownloaderAfterDownloadChapterEvent {
+        next(): void;
+
+        chapter?: HibikeManga_ChapterDetails;
+        rule?: Manga_AutoDownloaderRule;
+    }
+
 
     /**
      * @package continuity
@@ -2255,6 +2325,11 @@ declare namespace $app {
         node?: AL_BaseManga;
     }
 
+    /**
+     * - Filepath: internal/api/anilist/manga.go
+     */
+    export type AL_MangaListEntry = AL_MangaCollection_MediaListCollection_Lists_Entries;
+
     /**
      * - Filepath: internal/api/anilist/models_gen.go
      * @description
@@ -2413,6 +2488,10 @@ declare namespace $app {
         episodeNumbers?: Array<number>;
         destination: string;
         additionalTerms?: Array<string>;
+        torrentCategory?: string;
+        torrentTags?: Array<string>;
+        managed?: boolean;
+        templateId?: number;
     }
 
     /**
@@ -2826,6 +2905,24 @@ declare namespace $app {
         confirmed: boolean;
     }
 
+    /**
+     * - Filepath: internal/manga/auto_downloader_rule.go
+     */
+    interface Manga_AutoDownloaderRule {
+        /**
+         * Will be set when fetched from the database
+         */
+        dbId: number;
+        enabled: boolean;
+        mediaId: number;
+        /**
+         * Manga provider extension ID
+         */
+        provider: string;
+        scanlators?: Array<string>;
+        language: string;
+    }
+
     /**
      * - Filepath: internal/manga/chapter_container.go
      */
@@ -2988,6 +3085,11 @@ declare namespace $app {
         enableEnhancedQueries: boolean;
         enableSeasonCheck: boolean;
         useDebrid: boolean;
+        useAiringSchedule: boolean;
+        burstInterval: number;
+        burstWindow: number;
+        syncRules: boolean;
+        syncRulesTemplateId: number;
     }
 
 }
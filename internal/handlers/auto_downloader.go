@@ -82,6 +82,7 @@ func (h *Handler) HandleGetAutoDownloaderRules(c echo.Context) error {
 //
 //	@summary creates a new rule.
 //	@desc The body should contain the same fields as entities.AutoDownloaderRule.
+//	@desc The destination can contain placeholders (e.g. "{title}", "{year}") that are resolved when a torrent is downloaded.
 //	@desc It returns the created rule.
 //	@route /api/v1/auto-downloader/rule [POST]
 //	@returns anime.AutoDownloaderRule
@@ -97,6 +98,8 @@ func (h *Handler) HandleCreateAutoDownloaderRule(c echo.Context) error {
 		EpisodeType         anime.AutoDownloaderRuleEpisodeType         `json:"episodeType"`
 		EpisodeNumbers      []int                                       `json:"episodeNumbers,omitempty"`
 		Destination         string                                      `json:"destination"`
+		TorrentCategory     string                                      `json:"torrentCategory,omitempty"`
+		TorrentTags         []string                                    `json:"torrentTags,omitempty"`
 	}
 
 	var b body
@@ -124,6 +127,8 @@ func (h *Handler) HandleCreateAutoDownloaderRule(c echo.Context) error {
 		EpisodeNumbers:      b.EpisodeNumbers,
 		Destination:         b.Destination,
 		AdditionalTerms:     b.AdditionalTerms,
+		TorrentCategory:     b.TorrentCategory,
+		TorrentTags:         b.TorrentTags,
 	}
 
 	if err := db_bridge.InsertAutoDownloaderRule(h.App.Database, rule); err != nil {
@@ -160,6 +165,9 @@ func (h *Handler) HandleUpdateAutoDownloaderRule(c echo.Context) error {
 		return h.RespondWithError(c, errors.New("invalid id"))
 	}
 
+	// The rule is now in the state chosen by the user, syncing should not re-enable it
+	b.Rule.DisabledBySync = false
+
 	// Update the rule based on its DbID (primary key)
 	if err := db_bridge.UpdateAutoDownloaderRule(h.App.Database, b.Rule.DbID, b.Rule); err != nil {
 		return h.RespondWithError(c, err)
@@ -188,6 +196,130 @@ func (h *Handler) HandleDeleteAutoDownloaderRule(c echo.Context) error {
 	return h.RespondWithData(c, true)
 }
 
+// HandleGetAutoDownloaderRuleSchedules
+//
+//	@summary returns the computed next check time of each enabled rule.
+//	@desc When the airing schedule is used, rules whose episode aired recently are checked more frequently.
+//	@route /api/v1/auto-downloader/rules/schedule [GET]
+//	@returns []autodownloader.RuleSchedule
+func (h *Handler) HandleGetAutoDownloaderRuleSchedules(c echo.Context) error {
+	return h.RespondWithData(c, h.App.AutoDownloader.GetRuleSchedules())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// HandleGetAutoDownloaderRuleTemplates
+//
+//	@summary returns all rule templates.
+//	@desc Templates are used to create rules automatically when syncing with the collection.
+//	@route /api/v1/auto-downloader/templates [GET]
+//	@returns []anime.AutoDownloaderRuleTemplate
+func (h *Handler) HandleGetAutoDownloaderRuleTemplates(c echo.Context) error {
+	templates, err := db_bridge.GetAutoDownloaderRuleTemplates(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, templates)
+}
+
+// HandleCreateAutoDownloaderRuleTemplate
+//
+//	@summary creates a new rule template.
+//	@desc The destination can contain placeholders (e.g. "{title}", "{year}").
+//	@desc It returns the created template.
+//	@route /api/v1/auto-downloader/template [POST]
+//	@returns anime.AutoDownloaderRuleTemplate
+func (h *Handler) HandleCreateAutoDownloaderRuleTemplate(c echo.Context) error {
+
+	type body struct {
+		Template *anime.AutoDownloaderRuleTemplate `json:"template"`
+	}
+
+	var b body
+
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Template == nil {
+		return h.RespondWithError(c, errors.New("invalid template"))
+	}
+
+	if b.Template.Destination == "" {
+		return h.RespondWithError(c, errors.New("destination is required"))
+	}
+
+	if !filepath.IsAbs(b.Template.Destination) {
+		return h.RespondWithError(c, errors.New("destination must be an absolute path"))
+	}
+
+	b.Template.DbID = 0
+	if err := db_bridge.InsertAutoDownloaderRuleTemplate(h.App.Database, b.Template); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, b.Template)
+}
+
+// HandleUpdateAutoDownloaderRuleTemplate
+//
+//	@summary updates a rule template.
+//	@desc Existing rules created from the template are not updated.
+//	@desc It returns the updated template.
+//	@route /api/v1/auto-downloader/template [PATCH]
+//	@returns anime.AutoDownloaderRuleTemplate
+func (h *Handler) HandleUpdateAutoDownloaderRuleTemplate(c echo.Context) error {
+
+	type body struct {
+		Template *anime.AutoDownloaderRuleTemplate `json:"template"`
+	}
+
+	var b body
+
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Template == nil {
+		return h.RespondWithError(c, errors.New("invalid template"))
+	}
+
+	if b.Template.DbID == 0 {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	if !filepath.IsAbs(b.Template.Destination) {
+		return h.RespondWithError(c, errors.New("destination must be an absolute path"))
+	}
+
+	if err := db_bridge.UpdateAutoDownloaderRuleTemplate(h.App.Database, b.Template.DbID, b.Template); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, b.Template)
+}
+
+// HandleDeleteAutoDownloaderRuleTemplate
+//
+//	@summary deletes a rule template.
+//	@desc Rules created from the template are not deleted.
+//	@route /api/v1/auto-downloader/template/{id} [DELETE]
+//	@param id - int - true - "The DB id of the template"
+//	@returns bool
+func (h *Handler) HandleDeleteAutoDownloaderRuleTemplate(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	if err := db_bridge.DeleteAutoDownloaderRuleTemplate(h.App.Database, uint(id)); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // HandleGetAutoDownloaderItems
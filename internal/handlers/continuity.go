@@ -72,3 +72,88 @@ func (h *Handler) HandleGetContinuityWatchHistory(c echo.Context) error {
 	resp := h.App.ContinuityManager.GetWatchHistory()
 	return h.RespondWithData(c, resp)
 }
+
+// HandleGetContinuityEpisodeWatchHistoryItem
+//
+//	@summary Returns the resume position of an episode.
+//	@desc Nothing is returned if the episode was not stopped midway.
+//	@route /api/v1/continuity/item/{id}/episode/{episode} [GET]
+//	@param id - int - true - "AniList anime media ID"
+//	@param episode - int - true - "Episode number"
+//	@returns continuity.EpisodeWatchHistoryItemResponse
+func (h *Handler) HandleGetContinuityEpisodeWatchHistoryItem(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	episode, err := strconv.Atoi(c.Param("episode"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if !h.App.ContinuityManager.GetSettings().WatchContinuityEnabled {
+		return h.RespondWithData(c, &continuity.EpisodeWatchHistoryItemResponse{
+			Item:  nil,
+			Found: false,
+		})
+	}
+
+	resp := h.App.ContinuityManager.GetEpisodeWatchHistoryItem(id, episode)
+	return h.RespondWithData(c, resp)
+}
+
+// HandleGetContinuityRecentlyWatched
+//
+//	@summary Returns the recently watched episodes.
+//	@desc The episodes are sorted by the time they were last watched, most recent first.
+//	@route /api/v1/continuity/recent [GET]
+//	@returns []continuity.EpisodeWatchHistoryItem
+func (h *Handler) HandleGetContinuityRecentlyWatched(c echo.Context) error {
+	if !h.App.ContinuityManager.GetSettings().WatchContinuityEnabled {
+		return h.RespondWithData(c, []*continuity.EpisodeWatchHistoryItem{})
+	}
+
+	resp := h.App.ContinuityManager.GetRecentlyWatched(50)
+	return h.RespondWithData(c, resp)
+}
+
+// HandleGetContinuityResumeList
+//
+//	@summary Returns the episodes that can be resumed.
+//	@desc The episodes are sorted by the time they were last watched, most recent first.
+//	@route /api/v1/continuity/resume [GET]
+//	@returns []continuity.EpisodeWatchHistoryItem
+func (h *Handler) HandleGetContinuityResumeList(c echo.Context) error {
+	if !h.App.ContinuityManager.GetSettings().WatchContinuityEnabled {
+		return h.RespondWithData(c, []*continuity.EpisodeWatchHistoryItem{})
+	}
+
+	resp := h.App.ContinuityManager.GetResumeList()
+	return h.RespondWithData(c, resp)
+}
+
+// HandleGetContinuityWatchLog
+//
+//	@summary Returns the viewing sessions.
+//	@desc A session is created every time an episode is watched, the sessions are sorted from most recent to oldest.
+//	@desc The sessions can be filtered by media and by time range.
+//	@route /api/v1/continuity/log [POST]
+//	@returns []models.WatchLogEntry
+func (h *Handler) HandleGetContinuityWatchLog(c echo.Context) error {
+	type body struct {
+		Options continuity.GetWatchLogOptions `json:"options"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	resp, err := h.App.ContinuityManager.GetWatchLog(&b.Options)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, resp)
+}
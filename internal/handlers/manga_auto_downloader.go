@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/manga"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleRunMangaAutoDownloader
+//
+//	@summary tells the manga auto downloader to check for new chapters if enabled.
+//	@desc It does nothing if the manga auto downloader is disabled.
+//	@route /api/v1/manga/auto-downloader/run [POST]
+//	@returns bool
+func (h *Handler) HandleRunMangaAutoDownloader(c echo.Context) error {
+
+	h.App.MangaAutoDownloader.Run()
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetMangaAutoDownloaderRules
+//
+//	@summary returns all manga auto downloader rules.
+//	@desc It returns an empty slice if there are no rules.
+//	@route /api/v1/manga/auto-downloader/rules [GET]
+//	@returns []manga.AutoDownloaderRule
+func (h *Handler) HandleGetMangaAutoDownloaderRules(c echo.Context) error {
+	rules, err := db_bridge.GetMangaAutoDownloaderRules(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, rules)
+}
+
+// HandleGetMangaAutoDownloaderRulesByManga
+//
+//	@summary returns the manga auto downloader rules with the given media id.
+//	@route /api/v1/manga/auto-downloader/rule/manga/{id} [GET]
+//	@param id - int - true - "The AniList manga id of the rules"
+//	@returns []manga.AutoDownloaderRule
+func (h *Handler) HandleGetMangaAutoDownloaderRulesByManga(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	rules := db_bridge.GetMangaAutoDownloaderRulesByMediaId(h.App.Database, id)
+	return h.RespondWithData(c, rules)
+}
+
+// HandleCreateMangaAutoDownloaderRule
+//
+//	@summary creates a new manga auto downloader rule.
+//	@desc The body should contain the same fields as manga.AutoDownloaderRule.
+//	@desc It returns the created rule.
+//	@route /api/v1/manga/auto-downloader/rule [POST]
+//	@returns manga.AutoDownloaderRule
+func (h *Handler) HandleCreateMangaAutoDownloaderRule(c echo.Context) error {
+	type body struct {
+		Enabled    bool     `json:"enabled"`
+		MediaId    int      `json:"mediaId"`
+		Provider   string   `json:"provider"`
+		Scanlators []string `json:"scanlators"`
+		Language   string   `json:"language"`
+	}
+
+	var b body
+
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Provider == "" {
+		return h.RespondWithError(c, errors.New("provider is required"))
+	}
+
+	rule := &manga.AutoDownloaderRule{
+		Enabled:    b.Enabled,
+		MediaId:    b.MediaId,
+		Provider:   b.Provider,
+		Scanlators: b.Scanlators,
+		Language:   b.Language,
+	}
+
+	if err := db_bridge.InsertMangaAutoDownloaderRule(h.App.Database, rule); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, rule)
+}
+
+// HandleUpdateMangaAutoDownloaderRule
+//
+//	@summary updates a manga auto downloader rule.
+//	@desc The body should contain the same fields as manga.AutoDownloaderRule.
+//	@desc It returns the updated rule.
+//	@route /api/v1/manga/auto-downloader/rule [PATCH]
+//	@returns manga.AutoDownloaderRule
+func (h *Handler) HandleUpdateMangaAutoDownloaderRule(c echo.Context) error {
+
+	type body struct {
+		Rule *manga.AutoDownloaderRule `json:"rule"`
+	}
+
+	var b body
+
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.Rule == nil {
+		return h.RespondWithError(c, errors.New("invalid rule"))
+	}
+
+	if b.Rule.DbID == 0 {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	if b.Rule.Provider == "" {
+		return h.RespondWithError(c, errors.New("provider is required"))
+	}
+
+	// Update the rule based on its DbID (primary key)
+	if err := db_bridge.UpdateMangaAutoDownloaderRule(h.App.Database, b.Rule.DbID, b.Rule); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, b.Rule)
+}
+
+// HandleDeleteMangaAutoDownloaderRule
+//
+//	@summary deletes a manga auto downloader rule.
+//	@desc It returns 'true' if the rule was deleted.
+//	@route /api/v1/manga/auto-downloader/rule/{id} [DELETE]
+//	@param id - int - true - "The DB id of the rule"
+//	@returns bool
+func (h *Handler) HandleDeleteMangaAutoDownloaderRule(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	if err := db_bridge.DeleteMangaAutoDownloaderRule(h.App.Database, uint(id)); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
@@ -1,34 +1,45 @@
 package handlers
 
 import (
+	"errors"
+	"seanime/internal/mediaplayers/dlna"
+	"time"
+
 	"github.com/labstack/echo/v4"
 )
 
 // HandleStartDefaultMediaPlayer
 //
 //	@summary launches the default media player (vlc or mpc-hc).
+//	@desc For Kodi and DLNA renderers, this checks that they can be reached.
 //	@route /api/v1/media-player/start [POST]
 //	@returns bool
 func (h *Handler) HandleStartDefaultMediaPlayer(c echo.Context) error {
 
-	// Retrieve settings
-	settings, err := h.App.Database.GetSettings()
-	if err != nil {
-		return h.RespondWithError(c, err)
+	if h.App.MediaPlayerRepository == nil {
+		return h.RespondWithError(c, errors.New("media player settings not found"))
 	}
 
-	switch settings.MediaPlayer.Default {
-	case "vlc":
-		err = h.App.MediaPlayer.VLC.Start()
-		if err != nil {
-			return h.RespondWithError(c, err)
-		}
-	case "mpc-hc":
-		err = h.App.MediaPlayer.MpcHc.Start()
-		if err != nil {
-			return h.RespondWithError(c, err)
-		}
+	if err := h.App.MediaPlayerRepository.Start(); err != nil {
+		return h.RespondWithError(c, err)
 	}
 
 	return h.RespondWithData(c, true)
 }
+
+// HandleDiscoverDlnaRenderers
+//
+//	@summary searches the local network for DLNA renderers.
+//	@desc This returns the renderers that can be controlled, e.g. smart TVs.
+//	@desc The location of the selected renderer is saved in the media player settings.
+//	@route /api/v1/media-player/dlna/renderers [GET]
+//	@returns []dlna.Device
+func (h *Handler) HandleDiscoverDlnaRenderers(c echo.Context) error {
+
+	devices, err := dlna.Discover(c.Request().Context(), 3*time.Second)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, devices)
+}
@@ -3,10 +3,17 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"seanime/internal/database/db_bridge"
 	"seanime/internal/database/models"
+	"seanime/internal/library/anime"
 	"seanime/internal/mediastream"
+	"seanime/internal/mediastream/optimizer"
+	"seanime/internal/mediastream/skipdetect"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 )
 
 // HandleGetMediastreamSettings
@@ -63,6 +70,8 @@ func (h *Handler) HandleRequestMediastreamMediaContainer(c echo.Context) error {
 		StreamType       mediastream.StreamType `json:"streamType"`       // The type of stream to request.
 		AudioStreamIndex int                    `json:"audioStreamIndex"` // The audio stream index to use. (unused)
 		ClientId         string                 `json:"clientId"`         // The session id
+		// What the client can decode, when set the stream type is chosen from it.
+		Capabilities *mediastream.ClientCapabilities `json:"capabilities,omitempty"`
 	}
 
 	var b body
@@ -73,14 +82,21 @@ func (h *Handler) HandleRequestMediastreamMediaContainer(c echo.Context) error {
 	var mediaContainer *mediastream.MediaContainer
 	var err error
 
+	if b.Capabilities != nil {
+		mediaContainer, err = h.App.MediastreamRepository.RequestStreamForClient(b.Path, h.getMediastreamClientId(c), b.Capabilities)
+		if err != nil {
+			return h.RespondWithError(c, err)
+		}
+		return h.RespondWithData(c, mediaContainer)
+	}
+
 	switch b.StreamType {
 	case mediastream.StreamTypeDirect:
 		mediaContainer, err = h.App.MediastreamRepository.RequestDirectPlay(b.Path, b.ClientId)
 	case mediastream.StreamTypeTranscode:
-		mediaContainer, err = h.App.MediastreamRepository.RequestTranscodeStream(b.Path, b.ClientId)
+		mediaContainer, err = h.App.MediastreamRepository.RequestTranscodeStream(b.Path, h.getMediastreamClientId(c))
 	case mediastream.StreamTypeOptimized:
-		err = fmt.Errorf("stream type %s not implemented", b.StreamType)
-		//mediaContainer, err = h.App.MediastreamRepository.RequestOptimizedStream(b.Path)
+		mediaContainer, err = h.App.MediastreamRepository.RequestOptimizedStream(b.Path)
 	default:
 		err = fmt.Errorf("stream type %s not implemented", b.StreamType)
 	}
@@ -135,6 +151,292 @@ func (h *Handler) HandleMediastreamGetAttachments(c echo.Context) error {
 	return h.App.MediastreamRepository.ServeEchoExtractedAttachments(c)
 }
 
+// HandleGetMediastreamStreamTracks
+//
+//	@summary returns the subtitle tracks and fonts extracted from a torrent or debrid stream.
+//	@desc The ID is the extraction ID of the torrent stream session or the debrid stream.
+//	@desc The subtitle files are served by /mediastream/stream-subs/{id}/{link} and the fonts by /mediastream/stream-att/{id}/{font}.
+//	@desc Only the subtitle events of the time ranges in 'ranges' are in the files, use /mediastream/stream-tracks/{id}/load to extract the events around the playback position.
+//	@param id - string - true - "The extraction ID"
+//	@returns streamextract.Tracks
+//	@route /api/v1/mediastream/stream-tracks/{id} [GET]
+func (h *Handler) HandleGetMediastreamStreamTracks(c echo.Context) error {
+	tracks, err := h.App.StreamExtractor.GetTracks(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, tracks)
+}
+
+// HandleLoadMediastreamStreamTracks
+//
+//	@summary extracts the subtitle events around a position of a torrent or debrid stream.
+//	@desc Events are extracted by chunks of a few minutes, only the parts of the file containing the chunks are read.
+//	@desc The subtitle files are updated before the request returns.
+//	@param id - string - true - "The extraction ID"
+//	@returns streamextract.Tracks
+//	@route /api/v1/mediastream/stream-tracks/{id}/load [POST]
+func (h *Handler) HandleLoadMediastreamStreamTracks(c echo.Context) error {
+
+	type body struct {
+		// Playback position in seconds
+		Position float64 `json:"position"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	id := c.Param("id")
+
+	err := h.App.StreamExtractor.Load(id, time.Duration(b.Position*float64(time.Second)))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	tracks, err := h.App.StreamExtractor.GetTracks(id)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, tracks)
+}
+
+func (h *Handler) HandleMediastreamGetTrickplay(c echo.Context) error {
+	return h.App.MediastreamRepository.ServeEchoTrickplay(c)
+}
+
+// HandleMediastreamGenerateTrickplay
+//
+//	@summary queues the generation of seek-preview thumbnails.
+//	@desc If paths are provided, the files are processed one at a time in the background.
+//	@desc If no paths are provided, all local files of the library are added to the pre-transcoding queue,
+//	@desc which processes them during its time window. An error is returned if the queue is disabled.
+//	@desc Files that already have thumbnails are skipped.
+//	@returns bool
+//	@route /api/v1/mediastream/trickplay/generate [POST]
+func (h *Handler) HandleMediastreamGenerateTrickplay(c echo.Context) error {
+	type body struct {
+		Paths []string `json:"paths"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if len(b.Paths) > 0 {
+		if err := h.App.MediastreamRepository.GenerateTrickplayForFiles(b.Paths); err != nil {
+			return h.RespondWithError(c, err)
+		}
+		return h.RespondWithData(c, true)
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	files := lo.Map(lfs, func(lf *anime.LocalFile, _ int) *optimizer.QueueFile {
+		return &optimizer.QueueFile{Filepath: lf.GetPath(), MediaId: lf.MediaId}
+	})
+
+	if err := h.App.MediastreamRepository.EnqueueTrickplayGeneration(files); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetSkipMarkers
+//
+//	@summary returns the intro and outro of a local file.
+//	@desc Returns null if the file has not been analyzed.
+//	@returns models.EpisodeSkipMarkers
+//	@route /api/v1/mediastream/skip-markers [POST]
+func (h *Handler) HandleGetSkipMarkers(c echo.Context) error {
+	type body struct {
+		Path string `json:"path"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	markers, err := h.App.SkipDetector.GetMarkers(b.Path)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, markers)
+}
+
+// HandleGetMediaSkipMarkers
+//
+//	@summary returns the intro and outro of the analyzed episodes of a media.
+//	@returns []models.EpisodeSkipMarkers
+//	@param id - int - true - "AniList anime media ID"
+//	@route /api/v1/mediastream/skip-markers/{id} [GET]
+func (h *Handler) HandleGetMediaSkipMarkers(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	markers, err := h.App.SkipDetector.GetMediaMarkers(mId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, markers)
+}
+
+// HandleAnalyzeSkipMarkers
+//
+//	@summary detects the intro and outro of the episodes of a media.
+//	@desc The local files of the media are analyzed in the background, previous markers are replaced.
+//	@desc The events.SkipMarkersAnalyzed event is sent when the analysis is done.
+//	@returns bool
+//	@route /api/v1/mediastream/skip-markers/analyze [POST]
+func (h *Handler) HandleAnalyzeSkipMarkers(c echo.Context) error {
+	type body struct {
+		MediaId int `json:"mediaId"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	files := make([]*skipdetect.EpisodeFile, 0)
+	for _, lf := range lfs {
+		if lf.MediaId == b.MediaId && lf.IsMain() {
+			files = append(files, &skipdetect.EpisodeFile{
+				Path:          lf.GetPath(),
+				EpisodeNumber: lf.GetEpisodeNumber(),
+			})
+		}
+	}
+
+	if err := h.App.SkipDetector.AnalyzeMedia(b.MediaId, files); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetMediastreamOptimizationQueue
+//
+//	@summary returns the pre-transcoding queue.
+//	@desc The progress of the running item is sent by the events.MediastreamOptimizationProgress event.
+//	@returns []models.MediastreamOptimizationItem
+//	@route /api/v1/mediastream/optimizer/queue [GET]
+func (h *Handler) HandleGetMediastreamOptimizationQueue(c echo.Context) error {
+	queue, err := h.App.MediastreamRepository.GetMediaOptimizationQueue()
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, queue)
+}
+
+// HandleMediastreamEnqueueOptimization
+//
+//	@summary adds files to the pre-transcoding queue.
+//	@desc 'target' is "files" (the given paths), "series" (the local files of the media) or "library" (all local files).
+//	@desc If criteria is set, the files are analyzed in the background and only the ones matching the criteria are added.
+//	@desc Files that are already optimized are skipped. If quality is empty, the quality from the settings is used.
+//	@desc This only adds the files to the queue, they are optimized one at a time when the queue is enabled and during its time window.
+//	@returns bool
+//	@route /api/v1/mediastream/optimizer/queue [POST]
+func (h *Handler) HandleMediastreamEnqueueOptimization(c echo.Context) error {
+	type body struct {
+		Target   string              `json:"target"`
+		Paths    []string            `json:"paths"`
+		MediaId  int                 `json:"mediaId"`
+		Criteria *optimizer.Criteria `json:"criteria"`
+		Quality  optimizer.Quality   `json:"quality"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	var files []*optimizer.QueueFile
+	switch b.Target {
+	case "files":
+		files = lo.Map(b.Paths, func(path string, _ int) *optimizer.QueueFile {
+			return &optimizer.QueueFile{Filepath: path}
+		})
+	case "series", "library":
+		lfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
+		if err != nil {
+			return h.RespondWithError(c, err)
+		}
+		for _, lf := range lfs {
+			if lf.IsIgnored() || (b.Target == "series" && lf.MediaId != b.MediaId) {
+				continue
+			}
+			files = append(files, &optimizer.QueueFile{Filepath: lf.GetPath(), MediaId: lf.MediaId})
+		}
+	default:
+		return h.RespondWithError(c, fmt.Errorf("invalid target %q", b.Target))
+	}
+
+	if err := h.App.MediastreamRepository.EnqueueMediaOptimization(files, b.Criteria, b.Quality); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleMediastreamRemoveOptimizationItem
+//
+//	@summary removes an item from the pre-transcoding queue.
+//	@desc The item is stopped if it is running. If 'finished' is true, all the items that are done or failed are removed instead.
+//	@returns bool
+//	@route /api/v1/mediastream/optimizer/queue [DELETE]
+func (h *Handler) HandleMediastreamRemoveOptimizationItem(c echo.Context) error {
+	type body struct {
+		ID       uint `json:"id"`
+		Finished bool `json:"finished"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	var err error
+	if b.Finished {
+		err = h.App.MediastreamRepository.ClearFinishedMediaOptimizationItems()
+	} else {
+		err = h.App.MediastreamRepository.RemoveMediaOptimizationItem(b.ID)
+	}
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+func (h *Handler) HandleMediastreamGetStreamSubtitles(c echo.Context) error {
+	return h.App.StreamExtractor.ServeSubtitles(c)
+}
+
+func (h *Handler) HandleMediastreamGetStreamAttachments(c echo.Context) error {
+	return h.App.StreamExtractor.ServeAttachments(c)
+}
+
 //
 // Direct
 //
@@ -149,8 +451,15 @@ func (h *Handler) HandleMediastreamDirectPlay(c echo.Context) error {
 //
 
 func (h *Handler) HandleMediastreamTranscode(c echo.Context) error {
-	client := "1"
-	return h.App.MediastreamRepository.ServeEchoTranscodeStream(c, client)
+	return h.App.MediastreamRepository.ServeEchoTranscodeStream(c, h.getMediastreamClientId(c))
+}
+
+// getMediastreamClientId returns the client ID used to keep the transcoding state of each client.
+func (h *Handler) getMediastreamClientId(c echo.Context) string {
+	if clientId := h.getClientId(c); clientId != "" {
+		return clientId
+	}
+	return "1"
 }
 
 // HandleMediastreamShutdownTranscodeStream
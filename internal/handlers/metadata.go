@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"seanime/internal/api/metadata"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
@@ -146,3 +148,69 @@ func (h *Handler) HandleRemoveFillerData(c echo.Context) error {
 
 	return h.RespondWithData(c, true)
 }
+
+// HandleGetFillerPreference
+//
+//	@summary returns whether filler episodes of a media are skipped.
+//	@desc This returns the preference of the media, or the library settings if the media has none.
+//	@returns fillermanager.SkipFillerPreference
+//	@param id - int - true - "AniList anime media ID"
+//	@route /api/v1/metadata-provider/filler/preference/{id} [GET]
+func (h *Handler) HandleGetFillerPreference(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	return h.RespondWithData(c, h.App.FillerManager.GetSkipFillerPreference(mId))
+}
+
+// HandleSaveFillerPreference
+//
+//	@summary overrides the "skip filler" library settings for a media.
+//	@desc Skipped fillers are not played by autoplay, playlists, random play and the next episode of streams.
+//	@desc If markAsWatched is true, the progress is updated on the platform when fillers are skipped.
+//	@returns fillermanager.SkipFillerPreference
+//	@route /api/v1/metadata-provider/filler/preference [POST]
+func (h *Handler) HandleSaveFillerPreference(c echo.Context) error {
+	type body struct {
+		MediaId       int  `json:"mediaId"`
+		SkipFiller    bool `json:"skipFiller"`
+		MarkAsWatched bool `json:"markAsWatched"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if b.MediaId == 0 {
+		return h.RespondWithError(c, errors.New("invalid media id"))
+	}
+
+	if err := h.App.FillerManager.SaveSkipFillerPreference(b.MediaId, b.SkipFiller, b.MarkAsWatched); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, h.App.FillerManager.GetSkipFillerPreference(b.MediaId))
+}
+
+// HandleDeleteFillerPreference
+//
+//	@summary removes the "skip filler" preference of a media.
+//	@desc The library settings apply to the media again.
+//	@returns bool
+//	@param id - int - true - "AniList anime media ID"
+//	@route /api/v1/metadata-provider/filler/preference/{id} [DELETE]
+func (h *Handler) HandleDeleteFillerPreference(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	if err := h.App.FillerManager.DeleteSkipFillerPreference(mId); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
@@ -13,14 +13,18 @@ import (
 //
 //	@summary creates a new playlist.
 //	@desc This will create a new playlist with the given name and local file paths.
+//	@desc If rules are given, a smart playlist is created and the paths are ignored. The episodes of smart playlists are resolved when they are played.
+//	@desc If items are given, the playlist can mix episodes from the library and streamed episodes, the paths are ignored.
 //	@desc The response is ignored, the client should re-fetch the playlists after this.
 //	@route /api/v1/playlist [POST]
 //	@returns anime.Playlist
 func (h *Handler) HandleCreatePlaylist(c echo.Context) error {
 
 	type body struct {
-		Name  string   `json:"name"`
-		Paths []string `json:"paths"`
+		Name  string                    `json:"name"`
+		Paths []string                  `json:"paths"`
+		Items []*anime.PlaylistItem     `json:"items"`
+		Rules *anime.SmartPlaylistRules `json:"rules"`
 	}
 
 	var b body
@@ -28,6 +32,27 @@ func (h *Handler) HandleCreatePlaylist(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	if b.Rules != nil {
+		if err := b.Rules.Validate(); err != nil {
+			return h.RespondWithError(c, err)
+		}
+
+		playlist := anime.NewPlaylist(b.Name)
+		playlist.Rules = b.Rules
+
+		if err := db_bridge.SavePlaylist(h.App.Database, playlist); err != nil {
+			return h.RespondWithError(c, err)
+		}
+
+		return h.RespondWithData(c, playlist)
+	}
+
+	for _, item := range b.Items {
+		if err := item.Validate(); err != nil {
+			return h.RespondWithError(c, err)
+		}
+	}
+
 	// Get the local files
 	dbLfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
 	if err != nil {
@@ -48,6 +73,7 @@ func (h *Handler) HandleCreatePlaylist(c echo.Context) error {
 	// Create the playlist
 	playlist := anime.NewPlaylist(b.Name)
 	playlist.SetLocalFiles(lfs)
+	playlist.Items = b.Items
 
 	// Save the playlist
 	if err := db_bridge.SavePlaylist(h.App.Database, playlist); err != nil {
@@ -79,13 +105,17 @@ func (h *Handler) HandleGetPlaylists(c echo.Context) error {
 //	@desc The response is ignored, the client should re-fetch the playlists after this.
 //	@route /api/v1/playlist [PATCH]
 //	@param id - int - true - "The ID of the playlist to update."
+//	@desc If rules are given, the playlist becomes a smart playlist and the paths are ignored.
+//	@desc If items are given, the playlist can mix episodes from the library and streamed episodes, the paths are ignored.
 //	@returns anime.Playlist
 func (h *Handler) HandleUpdatePlaylist(c echo.Context) error {
 
 	type body struct {
-		DbId  uint     `json:"dbId"`
-		Name  string   `json:"name"`
-		Paths []string `json:"paths"`
+		DbId  uint                      `json:"dbId"`
+		Name  string                    `json:"name"`
+		Paths []string                  `json:"paths"`
+		Items []*anime.PlaylistItem     `json:"items"`
+		Rules *anime.SmartPlaylistRules `json:"rules"`
 	}
 
 	var b body
@@ -93,6 +123,28 @@ func (h *Handler) HandleUpdatePlaylist(c echo.Context) error {
 		return h.RespondWithError(c, err)
 	}
 
+	if b.Rules != nil {
+		if err := b.Rules.Validate(); err != nil {
+			return h.RespondWithError(c, err)
+		}
+
+		playlist := anime.NewPlaylist(b.Name)
+		playlist.DbId = b.DbId
+		playlist.Rules = b.Rules
+
+		if err := db_bridge.UpdatePlaylist(h.App.Database, playlist); err != nil {
+			return h.RespondWithError(c, err)
+		}
+
+		return h.RespondWithData(c, playlist)
+	}
+
+	for _, item := range b.Items {
+		if err := item.Validate(); err != nil {
+			return h.RespondWithError(c, err)
+		}
+	}
+
 	// Get the local files
 	dbLfs, _, err := db_bridge.GetLocalFiles(h.App.Database)
 	if err != nil {
@@ -115,6 +167,7 @@ func (h *Handler) HandleUpdatePlaylist(c echo.Context) error {
 	playlist.DbId = b.DbId
 	playlist.Name = b.Name
 	playlist.SetLocalFiles(lfs)
+	playlist.Items = b.Items
 
 	// Save the playlist
 	if err := db_bridge.UpdatePlaylist(h.App.Database, playlist); err != nil {
@@ -148,6 +201,32 @@ func (h *Handler) HandleDeletePlaylist(c echo.Context) error {
 	return h.RespondWithData(c, true)
 }
 
+// HandleResolveSmartPlaylist
+//
+//	@summary returns the episodes matching the rules of a smart playlist.
+//	@desc This is used to preview a smart playlist before saving it.
+//	@desc The episodes are resolved against the current AniList collection and local files.
+//	@route /api/v1/playlist/smart/resolve [POST]
+//	@returns []anime.LocalFile
+func (h *Handler) HandleResolveSmartPlaylist(c echo.Context) error {
+
+	type body struct {
+		Rules *anime.SmartPlaylistRules `json:"rules"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	lfs, err := h.App.PlaybackManager.ResolveSmartPlaylist(b.Rules)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, lfs)
+}
+
 // HandleGetPlaylistEpisodes
 //
 //	@summary returns all the local files of a playlist media entry that have not been watched.
@@ -39,6 +39,7 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 		"/api/v1/mediastream/transcode/",
 		"/api/v1/torrent-client/list",
 		"/api/v1/proxy",
+		"/api/v1/watch-party/ws",
 	}
 
 	// Logging middleware
@@ -136,10 +137,16 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.GET("/auto-downloader/rule/:id", h.HandleGetAutoDownloaderRule)
 	v1.GET("/auto-downloader/rule/anime/:id", h.HandleGetAutoDownloaderRulesByAnime)
 	v1.GET("/auto-downloader/rules", h.HandleGetAutoDownloaderRules)
+	v1.GET("/auto-downloader/rules/schedule", h.HandleGetAutoDownloaderRuleSchedules)
 	v1.POST("/auto-downloader/rule", h.HandleCreateAutoDownloaderRule)
 	v1.PATCH("/auto-downloader/rule", h.HandleUpdateAutoDownloaderRule)
 	v1.DELETE("/auto-downloader/rule/:id", h.HandleDeleteAutoDownloaderRule)
 
+	v1.GET("/auto-downloader/templates", h.HandleGetAutoDownloaderRuleTemplates)
+	v1.POST("/auto-downloader/template", h.HandleCreateAutoDownloaderRuleTemplate)
+	v1.PATCH("/auto-downloader/template", h.HandleUpdateAutoDownloaderRuleTemplate)
+	v1.DELETE("/auto-downloader/template/:id", h.HandleDeleteAutoDownloaderRuleTemplate)
+
 	v1.GET("/auto-downloader/items", h.HandleGetAutoDownloaderItems)
 	v1.DELETE("/auto-downloader/item", h.HandleDeleteAutoDownloaderItem)
 
@@ -151,6 +158,24 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.POST("/open-in-explorer", h.HandleOpenInExplorer)
 
 	v1.POST("/media-player/start", h.HandleStartDefaultMediaPlayer)
+	v1.GET("/media-player/dlna/renderers", h.HandleDiscoverDlnaRenderers)
+
+	v1.GET("/track-preferences", h.HandleGetAllTrackPreferences)
+	v1.GET("/track-preferences/:id", h.HandleGetTrackPreferences)
+	v1.POST("/track-preferences", h.HandleSaveTrackPreferences)
+	v1.DELETE("/track-preferences/:id", h.HandleDeleteTrackPreferences)
+
+	v1.GET("/watch-party", h.HandleGetWatchParty)
+	v1.POST("/watch-party/host", h.HandleHostWatchParty)
+	v1.POST("/watch-party/join", h.HandleJoinWatchParty)
+	v1.POST("/watch-party/leave", h.HandleLeaveWatchParty)
+	v1.POST("/watch-party/play", h.HandleWatchPartyPlay)
+	v1.POST("/watch-party/pause", h.HandleWatchPartyPause)
+	v1.POST("/watch-party/resume", h.HandleWatchPartyResume)
+	v1.POST("/watch-party/seek", h.HandleWatchPartySeek)
+	v1.POST("/watch-party/transfer", h.HandleTransferWatchPartyHost)
+	// Connections of the members when this instance hosts a watch party
+	v1.GET("/watch-party/ws", echo.WrapHandler(h.App.WatchPartyManager))
 
 	//
 	// AniList
@@ -281,6 +306,7 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.POST("/playlist", h.HandleCreatePlaylist)
 	v1.PATCH("/playlist", h.HandleUpdatePlaylist)
 	v1.DELETE("/playlist", h.HandleDeletePlaylist)
+	v1.POST("/playlist/smart/resolve", h.HandleResolveSmartPlaylist)
 	v1.GET("/playlist/episodes/:id/:progress", h.HandleGetPlaylistEpisodes)
 
 	//
@@ -305,6 +331,9 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 
 	v1.POST("/metadata-provider/filler", h.HandlePopulateFillerData)
 	v1.DELETE("/metadata-provider/filler", h.HandleRemoveFillerData)
+	v1.GET("/metadata-provider/filler/preference/:id", h.HandleGetFillerPreference)
+	v1.POST("/metadata-provider/filler/preference", h.HandleSaveFillerPreference)
+	v1.DELETE("/metadata-provider/filler/preference/:id", h.HandleDeleteFillerPreference)
 
 	//
 	// Manga
@@ -336,6 +365,13 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1Manga.DELETE("/download-queue", h.HandleClearAllChapterDownloadQueue)
 	v1Manga.POST("/download-queue/reset-errored", h.HandleResetErroredChapterDownloadQueue)
 
+	v1Manga.POST("/auto-downloader/run", h.HandleRunMangaAutoDownloader)
+	v1Manga.GET("/auto-downloader/rules", h.HandleGetMangaAutoDownloaderRules)
+	v1Manga.GET("/auto-downloader/rule/manga/:id", h.HandleGetMangaAutoDownloaderRulesByManga)
+	v1Manga.POST("/auto-downloader/rule", h.HandleCreateMangaAutoDownloaderRule)
+	v1Manga.PATCH("/auto-downloader/rule", h.HandleUpdateMangaAutoDownloaderRule)
+	v1Manga.DELETE("/auto-downloader/rule/:id", h.HandleDeleteMangaAutoDownloaderRule)
+
 	v1Manga.POST("/search", h.HandleMangaManualSearch)
 	v1Manga.POST("/manual-mapping", h.HandleMangaManualMapping)
 	v1Manga.POST("/get-mapping", h.HandleGetMangaMapping)
@@ -372,10 +408,28 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.GET("/mediastream/transcode/*", h.HandleMediastreamTranscode)
 	v1.GET("/mediastream/subs/*", h.HandleMediastreamGetSubtitles)
 	v1.GET("/mediastream/att/*", h.HandleMediastreamGetAttachments)
+	v1.GET("/mediastream/trickplay/*", h.HandleMediastreamGetTrickplay)
+	v1.POST("/mediastream/trickplay/generate", h.HandleMediastreamGenerateTrickplay)
+	v1.POST("/mediastream/skip-markers", h.HandleGetSkipMarkers)
+	v1.GET("/mediastream/skip-markers/:id", h.HandleGetMediaSkipMarkers)
+	v1.POST("/mediastream/skip-markers/analyze", h.HandleAnalyzeSkipMarkers)
+	v1.GET("/mediastream/optimizer/queue", h.HandleGetMediastreamOptimizationQueue)
+	v1.POST("/mediastream/optimizer/queue", h.HandleMediastreamEnqueueOptimization)
+	v1.DELETE("/mediastream/optimizer/queue", h.HandleMediastreamRemoveOptimizationItem)
+	v1.GET("/mediastream/stream-tracks/:id", h.HandleGetMediastreamStreamTracks)
+	v1.POST("/mediastream/stream-tracks/:id/load", h.HandleLoadMediastreamStreamTracks)
+	v1.GET("/mediastream/stream-subs/:id/*", h.HandleMediastreamGetStreamSubtitles)
+	v1.GET("/mediastream/stream-att/:id/*", h.HandleMediastreamGetStreamAttachments)
 	v1.GET("/mediastream/direct", h.HandleMediastreamDirectPlay)
 	v1.HEAD("/mediastream/direct", h.HandleMediastreamDirectPlay)
 	v1.GET("/mediastream/file/*", h.HandleMediastreamFile)
 
+	// DLNA server
+	dlnaHandler := echo.WrapHandler(h.App.DlnaServer)
+	v1.Any("/dlna/*", dlnaHandler)
+	v1.Add("SUBSCRIBE", "/dlna/*", dlnaHandler)
+	v1.Add("UNSUBSCRIBE", "/dlna/*", dlnaHandler)
+
 	//
 	// Torrent stream
 	//
@@ -384,7 +438,13 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1.PATCH("/torrentstream/settings", h.HandleSaveTorrentstreamSettings)
 	v1.POST("/torrentstream/start", h.HandleTorrentstreamStartStream)
 	v1.POST("/torrentstream/stop", h.HandleTorrentstreamStopStream)
+	v1.POST("/torrentstream/resume", h.HandleTorrentstreamResumeStream)
+	v1.GET("/torrentstream/resumable", h.HandleGetTorrentstreamResumableSessions)
+	v1.DELETE("/torrentstream/resumable", h.HandleDismissTorrentstreamResumableSession)
 	v1.POST("/torrentstream/drop", h.HandleTorrentstreamDropTorrent)
+	v1.GET("/torrentstream/sessions", h.HandleGetTorrentstreamSessions)
+	v1.GET("/torrentstream/cache", h.HandleGetTorrentstreamCache)
+	v1.DELETE("/torrentstream/cache", h.HandleRemoveTorrentstreamCacheEntry)
 	v1.POST("/torrentstream/torrent-file-previews", h.HandleGetTorrentstreamTorrentFilePreviews)
 	v1.POST("/torrentstream/batch-history", h.HandleGetTorrentstreamBatchHistory)
 	v1.GET("/torrentstream/stream/*", echo.WrapHandler(h.HandleTorrentstreamServeStream()))
@@ -421,6 +481,18 @@ func InitRoutes(app *core.App, e *echo.Echo) {
 	v1Continuity.PATCH("/item", h.HandleUpdateContinuityWatchHistoryItem)
 	v1Continuity.GET("/item/:id", h.HandleGetContinuityWatchHistoryItem)
 	v1Continuity.GET("/history", h.HandleGetContinuityWatchHistory)
+	v1Continuity.GET("/item/:id/episode/:episode", h.HandleGetContinuityEpisodeWatchHistoryItem)
+	v1Continuity.GET("/recent", h.HandleGetContinuityRecentlyWatched)
+	v1Continuity.GET("/resume", h.HandleGetContinuityResumeList)
+	v1Continuity.POST("/log", h.HandleGetContinuityWatchLog)
+
+	//
+	// Viewing stats
+	//
+	v1.POST("/viewing-stats", h.HandleGetViewingStats)
+	v1.POST("/viewing-stats/progress", h.HandleRecordViewingProgress)
+	v1.GET("/viewing-stats/recap/:year", h.HandleGetViewingStatsYearlyRecap)
+	v1.GET("/viewing-stats/recap/:year/export", h.HandleExportViewingStatsYearlyRecap)
 
 	//
 	// Sync
@@ -473,6 +545,12 @@ func (h *Handler) RespondWithError(c echo.Context, err error) error {
 	return c.JSON(500, NewErrorResponse(err))
 }
 
+// getClientId returns the ID of the client that made the request, set by the client ID middleware.
+func (h *Handler) getClientId(c echo.Context) string {
+	clientId, _ := c.Get("Seanime-Client-Id").(string)
+	return clientId
+}
+
 func headMethodMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if c.Request().Method == http.MethodHead {
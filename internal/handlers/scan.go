@@ -95,6 +95,9 @@ func (h *Handler) HandleScanLocalFiles(c echo.Context) error {
 	// Save the scan summary
 	_ = db_bridge.InsertScanSummary(h.App.Database, scanSummaryLogger.GenerateSummary())
 
+	// Queue the new downloads for pre-transcoding
+	go h.App.MediastreamRepository.EnqueueNewDownloads(existingLfs, allLfs)
+
 	go h.App.AutoDownloader.CleanUpDownloadedItems()
 
 	return h.RespondWithData(c, lfs)
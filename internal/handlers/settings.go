@@ -250,6 +250,11 @@ func (h *Handler) HandleSaveAutoDownloaderSettings(c echo.Context) error {
 		EnableEnhancedQueries bool `json:"enableEnhancedQueries"`
 		EnableSeasonCheck     bool `json:"enableSeasonCheck"`
 		UseDebrid             bool `json:"useDebrid"`
+		UseAiringSchedule     bool `json:"useAiringSchedule"`
+		BurstInterval         int  `json:"burstInterval"`
+		BurstWindow           int  `json:"burstWindow"`
+		SyncRules             bool `json:"syncRules"`
+		SyncRulesTemplateId   uint `json:"syncRulesTemplateId"`
 	}
 
 	var b body
@@ -267,6 +272,12 @@ func (h *Handler) HandleSaveAutoDownloaderSettings(c echo.Context) error {
 	if b.Interval < 15 {
 		return h.RespondWithError(c, errors.New("interval must be at least 15 minutes"))
 	}
+	if b.BurstInterval < 0 || b.BurstWindow < 0 {
+		return h.RespondWithError(c, errors.New("burst interval and window must be positive"))
+	}
+	if b.SyncRules && b.SyncRulesTemplateId == 0 {
+		return h.RespondWithError(c, errors.New("a rule template is required to sync rules"))
+	}
 
 	autoDownloaderSettings := &models.AutoDownloaderSettings{
 		Provider:              currSettings.Library.TorrentProvider,
@@ -276,6 +287,11 @@ func (h *Handler) HandleSaveAutoDownloaderSettings(c echo.Context) error {
 		EnableEnhancedQueries: b.EnableEnhancedQueries,
 		EnableSeasonCheck:     b.EnableSeasonCheck,
 		UseDebrid:             b.UseDebrid,
+		UseAiringSchedule:     b.UseAiringSchedule,
+		BurstInterval:         b.BurstInterval,
+		BurstWindow:           b.BurstWindow,
+		SyncRules:             b.SyncRules,
+		SyncRulesTemplateId:   b.SyncRulesTemplateId,
 	}
 
 	currSettings.AutoDownloader = autoDownloaderSettings
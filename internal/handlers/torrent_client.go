@@ -6,6 +6,7 @@ import (
 	"seanime/internal/api/anilist"
 	"seanime/internal/database/db_bridge"
 	"seanime/internal/events"
+	"seanime/internal/library/autodownloader"
 	"seanime/internal/torrent_clients/torrent_client"
 	"seanime/internal/util"
 
@@ -178,7 +179,7 @@ func (h *Handler) HandleTorrentClientDownload(c echo.Context) error {
 		}
 
 		// try to add torrents to client, on error return error
-		err = h.App.TorrentClientRepository.AddMagnets(magnets, b.Destination)
+		err = h.App.TorrentClientRepository.AddMagnets(magnets, b.Destination, nil)
 		if err != nil {
 			return h.RespondWithError(c, err)
 		}
@@ -248,8 +249,19 @@ func (h *Handler) HandleTorrentClientAddMagnetFromRule(c echo.Context) error {
 		return h.RespondWithError(c, errors.New("could not start torrent client, verify your settings"))
 	}
 
+	// Resolve the destination template
+	destination := rule.Destination
+	if animeCollection, err := h.App.GetAnimeCollection(false); err == nil {
+		if listEntry, found := animeCollection.GetListEntryFromAnimeId(rule.MediaId); found {
+			destination = autodownloader.ResolveRuleDestination(rule, listEntry.GetMedia())
+		}
+	}
+
 	// try to add torrents to client, on error return error
-	err = h.App.TorrentClientRepository.AddMagnets([]string{b.MagnetUrl}, rule.Destination)
+	err = h.App.TorrentClientRepository.AddMagnets([]string{b.MagnetUrl}, destination, &torrent_client.AddMagnetsOptions{
+		Category: rule.TorrentCategory,
+		Tags:     rule.TorrentTags,
+	})
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
@@ -17,6 +17,8 @@ var debridInstantAvailabilityCache = result.NewCache[string, map[string]debrid.T
 //	@summary searches torrents and returns a list of torrents and their previews.
 //	@desc This will search for torrents and return a list of torrents with previews.
 //	@desc If smart search is enabled, it will filter the torrents based on search parameters.
+//	@desc If aggregate is true, all providers are searched and the results are merged by info hash.
+//	@desc Providers that fail are listed in 'providerErrors' instead of failing the whole search.
 //	@route /api/v1/torrent/search [POST]
 //	@returns torrent.SearchData
 func (h *Handler) HandleSearchTorrent(c echo.Context) error {
@@ -32,6 +34,8 @@ func (h *Handler) HandleSearchTorrent(c echo.Context) error {
 		AbsoluteOffset int               `json:"absoluteOffset,omitempty"`
 		Resolution     string            `json:"resolution,omitempty"`
 		BestRelease    bool              `json:"bestRelease,omitempty"`
+		// Search all providers and merge the results
+		Aggregate bool `json:"aggregate,omitempty"`
 	}
 
 	var b body
@@ -41,6 +45,7 @@ func (h *Handler) HandleSearchTorrent(c echo.Context) error {
 
 	data, err := h.App.TorrentRepository.SearchAnime(c.Request().Context(), torrent.AnimeSearchOptions{
 		Provider:      b.Provider,
+		Aggregate:     b.Aggregate,
 		Type:          torrent.AnimeSearchType(b.Type),
 		Media:         &b.Media,
 		Query:         b.Query,
@@ -38,6 +38,7 @@ func (h *Handler) HandleGetTorrentstreamEpisodeCollection(c echo.Context) error
 	lop.ForEach(ec.Episodes, func(e *anime.Episode, _ int) {
 		h.App.FillerManager.HydrateEpisodeFillerData(mId, e)
 	})
+	ec.SkipFillers = h.App.FillerManager.GetSkipFillerPreference(mId).SkipFiller
 
 	return h.RespondWithData(c, ec)
 }
@@ -192,16 +193,100 @@ func (h *Handler) HandleTorrentstreamStartStream(c echo.Context) error {
 	return h.RespondWithData(c, true)
 }
 
+// HandleGetTorrentstreamResumableSessions
+//
+//	@summary returns the torrent streams that can be resumed.
+//	@desc Active streams are persisted so that they can be resumed after a restart.
+//	@returns []torrentstream.PersistedSession
+//	@route /api/v1/torrentstream/resumable [GET]
+func (h *Handler) HandleGetTorrentstreamResumableSessions(c echo.Context) error {
+	return h.RespondWithData(c, h.App.TorrentstreamRepository.GetResumableSessions())
+}
+
+// HandleTorrentstreamResumeStream
+//
+//	@summary resumes a persisted torrent stream.
+//	@desc The torrent is added again using the data already on disk, only the missing pieces are downloaded.
+//	@returns bool
+//	@route /api/v1/torrentstream/resume [POST]
+func (h *Handler) HandleTorrentstreamResumeStream(c echo.Context) error {
+
+	type body struct {
+		SessionId    string                     `json:"sessionId"`
+		PlaybackType torrentstream.PlaybackType `json:"playbackType"` // "default" or "externalPlayerLink"
+		ClientId     string                     `json:"clientId"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	userAgent := c.Request().Header.Get("User-Agent")
+
+	err := h.App.TorrentstreamRepository.ResumeStream(&torrentstream.ResumeStreamOptions{
+		SessionId:    b.SessionId,
+		ClientId:     b.ClientId,
+		PlaybackType: b.PlaybackType,
+		UserAgent:    userAgent,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleDismissTorrentstreamResumableSession
+//
+//	@summary dismisses a torrent stream that can be resumed.
+//	@desc This deletes the persisted stream and the downloaded files of its torrent.
+//	@returns bool
+//	@route /api/v1/torrentstream/resumable [DELETE]
+func (h *Handler) HandleDismissTorrentstreamResumableSession(c echo.Context) error {
+
+	type body struct {
+		SessionId string `json:"sessionId"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	err := h.App.TorrentstreamRepository.DismissResumableSession(b.SessionId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
 // HandleTorrentstreamStopStream
 //
 //	@summary stop a torrent stream.
 //	@desc This stops the entire streaming process and drops the torrent if it's below a threshold.
 //	@desc This is made to be used while the stream is running.
+//	@desc Only the stream of the client making the request is stopped, unless 'all' is true.
 //	@returns bool
 //	@route /api/v1/torrentstream/stop [POST]
 func (h *Handler) HandleTorrentstreamStopStream(c echo.Context) error {
 
-	err := h.App.TorrentstreamRepository.StopStream()
+	type body struct {
+		All bool `json:"all"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	var err error
+	if b.All {
+		err = h.App.TorrentstreamRepository.StopAllStreams()
+	} else {
+		err = h.App.TorrentstreamRepository.StopStream(h.getClientId(c))
+	}
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
@@ -214,11 +299,72 @@ func (h *Handler) HandleTorrentstreamStopStream(c echo.Context) error {
 //	@summary drops a torrent stream.
 //	@desc This stops the entire streaming process and drops the torrent completely.
 //	@desc This is made to be used to force drop a torrent.
+//	@desc Only the torrent of the client making the request is dropped, unless 'all' is true, in which case all streams are stopped and all torrents are dropped.
 //	@returns bool
 //	@route /api/v1/torrentstream/drop [POST]
 func (h *Handler) HandleTorrentstreamDropTorrent(c echo.Context) error {
 
-	err := h.App.TorrentstreamRepository.DropTorrent()
+	type body struct {
+		All bool `json:"all"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	var err error
+	if b.All {
+		err = h.App.TorrentstreamRepository.DropAllTorrents()
+	} else {
+		err = h.App.TorrentstreamRepository.DropTorrent(h.getClientId(c))
+	}
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleGetTorrentstreamSessions
+//
+//	@summary returns the active torrent streams.
+//	@desc Each client can stream one torrent at a time, up to the maximum number of concurrent streams.
+//	@returns []torrentstream.SessionInfo
+//	@route /api/v1/torrentstream/sessions [GET]
+func (h *Handler) HandleGetTorrentstreamSessions(c echo.Context) error {
+	return h.RespondWithData(c, h.App.TorrentstreamRepository.GetSessions())
+}
+
+// HandleGetTorrentstreamCache
+//
+//	@summary returns the torrents kept after their stream stopped.
+//	@desc The torrents are seeded until the seeding goal is reached and evicted according to the retention policy.
+//	@returns []torrentstream.CacheEntryInfo
+//	@route /api/v1/torrentstream/cache [GET]
+func (h *Handler) HandleGetTorrentstreamCache(c echo.Context) error {
+	return h.RespondWithData(c, h.App.TorrentstreamRepository.GetCacheEntries())
+}
+
+// HandleRemoveTorrentstreamCacheEntry
+//
+//	@summary removes a torrent from the stream cache.
+//	@desc This drops the torrent and deletes its files.
+//	@desc If no info hash is provided, the whole cache is cleared.
+//	@returns bool
+//	@route /api/v1/torrentstream/cache [DELETE]
+func (h *Handler) HandleRemoveTorrentstreamCacheEntry(c echo.Context) error {
+
+	type body struct {
+		InfoHash string `json:"infoHash"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	err := h.App.TorrentstreamRepository.RemoveCacheEntry(b.InfoHash)
 	if err != nil {
 		return h.RespondWithError(c, err)
 	}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"seanime/internal/database/models"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetAllTrackPreferences
+//
+//	@summary returns the default track preferences and those of all the media.
+//	@desc The defaults have a mediaId of 0.
+//	@returns []models.TrackPreferences
+//	@route /api/v1/track-preferences [GET]
+func (h *Handler) HandleGetAllTrackPreferences(c echo.Context) error {
+	prefs, err := h.App.TrackPreferences.GetAllPreferences()
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, prefs)
+}
+
+// HandleGetTrackPreferences
+//
+//	@summary returns the track preferences of a media.
+//	@desc Use 0 as ID to get the defaults. Returns null if no preferences are set.
+//	@returns models.TrackPreferences
+//	@param id - int - true - "AniList anime media ID, 0 for the defaults"
+//	@route /api/v1/track-preferences/{id} [GET]
+func (h *Handler) HandleGetTrackPreferences(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	prefs, err := h.App.TrackPreferences.GetPreferences(mId)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, prefs)
+}
+
+// HandleSaveTrackPreferences
+//
+//	@summary saves the track preferences of a media.
+//	@desc Use 0 as mediaId to save the defaults. Languages are comma-separated codes by priority, e.g. "ja,en".
+//	@desc The subtitle title pattern is a case-insensitive regular expression matched against the names of the tracks.
+//	@returns models.TrackPreferences
+//	@route /api/v1/track-preferences [POST]
+func (h *Handler) HandleSaveTrackPreferences(c echo.Context) error {
+	type body struct {
+		MediaId              int     `json:"mediaId"`
+		AudioLanguage        string  `json:"audioLanguage"`
+		SubtitleLanguage     string  `json:"subtitleLanguage"`
+		SubtitleTitlePattern string  `json:"subtitleTitlePattern"`
+		SubtitleDelay        float64 `json:"subtitleDelay"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	prefs := &models.TrackPreferences{
+		MediaId:              b.MediaId,
+		AudioLanguage:        b.AudioLanguage,
+		SubtitleLanguage:     b.SubtitleLanguage,
+		SubtitleTitlePattern: b.SubtitleTitlePattern,
+		SubtitleDelay:        b.SubtitleDelay,
+	}
+	if err := h.App.TrackPreferences.SavePreferences(prefs); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, prefs)
+}
+
+// HandleDeleteTrackPreferences
+//
+//	@summary deletes the track preferences of a media.
+//	@desc Use 0 as ID to delete the defaults.
+//	@returns bool
+//	@param id - int - true - "AniList anime media ID, 0 for the defaults"
+//	@route /api/v1/track-preferences/{id} [DELETE]
+func (h *Handler) HandleDeleteTrackPreferences(c echo.Context) error {
+	mId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return h.RespondWithError(c, errors.New("invalid id"))
+	}
+
+	if err := h.App.TrackPreferences.DeletePreferences(mId); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"seanime/internal/continuity"
+	"seanime/internal/library/viewingstats"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetViewingStats
+//
+//	@summary returns the local viewing statistics.
+//	@desc The statistics are computed from the watch log recorded by Seanime, unlike the AniList statistics.
+//	@desc Only the sessions that started in the time range are counted, omit the times to get the statistics of all time.
+//	@route /api/v1/viewing-stats [POST]
+//	@returns viewingstats.ViewingStats
+func (h *Handler) HandleGetViewingStats(c echo.Context) error {
+	type body struct {
+		From *time.Time `json:"from"`
+		To   *time.Time `json:"to"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	var from, to time.Time
+	if b.From != nil {
+		from = *b.From
+	}
+	if b.To != nil {
+		to = *b.To
+	}
+
+	// The collection is only used for the titles and genres
+	animeCollection, _ := h.App.GetAnimeCollection(false)
+
+	stats, err := h.App.ViewingStats.GetStats(from, to, animeCollection)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, stats)
+}
+
+// HandleGetViewingStatsYearlyRecap
+//
+//	@summary returns the viewing recap of a year.
+//	@route /api/v1/viewing-stats/recap/{year} [GET]
+//	@param year - int - true - "The year of the recap"
+//	@returns viewingstats.YearlyRecap
+func (h *Handler) HandleGetViewingStatsYearlyRecap(c echo.Context) error {
+	recap, err := h.getViewingStatsYearlyRecap(c)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, recap)
+}
+
+// HandleExportViewingStatsYearlyRecap
+//
+//	@summary exports the viewing recap of a year as a JSON file.
+//	@route /api/v1/viewing-stats/recap/{year}/export [GET]
+//	@param year - int - true - "The year of the recap"
+func (h *Handler) HandleExportViewingStatsYearlyRecap(c echo.Context) error {
+	recap, err := h.getViewingStatsYearlyRecap(c)
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	filename := fmt.Sprintf("seanime-recap-%d.json", recap.Year)
+
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().Header().Set("Content-Type", "application/json")
+
+	jsonData, err := json.MarshalIndent(recap, "", "  ")
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return c.Blob(200, "application/json", jsonData)
+}
+
+func (h *Handler) getViewingStatsYearlyRecap(c echo.Context) (*viewingstats.YearlyRecap, error) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		return nil, errors.New("invalid year")
+	}
+
+	animeCollection, _ := h.App.GetAnimeCollection(false)
+
+	return h.App.ViewingStats.GetYearlyRecap(year, animeCollection)
+}
+
+// HandleRecordViewingProgress
+//
+//	@summary records the progress of an episode played in the client.
+//	@desc This is used by the built-in players (online streaming and media streaming), the other players are tracked by the server.
+//	@desc The time played is added to the watch log entry of the episode, it should be called periodically while the episode is playing.
+//	@route /api/v1/viewing-stats/progress [POST]
+//	@returns bool
+func (h *Handler) HandleRecordViewingProgress(c echo.Context) error {
+	type body struct {
+		Options continuity.LogWatchProgressOptions `json:"options"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	switch viewingstats.Source(b.Options.Source) {
+	case viewingstats.SourceOnlineStream:
+		b.Options.Kind = continuity.OnlinestreamKind
+	case viewingstats.SourceMediastream:
+		b.Options.Kind = continuity.MediastreamKind
+	default:
+		return h.RespondWithError(c, fmt.Errorf("invalid source %q", b.Options.Source))
+	}
+
+	h.App.ContinuityManager.LogWatchProgress(&b.Options)
+
+	return h.RespondWithData(c, true)
+}
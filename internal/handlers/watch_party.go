@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"seanime/internal/watchparty"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HandleGetWatchParty
+//
+//	@summary returns the current watch party.
+//	@desc Returns null if this instance is not in a watch party.
+//	@returns watchparty.Session
+//	@route /api/v1/watch-party [GET]
+func (h *Handler) HandleGetWatchParty(c echo.Context) error {
+	return h.RespondWithData(c, h.App.WatchPartyManager.GetSession())
+}
+
+// HandleHostWatchParty
+//
+//	@summary starts a watch party hosted by this instance.
+//	@desc Other instances join it with the address of this instance and the passcode, which is required.
+//	@returns watchparty.Session
+//	@route /api/v1/watch-party/host [POST]
+func (h *Handler) HandleHostWatchParty(c echo.Context) error {
+	type body struct {
+		Name     string `json:"name"`
+		Passcode string `json:"passcode"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	session, err := h.App.WatchPartyManager.Host(&watchparty.HostOptions{
+		Name:     b.Name,
+		Passcode: b.Passcode,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, session)
+}
+
+// HandleJoinWatchParty
+//
+//	@summary joins the watch party hosted by another instance.
+//	@desc The address is the URL of the Seanime instance of the host, e.g. "http://192.168.1.10:43211".
+//	@returns watchparty.Session
+//	@route /api/v1/watch-party/join [POST]
+func (h *Handler) HandleJoinWatchParty(c echo.Context) error {
+	type body struct {
+		Address  string `json:"address"`
+		Name     string `json:"name"`
+		Passcode string `json:"passcode"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	session, err := h.App.WatchPartyManager.Join(&watchparty.JoinOptions{
+		Address:  b.Address,
+		Name:     b.Name,
+		Passcode: b.Passcode,
+	})
+	if err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, session)
+}
+
+// HandleLeaveWatchParty
+//
+//	@summary leaves the current watch party.
+//	@desc The watch party ends for everyone if this instance is the host.
+//	@returns bool
+//	@route /api/v1/watch-party/leave [POST]
+func (h *Handler) HandleLeaveWatchParty(c echo.Context) error {
+	if err := h.App.WatchPartyManager.Leave(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleWatchPartyPlay
+//
+//	@summary plays an episode for the whole watch party.
+//	@desc Local files are matched by media ID and episode number on each instance.
+//	@desc Torrent streams use the selected torrent if this instance is the host, a torrent is selected automatically otherwise.
+//	@returns bool
+//	@route /api/v1/watch-party/play [POST]
+func (h *Handler) HandleWatchPartyPlay(c echo.Context) error {
+	var b watchparty.Media
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.WatchPartyManager.PlayMedia(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleWatchPartyPause
+//
+//	@summary pauses the playback of the watch party.
+//	@returns bool
+//	@route /api/v1/watch-party/pause [POST]
+func (h *Handler) HandleWatchPartyPause(c echo.Context) error {
+	if err := h.App.WatchPartyManager.Pause(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleWatchPartyResume
+//
+//	@summary resumes the playback of the watch party.
+//	@returns bool
+//	@route /api/v1/watch-party/resume [POST]
+func (h *Handler) HandleWatchPartyResume(c echo.Context) error {
+	if err := h.App.WatchPartyManager.Resume(); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleWatchPartySeek
+//
+//	@summary seeks the playback of the watch party.
+//	@desc The position is in seconds.
+//	@returns bool
+//	@route /api/v1/watch-party/seek [POST]
+func (h *Handler) HandleWatchPartySeek(c echo.Context) error {
+	type body struct {
+		Position float64 `json:"position"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.WatchPartyManager.Seek(b.Position); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
+
+// HandleTransferWatchPartyHost
+//
+//	@summary makes another member the host of the watch party.
+//	@desc Only the host can transfer its role. The members reconnect to the instance of the new host.
+//	@returns bool
+//	@route /api/v1/watch-party/transfer [POST]
+func (h *Handler) HandleTransferWatchPartyHost(c echo.Context) error {
+	type body struct {
+		MemberId string `json:"memberId"`
+	}
+
+	var b body
+	if err := c.Bind(&b); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	if err := h.App.WatchPartyManager.TransferHost(b.MemberId); err != nil {
+		return h.RespondWithError(c, err)
+	}
+
+	return h.RespondWithData(c, true)
+}
@@ -55,6 +55,12 @@ type Manager interface {
 	OnAutoDownloaderBeforeDownloadTorrent() *Hook[hook_resolver.Resolver]
 	OnAutoDownloaderAfterDownloadTorrent() *Hook[hook_resolver.Resolver]
 
+	// Manga Auto Downloader events
+	OnMangaAutoDownloaderRunStarted() *Hook[hook_resolver.Resolver]
+	OnMangaAutoDownloaderMatchVerified() *Hook[hook_resolver.Resolver]
+	OnMangaAutoDownloaderBeforeDownloadChapter() *Hook[hook_resolver.Resolver]
+	OnMangaAutoDownloaderAfterDownloadChapter() *Hook[hook_resolver.Resolver]
+
 	// Scanner events
 	OnScanStarted() *Hook[hook_resolver.Resolver]
 	OnScanFilePathsRetrieved() *Hook[hook_resolver.Resolver]
@@ -154,6 +160,11 @@ type ManagerImpl struct {
 	onAutoDownloaderTorrentsFetched       *Hook[hook_resolver.Resolver]
 	onAutoDownloaderBeforeDownloadTorrent *Hook[hook_resolver.Resolver]
 	onAutoDownloaderAfterDownloadTorrent  *Hook[hook_resolver.Resolver]
+	// Manga Auto Downloader events
+	onMangaAutoDownloaderRunStarted            *Hook[hook_resolver.Resolver]
+	onMangaAutoDownloaderMatchVerified         *Hook[hook_resolver.Resolver]
+	onMangaAutoDownloaderBeforeDownloadChapter *Hook[hook_resolver.Resolver]
+	onMangaAutoDownloaderAfterDownloadChapter  *Hook[hook_resolver.Resolver]
 	// Scanner events
 	onScanStarted                   *Hook[hook_resolver.Resolver]
 	onScanFilePathsRetrieved        *Hook[hook_resolver.Resolver]
@@ -267,6 +278,11 @@ func (m *ManagerImpl) initHooks() {
 	m.onAutoDownloaderTorrentsFetched = &Hook[hook_resolver.Resolver]{}
 	m.onAutoDownloaderBeforeDownloadTorrent = &Hook[hook_resolver.Resolver]{}
 	m.onAutoDownloaderAfterDownloadTorrent = &Hook[hook_resolver.Resolver]{}
+	// Manga Auto Downloader events
+	m.onMangaAutoDownloaderRunStarted = &Hook[hook_resolver.Resolver]{}
+	m.onMangaAutoDownloaderMatchVerified = &Hook[hook_resolver.Resolver]{}
+	m.onMangaAutoDownloaderBeforeDownloadChapter = &Hook[hook_resolver.Resolver]{}
+	m.onMangaAutoDownloaderAfterDownloadChapter = &Hook[hook_resolver.Resolver]{}
 	// Scanner events
 	m.onScanStarted = &Hook[hook_resolver.Resolver]{}
 	m.onScanFilePathsRetrieved = &Hook[hook_resolver.Resolver]{}
@@ -556,6 +572,36 @@ func (m *ManagerImpl) OnAutoDownloaderAfterDownloadTorrent() *Hook[hook_resolver
 	return m.onAutoDownloaderAfterDownloadTorrent
 }
 
+// Manga Auto Downloader events
+
+func (m *ManagerImpl) OnMangaAutoDownloaderRunStarted() *Hook[hook_resolver.Resolver] {
+	if m == nil {
+		return &Hook[hook_resolver.Resolver]{}
+	}
+	return m.onMangaAutoDownloaderRunStarted
+}
+
+func (m *ManagerImpl) OnMangaAutoDownloaderMatchVerified() *Hook[hook_resolver.Resolver] {
+	if m == nil {
+		return &Hook[hook_resolver.Resolver]{}
+	}
+	return m.onMangaAutoDownloaderMatchVerified
+}
+
+func (m *ManagerImpl) OnMangaAutoDownloaderBeforeDownloadChapter() *Hook[hook_resolver.Resolver] {
+	if m == nil {
+		return &Hook[hook_resolver.Resolver]{}
+	}
+	return m.onMangaAutoDownloaderBeforeDownloadChapter
+}
+
+func (m *ManagerImpl) OnMangaAutoDownloaderAfterDownloadChapter() *Hook[hook_resolver.Resolver] {
+	if m == nil {
+		return &Hook[hook_resolver.Resolver]{}
+	}
+	return m.onMangaAutoDownloaderAfterDownloadChapter
+}
+
 // Scanner events
 func (m *ManagerImpl) OnScanStarted() *Hook[hook_resolver.Resolver] {
 	if m == nil {
@@ -31,5 +31,53 @@ type (
 		EpisodeNumbers      []int                                 `json:"episodeNumbers,omitempty"`
 		Destination         string                                `json:"destination"`
 		AdditionalTerms     []string                              `json:"additionalTerms"`
+		// TorrentCategory is the qBittorrent category of the added torrents.
+		// Transmission has no categories, it is added as a label instead.
+		TorrentCategory string `json:"torrentCategory,omitempty"`
+		// TorrentTags are added as qBittorrent tags or Transmission labels.
+		TorrentTags []string `json:"torrentTags,omitempty"`
+		// Managed is true if the rule was created by syncing with the collection.
+		// Managed rules are disabled automatically when the entry is completed or dropped.
+		Managed bool `json:"managed,omitempty"`
+		// DisabledBySync is true if the managed rule was disabled by syncing with the collection.
+		// Only these rules are re-enabled by syncing, so that a rule disabled by the user stays disabled.
+		DisabledBySync bool `json:"disabledBySync,omitempty"`
+		// TemplateId is the DB id of the template the rule was created from.
+		TemplateId uint `json:"templateId,omitempty"`
+	}
+
+	// AutoDownloaderRuleTemplate holds the values used to create new rules.
+	AutoDownloaderRuleTemplate struct {
+		DbID                uint                                  `json:"dbId"` // Will be set when fetched from the database
+		Name                string                                `json:"name"`
+		ReleaseGroups       []string                              `json:"releaseGroups"`
+		Resolutions         []string                              `json:"resolutions"`
+		TitleComparisonType AutoDownloaderRuleTitleComparisonType `json:"titleComparisonType"`
+		Destination         string                                `json:"destination"` // Can contain placeholders, e.g. "/anime/{title}"
+		AdditionalTerms     []string                              `json:"additionalTerms"`
+		TorrentCategory     string                                `json:"torrentCategory,omitempty"`
+		TorrentTags         []string                              `json:"torrentTags,omitempty"`
 	}
 )
+
+// NewRule creates an enabled rule for the media from the template.
+func (t *AutoDownloaderRuleTemplate) NewRule(mediaId int, comparisonTitle string) *AutoDownloaderRule {
+	titleComparisonType := t.TitleComparisonType
+	if titleComparisonType == "" {
+		titleComparisonType = AutoDownloaderRuleTitleComparisonLikely
+	}
+	return &AutoDownloaderRule{
+		Enabled:             true,
+		MediaId:             mediaId,
+		ReleaseGroups:       t.ReleaseGroups,
+		Resolutions:         t.Resolutions,
+		ComparisonTitle:     comparisonTitle,
+		TitleComparisonType: titleComparisonType,
+		EpisodeType:         AutoDownloaderRuleEpisodeRecent,
+		Destination:         t.Destination,
+		AdditionalTerms:     t.AdditionalTerms,
+		TorrentCategory:     t.TorrentCategory,
+		TorrentTags:         t.TorrentTags,
+		TemplateId:          t.DbID,
+	}
+}
@@ -165,6 +165,25 @@ func (e *LocalFileWrapperEntry) FindNextEpisode(lf *LocalFile) (*LocalFile, bool
 	return next, true
 }
 
+// FindNextEpisodeSkipping is like FindNextEpisode but skips the episodes for which skip returns true,
+// whether they are in the library or not, e.g. filler episodes.
+func (e *LocalFileWrapperEntry) FindNextEpisodeSkipping(lf *LocalFile, skip func(episodeNumber int) bool) (*LocalFile, bool) {
+	if skip == nil {
+		return e.FindNextEpisode(lf)
+	}
+	latest, ok := e.FindLatestLocalFile()
+	if !ok {
+		return nil, false
+	}
+	for ep := lf.GetEpisodeNumber() + 1; ep <= latest.GetEpisodeNumber(); ep++ {
+		if skip(ep) {
+			continue
+		}
+		return e.FindLocalFileWithEpisodeNumber(ep)
+	}
+	return nil, false
+}
+
 // GetProgressNumber returns the progress number of a **main** local file.
 func (e *LocalFileWrapperEntry) GetProgressNumber(lf *LocalFile) int {
 	lfs, ok := e.GetMainLocalFiles()
@@ -98,6 +98,52 @@ func TestLocalFileWrapperEntry(t *testing.T) {
 
 }
 
+func TestLocalFileWrapperEntry_FindNextEpisodeSkipping(t *testing.T) {
+
+	lfs := anime.MockHydratedLocalFiles(
+		anime.MockGenerateHydratedLocalFileGroupOptions("/mnt/anime/", "/mnt/anime/Naruto/Naruto - %ep.mkv", 20, []anime.MockHydratedLocalFileWrapperOptionsMetadata{
+			{MetadataEpisode: 25, MetadataAniDbEpisode: "25", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 26, MetadataAniDbEpisode: "26", MetadataType: anime.LocalFileTypeMain},
+			// 27 is a filler that is not in the library
+			{MetadataEpisode: 28, MetadataAniDbEpisode: "28", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 30, MetadataAniDbEpisode: "30", MetadataType: anime.LocalFileTypeMain},
+		}),
+	)
+	fillers := []int{26, 27, 29, 30}
+	skip := func(ep int) bool { return slices.Contains(fillers, ep) }
+
+	entry, ok := anime.NewLocalFileWrapper(lfs).GetLocalEntryById(20)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	tests := []struct {
+		episode         int
+		skip            func(int) bool
+		expectedEpisode int // 0 if there is no next episode
+	}{
+		{episode: 25, skip: skip, expectedEpisode: 28},
+		{episode: 25, skip: nil, expectedEpisode: 26},
+		{episode: 26, skip: nil, expectedEpisode: 0},
+		{episode: 28, skip: skip, expectedEpisode: 0}, // Only fillers remain
+	}
+
+	for _, tt := range tests {
+		lf, ok := entry.FindLocalFileWithEpisodeNumber(tt.episode)
+		if !assert.True(t, ok) {
+			continue
+		}
+		next, found := entry.FindNextEpisodeSkipping(lf, tt.skip)
+		if tt.expectedEpisode == 0 {
+			assert.False(t, found, "episode %d", tt.episode)
+			continue
+		}
+		if assert.True(t, found, "episode %d", tt.episode) {
+			assert.Equal(t, tt.expectedEpisode, next.GetEpisodeNumber())
+		}
+	}
+}
+
 func TestLocalFileWrapperEntryProgressNumber(t *testing.T) {
 
 	lfs := anime.MockHydratedLocalFiles(
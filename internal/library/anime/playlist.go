@@ -1,18 +1,72 @@
 package anime
 
 import (
+	"errors"
+	"fmt"
 	"seanime/internal/util"
 )
 
+const (
+	PlaylistItemSourceAuto          PlaylistItemSource = ""              // Local file if the episode is in the library, otherwise the first available streaming source
+	PlaylistItemSourceLocalFile     PlaylistItemSource = "localfile"     // Local file, the item fails if the episode is not in the library
+	PlaylistItemSourceTorrentStream PlaylistItemSource = "torrentstream" // Torrent stream, the torrent is selected automatically
+	PlaylistItemSourceDebridStream  PlaylistItemSource = "debridstream"  // Debrid stream, the torrent is selected automatically
+	PlaylistItemSourceOnlineStream  PlaylistItemSource = "onlinestream"  // Online stream, played by the client
+)
+
 type (
 	// Playlist holds the data from models.PlaylistEntry
 	Playlist struct {
 		DbId       uint         `json:"dbId"`       // DbId is the database ID of the models.PlaylistEntry
 		Name       string       `json:"name"`       // Name is the name of the playlist
 		LocalFiles []*LocalFile `json:"localFiles"` // LocalFiles is a list of local files in the playlist, in order
+		// Items is a list of episodes in the playlist, in order, they are resolved to a source when they are played.
+		// Playlists that only contain local files do not have items, see GetItems.
+		Items []*PlaylistItem `json:"items,omitempty"`
+		// Rules is set for smart playlists, the local files are resolved from the rules when the playlist is played
+		Rules *SmartPlaylistRules `json:"rules,omitempty"`
+	}
+
+	PlaylistItemSource string
+
+	// PlaylistItem references an episode of a playlist, which does not need to be in the library.
+	PlaylistItem struct {
+		MediaId       int                `json:"mediaId"`
+		EpisodeNumber int                `json:"episodeNumber"`
+		Source        PlaylistItemSource `json:"source"` // Preferred source
 	}
 )
 
+// Validate returns an error if the item cannot be played.
+func (i *PlaylistItem) Validate() error {
+	if i == nil || i.MediaId <= 0 || i.EpisodeNumber < 0 {
+		return errors.New("invalid playlist item")
+	}
+	switch i.Source {
+	case PlaylistItemSourceAuto, PlaylistItemSourceLocalFile, PlaylistItemSourceTorrentStream, PlaylistItemSourceDebridStream, PlaylistItemSourceOnlineStream:
+	default:
+		return fmt.Errorf("unknown playlist item source %q", i.Source)
+	}
+	return nil
+}
+
+// GetItems returns the episodes of the playlist, in order.
+// The local files are converted to items if the playlist does not have items.
+func (pd *Playlist) GetItems() []*PlaylistItem {
+	if len(pd.Items) > 0 {
+		return pd.Items
+	}
+	ret := make([]*PlaylistItem, 0, len(pd.LocalFiles))
+	for _, lf := range pd.LocalFiles {
+		ret = append(ret, &PlaylistItem{
+			MediaId:       lf.MediaId,
+			EpisodeNumber: lf.GetEpisodeNumber(),
+			Source:        PlaylistItemSourceLocalFile,
+		})
+	}
+	return ret
+}
+
 // NewPlaylist creates a new Playlist instance
 func NewPlaylist(name string) *Playlist {
 	return &Playlist{
@@ -21,6 +75,11 @@ func NewPlaylist(name string) *Playlist {
 	}
 }
 
+// IsSmart returns true if the local files of the playlist are resolved from rules
+func (pd *Playlist) IsSmart() bool {
+	return pd.Rules != nil
+}
+
 func (pd *Playlist) SetLocalFiles(lfs []*LocalFile) {
 	pd.LocalFiles = lfs
 }
@@ -0,0 +1,57 @@
+package anime_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"seanime/internal/library/anime"
+	"testing"
+)
+
+func TestPlaylist_GetItems(t *testing.T) {
+
+	lfs := anime.MockHydratedLocalFiles(
+		anime.MockGenerateHydratedLocalFileGroupOptions("/mnt/anime/", "/mnt/anime/Blue Lock/Blue Lock - %ep.mkv", 22222, []anime.MockHydratedLocalFileWrapperOptionsMetadata{
+			{MetadataEpisode: 1, MetadataAniDbEpisode: "1", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 2, MetadataAniDbEpisode: "2", MetadataType: anime.LocalFileTypeMain},
+		}),
+	)
+
+	// Local files are converted to items
+	playlist := anime.NewPlaylist("Local")
+	playlist.SetLocalFiles(lfs)
+	assert.Equal(t, []*anime.PlaylistItem{
+		{MediaId: 22222, EpisodeNumber: 1, Source: anime.PlaylistItemSourceLocalFile},
+		{MediaId: 22222, EpisodeNumber: 2, Source: anime.PlaylistItemSourceLocalFile},
+	}, playlist.GetItems())
+
+	// Items take precedence over the local files
+	items := []*anime.PlaylistItem{
+		{MediaId: 22222, EpisodeNumber: 2, Source: anime.PlaylistItemSourceLocalFile},
+		{MediaId: 22222, EpisodeNumber: 3, Source: anime.PlaylistItemSourceTorrentStream},
+		{MediaId: 21, EpisodeNumber: 1100},
+	}
+	playlist.Items = items
+	assert.Equal(t, items, playlist.GetItems())
+}
+
+func TestPlaylistItem_Validate(t *testing.T) {
+
+	tests := []struct {
+		item          *anime.PlaylistItem
+		expectedError bool
+	}{
+		{item: &anime.PlaylistItem{MediaId: 21, EpisodeNumber: 1}},
+		{item: &anime.PlaylistItem{MediaId: 21, EpisodeNumber: 1, Source: anime.PlaylistItemSourceOnlineStream}},
+		{item: &anime.PlaylistItem{MediaId: 21, EpisodeNumber: 1, Source: "nyaa"}, expectedError: true},
+		{item: &anime.PlaylistItem{MediaId: 0, EpisodeNumber: 1}, expectedError: true},
+		{item: nil, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		err := tt.item.Validate()
+		if tt.expectedError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package anime
+
+import (
+	"cmp"
+	"errors"
+	"seanime/internal/api/anilist"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const (
+	SmartPlaylistSortNone    SmartPlaylistSort = ""         // Order of the AniList collection
+	SmartPlaylistSortScore   SmartPlaylistSort = "score"    // Score given by the user, ties are broken by the mean score
+	SmartPlaylistSortAirDate SmartPlaylistSort = "air_date" // Start date of the media
+)
+
+type (
+	SmartPlaylistSort string
+
+	// SmartPlaylistRules defines a playlist that is resolved against the AniList collection and the local files when it is played,
+	// instead of a static list of local files.
+	SmartPlaylistRules struct {
+		// Statuses filters the media by list status, all statuses are included if empty
+		Statuses []anilist.MediaListStatus `json:"statuses"`
+		// Genres filters the media that have at least one of the genres
+		Genres []string `json:"genres"`
+		// Tags filters the media that have at least one of the tags
+		Tags []string `json:"tags"`
+		// Season and SeasonYear filter the media by airing season
+		Season     *anilist.MediaSeason `json:"season,omitempty"`
+		SeasonYear *int                 `json:"seasonYear,omitempty"`
+		// UnwatchedOnly excludes the episodes that are already watched
+		UnwatchedOnly bool `json:"unwatchedOnly"`
+		// NextEpisodeOnly only includes the next unwatched episode of each media
+		NextEpisodeOnly bool `json:"nextEpisodeOnly"`
+		// MaxEpisodes limits the number of episodes of the playlist, no limit if 0
+		MaxEpisodes int `json:"maxEpisodes"`
+		// SortBy orders the media, episodes of the same media are always in order
+		SortBy   SmartPlaylistSort `json:"sortBy"`
+		SortDesc bool              `json:"sortDesc"`
+	}
+
+	ResolveSmartPlaylistOptions struct {
+		Rules           *SmartPlaylistRules
+		AnimeCollection *anilist.AnimeCollection
+		LocalFiles      []*LocalFile
+		// MediaTags holds the tags of the media by media ID, only needed if Rules.Tags is set
+		MediaTags map[int][]string
+		// SkipEpisode returns true if an episode should not be part of the playlist (e.g. filler episodes), can be nil
+		SkipEpisode func(mediaId int, episodeNumber int) bool
+	}
+)
+
+var ErrInvalidSmartPlaylistRules = errors.New("invalid smart playlist rules")
+
+// Validate returns an error if the rules cannot be resolved.
+func (r *SmartPlaylistRules) Validate() error {
+	if r == nil {
+		return ErrInvalidSmartPlaylistRules
+	}
+	if r.MaxEpisodes < 0 {
+		return errors.New("max episodes cannot be negative")
+	}
+	switch r.SortBy {
+	case SmartPlaylistSortNone, SmartPlaylistSortScore, SmartPlaylistSortAirDate:
+	default:
+		return errors.New("unknown sort order")
+	}
+	return nil
+}
+
+// ResolveSmartPlaylist returns the local files matching the rules, in the order they should be played.
+func ResolveSmartPlaylist(opts *ResolveSmartPlaylistOptions) ([]*LocalFile, error) {
+	if err := opts.Rules.Validate(); err != nil {
+		return nil, err
+	}
+	rules := opts.Rules
+
+	ret := make([]*LocalFile, 0)
+	if opts.AnimeCollection == nil || opts.AnimeCollection.MediaListCollection == nil {
+		return ret, nil
+	}
+
+	lfw := NewLocalFileWrapper(opts.LocalFiles)
+
+	type item struct {
+		listEntry  *anilist.AnimeListEntry
+		localFiles []*LocalFile
+	}
+
+	// Go through the collection so that the default order is stable
+	items := make([]*item, 0)
+	added := make(map[int]struct{})
+	for _, list := range opts.AnimeCollection.MediaListCollection.GetLists() {
+		for _, listEntry := range list.GetEntries() {
+			if listEntry.GetMedia() == nil {
+				continue
+			}
+			mediaId := listEntry.GetMedia().GetID()
+			if _, ok := added[mediaId]; ok {
+				continue
+			}
+			if !rules.matches(listEntry) || !rules.matchesTags(opts.MediaTags[mediaId]) {
+				continue
+			}
+
+			lfe, found := lfw.GetLocalEntryById(mediaId)
+			if !found {
+				continue
+			}
+			lfs := rules.episodes(lfe, listEntry.GetProgressSafe(), opts.SkipEpisode)
+			if len(lfs) == 0 {
+				continue
+			}
+
+			added[mediaId] = struct{}{}
+			items = append(items, &item{listEntry: listEntry, localFiles: lfs})
+		}
+	}
+
+	if rules.SortBy != SmartPlaylistSortNone {
+		slices.SortStableFunc(items, func(a, b *item) int {
+			var c int
+			switch rules.SortBy {
+			case SmartPlaylistSortScore:
+				c = cmp.Or(
+					cmp.Compare(lo.FromPtr(a.listEntry.GetScore()), lo.FromPtr(b.listEntry.GetScore())),
+					cmp.Compare(lo.FromPtr(a.listEntry.GetMedia().GetMeanScore()), lo.FromPtr(b.listEntry.GetMedia().GetMeanScore())),
+				)
+			case SmartPlaylistSortAirDate:
+				c = cmp.Compare(smartPlaylistAirDate(a.listEntry.GetMedia()), smartPlaylistAirDate(b.listEntry.GetMedia()))
+			}
+			if rules.SortDesc {
+				return -c
+			}
+			return c
+		})
+	}
+
+	for _, it := range items {
+		ret = append(ret, it.localFiles...)
+	}
+
+	if rules.MaxEpisodes > 0 && len(ret) > rules.MaxEpisodes {
+		ret = ret[:rules.MaxEpisodes]
+	}
+
+	return ret, nil
+}
+
+// GetTagCandidates returns the IDs of the media in the library that match the rules other than the tags.
+// Only the tags of these media are needed to resolve the playlist.
+func (r *SmartPlaylistRules) GetTagCandidates(animeCollection *anilist.AnimeCollection, localFiles []*LocalFile) []int {
+	ret := make([]int, 0)
+	if animeCollection == nil || animeCollection.MediaListCollection == nil {
+		return ret
+	}
+
+	lfw := NewLocalFileWrapper(localFiles)
+
+	added := make(map[int]struct{})
+	for _, list := range animeCollection.MediaListCollection.GetLists() {
+		for _, listEntry := range list.GetEntries() {
+			if listEntry.GetMedia() == nil {
+				continue
+			}
+			mediaId := listEntry.GetMedia().GetID()
+			if _, ok := added[mediaId]; ok {
+				continue
+			}
+			if !r.matches(listEntry) {
+				continue
+			}
+			if _, found := lfw.GetLocalEntryById(mediaId); !found {
+				continue
+			}
+			added[mediaId] = struct{}{}
+			ret = append(ret, mediaId)
+		}
+	}
+
+	return ret
+}
+
+// matches returns true if the list entry matches the filters of the rules, except for the tags.
+func (r *SmartPlaylistRules) matches(listEntry *anilist.AnimeListEntry) bool {
+	media := listEntry.GetMedia()
+
+	if len(r.Statuses) > 0 && (listEntry.GetStatus() == nil || !slices.Contains(r.Statuses, *listEntry.GetStatus())) {
+		return false
+	}
+
+	if len(r.Genres) > 0 {
+		genres := lo.FilterMap(media.GetGenres(), func(g *string, _ int) (string, bool) {
+			return lo.FromPtr(g), g != nil
+		})
+		if !containsAnyFold(genres, r.Genres) {
+			return false
+		}
+	}
+
+	if r.Season != nil && (media.GetSeason() == nil || *media.GetSeason() != *r.Season) {
+		return false
+	}
+
+	if r.SeasonYear != nil && (media.GetSeasonYear() == nil || *media.GetSeasonYear() != *r.SeasonYear) {
+		return false
+	}
+
+	return true
+}
+
+// matchesTags returns true if the media has at least one of the tags of the rules.
+func (r *SmartPlaylistRules) matchesTags(tags []string) bool {
+	return len(r.Tags) == 0 || containsAnyFold(tags, r.Tags)
+}
+
+// episodes returns the main local files of the entry that should be part of the playlist, in order.
+func (r *SmartPlaylistRules) episodes(lfe *LocalFileWrapperEntry, progress int, skip func(mediaId int, episodeNumber int) bool) []*LocalFile {
+	var lfs []*LocalFile
+	if r.UnwatchedOnly || r.NextEpisodeOnly {
+		lfs = lfe.GetUnwatchedLocalFiles(progress)
+	} else {
+		lfs, _ = lfe.GetMainLocalFiles()
+	}
+
+	lfs = lo.Filter(lfs, func(lf *LocalFile, _ int) bool {
+		return skip == nil || !skip(lf.MediaId, lf.GetEpisodeNumber())
+	})
+	slices.SortStableFunc(lfs, func(a, b *LocalFile) int {
+		return cmp.Compare(a.GetEpisodeNumber(), b.GetEpisodeNumber())
+	})
+
+	if r.NextEpisodeOnly && len(lfs) > 1 {
+		lfs = lfs[:1]
+	}
+
+	return lfs
+}
+
+// smartPlaylistAirDate returns the start date of the media as a comparable number.
+// Media without a start date are considered to air last.
+func smartPlaylistAirDate(media *anilist.BaseAnime) int {
+	date := media.GetStartDate()
+	if date == nil || date.GetYear() == nil {
+		return 99999999
+	}
+	return *date.GetYear()*10000 + lo.FromPtr(date.GetMonth())*100 + lo.FromPtr(date.GetDay())
+}
+
+func containsAnyFold(values []string, targets []string) bool {
+	for _, v := range values {
+		for _, t := range targets {
+			if strings.EqualFold(v, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
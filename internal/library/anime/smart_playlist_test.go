@@ -0,0 +1,176 @@
+package anime_test
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"testing"
+)
+
+func TestResolveSmartPlaylist(t *testing.T) {
+
+	lfs := anime.MockHydratedLocalFiles(
+		anime.MockGenerateHydratedLocalFileGroupOptions("/mnt/anime/", "/mnt/anime/One Piece/One Piece - %ep.mkv", 21, []anime.MockHydratedLocalFileWrapperOptionsMetadata{
+			{MetadataEpisode: 1070, MetadataAniDbEpisode: "1070", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 1071, MetadataAniDbEpisode: "1071", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 1072, MetadataAniDbEpisode: "1072", MetadataType: anime.LocalFileTypeMain},
+		}),
+		anime.MockGenerateHydratedLocalFileGroupOptions("/mnt/anime/", "/mnt/anime/Blue Lock/Blue Lock - %ep.mkv", 22222, []anime.MockHydratedLocalFileWrapperOptionsMetadata{
+			{MetadataEpisode: 1, MetadataAniDbEpisode: "1", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 2, MetadataAniDbEpisode: "2", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 3, MetadataAniDbEpisode: "3", MetadataType: anime.LocalFileTypeMain},
+		}),
+		anime.MockGenerateHydratedLocalFileGroupOptions("/mnt/anime/", "/mnt/anime/Kimi ni Todoke/Kimi ni Todoke - %ep.mkv", 9656, []anime.MockHydratedLocalFileWrapperOptionsMetadata{
+			{MetadataEpisode: 1, MetadataAniDbEpisode: "1", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 2, MetadataAniDbEpisode: "2", MetadataType: anime.LocalFileTypeMain},
+			{MetadataEpisode: 1, MetadataAniDbEpisode: "OP1", MetadataType: anime.LocalFileTypeNC},
+		}),
+	)
+
+	newEntry := func(id int, status anilist.MediaListStatus, progress int, score float64, year int, genres ...string) *anilist.AnimeListEntry {
+		return &anilist.AnimeListEntry{
+			Status:   lo.ToPtr(status),
+			Progress: lo.ToPtr(progress),
+			Score:    lo.ToPtr(score),
+			Media: &anilist.BaseAnime{
+				ID:         id,
+				Genres:     lo.ToSlicePtr(genres),
+				SeasonYear: lo.ToPtr(year),
+				StartDate:  &anilist.BaseAnime_StartDate{Year: lo.ToPtr(year), Month: lo.ToPtr(4), Day: lo.ToPtr(1)},
+			},
+		}
+	}
+
+	collection := &anilist.AnimeCollection{
+		MediaListCollection: &anilist.AnimeCollection_MediaListCollection{
+			Lists: []*anilist.AnimeCollection_MediaListCollection_Lists{
+				{
+					Entries: []*anilist.AnimeListEntry{
+						newEntry(21, anilist.MediaListStatusCurrent, 1070, 8, 1999, "Action", "Adventure"),
+						newEntry(22222, anilist.MediaListStatusCurrent, 0, 9, 2022, "Sports"),
+					},
+				},
+				{
+					Entries: []*anilist.AnimeListEntry{
+						newEntry(9656, anilist.MediaListStatusPlanning, 0, 0, 2009, "Romance"),
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		rules         *anime.SmartPlaylistRules
+		mediaTags     map[int][]string
+		skipEpisode   func(mediaId int, episodeNumber int) bool
+		expectedFiles []string // "mediaId:episode"
+		expectedError bool
+	}{
+		{
+			name:  "All episodes in collection order",
+			rules: &anime.SmartPlaylistRules{},
+			expectedFiles: []string{
+				"21:1070", "21:1071", "21:1072",
+				"22222:1", "22222:2", "22222:3",
+				"9656:1", "9656:2",
+			},
+		},
+		{
+			name: "Next unwatched episode of every current show, oldest first",
+			rules: &anime.SmartPlaylistRules{
+				Statuses:        []anilist.MediaListStatus{anilist.MediaListStatusCurrent},
+				NextEpisodeOnly: true,
+				SortBy:          anime.SmartPlaylistSortAirDate,
+			},
+			expectedFiles: []string{"21:1071", "22222:1"},
+		},
+		{
+			name: "Unwatched episodes by score, highest first, limited",
+			rules: &anime.SmartPlaylistRules{
+				UnwatchedOnly: true,
+				SortBy:        anime.SmartPlaylistSortScore,
+				SortDesc:      true,
+				MaxEpisodes:   4,
+			},
+			expectedFiles: []string{"22222:1", "22222:2", "22222:3", "21:1071"},
+		},
+		{
+			name: "Genres",
+			rules: &anime.SmartPlaylistRules{
+				Genres: []string{"romance", "adventure"},
+			},
+			expectedFiles: []string{"21:1070", "21:1071", "21:1072", "9656:1", "9656:2"},
+		},
+		{
+			name: "Tags",
+			rules: &anime.SmartPlaylistRules{
+				Tags: []string{"Football"},
+			},
+			mediaTags: map[int][]string{
+				22222: {"Football", "Male Protagonist"},
+				9656:  {"Female Protagonist"},
+			},
+			expectedFiles: []string{"22222:1", "22222:2", "22222:3"},
+		},
+		{
+			name: "Airing season year",
+			rules: &anime.SmartPlaylistRules{
+				SeasonYear: lo.ToPtr(2009),
+			},
+			expectedFiles: []string{"9656:1", "9656:2"},
+		},
+		{
+			name: "Skipped episodes",
+			rules: &anime.SmartPlaylistRules{
+				Statuses:        []anilist.MediaListStatus{anilist.MediaListStatusCurrent},
+				NextEpisodeOnly: true,
+			},
+			skipEpisode: func(mediaId int, episodeNumber int) bool {
+				return mediaId == 21 && episodeNumber == 1071
+			},
+			expectedFiles: []string{"21:1072", "22222:1"},
+		},
+		{
+			name: "Invalid sort order",
+			rules: &anime.SmartPlaylistRules{
+				SortBy: "popularity",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := anime.ResolveSmartPlaylist(&anime.ResolveSmartPlaylistOptions{
+				Rules:           tt.rules,
+				AnimeCollection: collection,
+				LocalFiles:      lfs,
+				MediaTags:       tt.mediaTags,
+				SkipEpisode:     tt.skipEpisode,
+			})
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := lo.Map(res, func(lf *anime.LocalFile, _ int) string {
+				return fmt.Sprintf("%d:%d", lf.MediaId, lf.GetEpisodeNumber())
+			})
+			assert.Equal(t, tt.expectedFiles, got)
+		})
+	}
+
+	t.Run("Tags are only needed for the media matching the other filters", func(t *testing.T) {
+		rules := &anime.SmartPlaylistRules{
+			Statuses: []anilist.MediaListStatus{anilist.MediaListStatusCurrent},
+			Genres:   []string{"action"},
+			Tags:     []string{"Pirates"},
+		}
+		assert.Equal(t, []int{21}, rules.GetTagCandidates(collection, lfs))
+	})
+}
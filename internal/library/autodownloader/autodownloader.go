@@ -49,8 +49,10 @@ type (
 		settingsUpdatedCh       chan struct{}
 		stopCh                  chan struct{}
 		startCh                 chan struct{}
+		scheduler               *scheduler
 		debugTrace              bool
 		mu                      sync.Mutex
+		ruleSyncMu              sync.Mutex
 	}
 
 	NewAutoDownloaderOptions struct {
@@ -89,6 +91,7 @@ func New(opts *NewAutoDownloaderOptions) *AutoDownloader {
 		settingsUpdatedCh: make(chan struct{}, 1),
 		stopCh:            make(chan struct{}, 1),
 		startCh:           make(chan struct{}, 1),
+		scheduler:         newScheduler(),
 		debugTrace:        true,
 		mu:                sync.Mutex{},
 	}
@@ -118,6 +121,9 @@ func (ad *AutoDownloader) SetSettings(settings *models.AutoDownloaderSettings, p
 			ad.settings.Provider = provider
 		}
 		ad.settingsUpdatedCh <- struct{}{} // Notify that the settings have been updated
+		if ad.settings.SyncRules {
+			go ad.SyncRules()
+		}
 		if ad.settings.Enabled {
 			ad.startCh <- struct{}{} // Start the auto downloader
 		} else if !ad.settings.Enabled {
@@ -126,8 +132,11 @@ func (ad *AutoDownloader) SetSettings(settings *models.AutoDownloaderSettings, p
 	}()
 }
 
+// SetAnimeCollection should be called when the collection is refreshed.
+// Managed rules are synced with the new collection if enabled.
 func (ad *AutoDownloader) SetAnimeCollection(ac *anilist.AnimeCollection) {
 	ad.animeCollection = mo.Some(ac)
+	go ad.SyncRules()
 }
 
 func (ad *AutoDownloader) SetTorrentClientRepository(repo *torrent_client.Repository) {
@@ -201,12 +210,7 @@ func (ad *AutoDownloader) start() {
 	}
 
 	for {
-		interval := 20
-		// Use the user-defined interval if it's greater or equal to 15
-		if ad.settings != nil && ad.settings.Interval > 0 && ad.settings.Interval >= 15 {
-			interval = ad.settings.Interval
-		}
-		ticker := time.NewTicker(time.Duration(interval) * time.Minute)
+		timer := time.NewTimer(ad.getNextCheckDelay())
 		select {
 		case <-ad.settingsUpdatedCh:
 			break // Restart the loop
@@ -215,19 +219,86 @@ func (ad *AutoDownloader) start() {
 		case <-ad.startCh:
 			if ad.settings.Enabled {
 				ad.logger.Debug().Msg("autodownloader: Auto Downloader started")
-				ad.checkForNewEpisodes()
+				ad.checkForNewEpisodes(false)
 			}
-		case <-ticker.C:
+		case <-timer.C:
 			if ad.settings.Enabled {
-				ad.checkForNewEpisodes()
+				ad.checkForNewEpisodes(true)
 			}
 		}
-		ticker.Stop()
+		timer.Stop()
 	}
 
 }
 
-func (ad *AutoDownloader) checkForNewEpisodes() {
+// getScheduleOptions returns the options used to compute the rule schedules from the current settings.
+func (ad *AutoDownloader) getScheduleOptions() scheduleOptions {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+
+	interval := 20
+	// Use the user-defined interval if it's greater or equal to 15
+	if ad.settings != nil && ad.settings.Interval > 0 && ad.settings.Interval >= 15 {
+		interval = ad.settings.Interval
+	}
+	burstInterval := DefaultBurstInterval
+	if ad.settings != nil && ad.settings.BurstInterval > 0 {
+		burstInterval = ad.settings.BurstInterval
+	}
+	burstWindow := DefaultBurstWindow
+	if ad.settings != nil && ad.settings.BurstWindow > 0 {
+		burstWindow = ad.settings.BurstWindow
+	}
+
+	return scheduleOptions{
+		Interval:          time.Duration(interval) * time.Minute,
+		BurstInterval:     time.Duration(burstInterval) * time.Minute,
+		BurstWindow:       time.Duration(burstWindow) * time.Minute,
+		UseAiringSchedule: ad.settings != nil && ad.settings.UseAiringSchedule,
+	}
+}
+
+// getNextCheckDelay recomputes the rule schedules and returns how long to wait before the next check.
+// When the airing schedule is not used, this is the user-defined interval.
+func (ad *AutoDownloader) getNextCheckDelay() time.Duration {
+	opts := ad.getScheduleOptions()
+
+	rules, err := db_bridge.GetAutoDownloaderRules(ad.database)
+	if err != nil {
+		return opts.Interval
+	}
+
+	now := time.Now()
+	next := ad.scheduler.update(rules, ad.getRuleListEntry, opts, now)
+
+	if !opts.UseAiringSchedule {
+		return opts.Interval
+	}
+
+	delay := next.Sub(now)
+	if delay < minWakeUpDelay {
+		delay = minWakeUpDelay
+	}
+	return delay
+}
+
+// GetRuleSchedules returns the computed next check time of each enabled rule.
+func (ad *AutoDownloader) GetRuleSchedules() []*RuleSchedule {
+	if ad == nil {
+		return []*RuleSchedule{}
+	}
+
+	rules, err := db_bridge.GetAutoDownloaderRules(ad.database)
+	if err == nil {
+		ad.scheduler.update(rules, ad.getRuleListEntry, ad.getScheduleOptions(), time.Now())
+	}
+
+	return ad.scheduler.getSchedules()
+}
+
+// checkForNewEpisodes checks the enabled rules for new episodes.
+// If scheduled is true, only the rules that are due according to the scheduler are checked.
+func (ad *AutoDownloader) checkForNewEpisodes(scheduled bool) {
 	defer util.HandlePanicInModuleThen("autodownloader/checkForNewEpisodes", func() {})
 
 	ad.mu.Lock()
@@ -260,12 +331,17 @@ func (ad *AutoDownloader) checkForNewEpisodes() {
 		return
 	}
 
-	// Filter out disabled rules
+	// Filter out disabled rules and rules that are not due yet
+	now := time.Now()
 	_filteredRules := make([]*anime.AutoDownloaderRule, 0)
 	for _, rule := range rules {
-		if rule.Enabled {
-			_filteredRules = append(_filteredRules, rule)
+		if !rule.Enabled {
+			continue
+		}
+		if scheduled && !ad.scheduler.isDue(rule, now) {
+			continue
 		}
+		_filteredRules = append(_filteredRules, rule)
 	}
 	rules = _filteredRules
 
@@ -287,6 +363,8 @@ func (ad *AutoDownloader) checkForNewEpisodes() {
 		return
 	}
 
+	defer ad.scheduler.markChecked(rules, now)
+
 	// Get local files from the database
 	lfs, _, err := db_bridge.GetLocalFiles(ad.database)
 	if err != nil {
@@ -571,6 +649,12 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 		return false
 	}
 
+	// Resolve the destination template
+	destination := rule.Destination
+	if listEntry, found := ad.getRuleListEntry(rule); found {
+		destination = ResolveRuleDestination(rule, listEntry.GetMedia())
+	}
+
 	downloaded := false
 
 	if useDebrid {
@@ -583,7 +667,7 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 			_, err := ad.debridClientRepository.AddAndQueueTorrent(debrid.AddTorrentOptions{
 				MagnetLink:   magnet,
 				SelectFileId: "all", // RD-only, select all files
-			}, rule.Destination, rule.MediaId)
+			}, destination, rule.MediaId)
 			if err != nil {
 				ad.logger.Error().Err(err).Str("link", t.Link).Str("name", t.Name).Msg("autodownloader: Failed to add torrent to debrid")
 				return false
@@ -629,7 +713,10 @@ func (ad *AutoDownloader) downloadTorrent(t *NormalizedTorrent, rule *anime.Auto
 			ad.logger.Debug().Msgf("autodownloader: Downloading torrent: %s", t.Name)
 
 			// Add the torrent to torrent client
-			err := ad.torrentClientRepository.AddMagnets([]string{magnet}, rule.Destination)
+			err := ad.torrentClientRepository.AddMagnets([]string{magnet}, destination, &torrent_client.AddMagnetsOptions{
+				Category: rule.TorrentCategory,
+				Tags:     rule.TorrentTags,
+			})
 			if err != nil {
 				ad.logger.Error().Err(err).Str("link", t.Link).Str("name", t.Name).Msg("autodownloader: Failed to add torrent to torrent client")
 				return false
@@ -0,0 +1,133 @@
+package autodownloader
+
+import (
+	"path/filepath"
+	"regexp"
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"strconv"
+	"strings"
+)
+
+// Destination templates
+//
+// A rule destination can contain placeholders that are resolved when a torrent is downloaded.
+// e.g. "/data/anime/{year}/{romaji}" -> "/data/anime/2024/Sousou no Frieren"
+//
+//	{title}         User preferred title
+//	{romaji}        Romaji title
+//	{english}       English title, falls back to the romaji title
+//	{year}          Start year
+//	{season}        Airing season (e.g. "Fall")
+//	{seasonYear}    Airing season year
+//	{seasonNumber}  Season number found in the titles (e.g. "2"), defaults to "1"
+//	{format}        Format (e.g. "TV", "Movie")
+//	{id}            AniList ID
+
+var (
+	destinationPlaceholderRegex = regexp.MustCompile(`\{([a-zA-Z]+)\}`)
+	invalidPathCharsReplacer    = strings.NewReplacer(
+		"<", "",
+		">", "",
+		":", " -",
+		"\"", "",
+		"/", " ",
+		"\\", " ",
+		"|", " ",
+		"?", "",
+		"*", "",
+	)
+)
+
+// HasDestinationTemplate returns true if the destination contains at least one placeholder.
+func HasDestinationTemplate(dest string) bool {
+	return destinationPlaceholderRegex.MatchString(dest)
+}
+
+// ResolveRuleDestination returns the rule destination with its placeholders replaced by the media's values.
+// Unknown placeholders are left as is.
+func ResolveRuleDestination(rule *anime.AutoDownloaderRule, media *anilist.BaseAnime) string {
+	if rule == nil {
+		return ""
+	}
+	return ResolveDestination(rule.Destination, media)
+}
+
+// ResolveDestination replaces the placeholders in the destination with the media's values.
+// If the media is nil, the destination is returned as is.
+func ResolveDestination(dest string, media *anilist.BaseAnime) string {
+	if media == nil || !HasDestinationTemplate(dest) {
+		return dest
+	}
+
+	resolved := destinationPlaceholderRegex.ReplaceAllStringFunc(dest, func(match string) string {
+		value, ok := getDestinationPlaceholderValue(strings.Trim(match, "{}"), media)
+		if !ok {
+			return match
+		}
+		return sanitizePathSegment(value)
+	})
+
+	return filepath.Clean(resolved)
+}
+
+func getDestinationPlaceholderValue(key string, media *anilist.BaseAnime) (string, bool) {
+	switch key {
+	case "title":
+		return media.GetPreferredTitle(), true
+	case "romaji":
+		return media.GetRomajiTitleSafe(), true
+	case "english":
+		if media.GetTitle().GetEnglish() != nil && *media.GetTitle().GetEnglish() != "" {
+			return *media.GetTitle().GetEnglish(), true
+		}
+		return media.GetRomajiTitleSafe(), true
+	case "year":
+		if year := media.GetStartYearSafe(); year > 0 {
+			return strconv.Itoa(year), true
+		}
+		return "Unknown", true
+	case "season":
+		if media.GetSeason() == nil {
+			return "Unknown", true
+		}
+		season := strings.ToLower(string(*media.GetSeason()))
+		return strings.ToUpper(season[:1]) + season[1:], true
+	case "seasonYear":
+		if media.GetSeasonYear() != nil {
+			return strconv.Itoa(*media.GetSeasonYear()), true
+		}
+		if year := media.GetStartYearSafe(); year > 0 {
+			return strconv.Itoa(year), true
+		}
+		return "Unknown", true
+	case "seasonNumber":
+		if season := media.GetPossibleSeasonNumber(); season > 0 {
+			return strconv.Itoa(season), true
+		}
+		return "1", true
+	case "format":
+		if media.GetFormat() == nil {
+			return "Unknown", true
+		}
+		switch *media.GetFormat() {
+		case anilist.MediaFormatTv, anilist.MediaFormatOva, anilist.MediaFormatOna:
+			return string(*media.GetFormat()), true
+		case anilist.MediaFormatTvShort:
+			return "TV Short", true
+		default:
+			format := strings.ToLower(string(*media.GetFormat()))
+			return strings.ToUpper(format[:1]) + format[1:], true
+		}
+	case "id":
+		return strconv.Itoa(media.GetID()), true
+	}
+	return "", false
+}
+
+// sanitizePathSegment removes characters that are not allowed in file names.
+func sanitizePathSegment(s string) string {
+	s = invalidPathCharsReplacer.Replace(s)
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.TrimRight(s, ". ")
+}
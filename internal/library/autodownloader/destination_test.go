@@ -0,0 +1,67 @@
+package autodownloader
+
+import (
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveDestination(t *testing.T) {
+	media := &anilist.BaseAnime{
+		ID: 166531,
+		Title: &anilist.BaseAnime_Title{
+			Romaji:        lo.ToPtr("[Oshi no Ko] 2nd Season"),
+			English:       lo.ToPtr("Oshi no Ko: Season 2"),
+			UserPreferred: lo.ToPtr("[Oshi no Ko] 2nd Season"),
+		},
+		Synonyms:   []*string{lo.ToPtr("Oshi no Ko Season 2")},
+		Format:     lo.ToPtr(anilist.MediaFormatTv),
+		Season:     lo.ToPtr(anilist.MediaSeasonSummer),
+		SeasonYear: lo.ToPtr(2024),
+		StartDate: &anilist.BaseAnime_StartDate{
+			Year: lo.ToPtr(2024),
+		},
+	}
+
+	tests := []struct {
+		destination string
+		expected    string
+	}{
+		{
+			destination: "/data/anime/{romaji}",
+			expected:    "/data/anime/[Oshi no Ko] 2nd Season",
+		},
+		{
+			destination: "/data/anime/{english}",
+			expected:    "/data/anime/Oshi no Ko - Season 2",
+		},
+		{
+			destination: "/data/anime/{year}/{season} {seasonYear}/{title}",
+			expected:    "/data/anime/2024/Summer 2024/[Oshi no Ko] 2nd Season",
+		},
+		{
+			destination: "/data/anime/{format}/{romaji}/Season {seasonNumber}",
+			expected:    "/data/anime/TV/[Oshi no Ko] 2nd Season/Season 2",
+		},
+		{
+			destination: "/data/anime/{unknown}/{id}",
+			expected:    "/data/anime/{unknown}/166531",
+		},
+		{
+			destination: "/data/anime/Oshi no Ko",
+			expected:    "/data/anime/Oshi no Ko",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.destination, func(t *testing.T) {
+			assert.Equal(t, filepath.Clean(tt.expected), ResolveDestination(tt.destination, media))
+		})
+	}
+
+	// No media, the destination is returned as is
+	assert.Equal(t, "/data/anime/{title}", ResolveDestination("/data/anime/{title}", nil))
+}
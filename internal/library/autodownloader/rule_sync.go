@@ -0,0 +1,219 @@
+package autodownloader
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/events"
+	"seanime/internal/library/anime"
+	"seanime/internal/util"
+	"time"
+)
+
+const (
+	// finishedMediaGracePeriod is how long managed rules stay enabled after the media finishes airing,
+	// so that the last episodes can still be downloaded.
+	finishedMediaGracePeriod = 14 * 24 * time.Hour
+)
+
+type (
+	// ruleSyncChanges holds the changes to apply to the rules after syncing with the collection.
+	ruleSyncChanges struct {
+		ToCreate  []*anime.AutoDownloaderRule
+		ToEnable  []*anime.AutoDownloaderRule
+		ToDisable []*anime.AutoDownloaderRule
+	}
+)
+
+// SyncRules creates, re-enables and disables managed rules based on the anime collection.
+// It does nothing if rule syncing is disabled.
+func (ad *AutoDownloader) SyncRules() {
+	defer util.HandlePanicInModuleThen("autodownloader/SyncRules", func() {})
+
+	if ad == nil {
+		return
+	}
+
+	ad.ruleSyncMu.Lock()
+	defer ad.ruleSyncMu.Unlock()
+
+	ad.mu.Lock()
+	if ad.settings == nil || !ad.settings.SyncRules || ad.settings.SyncRulesTemplateId == 0 || ad.animeCollection.IsAbsent() {
+		ad.mu.Unlock()
+		return
+	}
+	templateId := ad.settings.SyncRulesTemplateId
+	collection := ad.animeCollection.MustGet()
+	ad.mu.Unlock()
+
+	template, err := db_bridge.GetAutoDownloaderRuleTemplate(ad.database, templateId)
+	if err != nil {
+		ad.logger.Error().Err(err).Uint("templateId", templateId).Msg("autodownloader: Failed to fetch rule template")
+		return
+	}
+
+	rules, err := db_bridge.GetAutoDownloaderRules(ad.database)
+	if err != nil {
+		ad.logger.Error().Err(err).Msg("autodownloader: Failed to fetch rules from the database")
+		return
+	}
+
+	changes := computeRuleSyncChanges(collection, rules, template, time.Now())
+	if len(changes.ToCreate) == 0 && len(changes.ToEnable) == 0 && len(changes.ToDisable) == 0 {
+		return
+	}
+
+	for _, rule := range changes.ToCreate {
+		if err := db_bridge.InsertAutoDownloaderRule(ad.database, rule); err != nil {
+			ad.logger.Error().Err(err).Int("mediaId", rule.MediaId).Msg("autodownloader: Failed to create rule")
+			continue
+		}
+		ad.logger.Debug().Int("mediaId", rule.MediaId).Msg("autodownloader: Created rule from collection")
+	}
+
+	for _, rule := range changes.ToEnable {
+		rule.Enabled = true
+		rule.DisabledBySync = false
+		if err := db_bridge.UpdateAutoDownloaderRule(ad.database, rule.DbID, rule); err != nil {
+			ad.logger.Error().Err(err).Int("mediaId", rule.MediaId).Msg("autodownloader: Failed to enable rule")
+			continue
+		}
+		ad.logger.Debug().Int("mediaId", rule.MediaId).Msg("autodownloader: Re-enabled rule")
+	}
+
+	for _, rule := range changes.ToDisable {
+		rule.Enabled = false
+		rule.DisabledBySync = true
+		if err := db_bridge.UpdateAutoDownloaderRule(ad.database, rule.DbID, rule); err != nil {
+			ad.logger.Error().Err(err).Int("mediaId", rule.MediaId).Msg("autodownloader: Failed to disable rule")
+			continue
+		}
+		ad.logger.Debug().Int("mediaId", rule.MediaId).Msg("autodownloader: Disabled rule")
+	}
+
+	ad.logger.Info().
+		Int("created", len(changes.ToCreate)).
+		Int("enabled", len(changes.ToEnable)).
+		Int("disabled", len(changes.ToDisable)).
+		Msg("autodownloader: Synced rules with collection")
+
+	ad.wsEventManager.SendEvent(events.InvalidateQueries, []string{events.GetAutoDownloaderRulesEndpoint, events.GetAutoDownloaderRulesByAnimeEndpoint})
+}
+
+// computeRuleSyncChanges returns the rules to create and the managed rules to re-enable or disable.
+//
+//   - A rule is created for each CURRENT or PLANNING entry that is airing and has no rule.
+//   - A managed rule disabled by syncing is re-enabled when its entry is back to CURRENT and the media has not finished airing.
+//     Rules disabled by the user are left alone.
+//   - A managed rule is disabled when its entry is COMPLETED, DROPPED or removed from the collection,
+//     or when the media finished airing more than finishedMediaGracePeriod ago.
+func computeRuleSyncChanges(
+	collection *anilist.AnimeCollection,
+	rules []*anime.AutoDownloaderRule,
+	template *anime.AutoDownloaderRuleTemplate,
+	now time.Time,
+) *ruleSyncChanges {
+	ret := &ruleSyncChanges{
+		ToCreate:  make([]*anime.AutoDownloaderRule, 0),
+		ToEnable:  make([]*anime.AutoDownloaderRule, 0),
+		ToDisable: make([]*anime.AutoDownloaderRule, 0),
+	}
+
+	if collection == nil || collection.MediaListCollection == nil {
+		return ret
+	}
+
+	rulesByMediaId := make(map[int][]*anime.AutoDownloaderRule)
+	for _, rule := range rules {
+		rulesByMediaId[rule.MediaId] = append(rulesByMediaId[rule.MediaId], rule)
+	}
+
+	entries := make(map[int]*anilist.AnimeListEntry)
+	for _, list := range collection.MediaListCollection.GetLists() {
+		for _, entry := range list.GetEntries() {
+			if entry.GetMedia() == nil {
+				continue
+			}
+			if _, ok := entries[entry.GetMedia().GetID()]; !ok {
+				entries[entry.GetMedia().GetID()] = entry
+			}
+		}
+	}
+
+	// Create rules
+	for mediaId, entry := range entries {
+		if entry.GetStatus() == nil || entry.GetMedia().GetStatus() == nil {
+			continue
+		}
+		status := *entry.GetStatus()
+		if status != anilist.MediaListStatusCurrent && status != anilist.MediaListStatusPlanning {
+			continue
+		}
+		if *entry.GetMedia().GetStatus() != anilist.MediaStatusReleasing {
+			continue
+		}
+		if _, ok := rulesByMediaId[mediaId]; ok {
+			continue // Rule already exists, managed or not
+		}
+		rule := template.NewRule(mediaId, entry.GetMedia().GetRomajiTitleSafe())
+		rule.Managed = true
+		ret.ToCreate = append(ret.ToCreate, rule)
+	}
+
+	// Re-enable managed rules, e.g. when the entry goes from COMPLETED or DROPPED back to CURRENT
+	for _, rule := range rules {
+		if !rule.Managed || rule.Enabled || !rule.DisabledBySync {
+			continue
+		}
+		entry, ok := entries[rule.MediaId]
+		if !ok || entry.GetStatus() == nil || *entry.GetStatus() != anilist.MediaListStatusCurrent {
+			continue
+		}
+		if hasMediaFinishedAiring(entry.GetMedia(), now) {
+			continue
+		}
+		ret.ToEnable = append(ret.ToEnable, rule)
+	}
+
+	// Disable managed rules
+	for _, rule := range rules {
+		if !rule.Managed || !rule.Enabled {
+			continue
+		}
+		entry, ok := entries[rule.MediaId]
+		if !ok {
+			ret.ToDisable = append(ret.ToDisable, rule)
+			continue
+		}
+		if entry.GetStatus() != nil {
+			status := *entry.GetStatus()
+			if status == anilist.MediaListStatusCompleted || status == anilist.MediaListStatusDropped {
+				ret.ToDisable = append(ret.ToDisable, rule)
+				continue
+			}
+		}
+		if hasMediaFinishedAiring(entry.GetMedia(), now) {
+			ret.ToDisable = append(ret.ToDisable, rule)
+		}
+	}
+
+	return ret
+}
+
+// hasMediaFinishedAiring returns true if the media is finished or cancelled and its end date is past the grace period.
+// If the end date is unknown, the media is considered finished.
+func hasMediaFinishedAiring(media *anilist.BaseAnime, now time.Time) bool {
+	if media == nil || media.GetStatus() == nil {
+		return false
+	}
+	if *media.GetStatus() != anilist.MediaStatusFinished && *media.GetStatus() != anilist.MediaStatusCancelled {
+		return false
+	}
+
+	endDate := media.GetEndDate()
+	if endDate == nil || endDate.GetYear() == nil || endDate.GetMonth() == nil || endDate.GetDay() == nil {
+		return true
+	}
+
+	end := time.Date(*endDate.GetYear(), time.Month(*endDate.GetMonth()), *endDate.GetDay(), 0, 0, 0, 0, time.UTC)
+	return now.Sub(end) > finishedMediaGracePeriod
+}
@@ -0,0 +1,100 @@
+package autodownloader
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeRuleSyncChanges(t *testing.T) {
+	now := time.Date(2024, 10, 10, 18, 0, 0, 0, time.UTC)
+
+	newEntry := func(mediaId int, listStatus anilist.MediaListStatus, mediaStatus anilist.MediaStatus, endDate *anilist.BaseAnime_EndDate) *anilist.AnimeListEntry {
+		return &anilist.AnimeListEntry{
+			Status: lo.ToPtr(listStatus),
+			Media: &anilist.BaseAnime{
+				ID:     mediaId,
+				Status: lo.ToPtr(mediaStatus),
+				Title: &anilist.BaseAnime_Title{
+					Romaji: lo.ToPtr("Media"),
+				},
+				EndDate: endDate,
+			},
+		}
+	}
+
+	collection := &anilist.AnimeCollection{
+		MediaListCollection: &anilist.AnimeCollection_MediaListCollection{
+			Lists: []*anilist.AnimeCollection_MediaListCollection_Lists{
+				{
+					Entries: []*anilist.AnimeListEntry{
+						newEntry(1, anilist.MediaListStatusCurrent, anilist.MediaStatusReleasing, nil),  // New rule
+						newEntry(2, anilist.MediaListStatusPlanning, anilist.MediaStatusReleasing, nil), // New rule
+						newEntry(3, anilist.MediaListStatusCurrent, anilist.MediaStatusFinished, nil),   // Not airing
+						newEntry(4, anilist.MediaListStatusCurrent, anilist.MediaStatusReleasing, nil),  // Has a manual rule
+						newEntry(5, anilist.MediaListStatusCompleted, anilist.MediaStatusReleasing, nil),
+						newEntry(6, anilist.MediaListStatusDropped, anilist.MediaStatusReleasing, nil),
+						// Finished recently
+						newEntry(7, anilist.MediaListStatusCurrent, anilist.MediaStatusFinished, &anilist.BaseAnime_EndDate{
+							Year: lo.ToPtr(2024), Month: lo.ToPtr(10), Day: lo.ToPtr(5),
+						}),
+						// Finished a month ago
+						newEntry(8, anilist.MediaListStatusCurrent, anilist.MediaStatusFinished, &anilist.BaseAnime_EndDate{
+							Year: lo.ToPtr(2024), Month: lo.ToPtr(9), Day: lo.ToPtr(10),
+						}),
+						newEntry(10, anilist.MediaListStatusCurrent, anilist.MediaStatusReleasing, nil), // Back to watching
+						newEntry(11, anilist.MediaListStatusPaused, anilist.MediaStatusReleasing, nil),
+						newEntry(12, anilist.MediaListStatusCurrent, anilist.MediaStatusFinished, nil), // Back to watching but not airing
+						newEntry(13, anilist.MediaListStatusCurrent, anilist.MediaStatusReleasing, nil),
+					},
+				},
+			},
+		},
+	}
+
+	rules := []*anime.AutoDownloaderRule{
+		{DbID: 1, MediaId: 4, Enabled: true},                                        // Manual rule
+		{DbID: 2, MediaId: 5, Enabled: true, Managed: true},                         // Completed
+		{DbID: 3, MediaId: 6, Enabled: true},                                        // Dropped but not managed
+		{DbID: 4, MediaId: 7, Enabled: true, Managed: true},                         // Finished recently
+		{DbID: 5, MediaId: 8, Enabled: true, Managed: true},                         // Finished a month ago
+		{DbID: 6, MediaId: 9, Enabled: true, Managed: true},                         // Removed from the collection
+		{DbID: 7, MediaId: 10, Enabled: false, Managed: true, DisabledBySync: true}, // Disabled when completed or dropped
+		{DbID: 8, MediaId: 11, Enabled: false, Managed: true, DisabledBySync: true},
+		{DbID: 9, MediaId: 12, Enabled: false, Managed: true, DisabledBySync: true},
+		{DbID: 10, MediaId: 13, Enabled: false, Managed: true}, // Disabled by the user
+	}
+
+	template := &anime.AutoDownloaderRuleTemplate{
+		DbID:          1,
+		ReleaseGroups: []string{"SubsPlease"},
+		Resolutions:   []string{"1080p"},
+		Destination:   "/data/anime/{title}",
+	}
+
+	changes := computeRuleSyncChanges(collection, rules, template, now)
+
+	require.Len(t, changes.ToCreate, 2)
+	createdIds := lo.Map(changes.ToCreate, func(r *anime.AutoDownloaderRule, _ int) int { return r.MediaId })
+	assert.ElementsMatch(t, []int{1, 2}, createdIds)
+	for _, rule := range changes.ToCreate {
+		assert.True(t, rule.Enabled)
+		assert.True(t, rule.Managed)
+		assert.Equal(t, uint(1), rule.TemplateId)
+		assert.Equal(t, "/data/anime/{title}", rule.Destination)
+		assert.Equal(t, anime.AutoDownloaderRuleEpisodeRecent, rule.EpisodeType)
+		assert.Equal(t, anime.AutoDownloaderRuleTitleComparisonLikely, rule.TitleComparisonType)
+	}
+
+	// The rule disabled by the user is not re-enabled
+	enabledIds := lo.Map(changes.ToEnable, func(r *anime.AutoDownloaderRule, _ int) uint { return r.DbID })
+	assert.ElementsMatch(t, []uint{7}, enabledIds)
+
+	disabledIds := lo.Map(changes.ToDisable, func(r *anime.AutoDownloaderRule, _ int) uint { return r.DbID })
+	assert.ElementsMatch(t, []uint{2, 5, 6}, disabledIds)
+}
@@ -0,0 +1,210 @@
+package autodownloader
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBurstInterval = 2   // minutes
+	DefaultBurstWindow   = 120 // minutes
+	// minWakeUpDelay prevents the loop from spinning when a rule is overdue.
+	minWakeUpDelay = 30 * time.Second
+	// airingWeek is used to estimate when the previous episode aired.
+	// AniList only exposes the next airing episode, so we assume a weekly schedule.
+	airingWeek = 7 * 24 * time.Hour
+)
+
+const (
+	// RuleScheduleReasonDefault means the rule is checked at the regular (slow) interval.
+	RuleScheduleReasonDefault RuleScheduleReason = "default"
+	// RuleScheduleReasonBurst means an episode aired recently and the rule is checked frequently.
+	RuleScheduleReasonBurst RuleScheduleReason = "burst"
+	// RuleScheduleReasonAiring means an episode will air before the next regular check.
+	RuleScheduleReasonAiring RuleScheduleReason = "airing"
+)
+
+type (
+	RuleScheduleReason string
+
+	// RuleSchedule holds the computed check times for a rule.
+	// It is sent to the client.
+	RuleSchedule struct {
+		RuleId            uint               `json:"ruleId"`
+		MediaId           int                `json:"mediaId"`
+		NextCheckAt       time.Time          `json:"nextCheckAt"`
+		LastCheckAt       *time.Time         `json:"lastCheckAt,omitempty"`
+		Reason            RuleScheduleReason `json:"reason"`
+		LastAiredEpisode  int                `json:"lastAiredEpisode,omitempty"`
+		LastAiredAt       *time.Time         `json:"lastAiredAt,omitempty"`
+		NextAiringEpisode int                `json:"nextAiringEpisode,omitempty"`
+		NextAiringAt      *time.Time         `json:"nextAiringAt,omitempty"`
+	}
+
+	scheduleOptions struct {
+		// Regular interval used when no episode aired recently
+		Interval time.Duration
+		// Interval used right after an episode aired
+		BurstInterval time.Duration
+		// How long after an episode aired the burst interval is used
+		BurstWindow time.Duration
+		// Whether the airing schedule should be taken into account
+		UseAiringSchedule bool
+	}
+
+	// scheduler keeps track of when each rule was last checked and when it should be checked next.
+	scheduler struct {
+		mu         sync.Mutex
+		lastChecks map[uint]time.Time
+		schedules  map[uint]*RuleSchedule
+	}
+)
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		lastChecks: make(map[uint]time.Time),
+		schedules:  make(map[uint]*RuleSchedule),
+	}
+}
+
+// update recomputes the schedule of every rule and returns the time at which the next check should happen.
+func (s *scheduler) update(rules []*anime.AutoDownloaderRule, getListEntry func(rule *anime.AutoDownloaderRule) (*anilist.AnimeListEntry, bool), opts scheduleOptions, now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := now.Add(opts.Interval)
+	schedules := make(map[uint]*RuleSchedule, len(rules))
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		listEntry, _ := getListEntry(rule)
+		sch := computeRuleSchedule(rule, listEntry, s.lastChecks[rule.DbID], opts, now)
+		schedules[rule.DbID] = sch
+		if sch.NextCheckAt.Before(next) {
+			next = sch.NextCheckAt
+		}
+	}
+	s.schedules = schedules
+
+	return next
+}
+
+// isDue returns true if the rule should be checked at the given time.
+// Rules that have not been scheduled yet are always due.
+func (s *scheduler) isDue(rule *anime.AutoDownloaderRule, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sch, ok := s.schedules[rule.DbID]
+	if !ok {
+		return true
+	}
+	return !sch.NextCheckAt.After(now)
+}
+
+// markChecked records that the rules have been checked at the given time.
+// Rules that were not due are skipped, so that manual runs do not delay their next scheduled check.
+func (s *scheduler) markChecked(rules []*anime.AutoDownloaderRule, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rule := range rules {
+		if sch, ok := s.schedules[rule.DbID]; ok && sch.NextCheckAt.After(now) {
+			continue
+		}
+		s.lastChecks[rule.DbID] = now
+	}
+}
+
+// getSchedules returns the schedules sorted by next check time.
+func (s *scheduler) getSchedules() []*RuleSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := make([]*RuleSchedule, 0, len(s.schedules))
+	for _, sch := range s.schedules {
+		ret = append(ret, sch)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].NextCheckAt.Equal(ret[j].NextCheckAt) {
+			return ret[i].RuleId < ret[j].RuleId
+		}
+		return ret[i].NextCheckAt.Before(ret[j].NextCheckAt)
+	})
+	return ret
+}
+
+// computeRuleSchedule computes when a rule should be checked next.
+//
+//   - If an episode aired less than BurstWindow ago, the rule is checked every BurstInterval.
+//   - If the next episode airs before the next regular check, the rule is checked when it airs.
+//   - Otherwise, the rule is checked every Interval.
+func computeRuleSchedule(rule *anime.AutoDownloaderRule, listEntry *anilist.AnimeListEntry, lastCheck time.Time, opts scheduleOptions, now time.Time) *RuleSchedule {
+	ret := &RuleSchedule{
+		RuleId:  rule.DbID,
+		MediaId: rule.MediaId,
+		Reason:  RuleScheduleReasonDefault,
+	}
+
+	if lastCheck.IsZero() {
+		// Never checked, check right away
+		ret.NextCheckAt = now
+	} else {
+		ret.LastCheckAt = &lastCheck
+		ret.NextCheckAt = lastCheck.Add(opts.Interval)
+	}
+
+	if !opts.UseAiringSchedule || listEntry == nil || listEntry.GetMedia() == nil {
+		return ret
+	}
+
+	nextAiring := listEntry.GetMedia().GetNextAiringEpisode()
+	if nextAiring == nil || nextAiring.GetAiringAt() == 0 {
+		return ret
+	}
+
+	airingAt := time.Unix(int64(nextAiring.GetAiringAt()), 0)
+
+	// Find the most recently aired episode
+	var lastAiredAt time.Time
+	lastAiredEpisode := 0
+	if !airingAt.After(now) {
+		// The collection hasn't been refreshed since the episode aired
+		lastAiredAt = airingAt
+		lastAiredEpisode = nextAiring.GetEpisode()
+	} else {
+		ret.NextAiringAt = &airingAt
+		ret.NextAiringEpisode = nextAiring.GetEpisode()
+		if nextAiring.GetEpisode() > 1 {
+			lastAiredAt = airingAt.Add(-airingWeek)
+			lastAiredEpisode = nextAiring.GetEpisode() - 1
+		}
+	}
+
+	if !lastAiredAt.IsZero() {
+		ret.LastAiredAt = &lastAiredAt
+		ret.LastAiredEpisode = lastAiredEpisode
+
+		if !lastAiredAt.After(now) && now.Sub(lastAiredAt) < opts.BurstWindow {
+			ret.Reason = RuleScheduleReasonBurst
+			if lastCheck.Before(lastAiredAt) {
+				// Not checked since the episode aired
+				ret.NextCheckAt = lastAiredAt
+			} else {
+				ret.NextCheckAt = lastCheck.Add(opts.BurstInterval)
+			}
+			return ret
+		}
+	}
+
+	if ret.NextAiringAt != nil && ret.NextAiringAt.Before(ret.NextCheckAt) {
+		ret.Reason = RuleScheduleReasonAiring
+		ret.NextCheckAt = *ret.NextAiringAt
+	}
+
+	return ret
+}
@@ -0,0 +1,144 @@
+package autodownloader
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeRuleSchedule(t *testing.T) {
+	now := time.Date(2024, 10, 10, 18, 0, 0, 0, time.UTC)
+
+	opts := scheduleOptions{
+		Interval:          60 * time.Minute,
+		BurstInterval:     2 * time.Minute,
+		BurstWindow:       120 * time.Minute,
+		UseAiringSchedule: true,
+	}
+
+	rule := &anime.AutoDownloaderRule{
+		DbID:    1,
+		Enabled: true,
+		MediaId: 1,
+	}
+
+	entryAiringAt := func(airingAt time.Time, episode int) *anilist.AnimeListEntry {
+		return &anilist.AnimeListEntry{
+			Media: &anilist.BaseAnime{
+				ID: 1,
+				NextAiringEpisode: &anilist.BaseAnime_NextAiringEpisode{
+					AiringAt: int(airingAt.Unix()),
+					Episode:  episode,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		listEntry      *anilist.AnimeListEntry
+		lastCheck      time.Time
+		expectedNext   time.Time
+		expectedReason RuleScheduleReason
+	}{
+		{
+			name:           "never checked",
+			listEntry:      nil,
+			lastCheck:      time.Time{},
+			expectedNext:   now,
+			expectedReason: RuleScheduleReasonDefault,
+		},
+		{
+			name:           "no airing data",
+			listEntry:      &anilist.AnimeListEntry{Media: &anilist.BaseAnime{ID: 1}},
+			lastCheck:      now.Add(-10 * time.Minute),
+			expectedNext:   now.Add(50 * time.Minute),
+			expectedReason: RuleScheduleReasonDefault,
+		},
+		{
+			name: "episode aired 30 minutes ago, estimated from next airing episode",
+			// Next episode airs in a week minus 30 minutes
+			listEntry:      entryAiringAt(now.Add(airingWeek-30*time.Minute), 5),
+			lastCheck:      now.Add(-1 * time.Minute),
+			expectedNext:   now.Add(1 * time.Minute),
+			expectedReason: RuleScheduleReasonBurst,
+		},
+		{
+			name: "episode aired 30 minutes ago, collection not refreshed",
+			// Not checked since the episode aired
+			listEntry:      entryAiringAt(now.Add(-30*time.Minute), 5),
+			lastCheck:      now.Add(-40 * time.Minute),
+			expectedNext:   now.Add(-30 * time.Minute),
+			expectedReason: RuleScheduleReasonBurst,
+		},
+		{
+			name:           "episode aired 3 hours ago",
+			listEntry:      entryAiringAt(now.Add(airingWeek-3*time.Hour), 5),
+			lastCheck:      now.Add(-10 * time.Minute),
+			expectedNext:   now.Add(50 * time.Minute),
+			expectedReason: RuleScheduleReasonDefault,
+		},
+		{
+			name:           "episode airs before the next regular check",
+			listEntry:      entryAiringAt(now.Add(20*time.Minute), 1),
+			lastCheck:      now.Add(-10 * time.Minute),
+			expectedNext:   now.Add(20 * time.Minute),
+			expectedReason: RuleScheduleReasonAiring,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch := computeRuleSchedule(rule, tt.listEntry, tt.lastCheck, opts, now)
+			assert.Equal(t, tt.expectedReason, sch.Reason)
+			assert.True(t, tt.expectedNext.Equal(sch.NextCheckAt), "expected %s, got %s", tt.expectedNext, sch.NextCheckAt)
+		})
+	}
+
+	t.Run("airing schedule disabled", func(t *testing.T) {
+		_opts := opts
+		_opts.UseAiringSchedule = false
+		sch := computeRuleSchedule(rule, entryAiringAt(now.Add(-30*time.Minute), 5), now.Add(-10*time.Minute), _opts, now)
+		assert.Equal(t, RuleScheduleReasonDefault, sch.Reason)
+		assert.True(t, now.Add(50*time.Minute).Equal(sch.NextCheckAt))
+	})
+}
+
+func TestSchedulerManualRun(t *testing.T) {
+	now := time.Date(2024, 10, 10, 18, 0, 0, 0, time.UTC)
+
+	opts := scheduleOptions{
+		Interval:      60 * time.Minute,
+		BurstInterval: 2 * time.Minute,
+		BurstWindow:   120 * time.Minute,
+	}
+
+	rules := []*anime.AutoDownloaderRule{
+		{DbID: 1, Enabled: true, MediaId: 1},
+		{DbID: 2, Enabled: true, MediaId: 2},
+	}
+	getListEntry := func(rule *anime.AutoDownloaderRule) (*anilist.AnimeListEntry, bool) {
+		return nil, false
+	}
+
+	s := newScheduler()
+	s.lastChecks[1] = now.Add(-50 * time.Minute) // Due in 10 minutes
+	s.lastChecks[2] = now.Add(-60 * time.Minute) // Due now
+	s.update(rules, getListEntry, opts, now)
+
+	// A manual run checks every rule
+	s.markChecked(rules, now)
+	s.update(rules, getListEntry, opts, now)
+
+	schedules := s.getSchedules()
+	assert.Len(t, schedules, 2)
+	// The rule that was not due keeps its schedule
+	assert.Equal(t, uint(1), schedules[0].RuleId)
+	assert.True(t, now.Add(10*time.Minute).Equal(schedules[0].NextCheckAt), "got %s", schedules[0].NextCheckAt)
+	// The rule that was due is rescheduled
+	assert.Equal(t, uint(2), schedules[1].RuleId)
+	assert.True(t, now.Add(60*time.Minute).Equal(schedules[1].NextCheckAt), "got %s", schedules[1].NextCheckAt)
+}
@@ -11,6 +11,7 @@ import (
 	"seanime/internal/library/autodownloader"
 	"seanime/internal/library/scanner"
 	"seanime/internal/library/summary"
+	"seanime/internal/mediastream"
 	"seanime/internal/notifier"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/util"
@@ -20,21 +21,22 @@ import (
 
 type (
 	AutoScanner struct {
-		fileActionCh     chan struct{} // Used to notify the scanner that a file action has occurred.
-		waiting          bool          // Used to prevent multiple scans from occurring at the same time.
-		missedAction     bool          // Used to indicate that a file action was missed while scanning.
-		mu               sync.Mutex
-		scannedCh        chan struct{}
-		waitTime         time.Duration // Wait time to listen to additional changes before triggering a scan.
-		enabled          bool
-		settings         models.LibrarySettings
-		platform         platform.Platform
-		logger           *zerolog.Logger
-		wsEventManager   events.WSEventManagerInterface
-		db               *db.Database                   // Database instance is required to update the local files.
-		autoDownloader   *autodownloader.AutoDownloader // AutoDownloader instance is required to refresh queue.
-		metadataProvider metadata.Provider
-		logsDir          string
+		fileActionCh          chan struct{} // Used to notify the scanner that a file action has occurred.
+		waiting               bool          // Used to prevent multiple scans from occurring at the same time.
+		missedAction          bool          // Used to indicate that a file action was missed while scanning.
+		mu                    sync.Mutex
+		scannedCh             chan struct{}
+		waitTime              time.Duration // Wait time to listen to additional changes before triggering a scan.
+		enabled               bool
+		settings              models.LibrarySettings
+		platform              platform.Platform
+		logger                *zerolog.Logger
+		wsEventManager        events.WSEventManagerInterface
+		db                    *db.Database                   // Database instance is required to update the local files.
+		autoDownloader        *autodownloader.AutoDownloader // AutoDownloader instance is required to refresh queue.
+		mediastreamRepository *mediastream.Repository        // Used to queue new downloads for pre-transcoding.
+		metadataProvider      metadata.Provider
+		logsDir               string
 	}
 	NewAutoScannerOptions struct {
 		Database         *db.Database
@@ -73,6 +75,13 @@ func New(opts *NewAutoScannerOptions) *AutoScanner {
 	}
 }
 
+func (as *AutoScanner) SetMediastreamRepository(repo *mediastream.Repository) {
+	if as == nil {
+		return
+	}
+	as.mediastreamRepository = repo
+}
+
 // Notify is used to notify the AutoScanner that a file action has occurred.
 func (as *AutoScanner) Notify() {
 	if as == nil {
@@ -251,6 +260,11 @@ func (as *AutoScanner) scan() {
 			return
 		}
 
+		// Queue the new downloads for pre-transcoding
+		if as.mediastreamRepository != nil {
+			go as.mediastreamRepository.EnqueueNewDownloads(existingLfs, allLfs)
+		}
+
 	}
 
 	// Save the scan summary
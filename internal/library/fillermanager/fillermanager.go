@@ -5,6 +5,7 @@ import (
 	lop "github.com/samber/lo/parallel"
 	"seanime/internal/api/filler"
 	"seanime/internal/database/db"
+	"seanime/internal/database/models"
 	"seanime/internal/library/anime"
 	"seanime/internal/onlinestream"
 	"seanime/internal/util"
@@ -31,6 +32,23 @@ type (
 		db        *db.Database
 		logger    *zerolog.Logger
 		fillerApi filler.API
+
+		settingsMu sync.RWMutex
+		settings   Settings
+	}
+
+	// Settings are the library settings for filler episodes, they apply to the media that do not have a preference.
+	Settings struct {
+		SkipFillerEpisodes         bool
+		MarkSkippedFillerAsWatched bool
+	}
+
+	// SkipFillerPreference is the effective "skip filler" preference of a media.
+	SkipFillerPreference struct {
+		MediaId       int  `json:"mediaId"`
+		SkipFiller    bool `json:"skipFiller"`
+		MarkAsWatched bool `json:"markAsWatched"`
+		IsOverridden  bool `json:"isOverridden"` // Whether the media has its own preference instead of the library settings
 	}
 
 	NewFillerManagerOptions struct {
@@ -228,3 +246,72 @@ func (fm *FillerManager) HydrateEpisodeFillerData(mId int, e *anime.Episode) {
 
 	e.EpisodeMetadata.IsFiller = fm.IsEpisodeFiller(mId, e.EpisodeNumber)
 }
+
+func (fm *FillerManager) SetSettings(settings *Settings) {
+	if fm == nil || settings == nil {
+		return
+	}
+	fm.settingsMu.Lock()
+	defer fm.settingsMu.Unlock()
+	fm.settings = *settings
+}
+
+// GetSkipFillerPreference returns the preference of the media, or the library settings if the media has none.
+func (fm *FillerManager) GetSkipFillerPreference(mediaId int) *SkipFillerPreference {
+	if fm == nil {
+		return &SkipFillerPreference{MediaId: mediaId}
+	}
+
+	pref, err := fm.db.GetFillerPreference(mediaId)
+	if err == nil && pref != nil {
+		return &SkipFillerPreference{
+			MediaId:       mediaId,
+			SkipFiller:    pref.SkipFiller,
+			MarkAsWatched: pref.MarkAsWatched,
+			IsOverridden:  true,
+		}
+	}
+
+	fm.settingsMu.RLock()
+	defer fm.settingsMu.RUnlock()
+	return &SkipFillerPreference{
+		MediaId:       mediaId,
+		SkipFiller:    fm.settings.SkipFillerEpisodes,
+		MarkAsWatched: fm.settings.MarkSkippedFillerAsWatched,
+	}
+}
+
+// SaveSkipFillerPreference overrides the library settings for a media.
+func (fm *FillerManager) SaveSkipFillerPreference(mediaId int, skipFiller bool, markAsWatched bool) error {
+	fm.logger.Debug().Int("mediaId", mediaId).Bool("skipFiller", skipFiller).Msg("fillermanager: Saving skip filler preference")
+
+	return fm.db.UpsertFillerPreference(&models.FillerPreference{
+		MediaId:       mediaId,
+		SkipFiller:    skipFiller,
+		MarkAsWatched: markAsWatched,
+	})
+}
+
+// DeleteSkipFillerPreference removes the preference of a media, the library settings apply again.
+func (fm *FillerManager) DeleteSkipFillerPreference(mediaId int) error {
+	return fm.db.DeleteFillerPreference(mediaId)
+}
+
+// ShouldSkipEpisode returns true if the episode is a filler and fillers of the media are skipped.
+func (fm *FillerManager) ShouldSkipEpisode(mediaId int, episodeNumber int) bool {
+	if fm == nil || !fm.HasFillerFetched(mediaId) {
+		return false
+	}
+	return fm.GetSkipFillerPreference(mediaId).SkipFiller && fm.IsEpisodeFiller(mediaId, episodeNumber)
+}
+
+// GetSkipFunc returns a function reporting whether an episode of the media should be skipped.
+// It returns nil if no episode of the media is skipped.
+func (fm *FillerManager) GetSkipFunc(mediaId int) func(episodeNumber int) bool {
+	if fm == nil || !fm.HasFillerFetched(mediaId) || !fm.GetSkipFillerPreference(mediaId).SkipFiller {
+		return nil
+	}
+	return func(episodeNumber int) bool {
+		return fm.IsEpisodeFiller(mediaId, episodeNumber)
+	}
+}
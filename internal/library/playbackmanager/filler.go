@@ -0,0 +1,56 @@
+package playbackmanager
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/library/anime"
+)
+
+// findNextEpisode returns the next main local file of the entry, skipping filler episodes if the user chose to.
+func (pm *PlaybackManager) findNextEpisode(lfe *anime.LocalFileWrapperEntry, lf *anime.LocalFile) (*anime.LocalFile, bool) {
+	return lfe.FindNextEpisodeSkipping(lf, pm.fillerManager.GetSkipFunc(lfe.GetMediaId()))
+}
+
+// shouldSkipLocalFile returns true if the local file is a filler episode that should not be played.
+func (pm *PlaybackManager) shouldSkipLocalFile(lf *anime.LocalFile) bool {
+	return lf != nil && pm.fillerManager.ShouldSkipEpisode(lf.MediaId, lf.GetEpisodeNumber())
+}
+
+// markSkippedFillersAsWatched updates the progress of the media when the episodes between the progress and the episode
+// that started playing are fillers that were skipped, so that the progress stays consistent if the user stops watching.
+// Nothing is done unless the user chose to mark skipped fillers as watched.
+func (pm *PlaybackManager) markSkippedFillersAsWatched(listEntry *anilist.AnimeListEntry, episodeNumber int, progressNumber int) {
+	if pm.fillerManager == nil || listEntry == nil || listEntry.GetMedia() == nil {
+		return
+	}
+
+	media := listEntry.GetMedia()
+	pref := pm.fillerManager.GetSkipFillerPreference(media.GetID())
+	if !pref.SkipFiller || !pref.MarkAsWatched {
+		return
+	}
+
+	progress := listEntry.GetProgressSafe()
+	newProgress := progressNumber - 1
+	if newProgress <= progress {
+		return
+	}
+
+	// Episode numbers can be offset from progress numbers, e.g. when there is an episode 0
+	offset := progressNumber - episodeNumber
+	for p := progress + 1; p <= newProgress; p++ {
+		if !pm.fillerManager.IsEpisodeFiller(media.GetID(), p-offset) {
+			return
+		}
+	}
+
+	totalEpisodes := media.GetTotalEpisodeCount()
+	err := pm.platform.UpdateEntryProgress(media.GetID(), newProgress, &totalEpisodes)
+	if err != nil {
+		pm.Logger.Error().Err(err).Msg("playback manager: Failed to mark skipped fillers as watched")
+		return
+	}
+
+	pm.Logger.Debug().Int("mediaId", media.GetID()).Int("progress", newProgress).Msg("playback manager: Marked skipped fillers as watched")
+
+	pm.refreshAnimeCollectionFunc()
+}
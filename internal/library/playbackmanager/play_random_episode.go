@@ -61,6 +61,9 @@ func (pm *PlaybackManager) StartRandomVideo(opts *StartRandomVideoOptions) error
 			continue
 		}
 		firstUnwatchedFile, found := e.GetFirstUnwatchedLocalFiles(progress)
+		if found && pm.shouldSkipLocalFile(firstUnwatchedFile) {
+			firstUnwatchedFile, found = pm.findNextEpisode(e, firstUnwatchedFile)
+		}
 		if !found {
 			continue
 		}
@@ -8,11 +8,15 @@ import (
 	"seanime/internal/continuity"
 	"seanime/internal/database/db"
 	"seanime/internal/database/db_bridge"
+	"seanime/internal/database/models"
 	discordrpc_presence "seanime/internal/discordrpc/presence"
 	"seanime/internal/events"
 	"seanime/internal/hook"
 	"seanime/internal/library/anime"
+	"seanime/internal/library/fillermanager"
+	"seanime/internal/library/viewingstats"
 	"seanime/internal/mediaplayers/mediaplayer"
+	"seanime/internal/mediastream/skipdetect"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/util"
 	"seanime/internal/util/result"
@@ -41,12 +45,15 @@ type (
 	// It can receive progress updates and dispatch appropriate events for:
 	//  - syncing progress with AniList, MAL, etc.
 	//  - sending notifications to the client
-	//  - DEVNOTE: in the future, it could also be used to implement w2g, handle built-in player or allow multiple watchers
+	//  - DEVNOTE: it is used by watch parties (w2g) to control the local player, see watchparty.LocalPlayer
+	//  - DEVNOTE: in the future, it could also be used to handle built-in player or allow multiple watchers
 	PlaybackManager struct {
 		Logger                *zerolog.Logger
 		Database              *db.Database
 		MediaPlayerRepository *mediaplayer.Repository // MediaPlayerRepository is used to control the media player
 		continuityManager     *continuity.Manager
+		skipDetector          *skipdetect.Detector
+		fillerManager         *fillermanager.FillerManager
 
 		settings *Settings
 
@@ -88,6 +95,8 @@ type (
 		currentStreamEpisode mo.Option[*anime.Episode]
 		// The current media being streamed, set in [StartStreamingUsingMediaPlayer]
 		currentStreamMedia mo.Option[*anilist.BaseAnime]
+		// Where the current stream comes from, set in [StartStreamingUsingMediaPlayer]
+		currentStreamSource viewingstats.Source
 
 		// \/ Manual progress tracking (non-integrated external player)
 		manualTrackingCtx           context.Context
@@ -97,12 +106,19 @@ type (
 		manualTrackingWg            sync.WaitGroup
 
 		// \/ Playlist
-		playlistHub *playlistHub // The playlist hub
+		playlistHub                   *playlistHub                                            // The playlist hub
+		playlistStreamers             *result.Map[anime.PlaylistItemSource, PlaylistStreamer] // Modules streaming the playlist items that are not played from the library
+		streamEpisodeCollectionLoader StreamEpisodeCollectionLoader                           // Loads the stream episode collection of streamed playlist items (can be nil)
 
 		isOffline       bool
 		animeCollection mo.Option[*anilist.AnimeCollection]
 
 		playbackStatusSubscribers *result.Map[string, *PlaybackStatusSubscriber]
+
+		// \/ Skip markers of the current local file
+		currentSkipMarkers *models.EpisodeSkipMarkers // Can be nil
+		introSkipped       bool
+		outroSkipped       bool
 	}
 
 	PlaybackStatusSubscriber struct {
@@ -144,10 +160,14 @@ type (
 		DiscordPresence            *discordrpc_presence.Presence
 		IsOffline                  bool
 		ContinuityManager          *continuity.Manager
+		SkipDetector               *skipdetect.Detector         // Optional
+		FillerManager              *fillermanager.FillerManager // Optional, used to skip filler episodes
 	}
 
 	Settings struct {
 		AutoPlayNextEpisode bool
+		AutoSkipIntro       bool
+		AutoSkipOutro       bool
 	}
 )
 
@@ -175,7 +195,10 @@ func New(opts *NewPlaybackManagerOptions) *PlaybackManager {
 		currentLocalFileWrapperEntry:   mo.None[*anime.LocalFileWrapperEntry](),
 		currentMediaListEntry:          mo.None[*anilist.AnimeListEntry](),
 		continuityManager:              opts.ContinuityManager,
+		skipDetector:                   opts.SkipDetector,
+		fillerManager:                  opts.FillerManager,
 		playbackStatusSubscribers:      result.NewResultMap[string, *PlaybackStatusSubscriber](),
+		playlistStreamers:              result.NewResultMap[anime.PlaylistItemSource, PlaylistStreamer](),
 	}
 
 	pm.playlistHub = newPlaylistHub(pm)
@@ -241,6 +264,8 @@ type StartPlayingOptions struct {
 	Payload   string // url or path
 	UserAgent string
 	ClientId  string
+	// Where the stream comes from, used for the viewing statistics
+	StreamSource viewingstats.Source
 }
 
 func (pm *PlaybackManager) StartPlayingUsingMediaPlayer(opts *StartPlayingOptions) error {
@@ -315,7 +340,10 @@ func (pm *PlaybackManager) StartStreamingUsingMediaPlayer(windowTitle string, op
 		return nil
 	}
 
-	pm.playlistHub.reset()
+	// Streams started by the playlist do not stop it
+	if !pm.playlistHub.isStreamRequested(media.GetID()) {
+		pm.playlistHub.reset()
+	}
 	if pm.isOffline {
 		return errors.New("cannot stream when offline")
 	}
@@ -336,6 +364,10 @@ func (pm *PlaybackManager) StartStreamingUsingMediaPlayer(windowTitle string, op
 	}
 
 	pm.currentStreamMedia = mo.Some(media)
+	pm.currentStreamSource = opts.StreamSource
+	if pm.currentStreamSource == "" {
+		pm.currentStreamSource = viewingstats.SourceOther
+	}
 
 	episodeNumber := 0
 
@@ -394,7 +426,7 @@ func (pm *PlaybackManager) PlayNextEpisode() (err error) {
 			return errors.New("could not play next episode")
 		}
 
-		nextLf, found := pm.currentLocalFileWrapperEntry.MustGet().FindNextEpisode(pm.currentLocalFile.MustGet())
+		nextLf, found := pm.findNextEpisode(pm.currentLocalFileWrapperEntry.MustGet(), pm.currentLocalFile.MustGet())
 		if !found {
 			return errors.New("could not play next episode")
 		}
@@ -495,10 +527,10 @@ func (pm *PlaybackManager) CancelCurrentPlaylist() error {
 	return nil
 }
 
-// RequestNextPlaylistFile will play the next file in the playlist.
-// This is an action triggered by the client.
+// RequestNextPlaylistFile will play the next item in the playlist.
+// This is an action triggered by the client, it is also used to move on from an online stream item.
 func (pm *PlaybackManager) RequestNextPlaylistFile() error {
-	go pm.playlistHub.playNextFile()
+	go pm.playlistHub.playNextItem()
 	return nil
 }
 
@@ -507,20 +539,34 @@ func (pm *PlaybackManager) RequestNextPlaylistFile() error {
 func (pm *PlaybackManager) StartPlaylist(playlist *anime.Playlist) (err error) {
 	defer util.HandlePanicInModuleWithError("library/playbackmanager/StartPlaylist", &err)
 
+	// The episodes of smart playlists are resolved from the rules
+	if playlist.IsSmart() {
+		lfs, err := pm.ResolveSmartPlaylist(playlist.Rules)
+		if err != nil {
+			return err
+		}
+		if len(lfs) == 0 {
+			return errors.New("no episode matches the rules of the smart playlist")
+		}
+		playlist.SetLocalFiles(lfs)
+	}
+
 	pm.playlistHub.loadPlaylist(playlist)
 
 	_ = pm.checkOrLoadAnimeCollection()
 
-	// Play the first video in the playlist
-	firstVidPath := playlist.LocalFiles[0].Path
-	err = pm.MediaPlayerRepository.Play(firstVidPath)
+	// Play the first item in the playlist, filler episodes are skipped if the user chose to
+	firstItem, found := pm.playlistHub.findFirstItem()
+	if !found {
+		pm.playlistHub.reset()
+		return errors.New("all the episodes of the playlist are skipped fillers")
+	}
+	err = pm.playPlaylistItem(firstItem)
 	if err != nil {
+		pm.playlistHub.reset()
 		return err
 	}
 
-	// Start tracking the video
-	pm.MediaPlayerRepository.StartTracking()
-
 	// Create a new context for the playlist hub
 	var ctx context.Context
 	ctx, pm.playlistHub.cancel = context.WithCancel(context.Background())
@@ -536,22 +582,21 @@ func (pm *PlaybackManager) StartPlaylist(playlist *anime.Playlist) (err error) {
 				// Send event to the client -- nil signals that no playlist is being played
 				pm.wsEventManager.SendEvent(events.PlaybackManagerPlaylistState, nil)
 				return
-			case path := <-pm.playlistHub.requestNewFileCh:
-				// requestNewFileCh receives the path of the next video to play
-				// The channel is fed when it's time to play the next video or when the client requests the next video
+			case item := <-pm.playlistHub.requestNextItemCh:
+				// requestNextItemCh receives the next item to play
+				// The channel is fed when it's time to play the next item or when the client requests the next item
 				// see: RequestNextPlaylistFile, playlistHub code
-				pm.Logger.Debug().Str("path", path).Msg("playback manager: Playing next file")
+				pm.Logger.Debug().Int("mediaId", item.MediaId).Int("episode", item.EpisodeNumber).Msg("playback manager: Playing next item")
 				// Send notification to the client
-				pm.wsEventManager.SendEvent(events.InfoToast, "Playing next file in playlist")
-				// Play the requested video
-				err := pm.MediaPlayerRepository.Play(path)
+				pm.wsEventManager.SendEvent(events.InfoToast, "Playing next episode in playlist")
+				// Play the requested item, local files are tracked right away, streams are tracked once they start
+				err := pm.playPlaylistItem(item)
 				if err != nil {
-					pm.Logger.Error().Err(err).Msg("playback manager: Failed to play next file in playlist")
+					pm.Logger.Error().Err(err).Msg("playback manager: Failed to play next item in playlist")
+					pm.wsEventManager.SendEvent(events.ErrorToast, fmt.Sprintf("Playlist: %s", err.Error()))
 					pm.playlistHub.cancel()
 					return
 				}
-				// Start tracking the video
-				pm.MediaPlayerRepository.StartTracking()
 			case <-pm.playlistHub.endOfPlaylistCh:
 				pm.Logger.Debug().Msg("playback manager: End of playlist")
 				pm.wsEventManager.SendEvent(events.InfoToast, "End of playlist")
@@ -565,6 +610,11 @@ func (pm *PlaybackManager) StartPlaylist(playlist *anime.Playlist) (err error) {
 		}
 	}()
 
+	// Smart playlists are kept since their episodes change over time
+	if playlist.IsSmart() {
+		return nil
+	}
+
 	// Delete playlist in goroutine
 	go func() {
 		err := db_bridge.DeletePlaylist(pm.Database, playlist.DbId)
@@ -11,20 +11,23 @@ import (
 )
 
 type (
+	// playlistHub advances through the items of the current playlist.
+	// Items can be played from local files or streams, the hub is notified of the episode that started playing by the progress tracking.
 	playlistHub struct {
-		requestNewFileCh chan string
-		endOfPlaylistCh  chan struct{}
+		requestNextItemCh chan *anime.PlaylistItem
+		endOfPlaylistCh   chan struct{}
 
 		wsEventManager  events.WSEventManagerInterface
 		logger          *zerolog.Logger
-		currentPlaylist *anime.Playlist    // The current playlist that is being played (can be nil)
-		nextLocalFile   *anime.LocalFile   // The next episode that will be played (can be nil)
-		cancel          context.CancelFunc // The cancel function for the current playlist
-		mu              sync.Mutex         // The mutex
+		currentPlaylist *anime.Playlist       // The current playlist that is being played (can be nil)
+		items           []*anime.PlaylistItem // The items of the current playlist
+		nextItem        *anime.PlaylistItem   // The next item that will be played (can be nil)
+		cancel          context.CancelFunc    // The cancel function for the current playlist
+		mu              sync.Mutex            // The mutex
 
-		playingLf             *anime.LocalFile        // The currently playing local file
-		playingMediaListEntry *anilist.AnimeListEntry // The currently playing media entry
-		completedCurrent      bool                    // Whether the current episode has been completed
+		playingIdx       int                 // The index of the currently playing item, -1 if no item has started
+		requestedItem    *anime.PlaylistItem // The item that was requested but has not started playing yet (can be nil)
+		completedCurrent bool                // Whether the current episode has been completed
 
 		currentState *PlaylistState // This is sent to the client to show the current playlist state
 
@@ -45,11 +48,12 @@ type (
 
 func newPlaylistHub(pm *PlaybackManager) *playlistHub {
 	return &playlistHub{
-		logger:           pm.Logger,
-		wsEventManager:   pm.wsEventManager,
-		playbackManager:  pm,
-		requestNewFileCh: make(chan string, 1),
-		endOfPlaylistCh:  make(chan struct{}, 1),
+		logger:            pm.Logger,
+		wsEventManager:    pm.wsEventManager,
+		playbackManager:   pm,
+		playingIdx:        -1,
+		requestNextItemCh: make(chan *anime.PlaylistItem, 1),
+		endOfPlaylistCh:   make(chan struct{}, 1),
 	}
 }
 
@@ -59,109 +63,201 @@ func (h *playlistHub) loadPlaylist(playlist *anime.Playlist) {
 		return
 	}
 	h.reset()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.currentPlaylist = playlist
-	h.logger.Debug().Str("name", playlist.Name).Msg("playlist hub: Playlist loaded")
+	h.items = playlist.GetItems()
+	h.logger.Debug().Str("name", playlist.Name).Int("items", len(h.items)).Msg("playlist hub: Playlist loaded")
 	return
 }
 
 func (h *playlistHub) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	if h.cancel != nil {
 		h.cancel()
 	}
 	h.currentPlaylist = nil
-	h.playingLf = nil
-	h.playingMediaListEntry = nil
+	h.items = nil
+	h.nextItem = nil
+	h.playingIdx = -1
+	h.requestedItem = nil
+	h.completedCurrent = false
 	h.currentState = nil
 	h.wsEventManager.SendEvent(events.PlaybackManagerPlaylistState, h.currentState)
 	return
 }
 
-func (h *playlistHub) check(currListEntry *anilist.AnimeListEntry, currLf *anime.LocalFile, ps PlaybackState) bool {
-	if h.currentPlaylist == nil || currLf == nil || currListEntry == nil {
-		h.currentPlaylist = nil
-		h.playingLf = nil
-		h.playingMediaListEntry = nil
-		return false
+// findFirstItem returns the first item of the playlist that should be played, filler episodes are skipped if the user chose to.
+func (h *playlistHub) findFirstItem() (*anime.PlaylistItem, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	idx, ok := h.findNextIdx(-1)
+	if !ok {
+		return nil, false
 	}
-	return true
+	return h.items[idx], true
 }
 
-func (h *playlistHub) findNextFile() (*anime.LocalFile, bool) {
-	if h.currentPlaylist == nil || h.playingLf == nil {
-		return nil, false
+// findNextIdx returns the index of the item that should be played after the item at idx.
+func (h *playlistHub) findNextIdx(idx int) (int, bool) {
+	for i := idx + 1; i < len(h.items); i++ {
+		// Filler episodes are skipped if the user chose to
+		if !h.playbackManager.fillerManager.ShouldSkipEpisode(h.items[i].MediaId, h.items[i].EpisodeNumber) {
+			return i, true
+		}
 	}
+	return -1, false
+}
 
-	for i, lf := range h.currentPlaylist.LocalFiles {
-		if lf.GetNormalizedPath() == h.playingLf.GetNormalizedPath() {
-			if i+1 < len(h.currentPlaylist.LocalFiles) {
-				return h.currentPlaylist.LocalFiles[i+1], true
-			}
-			break
+// indexOf returns the index of the item of the episode, starting from the currently playing item.
+func (h *playlistHub) indexOf(mediaId int, episodeNumber int) int {
+	for i := max(h.playingIdx, 0); i < len(h.items); i++ {
+		if h.items[i].MediaId == mediaId && h.items[i].EpisodeNumber == episodeNumber {
+			return i
 		}
 	}
+	return -1
+}
 
-	return nil, false
+// setRequestedItem is called before an item is played.
+func (h *playlistHub) setRequestedItem(item *anime.PlaylistItem) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requestedItem = item
 }
 
-func (h *playlistHub) playNextFile() (*anime.LocalFile, bool) {
-	if h.currentPlaylist == nil || h.playingLf == nil || h.nextLocalFile == nil {
-		return nil, false
-	}
+// isStreamRequested returns true if the stream of the media was requested by the playlist.
+// Streams that are not part of the playlist stop the playlist, see [PlaybackManager.StartStreamingUsingMediaPlayer].
+func (h *playlistHub) isStreamRequested(mediaId int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.currentPlaylist != nil && h.requestedItem != nil && h.requestedItem.MediaId == mediaId
+}
 
-	h.logger.Debug().Str("path", h.nextLocalFile.Path).Str("cmd", "playNextFile").Msg("playlist hub: Requesting next file")
-	h.requestNewFileCh <- h.nextLocalFile.Path
+// playNextItem requests the next item, it is called when the client skips to the next item or when an online stream is over.
+func (h *playlistHub) playNextItem() {
+	h.mu.Lock()
+	if h.currentPlaylist == nil || h.playingIdx == -1 || h.nextItem == nil {
+		h.mu.Unlock()
+		return
+	}
+	next := h.nextItem
 	h.completedCurrent = false
+	h.requestedItem = next
+	h.mu.Unlock()
 
-	return nil, false
+	h.logger.Debug().Int("mediaId", next.MediaId).Int("episode", next.EpisodeNumber).Str("cmd", "playNextItem").Msg("playlist hub: Requesting next item")
+	h.requestNextItemCh <- next
 }
 
-func (h *playlistHub) onVideoStart(currListEntry *anilist.AnimeListEntry, currLf *anime.LocalFile, ps PlaybackState) {
-	if !h.check(currListEntry, currLf, ps) {
+// onVideoStart is called when a local file or a stream starts playing.
+func (h *playlistHub) onVideoStart(media *anilist.BaseAnime, episodeNumber int) {
+	if media == nil {
 		return
 	}
 
-	h.completedCurrent = false
-	h.playingLf = currLf
-	h.playingMediaListEntry = currListEntry
+	h.mu.Lock()
+	if h.currentPlaylist == nil {
+		h.mu.Unlock()
+		return
+	}
+
+	idx := h.indexOf(media.GetID(), episodeNumber)
+	if idx != -1 {
+		h.setPlaying(idx, media)
+	}
+	h.mu.Unlock()
+
+	if idx == -1 {
+		// The user played an episode that is not part of the playlist
+		h.logger.Debug().Int("mediaId", media.GetID()).Int("episode", episodeNumber).Msg("playlist hub: Episode is not part of the playlist")
+		h.reset()
+		return
+	}
+
+	h.logger.Debug().Int("mediaId", media.GetID()).Int("episode", episodeNumber).Msgf("playlist hub: Video started")
 
-	h.nextLocalFile, _ = h.findNextFile()
+	return
+}
 
-	if h.playbackManager.animeCollection.IsAbsent() {
+// onOnlineStreamStart is called when an online stream item is sent to the client.
+// The progress tracking does not see online streams, so the item is considered playing right away.
+func (h *playlistHub) onOnlineStreamStart(item *anime.PlaylistItem) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.currentPlaylist == nil {
 		return
 	}
 
+	idx := h.indexOf(item.MediaId, item.EpisodeNumber)
+	if idx == -1 {
+		return
+	}
+
+	var media *anilist.BaseAnime
+	if ac, ok := h.playbackManager.animeCollection.Get(); ok {
+		if listEntry, found := ac.GetListEntryFromAnimeId(item.MediaId); found {
+			media = listEntry.GetMedia()
+		}
+	}
+
+	h.setPlaying(idx, media)
+	h.wsEventManager.SendEvent(events.PlaybackManagerPlaylistState, h.currentState)
+}
+
+// setPlaying sets the item at idx as the currently playing item and refreshes the playlist state.
+// media can be nil if the media is not in the collection.
+func (h *playlistHub) setPlaying(idx int, media *anilist.BaseAnime) {
+	h.completedCurrent = false
+	h.playingIdx = idx
+	h.requestedItem = nil
+
+	h.nextItem = nil
+	nextIdx, ok := h.findNextIdx(idx)
+	if ok {
+		h.nextItem = h.items[nextIdx]
+	}
+
 	// Refresh current playlist state
 	playlistState := &PlaylistState{}
+	current := h.items[idx]
 	playlistState.Current = &PlaylistStateItem{
-		Name:       fmt.Sprintf("%s - Episode %d", currListEntry.GetMedia().GetPreferredTitle(), currLf.GetEpisodeNumber()),
-		MediaImage: currListEntry.GetMedia().GetCoverImageSafe(),
-	}
-	if h.nextLocalFile != nil {
-		lfe, found := h.playbackManager.animeCollection.MustGet().GetListEntryFromAnimeId(h.nextLocalFile.MediaId)
-		if found {
-			playlistState.Next = &PlaylistStateItem{
-				Name:       fmt.Sprintf("%s - Episode %d", lfe.GetMedia().GetPreferredTitle(), h.nextLocalFile.GetEpisodeNumber()),
-				MediaImage: lfe.GetMedia().GetCoverImageSafe(),
+		Name:       fmt.Sprintf("Episode %d", current.EpisodeNumber),
+		MediaImage: "",
+	}
+	if media != nil {
+		playlistState.Current.Name = fmt.Sprintf("%s - Episode %d", media.GetPreferredTitle(), current.EpisodeNumber)
+		playlistState.Current.MediaImage = media.GetCoverImageSafe()
+	}
+	if h.nextItem != nil {
+		if ac, ok := h.playbackManager.animeCollection.Get(); ok {
+			if lfe, found := ac.GetListEntryFromAnimeId(h.nextItem.MediaId); found {
+				playlistState.Next = &PlaylistStateItem{
+					Name:       fmt.Sprintf("%s - Episode %d", lfe.GetMedia().GetPreferredTitle(), h.nextItem.EpisodeNumber),
+					MediaImage: lfe.GetMedia().GetCoverImageSafe(),
+				}
 			}
 		}
 	}
 	remaining := 0
-	for i, lf := range h.currentPlaylist.LocalFiles {
-		if lf.GetNormalizedPath() == currLf.GetNormalizedPath() {
-			remaining = len(h.currentPlaylist.LocalFiles) - 1 - i
-			break
-		}
+	for i, ok := h.findNextIdx(idx); ok; i, ok = h.findNextIdx(i) {
+		remaining++
 	}
 	playlistState.Remaining = remaining
 	h.currentState = playlistState
-
-	h.logger.Debug().Str("path", currLf.Path).Msgf("playlist hub: Video started")
-
-	return
 }
 
-func (h *playlistHub) onVideoCompleted(currListEntry *anilist.AnimeListEntry, currLf *anime.LocalFile, ps PlaybackState) {
-	if !h.check(currListEntry, currLf, ps) {
+func (h *playlistHub) onVideoCompleted() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.currentPlaylist == nil || h.playingIdx == -1 {
 		return
 	}
 
@@ -170,8 +266,11 @@ func (h *playlistHub) onVideoCompleted(currListEntry *anilist.AnimeListEntry, cu
 	return
 }
 
-func (h *playlistHub) onPlaybackStatus(currListEntry *anilist.AnimeListEntry, currLf *anime.LocalFile, ps PlaybackState) {
-	if !h.check(currListEntry, currLf, ps) {
+func (h *playlistHub) onPlaybackStatus() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.currentPlaylist == nil || h.playingIdx == -1 {
 		return
 	}
 
@@ -181,20 +280,18 @@ func (h *playlistHub) onPlaybackStatus(currListEntry *anilist.AnimeListEntry, cu
 }
 
 func (h *playlistHub) onTrackingStopped() {
-	if h.currentPlaylist == nil || h.playingLf == nil { // Return if no playlist
+	h.mu.Lock()
+	if h.currentPlaylist == nil || h.playingIdx == -1 { // Return if no playlist
+		h.mu.Unlock()
 		return
 	}
 
-	// When tracking has stopped, request next file
-	//if h.nextLocalFile != nil {
-	//	h.logger.Debug().Str("path", h.nextLocalFile.Path).Msg("playlist hub: Requesting next file")
-	//	h.requestNewFileCh <- h.nextLocalFile.Path
-	//} else {
-	//	h.logger.Debug().Msg("playlist hub: End of playlist")
-	//	h.endOfPlaylistCh <- struct{}{}
-	//}
+	// The playlist is stopped if the user closed the player before the end of the episode,
+	// unless the next item has already been requested
+	stop := !h.completedCurrent && h.requestedItem == nil
+	h.mu.Unlock()
 
-	if !h.completedCurrent {
+	if stop {
 		h.reset()
 	}
 
@@ -202,21 +299,24 @@ func (h *playlistHub) onTrackingStopped() {
 }
 
 func (h *playlistHub) onTrackingError() {
-	if h.currentPlaylist == nil { // Return if no playlist
+	h.mu.Lock()
+	if h.currentPlaylist == nil || !h.completedCurrent { // Return if no playlist
+		h.mu.Unlock()
 		return
 	}
 
-	// When tracking has stopped, request next file
-	if h.completedCurrent {
-		if h.nextLocalFile != nil {
-			h.logger.Debug().Str("path", h.nextLocalFile.Path).Msg("playlist hub: Requesting next file")
-			h.requestNewFileCh <- h.nextLocalFile.Path
-			h.completedCurrent = false
-		} else {
-			h.logger.Debug().Msg("playlist hub: End of playlist")
-			h.endOfPlaylistCh <- struct{}{}
-			h.completedCurrent = false
-		}
+	// When tracking has stopped after the episode was completed, request next item
+	next := h.nextItem
+	h.completedCurrent = false
+	h.requestedItem = next
+	h.mu.Unlock()
+
+	if next != nil {
+		h.logger.Debug().Int("mediaId", next.MediaId).Int("episode", next.EpisodeNumber).Msg("playlist hub: Requesting next item")
+		h.requestNextItemCh <- next
+	} else {
+		h.logger.Debug().Msg("playlist hub: End of playlist")
+		h.endOfPlaylistCh <- struct{}{}
 	}
 
 	return
@@ -0,0 +1,174 @@
+package playbackmanager
+
+import (
+	"errors"
+	"fmt"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/events"
+	"seanime/internal/library/anime"
+)
+
+type (
+	// PlaylistStreamer starts the stream of a playlist item that is not played from a local file.
+	// It is implemented by the torrent streaming and debrid streaming modules, see [PlaybackManager.SetPlaylistStreamer].
+	PlaylistStreamer interface {
+		// CanStreamPlaylistItem returns false if the streaming source is not enabled
+		CanStreamPlaylistItem() bool
+		// StreamPlaylistItem selects a torrent automatically and streams the episode with the media player.
+		// aniDBEpisode is the AniDB episode of the item, taken from the stream episode collection.
+		// The stream MUST be started with [PlaybackManager.StartStreamingUsingMediaPlayer] so that the progress is tracked.
+		StreamPlaylistItem(item *anime.PlaylistItem, aniDBEpisode string) error
+	}
+
+	// StreamEpisodeCollectionLoader returns the episodes of a media for streaming, see [PlaybackManager.SetStreamEpisodeCollection].
+	StreamEpisodeCollectionLoader func(mediaId int) ([]*anime.Episode, error)
+)
+
+// playlistStreamSources are the streaming sources used when a playlist item is not in the library, in order of preference
+var playlistStreamSources = []anime.PlaylistItemSource{
+	anime.PlaylistItemSourceTorrentStream,
+	anime.PlaylistItemSourceDebridStream,
+}
+
+// SetPlaylistStreamer sets the module used to stream the playlist items of a source.
+func (pm *PlaybackManager) SetPlaylistStreamer(source anime.PlaylistItemSource, streamer PlaylistStreamer) {
+	pm.playlistStreamers.Set(source, streamer)
+}
+
+// SetStreamEpisodeCollectionLoader sets the function used to load the episodes of a streamed playlist item.
+// Without the episodes, the progress of the stream cannot be tracked.
+func (pm *PlaybackManager) SetStreamEpisodeCollectionLoader(loader StreamEpisodeCollectionLoader) {
+	pm.streamEpisodeCollectionLoader = loader
+}
+
+func (pm *PlaybackManager) getPlaylistStreamer(source anime.PlaylistItemSource) (PlaylistStreamer, bool) {
+	streamer, ok := pm.playlistStreamers.Get(source)
+	if !ok || !streamer.CanStreamPlaylistItem() {
+		return nil, false
+	}
+	return streamer, true
+}
+
+// resolvePlaylistItem returns the source the playlist item should be played from.
+// The local file is returned if the item is played from the library.
+func (pm *PlaybackManager) resolvePlaylistItem(item *anime.PlaylistItem) (anime.PlaylistItemSource, *anime.LocalFile, error) {
+	switch item.Source {
+	case anime.PlaylistItemSourceAuto, anime.PlaylistItemSourceLocalFile:
+		lf, err := pm.findPlaylistItemLocalFile(item)
+		if err != nil {
+			return "", nil, err
+		}
+		if lf != nil {
+			return anime.PlaylistItemSourceLocalFile, lf, nil
+		}
+		if item.Source == anime.PlaylistItemSourceLocalFile {
+			return "", nil, fmt.Errorf("episode %d is not in the library", item.EpisodeNumber)
+		}
+		if pm.isOffline {
+			return "", nil, fmt.Errorf("episode %d is not in the library and cannot be streamed when offline", item.EpisodeNumber)
+		}
+		for _, source := range playlistStreamSources {
+			if _, ok := pm.getPlaylistStreamer(source); ok {
+				return source, nil, nil
+			}
+		}
+		return anime.PlaylistItemSourceOnlineStream, nil, nil
+
+	case anime.PlaylistItemSourceOnlineStream:
+		if pm.isOffline {
+			return "", nil, errors.New("cannot stream when offline")
+		}
+		return item.Source, nil, nil
+
+	case anime.PlaylistItemSourceTorrentStream, anime.PlaylistItemSourceDebridStream:
+		if pm.isOffline {
+			return "", nil, errors.New("cannot stream when offline")
+		}
+		if _, ok := pm.getPlaylistStreamer(item.Source); !ok {
+			return "", nil, fmt.Errorf("%s is not enabled", item.Source)
+		}
+		return item.Source, nil, nil
+	}
+
+	return "", nil, fmt.Errorf("unknown playlist item source %q", item.Source)
+}
+
+// findPlaylistItemLocalFile returns the main local file of the episode, nil if the episode is not in the library.
+func (pm *PlaybackManager) findPlaylistItemLocalFile(item *anime.PlaylistItem) (*anime.LocalFile, error) {
+	lfs, _, err := db_bridge.GetLocalFiles(pm.Database)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local files: %s", err.Error())
+	}
+	for _, lf := range lfs {
+		if lf.MediaId == item.MediaId && lf.IsMain() && lf.GetEpisodeNumber() == item.EpisodeNumber {
+			return lf, nil
+		}
+	}
+	return nil, nil
+}
+
+// playPlaylistItem plays an item of the current playlist from the source it resolves to.
+// Streams are started in the background, an error stops the playlist.
+func (pm *PlaybackManager) playPlaylistItem(item *anime.PlaylistItem) error {
+	source, lf, err := pm.resolvePlaylistItem(item)
+	if err != nil {
+		return err
+	}
+
+	pm.Logger.Debug().Int("mediaId", item.MediaId).Int("episode", item.EpisodeNumber).Str("source", string(source)).Msg("playback manager: Playing playlist item")
+
+	pm.playlistHub.setRequestedItem(item)
+
+	switch source {
+	case anime.PlaylistItemSourceLocalFile:
+		err = pm.MediaPlayerRepository.Play(lf.GetPath())
+		if err != nil {
+			return err
+		}
+		// Start tracking the video
+		pm.MediaPlayerRepository.StartTracking()
+
+	case anime.PlaylistItemSourceTorrentStream, anime.PlaylistItemSourceDebridStream:
+		streamer, _ := pm.getPlaylistStreamer(source)
+		episode, err := pm.loadStreamEpisodeCollection(item.MediaId, item.EpisodeNumber)
+		if err != nil {
+			return err
+		}
+		// Finding and buffering the torrent can take a while
+		go func() {
+			if err := streamer.StreamPlaylistItem(item, episode.AniDBEpisode); err != nil {
+				pm.Logger.Error().Err(err).Msg("playback manager: Failed to stream playlist item")
+				pm.wsEventManager.SendEvent(events.ErrorToast, fmt.Sprintf("Playlist: %s", err.Error()))
+				pm.playlistHub.reset()
+			}
+		}()
+
+	case anime.PlaylistItemSourceOnlineStream:
+		// The client plays the episode and tracks the progress, it requests the next item when the episode is over
+		pm.wsEventManager.SendEvent(events.PlaybackManagerPlaylistPlayOnlineStream, item)
+		pm.playlistHub.onOnlineStreamStart(item)
+	}
+
+	return nil
+}
+
+// loadStreamEpisodeCollection sets the stream episode collection for the media, so that the progress of the stream is tracked.
+// It returns the episode of the collection that is streamed.
+func (pm *PlaybackManager) loadStreamEpisodeCollection(mediaId int, episodeNumber int) (*anime.Episode, error) {
+	if pm.streamEpisodeCollectionLoader == nil {
+		return nil, errors.New("cannot load the episodes to stream")
+	}
+
+	episodes, err := pm.streamEpisodeCollectionLoader(mediaId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the episodes to stream: %s", err.Error())
+	}
+	pm.SetStreamEpisodeCollection(episodes)
+
+	for _, episode := range episodes {
+		if episode.EpisodeNumber == episodeNumber {
+			return episode, nil
+		}
+	}
+	return nil, fmt.Errorf("episode %d cannot be streamed", episodeNumber)
+}
@@ -72,6 +72,8 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				pm.currentMediaListEntry = mo.Some(currentMediaListEntry)
 				pm.currentLocalFile = mo.Some(currentLocalFile)
 				pm.currentLocalFileWrapperEntry = mo.Some(currentLocalFileWrapperEntry)
+				// Get the skip markers of the file
+				pm.loadSkipMarkers(currentLocalFile.GetPath())
 				pm.Logger.Debug().
 					Str("media", pm.currentMediaListEntry.MustGet().GetMedia().GetPreferredTitle()).
 					Int("episode", pm.currentLocalFile.MustGet().GetEpisodeNumber()).
@@ -83,8 +85,17 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 					Filepath:      pm.currentLocalFile.MustGet().GetPath(),
 				})
 
+				go pm.markSkippedFillersAsWatched(
+					pm.currentMediaListEntry.MustGet(),
+					pm.currentLocalFile.MustGet().GetEpisodeNumber(),
+					pm.currentLocalFileWrapperEntry.MustGet().GetProgressNumber(pm.currentLocalFile.MustGet()),
+				)
+
+				// ------- Viewing stats ------- //
+				pm.recordViewingProgress(status)
+
 				// ------- Playlist ------- //
-				go pm.playlistHub.onVideoStart(pm.currentMediaListEntry.MustGet(), pm.currentLocalFile.MustGet(), _ps)
+				go pm.playlistHub.onVideoStart(pm.currentMediaListEntry.MustGet().GetMedia(), pm.currentLocalFile.MustGet().GetEpisodeNumber())
 
 				// ------- Discord ------- //
 				if pm.discordPresence != nil && !pm.isOffline {
@@ -127,9 +138,12 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				// Push the video playback state to the history
 				pm.historyMap[status.Filename] = _ps
 
+				// ------- Viewing stats ------- //
+				pm.recordViewingProgress(status)
+
 				// ------- Playlist ------- //
 				if pm.currentMediaListEntry.IsPresent() && pm.currentLocalFile.IsPresent() {
-					go pm.playlistHub.onVideoCompleted(pm.currentMediaListEntry.MustGet(), pm.currentLocalFile.MustGet(), _ps)
+					go pm.playlistHub.onVideoCompleted()
 				}
 
 				pm.eventMu.Unlock()
@@ -141,7 +155,7 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 
 				// Find the next episode and set it to [PlaybackManager.nextEpisodeLocalFile]
 				if pm.currentMediaListEntry.IsPresent() && pm.currentLocalFile.IsPresent() && pm.currentLocalFileWrapperEntry.IsPresent() {
-					lf, ok := pm.currentLocalFileWrapperEntry.MustGet().FindNextEpisode(pm.currentLocalFile.MustGet())
+					lf, ok := pm.findNextEpisode(pm.currentLocalFileWrapperEntry.MustGet(), pm.currentLocalFile.MustGet())
 					if ok {
 						pm.nextEpisodeLocalFile = mo.Some(lf)
 					} else {
@@ -161,6 +175,9 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 					pm.continuityManager.UpdateExternalPlayerEpisodeWatchHistoryItem(pm.currentMediaPlaybackStatus.CurrentTimeInSeconds, pm.currentMediaPlaybackStatus.DurationInSeconds)
 				}
 
+				// ------- Viewing stats ------- //
+				pm.endViewingSession()
+
 				// ------- Playlist ------- //
 				go pm.playlistHub.onTrackingStopped()
 
@@ -195,9 +212,15 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				// Send the playback state to the client
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressPlaybackState, _ps)
 
+				// Skip the intro or outro
+				pm.autoSkip(status)
+
+				// ------- Viewing stats ------- //
+				pm.recordViewingProgress(status)
+
 				// ------- Playlist ------- //
 				if pm.currentMediaListEntry.IsPresent() && pm.currentLocalFile.IsPresent() {
-					go pm.playlistHub.onPlaybackStatus(pm.currentMediaListEntry.MustGet(), pm.currentLocalFile.MustGet(), _ps)
+					go pm.playlistHub.onPlaybackStatus()
 				}
 
 				pm.eventMu.Unlock()
@@ -255,6 +278,20 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 					Filepath:      "",
 				})
 
+				if listEntry, ok := pm.currentMediaListEntry.Get(); ok {
+					go pm.markSkippedFillersAsWatched(
+						listEntry,
+						pm.currentStreamEpisode.MustGet().GetEpisodeNumber(),
+						pm.currentStreamEpisode.MustGet().GetProgressNumber(),
+					)
+				}
+
+				// ------- Viewing stats ------- //
+				pm.recordViewingProgress(status)
+
+				// ------- Playlist ------- //
+				go pm.playlistHub.onVideoStart(pm.currentStreamMedia.MustGet(), pm.currentStreamEpisode.MustGet().GetEpisodeNumber())
+
 				// ------- Discord ------- //
 				if pm.discordPresence != nil && !pm.isOffline {
 					go pm.discordPresence.SetAnimeActivity(&discordrpc_presence.AnimeActivity{
@@ -296,6 +333,12 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				// Send the playback state to the client
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressPlaybackState, _ps)
 
+				// ------- Viewing stats ------- //
+				pm.recordViewingProgress(status)
+
+				// ------- Playlist ------- //
+				go pm.playlistHub.onPlaybackStatus()
+
 				pm.eventMu.Unlock()
 			case status := <-pm.mediaPlayerRepoSubscriber.StreamingVideoCompletedCh:
 				pm.eventMu.Lock()
@@ -331,6 +374,12 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				// Push the video playback state to the history
 				pm.historyMap[status.Filename] = _ps
 
+				// ------- Viewing stats ------- //
+				pm.recordViewingProgress(status)
+
+				// ------- Playlist ------- //
+				go pm.playlistHub.onVideoCompleted()
+
 				pm.eventMu.Unlock()
 			case reason := <-pm.mediaPlayerRepoSubscriber.StreamingTrackingStoppedCh:
 				pm.eventMu.Lock()
@@ -354,6 +403,12 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 				pm.Logger.Debug().Msg("playback manager: Received tracking stopped event")
 				pm.wsEventManager.SendEvent(events.PlaybackManagerProgressTrackingStopped, reason)
 
+				// ------- Viewing stats ------- //
+				pm.endViewingSession()
+
+				// ------- Playlist ------- //
+				go pm.playlistHub.onTrackingStopped()
+
 				// ------- Discord ------- //
 				if pm.discordPresence != nil && !pm.isOffline {
 					go pm.discordPresence.Close()
@@ -361,7 +416,10 @@ func (pm *PlaybackManager) listenToMediaPlayerEvents(ctx context.Context) {
 
 				pm.eventMu.Unlock()
 			case _ = <-pm.mediaPlayerRepoSubscriber.StreamingTrackingRetryCh:
-				// Do nothing
+				// DEVNOTE: Like for local files, the playlist hub plays the next item if the episode was completed
+
+				// ------- Playlist ------- //
+				go pm.playlistHub.onTrackingError()
 			}
 		}
 	}()
@@ -381,7 +439,7 @@ func (pm *PlaybackManager) getLocalFilePlaybackState(status *mediaplayer.Playbac
 	}
 
 	// Find the following episode
-	_, canPlayNext := pm.currentLocalFileWrapperEntry.MustGet().FindNextEpisode(pm.currentLocalFile.MustGet())
+	_, canPlayNext := pm.findNextEpisode(pm.currentLocalFileWrapperEntry.MustGet(), pm.currentLocalFile.MustGet())
 
 	return PlaybackState{
 		EpisodeNumber:        pm.currentLocalFileWrapperEntry.MustGet().GetProgressNumber(pm.currentLocalFile.MustGet()),
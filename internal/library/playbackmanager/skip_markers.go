@@ -0,0 +1,60 @@
+package playbackmanager
+
+import (
+	"seanime/internal/mediaplayers/mediaplayer"
+)
+
+// loadSkipMarkers gets the intro and outro of the local file that started playing.
+func (pm *PlaybackManager) loadSkipMarkers(path string) {
+	pm.currentSkipMarkers = nil
+	pm.introSkipped = false
+	pm.outroSkipped = false
+
+	if pm.skipDetector == nil {
+		return
+	}
+
+	markers, err := pm.skipDetector.GetMarkers(path)
+	if err != nil {
+		pm.Logger.Warn().Err(err).Msg("playback manager: Failed to get skip markers")
+		return
+	}
+	pm.currentSkipMarkers = markers
+}
+
+// autoSkip seeks to the end of the intro or the outro when the playback reaches it.
+// Each segment is skipped once per file, so the user can seek back to watch it.
+func (pm *PlaybackManager) autoSkip(status *mediaplayer.PlaybackStatus) {
+	markers := pm.currentSkipMarkers
+	if markers == nil || pm.MediaPlayerRepository == nil || pm.settings == nil {
+		return
+	}
+
+	t := status.CurrentTimeInSeconds
+
+	if pm.settings.AutoSkipIntro && !pm.introSkipped && markers.HasIntro() && isInSkipSegment(t, markers.IntroStart, markers.IntroEnd) {
+		pm.introSkipped = true
+		pm.Logger.Debug().Float64("to", markers.IntroEnd).Msg("playback manager: Skipping intro")
+		go pm.seekAndResume(markers.IntroEnd)
+		return
+	}
+
+	if pm.settings.AutoSkipOutro && !pm.outroSkipped && markers.HasOutro() && isInSkipSegment(t, markers.OutroStart, markers.OutroEnd) {
+		pm.outroSkipped = true
+		pm.Logger.Debug().Float64("to", markers.OutroEnd).Msg("playback manager: Skipping outro")
+		go pm.seekAndResume(markers.OutroEnd)
+	}
+}
+
+func (pm *PlaybackManager) seekAndResume(position float64) {
+	if err := pm.MediaPlayerRepository.Seek(position); err != nil {
+		pm.Logger.Warn().Err(err).Msg("playback manager: Failed to skip segment")
+		return
+	}
+	_ = pm.MediaPlayerRepository.Resume()
+}
+
+// isInSkipSegment returns true if the position is in the segment and not already at its end.
+func isInSkipSegment(position float64, start float64, end float64) bool {
+	return position >= start && position < end-1
+}
@@ -0,0 +1,46 @@
+package playbackmanager
+
+import (
+	"fmt"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/library/anime"
+)
+
+// ResolveSmartPlaylist returns the local files matching the rules of a smart playlist,
+// using the cached AniList collection, which is refreshed after each progress update.
+func (pm *PlaybackManager) ResolveSmartPlaylist(rules *anime.SmartPlaylistRules) ([]*anime.LocalFile, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	animeCollection, err := pm.platform.GetAnimeCollection(false)
+	if err != nil {
+		return nil, err
+	}
+
+	lfs, _, err := db_bridge.GetLocalFiles(pm.Database)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local files: %s", err.Error())
+	}
+
+	// Tags are not part of the collection, they are only fetched for the media that match the other filters
+	var mediaTags map[int][]string
+	if len(rules.Tags) > 0 {
+		ids := rules.GetTagCandidates(animeCollection, lfs)
+		if len(ids) > 0 {
+			mediaTags, err = anilist.FetchMediaTags(ids)
+			if err != nil {
+				return nil, fmt.Errorf("error getting media tags: %s", err.Error())
+			}
+		}
+	}
+
+	return anime.ResolveSmartPlaylist(&anime.ResolveSmartPlaylistOptions{
+		Rules:           rules,
+		AnimeCollection: animeCollection,
+		LocalFiles:      lfs,
+		MediaTags:       mediaTags,
+		SkipEpisode:     pm.fillerManager.ShouldSkipEpisode,
+	})
+}
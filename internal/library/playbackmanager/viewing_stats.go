@@ -0,0 +1,65 @@
+package playbackmanager
+
+import (
+	"seanime/internal/continuity"
+	"seanime/internal/library/viewingstats"
+	"seanime/internal/mediaplayers/mediaplayer"
+)
+
+// getViewingStatsEpisode returns the episode being played, for the viewing statistics.
+func (pm *PlaybackManager) getViewingStatsEpisode() (mediaId int, episodeNumber int, source viewingstats.Source, ok bool) {
+	switch pm.currentPlaybackType {
+	case LocalFilePlayback:
+		lf, found := pm.currentLocalFile.Get()
+		if !found || lf.MediaId == 0 {
+			return 0, 0, "", false
+		}
+		return lf.MediaId, lf.GetEpisodeNumber(), viewingstats.SourceLocalFile, true
+	case StreamPlayback:
+		media, found := pm.currentStreamMedia.Get()
+		if !found {
+			return 0, 0, "", false
+		}
+		episode, found := pm.currentStreamEpisode.Get()
+		if !found {
+			return 0, 0, "", false
+		}
+		return media.GetID(), episode.GetEpisodeNumber(), pm.currentStreamSource, true
+	}
+	return 0, 0, "", false
+}
+
+// recordViewingProgress logs the progress of the episode being played in the watch log.
+func (pm *PlaybackManager) recordViewingProgress(status *mediaplayer.PlaybackStatus) {
+	if pm.continuityManager == nil || status == nil {
+		return
+	}
+
+	mediaId, episodeNumber, source, ok := pm.getViewingStatsEpisode()
+	if !ok {
+		return
+	}
+
+	pm.continuityManager.LogWatchProgress(&continuity.LogWatchProgressOptions{
+		MediaId:       mediaId,
+		EpisodeNumber: episodeNumber,
+		Kind:          continuity.ExternalPlayerKind,
+		Source:        string(source),
+		CurrentTime:   status.CurrentTimeInSeconds,
+		Duration:      status.DurationInSeconds,
+	})
+}
+
+// endViewingSession saves the watch log entry of the episode that was being played.
+func (pm *PlaybackManager) endViewingSession() {
+	if pm.continuityManager == nil {
+		return
+	}
+
+	mediaId, episodeNumber, _, ok := pm.getViewingStatsEpisode()
+	if !ok {
+		return
+	}
+
+	pm.continuityManager.EndWatchSession(mediaId, episodeNumber)
+}
@@ -0,0 +1,39 @@
+package viewingstats
+
+import (
+	"github.com/rs/zerolog"
+	"seanime/internal/database/db"
+)
+
+const (
+	SourceLocalFile     Source = "localfile"
+	SourceTorrentStream Source = "torrentstream"
+	SourceDebridStream  Source = "debridstream"
+	SourceOnlineStream  Source = "onlinestream"
+	SourceMediastream   Source = "mediastream"
+	SourceOther         Source = "other"
+)
+
+type (
+	// Source is where the episode was played from, it is stored in the watch log entries.
+	Source string
+
+	// Manager computes the viewing statistics from the watch log.
+	// The progress of the episodes is logged by continuity.Manager.
+	Manager struct {
+		db     *db.Database
+		logger *zerolog.Logger
+	}
+
+	NewManagerOptions struct {
+		Database *db.Database
+		Logger   *zerolog.Logger
+	}
+)
+
+func NewManager(opts *NewManagerOptions) *Manager {
+	return &Manager{
+		db:     opts.Database,
+		logger: opts.Logger,
+	}
+}
@@ -0,0 +1,394 @@
+package viewingstats
+
+import (
+	"cmp"
+	"fmt"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/models"
+	"slices"
+	"time"
+)
+
+const (
+	dayLayout = "2006-01-02"
+	// maxRecapItems is the number of series and genres listed in the yearly recap.
+	maxRecapItems = 10
+	// maxRewatchedEpisodes is the number of most rewatched episodes listed.
+	maxRewatchedEpisodes = 10
+)
+
+type (
+	// ViewingStats are computed from the viewing sessions of the watch log.
+	// All the times are in seconds.
+	ViewingStats struct {
+		TotalSeconds      float64 `json:"totalSeconds"`
+		Sessions          int     `json:"sessions"`
+		EpisodesCompleted int     `json:"episodesCompleted"`
+		// Time watched per day, oldest first
+		Days []*PeriodStats `json:"days"`
+		// Time watched per ISO week, oldest first
+		Weeks []*PeriodStats `json:"weeks"`
+		// Most watched first
+		Series []*SeriesStats `json:"series"`
+		// The time watched of a series counts for all of its genres, most watched first
+		Genres []*GenreStats `json:"genres"`
+		// Most watched first
+		Sources []*SourceStats `json:"sources"`
+		// Longest run of consecutive days with something watched
+		LongestStreak *Streak `json:"longestStreak,omitempty"`
+		// Run of consecutive days ending today or yesterday
+		CurrentStreak *Streak `json:"currentStreak,omitempty"`
+		// Most episodes of the same series completed in a day
+		LongestBinge *Binge `json:"longestBinge,omitempty"`
+		// Episodes completed more than once, most viewed first
+		MostRewatched []*RewatchedEpisode `json:"mostRewatched"`
+	}
+
+	PeriodStats struct {
+		Period            string  `json:"period"` // "2006-01-02" for days, "2006-W01" for weeks
+		Seconds           float64 `json:"seconds"`
+		EpisodesCompleted int     `json:"episodesCompleted"`
+	}
+
+	SeriesStats struct {
+		MediaId           int     `json:"mediaId"`
+		Title             string  `json:"title"` // Empty if the media is not in the collection
+		Seconds           float64 `json:"seconds"`
+		EpisodesCompleted int     `json:"episodesCompleted"`
+	}
+
+	GenreStats struct {
+		Genre   string  `json:"genre"`
+		Seconds float64 `json:"seconds"`
+	}
+
+	SourceStats struct {
+		Source   Source  `json:"source"`
+		Seconds  float64 `json:"seconds"`
+		Sessions int     `json:"sessions"`
+	}
+
+	Streak struct {
+		Start string `json:"start"` // "2006-01-02"
+		End   string `json:"end"`
+		Days  int    `json:"days"`
+	}
+
+	Binge struct {
+		MediaId  int    `json:"mediaId"`
+		Title    string `json:"title"`
+		Date     string `json:"date"`
+		Episodes int    `json:"episodes"`
+	}
+
+	RewatchedEpisode struct {
+		MediaId       int    `json:"mediaId"`
+		Title         string `json:"title"`
+		EpisodeNumber int    `json:"episodeNumber"`
+		Views         int    `json:"views"`
+	}
+
+	// YearlyRecap is a summary of the viewing statistics of a year, it can be exported as JSON.
+	YearlyRecap struct {
+		Year        int            `json:"year"`
+		GeneratedAt time.Time      `json:"generatedAt"`
+		BusiestDay  *PeriodStats   `json:"busiestDay,omitempty"`
+		TopSeries   []*SeriesStats `json:"topSeries"`
+		TopGenres   []*GenreStats  `json:"topGenres"`
+		Stats       *ViewingStats  `json:"stats"`
+	}
+
+	ComputeOptions struct {
+		Sessions []*models.WatchLogEntry
+		// Used for the titles and genres of the media, can be nil
+		AnimeCollection *anilist.AnimeCollection
+		// Used for the current streak
+		Now time.Time
+		// Used to group the sessions by day, defaults to the local time zone
+		Location *time.Location
+	}
+)
+
+// GetStats returns the viewing statistics of the sessions that started in the time range, zero times are ignored.
+func (m *Manager) GetStats(from time.Time, to time.Time, animeCollection *anilist.AnimeCollection) (*ViewingStats, error) {
+	sessions, err := m.getSessions(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compute(&ComputeOptions{
+		Sessions:        sessions,
+		AnimeCollection: animeCollection,
+		Now:             time.Now(),
+	}), nil
+}
+
+// GetYearlyRecap returns the recap of a year.
+func (m *Manager) GetYearlyRecap(year int, animeCollection *anilist.AnimeCollection) (*YearlyRecap, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	sessions, err := m.getSessions(from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeYearlyRecap(year, &ComputeOptions{
+		Sessions:        sessions,
+		AnimeCollection: animeCollection,
+		Now:             time.Now(),
+	}), nil
+}
+
+// getSessions returns the watch log entries that started in the time range.
+// Sessions during which nothing was played are not counted.
+func (m *Manager) getSessions(from time.Time, to time.Time) ([]*models.WatchLogEntry, error) {
+	entries, err := m.db.GetWatchLogEntries(0, from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.WatchLogEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.WatchedSeconds <= 0 && !e.Completed {
+			continue
+		}
+		if (!from.IsZero() && e.CreatedAt.Before(from)) || (!to.IsZero() && !e.CreatedAt.Before(to)) {
+			continue
+		}
+		ret = append(ret, e)
+	}
+
+	return ret, nil
+}
+
+// Compute aggregates the viewing sessions.
+func Compute(opts *ComputeOptions) *ViewingStats {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	ret := &ViewingStats{
+		Days:          make([]*PeriodStats, 0),
+		Weeks:         make([]*PeriodStats, 0),
+		Series:        make([]*SeriesStats, 0),
+		Genres:        make([]*GenreStats, 0),
+		Sources:       make([]*SourceStats, 0),
+		MostRewatched: make([]*RewatchedEpisode, 0),
+	}
+
+	days := make(map[string]*PeriodStats)
+	weeks := make(map[string]*PeriodStats)
+	series := make(map[int]*SeriesStats)
+	genres := make(map[string]*GenreStats)
+	sources := make(map[Source]*SourceStats)
+	// Episodes completed per series per day
+	binges := make(map[string]*Binge)
+	// Completed sessions per episode
+	views := make(map[[2]int]int)
+
+	for _, s := range opts.Sessions {
+		media := getMedia(opts.AnimeCollection, s.MediaId)
+		startedAt := s.CreatedAt.In(loc)
+		day := startedAt.Format(dayLayout)
+		year, week := startedAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-W%02d", year, week)
+
+		completed := 0
+		if s.Completed {
+			completed = 1
+		}
+
+		ret.TotalSeconds += s.WatchedSeconds
+		ret.Sessions++
+		ret.EpisodesCompleted += completed
+
+		addPeriod(days, day, s.WatchedSeconds, completed)
+		addPeriod(weeks, weekKey, s.WatchedSeconds, completed)
+
+		if _, ok := series[s.MediaId]; !ok {
+			series[s.MediaId] = &SeriesStats{MediaId: s.MediaId, Title: getTitle(media)}
+		}
+		series[s.MediaId].Seconds += s.WatchedSeconds
+		series[s.MediaId].EpisodesCompleted += completed
+
+		if media != nil {
+			for _, genre := range media.GetGenres() {
+				if genre == nil {
+					continue
+				}
+				if _, ok := genres[*genre]; !ok {
+					genres[*genre] = &GenreStats{Genre: *genre}
+				}
+				genres[*genre].Seconds += s.WatchedSeconds
+			}
+		}
+
+		source := Source(s.Source)
+		if source == "" {
+			source = SourceOther
+		}
+		if _, ok := sources[source]; !ok {
+			sources[source] = &SourceStats{Source: source}
+		}
+		sources[source].Seconds += s.WatchedSeconds
+		sources[source].Sessions++
+
+		if s.Completed {
+			bingeKey := fmt.Sprintf("%d_%s", s.MediaId, day)
+			if _, ok := binges[bingeKey]; !ok {
+				binges[bingeKey] = &Binge{MediaId: s.MediaId, Title: getTitle(media), Date: day}
+			}
+			binges[bingeKey].Episodes++
+			views[[2]int{s.MediaId, s.EpisodeNumber}]++
+		}
+	}
+
+	ret.Days = sortedPeriods(days)
+	ret.Weeks = sortedPeriods(weeks)
+
+	for _, s := range series {
+		ret.Series = append(ret.Series, s)
+	}
+	slices.SortFunc(ret.Series, func(a, b *SeriesStats) int {
+		return cmp.Or(cmp.Compare(b.Seconds, a.Seconds), cmp.Compare(a.MediaId, b.MediaId))
+	})
+
+	for _, g := range genres {
+		ret.Genres = append(ret.Genres, g)
+	}
+	slices.SortFunc(ret.Genres, func(a, b *GenreStats) int {
+		return cmp.Or(cmp.Compare(b.Seconds, a.Seconds), cmp.Compare(a.Genre, b.Genre))
+	})
+
+	for _, s := range sources {
+		ret.Sources = append(ret.Sources, s)
+	}
+	slices.SortFunc(ret.Sources, func(a, b *SourceStats) int {
+		return cmp.Or(cmp.Compare(b.Seconds, a.Seconds), cmp.Compare(a.Source, b.Source))
+	})
+
+	for _, b := range binges {
+		if ret.LongestBinge == nil || b.Episodes > ret.LongestBinge.Episodes ||
+			(b.Episodes == ret.LongestBinge.Episodes && b.Date < ret.LongestBinge.Date) {
+			ret.LongestBinge = b
+		}
+	}
+
+	for key, count := range views {
+		if count < 2 {
+			continue
+		}
+		ret.MostRewatched = append(ret.MostRewatched, &RewatchedEpisode{
+			MediaId:       key[0],
+			Title:         getTitle(getMedia(opts.AnimeCollection, key[0])),
+			EpisodeNumber: key[1],
+			Views:         count,
+		})
+	}
+	slices.SortFunc(ret.MostRewatched, func(a, b *RewatchedEpisode) int {
+		return cmp.Or(cmp.Compare(b.Views, a.Views), cmp.Compare(a.MediaId, b.MediaId), cmp.Compare(a.EpisodeNumber, b.EpisodeNumber))
+	})
+	if len(ret.MostRewatched) > maxRewatchedEpisodes {
+		ret.MostRewatched = ret.MostRewatched[:maxRewatchedEpisodes]
+	}
+
+	ret.LongestStreak, ret.CurrentStreak = computeStreaks(ret.Days, opts.Now.In(loc))
+
+	return ret
+}
+
+// ComputeYearlyRecap aggregates the viewing sessions of a year.
+func ComputeYearlyRecap(year int, opts *ComputeOptions) *YearlyRecap {
+	stats := Compute(opts)
+
+	ret := &YearlyRecap{
+		Year:        year,
+		GeneratedAt: opts.Now,
+		TopSeries:   stats.Series[:min(len(stats.Series), maxRecapItems)],
+		TopGenres:   stats.Genres[:min(len(stats.Genres), maxRecapItems)],
+		Stats:       stats,
+	}
+
+	for _, d := range stats.Days {
+		if ret.BusiestDay == nil || d.Seconds > ret.BusiestDay.Seconds {
+			ret.BusiestDay = d
+		}
+	}
+
+	return ret
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func addPeriod(periods map[string]*PeriodStats, key string, seconds float64, completed int) {
+	if _, ok := periods[key]; !ok {
+		periods[key] = &PeriodStats{Period: key}
+	}
+	periods[key].Seconds += seconds
+	periods[key].EpisodesCompleted += completed
+}
+
+func sortedPeriods(periods map[string]*PeriodStats) []*PeriodStats {
+	ret := make([]*PeriodStats, 0, len(periods))
+	for _, p := range periods {
+		ret = append(ret, p)
+	}
+	slices.SortFunc(ret, func(a, b *PeriodStats) int {
+		return cmp.Compare(a.Period, b.Period)
+	})
+	return ret
+}
+
+// computeStreaks returns the longest streak and the current streak of consecutive days, days must be sorted.
+func computeStreaks(days []*PeriodStats, now time.Time) (longest *Streak, current *Streak) {
+	var streak *Streak
+	var prev time.Time
+	for _, d := range days {
+		if d.Seconds <= 0 {
+			continue
+		}
+		date, err := time.Parse(dayLayout, d.Period)
+		if err != nil {
+			continue
+		}
+		if streak != nil && date.Equal(prev.AddDate(0, 0, 1)) {
+			streak.End = d.Period
+			streak.Days++
+		} else {
+			streak = &Streak{Start: d.Period, End: d.Period, Days: 1}
+		}
+		prev = date
+		if longest == nil || streak.Days > longest.Days {
+			longest = streak
+		}
+	}
+
+	// The last streak is current if it ends today or yesterday
+	if streak != nil {
+		today := now.Format(dayLayout)
+		yesterday := now.AddDate(0, 0, -1).Format(dayLayout)
+		if streak.End == today || streak.End == yesterday {
+			current = streak
+		}
+	}
+
+	return longest, current
+}
+
+func getMedia(animeCollection *anilist.AnimeCollection, mediaId int) *anilist.BaseAnime {
+	if animeCollection == nil {
+		return nil
+	}
+	entry, found := animeCollection.GetListEntryFromAnimeId(mediaId)
+	if !found {
+		return nil
+	}
+	return entry.GetMedia()
+}
+
+func getTitle(media *anilist.BaseAnime) string {
+	if media == nil {
+		return ""
+	}
+	return media.GetPreferredTitle()
+}
@@ -0,0 +1,132 @@
+package viewingstats
+
+import (
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/models"
+	"testing"
+	"time"
+)
+
+func TestCompute(t *testing.T) {
+
+	newSession := func(mediaId int, episode int, source Source, startedAt string, seconds float64, completed bool) *models.WatchLogEntry {
+		date, err := time.ParseInLocation("2006-01-02 15:04", startedAt, time.UTC)
+		require.NoError(t, err)
+		return &models.WatchLogEntry{
+			BaseModel:      models.BaseModel{CreatedAt: date},
+			MediaId:        mediaId,
+			EpisodeNumber:  episode,
+			Source:         string(source),
+			WatchedSeconds: seconds,
+			Duration:       1440,
+			Completed:      completed,
+		}
+	}
+
+	newEntry := func(id int, title string, genres ...string) *anilist.AnimeListEntry {
+		return &anilist.AnimeListEntry{
+			Media: &anilist.BaseAnime{
+				ID:     id,
+				Title:  &anilist.BaseAnime_Title{UserPreferred: lo.ToPtr(title)},
+				Genres: lo.ToSlicePtr(genres),
+			},
+		}
+	}
+
+	collection := &anilist.AnimeCollection{
+		MediaListCollection: &anilist.AnimeCollection_MediaListCollection{
+			Lists: []*anilist.AnimeCollection_MediaListCollection_Lists{
+				{
+					Entries: []*anilist.AnimeListEntry{
+						newEntry(21, "One Piece", "Action", "Adventure"),
+						newEntry(22222, "Blue Lock", "Sports"),
+					},
+				},
+			},
+		},
+	}
+
+	sessions := []*models.WatchLogEntry{
+		newSession(21, 1071, SourceLocalFile, "2024-12-30 20:00", 1400, true),
+		newSession(21, 1072, SourceLocalFile, "2024-12-30 20:30", 1400, true),
+		newSession(21, 1073, SourceTorrentStream, "2024-12-30 21:00", 1400, true),
+		newSession(22222, 1, SourceOnlineStream, "2024-12-31 10:00", 600, false),
+		newSession(22222, 1, SourceOnlineStream, "2025-01-01 10:00", 1400, true),
+		newSession(21, 1071, SourceDebridStream, "2025-01-03 22:00", 1400, true),
+		newSession(21, 1071, SourceLocalFile, "2025-01-04 22:00", 1400, true),
+		newSession(9999, 5, SourceMediastream, "2025-01-04 23:00", 100, false),
+	}
+
+	stats := Compute(&ComputeOptions{
+		Sessions:        sessions,
+		AnimeCollection: collection,
+		Now:             time.Date(2025, time.January, 5, 12, 0, 0, 0, time.UTC),
+		Location:        time.UTC,
+	})
+
+	assert.Equal(t, 9100., stats.TotalSeconds)
+	assert.Equal(t, 8, stats.Sessions)
+	assert.Equal(t, 6, stats.EpisodesCompleted)
+
+	// Days and weeks
+	assert.Equal(t, []*PeriodStats{
+		{Period: "2024-12-30", Seconds: 4200, EpisodesCompleted: 3},
+		{Period: "2024-12-31", Seconds: 600, EpisodesCompleted: 0},
+		{Period: "2025-01-01", Seconds: 1400, EpisodesCompleted: 1},
+		{Period: "2025-01-03", Seconds: 1400, EpisodesCompleted: 1},
+		{Period: "2025-01-04", Seconds: 1500, EpisodesCompleted: 1},
+	}, stats.Days)
+	assert.Equal(t, []*PeriodStats{
+		{Period: "2025-W01", Seconds: 9100, EpisodesCompleted: 6},
+	}, stats.Weeks)
+
+	// Series
+	require.Len(t, stats.Series, 3)
+	assert.Equal(t, &SeriesStats{MediaId: 21, Title: "One Piece", Seconds: 7000, EpisodesCompleted: 5}, stats.Series[0])
+	assert.Equal(t, &SeriesStats{MediaId: 22222, Title: "Blue Lock", Seconds: 2000, EpisodesCompleted: 1}, stats.Series[1])
+	assert.Equal(t, &SeriesStats{MediaId: 9999, Title: "", Seconds: 100, EpisodesCompleted: 0}, stats.Series[2])
+
+	// Genres
+	assert.Equal(t, []*GenreStats{
+		{Genre: "Action", Seconds: 7000},
+		{Genre: "Adventure", Seconds: 7000},
+		{Genre: "Sports", Seconds: 2000},
+	}, stats.Genres)
+
+	// Sources
+	assert.Equal(t, []*SourceStats{
+		{Source: SourceLocalFile, Seconds: 4200, Sessions: 3},
+		{Source: SourceOnlineStream, Seconds: 2000, Sessions: 2},
+		{Source: SourceDebridStream, Seconds: 1400, Sessions: 1},
+		{Source: SourceTorrentStream, Seconds: 1400, Sessions: 1},
+		{Source: SourceMediastream, Seconds: 100, Sessions: 1},
+	}, stats.Sources)
+
+	// Streaks
+	assert.Equal(t, &Streak{Start: "2024-12-30", End: "2025-01-01", Days: 3}, stats.LongestStreak)
+	assert.Equal(t, &Streak{Start: "2025-01-03", End: "2025-01-04", Days: 2}, stats.CurrentStreak)
+
+	// Binge
+	assert.Equal(t, &Binge{MediaId: 21, Title: "One Piece", Date: "2024-12-30", Episodes: 3}, stats.LongestBinge)
+
+	// Rewatched episodes
+	assert.Equal(t, []*RewatchedEpisode{
+		{MediaId: 21, Title: "One Piece", EpisodeNumber: 1071, Views: 3},
+	}, stats.MostRewatched)
+
+	// Recap
+	recap := ComputeYearlyRecap(2025, &ComputeOptions{
+		Sessions:        sessions[4:],
+		AnimeCollection: collection,
+		Now:             time.Date(2025, time.January, 5, 12, 0, 0, 0, time.UTC),
+		Location:        time.UTC,
+	})
+	assert.Equal(t, 2025, recap.Year)
+	assert.Equal(t, &PeriodStats{Period: "2025-01-04", Seconds: 1500, EpisodesCompleted: 1}, recap.BusiestDay)
+	require.Len(t, recap.TopSeries, 3)
+	assert.Equal(t, 21, recap.TopSeries[0].MediaId)
+	assert.Equal(t, "2025-01-04", recap.Stats.CurrentStreak.End)
+}
@@ -0,0 +1,20 @@
+package manga
+
+// DEVNOTE: The struct is defined in this package because it is imported by both the manga autodownloader package and the db_bridge package.
+
+type (
+	// AutoDownloaderRule is a rule used to automatically download new chapters of a manga.
+	// The structs are sent to the client, thus adding `dbId` to facilitate mutations.
+	AutoDownloaderRule struct {
+		DbID     uint   `json:"dbId"` // Will be set when fetched from the database
+		Enabled  bool   `json:"enabled"`
+		MediaId  int    `json:"mediaId"`
+		Provider string `json:"provider"` // Manga provider extension ID
+		// Scanlators in order of preference.
+		// If none of the scanlators are found for a chapter, any scanlator is used.
+		Scanlators []string `json:"scanlators"`
+		// Language of the chapters, e.g. "en".
+		// Leave empty to accept any language.
+		Language string `json:"language"`
+	}
+)
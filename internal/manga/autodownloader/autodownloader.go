@@ -0,0 +1,414 @@
+package autodownloader
+
+import (
+	"fmt"
+	"seanime/internal/api/anilist"
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/database/models"
+	"seanime/internal/events"
+	"seanime/internal/hook"
+	"seanime/internal/manga"
+	manga_providers "seanime/internal/manga/providers"
+	"seanime/internal/notifier"
+	"seanime/internal/util"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/mo"
+
+	hibikemanga "seanime/internal/extension/hibike/manga"
+)
+
+const (
+	DefaultInterval = 60 // minutes
+	MinInterval     = 15 // minutes
+	// maxChaptersPerRun limits the number of chapters queued for a rule in a single run.
+	// This avoids queuing hundreds of chapters when a rule is created for a long-running series.
+	maxChaptersPerRun = 20
+	// delayBetweenChapters is used to avoid rate limiting when fetching chapter pages.
+	delayBetweenChapters = 400 * time.Millisecond
+)
+
+type (
+	// AutoDownloader periodically checks the manga auto downloader rules for new chapters
+	// and adds them to the chapter download queue.
+	AutoDownloader struct {
+		logger            *zerolog.Logger
+		database          *db.Database
+		repository        *manga.Repository
+		downloader        *manga.Downloader
+		wsEventManager    events.WSEventManagerInterface
+		mangaCollection   mo.Option[*anilist.MangaCollection]
+		settings          *models.MangaSettings
+		settingsUpdatedCh chan struct{}
+		startCh           chan struct{}
+		mu                sync.Mutex
+	}
+
+	NewAutoDownloaderOptions struct {
+		Logger         *zerolog.Logger
+		Database       *db.Database
+		Repository     *manga.Repository
+		Downloader     *manga.Downloader
+		WSEventManager events.WSEventManagerInterface
+	}
+)
+
+func New(opts *NewAutoDownloaderOptions) *AutoDownloader {
+	return &AutoDownloader{
+		logger:          opts.Logger,
+		database:        opts.Database,
+		repository:      opts.Repository,
+		downloader:      opts.Downloader,
+		wsEventManager:  opts.WSEventManager,
+		mangaCollection: mo.None[*anilist.MangaCollection](),
+		settings: &models.MangaSettings{
+			AutoDownloaderEnabled:  false,
+			AutoDownloaderInterval: DefaultInterval,
+		},
+		settingsUpdatedCh: make(chan struct{}, 1),
+		startCh:           make(chan struct{}, 1),
+		mu:                sync.Mutex{},
+	}
+}
+
+// SetSettings should be called after the settings are fetched and updated from the database.
+func (ad *AutoDownloader) SetSettings(settings *models.MangaSettings) {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/SetSettings", func() {})
+
+	if ad == nil || settings == nil {
+		return
+	}
+
+	go func() {
+		ad.mu.Lock()
+		ad.settings = settings
+		ad.mu.Unlock()
+		ad.settingsUpdatedCh <- struct{}{} // Notify that the settings have been updated
+	}()
+}
+
+func (ad *AutoDownloader) SetMangaCollection(mc *anilist.MangaCollection) {
+	if ad == nil {
+		return
+	}
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	ad.mangaCollection = mo.Some(mc)
+}
+
+// Start will start the manga auto downloader in a goroutine
+func (ad *AutoDownloader) Start() {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/Start", func() {})
+
+	if ad == nil {
+		return
+	}
+	go ad.start()
+}
+
+// Run tells the manga auto downloader to check for new chapters if it is enabled.
+func (ad *AutoDownloader) Run() {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/Run", func() {})
+
+	if ad == nil {
+		return
+	}
+	go func() {
+		ad.startCh <- struct{}{}
+		ad.logger.Trace().Msg("manga autodownloader: Received start signal")
+	}()
+}
+
+func (ad *AutoDownloader) isEnabled() bool {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+	return ad.settings != nil && ad.settings.AutoDownloaderEnabled
+}
+
+func (ad *AutoDownloader) getInterval() time.Duration {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+
+	interval := DefaultInterval
+	if ad.settings != nil && ad.settings.AutoDownloaderInterval >= MinInterval {
+		interval = ad.settings.AutoDownloaderInterval
+	}
+	return time.Duration(interval) * time.Minute
+}
+
+func (ad *AutoDownloader) start() {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/start", func() {})
+
+	for {
+		ticker := time.NewTicker(ad.getInterval())
+		select {
+		case <-ad.settingsUpdatedCh:
+			break // Restart the loop
+		case <-ad.startCh:
+			if ad.isEnabled() {
+				ad.checkForNewChapters()
+			}
+		case <-ticker.C:
+			if ad.isEnabled() {
+				ad.checkForNewChapters()
+			}
+		}
+		ticker.Stop()
+	}
+}
+
+func (ad *AutoDownloader) checkForNewChapters() {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/checkForNewChapters", func() {})
+
+	ad.mu.Lock()
+	if ad.mangaCollection.IsAbsent() {
+		ad.mu.Unlock()
+		ad.logger.Warn().Msg("manga autodownloader: Could not check for new chapters, manga collection not loaded")
+		return
+	}
+	collection := ad.mangaCollection.MustGet()
+	ad.mu.Unlock()
+
+	rules, err := db_bridge.GetMangaAutoDownloaderRules(ad.database)
+	if err != nil {
+		ad.logger.Error().Err(err).Msg("manga autodownloader: Failed to fetch rules from the database")
+		return
+	}
+
+	// Filter out disabled rules
+	rules = slices.DeleteFunc(rules, func(rule *manga.AutoDownloaderRule) bool {
+		return !rule.Enabled || rule.Provider == ""
+	})
+
+	// Event
+	event := &MangaAutoDownloaderRunStartedEvent{
+		Rules: rules,
+	}
+	_ = hook.GlobalHookManager.OnMangaAutoDownloaderRunStarted().Trigger(event)
+	rules = event.Rules
+
+	// Default prevented, return
+	if event.DefaultPrevented {
+		return
+	}
+
+	if len(rules) == 0 {
+		ad.logger.Debug().Msg("manga autodownloader: No rules found")
+		return
+	}
+
+	ad.logger.Debug().Int("rules", len(rules)).Msg("manga autodownloader: Checking for new chapters")
+
+	queued := 0
+	for _, rule := range rules {
+		queued += ad.checkRule(rule, collection)
+	}
+
+	if queued > 0 {
+		ad.downloader.RunChapterDownloadQueue()
+
+		ad.wsEventManager.SendEvent(events.InvalidateQueries, []string{events.GetMangaDownloadQueueEndpoint, events.GetMangaDownloadDataEndpoint})
+
+		notifier.GlobalNotifier.Notify(
+			notifier.MangaAutoDownloader,
+			fmt.Sprintf("%d %s %s been added to the download queue.", queued, util.Pluralize(queued, "chapter", "chapters"), util.Pluralize(queued, "has", "have")),
+		)
+	}
+}
+
+// checkRule fetches the chapters of the rule's media and queues the new ones.
+// It returns the number of queued chapters.
+func (ad *AutoDownloader) checkRule(rule *manga.AutoDownloaderRule, collection *anilist.MangaCollection) (queued int) {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/checkRule", func() {})
+
+	listEntry, found := collection.GetListEntryFromMangaId(rule.MediaId)
+	if !found || listEntry.GetMedia() == nil {
+		return 0
+	}
+
+	// Fetch the latest chapters from the provider
+	container, err := ad.repository.GetMangaChapterContainer(&manga.GetMangaChapterContainerOptions{
+		Provider:    rule.Provider,
+		MediaId:     rule.MediaId,
+		Titles:      listEntry.GetMedia().GetAllTitles(),
+		Year:        listEntry.GetMedia().GetStartYearSafe(),
+		BypassCache: true,
+	})
+	if err != nil {
+		ad.logger.Error().Err(err).Int("mediaId", rule.MediaId).Str("provider", rule.Provider).Msg("manga autodownloader: Failed to fetch chapters")
+		return 0
+	}
+
+	// Get downloaded and queued chapters
+	downloadData, err := ad.downloader.GetMediaDownloads(rule.MediaId, false)
+	if err != nil {
+		ad.logger.Error().Err(err).Int("mediaId", rule.MediaId).Msg("manga autodownloader: Failed to get downloaded chapters")
+		return 0
+	}
+	existing := getExistingChapterNumbers(downloadData)
+
+	progress := 0
+	if listEntry.GetProgress() != nil {
+		progress = *listEntry.GetProgress()
+	}
+
+	chapters := selectChapters(rule, container.Chapters, progress, existing)
+
+	for _, chapter := range chapters {
+		matchEvent := &MangaAutoDownloaderMatchVerifiedEvent{
+			Chapter:    chapter,
+			Rule:       rule,
+			ListEntry:  listEntry,
+			MatchFound: true,
+		}
+		_ = hook.GlobalHookManager.OnMangaAutoDownloaderMatchVerified().Trigger(matchEvent)
+		if matchEvent.DefaultPrevented || !matchEvent.MatchFound || matchEvent.Chapter == nil {
+			continue
+		}
+		chapter = matchEvent.Chapter
+
+		if queued >= maxChaptersPerRun {
+			break
+		}
+
+		if ok := ad.downloadChapter(chapter, rule); ok {
+			queued++
+		}
+		time.Sleep(delayBetweenChapters)
+	}
+
+	return queued
+}
+
+func (ad *AutoDownloader) downloadChapter(chapter *hibikemanga.ChapterDetails, rule *manga.AutoDownloaderRule) bool {
+	defer util.HandlePanicInModuleThen("manga/autodownloader/downloadChapter", func() {})
+
+	// Event
+	beforeEvent := &MangaAutoDownloaderBeforeDownloadChapterEvent{
+		Chapter: chapter,
+		Rule:    rule,
+	}
+	_ = hook.GlobalHookManager.OnMangaAutoDownloaderBeforeDownloadChapter().Trigger(beforeEvent)
+	if beforeEvent.DefaultPrevented {
+		return false
+	}
+
+	err := ad.downloader.DownloadChapter(manga.DownloadChapterOptions{
+		Provider:  rule.Provider,
+		MediaId:   rule.MediaId,
+		ChapterId: chapter.ID,
+	})
+	if err != nil {
+		ad.logger.Error().Err(err).Int("mediaId", rule.MediaId).Str("chapter", chapter.Chapter).Msg("manga autodownloader: Failed to queue chapter")
+		return false
+	}
+
+	ad.logger.Info().Int("mediaId", rule.MediaId).Str("chapter", chapter.Chapter).Msg("manga autodownloader: Queued chapter")
+
+	// Event
+	afterEvent := &MangaAutoDownloaderAfterDownloadChapterEvent{
+		Chapter: chapter,
+		Rule:    rule,
+	}
+	_ = hook.GlobalHookManager.OnMangaAutoDownloaderAfterDownloadChapter().Trigger(afterEvent)
+
+	return true
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// getExistingChapterNumbers returns the normalized numbers of downloaded and queued chapters, regardless of the provider.
+func getExistingChapterNumbers(data manga.MediaDownloadData) map[string]struct{} {
+	ret := make(map[string]struct{})
+	for _, providerMap := range []manga.ProviderDownloadMap{data.Downloaded, data.Queued} {
+		for _, chapters := range providerMap {
+			for _, ch := range chapters {
+				ret[manga_providers.GetNormalizedChapter(ch.ChapterNumber)] = struct{}{}
+			}
+		}
+	}
+	return ret
+}
+
+// selectChapters returns the chapters to download, sorted by chapter number.
+//
+//   - Chapters that have been read (number <= progress), downloaded or queued are skipped.
+//   - Chapters that don't match the rule's language are skipped.
+//   - If a chapter is available from multiple scanlators, the first preferred scanlator is used.
+func selectChapters(rule *manga.AutoDownloaderRule, chapters []*hibikemanga.ChapterDetails, progress int, existing map[string]struct{}) []*hibikemanga.ChapterDetails {
+	type candidate struct {
+		number  float64
+		chapter *hibikemanga.ChapterDetails
+		rank    int
+	}
+
+	candidates := make(map[string]*candidate)
+	for _, ch := range chapters {
+		if ch == nil || ch.ID == "" {
+			continue
+		}
+
+		if rule.Language != "" && ch.Language != "" && !strings.EqualFold(rule.Language, ch.Language) {
+			continue
+		}
+
+		normalized := manga_providers.GetNormalizedChapter(ch.Chapter)
+		number, err := strconv.ParseFloat(normalized, 64)
+		if err != nil {
+			continue
+		}
+		if number <= float64(progress) {
+			continue
+		}
+		if _, ok := existing[normalized]; ok {
+			continue
+		}
+
+		rank := getScanlatorRank(rule.Scanlators, ch.Scanlator)
+		if c, ok := candidates[normalized]; ok && c.rank <= rank {
+			continue
+		}
+		candidates[normalized] = &candidate{
+			number:  number,
+			chapter: ch,
+			rank:    rank,
+		}
+	}
+
+	sorted := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		sorted = append(sorted, c)
+	}
+	slices.SortFunc(sorted, func(a, b *candidate) int {
+		if a.number < b.number {
+			return -1
+		}
+		if a.number > b.number {
+			return 1
+		}
+		return 0
+	})
+
+	ret := make([]*hibikemanga.ChapterDetails, 0, len(sorted))
+	for _, c := range sorted {
+		ret = append(ret, c.chapter)
+	}
+	return ret
+}
+
+// getScanlatorRank returns the index of the scanlator in the preferred list.
+// Scanlators that are not in the list are ranked last.
+func getScanlatorRank(preferred []string, scanlator string) int {
+	for i, p := range preferred {
+		if strings.EqualFold(strings.TrimSpace(p), strings.TrimSpace(scanlator)) {
+			return i
+		}
+	}
+	return len(preferred)
+}
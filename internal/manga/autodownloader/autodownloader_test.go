@@ -0,0 +1,51 @@
+package autodownloader
+
+import (
+	"seanime/internal/manga"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	hibikemanga "seanime/internal/extension/hibike/manga"
+)
+
+func TestSelectChapters(t *testing.T) {
+	chapters := []*hibikemanga.ChapterDetails{
+		{ID: "9-a", Chapter: "9", Scanlator: "GroupA", Language: "en"},
+		{ID: "10-a", Chapter: "10", Scanlator: "GroupA", Language: "en"},
+		{ID: "11-b", Chapter: "11", Scanlator: "GroupB", Language: "en"},
+		{ID: "11-a", Chapter: "11", Scanlator: "GroupA", Language: "en"},
+		{ID: "12-c", Chapter: "12", Scanlator: "GroupC", Language: "en"}, // Fallback scanlator
+		{ID: "12.5-b", Chapter: "12.5", Scanlator: "GroupB", Language: "en"},
+		{ID: "13-a", Chapter: "13", Scanlator: "GroupA", Language: "en"}, // Already downloaded
+		{ID: "14-a-fr", Chapter: "14", Scanlator: "GroupA", Language: "fr"},
+		{ID: "14-b", Chapter: "14", Scanlator: "GroupB", Language: "en"},
+		{ID: "15-x", Chapter: "Chapter ???", Scanlator: "GroupA", Language: "en"}, // Invalid number
+	}
+
+	rule := &manga.AutoDownloaderRule{
+		Enabled:    true,
+		MediaId:    1,
+		Provider:   "provider",
+		Scanlators: []string{"GroupA", "GroupB"},
+		Language:   "en",
+	}
+
+	existing := map[string]struct{}{
+		"13": {},
+	}
+
+	selected := selectChapters(rule, chapters, 9, existing)
+
+	ids := lo.Map(selected, func(ch *hibikemanga.ChapterDetails, _ int) string { return ch.ID })
+	assert.Equal(t, []string{"10-a", "11-a", "12-c", "12.5-b", "14-b"}, ids)
+
+	// No language filter, no preferred scanlators
+	rule.Language = ""
+	rule.Scanlators = nil
+	selected = selectChapters(rule, chapters, 13, existing)
+
+	ids = lo.Map(selected, func(ch *hibikemanga.ChapterDetails, _ int) string { return ch.ID })
+	assert.Equal(t, []string{"14-a-fr"}, ids)
+}
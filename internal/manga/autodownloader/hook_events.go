@@ -0,0 +1,43 @@
+package autodownloader
+
+import (
+	"seanime/internal/api/anilist"
+	"seanime/internal/hook_resolver"
+	"seanime/internal/manga"
+
+	hibikemanga "seanime/internal/extension/hibike/manga"
+)
+
+// MangaAutoDownloaderRunStartedEvent is triggered when the manga autodownloader starts checking for new chapters.
+// Prevent default to abort the run.
+type MangaAutoDownloaderRunStartedEvent struct {
+	hook_resolver.Event
+	Rules []*manga.AutoDownloaderRule `json:"rules"`
+}
+
+// MangaAutoDownloaderMatchVerifiedEvent is triggered when a chapter is verified to follow a rule.
+// Prevent default to skip the chapter.
+type MangaAutoDownloaderMatchVerifiedEvent struct {
+	hook_resolver.Event
+	Chapter   *hibikemanga.ChapterDetails `json:"chapter"`
+	Rule      *manga.AutoDownloaderRule   `json:"rule"`
+	ListEntry *anilist.MangaListEntry     `json:"listEntry"`
+	// Whether the chapter matches the rule
+	// Changing this value to true will trigger a download even if the match failed;
+	MatchFound bool `json:"matchFound"`
+}
+
+// MangaAutoDownloaderBeforeDownloadChapterEvent is triggered when the manga autodownloader is about to queue a chapter.
+// Prevent default to abort the download.
+type MangaAutoDownloaderBeforeDownloadChapterEvent struct {
+	hook_resolver.Event
+	Chapter *hibikemanga.ChapterDetails `json:"chapter"`
+	Rule    *manga.AutoDownloaderRule   `json:"rule"`
+}
+
+// MangaAutoDownloaderAfterDownloadChapterEvent is triggered when the manga autodownloader has queued a chapter.
+type MangaAutoDownloaderAfterDownloadChapterEvent struct {
+	hook_resolver.Event
+	Chapter *hibikemanga.ChapterDetails `json:"chapter"`
+	Rule    *manga.AutoDownloaderRule   `json:"rule"`
+}
@@ -43,6 +43,8 @@ type GetMangaChapterContainerOptions struct {
 	MediaId  int
 	Titles   []*string
 	Year     int
+	// BypassCache will refetch the chapters from the provider and update the cache.
+	BypassCache bool
 }
 
 // GetMangaChapterContainer returns the ChapterContainer for a manga entry based on the provider.
@@ -99,7 +101,7 @@ func (r *Repository) GetMangaChapterContainer(opts *GetMangaChapterContainerOpti
 	containerBucket := r.getFcProviderBucket(provider, mediaId, bucketTypeChapter)
 
 	// Check if the container is in the cache
-	if found, _ := r.fileCacher.Get(containerBucket, chapterContainerKey, &container); found {
+	if found, _ := r.fileCacher.Get(containerBucket, chapterContainerKey, &container); found && !opts.BypassCache {
 		r.logger.Info().Str("bucket", containerBucket.Name()).Msg("manga: Chapter Container Cache HIT")
 
 		// Trigger hook event
@@ -0,0 +1,241 @@
+package dlna
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type (
+	// PositionInfo is the result of the GetPositionInfo action.
+	PositionInfo struct {
+		TrackURI string
+		Position float64 // In seconds
+		Duration float64 // In seconds
+	}
+
+	soapEnvelope struct {
+		Body soapBody `xml:"Body"`
+	}
+
+	soapBody struct {
+		Content []byte     `xml:",innerxml"`
+		Fault   *soapFault `xml:"Fault"`
+	}
+
+	soapFault struct {
+		FaultString string `xml:"faultstring"`
+		Detail      struct {
+			UPnPError struct {
+				ErrorCode        int    `xml:"errorCode"`
+				ErrorDescription string `xml:"errorDescription"`
+			} `xml:"UPnPError"`
+		} `xml:"detail"`
+	}
+
+	// soapArg is an argument of an action, arguments must be sent in the order of the specification.
+	soapArg struct {
+		Name  string
+		Value string
+	}
+)
+
+// Transport states, see CurrentTransportState.
+const (
+	StateStopped        = "STOPPED"
+	StatePlaying        = "PLAYING"
+	StatePaused         = "PAUSED_PLAYBACK"
+	StateTransitioning  = "TRANSITIONING"
+	StateNoMediaPresent = "NO_MEDIA_PRESENT"
+)
+
+// call invokes an action of the AVTransport service and returns its output arguments.
+func (r *Renderer) call(action string, args ...soapArg) (map[string]string, error) {
+	device, err := r.Device()
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, action, AVTransportServiceType)
+	body.WriteString(`<InstanceID>0</InstanceID>`)
+	for _, arg := range args {
+		fmt.Fprintf(&body, "<%s>", arg.Name)
+		_ = xml.EscapeText(&body, []byte(arg.Value))
+		fmt.Fprintf(&body, "</%s>", arg.Name)
+	}
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	req, err := http.NewRequest(http.MethodPost, device.AVTransportURL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, AVTransportServiceType, action))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope soapEnvelope
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("dlna: invalid response to %s, status %d", action, resp.StatusCode)
+	}
+
+	if envelope.Body.Fault != nil {
+		upnpErr := envelope.Body.Fault.Detail.UPnPError
+		if upnpErr.ErrorDescription != "" {
+			return nil, fmt.Errorf("dlna: %s failed, %s (%d)", action, upnpErr.ErrorDescription, upnpErr.ErrorCode)
+		}
+		return nil, fmt.Errorf("dlna: %s failed, %s", action, envelope.Body.Fault.FaultString)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dlna: %s failed, status %d", action, resp.StatusCode)
+	}
+
+	return parseArguments(envelope.Body.Content)
+}
+
+// parseArguments returns the child elements of the action response.
+func parseArguments(content []byte) (map[string]string, error) {
+	var response struct {
+		Args []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	}
+	if err := xml.Unmarshal(content, &response); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]string, len(response.Args))
+	for _, arg := range response.Args {
+		ret[arg.XMLName.Local] = arg.Value
+	}
+	return ret, nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// SetAVTransportURI loads the media at uri, with title shown by the renderer.
+func (r *Renderer) SetAVTransportURI(uri string, title string) error {
+	_, err := r.call("SetAVTransportURI",
+		soapArg{"CurrentURI", uri},
+		soapArg{"CurrentURIMetaData", didlMetadata(uri, title)},
+	)
+	return err
+}
+
+func (r *Renderer) Play() error {
+	_, err := r.call("Play", soapArg{"Speed", "1"})
+	return err
+}
+
+func (r *Renderer) Pause() error {
+	_, err := r.call("Pause")
+	return err
+}
+
+func (r *Renderer) Stop() error {
+	_, err := r.call("Stop")
+	return err
+}
+
+// Seek seeks to the position, in seconds.
+func (r *Renderer) Seek(seconds float64) error {
+	_, err := r.call("Seek", soapArg{"Unit", "REL_TIME"}, soapArg{"Target", formatDuration(seconds)})
+	return err
+}
+
+func (r *Renderer) GetPositionInfo() (*PositionInfo, error) {
+	res, err := r.call("GetPositionInfo")
+	if err != nil {
+		return nil, err
+	}
+	return &PositionInfo{
+		TrackURI: res["TrackURI"],
+		Position: parseDuration(res["RelTime"]),
+		Duration: parseDuration(res["TrackDuration"]),
+	}, nil
+}
+
+// GetTransportState returns the CurrentTransportState, e.g. StatePlaying.
+func (r *Renderer) GetTransportState() (string, error) {
+	res, err := r.call("GetTransportInfo")
+	if err != nil {
+		return "", err
+	}
+	return res["CurrentTransportState"], nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// didlMetadata returns the DIDL-Lite description of the media, some renderers refuse media without it.
+func didlMetadata(uri string, title string) string {
+	name := strings.SplitN(uri, "?", 2)[0]
+	if localPath := LocalPath(uri); localPath != "" {
+		name = localPath
+	}
+
+	mimeType := MimeType(name)
+
+	return `<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">` +
+		`<item id="0" parentID="-1" restricted="1">` +
+		`<dc:title>` + html.EscapeString(title) + `</dc:title>` +
+		`<upnp:class>object.item.videoItem</upnp:class>` +
+		`<res protocolInfo="http-get:*:` + mimeType + `:*">` + html.EscapeString(uri) + `</res>` +
+		`</item></DIDL-Lite>`
+}
+
+// formatDuration formats seconds as "H:MM:SS".
+func formatDuration(seconds float64) string {
+	s := int(max(seconds, 0))
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// parseDuration parses "H+:MM:SS[.F+]", it returns 0 for "NOT_IMPLEMENTED" or invalid values.
+func parseDuration(value string) float64 {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return 0
+	}
+	var ret float64
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		ret = ret*60 + v
+	}
+	return ret
+}
+
+// MimeType returns the video MIME type of the file, renderers use it to select the decoder.
+func MimeType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	switch ext {
+	case "":
+		return "video/mp4"
+	case ".mkv":
+		return "video/x-matroska"
+	}
+	if t := mime.TypeByExtension(ext); strings.HasPrefix(t, "video/") {
+		return t
+	}
+	return "video/mp4"
+}
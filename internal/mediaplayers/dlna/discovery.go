@@ -0,0 +1,108 @@
+package dlna
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ssdpAddr is the SSDP multicast address, replaced in tests.
+	ssdpAddr = "239.255.255.250:1900"
+)
+
+// Discover searches the local network for MediaRenderers that can be controlled.
+// Devices that do not answer before the timeout or do not have an AVTransport service are ignored.
+func Discover(ctx context.Context, timeout time.Duration) ([]*Device, error) {
+	addr, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	mx := max(int(timeout.Seconds()), 1)
+	search := fmt.Sprintf("M-SEARCH * HTTP/1.1\r\n"+
+		"HOST: 239.255.255.250:1900\r\n"+
+		"MAN: \"ssdp:discover\"\r\n"+
+		"MX: %d\r\n"+
+		"ST: %s\r\n\r\n", mx, MediaRendererDeviceType)
+
+	// UDP is unreliable, the request is sent twice
+	for i := 0; i < 2; i++ {
+		if _, err := conn.WriteToUDP([]byte(search), addr); err != nil {
+			return nil, err
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+
+	locations := make([]string, 0)
+	seen := make(map[string]struct{})
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			// The deadline has been reached
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		location := resp.Header.Get("Location")
+		_ = resp.Body.Close()
+		if location == "" {
+			continue
+		}
+		if _, ok := seen[location]; ok {
+			continue
+		}
+		seen[location] = struct{}{}
+		locations = append(locations, location)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	devices := make([]*Device, 0, len(locations))
+	for _, location := range locations {
+		wg.Add(1)
+		go func(location string) {
+			defer wg.Done()
+			device, err := fetchDevice(client, location)
+			if err != nil || device.AVTransportURL == "" {
+				return
+			}
+			mu.Lock()
+			devices = append(devices, device)
+			mu.Unlock()
+		}(location)
+	}
+	wg.Wait()
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].FriendlyName < devices[j].FriendlyName
+	})
+
+	return devices, nil
+}
@@ -0,0 +1,266 @@
+package dlna
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"seanime/internal/util"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// https://upnp.org/specs/av/UPnP-av-AVTransport-v1-Service.pdf
+
+const (
+	MediaRendererDeviceType = "urn:schemas-upnp-org:device:MediaRenderer:1"
+	AVTransportServiceType  = "urn:schemas-upnp-org:service:AVTransport:1"
+)
+
+// fileRoute serves local files, the path is base64 encoded
+const fileRoute = "/api/v1/mediastream/file/"
+
+var ErrNoAVTransport = errors.New("dlna: the device does not have an AVTransport service")
+
+type (
+	// Renderer controls a UPnP MediaRenderer, e.g. a smart TV, through its AVTransport service.
+	// The renderer fetches the media from Seanime, so local URLs are rewritten to an address it can reach.
+	Renderer struct {
+		Location      string // URL of the device description, found by Discover
+		ServerAddress string // Address of Seanime as seen by the renderer, e.g. "192.168.1.10:43211", detected if empty
+		ServerPort    int    // Port of Seanime, used when the address is detected
+		Logger        *zerolog.Logger
+
+		client    *http.Client
+		initOnce  sync.Once
+		mu        sync.Mutex
+		device    *Device
+		stoppedCh chan struct{} // Receives a value when the playback stops
+		active    bool          // Whether the renderer was playing or paused during the last status update
+	}
+
+	// Device is a UPnP device, as described by its device description.
+	Device struct {
+		FriendlyName   string `json:"friendlyName"`
+		Manufacturer   string `json:"manufacturer"`
+		ModelName      string `json:"modelName"`
+		UDN            string `json:"udn"`
+		Location       string `json:"location"`
+		AVTransportURL string `json:"-"` // Control URL of the AVTransport service
+	}
+
+	deviceDescription struct {
+		URLBase string            `xml:"URLBase"`
+		Device  deviceDescElement `xml:"device"`
+	}
+
+	deviceDescElement struct {
+		DeviceType   string              `xml:"deviceType"`
+		FriendlyName string              `xml:"friendlyName"`
+		Manufacturer string              `xml:"manufacturer"`
+		ModelName    string              `xml:"modelName"`
+		UDN          string              `xml:"UDN"`
+		Services     []serviceElement    `xml:"serviceList>service"`
+		Devices      []deviceDescElement `xml:"deviceList>device"`
+	}
+
+	serviceElement struct {
+		ServiceType string `xml:"serviceType"`
+		ControlURL  string `xml:"controlURL"`
+	}
+)
+
+func (r *Renderer) init() {
+	r.initOnce.Do(func() {
+		r.client = &http.Client{Timeout: 10 * time.Second}
+		r.stoppedCh = make(chan struct{}, 1)
+	})
+}
+
+// Device returns the description of the renderer, fetched once.
+func (r *Renderer) Device() (*Device, error) {
+	r.init()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.device != nil {
+		return r.device, nil
+	}
+
+	if r.Location == "" {
+		return nil, errors.New("dlna: no renderer selected")
+	}
+
+	device, err := fetchDevice(r.client, r.Location)
+	if err != nil {
+		return nil, err
+	}
+	if device.AVTransportURL == "" {
+		return nil, ErrNoAVTransport
+	}
+	r.device = device
+	return device, nil
+}
+
+// fetchDevice fetches and parses the device description at location.
+func fetchDevice(client *http.Client, location string) (*Device, error) {
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dlna: could not fetch the device description, status %d", resp.StatusCode)
+	}
+
+	var desc deviceDescription
+	if err := xml.NewDecoder(resp.Body).Decode(&desc); err != nil {
+		return nil, fmt.Errorf("dlna: invalid device description, %w", err)
+	}
+
+	base := location
+	if desc.URLBase != "" {
+		base = desc.URLBase
+	}
+
+	device := &Device{
+		FriendlyName: desc.Device.FriendlyName,
+		Manufacturer: desc.Device.Manufacturer,
+		ModelName:    desc.Device.ModelName,
+		UDN:          desc.Device.UDN,
+		Location:     location,
+	}
+
+	// The AVTransport service can be declared by an embedded device
+	if controlURL, found := findService(&desc.Device, AVTransportServiceType); found {
+		device.AVTransportURL, err = resolveURL(base, controlURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return device, nil
+}
+
+func findService(d *deviceDescElement, serviceType string) (string, bool) {
+	for _, s := range d.Services {
+		if s.ServiceType == serviceType {
+			return s.ControlURL, true
+		}
+	}
+	for i := range d.Devices {
+		if controlURL, found := findService(&d.Devices[i], serviceType); found {
+			return controlURL, true
+		}
+	}
+	return "", false
+}
+
+func resolveURL(base string, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ReachableURL rewrites URLs pointing to the loopback interface, e.g. torrent stream URLs,
+// so that the renderer can fetch them from Seanime.
+func (r *Renderer) ReachableURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	host := u.Hostname()
+	if host != "localhost" && host != "0.0.0.0" && !net.ParseIP(host).IsLoopback() {
+		return rawURL
+	}
+
+	address, err := r.serverAddress(u.Port())
+	if err != nil {
+		return rawURL
+	}
+	u.Host = address
+	return u.String()
+}
+
+// FileURL returns the URL of a local file served by Seanime.
+func (r *Renderer) FileURL(path string) (string, error) {
+	address, err := r.serverAddress("")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://%s%s%s", address, fileRoute, util.Base64EncodeStr(path)), nil
+}
+
+// LocalPath returns the path of the local file served at rawURL, or an empty string if it is not a local file.
+func LocalPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	encoded, found := strings.CutPrefix(u.EscapedPath(), fileRoute)
+	if !found {
+		return ""
+	}
+	ret, err := util.Base64DecodeStr(encoded)
+	if err != nil {
+		return ""
+	}
+	return ret
+}
+
+// serverAddress returns the address of Seanime as seen by the renderer.
+// If it is not set, the address of the interface used to reach the renderer is used.
+func (r *Renderer) serverAddress(port string) (string, error) {
+	if r.ServerAddress != "" {
+		address := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(r.ServerAddress, "http://"), "https://"), "/")
+		// URLs served on another port, e.g. by the separate streaming server, keep their port
+		if host, _, err := net.SplitHostPort(address); err == nil && port != "" && port != fmt.Sprintf("%d", r.ServerPort) {
+			return net.JoinHostPort(host, port), nil
+		}
+		return address, nil
+	}
+
+	device, err := r.Device()
+	if err != nil {
+		return "", err
+	}
+	location, err := url.Parse(device.Location)
+	if err != nil {
+		return "", err
+	}
+
+	// No packet is sent, this only selects the outbound interface
+	conn, err := net.Dial("udp", net.JoinHostPort(location.Hostname(), "1900"))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	ip := conn.LocalAddr().(*net.UDPAddr).IP.String()
+
+	if port == "" {
+		port = fmt.Sprintf("%d", r.ServerPort)
+	}
+	return net.JoinHostPort(ip, port), nil
+}
+
+// readAll reads at most 1 MB of the body.
+func readAll(body io.Reader) ([]byte, error) {
+	return io.ReadAll(io.LimitReader(body, 1<<20))
+}
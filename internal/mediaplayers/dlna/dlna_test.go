@@ -0,0 +1,260 @@
+package dlna
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRenderer implements the device description and the AVTransport actions used by the client.
+type fakeRenderer struct {
+	t        *testing.T
+	server   *httptest.Server
+	mu       sync.Mutex
+	uri      string
+	metadata string
+	state    string
+	position float64
+	duration float64
+	actions  []string
+}
+
+const fakeDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>Living Room TV</friendlyName>
+    <manufacturer>Fake</manufacturer>
+    <modelName>Renderer 1</modelName>
+    <UDN>uuid:fake-renderer</UDN>
+    <serviceList>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType>
+        <controlURL>/control/rendering</controlURL>
+      </service>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType>
+        <controlURL>control/avtransport</controlURL>
+      </service>
+    </serviceList>
+  </device>
+</root>`
+
+func newFakeRenderer(t *testing.T) (*fakeRenderer, *Renderer) {
+	f := &fakeRenderer{t: t, state: StateNoMediaPresent}
+	f.server = httptest.NewServer(f)
+	t.Cleanup(f.server.Close)
+
+	r := &Renderer{
+		Location:      f.server.URL + "/description.xml",
+		ServerAddress: "192.168.1.10:43211",
+		ServerPort:    43211,
+	}
+	return f, r
+}
+
+func (f *fakeRenderer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/description.xml":
+		_, _ = io.WriteString(w, fakeDescription)
+		return
+	case "/control/avtransport":
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	action := strings.TrimSuffix(strings.SplitN(r.Header.Get("SOAPAction"), "#", 2)[1], `"`)
+
+	var envelope struct {
+		Body struct {
+			Action struct {
+				Args []struct {
+					XMLName xml.Name
+					Value   string `xml:",chardata"`
+				} `xml:",any"`
+			} `xml:",any"`
+		} `xml:"Body"`
+	}
+	require.NoError(f.t, xml.NewDecoder(r.Body).Decode(&envelope))
+	args := make(map[string]string)
+	for _, arg := range envelope.Body.Action.Args {
+		args[arg.XMLName.Local] = arg.Value
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.actions = append(f.actions, action)
+
+	out := ""
+	switch action {
+	case "SetAVTransportURI":
+		f.uri = args["CurrentURI"]
+		f.metadata = args["CurrentURIMetaData"]
+		f.state = StateStopped
+		f.position = 0
+		f.duration = 1440
+	case "Play":
+		f.state = StatePlaying
+	case "Pause":
+		f.state = StatePaused
+	case "Stop":
+		f.state = StateStopped
+	case "Seek":
+		if f.state != StatePlaying && f.state != StatePaused {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>`+
+				`<faultstring>UPnPError</faultstring><detail><UPnPError xmlns="urn:schemas-upnp-org:control-1-0">`+
+				`<errorCode>701</errorCode><errorDescription>Transition not available</errorDescription></UPnPError></detail>`+
+				`</s:Fault></s:Body></s:Envelope>`)
+			return
+		}
+		f.position = parseDuration(args["Target"])
+	case "GetPositionInfo":
+		out = fmt.Sprintf("<Track>1</Track><TrackDuration>%s</TrackDuration><TrackURI>%s</TrackURI><RelTime>%s</RelTime>",
+			formatDuration(f.duration), xmlEscape(f.uri), formatDuration(f.position))
+	case "GetTransportInfo":
+		out = fmt.Sprintf("<CurrentTransportState>%s</CurrentTransportState><CurrentTransportStatus>OK</CurrentTransportStatus>", f.state)
+	}
+
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+		`<u:%sResponse xmlns:u="%s">%s</u:%sResponse></s:Body></s:Envelope>`, action, AVTransportServiceType, out, action)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func TestRenderer_Device(t *testing.T) {
+	f, r := newFakeRenderer(t)
+
+	device, err := r.Device()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Living Room TV", device.FriendlyName)
+	assert.Equal(t, "uuid:fake-renderer", device.UDN)
+	// The relative control URL is resolved against the location
+	assert.Equal(t, f.server.URL+"/control/avtransport", device.AVTransportURL)
+}
+
+func TestRenderer_Playback(t *testing.T) {
+	f, r := newFakeRenderer(t)
+	startPollInterval = 10 * time.Millisecond
+
+	uri, err := r.FileURL("/anime/Frieren/[SubsPlease] Frieren - 01 (1080p).mkv")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(uri, "http://192.168.1.10:43211/api/v1/mediastream/file/"))
+
+	require.NoError(t, r.Open(uri, "Frieren - Episode 1", 300))
+
+	f.mu.Lock()
+	assert.Equal(t, uri, f.uri)
+	assert.Contains(t, f.metadata, "<dc:title>Frieren - Episode 1</dc:title>")
+	assert.Contains(t, f.metadata, "video/x-matroska")
+	assert.Equal(t, []string{"GetTransportInfo", "SetAVTransportURI", "Play", "GetTransportInfo", "Seek"}, f.actions)
+	f.mu.Unlock()
+
+	status, err := r.GetStatus()
+	require.NoError(t, err)
+	assert.Equal(t, StatePlaying, status.State)
+	assert.Equal(t, 300.0, status.Position)
+	assert.Equal(t, 1440.0, status.Duration)
+	assert.Equal(t, "/anime/Frieren/[SubsPlease] Frieren - 01 (1080p).mkv", LocalPath(status.TrackURI))
+
+	require.NoError(t, r.Pause())
+	status, err = r.GetStatus()
+	require.NoError(t, err)
+	assert.Equal(t, StatePaused, status.State)
+
+	// Stopping the playback on the renderer notifies Stopped on the next status update
+	require.NoError(t, r.Stop())
+	select {
+	case <-r.Stopped():
+		t.Fatal("stopped before the status update")
+	default:
+	}
+	_, err = r.GetStatus()
+	require.NoError(t, err)
+	select {
+	case <-r.Stopped():
+	default:
+		t.Fatal("stop not notified")
+	}
+}
+
+func TestRenderer_SeekFault(t *testing.T) {
+	_, r := newFakeRenderer(t)
+
+	err := r.Seek(10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Transition not available (701)")
+}
+
+func TestRenderer_ReachableURL(t *testing.T) {
+	_, r := newFakeRenderer(t)
+
+	assert.Equal(t, "http://192.168.1.10:43211/api/v1/torrentstream/stream/abc/file.mkv",
+		r.ReachableURL("http://127.0.0.1:43211/api/v1/torrentstream/stream/abc/file.mkv"))
+	// The separate streaming server keeps its port
+	assert.Equal(t, "http://192.168.1.10:43214/stream/abc/file.mkv",
+		r.ReachableURL("http://localhost:43214/stream/abc/file.mkv"))
+	// Remote URLs, e.g. debrid streams, are not modified
+	assert.Equal(t, "https://debrid.example.com/dl/file.mkv", r.ReachableURL("https://debrid.example.com/dl/file.mkv"))
+
+	// Without a server address, the address of the interface used to reach the renderer is used
+	r.ServerAddress = ""
+	assert.Equal(t, "http://127.0.0.1:43211/file.mkv", r.ReachableURL("http://localhost:43211/file.mkv"))
+}
+
+func TestDiscover(t *testing.T) {
+	f, _ := newFakeRenderer(t)
+
+	// Fake SSDP responder
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	prevAddr := ssdpAddr
+	ssdpAddr = conn.LocalAddr().String()
+	t.Cleanup(func() { ssdpAddr = prevAddr })
+
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if !strings.Contains(string(buf[:n]), "ST: "+MediaRendererDeviceType) {
+				continue
+			}
+			_, _ = conn.WriteToUDP([]byte("HTTP/1.1 200 OK\r\n"+
+				"CACHE-CONTROL: max-age=1800\r\n"+
+				"LOCATION: "+f.server.URL+"/description.xml\r\n"+
+				"ST: "+MediaRendererDeviceType+"\r\n"+
+				"USN: uuid:fake-renderer::"+MediaRendererDeviceType+"\r\n\r\n"), addr)
+		}
+	}()
+
+	devices, err := Discover(context.Background(), 500*time.Millisecond)
+	require.NoError(t, err)
+
+	// Both search requests are answered, the device is listed once
+	require.Len(t, devices, 1)
+	assert.Equal(t, "Living Room TV", devices[0].FriendlyName)
+	assert.Equal(t, f.server.URL+"/description.xml", devices[0].Location)
+}
@@ -0,0 +1,123 @@
+package dlna
+
+import (
+	"time"
+)
+
+type (
+	// Status is the playback status of the renderer.
+	Status struct {
+		State    string  // CurrentTransportState
+		TrackURI string  // URL of the media being played
+		Position float64 // In seconds
+		Duration float64 // In seconds
+	}
+)
+
+var (
+	// startPollInterval and startPollMaxTries control how long Open waits for the renderer to start playing before seeking.
+	startPollInterval = 500 * time.Millisecond
+	startPollMaxTries = 20
+)
+
+// Open loads the media at uri and plays it from startTime, in seconds.
+func (r *Renderer) Open(uri string, title string, startTime float64) error {
+	r.init()
+
+	// Some renderers refuse to load a new media while playing
+	if state, err := r.GetTransportState(); err == nil && (state == StatePlaying || state == StatePaused) {
+		_ = r.Stop()
+	}
+
+	r.drainStopped()
+
+	if err := r.SetAVTransportURI(uri, title); err != nil {
+		return err
+	}
+	if err := r.Play(); err != nil {
+		return err
+	}
+
+	if startTime <= 0 {
+		return nil
+	}
+
+	// Renderers reject seeks until the media is loaded
+	for i := 0; i < startPollMaxTries; i++ {
+		state, err := r.GetTransportState()
+		if err == nil && (state == StatePlaying || state == StatePaused) {
+			break
+		}
+		time.Sleep(startPollInterval)
+	}
+
+	return r.Seek(startTime)
+}
+
+// Resume resumes the playback, Play is also used to resume a paused media.
+func (r *Renderer) Resume() error {
+	return r.Play()
+}
+
+// IsPlaying returns true if a media is loaded, even if it is paused.
+func (r *Renderer) IsPlaying() (bool, error) {
+	state, err := r.GetTransportState()
+	if err != nil {
+		return false, err
+	}
+	return state == StatePlaying || state == StatePaused || state == StateTransitioning, nil
+}
+
+// GetStatus returns the playback status.
+// It also notifies Stopped when the renderer goes from playing to stopped.
+func (r *Renderer) GetStatus() (*Status, error) {
+	state, err := r.GetTransportState()
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := r.GetPositionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	wasActive := r.active
+	r.active = state == StatePlaying || state == StatePaused || state == StateTransitioning
+	r.mu.Unlock()
+
+	if wasActive && (state == StateStopped || state == StateNoMediaPresent) {
+		r.notifyStopped()
+	}
+
+	return &Status{
+		State:    state,
+		TrackURI: info.TrackURI,
+		Position: info.Position,
+		Duration: info.Duration,
+	}, nil
+}
+
+// Stopped returns a channel that receives a value when the playback stops.
+// The renderer does not send notifications, the state is checked by GetStatus.
+func (r *Renderer) Stopped() <-chan struct{} {
+	r.init()
+	return r.stoppedCh
+}
+
+func (r *Renderer) notifyStopped() {
+	select {
+	case r.stoppedCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Renderer) drainStopped() {
+	r.mu.Lock()
+	r.active = false
+	r.mu.Unlock()
+	select {
+	case <-r.stoppedCh:
+	default:
+	}
+}
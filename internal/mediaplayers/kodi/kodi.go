@@ -0,0 +1,194 @@
+package kodi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+// https://kodi.wiki/view/JSON-RPC_API/v13
+
+const (
+	DefaultPort   = 8080
+	DefaultWsPort = 9090
+)
+
+var ErrNoActivePlayer = errors.New("kodi: nothing is playing")
+
+type (
+	// Kodi controls a Kodi instance through its JSON-RPC API.
+	// Requests are sent over HTTP and notifications are received over the websocket of the TCP interface.
+	// Kodi usually runs on another device, so paths are translated using PathMappings.
+	Kodi struct {
+		Host         string
+		Port         int // Port of the web server
+		WsPort       int // Port of the JSON-RPC notifications
+		Username     string
+		Password     string
+		PathMappings []PathMapping
+		Logger       *zerolog.Logger
+
+		client    *http.Client
+		requestId atomic.Int64
+
+		wsMu      sync.Mutex
+		wsConn    *websocket.Conn
+		stoppedCh chan struct{} // Receives a value when the playback stops or Kodi quits
+		initOnce  sync.Once
+	}
+
+	// PathMapping maps a local directory to the same directory as seen by Kodi, e.g. "/mnt/anime" to "smb://nas/anime".
+	PathMapping struct {
+		Local  string
+		Remote string
+	}
+
+	request struct {
+		JsonRPC string      `json:"jsonrpc"`
+		Method  string      `json:"method"`
+		Params  interface{} `json:"params,omitempty"`
+		Id      int64       `json:"id"`
+	}
+
+	response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *responseError  `json:"error"`
+	}
+
+	responseError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+func (k *Kodi) init() {
+	k.initOnce.Do(func() {
+		k.client = &http.Client{Timeout: 10 * time.Second}
+		k.stoppedCh = make(chan struct{}, 1)
+	})
+}
+
+func (k *Kodi) url() string {
+	port := k.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return fmt.Sprintf("http://%s:%d/jsonrpc", k.Host, port)
+}
+
+func (k *Kodi) wsUrl() string {
+	port := k.WsPort
+	if port == 0 {
+		port = DefaultWsPort
+	}
+	return fmt.Sprintf("ws://%s:%d/jsonrpc", k.Host, port)
+}
+
+// Call sends a JSON-RPC request and decodes the result into ret, which can be nil.
+func (k *Kodi) Call(method string, params interface{}, ret interface{}) error {
+	k.init()
+
+	body, err := json.Marshal(&request{
+		JsonRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		Id:      k.requestId.Add(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, k.url(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if k.Username != "" || k.Password != "" {
+		req.SetBasicAuth(k.Username, k.Password)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kodi: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("kodi: invalid username or password")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("kodi: http error code %d", resp.StatusCode)
+	}
+
+	var res response
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("kodi: invalid response, %w", err)
+	}
+	if res.Error != nil {
+		return fmt.Errorf("kodi: %s (%d)", res.Error.Message, res.Error.Code)
+	}
+	if ret != nil && len(res.Result) > 0 {
+		return json.Unmarshal(res.Result, ret)
+	}
+	return nil
+}
+
+// ToRemotePath returns the path of a local file as seen by Kodi.
+func (k *Kodi) ToRemotePath(localPath string) string {
+	normalized := strings.ReplaceAll(localPath, "\\", "/")
+	for _, mapping := range k.PathMappings {
+		local := strings.TrimSuffix(strings.ReplaceAll(mapping.Local, "\\", "/"), "/")
+		if local != "" && (strings.EqualFold(normalized, local) || hasPrefixFold(normalized, local+"/")) {
+			return strings.TrimSuffix(mapping.Remote, "/") + normalized[len(local):]
+		}
+	}
+	return localPath
+}
+
+// ToLocalPath returns the local path of a file played by Kodi.
+func (k *Kodi) ToLocalPath(remotePath string) string {
+	for _, mapping := range k.PathMappings {
+		remote := strings.TrimSuffix(mapping.Remote, "/")
+		if remote != "" && (remotePath == remote || strings.HasPrefix(remotePath, remote+"/")) {
+			return strings.TrimSuffix(mapping.Local, "/") + remotePath[len(remote):]
+		}
+	}
+	return remotePath
+}
+
+// ParsePathMappings parses one "local=remote" mapping per line.
+func ParsePathMappings(value string) []PathMapping {
+	ret := make([]PathMapping, 0)
+	for _, line := range strings.Split(value, "\n") {
+		local, remote, found := strings.Cut(line, "=")
+		local, remote = strings.TrimSpace(local), strings.TrimSpace(remote)
+		if !found || local == "" || remote == "" {
+			continue
+		}
+		ret = append(ret, PathMapping{Local: local, Remote: remote})
+	}
+	return ret
+}
+
+func hasPrefixFold(s string, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// baseName returns the name of a file from a local path, a network path or a URL.
+func baseName(file string) string {
+	file = strings.ReplaceAll(file, "\\", "/")
+	if i := strings.IndexAny(file, "?#"); i >= 0 && strings.Contains(file, "://") {
+		file = file[:i]
+	}
+	return path.Base(file)
+}
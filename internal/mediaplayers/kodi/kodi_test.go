@@ -0,0 +1,249 @@
+package kodi
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKodi implements the parts of the JSON-RPC API used by the client.
+type fakeKodi struct {
+	t        *testing.T
+	mu       sync.Mutex
+	file     string
+	position float64
+	duration float64
+	speed    int
+	calls    []string
+
+	wsMu    sync.Mutex
+	wsConns []*websocket.Conn
+}
+
+func newFakeKodi(t *testing.T) (*fakeKodi, *Kodi) {
+	f := &fakeKodi{t: t}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, _ := strconv.Atoi(portStr)
+
+	k := &Kodi{
+		Host:     host,
+		Port:     port,
+		WsPort:   port,
+		Username: "kodi",
+		Password: "secret",
+	}
+	return f, k
+}
+
+func (f *fakeKodi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, pass, ok := r.BasicAuth(); !ok || user != "kodi" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(f.t, err)
+		f.wsMu.Lock()
+		f.wsConns = append(f.wsConns, conn)
+		f.wsMu.Unlock()
+		return
+	}
+
+	var req struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+		Id     int64           `json:"id"`
+	}
+	require.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, req.Method)
+
+	var result interface{} = "OK"
+	switch req.Method {
+	case "JSONRPC.Ping":
+		result = "pong"
+	case "Player.Open":
+		var params struct {
+			Item struct {
+				File string `json:"file"`
+			} `json:"item"`
+			Options struct {
+				Resume globalTime `json:"resume"`
+			} `json:"options"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		f.file, f.position, f.duration, f.speed = params.Item.File, params.Options.Resume.toSeconds(), 1440, 1
+	case "Player.GetActivePlayers":
+		if f.file == "" {
+			result = []interface{}{}
+		} else {
+			result = []interface{}{map[string]interface{}{"playerid": 1, "type": "video"}}
+		}
+	case "Player.GetProperties":
+		result = map[string]interface{}{
+			"time":       newGlobalTime(f.position),
+			"totaltime":  newGlobalTime(f.duration),
+			"percentage": f.position / f.duration * 100,
+			"speed":      f.speed,
+		}
+	case "Player.GetItem":
+		result = map[string]interface{}{"item": map[string]interface{}{"file": f.file, "label": "Episode", "type": "movie"}}
+	case "Player.PlayPause":
+		var params struct {
+			Play bool `json:"play"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		f.speed = 0
+		if params.Play {
+			f.speed = 1
+		}
+	case "Player.Seek":
+		var params struct {
+			Value struct {
+				Time globalTime `json:"time"`
+			} `json:"value"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		f.position = params.Value.Time.toSeconds()
+	case "Player.Stop":
+		f.file = ""
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0", "id": req.Id,
+			"error": map[string]interface{}{"code": -32601, "message": "Method not found."},
+		})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": result})
+}
+
+func (f *fakeKodi) notify(method string) {
+	f.wsMu.Lock()
+	defer f.wsMu.Unlock()
+	for _, conn := range f.wsConns {
+		_ = conn.WriteJSON(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  method,
+			"params":  map[string]interface{}{"sender": "xbmc", "data": map[string]interface{}{}},
+		})
+	}
+}
+
+func (f *fakeKodi) wsConnCount() int {
+	f.wsMu.Lock()
+	defer f.wsMu.Unlock()
+	return len(f.wsConns)
+}
+
+func TestKodi_Playback(t *testing.T) {
+	f, k := newFakeKodi(t)
+	k.PathMappings = []PathMapping{{Local: "/mnt/anime", Remote: "smb://nas/anime"}}
+
+	require.NoError(t, k.Ping())
+
+	_, err := k.GetStatus()
+	assert.ErrorIs(t, err, ErrNoActivePlayer)
+
+	require.NoError(t, k.Open("/mnt/anime/Show/Show - 01.mkv", 90))
+	assert.Equal(t, "smb://nas/anime/Show/Show - 01.mkv", f.file)
+	assert.Equal(t, 90.0, f.position)
+
+	require.NoError(t, k.Seek(754.5))
+	require.NoError(t, k.Pause())
+
+	status, err := k.GetStatus()
+	require.NoError(t, err)
+	assert.Equal(t, "smb://nas/anime/Show/Show - 01.mkv", status.File)
+	assert.Equal(t, "Show - 01.mkv", status.Filename)
+	assert.Equal(t, "Episode", status.Title)
+	assert.InDelta(t, 754.5, status.Position, 0.001)
+	assert.Equal(t, 1440.0, status.Duration)
+	assert.False(t, status.Playing)
+
+	require.NoError(t, k.Resume())
+	status, err = k.GetStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Playing)
+
+	require.NoError(t, k.Stop())
+	assert.False(t, k.IsPlaying())
+
+	err = k.Call("Unknown.Method", nil, nil)
+	assert.ErrorContains(t, err, "Method not found.")
+}
+
+func TestKodi_InvalidCredentials(t *testing.T) {
+	_, k := newFakeKodi(t)
+	k.Password = "wrong"
+
+	assert.ErrorContains(t, k.Ping(), "invalid username or password")
+}
+
+func TestKodi_Notifications(t *testing.T) {
+	stopCheckDelay = 10 * time.Millisecond
+	defer func() { stopCheckDelay = time.Second }()
+
+	f, k := newFakeKodi(t)
+
+	require.NoError(t, k.Listen())
+	defer k.Close()
+	require.Eventually(t, func() bool { return f.wsConnCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	// A file replaced by another one is not a stop
+	require.NoError(t, k.Open("/anime/01.mkv", 0))
+	f.notify("Player.OnStop")
+	select {
+	case <-k.Stopped():
+		t.Fatal("unexpected stop notification")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, k.Stop())
+	f.notify("Player.OnStop")
+	select {
+	case <-k.Stopped():
+	case <-time.After(time.Second):
+		t.Fatal("expected stop notification")
+	}
+
+	f.notify("System.OnQuit")
+	select {
+	case <-k.Stopped():
+	case <-time.After(time.Second):
+		t.Fatal("expected quit notification")
+	}
+}
+
+func TestPathMappings(t *testing.T) {
+	k := &Kodi{PathMappings: ParsePathMappings("D:\\Anime = smb://nas/anime/\n\ninvalid\n/mnt/media=nfs://nas/media")}
+	require.Len(t, k.PathMappings, 2)
+
+	assert.Equal(t, "smb://nas/anime/Show/01.mkv", k.ToRemotePath("D:\\Anime\\Show\\01.mkv"))
+	assert.Equal(t, "smb://nas/anime/Show/01.mkv", k.ToRemotePath("d:/anime/Show/01.mkv"))
+	assert.Equal(t, "nfs://nas/media/01.mkv", k.ToRemotePath("/mnt/media/01.mkv"))
+	assert.Equal(t, "/mnt/mediaserver/01.mkv", k.ToRemotePath("/mnt/mediaserver/01.mkv"))
+
+	assert.Equal(t, "/mnt/media/01.mkv", k.ToLocalPath("nfs://nas/media/01.mkv"))
+	assert.Equal(t, "http://host/stream", k.ToLocalPath("http://host/stream"))
+
+	assert.Equal(t, "01.mkv", baseName("smb://nas/anime/01.mkv"))
+	assert.Equal(t, "01.mkv", baseName("C:\\Anime\\01.mkv"))
+	assert.Equal(t, "stream.mkv", baseName("http://host/stream.mkv?token=a/b"))
+}
@@ -0,0 +1,118 @@
+package kodi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type notification struct {
+	Method string `json:"method"`
+}
+
+// Stopped returns a channel that receives a value when the playback stops or Kodi quits.
+// Call Listen to receive the notifications.
+func (k *Kodi) Stopped() <-chan struct{} {
+	k.init()
+	return k.stoppedCh
+}
+
+// Listen connects to the notifications of Kodi, if it is not already connected.
+func (k *Kodi) Listen() error {
+	k.init()
+
+	k.wsMu.Lock()
+	defer k.wsMu.Unlock()
+
+	if k.wsConn != nil {
+		return nil
+	}
+
+	header := http.Header{}
+	if k.Username != "" || k.Password != "" {
+		req, _ := http.NewRequest(http.MethodGet, k.wsUrl(), nil)
+		req.SetBasicAuth(k.Username, k.Password)
+		header.Set("Authorization", req.Header.Get("Authorization"))
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: 5 * time.Second}
+	conn, _, err := dialer.Dial(k.wsUrl(), header)
+	if err != nil {
+		return err
+	}
+	k.wsConn = conn
+
+	go k.readNotifications(conn)
+	return nil
+}
+
+// Close closes the connection to the notifications.
+func (k *Kodi) Close() {
+	k.wsMu.Lock()
+	defer k.wsMu.Unlock()
+	if k.wsConn != nil {
+		_ = k.wsConn.Close()
+		k.wsConn = nil
+	}
+}
+
+func (k *Kodi) readNotifications(conn *websocket.Conn) {
+	defer func() {
+		k.wsMu.Lock()
+		if k.wsConn == conn {
+			k.wsConn = nil
+		}
+		k.wsMu.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if k.Logger != nil {
+				k.Logger.Debug().Err(err).Msg("kodi: Notification connection closed")
+			}
+			return
+		}
+
+		var n notification
+		if err := json.Unmarshal(data, &n); err != nil || n.Method == "" {
+			continue
+		}
+
+		switch n.Method {
+		case "Player.OnStop":
+			// Kodi sends this when a file replaces another one,
+			// so the playback is only considered stopped if no player is active shortly after
+			go func() {
+				time.Sleep(stopCheckDelay)
+				if !k.IsPlaying() {
+					k.notifyStopped()
+				}
+			}()
+		case "System.OnQuit", "System.OnSleep":
+			k.notifyStopped()
+		}
+	}
+}
+
+// stopCheckDelay is the delay before checking if the playback stopped after a Player.OnStop notification.
+var stopCheckDelay = time.Second
+
+func (k *Kodi) notifyStopped() {
+	select {
+	case k.stoppedCh <- struct{}{}:
+	default:
+	}
+}
+
+// drainStopped discards a pending notification, e.g. before opening a new file.
+func (k *Kodi) drainStopped() {
+	k.init()
+	select {
+	case <-k.stoppedCh:
+	default:
+	}
+}
@@ -0,0 +1,191 @@
+package kodi
+
+import (
+	"math"
+)
+
+type (
+	// Status is the playback status of the active video player.
+	Status struct {
+		File       string  // Path or URL of the file, as seen by Kodi
+		Filename   string  // Name of the file
+		Title      string  // Title of the item, can be empty
+		Position   float64 // In seconds
+		Duration   float64 // In seconds
+		Percentage float64 // From 0 to 100
+		Playing    bool
+	}
+
+	activePlayer struct {
+		PlayerId int    `json:"playerid"`
+		Type     string `json:"type"`
+	}
+
+	globalTime struct {
+		Hours        int `json:"hours"`
+		Minutes      int `json:"minutes"`
+		Seconds      int `json:"seconds"`
+		Milliseconds int `json:"milliseconds"`
+	}
+
+	playerProperties struct {
+		Time       globalTime `json:"time"`
+		TotalTime  globalTime `json:"totaltime"`
+		Percentage float64    `json:"percentage"`
+		Speed      int        `json:"speed"`
+	}
+
+	playerItem struct {
+		Item struct {
+			File  string `json:"file"`
+			Label string `json:"label"`
+			Title string `json:"title"`
+		} `json:"item"`
+	}
+)
+
+func (t globalTime) toSeconds() float64 {
+	return float64(t.Hours*3600+t.Minutes*60+t.Seconds) + float64(t.Milliseconds)/1000
+}
+
+func newGlobalTime(seconds float64) globalTime {
+	ms := int(math.Round(max(seconds, 0) * 1000))
+	return globalTime{
+		Hours:        ms / 3600000,
+		Minutes:      ms / 60000 % 60,
+		Seconds:      ms / 1000 % 60,
+		Milliseconds: ms % 1000,
+	}
+}
+
+// Ping checks that Kodi can be reached.
+func (k *Kodi) Ping() error {
+	return k.Call("JSONRPC.Ping", nil, nil)
+}
+
+// Open plays a file or a URL from the start time, in seconds. Local paths are translated using the path mappings.
+func (k *Kodi) Open(file string, startTime float64) error {
+	k.drainStopped()
+
+	params := map[string]interface{}{
+		"item": map[string]interface{}{
+			"file": k.ToRemotePath(file),
+		},
+	}
+	if startTime > 0 {
+		params["options"] = map[string]interface{}{
+			"resume": newGlobalTime(startTime),
+		}
+	}
+
+	return k.Call("Player.Open", params, nil)
+}
+
+// getActivePlayerId returns the ID of the video player, or the first active player.
+func (k *Kodi) getActivePlayerId() (int, error) {
+	var players []activePlayer
+	if err := k.Call("Player.GetActivePlayers", nil, &players); err != nil {
+		return 0, err
+	}
+	if len(players) == 0 {
+		return 0, ErrNoActivePlayer
+	}
+	for _, p := range players {
+		if p.Type == "video" {
+			return p.PlayerId, nil
+		}
+	}
+	return players[0].PlayerId, nil
+}
+
+// IsPlaying returns true if a player is active.
+func (k *Kodi) IsPlaying() bool {
+	_, err := k.getActivePlayerId()
+	return err == nil
+}
+
+func (k *Kodi) setPlaying(play bool) error {
+	playerId, err := k.getActivePlayerId()
+	if err != nil {
+		return err
+	}
+	return k.Call("Player.PlayPause", map[string]interface{}{
+		"playerid": playerId,
+		"play":     play,
+	}, nil)
+}
+
+func (k *Kodi) Pause() error {
+	return k.setPlaying(false)
+}
+
+func (k *Kodi) Resume() error {
+	return k.setPlaying(true)
+}
+
+// Seek seeks to the position, in seconds.
+func (k *Kodi) Seek(position float64) error {
+	playerId, err := k.getActivePlayerId()
+	if err != nil {
+		return err
+	}
+	return k.Call("Player.Seek", map[string]interface{}{
+		"playerid": playerId,
+		"value": map[string]interface{}{
+			"time": newGlobalTime(position),
+		},
+	}, nil)
+}
+
+// Stop stops the playback.
+func (k *Kodi) Stop() error {
+	playerId, err := k.getActivePlayerId()
+	if err != nil {
+		return err
+	}
+	return k.Call("Player.Stop", map[string]interface{}{
+		"playerid": playerId,
+	}, nil)
+}
+
+// GetStatus returns the status of the active player.
+// It returns ErrNoActivePlayer if nothing is playing.
+func (k *Kodi) GetStatus() (*Status, error) {
+	playerId, err := k.getActivePlayerId()
+	if err != nil {
+		return nil, err
+	}
+
+	var props playerProperties
+	err = k.Call("Player.GetProperties", map[string]interface{}{
+		"playerid":   playerId,
+		"properties": []string{"time", "totaltime", "percentage", "speed"},
+	}, &props)
+	if err != nil {
+		return nil, err
+	}
+
+	var item playerItem
+	err = k.Call("Player.GetItem", map[string]interface{}{
+		"playerid":   playerId,
+		"properties": []string{"file", "title"},
+	}, &item)
+	if err != nil {
+		return nil, err
+	}
+
+	title := item.Item.Title
+	if title == "" {
+		title = item.Item.Label
+	}
+
+	return &Status{
+		File:       item.Item.File,
+		Filename:   baseName(item.Item.File),
+		Title:      title,
+		Position:   props.Time.toSeconds(),
+		Duration:   props.TotalTime.toSeconds(),
+		Percentage: props.Percentage,
+		Playing:    props.Speed != 0,
+	}, nil
+}
@@ -0,0 +1,550 @@
+package mediaplayer
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"path/filepath"
+	"seanime/internal/mediaplayers/dlna"
+	"seanime/internal/mediaplayers/kodi"
+	mpchc2 "seanime/internal/mediaplayers/mpchc"
+	"seanime/internal/mediaplayers/mpv"
+	vlc2 "seanime/internal/mediaplayers/vlc"
+	"seanime/internal/mediastream/trackprefs"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// MediaPlayer is implemented by the media players that can be controlled and tracked.
+	// Players are registered by name in the Repository, the default player is selected by its name.
+	MediaPlayer interface {
+		// Start launches the player if it needs to be launched before opening files.
+		Start() error
+		// Open plays a local file.
+		Open(path string, opts *OpenOptions) error
+		// OpenStream plays a stream URL.
+		OpenStream(url string, opts *OpenOptions) error
+		Pause() error
+		Resume() error
+		// Seek seeks to the position, in seconds.
+		Seek(seconds float64) error
+		// GetStatus returns the current playback status.
+		// It returns an error if the player cannot be reached, and nil if the player does not play anything that can be tracked.
+		GetStatus() (*PlaybackStatus, error)
+		// Exited returns a channel that receives a value when the playback stops or the player exits.
+		// Players that cannot send notifications return nil, tracking then relies on GetStatus errors.
+		Exited() <-chan struct{}
+		// Close releases the player, e.g. closes the player if it was launched by Seanime.
+		Close()
+		GetExecutablePath() string
+	}
+
+	// OpenOptions are the options of MediaPlayer.Open and MediaPlayer.OpenStream.
+	// Players ignore the options they do not support.
+	OpenOptions struct {
+		StartTime   float64  // Position to resume from, in seconds, 0 to play from the start
+		WindowTitle string   // Title of the player window
+		ScriptOpts  []string // "key=value" options passed to user scripts (mpv)
+		// Audio and subtitle tracks to select, nil to let the player decide (mpv, VLC, MPC-HC)
+		Tracks *trackprefs.Selection
+	}
+)
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// VLC
+
+type vlcPlayer struct {
+	vlc *vlc2.VLC
+}
+
+func NewVLCPlayer(vlc *vlc2.VLC) MediaPlayer {
+	return &vlcPlayer{vlc: vlc}
+}
+
+func (p *vlcPlayer) Start() error {
+	return p.vlc.Start()
+}
+
+func (p *vlcPlayer) Open(path string, opts *OpenOptions) error {
+	if err := p.vlc.Start(); err != nil {
+		return fmt.Errorf("could not start VLC, %w", err)
+	}
+
+	if err := p.vlc.AddAndPlayWithOptions(path, vlcTrackOptions(opts.Tracks)...); err != nil {
+		return err
+	}
+
+	if opts.StartTime > 0 {
+		time.Sleep(400 * time.Millisecond)
+		_ = p.vlc.ForcePause()
+		time.Sleep(400 * time.Millisecond)
+		_ = p.vlc.Seek(fmt.Sprintf("%d", int(opts.StartTime)))
+		time.Sleep(400 * time.Millisecond)
+		_ = p.vlc.Resume()
+	}
+
+	if opts.Tracks != nil && opts.Tracks.SubtitleDelay != 0 {
+		time.Sleep(400 * time.Millisecond)
+		_ = p.vlc.SubDelay(opts.Tracks.SubtitleDelay)
+	}
+
+	return nil
+}
+
+// vlcTrackOptions returns the input options selecting the tracks.
+// The indexes of the tracks take precedence over the languages.
+func vlcTrackOptions(tracks *trackprefs.Selection) []string {
+	ret := make([]string, 0)
+	if tracks == nil {
+		return ret
+	}
+	if tracks.AudioIndex != nil {
+		ret = append(ret, ":audio-track="+strconv.Itoa(*tracks.AudioIndex))
+	} else if len(tracks.AudioLanguages) > 0 {
+		ret = append(ret, ":audio-language="+strings.Join(tracks.AudioLanguages, ","))
+	}
+	if tracks.SubtitleIndex != nil {
+		ret = append(ret, ":sub-track="+strconv.Itoa(*tracks.SubtitleIndex))
+	} else if len(tracks.SubtitleLanguages) > 0 {
+		ret = append(ret, ":sub-language="+strings.Join(tracks.SubtitleLanguages, ","))
+	}
+	return ret
+}
+
+func (p *vlcPlayer) OpenStream(url string, opts *OpenOptions) error {
+	return p.Open(url, opts)
+}
+
+func (p *vlcPlayer) Pause() error {
+	return p.vlc.Pause()
+}
+
+func (p *vlcPlayer) Resume() error {
+	return p.vlc.Resume()
+}
+
+func (p *vlcPlayer) Seek(seconds float64) error {
+	return p.vlc.Seek(fmt.Sprintf("%d", int(seconds)))
+}
+
+func (p *vlcPlayer) GetStatus() (*PlaybackStatus, error) {
+	st, err := p.vlc.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, nil
+	}
+
+	return &PlaybackStatus{
+		CompletionPercentage: st.Position,
+		Playing:              st.State == "playing",
+		Filename:             st.Information.Category["meta"].Filename,
+		Duration:             int(st.Length * 1000),
+		Filepath:             "", // VLC does not provide the filepath
+		CurrentTimeInSeconds: float64(st.Time),
+		DurationInSeconds:    float64(st.Length),
+	}, nil
+}
+
+func (p *vlcPlayer) Exited() <-chan struct{} {
+	return nil
+}
+
+func (p *vlcPlayer) Close() {}
+
+func (p *vlcPlayer) GetExecutablePath() string {
+	return p.vlc.GetExecutablePath()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// MPC-HC
+
+type mpcHcPlayer struct {
+	mpcHc *mpchc2.MpcHc
+}
+
+func NewMpcHcPlayer(mpcHc *mpchc2.MpcHc) MediaPlayer {
+	return &mpcHcPlayer{mpcHc: mpcHc}
+}
+
+func (p *mpcHcPlayer) Start() error {
+	return p.mpcHc.Start()
+}
+
+func (p *mpcHcPlayer) Open(path string, opts *OpenOptions) error {
+	if err := p.mpcHc.Start(); err != nil {
+		return fmt.Errorf("could not start MPC-HC, %w", err)
+	}
+
+	if _, err := p.mpcHc.OpenAndPlay(path); err != nil {
+		return err
+	}
+
+	if opts.StartTime > 0 {
+		time.Sleep(400 * time.Millisecond)
+		_ = p.mpcHc.Pause()
+		time.Sleep(400 * time.Millisecond)
+		_ = p.mpcHc.Seek(int(opts.StartTime))
+		time.Sleep(400 * time.Millisecond)
+		_ = p.mpcHc.Play()
+	}
+
+	if opts.Tracks != nil {
+		p.selectTracks(opts.Tracks)
+	}
+
+	return nil
+}
+
+// selectTracks selects the tracks with the commands of the web interface, since it cannot select a track directly.
+// It assumes MPC-HC selected the first tracks, i.e. that its own language preferences are not set,
+// and that the subtitle delay step is the default 500 ms.
+func (p *mpcHcPlayer) selectTracks(tracks *trackprefs.Selection) {
+	time.Sleep(400 * time.Millisecond)
+	if tracks.AudioIndex != nil {
+		for i := 0; i < *tracks.AudioIndex; i++ {
+			_ = p.mpcHc.NextAudioTrack()
+		}
+	}
+	if tracks.SubtitleIndex != nil {
+		for i := 0; i < *tracks.SubtitleIndex; i++ {
+			_ = p.mpcHc.NextSubtitleTrack()
+		}
+	}
+	steps := int(math.Round(tracks.SubtitleDelay / 0.5))
+	for i := 0; i < max(steps, -steps); i++ {
+		_ = p.mpcHc.ShiftSubtitleDelay(steps > 0)
+	}
+}
+
+func (p *mpcHcPlayer) OpenStream(url string, opts *OpenOptions) error {
+	return p.Open(url, opts)
+}
+
+func (p *mpcHcPlayer) Pause() error {
+	return p.mpcHc.Pause()
+}
+
+func (p *mpcHcPlayer) Resume() error {
+	return p.mpcHc.Play()
+}
+
+func (p *mpcHcPlayer) Seek(seconds float64) error {
+	return p.mpcHc.Seek(int(seconds))
+}
+
+func (p *mpcHcPlayer) GetStatus() (*PlaybackStatus, error) {
+	st, err := p.mpcHc.GetVariables()
+	if err != nil {
+		return nil, err
+	}
+	if st == nil || st.Duration == 0 {
+		return nil, nil
+	}
+
+	return &PlaybackStatus{
+		CompletionPercentage: st.Position / st.Duration,
+		Playing:              st.State == 2,
+		Filename:             st.File,
+		Duration:             int(st.Duration),
+		Filepath:             st.FilePath,
+		CurrentTimeInSeconds: st.Position / 1000,
+		DurationInSeconds:    st.Duration / 1000,
+	}, nil
+}
+
+func (p *mpcHcPlayer) Exited() <-chan struct{} {
+	return nil
+}
+
+func (p *mpcHcPlayer) Close() {}
+
+func (p *mpcHcPlayer) GetExecutablePath() string {
+	return p.mpcHc.GetExecutablePath()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// MPV
+
+type mpvPlayer struct {
+	mpv *mpv.Mpv
+}
+
+func NewMpvPlayer(mpv *mpv.Mpv) MediaPlayer {
+	return &mpvPlayer{mpv: mpv}
+}
+
+// Start does nothing, mpv is launched when a file is opened.
+func (p *mpvPlayer) Start() error {
+	return nil
+}
+
+func (p *mpvPlayer) Open(path string, opts *OpenOptions) error {
+	// Script options are set before loading the file if the player is already running,
+	// and passed as arguments otherwise
+	_ = p.mpv.SetScriptOpts(opts.ScriptOpts)
+
+	args := make([]string, 0)
+	for _, opt := range opts.ScriptOpts {
+		args = append(args, "--script-opts-append="+opt)
+	}
+	if opts.WindowTitle != "" {
+		args = append(args, fmt.Sprintf("--title=%q", opts.WindowTitle))
+	}
+	if opts.StartTime > 0 {
+		args = append(args, "--no-resume-playback")
+	}
+
+	if err := p.mpv.OpenAndPlayWithOptions(path, mpvTrackOptions(opts.Tracks), args...); err != nil {
+		return err
+	}
+
+	if opts.StartTime > 0 {
+		_ = p.mpv.SeekTo(opts.StartTime)
+	}
+
+	return nil
+}
+
+// mpvTrackOptions returns the mpv options selecting the tracks.
+// Options that are not set are empty so that the values set for a previous file are restored.
+func mpvTrackOptions(tracks *trackprefs.Selection) map[string]string {
+	ret := map[string]string{"alang": "", "slang": "", "aid": "", "sid": "", "sub-delay": ""}
+	if tracks == nil {
+		return ret
+	}
+	ret["alang"] = strings.Join(tracks.AudioLanguages, ",")
+	ret["slang"] = strings.Join(tracks.SubtitleLanguages, ",")
+	// mpv track IDs start at 1
+	if tracks.AudioIndex != nil {
+		ret["aid"] = strconv.Itoa(*tracks.AudioIndex + 1)
+	}
+	if tracks.SubtitleIndex != nil {
+		ret["sid"] = strconv.Itoa(*tracks.SubtitleIndex + 1)
+	}
+	if tracks.SubtitleDelay != 0 {
+		ret["sub-delay"] = strconv.FormatFloat(tracks.SubtitleDelay, 'f', -1, 64)
+	}
+	return ret
+}
+
+func (p *mpvPlayer) OpenStream(url string, opts *OpenOptions) error {
+	return p.Open(url, opts)
+}
+
+func (p *mpvPlayer) Pause() error {
+	return p.mpv.Pause()
+}
+
+func (p *mpvPlayer) Resume() error {
+	return p.mpv.Resume()
+}
+
+func (p *mpvPlayer) Seek(seconds float64) error {
+	return p.mpv.Seek(seconds)
+}
+
+func (p *mpvPlayer) GetStatus() (*PlaybackStatus, error) {
+	st, err := p.mpv.GetPlaybackStatus()
+	if err != nil {
+		return nil, err
+	}
+	if st == nil || st.Duration == 0 || !st.IsRunning {
+		return nil, nil
+	}
+
+	return &PlaybackStatus{
+		CompletionPercentage: st.Position / st.Duration,
+		Playing:              !st.Paused,
+		Filename:             st.Filename,
+		Duration:             int(st.Duration),
+		Filepath:             st.Filepath,
+		CurrentTimeInSeconds: st.Position,
+		DurationInSeconds:    st.Duration,
+	}, nil
+}
+
+func (p *mpvPlayer) Exited() <-chan struct{} {
+	return nil
+}
+
+func (p *mpvPlayer) Close() {
+	p.mpv.CloseAll()
+}
+
+func (p *mpvPlayer) GetExecutablePath() string {
+	return p.mpv.GetExecutablePath()
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Kodi
+
+type kodiPlayer struct {
+	kodi *kodi.Kodi
+}
+
+func NewKodiPlayer(kodi *kodi.Kodi) MediaPlayer {
+	return &kodiPlayer{kodi: kodi}
+}
+
+// Start checks that Kodi can be reached and subscribes to its notifications.
+func (p *kodiPlayer) Start() error {
+	if err := p.kodi.Ping(); err != nil {
+		return err
+	}
+	if err := p.kodi.Listen(); err != nil && p.kodi.Logger != nil {
+		// Tracking still works without notifications
+		p.kodi.Logger.Warn().Err(err).Msg("media player: Could not subscribe to Kodi notifications")
+	}
+	return nil
+}
+
+func (p *kodiPlayer) Open(path string, opts *OpenOptions) error {
+	if err := p.Start(); err != nil {
+		return fmt.Errorf("could not connect to Kodi, %w", err)
+	}
+	return p.kodi.Open(path, opts.StartTime)
+}
+
+func (p *kodiPlayer) OpenStream(url string, opts *OpenOptions) error {
+	return p.Open(url, opts)
+}
+
+func (p *kodiPlayer) Pause() error {
+	return p.kodi.Pause()
+}
+
+func (p *kodiPlayer) Resume() error {
+	return p.kodi.Resume()
+}
+
+func (p *kodiPlayer) Seek(seconds float64) error {
+	return p.kodi.Seek(seconds)
+}
+
+func (p *kodiPlayer) GetStatus() (*PlaybackStatus, error) {
+	st, err := p.kodi.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	if st.Duration == 0 {
+		return nil, nil
+	}
+
+	return &PlaybackStatus{
+		CompletionPercentage: st.Percentage / 100,
+		Playing:              st.Playing,
+		Filename:             st.Filename,
+		Duration:             int(st.Duration * 1000),
+		Filepath:             p.kodi.ToLocalPath(st.File),
+		CurrentTimeInSeconds: st.Position,
+		DurationInSeconds:    st.Duration,
+	}, nil
+}
+
+func (p *kodiPlayer) Exited() <-chan struct{} {
+	return p.kodi.Stopped()
+}
+
+// Close unsubscribes from the notifications, Kodi keeps running.
+func (p *kodiPlayer) Close() {
+	p.kodi.Close()
+}
+
+func (p *kodiPlayer) GetExecutablePath() string {
+	return ""
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// DLNA
+
+type dlnaPlayer struct {
+	renderer *dlna.Renderer
+}
+
+func NewDlnaPlayer(renderer *dlna.Renderer) MediaPlayer {
+	return &dlnaPlayer{renderer: renderer}
+}
+
+// Start checks that the renderer can be reached.
+func (p *dlnaPlayer) Start() error {
+	_, err := p.renderer.Device()
+	return err
+}
+
+// Open casts a local file, the renderer fetches it from Seanime.
+func (p *dlnaPlayer) Open(path string, opts *OpenOptions) error {
+	uri, err := p.renderer.FileURL(path)
+	if err != nil {
+		return fmt.Errorf("could not connect to the DLNA renderer, %w", err)
+	}
+	return p.renderer.Open(uri, p.title(path, opts), opts.StartTime)
+}
+
+func (p *dlnaPlayer) OpenStream(url string, opts *OpenOptions) error {
+	if err := p.Start(); err != nil {
+		return fmt.Errorf("could not connect to the DLNA renderer, %w", err)
+	}
+	return p.renderer.Open(p.renderer.ReachableURL(url), p.title(url, opts), opts.StartTime)
+}
+
+func (p *dlnaPlayer) title(path string, opts *OpenOptions) string {
+	if opts.WindowTitle != "" {
+		return opts.WindowTitle
+	}
+	return filepath.Base(path)
+}
+
+func (p *dlnaPlayer) Pause() error {
+	return p.renderer.Pause()
+}
+
+func (p *dlnaPlayer) Resume() error {
+	return p.renderer.Resume()
+}
+
+func (p *dlnaPlayer) Seek(seconds float64) error {
+	return p.renderer.Seek(seconds)
+}
+
+func (p *dlnaPlayer) GetStatus() (*PlaybackStatus, error) {
+	st, err := p.renderer.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	if st.Duration == 0 || st.State == dlna.StateStopped || st.State == dlna.StateNoMediaPresent {
+		return nil, nil
+	}
+
+	ret := &PlaybackStatus{
+		CompletionPercentage: st.Position / st.Duration,
+		Playing:              st.State == dlna.StatePlaying || st.State == dlna.StateTransitioning,
+		Duration:             int(st.Duration * 1000),
+		CurrentTimeInSeconds: st.Position,
+		DurationInSeconds:    st.Duration,
+	}
+	// Local files are served by Seanime, their path is part of the URL
+	if localPath := dlna.LocalPath(st.TrackURI); localPath != "" {
+		ret.Filepath = localPath
+		ret.Filename = filepath.Base(localPath)
+	} else if u, err := url.Parse(st.TrackURI); err == nil {
+		ret.Filename = path.Base(u.Path)
+	}
+
+	return ret, nil
+}
+
+func (p *dlnaPlayer) Exited() <-chan struct{} {
+	return p.renderer.Stopped()
+}
+
+// Close does nothing, the renderer keeps playing until it is stopped from the TV.
+func (p *dlnaPlayer) Close() {}
+
+func (p *dlnaPlayer) GetExecutablePath() string {
+	return ""
+}
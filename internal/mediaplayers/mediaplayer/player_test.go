@@ -0,0 +1,133 @@
+package mediaplayer
+
+import (
+	"seanime/internal/events"
+	"seanime/internal/util"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlayer is a MediaPlayer that plays nothing.
+type fakePlayer struct {
+	mu       sync.Mutex
+	status   *PlaybackStatus
+	paused   bool
+	position float64
+	exitedCh chan struct{}
+}
+
+func (p *fakePlayer) Start() error { return nil }
+
+func (p *fakePlayer) Open(path string, opts *OpenOptions) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = &PlaybackStatus{
+		Filename:             path,
+		Filepath:             path,
+		Playing:              true,
+		CurrentTimeInSeconds: opts.StartTime,
+		DurationInSeconds:    100,
+	}
+	return nil
+}
+
+func (p *fakePlayer) OpenStream(url string, opts *OpenOptions) error { return p.Open(url, opts) }
+
+func (p *fakePlayer) Pause() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+	return nil
+}
+
+func (p *fakePlayer) Resume() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+	return nil
+}
+
+func (p *fakePlayer) Seek(seconds float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.position = seconds
+	return nil
+}
+
+func (p *fakePlayer) GetStatus() (*PlaybackStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.status == nil {
+		return nil, nil
+	}
+	ret := *p.status
+	ret.CompletionPercentage = ret.CurrentTimeInSeconds / ret.DurationInSeconds
+	return &ret, nil
+}
+
+func (p *fakePlayer) Exited() <-chan struct{} { return p.exitedCh }
+
+func (p *fakePlayer) Close() {}
+
+func (p *fakePlayer) GetExecutablePath() string { return "" }
+
+func newFakePlayerRepository(player MediaPlayer) *Repository {
+	logger := util.NewLogger()
+	return NewRepository(&NewRepositoryOptions{
+		Logger:         logger,
+		Default:        "fake",
+		WSEventManager: events.NewMockWSEventManager(logger),
+		Players:        map[string]MediaPlayer{"fake": player},
+	})
+}
+
+func TestRepository_RegisteredPlayer(t *testing.T) {
+	player := &fakePlayer{}
+	repo := newFakePlayerRepository(player)
+
+	require.NoError(t, repo.Start())
+	require.NoError(t, repo.Pause())
+	assert.True(t, player.paused)
+	require.NoError(t, repo.Resume())
+	assert.False(t, player.paused)
+	require.NoError(t, repo.Seek(42))
+	assert.Equal(t, 42.0, player.position)
+
+	repo.Default = "unknown"
+	assert.Error(t, repo.Pause())
+	assert.Empty(t, repo.GetExecutablePath())
+}
+
+func TestRepository_TrackingStopsWhenPlayerExits(t *testing.T) {
+	player := &fakePlayer{exitedCh: make(chan struct{}, 1)}
+	repo := newFakePlayerRepository(player)
+	sub := repo.Subscribe("test")
+
+	require.NoError(t, player.Open("/anime/01.mkv", &OpenOptions{StartTime: 90}))
+	repo.StartTracking()
+
+	select {
+	case status := <-sub.TrackingStartedCh:
+		assert.Equal(t, "/anime/01.mkv", status.Filename)
+		assert.Equal(t, 90.0, status.CurrentTimeInSeconds)
+	case <-time.After(10 * time.Second):
+		t.Fatal("tracking did not start")
+	}
+
+	player.exitedCh <- struct{}{}
+
+	for {
+		select {
+		case reason := <-sub.TrackingStoppedCh:
+			assert.Equal(t, PlayerClosedEvent, reason)
+			return
+		case <-sub.PlaybackStatusCh:
+		case <-time.After(10 * time.Second):
+			t.Fatal("tracking did not stop")
+		}
+	}
+}
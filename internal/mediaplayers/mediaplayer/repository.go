@@ -7,10 +7,15 @@ import (
 	"seanime/internal/continuity"
 	"seanime/internal/events"
 	"seanime/internal/hook"
+	"seanime/internal/mediaplayers/dlna"
+	"seanime/internal/mediaplayers/kodi"
 	mpchc2 "seanime/internal/mediaplayers/mpchc"
 	"seanime/internal/mediaplayers/mpv"
 	vlc2 "seanime/internal/mediaplayers/vlc"
+	"seanime/internal/mediastream/skipdetect"
+	"seanime/internal/mediastream/trackprefs"
 	"seanime/internal/util/result"
+	"strconv"
 	"sync"
 	"time"
 
@@ -26,11 +31,11 @@ type (
 	Repository struct {
 		Logger                *zerolog.Logger
 		Default               string
-		VLC                   *vlc2.VLC
-		MpcHc                 *mpchc2.MpcHc
-		Mpv                   *mpv.Mpv
+		players               map[string]MediaPlayer // Registered players by name, e.g. "mpv"
 		wsEventManager        events.WSEventManagerInterface
 		continuityManager     *continuity.Manager
+		skipDetector          *skipdetect.Detector
+		trackPreferences      *trackprefs.Manager
 		playerInUse           string
 		completionThreshold   float64
 		mu                    sync.Mutex
@@ -38,7 +43,6 @@ type (
 		currentPlaybackStatus *PlaybackStatus
 		subscribers           *result.Map[string, *RepositorySubscriber]
 		cancel                context.CancelFunc
-		exitedCh              chan struct{} // Closed when the media player exits
 	}
 
 	NewRepositoryOptions struct {
@@ -47,8 +51,13 @@ type (
 		VLC               *vlc2.VLC
 		MpcHc             *mpchc2.MpcHc
 		Mpv               *mpv.Mpv
+		Kodi              *kodi.Kodi
+		Dlna              *dlna.Renderer
+		Players           map[string]MediaPlayer // Other players, by name
 		WSEventManager    events.WSEventManagerInterface
 		ContinuityManager *continuity.Manager
+		SkipDetector      *skipdetect.Detector // Optional, used to pass the skip markers to mpv
+		TrackPreferences  *trackprefs.Manager  // Optional, used to select the preferred audio and subtitle tracks
 	}
 
 	RepositorySubscriber struct {
@@ -79,20 +88,57 @@ type (
 )
 
 func NewRepository(opts *NewRepositoryOptions) *Repository {
-
-	return &Repository{
+	ret := &Repository{
 		Logger:                opts.Logger,
 		Default:               opts.Default,
-		VLC:                   opts.VLC,
-		MpcHc:                 opts.MpcHc,
-		Mpv:                   opts.Mpv,
+		players:               make(map[string]MediaPlayer),
 		wsEventManager:        opts.WSEventManager,
 		continuityManager:     opts.ContinuityManager,
+		skipDetector:          opts.SkipDetector,
+		trackPreferences:      opts.TrackPreferences,
 		completionThreshold:   0.8,
 		subscribers:           result.NewResultMap[string, *RepositorySubscriber](),
 		currentPlaybackStatus: &PlaybackStatus{},
-		exitedCh:              make(chan struct{}),
 	}
+
+	if opts.VLC != nil {
+		ret.RegisterPlayer("vlc", NewVLCPlayer(opts.VLC))
+	}
+	if opts.MpcHc != nil {
+		ret.RegisterPlayer("mpc-hc", NewMpcHcPlayer(opts.MpcHc))
+	}
+	if opts.Mpv != nil {
+		ret.RegisterPlayer("mpv", NewMpvPlayer(opts.Mpv))
+	}
+	if opts.Kodi != nil {
+		ret.RegisterPlayer("kodi", NewKodiPlayer(opts.Kodi))
+	}
+	if opts.Dlna != nil {
+		ret.RegisterPlayer("dlna", NewDlnaPlayer(opts.Dlna))
+	}
+	for name, player := range opts.Players {
+		ret.RegisterPlayer(name, player)
+	}
+
+	return ret
+}
+
+// RegisterPlayer adds a player that can be selected as the default player.
+func (m *Repository) RegisterPlayer(name string, player MediaPlayer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.players[name] = player
+}
+
+// getPlayer returns the default player.
+func (m *Repository) getPlayer() (MediaPlayer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	player, ok := m.players[m.Default]
+	if !ok {
+		return nil, errors.New("no default media player set")
+	}
+	return player, nil
 }
 
 func (m *Repository) Subscribe(id string) *RepositorySubscriber {
@@ -123,227 +169,127 @@ func (m *Repository) IsRunning() bool {
 }
 
 func (m *Repository) GetExecutablePath() string {
-	switch m.Default {
-	case "vlc":
-		return m.VLC.GetExecutablePath()
-	case "mpc-hc":
-		return m.MpcHc.GetExecutablePath()
-	case "mpv":
-		return m.Mpv.GetExecutablePath()
+	player, err := m.getPlayer()
+	if err != nil {
+		return ""
 	}
-	return ""
+	return player.GetExecutablePath()
 }
 
 func (m *Repository) GetDefault() string {
 	return m.Default
 }
 
+// Start launches the default media player, if it needs to be launched.
+func (m *Repository) Start() error {
+	player, err := m.getPlayer()
+	if err != nil {
+		return err
+	}
+	return player.Start()
+}
+
 // Play will start the media player and load the video at the given path.
-// The implementation of the specific media player is handled by the respective media player package.
+// The implementation of the specific media player is handled by the respective MediaPlayer.
 // Calling it multiple *should* not open multiple instances of the media player -- subsequent calls should just load a new video if the media player is already open.
 func (m *Repository) Play(path string) error {
 
 	m.Logger.Debug().Str("path", path).Msg("media player: Media requested")
 
-	lastWatched := m.continuityManager.GetExternalPlayerEpisodeWatchHistoryItem(path, false, 0, 0)
-
-	switch m.Default {
-	case "vlc":
-		err := m.VLC.Start()
-		if err != nil {
-			m.Logger.Error().Err(err).Msg("media player: Could not start media player using VLC")
-			return fmt.Errorf("could not start VLC, %w", err)
-		}
+	player, err := m.getPlayer()
+	if err != nil {
+		return err
+	}
 
-		err = m.VLC.AddAndPlay(path)
-		if err != nil {
-			m.Logger.Error().Err(err).Msg("media player: Could not open and play video using VLC")
-			if m.VLC.Path != "" {
-				return fmt.Errorf("could not open and play video, %w", err)
-			} else {
-				return fmt.Errorf("could not open and play video, %w", err)
-			}
-		}
+	opts := &OpenOptions{
+		// Pass the skip markers to user scripts
+		ScriptOpts: m.getSkipMarkersScriptOpts(path),
+		Tracks:     m.trackPreferences.GetFileSelection(path),
+	}
 
-		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			if lastWatched.Found {
-				time.Sleep(400 * time.Millisecond)
-				_ = m.VLC.ForcePause()
-				time.Sleep(400 * time.Millisecond)
-				_ = m.VLC.Seek(fmt.Sprintf("%d", int(lastWatched.Item.CurrentTime)))
-				time.Sleep(400 * time.Millisecond)
-				_ = m.VLC.Resume()
-			}
-		}
+	lastWatched := m.continuityManager.GetExternalPlayerEpisodeWatchHistoryItem(path, false, 0, 0)
+	if m.continuityManager.GetSettings().WatchContinuityEnabled && lastWatched.Found {
+		opts.StartTime = lastWatched.Item.CurrentTime
+	}
 
-		return nil
-	case "mpc-hc":
-		err := m.MpcHc.Start()
-		if err != nil {
-			m.Logger.Error().Err(err).Msg("media player: Could not start media player using MPC-HC")
-			return fmt.Errorf("could not start MPC-HC, %w", err)
-		}
-		_, err = m.MpcHc.OpenAndPlay(path)
-		if err != nil {
-			m.Logger.Error().Err(err).Msg("media player: Could not open and play video using MPC-HC")
-			return fmt.Errorf("could not open and play video, %w", err)
-		}
+	err = player.Open(path, opts)
+	if err != nil {
+		m.Logger.Error().Err(err).Str("player", m.Default).Msg("media player: Could not open and play video")
+		return fmt.Errorf("could not open and play video, %w", err)
+	}
 
-		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			if lastWatched.Found {
-				time.Sleep(400 * time.Millisecond)
-				_ = m.MpcHc.Pause()
-				time.Sleep(400 * time.Millisecond)
-				_ = m.MpcHc.Seek(int(lastWatched.Item.CurrentTime))
-				time.Sleep(400 * time.Millisecond)
-				_ = m.MpcHc.Play()
-			}
-		}
+	return nil
+}
 
-		return nil
-	case "mpv":
-		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			var args []string
-			if lastWatched.Found {
-				//args = append(args, "--no-resume-playback", fmt.Sprintf("--start=+%d", int(lastWatched.Item.CurrentTime)))
-				args = append(args, "--no-resume-playback")
-			}
-			err := m.Mpv.OpenAndPlay(path, args...)
-			if err != nil {
-				m.Logger.Error().Err(err).Msg("media player: Could not open and play video using MPV")
-				return fmt.Errorf("could not open and play video, %w", err)
-			}
-			if lastWatched.Found {
-				_ = m.Mpv.SeekTo(lastWatched.Item.CurrentTime)
-			}
-		} else {
-			err := m.Mpv.OpenAndPlay(path)
-			if err != nil {
-				m.Logger.Error().Err(err).Msg("media player: Could not open and play video using MPV")
-				return fmt.Errorf("could not open and play video, %w", err)
-			}
-			if lastWatched.Found {
-				_ = m.Mpv.SeekTo(lastWatched.Item.CurrentTime)
-			}
-		}
+// getSkipMarkersScriptOpts returns the mpv script options holding the intro and outro of the file, in seconds.
+// Absent markers are set to an empty value so that the values of the previous file are cleared.
+func (m *Repository) getSkipMarkersScriptOpts(path string) []string {
+	markers, _ := m.skipDetector.GetMarkers(path)
 
-		return nil
-	default:
-		return errors.New("no default media player set")
+	var introStart, introEnd, outroStart, outroEnd string
+	if markers.HasIntro() {
+		introStart = strconv.FormatFloat(markers.IntroStart, 'f', 3, 64)
+		introEnd = strconv.FormatFloat(markers.IntroEnd, 'f', 3, 64)
+	}
+	if markers.HasOutro() {
+		outroStart = strconv.FormatFloat(markers.OutroStart, 'f', 3, 64)
+		outroEnd = strconv.FormatFloat(markers.OutroEnd, 'f', 3, 64)
 	}
 
+	return []string{
+		"seanime-intro_start=" + introStart,
+		"seanime-intro_end=" + introEnd,
+		"seanime-outro_start=" + outroStart,
+		"seanime-outro_end=" + outroEnd,
+	}
 }
 
 func (m *Repository) Pause() error {
-	switch m.Default {
-	case "vlc":
-		return m.VLC.Pause()
-	case "mpc-hc":
-		return m.MpcHc.Pause()
-	case "mpv":
-		return m.Mpv.Pause()
-	default:
-		return errors.New("no default media player set")
+	player, err := m.getPlayer()
+	if err != nil {
+		return err
 	}
+	return player.Pause()
 }
 
 func (m *Repository) Resume() error {
-	switch m.Default {
-	case "vlc":
-		return m.VLC.Resume()
-	case "mpc-hc":
-		return m.MpcHc.Play()
-	case "mpv":
-		return m.Mpv.Resume()
-	default:
-		return errors.New("no default media player set")
+	player, err := m.getPlayer()
+	if err != nil {
+		return err
 	}
+	return player.Resume()
 }
 
 func (m *Repository) Seek(seconds float64) error {
-	switch m.Default {
-	case "vlc":
-		return m.VLC.Seek(fmt.Sprintf("%d", int(seconds)))
-	case "mpc-hc":
-		return m.MpcHc.Seek(int(seconds))
-	case "mpv":
-		return m.Mpv.Seek(seconds)
-	default:
-		return errors.New("no default media player set")
+	player, err := m.getPlayer()
+	if err != nil {
+		return err
 	}
+	return player.Seek(seconds)
 }
 
 func (m *Repository) Stream(streamUrl string, episode int, mediaId int, windowTitle string) error {
 
 	m.Logger.Debug().Str("streamUrl", streamUrl).Msg("media player: Stream requested")
-	var err error
 
-	switch m.Default {
-	case "vlc":
-		err = m.VLC.Start()
-	case "mpc-hc":
-		err = m.MpcHc.Start()
-		_, err = m.MpcHc.OpenAndPlay(streamUrl)
-	case "mpv":
-		// MPV does not need to be started
-	default:
-		return errors.New("no default media player set")
+	player, err := m.getPlayer()
+	if err != nil {
+		return err
 	}
 
-	if err != nil {
-		m.Logger.Error().Err(err).Msg("media player: Could not start media player for stream")
-		return fmt.Errorf("could not open media player, %w", err)
+	opts := &OpenOptions{
+		WindowTitle: windowTitle,
+		Tracks:      m.trackPreferences.GetStreamSelection(mediaId),
 	}
 
 	lastWatched := m.continuityManager.GetExternalPlayerEpisodeWatchHistoryItem("", true, episode, mediaId)
-
-	switch m.Default {
-	case "vlc":
-		err = m.VLC.AddAndPlay(streamUrl)
-
-		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			if lastWatched.Found {
-				time.Sleep(400 * time.Millisecond)
-				_ = m.VLC.ForcePause()
-				time.Sleep(400 * time.Millisecond)
-				_ = m.VLC.Seek(fmt.Sprintf("%d", int(lastWatched.Item.CurrentTime)))
-				time.Sleep(400 * time.Millisecond)
-				_ = m.VLC.Resume()
-			}
-		}
-
-	case "mpc-hc":
-		_, err = m.MpcHc.OpenAndPlay(streamUrl)
-
-		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			if lastWatched.Found {
-				time.Sleep(400 * time.Millisecond)
-				_ = m.MpcHc.Pause()
-				time.Sleep(400 * time.Millisecond)
-				_ = m.MpcHc.Seek(int(lastWatched.Item.CurrentTime))
-				time.Sleep(400 * time.Millisecond)
-				_ = m.MpcHc.Play()
-			}
-		}
-
-	case "mpv":
-		args := []string{}
-		if windowTitle != "" {
-			args = append(args, fmt.Sprintf("--title=%q", windowTitle))
-		}
-		if m.continuityManager.GetSettings().WatchContinuityEnabled {
-			err = m.Mpv.OpenAndPlay(streamUrl, args...)
-			if lastWatched.Found {
-				_ = m.Mpv.SeekTo(lastWatched.Item.CurrentTime)
-			}
-		} else {
-			err = m.Mpv.OpenAndPlay(streamUrl, args...)
-		}
-
+	if m.continuityManager.GetSettings().WatchContinuityEnabled && lastWatched.Found {
+		opts.StartTime = lastWatched.Item.CurrentTime
 	}
 
+	err = player.OpenStream(streamUrl, opts)
 	if err != nil {
-		m.Logger.Error().Err(err).Msg("media player: Could not open and play stream")
+		m.Logger.Error().Err(err).Str("player", m.Default).Msg("media player: Could not open and play stream")
 		return fmt.Errorf("could not open and play stream, %w", err)
 	}
 
@@ -360,9 +306,9 @@ func (m *Repository) Cancel() {
 	} else {
 		m.Logger.Debug().Msg("media player: Cancel request received, but no context found")
 	}
-	// Close MPV if it's the default player
-	if m.Default == "mpv" {
-		m.Mpv.CloseAll()
+	// Close the player, e.g. MPV if it was launched by Seanime
+	if player, ok := m.players[m.Default]; ok {
+		player.Close()
 	}
 	m.mu.Unlock()
 }
@@ -375,9 +321,9 @@ func (m *Repository) Stop() {
 		m.cancel()
 		m.trackingStopped("Tracking stopped")
 	}
-	// Close MPV if it's the default player
-	if m.Default == "mpv" {
-		m.Mpv.CloseAll()
+	// Close the player, e.g. MPV if it was launched by Seanime
+	if player, ok := m.players[m.Default]; ok {
+		player.Close()
 	}
 	m.mu.Unlock()
 }
@@ -428,6 +374,9 @@ func (m *Repository) StartTrackingTorrentStream() {
 
 	m.mu.Unlock()
 
+	// Not all players notify when they exit (nil channel)
+	exitedCh := m.getExitedCh()
+
 	go func() {
 		defer func() {
 			m.mu.Lock()
@@ -451,13 +400,13 @@ func (m *Repository) StartTrackingTorrentStream() {
 				m.isRunning = false
 				m.mu.Unlock()
 				return
-			//case <-m.exitedCh:
-			//	m.mu.Lock()
-			//	m.Logger.Debug().Msg("media player: Player exited")
-			//	m.isRunning = false
-			//	m.streamingTrackingStopped(PlayerClosedEvent)
-			//	m.mu.Unlock()
-			//	return
+			case <-exitedCh:
+				m.mu.Lock()
+				m.Logger.Debug().Msg("media player: Player exited")
+				m.isRunning = false
+				m.streamingTrackingStopped(PlayerClosedEvent)
+				m.mu.Unlock()
+				return
 			default:
 				// Wait at least 3 seconds before we start checking the status
 				if !gotFirstStatus {
@@ -500,7 +449,7 @@ func (m *Repository) StartTrackingTorrentStream() {
 				}
 
 				trackingStarted = true
-				ok := m.processStreamStatus(m.Default, status)
+				ok := m.processStatus(status)
 
 				if !ok {
 					m.streamingTrackingRetry("Failed to get player status")
@@ -576,6 +525,9 @@ func (m *Repository) StartTracking() {
 
 	m.mu.Unlock()
 
+	// Not all players notify when they exit (nil channel)
+	exitedCh := m.getExitedCh()
+
 	go func() {
 		for {
 			select {
@@ -591,13 +543,13 @@ func (m *Repository) StartTracking() {
 				m.isRunning = false
 				m.mu.Unlock()
 				return
-			//case <-m.exitedCh:
-			//	m.mu.Lock()
-			//	m.Logger.Debug().Msg("media player: Player exited")
-			//	m.isRunning = false
-			//	m.trackingStopped(PlayerClosedEvent)
-			//	m.mu.Unlock()
-			//	return
+			case <-exitedCh:
+				m.mu.Lock()
+				m.Logger.Debug().Msg("media player: Player exited")
+				m.isRunning = false
+				m.trackingStopped(PlayerClosedEvent)
+				m.mu.Unlock()
+				return
 			default:
 				// Wait at least X seconds before we start checking the status
 				if !gotFirstStatus {
@@ -629,7 +581,7 @@ func (m *Repository) StartTracking() {
 
 				gotFirstStatus = true
 
-				ok := m.processStatus(m.Default, status)
+				ok := m.processStatus(status)
 
 				if !ok {
 					m.trackingRetry("Failed to get player status")
@@ -738,131 +690,39 @@ func (m *Repository) streamingPlaybackStatus(status *PlaybackStatus) {
 	})
 }
 
-func (m *Repository) getStatus() (interface{}, error) {
-	switch m.Default {
-	case "vlc":
-		return m.VLC.GetStatus()
-	case "mpc-hc":
-		return m.MpcHc.GetVariables()
-	case "mpv":
-		return m.Mpv.GetPlaybackStatus()
+func (m *Repository) getStatus() (*PlaybackStatus, error) {
+	player, err := m.getPlayer()
+	if err != nil {
+		return nil, err
 	}
-	return nil, errors.New("unsupported media player")
+	return player.GetStatus()
 }
 
-func (m *Repository) processStatus(player string, status interface{}) bool {
-	switch player {
-	case "vlc":
-		// Process VLC status
-		st, ok := status.(*vlc2.Status)
-		if !ok || st == nil {
-			return false
-		}
-
-		m.currentPlaybackStatus.CompletionPercentage = st.Position
-		m.currentPlaybackStatus.Playing = st.State == "playing"
-		m.currentPlaybackStatus.Filename = st.Information.Category["meta"].Filename
-		m.currentPlaybackStatus.Duration = int(st.Length * 1000)
-		m.currentPlaybackStatus.Filepath = "" // VLC does not provide the filepath
-
-		m.currentPlaybackStatus.CurrentTimeInSeconds = float64(st.Time)
-		m.currentPlaybackStatus.DurationInSeconds = float64(st.Length)
-		return true
-	case "mpc-hc":
-		// Process MPC-HC status
-		st, ok := status.(*mpchc2.Variables)
-		if !ok || st == nil || st.Duration == 0 {
-			return false
-		}
-
-		m.currentPlaybackStatus.CompletionPercentage = st.Position / st.Duration
-		m.currentPlaybackStatus.Playing = st.State == 2
-		m.currentPlaybackStatus.Filename = st.File
-		m.currentPlaybackStatus.Duration = int(st.Duration)
-		m.currentPlaybackStatus.Filepath = st.FilePath
-
-		m.currentPlaybackStatus.CurrentTimeInSeconds = st.Position / 1000
-		m.currentPlaybackStatus.DurationInSeconds = st.Duration / 1000
-
-		return true
-	case "mpv":
-		// Process MPV status
-		st, ok := status.(*mpv.Playback)
-		if !ok || st == nil || st.Duration == 0 || st.IsRunning == false {
-			return false
-		}
-
-		m.currentPlaybackStatus.CompletionPercentage = st.Position / st.Duration
-		m.currentPlaybackStatus.Playing = !st.Paused
-		m.currentPlaybackStatus.Filename = st.Filename
-		m.currentPlaybackStatus.Duration = int(st.Duration)
-		m.currentPlaybackStatus.Filepath = st.Filepath
-
-		m.currentPlaybackStatus.CurrentTimeInSeconds = st.Position
-		m.currentPlaybackStatus.DurationInSeconds = st.Duration
-
-		return true
-	default:
-		return false
+// getExitedCh returns the exit notifications of the default player, nil if it does not send them.
+func (m *Repository) getExitedCh() <-chan struct{} {
+	player, err := m.getPlayer()
+	if err != nil {
+		return nil
 	}
+	return player.Exited()
 }
 
-func (m *Repository) processStreamStatus(player string, status interface{}) bool {
-	switch player {
-	case "vlc":
-		// Process VLC status
-		st, ok := status.(*vlc2.Status)
-		if !ok || st == nil {
-			return false
-		}
-
-		m.currentPlaybackStatus.CompletionPercentage = st.Position
-		m.currentPlaybackStatus.Playing = st.State == "playing"
-		m.currentPlaybackStatus.Filename = st.Information.Category["meta"].Filename
-		m.currentPlaybackStatus.Duration = int(st.Length * 1000)
-		m.currentPlaybackStatus.Filepath = "" // VLC does not provide the filepath
-
-		m.currentPlaybackStatus.CurrentTimeInSeconds = float64(st.Time)
-		m.currentPlaybackStatus.DurationInSeconds = float64(st.Length)
-
-		return true
-	case "mpc-hc":
-		// Process MPC-HC status
-		st, ok := status.(*mpchc2.Variables)
-		if !ok || st == nil {
-			return false
-		}
-
-		m.currentPlaybackStatus.CompletionPercentage = st.Position / st.Duration
-		m.currentPlaybackStatus.Playing = st.State == 2
-		m.currentPlaybackStatus.Filename = st.File
-		m.currentPlaybackStatus.Duration = int(st.Duration)
-		m.currentPlaybackStatus.Filepath = st.FilePath
-
-		m.currentPlaybackStatus.CurrentTimeInSeconds = st.Position / 1000
-		m.currentPlaybackStatus.DurationInSeconds = st.Duration / 1000
-
-		return true
-	case "mpv":
-		// Process MPV status
-		st, ok := status.(*mpv.Playback)
-		if !ok || st == nil || st.Duration == 0 || st.IsRunning == false {
-			return false
-		}
+// processStatus updates the current playback status, it returns false if the status cannot be tracked.
+func (m *Repository) processStatus(status *PlaybackStatus) bool {
+	if status == nil {
+		return false
+	}
 
-		m.currentPlaybackStatus.CompletionPercentage = st.Position / st.Duration
-		m.currentPlaybackStatus.Playing = !st.Paused
-		m.currentPlaybackStatus.Filename = st.Filename
-		m.currentPlaybackStatus.Duration = int(st.Duration)
-		m.currentPlaybackStatus.Filepath = st.Filepath
+	m.currentPlaybackStatus.CompletionPercentage = status.CompletionPercentage
+	m.currentPlaybackStatus.Playing = status.Playing
+	m.currentPlaybackStatus.Filename = status.Filename
+	m.currentPlaybackStatus.Duration = status.Duration
+	m.currentPlaybackStatus.Filepath = status.Filepath
 
-		m.currentPlaybackStatus.CurrentTimeInSeconds = st.Position
-		m.currentPlaybackStatus.DurationInSeconds = st.Duration
+	m.currentPlaybackStatus.CurrentTimeInSeconds = status.CurrentTimeInSeconds
+	m.currentPlaybackStatus.DurationInSeconds = status.DurationInSeconds
 
-		return true
-	default:
-		return false
-	}
+	return true
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
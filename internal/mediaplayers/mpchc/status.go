@@ -33,6 +33,29 @@ func (api *MpcHc) Seek(pos int) (err error) {
 	return
 }
 
+// NextAudioTrack switches to the next audio track
+func (api *MpcHc) NextAudioTrack() (err error) {
+	_, err = api.Execute(nextAudioCmd, nil)
+	return
+}
+
+// NextSubtitleTrack switches to the next subtitle track
+func (api *MpcHc) NextSubtitleTrack() (err error) {
+	_, err = api.Execute(nextSubtitleCmd, nil)
+	return
+}
+
+// ShiftSubtitleDelay increases the subtitle delay by one step, or decreases it if later is false.
+// The step is set in the MPC-HC options, 500 ms by default.
+func (api *MpcHc) ShiftSubtitleDelay(later bool) (err error) {
+	cmd := subtitleDelayMinusCmd
+	if later {
+		cmd = subtitleDelayPlusCmd
+	}
+	_, err = api.Execute(cmd, nil)
+	return
+}
+
 //----------------------------------------------------------------------------------------------------------------------
 
 func millisecondsToDuration(ms int) string {
@@ -5,11 +5,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"maps"
 	"os/exec"
 	"runtime"
 	"seanime/internal/mediaplayers/mpvipc"
 	"seanime/internal/util"
 	"seanime/internal/util/result"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +42,9 @@ type (
 		cmd            *exec.Cmd
 		prevSocketName string
 		exitedCh       chan struct{}
+		// Options changed by OpenAndPlayWithOptions, with their previous value.
+		// A nil value means the option was passed at launch, it is restored to the mpv default.
+		changedOptions map[string]*string
 	}
 
 	// Subscriber is a subscriber to the mpv events.
@@ -185,11 +190,57 @@ func (m *Mpv) replaceFile(filePath string) error {
 	return nil
 }
 
+// setOptions sets the options of the running player, they apply to the files loaded afterward.
+func (m *Mpv) setOptions(options map[string]string) {
+	if m.changedOptions == nil {
+		m.changedOptions = make(map[string]*string)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		value := options[name]
+		previous, changed := m.changedOptions[name]
+
+		if value == "" {
+			if !changed {
+				continue
+			}
+			if previous == nil {
+				if def, err := m.conn.Call("get_property_string", "option-info/"+name+"/default-value"); err == nil {
+					if str, ok := def.(string); ok {
+						_, _ = m.conn.Call("set_property_string", "options/"+name, str)
+					}
+				}
+			} else {
+				_, _ = m.conn.Call("set_property_string", "options/"+name, *previous)
+			}
+			delete(m.changedOptions, name)
+			continue
+		}
+
+		if !changed {
+			current, err := m.conn.Call("get_property_string", "options/"+name)
+			if str, ok := current.(string); err == nil && ok {
+				m.changedOptions[name] = &str
+			}
+		}
+		if _, err := m.conn.Call("set_property_string", "options/"+name, value); err != nil {
+			m.Logger.Warn().Err(err).Str("option", name).Msg("mpv: Failed to set option")
+		}
+	}
+}
+
 func (m *Mpv) Exited() chan struct{} {
 	return m.exitedCh
 }
 
 func (m *Mpv) OpenAndPlay(filePath string, args ...string) error {
+	return m.OpenAndPlayWithOptions(filePath, nil, args...)
+}
+
+// OpenAndPlayWithOptions plays the file with the given options, e.g. "alang".
+// The options are passed as arguments if the player is launched, and set before loading the file otherwise.
+// Options with an empty value are restored to the value they had before being changed.
+func (m *Mpv) OpenAndPlayWithOptions(filePath string, options map[string]string, args ...string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -198,9 +249,17 @@ func (m *Mpv) OpenAndPlay(filePath string, args ...string) error {
 	// If the player is already running, just load the new file
 	var err error
 	if m.conn != nil && !m.conn.IsClosed() {
+		m.setOptions(options)
 		// Launch player or replace file
 		err = m.replaceFile(filePath)
 	} else {
+		m.changedOptions = make(map[string]*string)
+		for _, name := range slices.Sorted(maps.Keys(options)) {
+			if options[name] != "" {
+				args = append(args, "--"+name+"="+options[name])
+				m.changedOptions[name] = nil
+			}
+		}
 		// Launch player
 		err = m.launchPlayer(false, filePath, args...)
 	}
@@ -328,6 +387,25 @@ func (m *Mpv) Seek(position float64) error {
 	return nil
 }
 
+// SetScriptOpts appends "key=value" options to the script options of the running player.
+func (m *Mpv) SetScriptOpts(opts []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conn == nil || m.conn.IsClosed() {
+		return errors.New("mpv is not running")
+	}
+
+	for _, opt := range opts {
+		_, err := m.conn.Call("change-list", "script-opts", "append", opt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Mpv) GetOpenConnection() (*mpvipc.Connection, error) {
 	if m.conn == nil || m.conn.IsClosed() {
 		return nil, errors.New("mpv is not running")
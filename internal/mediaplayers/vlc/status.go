@@ -216,6 +216,19 @@ func (vlc *VLC) AddAndPlay(uri string, option ...string) error {
 	return err
 }
 
+// AddAndPlayWithOptions adds a URI to the playlist with input options, e.g. ":audio-track=1", and starts playback.
+func (vlc *VLC) AddAndPlayWithOptions(uri string, options ...string) error {
+	urlSegment := "/requests/status.json?command=in_play&input=" + url.PathEscape(filepath.FromSlash(uri))
+	if strings.HasPrefix(uri, "http") {
+		urlSegment = "/requests/status.json?command=in_play&input=" + url.PathEscape(uri)
+	}
+	for _, option := range options {
+		urlSegment = urlSegment + "&option=" + url.QueryEscape(option)
+	}
+	_, err := vlc.RequestMaker(urlSegment)
+	return err
+}
+
 // Add adds a URI to the playlist
 func (vlc *VLC) Add(uri string) (err error) {
 	_, err = vlc.RequestMaker("/requests/status.json?command=in_enqueue&input=" + url.PathEscape(uri))
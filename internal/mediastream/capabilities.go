@@ -0,0 +1,191 @@
+package mediastream
+
+import (
+	"errors"
+	"fmt"
+	"seanime/internal/mediastream/transcoder"
+	"seanime/internal/mediastream/videofile"
+	"slices"
+	"strings"
+)
+
+const (
+	PlaybackPlanDirectPlay     PlaybackPlan = "directPlay"     // The file is served as is
+	PlaybackPlanRemux          PlaybackPlan = "remux"          // The streams are copied to HLS, only the container changes
+	PlaybackPlanAudioTranscode PlaybackPlan = "audioTranscode" // The video stream is copied, the audio is transcoded
+	PlaybackPlanTranscode      PlaybackPlan = "transcode"      // The video and audio are transcoded
+)
+
+type (
+	PlaybackPlan string
+
+	// ClientCapabilities describes what the client requesting the stream can decode.
+	// Codec and container names are the FFmpeg names (e.g. "h264", "hevc", "aac", "matroska").
+	// An empty list means the client did not specify any restriction.
+	ClientCapabilities struct {
+		Containers  []string `json:"containers"`
+		VideoCodecs []string `json:"videoCodecs"`
+		AudioCodecs []string `json:"audioCodecs"`
+		// Maximum video height, 0 for no limit
+		MaxHeight uint32 `json:"maxHeight"`
+		// Whether the client can display HDR videos
+		HDR bool `json:"hdr"`
+	}
+)
+
+// decidePlaybackPlan returns the cheapest plan that the client can play, and the reason of the decision.
+func decidePlaybackPlan(mediaInfo *videofile.MediaInfo, caps *ClientCapabilities) (PlaybackPlan, string) {
+	container := getContainerName(mediaInfo)
+	containerOk := supports(caps.Containers, container)
+
+	video := mediaInfo.Video
+	if video == nil && len(mediaInfo.Videos) > 0 {
+		video = &mediaInfo.Videos[0]
+	}
+
+	if video != nil {
+		if !supports(caps.VideoCodecs, video.Codec) {
+			return PlaybackPlanTranscode, fmt.Sprintf("The client does not support the %s video codec", video.Codec)
+		}
+		if caps.MaxHeight > 0 && video.Height > caps.MaxHeight {
+			return PlaybackPlanTranscode, fmt.Sprintf("The video resolution (%dp) is higher than the client maximum (%dp)", video.Height, caps.MaxHeight)
+		}
+		if video.IsHDR && !caps.HDR {
+			return PlaybackPlanTranscode, "The client does not support HDR"
+		}
+		if !containerOk && !transcoder.CanCopyVideo(video.Codec) {
+			return PlaybackPlanTranscode, fmt.Sprintf("The client does not support the %s container and the %s video codec cannot be remuxed", container, video.Codec)
+		}
+	}
+
+	audio, hasAudio := getDefaultAudio(mediaInfo)
+
+	if hasAudio && !supports(caps.AudioCodecs, audio.Codec) {
+		return PlaybackPlanAudioTranscode, fmt.Sprintf("The client does not support the %s audio codec", audio.Codec)
+	}
+
+	if !containerOk {
+		if hasAudio && !transcoder.CanCopyAudio(audio.Codec) {
+			return PlaybackPlanAudioTranscode, fmt.Sprintf("The client does not support the %s container and the %s audio codec cannot be remuxed", container, audio.Codec)
+		}
+		return PlaybackPlanRemux, fmt.Sprintf("The client does not support the %s container", container)
+	}
+
+	return PlaybackPlanDirectPlay, "The client supports the container and the codecs"
+}
+
+// getMasterOptions returns the variants of the master playlist for the plan.
+func getMasterOptions(plan PlaybackPlan, caps *ClientCapabilities) transcoder.MasterOptions {
+	switch plan {
+	case PlaybackPlanRemux:
+		return transcoder.MasterOptions{OriginalOnly: true, AudioCopy: true}
+	case PlaybackPlanAudioTranscode:
+		return transcoder.MasterOptions{OriginalOnly: true}
+	case PlaybackPlanTranscode:
+		ret := transcoder.MasterOptions{TranscodeOnly: true}
+		if caps != nil {
+			ret.MaxHeight = caps.MaxHeight
+		}
+		return ret
+	}
+	return transcoder.MasterOptions{}
+}
+
+// getContainerName returns the FFmpeg name of the container of the file.
+// FFprobe reports the names of the demuxer (e.g. "matroska,webm"), so the extension is used to tell them apart.
+func getContainerName(mediaInfo *videofile.MediaInfo) string {
+	switch strings.ToLower(mediaInfo.Extension) {
+	case "mkv":
+		return "matroska"
+	case "webm":
+		return "webm"
+	case "mp4", "m4v":
+		return "mp4"
+	}
+	if mediaInfo.Container != nil {
+		name, _, _ := strings.Cut(*mediaInfo.Container, ",")
+		return name
+	}
+	return strings.ToLower(mediaInfo.Extension)
+}
+
+// canPlayOptimized returns true if the client can play the files of the pre-transcoding queue (H.264 and AAC in MP4).
+func canPlayOptimized(caps *ClientCapabilities) bool {
+	return supports(caps.Containers, "mp4") && supports(caps.VideoCodecs, "h264") && supports(caps.AudioCodecs, "aac")
+}
+
+// getDefaultAudio returns the audio track played by default.
+func getDefaultAudio(mediaInfo *videofile.MediaInfo) (videofile.Audio, bool) {
+	if len(mediaInfo.Audios) == 0 {
+		return videofile.Audio{}, false
+	}
+	for _, audio := range mediaInfo.Audios {
+		if audio.IsDefault {
+			return audio, true
+		}
+	}
+	return mediaInfo.Audios[0], true
+}
+
+func supports(list []string, name string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(list, func(s string) bool {
+		return strings.EqualFold(s, name)
+	})
+}
+
+// RequestStreamForClient picks the cheapest stream the client can play from its capabilities and starts the playback.
+// The decision and its reason are returned in the media container, the transcoded variants are kept for the client.
+func (r *Repository) RequestStreamForClient(filepath string, clientId string, caps *ClientCapabilities) (ret *MediaContainer, err error) {
+	r.reqMu.Lock()
+	defer r.reqMu.Unlock()
+
+	r.logger.Debug().Str("filepath", filepath).Msg("mediastream: Stream requested with client capabilities")
+
+	if !r.IsInitialized() {
+		return nil, errors.New("module not initialized")
+	}
+
+	if caps == nil {
+		caps = &ClientCapabilities{}
+	}
+
+	settings := r.settings.MustGet()
+
+	mediaInfo, err := r.mediaInfoExtractor.GetInfo(settings.FfprobePath, filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, reason := decidePlaybackPlan(mediaInfo, caps)
+
+	streamType := StreamTypeDirect
+	switch {
+	case plan == PlaybackPlanDirectPlay:
+	case r.hasOptimizedVersion(filepath) && canPlayOptimized(caps):
+		// The pre-transcoded version is played directly instead of transcoding the file again
+		reason = reason + ", playing the optimized version"
+		plan = PlaybackPlanDirectPlay
+		streamType = StreamTypeOptimized
+	case settings.DirectPlayOnly:
+		reason = "Direct play only is enabled, ignoring: " + reason
+		plan = PlaybackPlanDirectPlay
+	default:
+		streamType = StreamTypeTranscode
+		// Reinitialize the transcoder for each new transcode request
+		if ok := r.initializeTranscoder(r.settings); !ok {
+			return nil, errors.New("real-time transcoder not initialized, check your settings")
+		}
+	}
+
+	r.logger.Debug().Str("plan", string(plan)).Str("reason", reason).Msg("mediastream: Decided playback plan")
+
+	ret, err = r.playbackManager.RequestPlayback(filepath, streamType)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.playbackManager.setPlaybackPlan(clientId, ret, plan, reason, getMasterOptions(plan, caps)), nil
+}
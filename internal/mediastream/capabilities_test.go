@@ -0,0 +1,76 @@
+package mediastream
+
+import (
+	"seanime/internal/mediastream/transcoder"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecidePlaybackPlan(t *testing.T) {
+	newInfo := func(ext string, videoCodec string, height uint32, hdr bool, audioCodec string) *videofile.MediaInfo {
+		return &videofile.MediaInfo{
+			Extension: ext,
+			Video:     &videofile.Video{Codec: videoCodec, Height: height, IsHDR: hdr},
+			Audios:    []videofile.Audio{{Codec: audioCodec, IsDefault: true}},
+		}
+	}
+
+	browser := &ClientCapabilities{
+		Containers:  []string{"mp4", "webm"},
+		VideoCodecs: []string{"h264", "vp9", "av1"},
+		AudioCodecs: []string{"aac", "mp3", "opus"},
+		MaxHeight:   1080,
+	}
+
+	tests := []struct {
+		name     string
+		info     *videofile.MediaInfo
+		caps     *ClientCapabilities
+		expected PlaybackPlan
+	}{
+		{"no restriction", newInfo("mkv", "hevc", 2160, false, "flac"), &ClientCapabilities{}, PlaybackPlanDirectPlay},
+		{"supported mp4", newInfo("mp4", "h264", 1080, false, "aac"), browser, PlaybackPlanDirectPlay},
+		{"mkv with copyable streams", newInfo("mkv", "h264", 1080, false, "aac"), browser, PlaybackPlanRemux},
+		{"unsupported audio codec", newInfo("mkv", "h264", 1080, false, "flac"), browser, PlaybackPlanAudioTranscode},
+		{"unsupported video codec", newInfo("mkv", "hevc", 1080, false, "aac"), browser, PlaybackPlanTranscode},
+		{"resolution above maximum", newInfo("mp4", "h264", 2160, false, "aac"), browser, PlaybackPlanTranscode},
+		{"hdr", newInfo("mp4", "h264", 1080, true, "aac"), browser, PlaybackPlanTranscode},
+		{"webm cannot be remuxed", newInfo("webm", "vp9", 1080, false, "opus"), &ClientCapabilities{Containers: []string{"mp4"}}, PlaybackPlanTranscode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, reason := decidePlaybackPlan(tt.info, tt.caps)
+			assert.Equal(t, tt.expected, plan)
+			assert.NotEmpty(t, reason)
+		})
+	}
+}
+
+func TestPlaybackManager_MasterOptionsPerClient(t *testing.T) {
+	pm := NewPlaybackManager(&Repository{logger: util.NewLogger()})
+	mc := &MediaContainer{Filepath: "/anime/episode.mkv", StreamType: StreamTypeTranscode}
+
+	tv := pm.setPlaybackPlan("tv", mc, PlaybackPlanTranscode, "", transcoder.MasterOptions{TranscodeOnly: true, MaxHeight: 720})
+	browser := pm.setPlaybackPlan("browser", mc, PlaybackPlanRemux, "", transcoder.MasterOptions{OriginalOnly: true})
+
+	// The second client does not overwrite the plan of the first one
+	assert.Equal(t, PlaybackPlanTranscode, tv.PlaybackPlan)
+	assert.Equal(t, PlaybackPlanRemux, browser.PlaybackPlan)
+	assert.Empty(t, mc.PlaybackPlan)
+	assert.Equal(t, transcoder.MasterOptions{TranscodeOnly: true, MaxHeight: 720}, pm.getMasterOptions("tv"))
+	assert.Equal(t, transcoder.MasterOptions{OriginalOnly: true}, pm.getMasterOptions("browser"))
+
+	pm.clearPlaybackPlan("tv")
+	assert.Equal(t, transcoder.MasterOptions{}, pm.getMasterOptions("tv"))
+}
+
+func TestCanPlayOptimized(t *testing.T) {
+	assert.True(t, canPlayOptimized(&ClientCapabilities{}))
+	assert.True(t, canPlayOptimized(&ClientCapabilities{Containers: []string{"mp4"}, VideoCodecs: []string{"h264", "hevc"}, AudioCodecs: []string{"aac"}}))
+	assert.False(t, canPlayOptimized(&ClientCapabilities{Containers: []string{"webm"}}))
+	assert.False(t, canPlayOptimized(&ClientCapabilities{AudioCodecs: []string{"opus"}}))
+}
@@ -30,6 +30,14 @@ func (r *Repository) ServeEchoFile(c echo.Context, rawFilePath string, clientId
 		}
 	}
 
+	// DLNA renderers ask for the content features before streaming, this allows seeking with byte ranges
+	if c.Request().Header.Get("getcontentFeatures.dlna.org") == "1" {
+		c.Response().Header().Set("contentFeatures.dlna.org", "DLNA.ORG_OP=01;DLNA.ORG_CI=0;DLNA.ORG_FLAGS=01700000000000000000000000000000")
+	}
+	if c.Request().Header.Get("transferMode.dlna.org") != "" {
+		c.Response().Header().Set("transferMode.dlna.org", "Streaming")
+	}
+
 	r.logger.Trace().Str("filepath", filePath).Str("payload", rawFilePath).Msg("mediastream: Served file")
 	return c.File(filePath)
 }
@@ -48,11 +56,17 @@ func (r *Repository) ServeEchoDirectPlay(c echo.Context, clientId string) error
 		return errors.New("no file has been loaded")
 	}
 
+	// The pre-transcoded version is served instead of the original file
+	path := mediaContainer.Filepath
+	if mediaContainer.StreamType == StreamTypeOptimized {
+		path = mediaContainer.optimizedPath
+	}
+
 	if c.Request().Method == http.MethodHead {
 		r.logger.Trace().Msg("mediastream: Received HEAD request for direct play")
 
 		// Get the file size
-		fileInfo, err := os.Stat(mediaContainer.Filepath)
+		fileInfo, err := os.Stat(path)
 		if err != nil {
 			r.logger.Error().Msg("mediastream: Failed to get file info")
 			return c.NoContent(http.StatusInternalServerError)
@@ -66,5 +80,5 @@ func (r *Repository) ServeEchoDirectPlay(c echo.Context, clientId string) error
 		return c.NoContent(http.StatusOK)
 	}
 
-	return c.File(mediaContainer.Filepath)
+	return c.File(path)
 }
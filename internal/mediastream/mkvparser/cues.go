@@ -0,0 +1,103 @@
+package mkvparser
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+)
+
+var ErrNoCues = errors.New("mkvparser: the file has no cues")
+
+// CuePoint is an entry of the index of the file.
+type CuePoint struct {
+	Time time.Duration
+	// Offset of the cluster containing the time
+	ClusterOffset int64
+}
+
+// ReadCues reads the index of the file, sorted by time.
+// The cues are used to read the subtitle blocks of a time range without reading the whole file.
+func (p *Parser) ReadCues(m *Metadata) ([]*CuePoint, error) {
+	if p.cuesOffset == 0 {
+		return nil, ErrNoCues
+	}
+	if err := p.r.seek(p.cuesOffset); err != nil {
+		return nil, err
+	}
+	h, err := p.r.readElementHeader()
+	if err != nil {
+		return nil, err
+	}
+	if h.id != idCues {
+		return nil, ErrNoCues
+	}
+
+	ret := make([]*CuePoint, 0)
+	err = p.parseChildren(h, func(child *elementHeader) error {
+		if child.id != idCuePoint {
+			return nil
+		}
+		var cueTime uint64
+		var clusterPosition int64 = -1
+		err := p.parseChildren(child, func(cueChild *elementHeader) error {
+			switch cueChild.id {
+			case idCueTime:
+				v, err := p.r.readUint(cueChild)
+				if err != nil {
+					return err
+				}
+				cueTime = v
+			case idCueTrackPositions:
+				if clusterPosition >= 0 {
+					return nil
+				}
+				return p.parseChildren(cueChild, func(c *elementHeader) error {
+					if c.id != idCueClusterPosition {
+						return nil
+					}
+					v, err := p.r.readUint(c)
+					if err != nil {
+						return err
+					}
+					clusterPosition = int64(v)
+					return nil
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if clusterPosition >= 0 {
+			ret = append(ret, &CuePoint{
+				Time: time.Duration(cueTime * m.TimecodeScale),
+				// Positions are relative to the beginning of the segment's data
+				ClusterOffset: p.segmentDataOffset + clusterPosition,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(ret, func(a, b *CuePoint) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+
+	return ret, nil
+}
+
+// FindCluster returns the offset of the last cluster starting at or before t.
+// It returns 0, which is the beginning of the clusters, if no cue is before t.
+func FindCluster(cues []*CuePoint, t time.Duration) int64 {
+	var ret int64
+	for _, cue := range cues {
+		if cue.Time > t {
+			break
+		}
+		ret = cue.ClusterOffset
+	}
+	return ret
+}
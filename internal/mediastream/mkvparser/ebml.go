@@ -0,0 +1,271 @@
+package mkvparser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Matroska element IDs
+// https://www.matroska.org/technical/elements.html
+const (
+	idEBML = 0x1A45DFA3
+
+	idSegment = 0x18538067
+
+	idSeekHead     = 0x114D9B74
+	idSeek         = 0x4DBB
+	idSeekID       = 0x53AB
+	idSeekPosition = 0x53AC
+
+	idInfo          = 0x1549A966
+	idTimecodeScale = 0x2AD7B1
+
+	idTracks               = 0x1654AE6B
+	idTrackEntry           = 0xAE
+	idTrackNumber          = 0xD7
+	idTrackType            = 0x83
+	idCodecID              = 0x86
+	idCodecPrivate         = 0x63A2
+	idLanguage             = 0x22B59C
+	idLanguageBCP47        = 0x22B59D
+	idName                 = 0x536E
+	idFlagDefault          = 0x88
+	idFlagForced           = 0x55AA
+	idContentEncodings     = 0x6D80
+	idContentEncoding      = 0x6240
+	idContentCompression   = 0x5034
+	idContentCompAlgo      = 0x4254
+	idContentCompSettings  = 0x4255
+	idContentEncodingScope = 0x5032
+	idAttachments          = 0x1941A469
+	idAttachedFile         = 0x61A7
+	idFileName             = 0x466E
+	idFileMimeType         = 0x4660
+	idFileData             = 0x465C
+	idCluster              = 0x1F43B675
+	idClusterTimecode      = 0xE7
+	idSimpleBlock          = 0xA3
+	idBlockGroup           = 0xA0
+	idBlock                = 0xA1
+	idBlockDuration        = 0x9B
+	idCues                 = 0x1C53BB6B
+	idCuePoint             = 0xBB
+	idCueTime              = 0xB3
+	idCueTrackPositions    = 0xB7
+	idCueClusterPosition   = 0xF1
+	idChapters             = 0x1043A770
+	idTags                 = 0x1254C367
+)
+
+// unknownSize is the size of elements whose size is not known, e.g. live streams
+const unknownSize int64 = -1
+
+var errInvalidVint = errors.New("mkvparser: invalid variable size integer")
+
+// isTopLevelID returns true if the ID is a child of the Segment element.
+// It is used to detect the end of clusters with an unknown size.
+func isTopLevelID(id uint32) bool {
+	switch id {
+	case idSeekHead, idInfo, idTracks, idAttachments, idCluster, idCues, idChapters, idTags:
+		return true
+	}
+	return false
+}
+
+// elementHeader is the ID and size of an element, and the offsets of the element and its data.
+type elementHeader struct {
+	id         uint32
+	size       int64 // unknownSize if the size is unknown
+	offset     int64
+	dataOffset int64
+}
+
+func (h *elementHeader) end() int64 {
+	if h.size == unknownSize {
+		return unknownSize
+	}
+	return h.dataOffset + h.size
+}
+
+// ebmlReader reads EBML elements and keeps track of the position in the stream.
+type ebmlReader struct {
+	r   io.ReadSeeker
+	pos int64
+	buf [8]byte
+}
+
+func newEBMLReader(r io.ReadSeeker) *ebmlReader {
+	return &ebmlReader{r: r}
+}
+
+func (e *ebmlReader) seek(offset int64) error {
+	if offset == e.pos {
+		return nil
+	}
+	n, err := e.r.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	e.pos = n
+	return nil
+}
+
+func (e *ebmlReader) read(p []byte) error {
+	n, err := io.ReadFull(e.r, p)
+	e.pos += int64(n)
+	return err
+}
+
+func (e *ebmlReader) readByte() (byte, error) {
+	if err := e.read(e.buf[:1]); err != nil {
+		return 0, err
+	}
+	return e.buf[0], nil
+}
+
+// readID reads an element ID, the length marker is kept.
+func (e *ebmlReader) readID() (uint32, error) {
+	first, err := e.readByte()
+	if err != nil {
+		return 0, err
+	}
+	length := vintLength(first)
+	if length == 0 || length > 4 {
+		return 0, errInvalidVint
+	}
+	id := uint32(first)
+	if length > 1 {
+		if err := e.read(e.buf[:length-1]); err != nil {
+			return 0, err
+		}
+		for _, b := range e.buf[:length-1] {
+			id = id<<8 | uint32(b)
+		}
+	}
+	return id, nil
+}
+
+// readSize reads an element size, the length marker is removed.
+func (e *ebmlReader) readSize() (int64, error) {
+	first, err := e.readByte()
+	if err != nil {
+		return 0, err
+	}
+	length := vintLength(first)
+	if length == 0 {
+		return 0, errInvalidVint
+	}
+	value := uint64(first & (0xFF >> length))
+	allOnes := value == uint64(0xFF>>length)
+	if length > 1 {
+		if err := e.read(e.buf[:length-1]); err != nil {
+			return 0, err
+		}
+		for _, b := range e.buf[:length-1] {
+			value = value<<8 | uint64(b)
+			allOnes = allOnes && b == 0xFF
+		}
+	}
+	if allOnes {
+		return unknownSize, nil
+	}
+	if value > math.MaxInt64 {
+		return 0, errInvalidVint
+	}
+	return int64(value), nil
+}
+
+func (e *ebmlReader) readElementHeader() (*elementHeader, error) {
+	offset := e.pos
+	id, err := e.readID()
+	if err != nil {
+		return nil, err
+	}
+	size, err := e.readSize()
+	if err != nil {
+		return nil, err
+	}
+	return &elementHeader{id: id, size: size, offset: offset, dataOffset: e.pos}, nil
+}
+
+// readData reads the data of the element, up to maxSize bytes.
+func (e *ebmlReader) readData(h *elementHeader, maxSize int64) ([]byte, error) {
+	if h.size == unknownSize || h.size > maxSize {
+		return nil, fmt.Errorf("mkvparser: element 0x%X is too large (%d bytes)", h.id, h.size)
+	}
+	data := make([]byte, h.size)
+	if err := e.read(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (e *ebmlReader) readUint(h *elementHeader) (uint64, error) {
+	data, err := e.readData(h, 8)
+	if err != nil {
+		return 0, err
+	}
+	return decodeUint(data), nil
+}
+
+func (e *ebmlReader) readString(h *elementHeader) (string, error) {
+	data, err := e.readData(h, maxStringSize)
+	if err != nil {
+		return "", err
+	}
+	// Strings can be padded with null bytes
+	for len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+	return string(data), nil
+}
+
+// skip moves to the end of the element.
+func (e *ebmlReader) skip(h *elementHeader) error {
+	if h.size == unknownSize {
+		return fmt.Errorf("mkvparser: cannot skip element 0x%X of unknown size", h.id)
+	}
+	return e.seek(h.end())
+}
+
+// vintLength returns the length of a variable size integer from its first byte, 0 if invalid.
+func vintLength(first byte) int {
+	for i := 0; i < 8; i++ {
+		if first&(0x80>>i) != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// decodeVint decodes a variable size integer from the beginning of data.
+// It returns the value and the number of bytes read.
+func decodeVint(data []byte) (uint64, int, error) {
+	if len(data) == 0 {
+		return 0, 0, errInvalidVint
+	}
+	length := vintLength(data[0])
+	if length == 0 || len(data) < length {
+		return 0, 0, errInvalidVint
+	}
+	value := uint64(data[0] & (0xFF >> length))
+	for _, b := range data[1:length] {
+		value = value<<8 | uint64(b)
+	}
+	return value, length, nil
+}
+
+func decodeUint(data []byte) uint64 {
+	var value uint64
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+func decodeInt16(data []byte) int16 {
+	return int16(binary.BigEndian.Uint16(data))
+}
@@ -0,0 +1,662 @@
+package mkvparser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// TrackTypeSubtitle is the Matroska track type of subtitle tracks.
+	TrackTypeSubtitle = 17
+
+	defaultTimecodeScale = 1_000_000 // 1ms
+
+	maxStringSize       = 1 << 20  // 1MB
+	maxCodecPrivateSize = 16 << 20 // 16MB
+	maxBlockSize        = 16 << 20 // 16MB
+	// blockHeaderSize is enough to read the track number, timecode and flags of a block
+	blockHeaderSize = 12
+)
+
+var ErrNotMatroska = errors.New("mkvparser: not a Matroska file")
+
+type (
+	// Parser reads the metadata, attachments and subtitles of a Matroska file.
+	// The parser only reads the parts of the file it needs, the rest is skipped by seeking.
+	Parser struct {
+		r *ebmlReader
+
+		segmentDataOffset  int64
+		segmentEnd         int64 // unknownSize if the size of the segment is unknown
+		firstClusterOffset int64
+		cuesOffset         int64 // 0 if the file has no cues
+	}
+
+	Metadata struct {
+		TimecodeScale uint64
+		Tracks        []*Track
+		Attachments   []*Attachment
+	}
+
+	Track struct {
+		Number       uint64
+		Type         uint64
+		CodecID      string
+		CodecPrivate []byte
+		Language     string
+		Name         string
+		IsDefault    bool
+		IsForced     bool
+
+		// Compression applied to the blocks of the track
+		compression         contentCompression
+		compressionSettings []byte
+	}
+
+	Attachment struct {
+		Filename string
+		MimeType string
+		Size     int64
+
+		dataOffset int64
+	}
+
+	// SubtitleEvent is a subtitle block.
+	SubtitleEvent struct {
+		TrackNumber uint64
+		StartTime   time.Duration
+		Duration    time.Duration
+		Data        []byte
+	}
+
+	contentCompression int
+)
+
+const (
+	compressionNone contentCompression = iota
+	compressionZlib
+	compressionHeaderStripping
+)
+
+func NewParser(r io.ReadSeeker) *Parser {
+	return &Parser{
+		r:          newEBMLReader(r),
+		segmentEnd: unknownSize,
+	}
+}
+
+// IsSubtitle returns true if the track is a subtitle track.
+func (t *Track) IsSubtitle() bool {
+	return t.Type == TrackTypeSubtitle
+}
+
+// SubtitleTracks returns the subtitle tracks.
+func (m *Metadata) SubtitleTracks() []*Track {
+	ret := make([]*Track, 0)
+	for _, t := range m.Tracks {
+		if t.IsSubtitle() {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+func (m *Metadata) getTrack(number uint64) (*Track, bool) {
+	for _, t := range m.Tracks {
+		if t.Number == number {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// ReadMetadata reads the tracks and the attachments of the file.
+// Elements located after the first cluster are found using the SeekHead.
+func (p *Parser) ReadMetadata() (*Metadata, error) {
+	if err := p.r.seek(0); err != nil {
+		return nil, err
+	}
+
+	// EBML header
+	h, err := p.r.readElementHeader()
+	if err != nil || h.id != idEBML {
+		return nil, ErrNotMatroska
+	}
+	if err := p.r.skip(h); err != nil {
+		return nil, err
+	}
+
+	// Segment
+	h, err = p.r.readElementHeader()
+	if err != nil || h.id != idSegment {
+		return nil, ErrNotMatroska
+	}
+	p.segmentDataOffset = h.dataOffset
+	p.segmentEnd = h.end()
+
+	ret := &Metadata{
+		TimecodeScale: defaultTimecodeScale,
+	}
+
+	seekPositions := make(map[uint32]int64)
+	parsed := make(map[uint32]bool)
+
+	for {
+		if p.segmentEnd != unknownSize && p.r.pos >= p.segmentEnd {
+			break
+		}
+		h, err := p.r.readElementHeader()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, err
+		}
+
+		if h.id == idCluster {
+			p.firstClusterOffset = h.offset
+			break
+		}
+		if h.id == idCues {
+			p.cuesOffset = h.offset
+		}
+
+		if err := p.parseTopLevelElement(h, ret, seekPositions); err != nil {
+			return nil, err
+		}
+		parsed[h.id] = true
+
+		if h.size == unknownSize {
+			break
+		}
+		if err := p.r.seek(h.end()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse the elements that are located after the first cluster
+	for _, id := range []uint32{idTracks, idAttachments, idInfo} {
+		pos, ok := seekPositions[id]
+		if !ok || parsed[id] {
+			continue
+		}
+		if err := p.r.seek(pos); err != nil {
+			return nil, err
+		}
+		h, err := p.r.readElementHeader()
+		if err != nil || h.id != id {
+			continue
+		}
+		if err := p.parseTopLevelElement(h, ret, seekPositions); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.firstClusterOffset == 0 {
+		if pos, ok := seekPositions[idCluster]; ok {
+			p.firstClusterOffset = pos
+		}
+	}
+	if p.cuesOffset == 0 {
+		if pos, ok := seekPositions[idCues]; ok {
+			p.cuesOffset = pos
+		}
+	}
+
+	return ret, nil
+}
+
+func (p *Parser) parseTopLevelElement(h *elementHeader, m *Metadata, seekPositions map[uint32]int64) error {
+	switch h.id {
+	case idSeekHead:
+		return p.parseSeekHead(h, seekPositions)
+	case idInfo:
+		return p.parseChildren(h, func(child *elementHeader) error {
+			if child.id == idTimecodeScale {
+				scale, err := p.r.readUint(child)
+				if err != nil {
+					return err
+				}
+				if scale > 0 {
+					m.TimecodeScale = scale
+				}
+			}
+			return nil
+		})
+	case idTracks:
+		return p.parseChildren(h, func(child *elementHeader) error {
+			if child.id != idTrackEntry {
+				return nil
+			}
+			track, err := p.parseTrackEntry(child)
+			if err != nil {
+				return err
+			}
+			m.Tracks = append(m.Tracks, track)
+			return nil
+		})
+	case idAttachments:
+		return p.parseChildren(h, func(child *elementHeader) error {
+			if child.id != idAttachedFile {
+				return nil
+			}
+			attachment, err := p.parseAttachedFile(child)
+			if err != nil {
+				return err
+			}
+			m.Attachments = append(m.Attachments, attachment)
+			return nil
+		})
+	}
+	return nil
+}
+
+func (p *Parser) parseSeekHead(h *elementHeader, seekPositions map[uint32]int64) error {
+	return p.parseChildren(h, func(child *elementHeader) error {
+		if child.id != idSeek {
+			return nil
+		}
+		var id uint32
+		var pos int64 = -1
+		err := p.parseChildren(child, func(seekChild *elementHeader) error {
+			switch seekChild.id {
+			case idSeekID:
+				data, err := p.r.readData(seekChild, 4)
+				if err != nil {
+					return err
+				}
+				id = uint32(decodeUint(data))
+			case idSeekPosition:
+				v, err := p.r.readUint(seekChild)
+				if err != nil {
+					return err
+				}
+				pos = int64(v)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if id != 0 && pos >= 0 {
+			// Positions are relative to the beginning of the segment's data
+			seekPositions[id] = p.segmentDataOffset + pos
+		}
+		return nil
+	})
+}
+
+func (p *Parser) parseTrackEntry(h *elementHeader) (*Track, error) {
+	ret := &Track{
+		IsDefault: true, // FlagDefault defaults to 1
+	}
+	err := p.parseChildren(h, func(child *elementHeader) (err error) {
+		switch child.id {
+		case idTrackNumber:
+			ret.Number, err = p.r.readUint(child)
+		case idTrackType:
+			ret.Type, err = p.r.readUint(child)
+		case idCodecID:
+			ret.CodecID, err = p.r.readString(child)
+		case idCodecPrivate:
+			ret.CodecPrivate, err = p.r.readData(child, maxCodecPrivateSize)
+		case idLanguage:
+			if ret.Language == "" {
+				ret.Language, err = p.r.readString(child)
+			}
+		case idLanguageBCP47:
+			// Takes precedence over Language
+			ret.Language, err = p.r.readString(child)
+		case idName:
+			ret.Name, err = p.r.readString(child)
+		case idFlagDefault:
+			var v uint64
+			v, err = p.r.readUint(child)
+			ret.IsDefault = v != 0
+		case idFlagForced:
+			var v uint64
+			v, err = p.r.readUint(child)
+			ret.IsForced = v != 0
+		case idContentEncodings:
+			err = p.parseContentEncodings(child, ret)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (p *Parser) parseContentEncodings(h *elementHeader, track *Track) error {
+	return p.parseChildren(h, func(encoding *elementHeader) error {
+		if encoding.id != idContentEncoding {
+			return nil
+		}
+		// Scope defaults to 1 (all frame contents)
+		scope := uint64(1)
+		compression := compressionNone
+		var settings []byte
+		isCompressed := false
+		err := p.parseChildren(encoding, func(child *elementHeader) error {
+			switch child.id {
+			case idContentEncodingScope:
+				v, err := p.r.readUint(child)
+				if err != nil {
+					return err
+				}
+				scope = v
+			case idContentCompression:
+				isCompressed = true
+				compression = compressionZlib // ContentCompAlgo defaults to 0 (zlib)
+				return p.parseChildren(child, func(c *elementHeader) error {
+					switch c.id {
+					case idContentCompAlgo:
+						algo, err := p.r.readUint(c)
+						if err != nil {
+							return err
+						}
+						switch algo {
+						case 0:
+							compression = compressionZlib
+						case 3:
+							compression = compressionHeaderStripping
+						default:
+							return fmt.Errorf("mkvparser: unsupported compression algorithm %d", algo)
+						}
+					case idContentCompSettings:
+						data, err := p.r.readData(c, maxStringSize)
+						if err != nil {
+							return err
+						}
+						settings = data
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if isCompressed && scope&1 != 0 {
+			track.compression = compression
+			track.compressionSettings = settings
+		}
+		return nil
+	})
+}
+
+func (p *Parser) parseAttachedFile(h *elementHeader) (*Attachment, error) {
+	ret := &Attachment{}
+	err := p.parseChildren(h, func(child *elementHeader) (err error) {
+		switch child.id {
+		case idFileName:
+			ret.Filename, err = p.r.readString(child)
+		case idFileMimeType:
+			ret.MimeType, err = p.r.readString(child)
+		case idFileData:
+			// The data is read on demand
+			ret.dataOffset = child.dataOffset
+			ret.Size = child.size
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// parseChildren calls fn for each child of the element.
+// fn does not need to read the whole element, the reader is moved to the next child after each call.
+func (p *Parser) parseChildren(h *elementHeader, fn func(child *elementHeader) error) error {
+	if h.size == unknownSize {
+		return fmt.Errorf("mkvparser: element 0x%X has an unknown size", h.id)
+	}
+	end := h.end()
+	if err := p.r.seek(h.dataOffset); err != nil {
+		return err
+	}
+	for p.r.pos < end {
+		child, err := p.r.readElementHeader()
+		if err != nil {
+			return err
+		}
+		if child.size == unknownSize || child.end() > end {
+			return fmt.Errorf("mkvparser: invalid size for element 0x%X", child.id)
+		}
+		if err := fn(child); err != nil {
+			return err
+		}
+		if err := p.r.seek(child.end()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAttachment returns the data of the attachment.
+func (p *Parser) ReadAttachment(a *Attachment) ([]byte, error) {
+	if a.Size <= 0 {
+		return []byte{}, nil
+	}
+	if err := p.r.seek(a.dataOffset); err != nil {
+		return nil, err
+	}
+	data := make([]byte, a.Size)
+	if err := p.r.read(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadSubtitleEvents reads the clusters from the beginning and calls onEvent for each subtitle block.
+// Blocks of other tracks are skipped by seeking. It returns nil when the end of the file is reached.
+func (p *Parser) ReadSubtitleEvents(ctx context.Context, m *Metadata, onEvent func(*SubtitleEvent) error) error {
+	_, err := p.ReadSubtitleEventsUntil(ctx, m, 0, -1, onEvent)
+	return err
+}
+
+// ReadSubtitleEventsUntil reads the clusters from the cluster at offset and calls onEvent for each subtitle block.
+// If offset is 0, the clusters are read from the beginning.
+// Reading stops at the first cluster starting after until, a negative value reads the clusters until the end of the file.
+// It returns true if the end of the file was reached.
+func (p *Parser) ReadSubtitleEventsUntil(ctx context.Context, m *Metadata, offset int64, until time.Duration, onEvent func(*SubtitleEvent) error) (reachedEnd bool, err error) {
+	if p.firstClusterOffset == 0 {
+		return true, nil
+	}
+	if len(m.SubtitleTracks()) == 0 {
+		return true, nil
+	}
+
+	if offset == 0 {
+		offset = p.firstClusterOffset
+	}
+	if err := p.r.seek(offset); err != nil {
+		return false, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		default:
+		}
+
+		if p.segmentEnd != unknownSize && p.r.pos >= p.segmentEnd {
+			return true, nil
+		}
+
+		h, err := p.r.readElementHeader()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return true, nil
+			}
+			return false, err
+		}
+
+		if h.id != idCluster {
+			if h.size == unknownSize {
+				return true, nil
+			}
+			if err := p.r.skip(h); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		stop, err := p.readCluster(h, m, until, onEvent)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return true, nil
+			}
+			return false, err
+		}
+		if stop {
+			return false, nil
+		}
+	}
+}
+
+// readCluster reads the subtitle blocks of a cluster.
+// Clusters with an unknown size end when the next top-level element is found.
+// It returns true without reading the blocks if the cluster starts after until.
+func (p *Parser) readCluster(h *elementHeader, m *Metadata, until time.Duration, onEvent func(*SubtitleEvent) error) (bool, error) {
+	end := h.end()
+	var clusterTimecode uint64
+
+	for end == unknownSize || p.r.pos < end {
+		child, err := p.r.readElementHeader()
+		if err != nil {
+			return false, err
+		}
+
+		if end == unknownSize && isTopLevelID(child.id) {
+			// Go back to the beginning of the element so that it's read by the caller
+			return false, p.r.seek(child.offset)
+		}
+		if child.size == unknownSize {
+			return false, fmt.Errorf("mkvparser: element 0x%X has an unknown size", child.id)
+		}
+
+		switch child.id {
+		case idClusterTimecode:
+			clusterTimecode, err = p.r.readUint(child)
+			if err != nil {
+				return false, err
+			}
+			if until >= 0 && time.Duration(clusterTimecode*m.TimecodeScale) > until {
+				return true, nil
+			}
+		case idSimpleBlock:
+			if err := p.readBlock(child, m, clusterTimecode, 0, onEvent); err != nil {
+				return false, err
+			}
+		case idBlockGroup:
+			if err := p.readBlockGroup(child, m, clusterTimecode, onEvent); err != nil {
+				return false, err
+			}
+		}
+
+		if err := p.r.seek(child.end()); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func (p *Parser) readBlockGroup(h *elementHeader, m *Metadata, clusterTimecode uint64, onEvent func(*SubtitleEvent) error) error {
+	var block *elementHeader
+	var duration uint64
+	err := p.parseChildren(h, func(child *elementHeader) (err error) {
+		switch child.id {
+		case idBlock:
+			block = child
+		case idBlockDuration:
+			duration, err = p.r.readUint(child)
+		}
+		return err
+	})
+	if err != nil || block == nil {
+		return err
+	}
+	return p.readBlock(block, m, clusterTimecode, duration, onEvent)
+}
+
+// readBlock reads the block if it belongs to a subtitle track.
+func (p *Parser) readBlock(h *elementHeader, m *Metadata, clusterTimecode uint64, duration uint64, onEvent func(*SubtitleEvent) error) error {
+	if err := p.r.seek(h.dataOffset); err != nil {
+		return err
+	}
+
+	header := make([]byte, min(h.size, blockHeaderSize))
+	if err := p.r.read(header); err != nil {
+		return err
+	}
+
+	trackNumber, n, err := decodeVint(header)
+	if err != nil || len(header) < n+3 {
+		return nil
+	}
+	track, ok := m.getTrack(trackNumber)
+	if !ok || !track.IsSubtitle() {
+		return nil
+	}
+
+	relativeTimecode := decodeInt16(header[n : n+2])
+	flags := header[n+2]
+	// Laced subtitle blocks are not supported
+	if flags&0x06 != 0 {
+		return nil
+	}
+
+	if h.size > maxBlockSize {
+		return nil
+	}
+	dataOffset := h.dataOffset + int64(n+3)
+	if err := p.r.seek(dataOffset); err != nil {
+		return err
+	}
+	data := make([]byte, h.end()-dataOffset)
+	if err := p.r.read(data); err != nil {
+		return err
+	}
+
+	data, err = track.decompress(data)
+	if err != nil {
+		return nil
+	}
+
+	timecode := int64(clusterTimecode) + int64(relativeTimecode)
+	if timecode < 0 {
+		timecode = 0
+	}
+
+	return onEvent(&SubtitleEvent{
+		TrackNumber: trackNumber,
+		StartTime:   time.Duration(timecode * int64(m.TimecodeScale)),
+		Duration:    time.Duration(int64(duration) * int64(m.TimecodeScale)),
+		Data:        data,
+	})
+}
+
+func (t *Track) decompress(data []byte) ([]byte, error) {
+	switch t.compression {
+	case compressionZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case compressionHeaderStripping:
+		return append(append([]byte{}, t.compressionSettings...), data...), nil
+	}
+	return data, nil
+}
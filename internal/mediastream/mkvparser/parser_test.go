@@ -0,0 +1,235 @@
+package mkvparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// element encodes an EBML element, the size is always encoded on 8 bytes.
+func element(id uint32, children ...[]byte) []byte {
+	data := bytes.Join(children, nil)
+	ret := idBytes(id)
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(data)))
+	size[0] = 0x01
+	ret = append(ret, size...)
+	return append(ret, data...)
+}
+
+// unknownSizeElement encodes an EBML element with an unknown size.
+func unknownSizeElement(id uint32, children ...[]byte) []byte {
+	ret := idBytes(id)
+	ret = append(ret, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)
+	return append(ret, bytes.Join(children, nil)...)
+}
+
+func idBytes(id uint32) []byte {
+	switch {
+	case id <= 0xFF:
+		return []byte{byte(id)}
+	case id <= 0xFFFF:
+		return []byte{byte(id >> 8), byte(id)}
+	case id <= 0xFFFFFF:
+		return []byte{byte(id >> 16), byte(id >> 8), byte(id)}
+	}
+	return []byte{byte(id >> 24), byte(id >> 16), byte(id >> 8), byte(id)}
+}
+
+func uintData(v uint64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, v)
+	return data
+}
+
+func block(track byte, relativeTimecode int16, data string) []byte {
+	ret := []byte{0x80 | track, byte(uint16(relativeTimecode) >> 8), byte(relativeTimecode), 0x00}
+	return append(ret, data...)
+}
+
+func testFile() []byte {
+	assHeader := "[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\nFormat: Name, Fontname\nStyle: Default,Arial\n\n[Events]\n" + assEventsFormat + "\n"
+
+	return bytes.Join([][]byte{
+		element(idEBML, element(0x4282, []byte("matroska"))),
+		element(idSegment,
+			element(idInfo, element(idTimecodeScale, uintData(1_000_000))),
+			element(idTracks,
+				element(idTrackEntry,
+					element(idTrackNumber, uintData(1)),
+					element(idTrackType, uintData(1)),
+					element(idCodecID, []byte("V_MPEG4/ISO/AVC")),
+				),
+				element(idTrackEntry,
+					element(idTrackNumber, uintData(2)),
+					element(idTrackType, uintData(TrackTypeSubtitle)),
+					element(idCodecID, []byte("S_TEXT/ASS")),
+					element(idCodecPrivate, []byte(assHeader)),
+					element(idLanguage, []byte("jpn")),
+					element(idName, []byte("Full Subtitles")),
+				),
+				element(idTrackEntry,
+					element(idTrackNumber, uintData(3)),
+					element(idTrackType, uintData(TrackTypeSubtitle)),
+					element(idCodecID, []byte("S_TEXT/UTF8")),
+					element(idFlagDefault, uintData(0)),
+				),
+			),
+			element(idAttachments,
+				element(idAttachedFile,
+					element(idFileName, []byte("font.ttf")),
+					element(idFileMimeType, []byte("font/ttf")),
+					element(idFileData, []byte("FONTDATA")),
+				),
+			),
+			element(idCluster,
+				element(idClusterTimecode, uintData(1000)),
+				element(idSimpleBlock, block(1, 0, string(make([]byte, 100)))),
+				element(idBlockGroup,
+					element(idBlock, block(2, 500, "0,0,Default,,0,0,0,,Hello")),
+					element(idBlockDuration, uintData(2000)),
+				),
+				element(idBlockGroup,
+					element(idBlock, block(3, 0, "Hi")),
+					element(idBlockDuration, uintData(1500)),
+				),
+			),
+			unknownSizeElement(idCluster,
+				element(idClusterTimecode, uintData(5000)),
+				element(idSimpleBlock, block(1, 0, "video")),
+				element(idBlockGroup,
+					element(idBlock, block(2, 0, "1,0,Default,,0,0,0,,World")),
+					element(idBlockDuration, uintData(1000)),
+				),
+			),
+		),
+	}, nil)
+}
+
+func TestParser(t *testing.T) {
+	parser := NewParser(bytes.NewReader(testFile()))
+
+	metadata, err := parser.ReadMetadata()
+	require.NoError(t, err)
+
+	require.Len(t, metadata.Tracks, 3)
+	subtitleTracks := metadata.SubtitleTracks()
+	require.Len(t, subtitleTracks, 2)
+	assert.Equal(t, "S_TEXT/ASS", subtitleTracks[0].CodecID)
+	assert.Equal(t, "jpn", subtitleTracks[0].Language)
+	assert.Equal(t, "Full Subtitles", subtitleTracks[0].Name)
+	assert.True(t, subtitleTracks[0].IsDefault)
+	assert.False(t, subtitleTracks[1].IsDefault)
+
+	require.Len(t, metadata.Attachments, 1)
+	assert.Equal(t, "font.ttf", metadata.Attachments[0].Filename)
+	data, err := parser.ReadAttachment(metadata.Attachments[0])
+	require.NoError(t, err)
+	assert.Equal(t, "FONTDATA", string(data))
+
+	files := make(map[uint64]*SubtitleFile)
+	for _, track := range subtitleTracks {
+		f, ok := NewSubtitleFile(track)
+		require.True(t, ok)
+		files[track.Number] = f
+	}
+
+	err = parser.ReadSubtitleEvents(context.Background(), metadata, func(e *SubtitleEvent) error {
+		files[e.TrackNumber].AddEvent(e)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assFile := string(files[2].Bytes())
+	assert.Contains(t, assFile, "[V4+ Styles]")
+	assert.Contains(t, assFile, "Dialogue: 0,0:00:01.50,0:00:03.50,Default,,0,0,0,,Hello\n")
+	assert.Contains(t, assFile, "Dialogue: 0,0:00:05.00,0:00:06.00,Default,,0,0,0,,World\n")
+
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,500\nHi\n\n", string(files[3].Bytes()))
+}
+
+func TestParser_NotMatroska(t *testing.T) {
+	_, err := NewParser(bytes.NewReader([]byte("not a matroska file"))).ReadMetadata()
+	assert.ErrorIs(t, err, ErrNotMatroska)
+}
+
+func TestParser_ReadSubtitleEventsUntil(t *testing.T) {
+	tracks := element(idTracks,
+		element(idTrackEntry,
+			element(idTrackNumber, uintData(1)),
+			element(idTrackType, uintData(TrackTypeSubtitle)),
+			element(idCodecID, []byte("S_TEXT/UTF8")),
+		),
+	)
+	clusters := [][]byte{
+		element(idCluster,
+			element(idClusterTimecode, uintData(0)),
+			element(idBlockGroup, element(idBlock, block(1, 0, "First")), element(idBlockDuration, uintData(1000))),
+		),
+		element(idCluster,
+			element(idClusterTimecode, uintData(60_000)),
+			element(idBlockGroup, element(idBlock, block(1, 0, "Second")), element(idBlockDuration, uintData(1000))),
+		),
+		element(idCluster,
+			element(idClusterTimecode, uintData(120_000)),
+			element(idBlockGroup, element(idBlock, block(1, 0, "Third")), element(idBlockDuration, uintData(1000))),
+		),
+	}
+
+	// The cues are located before the clusters, their size does not depend on the positions
+	cues := func(positions []int) []byte {
+		points := make([][]byte, 0)
+		for i, pos := range positions {
+			points = append(points, element(idCuePoint,
+				element(idCueTime, uintData(uint64(i*60_000))),
+				element(idCueTrackPositions, element(idCueClusterPosition, uintData(uint64(pos)))),
+			))
+		}
+		return element(idCues, points...)
+	}
+	positions := make([]int, len(clusters))
+	pos := len(tracks) + len(cues(positions))
+	for i, c := range clusters {
+		positions[i] = pos
+		pos += len(c)
+	}
+
+	file := bytes.Join([][]byte{
+		element(idEBML, element(0x4282, []byte("matroska"))),
+		element(idSegment, append([][]byte{tracks, cues(positions)}, clusters...)...),
+	}, nil)
+
+	parser := NewParser(bytes.NewReader(file))
+	metadata, err := parser.ReadMetadata()
+	require.NoError(t, err)
+
+	cuePoints, err := parser.ReadCues(metadata)
+	require.NoError(t, err)
+	require.Len(t, cuePoints, 3)
+	assert.Equal(t, time.Minute, cuePoints[1].Time)
+
+	// Only the second cluster is read
+	events := make([]string, 0)
+	reachedEnd, err := parser.ReadSubtitleEventsUntil(context.Background(), metadata, FindCluster(cuePoints, 90*time.Second), 90*time.Second, func(e *SubtitleEvent) error {
+		events = append(events, string(e.Data))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, reachedEnd)
+	assert.Equal(t, []string{"Second"}, events)
+
+	// Reading until the end
+	events = events[:0]
+	reachedEnd, err = parser.ReadSubtitleEventsUntil(context.Background(), metadata, FindCluster(cuePoints, 2*time.Minute), -1, func(e *SubtitleEvent) error {
+		events = append(events, string(e.Data))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, reachedEnd)
+	assert.Equal(t, []string{"Third"}, events)
+}
@@ -0,0 +1,168 @@
+package mkvparser
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSubtitleDuration is used for subtitle blocks that do not have a duration.
+	defaultSubtitleDuration = 3 * time.Second
+
+	assEventsFormat = "Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text"
+)
+
+type (
+	// SubtitleFile accumulates the events of a subtitle track and formats them as a subtitle file.
+	SubtitleFile struct {
+		track     *Track
+		codec     string
+		extension string
+		events    []*subtitleEntry
+	}
+
+	subtitleEntry struct {
+		readOrder int
+		start     time.Duration
+		end       time.Duration
+		// Fields of ASS/SSA events, without the ReadOrder
+		fields []string
+		text   string
+	}
+)
+
+// SubtitleFormat returns the codec name and the file extension of a subtitle codec.
+// Image-based subtitles (e.g. PGS, VobSub) are not supported.
+func SubtitleFormat(codecID string) (codec string, extension string, ok bool) {
+	switch codecID {
+	case "S_TEXT/ASS", "S_ASS":
+		return "ass", "ass", true
+	case "S_TEXT/SSA", "S_SSA":
+		return "ssa", "ssa", true
+	case "S_TEXT/UTF8", "S_TEXT/ASCII":
+		return "subrip", "srt", true
+	case "S_TEXT/WEBVTT":
+		return "webvtt", "vtt", true
+	}
+	return "", "", false
+}
+
+// NewSubtitleFile returns a new subtitle file for the track, false if the codec is not supported.
+func NewSubtitleFile(track *Track) (*SubtitleFile, bool) {
+	codec, extension, ok := SubtitleFormat(track.CodecID)
+	if !ok {
+		return nil, false
+	}
+	return &SubtitleFile{
+		track:     track,
+		codec:     codec,
+		extension: extension,
+		events:    make([]*subtitleEntry, 0),
+	}, true
+}
+
+func (f *SubtitleFile) Codec() string {
+	return f.codec
+}
+
+func (f *SubtitleFile) Extension() string {
+	return f.extension
+}
+
+func (f *SubtitleFile) Len() int {
+	return len(f.events)
+}
+
+// AddEvent adds a subtitle block of the track.
+func (f *SubtitleFile) AddEvent(e *SubtitleEvent) {
+	duration := e.Duration
+	if duration <= 0 {
+		duration = defaultSubtitleDuration
+	}
+	entry := &subtitleEntry{
+		readOrder: len(f.events),
+		start:     e.StartTime,
+		end:       e.StartTime + duration,
+	}
+
+	switch f.codec {
+	case "ass", "ssa":
+		// ReadOrder, Layer, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+		parts := strings.SplitN(string(e.Data), ",", 9)
+		if len(parts) != 9 {
+			return
+		}
+		if readOrder, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
+			entry.readOrder = readOrder
+		}
+		entry.fields = parts[1:]
+	default:
+		entry.text = strings.TrimSpace(strings.ReplaceAll(string(e.Data), "\r\n", "\n"))
+	}
+
+	f.events = append(f.events, entry)
+}
+
+// Bytes returns the content of the subtitle file.
+func (f *SubtitleFile) Bytes() []byte {
+	var buf bytes.Buffer
+
+	switch f.codec {
+	case "ass", "ssa":
+		events := slices.Clone(f.events)
+		slices.SortStableFunc(events, func(a, b *subtitleEntry) int {
+			return cmp.Compare(a.readOrder, b.readOrder)
+		})
+
+		header := strings.TrimRight(strings.ReplaceAll(string(f.track.CodecPrivate), "\r\n", "\n"), "\n")
+		buf.WriteString(header)
+		buf.WriteString("\n")
+		if !strings.Contains(header, "[Events]") {
+			buf.WriteString("\n[Events]\n")
+			buf.WriteString(assEventsFormat)
+			buf.WriteString("\n")
+		}
+		for _, e := range events {
+			// Dialogue: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+			fmt.Fprintf(&buf, "Dialogue: %s,%s,%s,%s\n", e.fields[0], formatASSTime(e.start), formatASSTime(e.end), strings.Join(e.fields[1:], ","))
+		}
+	case "webvtt":
+		events := f.sortedByStartTime()
+		buf.WriteString("WEBVTT\n\n")
+		for _, e := range events {
+			fmt.Fprintf(&buf, "%s --> %s\n%s\n\n", formatTimestamp(e.start, "."), formatTimestamp(e.end, "."), e.text)
+		}
+	default:
+		events := f.sortedByStartTime()
+		for i, e := range events {
+			fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", i+1, formatTimestamp(e.start, ","), formatTimestamp(e.end, ","), e.text)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func (f *SubtitleFile) sortedByStartTime() []*subtitleEntry {
+	events := slices.Clone(f.events)
+	slices.SortStableFunc(events, func(a, b *subtitleEntry) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	return events
+}
+
+// formatASSTime formats the duration as H:MM:SS.cc
+func formatASSTime(d time.Duration) string {
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, (cs/6000)%60, (cs/100)%60, cs%100)
+}
+
+// formatTimestamp formats the duration as HH:MM:SS{sep}mmm
+func formatTimestamp(d time.Duration, sep string) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, (ms/60000)%60, (ms/1000)%60, sep, ms%1000)
+}
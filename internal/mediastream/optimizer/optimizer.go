@@ -1,12 +1,17 @@
 package optimizer
 
 import (
+	"context"
 	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/samber/mo"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
 	"seanime/internal/events"
+	"seanime/internal/mediastream/trickplay"
 	"seanime/internal/mediastream/videofile"
 	"seanime/internal/util"
+	"sync"
 )
 
 const (
@@ -20,24 +25,58 @@ type (
 	Quality string
 
 	Optimizer struct {
-		wsEventManager  events.WSEventManagerInterface
-		logger          *zerolog.Logger
-		libraryDir      mo.Option[string]
-		concurrentTasks int
+		wsEventManager     events.WSEventManagerInterface
+		logger             *zerolog.Logger
+		db                 *db.Database
+		mediaInfoExtractor *videofile.MediaInfoExtractor
+		trickplay          *trickplay.Generator
+		libraryDir         mo.Option[string]
+		concurrentTasks    int
+
+		settingsMu sync.RWMutex
+		settings   *Settings
+
+		// Wakes up the queue worker, e.g. when an item is added
+		wakeCh chan struct{}
+		// Cancels the item being transcoded
+		currentMu     sync.Mutex
+		currentItemId uint
+		currentCancel context.CancelFunc
+		startOnce     sync.Once
+	}
+
+	// Settings of the pre-transcoding queue.
+	Settings struct {
+		Enabled     bool
+		FfmpegPath  string
+		FfprobePath string
+		Quality     Quality
+		Niceness    int
+		WindowStart string // "HH:MM", empty for no restriction
+		WindowEnd   string // "HH:MM"
 	}
 
 	NewOptimizerOptions struct {
-		Logger         *zerolog.Logger
-		WSEventManager events.WSEventManagerInterface
+		Logger             *zerolog.Logger
+		WSEventManager     events.WSEventManagerInterface
+		Database           *db.Database
+		MediaInfoExtractor *videofile.MediaInfoExtractor
+		// Optional, generates the thumbnails of the "trickplay" items
+		Trickplay *trickplay.Generator
 	}
 )
 
 func NewOptimizer(opts *NewOptimizerOptions) *Optimizer {
 	ret := &Optimizer{
-		logger:          opts.Logger,
-		wsEventManager:  opts.WSEventManager,
-		libraryDir:      mo.None[string](),
-		concurrentTasks: 2,
+		logger:             opts.Logger,
+		wsEventManager:     opts.WSEventManager,
+		db:                 opts.Database,
+		mediaInfoExtractor: opts.MediaInfoExtractor,
+		trickplay:          opts.Trickplay,
+		libraryDir:         mo.None[string](),
+		concurrentTasks:    2,
+		settings:           &Settings{},
+		wakeCh:             make(chan struct{}, 1),
 	}
 	return ret
 }
@@ -46,6 +85,27 @@ func (o *Optimizer) SetLibraryDir(libraryDir string) {
 	o.libraryDir = mo.Some[string](libraryDir)
 }
 
+// SetSettings updates the settings of the queue and starts the queue worker if needed.
+func (o *Optimizer) SetSettings(settings *Settings) {
+	o.settingsMu.Lock()
+	o.settings = settings
+	o.settingsMu.Unlock()
+
+	if settings.Enabled {
+		o.startOnce.Do(func() {
+			go o.runQueue()
+		})
+	}
+
+	o.wake()
+}
+
+func (o *Optimizer) getSettings() *Settings {
+	o.settingsMu.RLock()
+	defer o.settingsMu.RUnlock()
+	return o.settings
+}
+
 /////////////
 
 type StartMediaOptimizationOptions struct {
@@ -55,6 +115,8 @@ type StartMediaOptimizationOptions struct {
 	MediaInfo         *videofile.MediaInfo
 }
 
+// StartMediaOptimization adds a single file to the queue.
+// The file is processed by the queue worker, i.e. only when the queue is enabled and during its time window.
 func (o *Optimizer) StartMediaOptimization(opts *StartMediaOptimizationOptions) (err error) {
 	defer util.HandlePanicInModuleWithError("mediastream/optimizer/StartMediaOptimization", &err)
 
@@ -68,7 +130,7 @@ func (o *Optimizer) StartMediaOptimization(opts *StartMediaOptimizationOptions)
 		return fmt.Errorf("no filepath")
 	}
 
-	return
+	return o.Enqueue([]*QueueFile{{Filepath: opts.Filepath}}, opts.Quality)
 }
 
 func qualityToPreset(quality Quality) string {
@@ -85,3 +147,22 @@ func qualityToPreset(quality Quality) string {
 		return "veryfast"
 	}
 }
+
+func qualityToCrf(quality Quality) string {
+	switch quality {
+	case QualityLow:
+		return "28"
+	case QualityMedium:
+		return "23"
+	case QualityHigh:
+		return "20"
+	case QualityMax:
+		return "18"
+	default:
+		return "23"
+	}
+}
+
+func (o *Optimizer) sendProgress(item *models.MediastreamOptimizationItem) {
+	o.wsEventManager.SendEvent(events.MediastreamOptimizationProgress, item)
+}
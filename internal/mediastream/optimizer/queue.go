@@ -0,0 +1,430 @@
+package optimizer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"seanime/internal/database/models"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ItemStatusPending = "pending"
+	ItemStatusRunning = "running"
+	ItemStatusDone    = "done"
+	ItemStatusFailed  = "failed"
+
+	// TaskOptimize converts the file to a format that can be played directly by browsers.
+	TaskOptimize = "optimize"
+	// TaskTrickplay generates the seek-preview thumbnails of the file.
+	TaskTrickplay = "trickplay"
+)
+
+type (
+	// QueueFile is a file to add to the queue.
+	QueueFile struct {
+		Filepath string `json:"filepath"`
+		MediaId  int    `json:"mediaId"`
+	}
+
+	// Criteria selects the files to optimize from their streams.
+	// Empty fields match any file.
+	Criteria struct {
+		VideoCodecs []string `json:"videoCodecs"` // FFmpeg names, e.g. "hevc"
+		AudioCodecs []string `json:"audioCodecs"` // Matches if any audio track has one of these codecs
+		MinHeight   uint32   `json:"minHeight"`
+	}
+)
+
+// GetQueue returns all the items of the queue, including the finished ones.
+func (o *Optimizer) GetQueue() ([]*models.MediastreamOptimizationItem, error) {
+	return o.db.GetMediastreamOptimizationItems()
+}
+
+// Enqueue adds the files to the queue.
+// Files that are already optimized are skipped.
+func (o *Optimizer) Enqueue(files []*QueueFile, quality Quality) error {
+	if quality == "" {
+		quality = o.getSettings().Quality
+	}
+
+	added := 0
+	for _, file := range files {
+		if _, ok := o.GetOptimizedPath(file.Filepath); ok {
+			continue
+		}
+		err := o.db.InsertMediastreamOptimizationItem(&models.MediastreamOptimizationItem{
+			Filepath: file.Filepath,
+			Task:     TaskOptimize,
+			MediaId:  file.MediaId,
+			Quality:  string(quality),
+			Status:   ItemStatusPending,
+		})
+		if err != nil {
+			return err
+		}
+		added++
+	}
+
+	o.logger.Debug().Int("count", added).Msg("optimizer: Added files to the queue")
+	o.wake()
+	return nil
+}
+
+// EnqueueTrickplay adds the files to the queue to generate their seek-preview thumbnails.
+// Files whose thumbnails are already generated are skipped.
+func (o *Optimizer) EnqueueTrickplay(files []*QueueFile) error {
+	if o.trickplay == nil {
+		return errors.New("thumbnail generation not available")
+	}
+
+	added := 0
+	for _, file := range files {
+		hash, err := videofile.GetHashFromPath(file.Filepath)
+		if err != nil || o.trickplay.IsGenerated(hash) {
+			continue
+		}
+		err = o.db.InsertMediastreamOptimizationItem(&models.MediastreamOptimizationItem{
+			Filepath: file.Filepath,
+			Task:     TaskTrickplay,
+			MediaId:  file.MediaId,
+			Status:   ItemStatusPending,
+		})
+		if err != nil {
+			return err
+		}
+		added++
+	}
+
+	o.logger.Debug().Int("count", added).Msg("optimizer: Added files to the thumbnail queue")
+	o.wake()
+	return nil
+}
+
+// EnqueueMatching adds the files whose streams match the criteria to the queue.
+// Each file is analyzed, so this can take a while for a whole library.
+func (o *Optimizer) EnqueueMatching(files []*QueueFile, criteria *Criteria, quality Quality) error {
+	ffprobePath := o.getSettings().FfprobePath
+
+	matching := make([]*QueueFile, 0)
+	for _, file := range files {
+		mediaInfo, err := o.mediaInfoExtractor.GetInfo(ffprobePath, file.Filepath)
+		if err != nil {
+			o.logger.Warn().Err(err).Str("filepath", file.Filepath).Msg("optimizer: Failed to analyze file")
+			continue
+		}
+		if matchesCriteria(mediaInfo, criteria) {
+			matching = append(matching, file)
+		}
+	}
+
+	return o.Enqueue(matching, quality)
+}
+
+// RemoveItem removes an item from the queue, stopping it if it is running.
+func (o *Optimizer) RemoveItem(id uint) error {
+	if err := o.db.DeleteMediastreamOptimizationItem(id); err != nil {
+		return err
+	}
+
+	o.currentMu.Lock()
+	defer o.currentMu.Unlock()
+	if o.currentItemId == id && o.currentCancel != nil {
+		o.currentCancel()
+	}
+	return nil
+}
+
+// ClearFinished removes the items that are done or failed from the queue.
+func (o *Optimizer) ClearFinished() error {
+	return o.db.DeleteFinishedMediastreamOptimizationItems()
+}
+
+// GetOptimizedPath returns the path of the optimized version of a file, if it exists.
+func (o *Optimizer) GetOptimizedPath(path string) (string, bool) {
+	libraryDir, ok := o.libraryDir.Get()
+	if !ok || libraryDir == "" {
+		return "", false
+	}
+	hash, err := videofile.GetHashFromPath(path)
+	if err != nil {
+		return "", false
+	}
+	ret := filepath.Join(libraryDir, hash+".mp4")
+	if _, err := os.Stat(ret); err != nil {
+		return "", false
+	}
+	return ret, true
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// runQueue processes the pending items one by one, for the lifetime of the app.
+func (o *Optimizer) runQueue() {
+	defer util.HandlePanicInModuleThen("mediastream/optimizer/runQueue", func() {})
+
+	// Items interrupted by a restart are queued again
+	if err := o.db.ResetRunningMediastreamOptimizationItems(); err != nil {
+		o.logger.Error().Err(err).Msg("optimizer: Failed to reset interrupted items")
+	}
+
+	for {
+		settings := o.getSettings()
+		if !settings.Enabled || !o.libraryDir.IsPresent() || !isInWindow(time.Now(), settings.WindowStart, settings.WindowEnd) {
+			o.waitForWake(time.Minute)
+			continue
+		}
+
+		item, err := o.db.GetNextMediastreamOptimizationItem()
+		if err != nil || item == nil {
+			o.waitForWake(time.Minute)
+			continue
+		}
+
+		o.processItem(item, settings)
+	}
+}
+
+func (o *Optimizer) wake() {
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Optimizer) waitForWake(d time.Duration) {
+	select {
+	case <-o.wakeCh:
+	case <-time.After(d):
+	}
+}
+
+func (o *Optimizer) processItem(item *models.MediastreamOptimizationItem, settings *Settings) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o.currentMu.Lock()
+	o.currentItemId = item.ID
+	o.currentCancel = cancel
+	o.currentMu.Unlock()
+
+	defer func() {
+		o.currentMu.Lock()
+		o.currentItemId = 0
+		o.currentCancel = nil
+		o.currentMu.Unlock()
+	}()
+
+	// Stop when the queue is disabled or the allowed time window closes
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s := o.getSettings()
+				if !s.Enabled || !isInWindow(time.Now(), s.WindowStart, s.WindowEnd) {
+					o.logger.Debug().Str("filepath", item.Filepath).Msg("optimizer: Pausing the queue")
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	o.logger.Info().Str("filepath", item.Filepath).Str("task", item.Task).Msg("optimizer: Processing file")
+	o.setItemStatus(item, ItemStatusRunning, 0, "")
+
+	var err error
+	switch item.Task {
+	case TaskTrickplay:
+		err = o.generateTrickplay(ctx, item, settings)
+	default:
+		err = o.transcode(ctx, item, settings)
+	}
+	switch {
+	case ctx.Err() != nil:
+		// Interrupted, the item is processed again later (no-op if it was removed)
+		o.setItemStatus(item, ItemStatusPending, 0, "")
+	case err != nil:
+		o.logger.Error().Err(err).Str("filepath", item.Filepath).Str("task", item.Task).Msg("optimizer: Failed to process file")
+		o.setItemStatus(item, ItemStatusFailed, 0, err.Error())
+	default:
+		o.logger.Info().Str("filepath", item.Filepath).Str("task", item.Task).Msg("optimizer: File processed")
+		o.setItemStatus(item, ItemStatusDone, 1, "")
+	}
+}
+
+func (o *Optimizer) setItemStatus(item *models.MediastreamOptimizationItem, status string, progress float64, errStr string) {
+	item.Status = status
+	item.Progress = progress
+	item.Error = errStr
+	if err := o.db.UpdateMediastreamOptimizationItemStatus(item.ID, status, progress, errStr); err != nil {
+		o.logger.Error().Err(err).Msg("optimizer: Failed to update item")
+	}
+	o.sendProgress(item)
+}
+
+// transcode converts the file to H.264/AAC in an MP4 container that can be played directly by browsers.
+// Subtitles and attachments are not included, they are extracted separately by the mediastream module.
+func (o *Optimizer) transcode(ctx context.Context, item *models.MediastreamOptimizationItem, settings *Settings) error {
+	mediaInfo, err := o.mediaInfoExtractor.GetInfo(settings.FfprobePath, item.Filepath)
+	if err != nil {
+		return err
+	}
+
+	libraryDir := o.libraryDir.MustGet()
+	if err = os.MkdirAll(libraryDir, 0755); err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(libraryDir, mediaInfo.Sha+".mp4")
+	tmpPath := outPath + ".tmp"
+
+	quality := Quality(item.Quality)
+	cmd := util.NewCmdCtx(ctx, settings.FfmpegPath,
+		"-nostats", "-hide_banner", "-loglevel", "error", "-y",
+		"-i", item.Filepath,
+		"-map", "0:V:0", "-map", "0:a?",
+		"-sn", "-dn",
+		"-c:v", "libx264",
+		"-preset", qualityToPreset(quality),
+		"-crf", qualityToCrf(quality),
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-ac", "2", "-b:a", "192k",
+		"-movflags", "+faststart",
+		"-progress", "pipe:1",
+		"-f", "mp4",
+		tmpPath,
+	)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err = util.StartWithNiceness(cmd, settings.Niceness); err != nil {
+		return err
+	}
+
+	lastSent := time.Time{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		progress, ok := parseProgressLine(scanner.Text(), float64(mediaInfo.Duration))
+		if !ok || time.Since(lastSent) < time.Second {
+			continue
+		}
+		lastSent = time.Now()
+		item.Progress = progress
+		o.sendProgress(item)
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		_ = os.Remove(tmpPath)
+		return ctx.Err()
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return os.Rename(tmpPath, outPath)
+}
+
+// generateTrickplay generates the seek-preview thumbnails of the file.
+func (o *Optimizer) generateTrickplay(ctx context.Context, item *models.MediastreamOptimizationItem, settings *Settings) error {
+	if o.trickplay == nil {
+		return errors.New("thumbnail generation not available")
+	}
+
+	mediaInfo, err := o.mediaInfoExtractor.GetInfo(settings.FfprobePath, item.Filepath)
+	if err != nil {
+		return err
+	}
+
+	return o.trickplay.Generate(ctx, item.Filepath, mediaInfo.Sha, mediaInfo)
+}
+
+// parseProgressLine returns the progress from 0 to 1 of a "-progress" line of FFmpeg.
+func parseProgressLine(line string, duration float64) (float64, bool) {
+	key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+	// "out_time_ms" is in microseconds as well
+	if !found || duration <= 0 || (key != "out_time_us" && key != "out_time_ms") {
+		return 0, false
+	}
+	us, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return min(max(float64(us)/1e6/duration, 0), 1), true
+}
+
+// isInWindow returns true if the time of the day is in the window.
+// The window can span midnight, e.g. "22:00" to "06:00". Empty or invalid bounds do not restrict anything.
+func isInWindow(now time.Time, start string, end string) bool {
+	startMin, err := parseTimeOfDay(start)
+	if err != nil {
+		return true
+	}
+	endMin, err := parseTimeOfDay(end)
+	if err != nil || startMin == endMin {
+		return true
+	}
+	nowMin := now.Hour()*60 + now.Minute()
+	if startMin < endMin {
+		return nowMin >= startMin && nowMin < endMin
+	}
+	return nowMin >= startMin || nowMin < endMin
+}
+
+// parseTimeOfDay returns the number of minutes since midnight of "HH:MM".
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, errors.New("invalid time of day")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func matchesCriteria(mediaInfo *videofile.MediaInfo, criteria *Criteria) bool {
+	if criteria == nil {
+		return true
+	}
+
+	video := mediaInfo.Video
+	if video == nil && len(mediaInfo.Videos) > 0 {
+		video = &mediaInfo.Videos[0]
+	}
+
+	if len(criteria.VideoCodecs) > 0 && (video == nil || !containsFold(criteria.VideoCodecs, video.Codec)) {
+		return false
+	}
+	if criteria.MinHeight > 0 && (video == nil || video.Height < criteria.MinHeight) {
+		return false
+	}
+	if len(criteria.AudioCodecs) > 0 && !slices.ContainsFunc(mediaInfo.Audios, func(audio videofile.Audio) bool {
+		return containsFold(criteria.AudioCodecs, audio.Codec)
+	}) {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, value string) bool {
+	return slices.ContainsFunc(list, func(s string) bool {
+		return strings.EqualFold(s, value)
+	})
+}
@@ -0,0 +1,67 @@
+package optimizer
+
+import (
+	"seanime/internal/mediastream/videofile"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		now        time.Time
+		start, end string
+		expected   bool
+	}{
+		{at(12, 0), "", "", true},
+		{at(12, 0), "invalid", "06:00", true},
+		{at(12, 0), "08:00", "08:00", true},
+		{at(12, 0), "08:00", "18:00", true},
+		{at(18, 0), "08:00", "18:00", false},
+		{at(7, 59), "08:00", "18:00", false},
+		// Window spanning midnight
+		{at(23, 30), "22:00", "06:00", true},
+		{at(3, 0), "22:00", "06:00", true},
+		{at(12, 0), "22:00", "06:00", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, isInWindow(tt.now, tt.start, tt.end), "%s in %s-%s", tt.now.Format("15:04"), tt.start, tt.end)
+	}
+}
+
+func TestParseProgressLine(t *testing.T) {
+	progress, ok := parseProgressLine("out_time_us=30000000", 120)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.25, progress, 0.0001)
+
+	progress, ok = parseProgressLine("out_time_ms=150000000", 120)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, progress)
+
+	_, ok = parseProgressLine("out_time_us=N/A", 120)
+	assert.False(t, ok)
+
+	_, ok = parseProgressLine("frame=100", 120)
+	assert.False(t, ok)
+}
+
+func TestMatchesCriteria(t *testing.T) {
+	mediaInfo := &videofile.MediaInfo{
+		Video:  &videofile.Video{Codec: "hevc", Height: 1080},
+		Audios: []videofile.Audio{{Codec: "aac"}, {Codec: "flac"}},
+	}
+
+	assert.True(t, matchesCriteria(mediaInfo, nil))
+	assert.True(t, matchesCriteria(mediaInfo, &Criteria{VideoCodecs: []string{"HEVC"}}))
+	assert.False(t, matchesCriteria(mediaInfo, &Criteria{VideoCodecs: []string{"h264"}}))
+	assert.True(t, matchesCriteria(mediaInfo, &Criteria{AudioCodecs: []string{"flac"}}))
+	assert.False(t, matchesCriteria(mediaInfo, &Criteria{AudioCodecs: []string{"opus"}}))
+	assert.True(t, matchesCriteria(mediaInfo, &Criteria{MinHeight: 1080}))
+	assert.False(t, matchesCriteria(mediaInfo, &Criteria{MinHeight: 2160}))
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/samber/mo"
+	"seanime/internal/mediastream/trackprefs"
+	"seanime/internal/mediastream/transcoder"
+	"seanime/internal/mediastream/trickplay"
 	"seanime/internal/mediastream/videofile"
 	"seanime/internal/util/result"
 )
@@ -23,6 +26,8 @@ type (
 		currentMediaContainer mo.Option[*MediaContainer] // The current media being played.
 		repository            *Repository
 		mediaContainers       *result.Map[string, *MediaContainer] // Temporary cache for the media containers.
+		// The variants listed in the master playlist of each client when transcoding, chosen from its capabilities.
+		masterOptions *result.Map[string, transcoder.MasterOptions]
 	}
 
 	PlaybackState struct {
@@ -35,6 +40,16 @@ type (
 		StreamType StreamType           `json:"streamType"` // Tells the frontend how to play the media.
 		StreamUrl  string               `json:"streamUrl"`  // The relative endpoint to stream the media.
 		MediaInfo  *videofile.MediaInfo `json:"mediaInfo"`
+		// The relative endpoint of the WebVTT thumbnail track, served once the thumbnails are generated.
+		TrickplayUrl string `json:"trickplayUrl"`
+		// The plan chosen from the client capabilities, empty if the client did not send any.
+		PlaybackPlan PlaybackPlan `json:"playbackPlan,omitempty"`
+		// Why the plan was chosen.
+		PlaybackPlanReason string `json:"playbackPlanReason,omitempty"`
+		// The audio and subtitle tracks preferred by the user, nil if no preferences apply.
+		TrackSelection *trackprefs.Selection `json:"trackSelection,omitempty"`
+		// The pre-transcoded file served instead of the original file, set when the stream type is StreamTypeOptimized.
+		optimizedPath string `json:"-"`
 		//Metadata  *Metadata       `json:"metadata"`
 		// todo: add more fields (e.g. metadata)
 	}
@@ -45,6 +60,7 @@ func NewPlaybackManager(repository *Repository) *PlaybackManager {
 		logger:          repository.logger,
 		repository:      repository,
 		mediaContainers: result.NewResultMap[string, *MediaContainer](),
+		masterOptions:   result.NewResultMap[string, transcoder.MasterOptions](),
 	}
 }
 
@@ -77,6 +93,29 @@ func (p *PlaybackManager) RequestPlayback(filepath string, streamType StreamType
 	return
 }
 
+// setPlaybackPlan returns a copy of the media container with the plan chosen for the client.
+// The container is copied since cached containers are shared between clients with different capabilities,
+// the variants of the master playlist are kept per client.
+func (p *PlaybackManager) setPlaybackPlan(clientId string, mc *MediaContainer, plan PlaybackPlan, reason string, opts transcoder.MasterOptions) *MediaContainer {
+	ret := *mc
+	ret.PlaybackPlan = plan
+	ret.PlaybackPlanReason = reason
+	p.masterOptions.Set(clientId, opts)
+	return &ret
+}
+
+// clearPlaybackPlan removes the plan of a client that requested a stream without capabilities.
+func (p *PlaybackManager) clearPlaybackPlan(clientId string) {
+	p.masterOptions.Delete(clientId)
+}
+
+// getMasterOptions returns the variants of the master playlist of a client.
+// All the variants are listed if the client did not send its capabilities.
+func (p *PlaybackManager) getMasterOptions(clientId string) transcoder.MasterOptions {
+	opts, _ := p.masterOptions.Get(clientId)
+	return opts
+}
+
 // PreloadPlayback is called by the frontend to preload a media container so that the data is stored in advanced
 func (p *PlaybackManager) PreloadPlayback(filepath string, streamType StreamType) (ret *MediaContainer, err error) {
 
@@ -114,6 +153,8 @@ func (p *PlaybackManager) newMediaContainer(filepath string, streamType StreamTy
 	// Check the cache ONLY if the stream type is the same.
 	if mc, ok := p.mediaContainers.Get(hash); ok && mc.StreamType == streamType {
 		p.logger.Debug().Str("hash", hash).Msg("mediastream: Media container cache HIT")
+		// The preferences might have changed since the container was created
+		mc.TrackSelection = p.repository.trackPreferences.GetMediaInfoSelection(filepath, mc.MediaInfo)
 		return mc, nil
 	}
 
@@ -133,6 +174,8 @@ func (p *PlaybackManager) newMediaContainer(filepath string, streamType StreamTy
 		return nil, err
 	}
 
+	ret.TrackSelection = p.repository.trackPreferences.GetMediaInfoSelection(filepath, ret.MediaInfo)
+
 	p.logger.Debug().Msg("mediastream: Extracted media info, extracting attachments")
 
 	// Extract the attachments from the file.
@@ -144,6 +187,10 @@ func (p *PlaybackManager) newMediaContainer(filepath string, streamType StreamTy
 
 	p.logger.Debug().Msg("mediastream: Extracted attachments")
 
+	// Generate the seek-preview thumbnails in the background
+	p.repository.trickplay.Queue(filepath, hash, ret.MediaInfo, true)
+	ret.TrickplayUrl = "/api/v1/mediastream/trickplay/" + trickplay.VTTFilename
+
 	streamUrl := ""
 	switch streamType {
 	case StreamTypeDirect:
@@ -153,9 +200,13 @@ func (p *PlaybackManager) newMediaContainer(filepath string, streamType StreamTy
 		// Live transcode the file.
 		streamUrl = "/api/v1/mediastream/transcode/master.m3u8"
 	case StreamTypeOptimized:
-		// TODO: Check if the file is already transcoded when the feature is implemented.
-		// ...
-		streamUrl = "/api/v1/mediastream/hls/master.m3u8"
+		// Directly serve the pre-transcoded file.
+		optimizedPath, ok := p.repository.optimizer.GetOptimizedPath(filepath)
+		if !ok {
+			return nil, errors.New("the file has not been optimized")
+		}
+		ret.optimizedPath = optimizedPath
+		streamUrl = "/api/v1/mediastream/direct"
 	}
 
 	// TODO: Add metadata to the media container.
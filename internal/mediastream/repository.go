@@ -6,10 +6,14 @@ import (
 	"github.com/samber/mo"
 	"os"
 	"path/filepath"
+	"seanime/internal/database/db"
 	"seanime/internal/database/models"
 	"seanime/internal/events"
+	"seanime/internal/library/anime"
 	"seanime/internal/mediastream/optimizer"
+	"seanime/internal/mediastream/trackprefs"
 	"seanime/internal/mediastream/transcoder"
+	"seanime/internal/mediastream/trickplay"
 	"seanime/internal/mediastream/videofile"
 	"seanime/internal/util/filecache"
 	"sync"
@@ -19,6 +23,8 @@ type (
 	Repository struct {
 		transcoder         mo.Option[*transcoder.Transcoder]
 		optimizer          *optimizer.Optimizer
+		trickplay          *trickplay.Generator
+		trackPreferences   *trackprefs.Manager
 		settings           mo.Option[*models.MediastreamSettings]
 		playbackManager    *PlaybackManager
 		mediaInfoExtractor *videofile.MediaInfoExtractor
@@ -34,21 +40,34 @@ type (
 		Logger         *zerolog.Logger
 		WSEventManager events.WSEventManagerInterface
 		FileCacher     *filecache.Cacher
+		Database       *db.Database
+		// Optional, used to tell the web player which tracks to select
+		TrackPreferences *trackprefs.Manager
 	}
 )
 
 func NewRepository(opts *NewRepositoryOptions) *Repository {
+	mediaInfoExtractor := videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger)
+	trickplayGenerator := trickplay.NewGenerator(&trickplay.NewGeneratorOptions{
+		Logger:     opts.Logger,
+		FileCacher: opts.FileCacher,
+	})
 	ret := &Repository{
 		logger: opts.Logger,
 		optimizer: optimizer.NewOptimizer(&optimizer.NewOptimizerOptions{
-			Logger:         opts.Logger,
-			WSEventManager: opts.WSEventManager,
+			Logger:             opts.Logger,
+			WSEventManager:     opts.WSEventManager,
+			Database:           opts.Database,
+			MediaInfoExtractor: mediaInfoExtractor,
+			Trickplay:          trickplayGenerator,
 		}),
+		trickplay:          trickplayGenerator,
 		settings:           mo.None[*models.MediastreamSettings](),
 		transcoder:         mo.None[*transcoder.Transcoder](),
+		trackPreferences:   opts.TrackPreferences,
 		wsEventManager:     opts.WSEventManager,
 		fileCacher:         opts.FileCacher,
-		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+		mediaInfoExtractor: mediaInfoExtractor,
 	}
 	ret.playbackManager = NewPlaybackManager(ret)
 
@@ -90,6 +109,18 @@ func (r *Repository) InitializeModules(settings *models.MediastreamSettings, cac
 
 	// Set the optimizer settings
 	r.optimizer.SetLibraryDir(settings.PreTranscodeLibraryDir)
+	r.optimizer.SetSettings(&optimizer.Settings{
+		Enabled:     settings.PreTranscodeEnabled && settings.PreTranscodeLibraryDir != "",
+		FfmpegPath:  settings.FfmpegPath,
+		FfprobePath: settings.FfprobePath,
+		Quality:     optimizer.Quality(settings.PreTranscodeQuality),
+		Niceness:    settings.PreTranscodeNiceness,
+		WindowStart: settings.PreTranscodeWindowStart,
+		WindowEnd:   settings.PreTranscodeWindowEnd,
+	})
+
+	// Set the trickplay settings
+	r.trickplay.SetSettings(settings.FfmpegPath, cacheDir)
 
 	// Initialize the transcoder
 	if ok := r.initializeTranscoder(r.settings); ok {
@@ -101,6 +132,7 @@ func (r *Repository) InitializeModules(settings *models.MediastreamSettings, cac
 // CacheWasCleared should be called when the cache directory is manually cleared.
 func (r *Repository) CacheWasCleared() {
 	r.playbackManager.mediaContainers.Clear()
+	r.trickplay.Clear()
 }
 
 func (r *Repository) ClearTranscodeDir() {
@@ -140,6 +172,8 @@ type StartMediaOptimizationOptions struct {
 	AudioChannelIndex int
 }
 
+// StartMediaOptimization adds a single file to the pre-transcoding queue.
+// The file is not optimized right away, it is processed when the queue is enabled and during its time window.
 func (r *Repository) StartMediaOptimization(opts *StartMediaOptimizationOptions) (err error) {
 	if !r.IsInitialized() {
 		return errors.New("module not initialized")
@@ -158,7 +192,71 @@ func (r *Repository) StartMediaOptimization(opts *StartMediaOptimizationOptions)
 	return
 }
 
+// EnqueueMediaOptimization adds files to the pre-transcoding queue.
+// If criteria is set, the files are analyzed in the background and only the matching ones are added.
+func (r *Repository) EnqueueMediaOptimization(files []*optimizer.QueueFile, criteria *optimizer.Criteria, quality optimizer.Quality) error {
+	if !r.IsInitialized() {
+		return errors.New("module not initialized")
+	}
+
+	if criteria == nil {
+		return r.optimizer.Enqueue(files, quality)
+	}
+
+	go func() {
+		if err := r.optimizer.EnqueueMatching(files, criteria, quality); err != nil {
+			r.logger.Error().Err(err).Msg("mediastream: Failed to add matching files to the optimization queue")
+		}
+	}()
+	return nil
+}
+
+// EnqueueNewDownloads adds the files that were added to the library by a scan to the pre-transcoding queue, if enabled.
+func (r *Repository) EnqueueNewDownloads(previous []*anime.LocalFile, current []*anime.LocalFile) {
+	settings, ok := r.settings.Get()
+	if !ok || !settings.PreTranscodeEnabled || !settings.PreTranscodeNewDownloads {
+		return
+	}
+
+	known := make(map[string]struct{}, len(previous))
+	for _, lf := range previous {
+		known[lf.GetNormalizedPath()] = struct{}{}
+	}
+
+	files := make([]*optimizer.QueueFile, 0)
+	for _, lf := range current {
+		if _, ok := known[lf.GetNormalizedPath()]; ok || lf.IsIgnored() || lf.MediaId == 0 {
+			continue
+		}
+		files = append(files, &optimizer.QueueFile{Filepath: lf.GetPath(), MediaId: lf.MediaId})
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	if err := r.optimizer.Enqueue(files, ""); err != nil {
+		r.logger.Error().Err(err).Msg("mediastream: Failed to add new downloads to the optimization queue")
+	}
+}
+
+func (r *Repository) GetMediaOptimizationQueue() ([]*models.MediastreamOptimizationItem, error) {
+	return r.optimizer.GetQueue()
+}
+
+func (r *Repository) RemoveMediaOptimizationItem(id uint) error {
+	return r.optimizer.RemoveItem(id)
+}
+
+func (r *Repository) ClearFinishedMediaOptimizationItems() error {
+	return r.optimizer.ClearFinished()
+}
+
+// RequestOptimizedStream plays the pre-transcoded version of the file directly.
+// An error is returned if the file has not been optimized.
 func (r *Repository) RequestOptimizedStream(filepath string) (ret *MediaContainer, err error) {
+	r.reqMu.Lock()
+	defer r.reqMu.Unlock()
+
 	if !r.IsInitialized() {
 		return nil, errors.New("module not initialized")
 	}
@@ -168,6 +266,12 @@ func (r *Repository) RequestOptimizedStream(filepath string) (ret *MediaContaine
 	return
 }
 
+// hasOptimizedVersion returns true if the file has been pre-transcoded and can be played directly instead of being transcoded.
+func (r *Repository) hasOptimizedVersion(filepath string) bool {
+	_, ok := r.optimizer.GetOptimizedPath(filepath)
+	return ok
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Transcode
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -186,6 +290,14 @@ func (r *Repository) RequestTranscodeStream(filepath string, clientId string) (r
 		return nil, errors.New("module not initialized")
 	}
 
+	r.playbackManager.clearPlaybackPlan(clientId)
+
+	// The pre-transcoded version is played instead of transcoding the file again
+	if r.hasOptimizedVersion(filepath) {
+		r.logger.Debug().Str("filepath", filepath).Msg("mediastream: Playing the optimized version")
+		return r.playbackManager.RequestPlayback(filepath, StreamTypeOptimized)
+	}
+
 	// Reinitialize the transcoder for each new transcode request
 	if ok := r.initializeTranscoder(r.settings); !ok {
 		return nil, errors.New("real-time transcoder not initialized, check your settings")
@@ -269,6 +381,7 @@ func (r *Repository) initializeTranscoder(settings mo.Option[*models.Mediastream
 		FfprobePath:           settings.MustGet().FfprobePath,
 		HwAccelCustomSettings: settings.MustGet().TranscodeHwAccelCustomSettings,
 		TempOutDir:            r.transcodeDir,
+		CacheDir:              r.cacheDir,
 	}
 
 	tc, err := transcoder.NewTranscoder(opts)
@@ -0,0 +1,126 @@
+package skipdetect
+
+import (
+	"math"
+)
+
+const (
+	// sampleRate is the sample rate of the audio extracted by FFmpeg
+	sampleRate = 11025
+	frameSize  = 2048
+	frameHop   = 256
+	numBands   = 33
+	minFreq    = 300
+	maxFreq    = 2000
+)
+
+// itemDuration is the duration covered by one item of a fingerprint, in seconds (~23ms).
+const itemDuration = float64(frameHop) / sampleRate
+
+// Fingerprint computes the fingerprint of mono PCM samples (sampled at 11025 Hz).
+// Each item is a 32-bit hash of how the energy of 33 frequency bands changed since the previous frame,
+// so that the same audio gives the same items regardless of the volume.
+func Fingerprint(samples []float64) []uint32 {
+	if len(samples) < frameSize*2 {
+		return nil
+	}
+
+	window := hannWindow(frameSize)
+	edges := bandEdges()
+	re := make([]float64, frameSize)
+	im := make([]float64, frameSize)
+	prev := make([]float64, numBands)
+	energies := make([]float64, numBands)
+
+	ret := make([]uint32, 0, (len(samples)-frameSize)/frameHop)
+	for start := 0; start+frameSize <= len(samples); start += frameHop {
+		for i := range re {
+			re[i] = samples[start+i] * window[i]
+			im[i] = 0
+		}
+		fft(re, im)
+
+		for b := 0; b < numBands; b++ {
+			energies[b] = 0
+			for k := edges[b]; k < edges[b+1]; k++ {
+				energies[b] += re[k]*re[k] + im[k]*im[k]
+			}
+		}
+
+		if start > 0 {
+			var item uint32
+			for b := 0; b < numBands-1; b++ {
+				if (energies[b]-energies[b+1])-(prev[b]-prev[b+1]) > 0 {
+					item |= 1 << b
+				}
+			}
+			ret = append(ret, item)
+		}
+		prev, energies = energies, prev
+	}
+
+	return ret
+}
+
+// BytesToSamples converts signed 16-bit little-endian PCM to samples.
+func BytesToSamples(data []byte) []float64 {
+	ret := make([]float64, len(data)/2)
+	for i := range ret {
+		ret[i] = float64(int16(uint16(data[2*i])|uint16(data[2*i+1])<<8)) / 32768
+	}
+	return ret
+}
+
+// bandEdges returns the FFT bins delimiting the logarithmically spaced frequency bands.
+func bandEdges() []int {
+	ret := make([]int, numBands+1)
+	ratio := math.Log(float64(maxFreq) / float64(minFreq))
+	for b := 0; b <= numBands; b++ {
+		freq := float64(minFreq) * math.Exp(ratio*float64(b)/numBands)
+		ret[b] = int(math.Round(freq * frameSize / sampleRate))
+	}
+	return ret
+}
+
+func hannWindow(n int) []float64 {
+	ret := make([]float64, n)
+	for i := range ret {
+		ret[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return ret
+}
+
+// fft computes the discrete Fourier transform in place. The length must be a power of 2.
+func fft(re []float64, im []float64) {
+	n := len(re)
+
+	// Bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		angle := -2 * math.Pi / float64(size)
+		wRe, wIm := math.Cos(angle), math.Sin(angle)
+		half := size / 2
+		for start := 0; start < n; start += size {
+			cRe, cIm := 1.0, 0.0
+			for k := 0; k < half; k++ {
+				a, b := start+k, start+k+half
+				tRe := re[b]*cRe - im[b]*cIm
+				tIm := re[b]*cIm + im[b]*cRe
+				re[b], im[b] = re[a]-tRe, im[a]-tIm
+				re[a], im[a] = re[a]+tRe, im[a]+tIm
+				cRe, cIm = cRe*wRe-cIm*wIm, cRe*wIm+cIm*wRe
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package skipdetect
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+)
+
+const (
+	// Two items match if they differ by at most this number of bits
+	maxBitDifference = 6
+	// Unmatched gap allowed inside a segment, in seconds
+	maxGapDuration = 3.5
+	// Bounds of the duration of an opening or ending, in seconds
+	minSegmentDuration = 15.0
+	maxSegmentDuration = 180.0
+	// Number of items on each side of an item that are considered to decide if it is part of a run
+	densityRadius = 4
+	// Number of candidate alignments that are compared item by item
+	maxCandidateOffsets = 50
+)
+
+// segment is a time range in seconds.
+type segment struct {
+	Start float64
+	End   float64
+}
+
+func (s segment) duration() float64 {
+	return s.End - s.Start
+}
+
+// findSharedSegment returns the longest audio segment present in both fingerprints,
+// as time ranges relative to the start of each fingerprint.
+//
+// Candidate alignments are found by counting the identical items of both fingerprints for each offset,
+// then the items of each candidate alignment are compared to find the longest run of similar items.
+func findSharedSegment(a []uint32, b []uint32) (segA segment, segB segment, found bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return
+	}
+
+	index := make(map[uint32][]int, len(b))
+	for j, item := range b {
+		// Silence gives empty items that would match any other silence
+		if item == 0 {
+			continue
+		}
+		index[item] = append(index[item], j)
+	}
+
+	votes := make(map[int]int)
+	for i, item := range a {
+		if item == 0 {
+			continue
+		}
+		for _, j := range index[item] {
+			votes[i-j]++
+		}
+	}
+
+	offsets := make([]int, 0, len(votes))
+	for offset := range votes {
+		offsets = append(offsets, offset)
+	}
+	slices.SortFunc(offsets, func(x, y int) int {
+		if votes[x] != votes[y] {
+			return votes[y] - votes[x]
+		}
+		return x - y
+	})
+	if len(offsets) > maxCandidateOffsets {
+		offsets = offsets[:maxCandidateOffsets]
+	}
+
+	bestStart, bestEnd, bestOffset := 0, -1, 0
+	for _, offset := range offsets {
+		start, end, ok := longestRun(a, b, offset)
+		if ok && end-start > bestEnd-bestStart {
+			bestStart, bestEnd, bestOffset = start, end, offset
+		}
+	}
+	if bestEnd < 0 {
+		return
+	}
+
+	segA = segment{Start: float64(bestStart) * itemDuration, End: float64(bestEnd+1) * itemDuration}
+	segB = segment{Start: float64(bestStart-bestOffset) * itemDuration, End: float64(bestEnd+1-bestOffset) * itemDuration}
+	found = segA.duration() >= minSegmentDuration && segA.duration() <= maxSegmentDuration
+	return
+}
+
+// longestRun returns the longest run of similar items when a[i] is aligned with b[i-offset].
+// Runs can contain gaps shorter than maxGapDuration. Isolated similar items are ignored,
+// an item is part of a run only if most of the items around it are similar.
+func longestRun(a []uint32, b []uint32, offset int) (start int, end int, ok bool) {
+	maxGap := int(math.Floor(maxGapDuration / itemDuration))
+
+	from := max(0, offset)
+	to := min(len(a), len(b)+offset)
+	if from >= to {
+		return
+	}
+
+	// Prefix sum of the similar items
+	similar := make([]int, to-from+1)
+	for i := from; i < to; i++ {
+		x, y := a[i], b[i-offset]
+		similar[i-from+1] = similar[i-from]
+		if x != 0 && y != 0 && bits.OnesCount32(x^y) <= maxBitDifference {
+			similar[i-from+1]++
+		}
+	}
+
+	runStart, lastMatch := -1, -1
+	for i := from; i < to; i++ {
+		lo, hi := max(i-densityRadius, from), min(i+densityRadius+1, to)
+		if 2*(similar[hi-from]-similar[lo-from]) <= hi-lo {
+			continue
+		}
+		if runStart < 0 || i-lastMatch > maxGap {
+			runStart = i
+		}
+		lastMatch = i
+		if !ok || lastMatch-runStart > end-start {
+			start, end, ok = runStart, lastMatch, true
+		}
+	}
+	return
+}
@@ -0,0 +1,303 @@
+package skipdetect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"seanime/internal/database/db"
+	"seanime/internal/database/models"
+	"seanime/internal/events"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	SourceFingerprint = "fingerprint"
+	SourceChapters    = "chapters"
+
+	// Maximum duration of the start and end of the episodes that are analyzed, in seconds
+	analysisDuration = 300.0
+	// FFmpeg runs at low priority
+	analysisNiceness = 10
+)
+
+var (
+	introChapterRegex = regexp.MustCompile(`(?i)\b(opening|intro)\b|^\s*op\s*\d*\s*$`)
+	outroChapterRegex = regexp.MustCompile(`(?i)\b(ending|outro|credits)\b|^\s*ed\s*\d*\s*$`)
+)
+
+type (
+	// Detector finds the intro and outro of the episodes of local media.
+	//
+	// The openings and endings are found by comparing the audio fingerprints of the start and end of consecutive episodes,
+	// since they share the same song. If no shared segment is found, the chapters named "Opening"/"Ending" are used.
+	// The markers are stored in the database so that players can skip the segments.
+	Detector struct {
+		logger             *zerolog.Logger
+		db                 *db.Database
+		wsEventManager     events.WSEventManagerInterface
+		mediaInfoExtractor *videofile.MediaInfoExtractor
+
+		mu          sync.Mutex
+		ffmpegPath  string
+		ffprobePath string
+		analyzing   map[int]struct{} // IDs of the media being analyzed
+	}
+
+	NewDetectorOptions struct {
+		Logger         *zerolog.Logger
+		Database       *db.Database
+		WSEventManager events.WSEventManagerInterface
+		FileCacher     *filecache.Cacher
+	}
+
+	// EpisodeFile is a local file to analyze.
+	EpisodeFile struct {
+		Path          string `json:"path"`
+		EpisodeNumber int    `json:"episodeNumber"`
+	}
+
+	// AnalysisResult is sent to the client when the analysis of a media is done.
+	AnalysisResult struct {
+		MediaId int                          `json:"mediaId"`
+		Markers []*models.EpisodeSkipMarkers `json:"markers"`
+	}
+
+	// episodeAudio holds what is needed to detect the markers of an episode.
+	episodeAudio struct {
+		file        *EpisodeFile
+		duration    float64
+		chapters    []videofile.Chapter
+		intro       []uint32 // Fingerprint of the start of the episode
+		outro       []uint32 // Fingerprint of the end of the episode
+		outroOffset float64  // Start time of the outro fingerprint
+	}
+)
+
+func NewDetector(opts *NewDetectorOptions) *Detector {
+	return &Detector{
+		logger:             opts.Logger,
+		db:                 opts.Database,
+		wsEventManager:     opts.WSEventManager,
+		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+		ffmpegPath:         "ffmpeg",
+		ffprobePath:        "ffprobe",
+		analyzing:          make(map[int]struct{}),
+	}
+}
+
+// SetSettings sets the FFmpeg and FFprobe paths.
+func (d *Detector) SetSettings(ffmpegPath string, ffprobePath string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if ffmpegPath != "" {
+		d.ffmpegPath = ffmpegPath
+	}
+	if ffprobePath != "" {
+		d.ffprobePath = ffprobePath
+	}
+}
+
+// GetMarkers returns the markers of a file, or nil if the file has not been analyzed.
+func (d *Detector) GetMarkers(path string) (*models.EpisodeSkipMarkers, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return d.db.GetEpisodeSkipMarkers(path)
+}
+
+// GetMediaMarkers returns the markers of the analyzed episodes of a media.
+func (d *Detector) GetMediaMarkers(mediaId int) ([]*models.EpisodeSkipMarkers, error) {
+	return d.db.GetEpisodeSkipMarkersByMediaId(mediaId)
+}
+
+// IsAnalyzing returns true if the episodes of the media are being analyzed.
+func (d *Detector) IsAnalyzing(mediaId int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.analyzing[mediaId]
+	return ok
+}
+
+// AnalyzeMedia detects the markers of the episodes of a media in the background.
+// Previous markers of the media are replaced.
+func (d *Detector) AnalyzeMedia(mediaId int, files []*EpisodeFile) error {
+	if len(files) == 0 {
+		return errors.New("no files to analyze")
+	}
+
+	d.mu.Lock()
+	if _, ok := d.analyzing[mediaId]; ok {
+		d.mu.Unlock()
+		return errors.New("media is already being analyzed")
+	}
+	d.analyzing[mediaId] = struct{}{}
+	d.mu.Unlock()
+
+	go func() {
+		defer util.HandlePanicInModuleThen("mediastream/skipdetect/AnalyzeMedia", func() {})
+		defer func() {
+			d.mu.Lock()
+			delete(d.analyzing, mediaId)
+			d.mu.Unlock()
+		}()
+
+		d.analyzeMedia(mediaId, files)
+	}()
+
+	return nil
+}
+
+func (d *Detector) analyzeMedia(mediaId int, files []*EpisodeFile) {
+	d.mu.Lock()
+	ffmpegPath, ffprobePath := d.ffmpegPath, d.ffprobePath
+	d.mu.Unlock()
+
+	files = slices.Clone(files)
+	slices.SortStableFunc(files, func(a, b *EpisodeFile) int {
+		return a.EpisodeNumber - b.EpisodeNumber
+	})
+
+	d.logger.Info().Int("mediaId", mediaId).Int("count", len(files)).Msg("skipdetect: Analyzing episodes")
+
+	episodes := make([]*episodeAudio, 0, len(files))
+	for _, file := range files {
+		mediaInfo, err := d.mediaInfoExtractor.GetInfo(ffprobePath, file.Path)
+		if err != nil {
+			d.logger.Warn().Err(err).Str("filepath", file.Path).Msg("skipdetect: Failed to get media info")
+			continue
+		}
+
+		ep := &episodeAudio{
+			file:     file,
+			duration: float64(mediaInfo.Duration),
+			chapters: mediaInfo.Chapters,
+		}
+
+		// Fingerprints are only compared between episodes
+		if len(files) > 1 && len(mediaInfo.Audios) > 0 {
+			regionDuration := min(analysisDuration, ep.duration*0.4)
+			ep.outroOffset = ep.duration - regionDuration
+
+			ep.intro, err = extractFingerprint(ffmpegPath, file.Path, 0, regionDuration)
+			if err != nil {
+				d.logger.Warn().Err(err).Str("filepath", file.Path).Msg("skipdetect: Failed to extract audio")
+			}
+			ep.outro, err = extractFingerprint(ffmpegPath, file.Path, ep.outroOffset, regionDuration)
+			if err != nil {
+				d.logger.Warn().Err(err).Str("filepath", file.Path).Msg("skipdetect: Failed to extract audio")
+			}
+		}
+
+		episodes = append(episodes, ep)
+	}
+
+	markers := detectMarkers(episodes)
+	for _, m := range markers {
+		m.MediaId = mediaId
+	}
+
+	_ = d.db.DeleteEpisodeSkipMarkersByMediaId(mediaId)
+	for _, m := range markers {
+		if err := d.db.UpsertEpisodeSkipMarkers(m); err != nil {
+			d.logger.Error().Err(err).Str("filepath", m.Filepath).Msg("skipdetect: Failed to save markers")
+		}
+	}
+
+	d.logger.Info().Int("mediaId", mediaId).Msg("skipdetect: Analysis done")
+	d.wsEventManager.SendEvent(events.SkipMarkersAnalyzed, &AnalysisResult{
+		MediaId: mediaId,
+		Markers: markers,
+	})
+}
+
+// detectMarkers finds the markers of each episode by comparing it to the next episode, or the previous one.
+func detectMarkers(episodes []*episodeAudio) []*models.EpisodeSkipMarkers {
+	ret := make([]*models.EpisodeSkipMarkers, 0, len(episodes))
+
+	for i, ep := range episodes {
+		m := &models.EpisodeSkipMarkers{
+			Filepath:      ep.file.Path,
+			EpisodeNumber: ep.file.EpisodeNumber,
+		}
+
+		for _, j := range []int{i + 1, i - 1} {
+			if j < 0 || j >= len(episodes) {
+				continue
+			}
+			other := episodes[j]
+			if !m.HasIntro() {
+				if seg, _, ok := findSharedSegment(ep.intro, other.intro); ok {
+					m.IntroStart, m.IntroEnd, m.IntroSource = seg.Start, seg.End, SourceFingerprint
+				}
+			}
+			if !m.HasOutro() {
+				if seg, _, ok := findSharedSegment(ep.outro, other.outro); ok {
+					m.OutroStart, m.OutroEnd, m.OutroSource = ep.outroOffset+seg.Start, ep.outroOffset+seg.End, SourceFingerprint
+				}
+			}
+		}
+
+		if !m.HasIntro() {
+			if seg, ok := findChapter(ep.chapters, introChapterRegex); ok {
+				m.IntroStart, m.IntroEnd, m.IntroSource = seg.Start, seg.End, SourceChapters
+			}
+		}
+		if !m.HasOutro() {
+			if seg, ok := findChapter(ep.chapters, outroChapterRegex); ok {
+				m.OutroStart, m.OutroEnd, m.OutroSource = seg.Start, seg.End, SourceChapters
+			}
+		}
+
+		ret = append(ret, m)
+	}
+
+	return ret
+}
+
+// findChapter returns the time range of the first chapter whose name matches.
+func findChapter(chapters []videofile.Chapter, nameRegex *regexp.Regexp) (segment, bool) {
+	for _, chapter := range chapters {
+		if chapter.EndTime > chapter.StartTime && nameRegex.MatchString(chapter.Name) {
+			return segment{Start: float64(chapter.StartTime), End: float64(chapter.EndTime)}, true
+		}
+	}
+	return segment{}, false
+}
+
+// extractFingerprint decodes a part of the first audio track and returns its fingerprint.
+func extractFingerprint(ffmpegPath string, path string, start float64, duration float64) ([]uint32, error) {
+	cmd := util.NewCmd(ffmpegPath,
+		"-nostats", "-hide_banner", "-loglevel", "error",
+		"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64),
+		"-i", path,
+		"-map", "0:a:0",
+		"-ac", "1",
+		"-ar", strconv.Itoa(sampleRate),
+		"-f", "s16le",
+		"-",
+	)
+
+	var stdout bytes.Buffer
+	var stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := util.StartWithNiceness(cmd, analysisNiceness); err != nil {
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return Fingerprint(BytesToSamples(stdout.Bytes())), nil
+}
@@ -0,0 +1,198 @@
+package skipdetect
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"seanime/internal/mediastream/videofile"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noise returns a deterministic "music-like" signal: random tones whose pitch changes every 250ms.
+func noise(seed int64, seconds float64) []float64 {
+	r := rand.New(rand.NewSource(seed))
+	n := int(seconds * sampleRate)
+	ret := make([]float64, n)
+	noteLen := sampleRate / 4
+	var freqs [3]float64
+	for i := range ret {
+		if i%noteLen == 0 {
+			for k := range freqs {
+				freqs[k] = 200 + r.Float64()*1800
+			}
+		}
+		t := float64(i) / sampleRate
+		for _, f := range freqs {
+			ret[i] += math.Sin(2*math.Pi*f*t) / 3
+		}
+		ret[i] = 0.8*ret[i] + 0.2*(r.Float64()*2-1)
+	}
+	return ret
+}
+
+func concat(parts ...[]float64) []float64 {
+	ret := make([]float64, 0)
+	for _, p := range parts {
+		ret = append(ret, p...)
+	}
+	return ret
+}
+
+func TestFFT(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 64
+	re := make([]float64, n)
+	im := make([]float64, n)
+	input := make([]complex128, n)
+	for i := range re {
+		re[i] = r.Float64()
+		input[i] = complex(re[i], 0)
+	}
+
+	fft(re, im)
+
+	for k := 0; k < n; k++ {
+		var expected complex128
+		for i := 0; i < n; i++ {
+			expected += input[i] * cmplx.Exp(complex(0, -2*math.Pi*float64(k*i)/float64(n)))
+		}
+		assert.InDelta(t, real(expected), re[k], 1e-9)
+		assert.InDelta(t, imag(expected), im[k], 1e-9)
+	}
+}
+
+func TestFingerprintIsVolumeIndependent(t *testing.T) {
+	samples := noise(1, 10)
+	quieter := make([]float64, len(samples))
+	for i, s := range samples {
+		quieter[i] = s * 0.3
+	}
+
+	a := Fingerprint(samples)
+	b := Fingerprint(quieter)
+	require.NotEmpty(t, a)
+	assert.Equal(t, a, b)
+	assert.Len(t, a, (len(samples)-frameSize)/frameHop)
+}
+
+func TestFindSharedSegment(t *testing.T) {
+	song := noise(100, 60)
+
+	tests := []struct {
+		name           string
+		startA, startB float64 // Start of the song in each episode, in seconds
+	}{
+		{"aligned", 20 * frameHop / float64(sampleRate), 200 * frameHop / float64(sampleRate)},
+		{"unaligned", 12.3, 41.7},
+		{"same position", 30, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := concat(noise(1, tt.startA), song, noise(2, 30))
+			b := concat(noise(3, tt.startB), song, noise(4, 10))
+
+			segA, segB, found := findSharedSegment(Fingerprint(a), Fingerprint(b))
+			require.True(t, found)
+			assert.InDelta(t, tt.startA, segA.Start, 1)
+			assert.InDelta(t, tt.startA+60, segA.End, 1)
+			assert.InDelta(t, tt.startB, segB.Start, 1)
+			assert.InDelta(t, tt.startB+60, segB.End, 1)
+		})
+	}
+}
+
+func TestFindSharedSegmentNotFound(t *testing.T) {
+	_, _, found := findSharedSegment(Fingerprint(noise(1, 90)), Fingerprint(noise(2, 90)))
+	assert.False(t, found)
+
+	// Too short to be an opening
+	jingle := noise(100, 5)
+	a := concat(noise(1, 20), jingle, noise(2, 20))
+	b := concat(noise(3, 30), jingle, noise(4, 20))
+	_, _, found = findSharedSegment(Fingerprint(a), Fingerprint(b))
+	assert.False(t, found)
+
+	// Silence is ignored
+	_, _, found = findSharedSegment(Fingerprint(make([]float64, 60*sampleRate)), Fingerprint(make([]float64, 60*sampleRate)))
+	assert.False(t, found)
+}
+
+func TestFindChapter(t *testing.T) {
+	chapters := []videofile.Chapter{
+		{StartTime: 0, EndTime: 60, Name: "Prologue"},
+		{StartTime: 60, EndTime: 150, Name: "Opening"},
+		{StartTime: 150, EndTime: 1300, Name: "Part A"},
+		{StartTime: 1300, EndTime: 1390, Name: "ED"},
+		{StartTime: 1390, EndTime: 1420, Name: "Preview"},
+	}
+
+	seg, ok := findChapter(chapters, introChapterRegex)
+	require.True(t, ok)
+	assert.Equal(t, segment{Start: 60, End: 150}, seg)
+
+	seg, ok = findChapter(chapters, outroChapterRegex)
+	require.True(t, ok)
+	assert.Equal(t, segment{Start: 1300, End: 1390}, seg)
+
+	for _, name := range []string{"OP", "op1", "Intro", "Opening Theme"} {
+		assert.True(t, introChapterRegex.MatchString(name), name)
+	}
+	for _, name := range []string{"Ending", "ED2", "End Credits", "Outro"} {
+		assert.True(t, outroChapterRegex.MatchString(name), name)
+	}
+	for _, name := range []string{"Part B", "Operation", "Episode", "Chapter 1"} {
+		assert.False(t, introChapterRegex.MatchString(name), name)
+		assert.False(t, outroChapterRegex.MatchString(name), name)
+	}
+}
+
+func TestDetectMarkers(t *testing.T) {
+	opening := noise(100, 80)
+	ending := noise(200, 85)
+
+	newEpisode := func(number int, introAt float64, chapters []videofile.Chapter) *episodeAudio {
+		seed := int64(number * 10)
+		return &episodeAudio{
+			file:        &EpisodeFile{Path: "/ep" + string(rune('0'+number)) + ".mkv", EpisodeNumber: number},
+			chapters:    chapters,
+			intro:       Fingerprint(concat(noise(seed, introAt), opening, noise(seed+1, 30))),
+			outro:       Fingerprint(concat(noise(seed+2, 20), ending, noise(seed+3, 10))),
+			outroOffset: 1300,
+		}
+	}
+
+	episodes := []*episodeAudio{
+		newEpisode(1, 5, nil),
+		newEpisode(2, 90, nil),
+		newEpisode(3, 30, nil),
+		// No shared audio, e.g. a special episode
+		{
+			file:     &EpisodeFile{Path: "/ep4.mkv", EpisodeNumber: 4},
+			chapters: []videofile.Chapter{{StartTime: 10, EndTime: 100, Name: "Opening"}},
+			intro:    Fingerprint(noise(41, 60)),
+			outro:    Fingerprint(noise(42, 60)),
+		},
+	}
+
+	markers := detectMarkers(episodes)
+	require.Len(t, markers, 4)
+
+	for i, introAt := range []float64{5, 90, 30} {
+		m := markers[i]
+		assert.Equal(t, SourceFingerprint, m.IntroSource)
+		assert.InDelta(t, introAt, m.IntroStart, 1)
+		assert.InDelta(t, introAt+80, m.IntroEnd, 1)
+		assert.Equal(t, SourceFingerprint, m.OutroSource)
+		assert.InDelta(t, 1320, m.OutroStart, 1)
+		assert.InDelta(t, 1405, m.OutroEnd, 1)
+	}
+
+	assert.Equal(t, SourceChapters, markers[3].IntroSource)
+	assert.Equal(t, 10.0, markers[3].IntroStart)
+	assert.Equal(t, 100.0, markers[3].IntroEnd)
+	assert.False(t, markers[3].HasOutro())
+}
@@ -0,0 +1,187 @@
+package streamextract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxDiscardSize is the maximum distance of a forward seek that is done by discarding the response body
+// instead of sending a new request.
+const maxDiscardSize = 1024 * 1024
+
+var errReaderClosed = errors.New("streamextract: reader closed")
+
+// HTTPRangeReader reads a remote file using range requests, e.g. a debrid stream URL.
+type HTTPRangeReader struct {
+	url    string
+	client *http.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	body   io.ReadCloser
+	pos    int64 // position of the reader
+	bodyAt int64 // position of the response body
+	size   int64 // -1 if unknown
+	closed bool
+}
+
+// NewHTTPRangeReader returns a reader for the URL. Nothing is requested until the first read.
+func NewHTTPRangeReader(url string) *HTTPRangeReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HTTPRangeReader{
+		url:    url,
+		client: &http.Client{},
+		ctx:    ctx,
+		cancel: cancel,
+		size:   -1,
+	}
+}
+
+// NewHTTPRangeReaderFunc returns a ReaderFunc for the URL.
+func NewHTTPRangeReaderFunc(url string) ReaderFunc {
+	return func() (io.ReadSeekCloser, error) {
+		return NewHTTPRangeReader(url), nil
+	}
+}
+
+func (r *HTTPRangeReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, errReaderClosed
+	}
+
+	if r.body != nil && r.bodyAt != r.pos {
+		// Skip small forward seeks by discarding the data
+		if r.pos > r.bodyAt && r.pos-r.bodyAt <= maxDiscardSize {
+			n, err := io.CopyN(io.Discard, r.body, r.pos-r.bodyAt)
+			r.bodyAt += n
+			if err != nil {
+				r.closeBody()
+			}
+		} else {
+			r.closeBody()
+		}
+	}
+
+	if r.body == nil {
+		if r.size >= 0 && r.pos >= r.size {
+			return 0, io.EOF
+		}
+		if err := r.request(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.body.Read(p)
+	r.pos += int64(n)
+	r.bodyAt += int64(n)
+	if err != nil {
+		r.closeBody()
+	}
+	return n, err
+}
+
+func (r *HTTPRangeReader) Seek(offset int64, whence int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		if r.size < 0 {
+			return 0, errors.New("streamextract: size of the file is unknown")
+		}
+		pos = r.size + offset
+	default:
+		return 0, errors.New("streamextract: invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("streamextract: negative position")
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *HTTPRangeReader) Close() error {
+	// Cancel the pending request first, a read might be holding the lock
+	r.cancel()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	r.closeBody()
+	return nil
+}
+
+func (r *HTTPRangeReader) request() error {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.pos))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server does not support range requests
+		if r.pos > 0 {
+			_ = resp.Body.Close()
+			return errors.New("streamextract: server does not support range requests")
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		_ = resp.Body.Close()
+		return io.EOF
+	default:
+		_ = resp.Body.Close()
+		return fmt.Errorf("streamextract: unexpected status code %d", resp.StatusCode)
+	}
+
+	if size := parseContentRangeSize(resp.Header.Get("Content-Range")); size >= 0 {
+		r.size = size
+	} else if resp.StatusCode == http.StatusOK && resp.ContentLength >= 0 {
+		r.size = resp.ContentLength
+	}
+
+	r.body = resp.Body
+	r.bodyAt = r.pos
+	return nil
+}
+
+func (r *HTTPRangeReader) closeBody() {
+	if r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+}
+
+// parseContentRangeSize returns the total size from a Content-Range header, e.g. "bytes 0-99/1000", -1 if unknown.
+func parseContentRangeSize(header string) int64 {
+	_, size, ok := strings.Cut(header, "/")
+	if !ok || size == "*" {
+		return -1
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
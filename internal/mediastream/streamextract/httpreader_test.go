@@ -0,0 +1,62 @@
+package streamextract
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPRangeReader(t *testing.T) {
+	data := make([]byte, 3*maxDiscardSize)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeContent(w, r, "file.mkv", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	r := NewHTTPRangeReader(server.URL)
+	defer r.Close()
+
+	buf := make([]byte, 16)
+	_, err := io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, data[:16], buf)
+
+	// Small forward seek, the body is discarded
+	_, err = r.Seek(1000, io.SeekCurrent)
+	require.NoError(t, err)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, data[1016:1032], buf)
+	assert.Equal(t, 1, requests)
+
+	// Backward seek, a new request is sent
+	_, err = r.Seek(10, io.SeekStart)
+	require.NoError(t, err)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, data[10:26], buf)
+	assert.Equal(t, 2, requests)
+
+	// The size is known from the Content-Range header
+	pos, err := r.Seek(-16, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)-16), pos)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, data[len(data)-16:], buf)
+
+	_, err = r.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,491 @@
+package streamextract
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"seanime/internal/mediastream/mkvparser"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/result"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"golang.org/x/text/language"
+)
+
+// chunkDuration is the duration of the subtitle events extracted at once.
+// Only the chunks around the playback position are extracted, so that the whole file is not read.
+const chunkDuration = 5 * time.Minute
+
+var ErrExtractionNotFound = errors.New("streamextract: no extraction found for this stream")
+
+type (
+	// Manager extracts subtitles and fonts from Matroska files that are being streamed (torrent streaming, debrid streaming).
+	// Unlike the mediastream extraction, the file does not need to be on disk, it is read from the streaming reader.
+	// The track headers and the fonts are extracted when the stream starts, the subtitle events are extracted
+	// by chunks around the requested positions using the index of the file.
+	// The extracted files are served by stream ID.
+	Manager struct {
+		cacheDir    string
+		logger      *zerolog.Logger
+		extractions *result.Map[string, *extraction]
+	}
+
+	NewManagerOptions struct {
+		CacheDir string
+		Logger   *zerolog.Logger
+	}
+
+	// Tracks are the subtitle tracks and fonts of a stream.
+	Tracks struct {
+		Subtitles []videofile.Subtitle `json:"subtitles"`
+		Fonts     []string             `json:"fonts"`
+		// Time ranges whose subtitle events have been extracted
+		Ranges []*TimeRange `json:"ranges"`
+		// Whether all subtitle events have been extracted
+		Done bool `json:"done"`
+	}
+
+	// TimeRange is a time range in seconds.
+	TimeRange struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+	}
+
+	extraction struct {
+		id     string
+		hash   string
+		ctx    context.Context
+		cancel context.CancelFunc
+		// Closed when the metadata has been read or the extraction failed
+		ready     chan struct{}
+		readyOnce sync.Once
+
+		mu     sync.RWMutex
+		tracks *Tracks
+
+		// Only accessed while holding loadMu
+		loadMu    sync.Mutex
+		parser    *mkvparser.Parser
+		metadata  *mkvparser.Metadata
+		cues      []*mkvparser.CuePoint
+		files     map[uint64]*mkvparser.SubtitleFile
+		filenames map[uint64]string
+		subsDir   string
+		loaded    map[int]bool
+		lastChunk int // Index of the last chunk, -1 until the end of the file is reached
+	}
+
+	// ReaderFunc returns a new reader of the streamed file.
+	ReaderFunc func() (io.ReadSeekCloser, error)
+)
+
+func NewManager(opts *NewManagerOptions) *Manager {
+	return &Manager{
+		cacheDir:    opts.CacheDir,
+		logger:      opts.Logger,
+		extractions: result.NewResultMap[string, *extraction](),
+	}
+}
+
+// Start starts the extraction of the subtitles and fonts of a stream.
+// Any previous extraction with the same ID is stopped.
+func (m *Manager) Start(id string, newReader ReaderFunc) {
+	m.Stop(id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &extraction{
+		id:     id,
+		hash:   hashStreamId(id),
+		ctx:    ctx,
+		cancel: cancel,
+		ready:  make(chan struct{}),
+		tracks: &Tracks{
+			Subtitles: make([]videofile.Subtitle, 0),
+			Fonts:     make([]string, 0),
+			Ranges:    make([]*TimeRange, 0),
+		},
+		loaded:    make(map[int]bool),
+		lastChunk: -1,
+	}
+	m.extractions.Set(id, e)
+
+	// Remove files left by a previous stream
+	_ = os.RemoveAll(m.getDir(e.hash))
+
+	go func() {
+		defer util.HandlePanicInModuleThen("mediastream/streamextract/Start", func() {})
+
+		err := m.extract(ctx, e, newReader)
+		e.markReady()
+		if err != nil && !errors.Is(err, context.Canceled) {
+			m.logger.Warn().Err(err).Str("id", id).Msg("streamextract: Extraction failed")
+		}
+	}()
+}
+
+// Load extracts the subtitle events around the position, if they have not been extracted yet.
+// It blocks until the events are extracted.
+func (m *Manager) Load(id string, position time.Duration) error {
+	e, ok := m.extractions.Get(id)
+	if !ok {
+		return ErrExtractionNotFound
+	}
+
+	select {
+	case <-e.ready:
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	}
+
+	e.loadMu.Lock()
+	defer e.loadMu.Unlock()
+
+	return m.loadAround(e, position)
+}
+
+// Prefetch extracts the subtitle events around the position in the background.
+// It does nothing if events are already being extracted.
+func (m *Manager) Prefetch(id string, position time.Duration) {
+	e, ok := m.extractions.Get(id)
+	if !ok {
+		return
+	}
+	select {
+	case <-e.ready:
+	default:
+		return
+	}
+
+	if !e.loadMu.TryLock() {
+		return
+	}
+	go func() {
+		defer util.HandlePanicInModuleThen("mediastream/streamextract/Prefetch", func() {})
+		defer e.loadMu.Unlock()
+
+		err := m.loadAround(e, position)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			m.logger.Warn().Err(err).Str("id", id).Msg("streamextract: Failed to extract subtitle events")
+		}
+	}()
+}
+
+// Stop stops the extraction of a stream and removes the extracted files.
+func (m *Manager) Stop(id string) {
+	e, ok := m.extractions.Get(id)
+	if !ok {
+		return
+	}
+	e.cancel()
+	m.extractions.Delete(id)
+	_ = os.RemoveAll(m.getDir(e.hash))
+	m.logger.Debug().Str("id", id).Msg("streamextract: Extraction stopped")
+}
+
+// GetTracks returns the subtitle tracks and fonts extracted so far.
+func (m *Manager) GetTracks(id string) (*Tracks, error) {
+	e, ok := m.extractions.Get(id)
+	if !ok {
+		return nil, ErrExtractionNotFound
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return &Tracks{
+		Subtitles: e.tracks.Subtitles,
+		Fonts:     e.tracks.Fonts,
+		Ranges:    e.tracks.Ranges,
+		Done:      e.tracks.Done,
+	}, nil
+}
+
+// ServeSubtitles serves the extracted subtitle files of a stream.
+// Route: /stream-subs/:id/*
+func (m *Manager) ServeSubtitles(c echo.Context) error {
+	e, ok := m.extractions.Get(c.Param("id"))
+	if !ok {
+		return ErrExtractionNotFound
+	}
+	return c.File(filepath.Join(videofile.GetFileSubsCacheDir(m.cacheDir, e.hash), cleanParam(c.Param("*"))))
+}
+
+// ServeAttachments serves the extracted attachments of a stream.
+// Route: /stream-att/:id/*
+func (m *Manager) ServeAttachments(c echo.Context) error {
+	e, ok := m.extractions.Get(c.Param("id"))
+	if !ok {
+		return ErrExtractionNotFound
+	}
+	return c.File(filepath.Join(videofile.GetFileAttCacheDir(m.cacheDir, e.hash), cleanParam(c.Param("*"))))
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// extract reads the metadata of the file, extracts the fonts and writes the headers of the subtitle files.
+// The reader is kept open until the extraction is stopped, the subtitle events are read from it on demand.
+func (m *Manager) extract(ctx context.Context, e *extraction, newReader ReaderFunc) error {
+	e.loadMu.Lock()
+	defer e.loadMu.Unlock()
+
+	r, err := newReader()
+	if err != nil {
+		return err
+	}
+	// Close the reader when the extraction is stopped, this also unblocks pending reads
+	go func() {
+		<-ctx.Done()
+		_ = r.Close()
+	}()
+
+	parser := mkvparser.NewParser(r)
+	metadata, err := parser.ReadMetadata()
+	if err != nil {
+		return err
+	}
+
+	subsDir := videofile.GetFileSubsCacheDir(m.cacheDir, e.hash)
+	attDir := videofile.GetFileAttCacheDir(m.cacheDir, e.hash)
+	_ = os.MkdirAll(subsDir, 0755)
+	_ = os.MkdirAll(attDir, 0755)
+
+	//
+	// Attachments
+	//
+	fonts := make([]string, 0)
+	for _, attachment := range metadata.Attachments {
+		filename := filepath.Base(attachment.Filename)
+		if filename == "." || filename == string(filepath.Separator) {
+			continue
+		}
+		data, err := parser.ReadAttachment(attachment)
+		if err != nil {
+			return fmt.Errorf("streamextract: failed to read attachment %s: %w", filename, err)
+		}
+		if err := os.WriteFile(filepath.Join(attDir, filename), data, 0644); err != nil {
+			return err
+		}
+		fonts = append(fonts, filename)
+	}
+
+	//
+	// Subtitle tracks
+	//
+	files := make(map[uint64]*mkvparser.SubtitleFile)
+	filenames := make(map[uint64]string)
+	subtitles := make([]videofile.Subtitle, 0)
+	for i, track := range metadata.SubtitleTracks() {
+		f, ok := mkvparser.NewSubtitleFile(track)
+		if !ok {
+			m.logger.Debug().Str("codec", track.CodecID).Msg("streamextract: Subtitle format is not supported")
+			continue
+		}
+		files[track.Number] = f
+		filenames[track.Number] = fmt.Sprintf("%d.%s", i, f.Extension())
+
+		var lang *string
+		if track.Language != "" {
+			if tag, err := language.Parse(track.Language); err == nil {
+				lang = lo.ToPtr(tag.String())
+			}
+		}
+		subtitles = append(subtitles, videofile.Subtitle{
+			Index:     uint32(i),
+			Title:     lo.Ternary(track.Name != "", lo.ToPtr(track.Name), nil),
+			Language:  lang,
+			Codec:     f.Codec(),
+			Extension: lo.ToPtr(f.Extension()),
+			IsDefault: track.IsDefault,
+			IsForced:  track.IsForced,
+			Link:      lo.ToPtr("/" + filenames[track.Number]),
+		})
+	}
+
+	// Without an index, the clusters are read from the beginning of the file up to the requested position
+	cues, err := parser.ReadCues(metadata)
+	if err != nil {
+		m.logger.Debug().Err(err).Str("id", e.id).Msg("streamextract: Could not read the index of the file")
+	}
+
+	e.parser = parser
+	e.metadata = metadata
+	e.cues = cues
+	e.files = files
+	e.filenames = filenames
+	e.subsDir = subsDir
+
+	// Write the files before reading the events so that the client can load the headers (styles) right away
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.tracks.Subtitles = subtitles
+	e.tracks.Fonts = fonts
+	e.tracks.Done = len(files) == 0
+	e.mu.Unlock()
+
+	e.markReady()
+
+	m.logger.Debug().Str("id", e.id).Int("subtitles", len(subtitles)).Int("fonts", len(fonts)).Bool("indexed", len(cues) > 0).Msg("streamextract: Extracted stream metadata")
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	// Extract the beginning of the file
+	return m.loadAround(e, 0)
+}
+
+// loadAround extracts the chunk containing the position and the next one.
+// The caller should hold e.loadMu.
+func (m *Manager) loadAround(e *extraction, position time.Duration) error {
+	if len(e.files) == 0 {
+		return nil
+	}
+
+	chunk := int(max(position, 0) / chunkDuration)
+	for i := chunk; i <= chunk+1; i++ {
+		if e.loaded[i] || (e.lastChunk >= 0 && i > e.lastChunk) {
+			continue
+		}
+		if err := m.loadChunk(e, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadChunk extracts the subtitle events starting in the chunk.
+// The clusters are read from the indexed cluster preceding the chunk, or from the beginning of the file if the file has no index.
+// In the latter case, the events of the previous chunks are extracted as well.
+func (m *Manager) loadChunk(e *extraction, chunk int) error {
+	start := time.Duration(chunk) * chunkDuration
+	end := start + chunkDuration
+
+	offset := mkvparser.FindCluster(e.cues, start)
+	from := start
+	if offset == 0 {
+		from = 0
+	}
+
+	reachedEnd, err := e.parser.ReadSubtitleEventsUntil(e.ctx, e.metadata, offset, end, func(event *mkvparser.SubtitleEvent) error {
+		if event.StartTime < from || event.StartTime >= end {
+			return nil
+		}
+		if e.loaded[int(event.StartTime/chunkDuration)] {
+			return nil
+		}
+		if f, ok := e.files[event.TrackNumber]; ok {
+			f.AddEvent(event)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := int(from / chunkDuration); i <= chunk; i++ {
+		e.loaded[i] = true
+	}
+	if reachedEnd && (e.lastChunk < 0 || chunk < e.lastChunk) {
+		e.lastChunk = chunk
+	}
+
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.tracks.Ranges = e.loadedRanges()
+	e.tracks.Done = e.isDone()
+	e.mu.Unlock()
+
+	m.logger.Trace().Str("id", e.id).Int("chunk", chunk).Msg("streamextract: Extracted subtitle events")
+
+	return nil
+}
+
+func (e *extraction) markReady() {
+	e.readyOnce.Do(func() {
+		close(e.ready)
+	})
+}
+
+func (e *extraction) flush() error {
+	for number, f := range e.files {
+		if err := writeFileAtomic(filepath.Join(e.subsDir, e.filenames[number]), f.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadedRanges returns the time ranges of the extracted chunks, consecutive chunks are merged.
+func (e *extraction) loadedRanges() []*TimeRange {
+	chunks := make([]int, 0, len(e.loaded))
+	for i := range e.loaded {
+		chunks = append(chunks, i)
+	}
+	slices.Sort(chunks)
+
+	ret := make([]*TimeRange, 0)
+	for _, i := range chunks {
+		start := (time.Duration(i) * chunkDuration).Seconds()
+		end := (time.Duration(i+1) * chunkDuration).Seconds()
+		if len(ret) > 0 && ret[len(ret)-1].End == start {
+			ret[len(ret)-1].End = end
+			continue
+		}
+		ret = append(ret, &TimeRange{Start: start, End: end})
+	}
+	return ret
+}
+
+// isDone returns true if all the chunks up to the end of the file have been extracted.
+func (e *extraction) isDone() bool {
+	if e.lastChunk < 0 {
+		return false
+	}
+	for i := 0; i <= e.lastChunk; i++ {
+		if !e.loaded[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Manager) getDir(hash string) string {
+	return filepath.Dir(videofile.GetFileSubsCacheDir(m.cacheDir, hash))
+}
+
+// hashStreamId returns the name of the directory of the extracted files.
+func hashStreamId(id string) string {
+	h := sha1.Sum([]byte(id))
+	return "stream-" + hex.EncodeToString(h[:])
+}
+
+// cleanParam removes any directory from the path parameter.
+func cleanParam(param string) string {
+	param, _ = url.PathUnescape(param)
+	return filepath.Base(filepath.Clean("/" + param))
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
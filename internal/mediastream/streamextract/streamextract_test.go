@@ -0,0 +1,32 @@
+package streamextract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraction_LoadedRanges(t *testing.T) {
+	chunk := chunkDuration.Seconds()
+
+	e := &extraction{
+		loaded:    map[int]bool{0: true, 1: true, 4: true},
+		lastChunk: -1,
+	}
+
+	ranges := e.loadedRanges()
+	if assert.Len(t, ranges, 2) {
+		assert.Equal(t, &TimeRange{Start: 0, End: 2 * chunk}, ranges[0])
+		assert.Equal(t, &TimeRange{Start: 4 * chunk, End: 5 * chunk}, ranges[1])
+	}
+	assert.False(t, e.isDone())
+
+	// The end of the file is in the chunk 4
+	e.lastChunk = 4
+	assert.False(t, e.isDone())
+
+	e.loaded[2] = true
+	e.loaded[3] = true
+	assert.True(t, e.isDone())
+	assert.Len(t, e.loadedRanges(), 1)
+}
@@ -0,0 +1,181 @@
+package trackprefs
+
+import (
+	"regexp"
+	"seanime/internal/database/models"
+	"seanime/internal/mediastream/videofile"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"golang.org/x/text/language"
+)
+
+type (
+	// Selection is what the players need to select the preferred tracks of a file.
+	Selection struct {
+		// Preferred languages by priority, with their 2-letter and 3-letter codes, e.g. ["ja", "jpn", "en", "eng"].
+		// Players use them when the tracks of the file are not known, e.g. for streams.
+		AudioLanguages    []string `json:"audioLanguages"`
+		SubtitleLanguages []string `json:"subtitleLanguages"`
+		// Indexes of the tracks among the tracks of the same type, nil if no track matches or the file was not analyzed
+		AudioIndex    *int    `json:"audioIndex"`
+		SubtitleIndex *int    `json:"subtitleIndex"`
+		SubtitleDelay float64 `json:"subtitleDelay"` // In seconds
+	}
+)
+
+// IsEmpty returns true if the selection does not change anything.
+func (s *Selection) IsEmpty() bool {
+	return s == nil || (len(s.AudioLanguages) == 0 && len(s.SubtitleLanguages) == 0 &&
+		s.AudioIndex == nil && s.SubtitleIndex == nil && s.SubtitleDelay == 0)
+}
+
+// Select returns the selection of the preferences for a file.
+// The tracks are only selected if the media info is known.
+func Select(prefs *models.TrackPreferences, mediaInfo *videofile.MediaInfo) *Selection {
+	if prefs == nil {
+		return nil
+	}
+
+	audioBases := parseLanguages(prefs.AudioLanguage)
+	subtitleBases := parseLanguages(prefs.SubtitleLanguage)
+
+	ret := &Selection{
+		AudioLanguages:    languageCodes(audioBases),
+		SubtitleLanguages: languageCodes(subtitleBases),
+		SubtitleDelay:     prefs.SubtitleDelay,
+	}
+
+	if mediaInfo == nil {
+		return ret
+	}
+
+	// Audio, first track of the first preferred language found
+	for _, base := range audioBases {
+		idx := slices.IndexFunc(mediaInfo.Audios, func(a videofile.Audio) bool {
+			return matchLanguage(a.Language, base)
+		})
+		if idx != -1 {
+			ret.AudioIndex = lo.ToPtr(int(mediaInfo.Audios[idx].Index))
+			break
+		}
+	}
+
+	// Subtitles, the first track of the first preferred language found, preferring the tracks matching the pattern
+	var pattern *regexp.Regexp
+	if prefs.SubtitleTitlePattern != "" {
+		pattern, _ = regexp.Compile("(?i)" + prefs.SubtitleTitlePattern)
+	}
+
+	candidates := make([][]videofile.Subtitle, 0)
+	for _, base := range subtitleBases {
+		subs := make([]videofile.Subtitle, 0)
+		for _, sub := range mediaInfo.Subtitles {
+			if matchLanguage(sub.Language, base) {
+				subs = append(subs, sub)
+			}
+		}
+		if len(subs) > 0 {
+			candidates = append(candidates, subs)
+		}
+	}
+	if len(subtitleBases) == 0 && pattern != nil {
+		candidates = append(candidates, mediaInfo.Subtitles)
+	}
+
+	for _, subs := range candidates {
+		if sub, found := selectSubtitle(subs, pattern); found {
+			ret.SubtitleIndex = lo.ToPtr(int(sub.Index))
+			break
+		}
+	}
+
+	return ret
+}
+
+// selectSubtitle returns the first track matching the pattern.
+// Without a pattern, or if no language was set, forced tracks are only selected if no other track is found.
+func selectSubtitle(subs []videofile.Subtitle, pattern *regexp.Regexp) (videofile.Subtitle, bool) {
+	if pattern != nil {
+		for _, sub := range subs {
+			if sub.Title != nil && pattern.MatchString(*sub.Title) {
+				return sub, true
+			}
+		}
+	}
+	for _, sub := range subs {
+		if !sub.IsForced {
+			return sub, true
+		}
+	}
+	if len(subs) > 0 {
+		return subs[0], true
+	}
+	return videofile.Subtitle{}, false
+}
+
+// parseLanguages returns the base languages of a comma-separated list of language codes, in order.
+// Unknown codes are ignored.
+func parseLanguages(list string) []language.Base {
+	ret := make([]language.Base, 0)
+	for _, code := range strings.Split(list, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		base, ok := parseBase(code)
+		if !ok || slices.Contains(ret, base) {
+			continue
+		}
+		ret = append(ret, base)
+	}
+	return ret
+}
+
+// ValidateLanguages returns the codes that are not recognized in a comma-separated list of language codes.
+func ValidateLanguages(list string) []string {
+	ret := make([]string, 0)
+	for _, code := range strings.Split(list, ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		if _, ok := parseBase(code); !ok {
+			ret = append(ret, code)
+		}
+	}
+	return ret
+}
+
+// languageCodes returns the 2-letter and 3-letter codes of the languages, since players match either.
+func languageCodes(bases []language.Base) []string {
+	ret := make([]string, 0, len(bases)*2)
+	for _, base := range bases {
+		ret = append(ret, base.String())
+		if iso3 := base.ISO3(); iso3 != base.String() {
+			ret = append(ret, iso3)
+		}
+	}
+	return ret
+}
+
+// parseBase returns the base language of a code, bibliographic codes are accepted, e.g. "fre".
+// It returns false for unknown and undetermined languages.
+func parseBase(code string) (language.Base, bool) {
+	tag, err := language.Parse(code)
+	if err != nil || tag == language.Und {
+		return language.Base{}, false
+	}
+	base, confidence := tag.Base()
+	return base, confidence != language.No
+}
+
+// matchLanguage returns true if the language of a track, as normalized by videofile, is the base language.
+func matchLanguage(lang *string, base language.Base) bool {
+	if lang == nil {
+		return false
+	}
+	b, ok := parseBase(*lang)
+	return ok && b == base
+}
@@ -0,0 +1,89 @@
+package trackprefs
+
+import (
+	"seanime/internal/database/models"
+	"seanime/internal/mediastream/videofile"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMediaInfo() *videofile.MediaInfo {
+	return &videofile.MediaInfo{
+		Audios: []videofile.Audio{
+			{Index: 0, Language: lo.ToPtr("ja")},
+			{Index: 1, Language: lo.ToPtr("en")},
+		},
+		Subtitles: []videofile.Subtitle{
+			{Index: 0, Language: lo.ToPtr("en"), Title: lo.ToPtr("Signs & Songs"), IsForced: true},
+			{Index: 1, Language: lo.ToPtr("en"), Title: lo.ToPtr("Full Subtitles")},
+			{Index: 2, Language: lo.ToPtr("und")},
+			{Index: 3, Language: lo.ToPtr("fr"), Title: lo.ToPtr("Français")},
+		},
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefs         *models.TrackPreferences
+		audioIndex    *int
+		subtitleIndex *int
+	}{
+		{
+			name:          "Languages by priority",
+			prefs:         &models.TrackPreferences{AudioLanguage: "de, jpn", SubtitleLanguage: "fre,en"},
+			audioIndex:    lo.ToPtr(0),
+			subtitleIndex: lo.ToPtr(3),
+		},
+		{
+			name:          "Forced subtitles are skipped",
+			prefs:         &models.TrackPreferences{AudioLanguage: "en", SubtitleLanguage: "en"},
+			audioIndex:    lo.ToPtr(1),
+			subtitleIndex: lo.ToPtr(1),
+		},
+		{
+			name:          "Title pattern",
+			prefs:         &models.TrackPreferences{SubtitleLanguage: "en", SubtitleTitlePattern: "signs"},
+			subtitleIndex: lo.ToPtr(0),
+		},
+		{
+			name:          "Title pattern without language",
+			prefs:         &models.TrackPreferences{SubtitleTitlePattern: "fran"},
+			subtitleIndex: lo.ToPtr(3),
+		},
+		{
+			name:  "No matching language",
+			prefs: &models.TrackPreferences{AudioLanguage: "de", SubtitleLanguage: "es"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := Select(tt.prefs, testMediaInfo())
+			require.NotNil(t, sel)
+			assert.Equal(t, tt.audioIndex, sel.AudioIndex)
+			assert.Equal(t, tt.subtitleIndex, sel.SubtitleIndex)
+		})
+	}
+}
+
+func TestSelectWithoutMediaInfo(t *testing.T) {
+	sel := Select(&models.TrackPreferences{AudioLanguage: "ja,en", SubtitleLanguage: "eng", SubtitleDelay: -0.5}, nil)
+	require.NotNil(t, sel)
+
+	assert.Equal(t, []string{"ja", "jpn", "en", "eng"}, sel.AudioLanguages)
+	assert.Equal(t, []string{"en", "eng"}, sel.SubtitleLanguages)
+	assert.Nil(t, sel.AudioIndex)
+	assert.Nil(t, sel.SubtitleIndex)
+	assert.Equal(t, -0.5, sel.SubtitleDelay)
+
+	assert.Nil(t, Select(nil, nil))
+}
+
+func TestValidateLanguages(t *testing.T) {
+	assert.Empty(t, ValidateLanguages("ja, eng,fr"))
+	assert.Equal(t, []string{"klingon", "und"}, ValidateLanguages("ja,klingon,und"))
+}
@@ -0,0 +1,182 @@
+package trackprefs
+
+import (
+	"fmt"
+	"regexp"
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/database/models"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/filecache"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	// Manager stores the audio and subtitle preferences of the user and resolves the tracks to select when playing a file.
+	// The preferences of a media override the defaults field by field.
+	Manager struct {
+		logger             *zerolog.Logger
+		db                 *db.Database
+		mediaInfoExtractor *videofile.MediaInfoExtractor
+
+		mu          sync.Mutex
+		ffprobePath string
+	}
+
+	NewManagerOptions struct {
+		Logger     *zerolog.Logger
+		Database   *db.Database
+		FileCacher *filecache.Cacher
+	}
+)
+
+func NewManager(opts *NewManagerOptions) *Manager {
+	return &Manager{
+		logger:             opts.Logger,
+		db:                 opts.Database,
+		mediaInfoExtractor: videofile.NewMediaInfoExtractor(opts.FileCacher, opts.Logger),
+		ffprobePath:        "ffprobe",
+	}
+}
+
+// SetSettings sets the FFprobe path used to list the tracks of the files.
+func (m *Manager) SetSettings(ffprobePath string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ffprobePath != "" {
+		m.ffprobePath = ffprobePath
+	}
+}
+
+func (m *Manager) getFfprobePath() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ffprobePath
+}
+
+// GetPreferences returns the preferences of a media, or the defaults if mediaId is 0.
+// The returned preferences are not merged with the defaults, nil is returned if none are set.
+func (m *Manager) GetPreferences(mediaId int) (*models.TrackPreferences, error) {
+	return m.db.GetTrackPreferences(mediaId)
+}
+
+// GetAllPreferences returns the defaults and the preferences of all the media.
+func (m *Manager) GetAllPreferences() ([]*models.TrackPreferences, error) {
+	return m.db.GetAllTrackPreferences()
+}
+
+// SavePreferences validates and saves the preferences of a media, or the defaults if MediaId is 0.
+func (m *Manager) SavePreferences(prefs *models.TrackPreferences) error {
+	if prefs == nil {
+		return fmt.Errorf("no preferences provided")
+	}
+	if invalid := ValidateLanguages(prefs.AudioLanguage); len(invalid) > 0 {
+		return fmt.Errorf("unknown audio language: %s", strings.Join(invalid, ", "))
+	}
+	if invalid := ValidateLanguages(prefs.SubtitleLanguage); len(invalid) > 0 {
+		return fmt.Errorf("unknown subtitle language: %s", strings.Join(invalid, ", "))
+	}
+	if _, err := regexp.Compile("(?i)" + prefs.SubtitleTitlePattern); err != nil {
+		return fmt.Errorf("invalid subtitle title pattern, %w", err)
+	}
+
+	return m.db.UpsertTrackPreferences(prefs)
+}
+
+func (m *Manager) DeletePreferences(mediaId int) error {
+	return m.db.DeleteTrackPreferences(mediaId)
+}
+
+// GetEffectivePreferences returns the preferences of a media merged with the defaults, or nil if none are set.
+// The subtitle delay of the media is used if it is not 0.
+func (m *Manager) GetEffectivePreferences(mediaId int) *models.TrackPreferences {
+	if m == nil {
+		return nil
+	}
+
+	defaults, _ := m.db.GetTrackPreferences(0)
+	if mediaId == 0 {
+		return defaults
+	}
+
+	prefs, _ := m.db.GetTrackPreferences(mediaId)
+	if prefs == nil {
+		return defaults
+	}
+	if defaults == nil {
+		return prefs
+	}
+
+	ret := *prefs
+	if ret.AudioLanguage == "" {
+		ret.AudioLanguage = defaults.AudioLanguage
+	}
+	if ret.SubtitleLanguage == "" {
+		ret.SubtitleLanguage = defaults.SubtitleLanguage
+	}
+	if ret.SubtitleTitlePattern == "" {
+		ret.SubtitleTitlePattern = defaults.SubtitleTitlePattern
+	}
+	if ret.SubtitleDelay == 0 {
+		ret.SubtitleDelay = defaults.SubtitleDelay
+	}
+	return &ret
+}
+
+// GetFileSelection returns the tracks to select for a local file.
+// The media is found from the local files, and the tracks of the file are listed with FFprobe.
+// It returns nil if no preferences apply.
+func (m *Manager) GetFileSelection(path string) *Selection {
+	if m == nil {
+		return nil
+	}
+
+	prefs := m.GetEffectivePreferences(m.getMediaIdFromPath(path))
+	if prefs == nil {
+		return nil
+	}
+
+	mediaInfo, err := m.mediaInfoExtractor.GetInfo(m.getFfprobePath(), path)
+	if err != nil {
+		m.logger.Warn().Err(err).Str("path", path).Msg("trackprefs: Could not list the tracks of the file")
+		mediaInfo = nil
+	}
+
+	return Select(prefs, mediaInfo)
+}
+
+// GetMediaInfoSelection returns the tracks to select for a local file whose tracks are already known.
+func (m *Manager) GetMediaInfoSelection(path string, mediaInfo *videofile.MediaInfo) *Selection {
+	if m == nil {
+		return nil
+	}
+	return Select(m.GetEffectivePreferences(m.getMediaIdFromPath(path)), mediaInfo)
+}
+
+// GetStreamSelection returns the languages to select for a stream of a media, since its tracks are not known.
+// It returns nil if no preferences apply.
+func (m *Manager) GetStreamSelection(mediaId int) *Selection {
+	if m == nil {
+		return nil
+	}
+	return Select(m.GetEffectivePreferences(mediaId), nil)
+}
+
+// getMediaIdFromPath returns the ID of the media of a local file, or 0 if the file is not in the library.
+func (m *Manager) getMediaIdFromPath(path string) int {
+	lfs, _, err := db_bridge.GetLocalFiles(m.db)
+	if err != nil {
+		return 0
+	}
+	normalized := util.NormalizePath(path)
+	for _, lf := range lfs {
+		if lf.GetNormalizedPath() == normalized {
+			return lf.MediaId
+		}
+	}
+	return 0
+}
@@ -36,7 +36,7 @@ func (r *Repository) ServeEchoTranscodeStream(c echo.Context, clientId string) e
 	}
 
 	if path == "master.m3u8" {
-		ret, err := r.transcoder.MustGet().GetMaster(mediaContainer.Filepath, mediaContainer.Hash, mediaContainer.MediaInfo, clientId)
+		ret, err := r.transcoder.MustGet().GetMaster(mediaContainer.Filepath, mediaContainer.Hash, mediaContainer.MediaInfo, clientId, r.playbackManager.getMasterOptions(clientId))
 		if err != nil {
 			return err
 		}
@@ -44,6 +44,12 @@ func (r *Repository) ServeEchoTranscodeStream(c echo.Context, clientId string) e
 		return c.String(200, ret)
 	}
 
+	// Seek-preview thumbnails
+	// /trickplay/:file
+	if strings.HasPrefix(path, "trickplay/") {
+		return r.serveTrickplayFile(c, mediaContainer, strings.TrimPrefix(path, "trickplay/"))
+	}
+
 	// Video stream
 	// /:quality/index.m3u8
 	if strings.HasSuffix(path, "index.m3u8") && !strings.Contains(path, "audio") {
@@ -171,6 +177,11 @@ func (r *Repository) ShutdownTranscodeStream(clientId string) {
 	r.transcoder = mo.None[*transcoder.Transcoder]()
 	r.initializeTranscoder(r.settings)
 
+	// Bound the segments kept for the next sessions
+	if r.fileCacher != nil {
+		go r.fileCacher.TrimMediastreamSegments()
+	}
+
 	// Send event
 	r.wsEventManager.SendEvent(events.MediastreamShutdownStream, nil)
 }
@@ -6,18 +6,26 @@ import (
 	"path/filepath"
 )
 
+// AudioCopyOffset is added to the index of an audio track to request a stream that copies the track instead of transcoding it.
+// e.g. "audio/65537/index.m3u8" is the copy of the audio track 1.
+// Copied and transcoded streams of a track are different streams, they are stored and tracked the same way.
+const AudioCopyOffset int32 = 1 << 16
+
 type AudioStream struct {
 	Stream
 	index    int32
+	copy     bool
 	logger   *zerolog.Logger
 	settings *Settings
 }
 
 // NewAudioStream creates a new AudioStream for a file, at a given audio index.
+// If the index includes AudioCopyOffset, the audio track is copied.
 func NewAudioStream(file *FileStream, idx int32, logger *zerolog.Logger, settings *Settings) *AudioStream {
 	logger.Trace().Str("file", filepath.Base(file.Path)).Int32("idx", idx).Msgf("trancoder: Creating audio stream")
 	ret := new(AudioStream)
-	ret.index = idx
+	ret.index = idx % AudioCopyOffset
+	ret.copy = idx >= AudioCopyOffset
 	ret.logger = logger
 	ret.settings = settings
 	NewStream(fmt.Sprintf("audio %d", idx), file, ret, &ret.Stream, settings, logger)
@@ -25,14 +33,30 @@ func NewAudioStream(file *FileStream, idx int32, logger *zerolog.Logger, setting
 }
 
 func (as *AudioStream) getOutPath(encoderId int) string {
+	if as.copy {
+		return filepath.Join(as.file.Out, fmt.Sprintf("segment-a%dc-%d-%%d.ts", as.index, encoderId))
+	}
 	return filepath.Join(as.file.Out, fmt.Sprintf("segment-a%d-%d-%%d.ts", as.index, encoderId))
 }
 
+func (as *AudioStream) getCacheKey() string {
+	if as.copy {
+		return fmt.Sprintf("audio-%dc", as.index)
+	}
+	return fmt.Sprintf("audio-%d", as.index)
+}
+
 func (as *AudioStream) getFlags() Flags {
 	return AudioF
 }
 
 func (as *AudioStream) getTranscodeArgs(segments string) []string {
+	if as.copy {
+		return []string{
+			"-map", fmt.Sprintf("0:a:%d", as.index),
+			"-c:a", "copy",
+		}
+	}
 	return []string{
 		"-map", fmt.Sprintf("0:a:%d", as.index),
 		"-c:a", "aac",
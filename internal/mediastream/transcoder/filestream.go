@@ -26,6 +26,7 @@ type FileStream struct {
 	Info      *videofile.MediaInfo               // The media information of the file.
 	videos    *result.Map[Quality, *VideoStream] // A map of video streams.
 	audios    *result.Map[int32, *AudioStream]   // A map of audio streams.
+	cache     *SegmentCache                      // The finished segments kept across sessions, nil if disabled.
 	logger    *zerolog.Logger
 	settings  *Settings
 }
@@ -46,6 +47,7 @@ func NewFileStream(
 		logger:   logger,
 		settings: settings,
 		Info:     mediaInfo,
+		cache:    NewSegmentCache(settings.CacheDir, sha, logger),
 	}
 
 	ret.ready.Add(1)
@@ -76,8 +78,21 @@ func (fs *FileStream) Destroy() {
 	_ = os.RemoveAll(fs.Out)
 }
 
+// MasterOptions changes the variants listed in the master playlist, e.g. depending on what the client can decode.
+// The zero value lists all variants.
+type MasterOptions struct {
+	// Only list the original quality, the video stream is copied
+	OriginalOnly bool
+	// Do not list the original quality, e.g. the client cannot decode the video codec
+	TranscodeOnly bool
+	// Do not list qualities above this height, 0 for no limit
+	MaxHeight uint32
+	// Copy the audio tracks instead of transcoding them to AAC, if their codec allows it
+	AudioCopy bool
+}
+
 // GetMaster generates the master playlist.
-func (fs *FileStream) GetMaster() string {
+func (fs *FileStream) GetMaster(opts MasterOptions) string {
 	master := "#EXTM3U\n"
 	if fs.Info.Video != nil {
 		var transmuxQuality Quality
@@ -87,7 +102,7 @@ func (fs *FileStream) GetMaster() string {
 				break
 			}
 		}
-		{
+		if !opts.TranscodeOnly {
 			bitrate := float64(fs.Info.Video.Bitrate)
 			master += "#EXT-X-STREAM-INF:"
 			master += fmt.Sprintf("AVERAGE-BANDWIDTH=%d,", int(math.Min(bitrate*0.8, float64(transmuxQuality.AverageBitrate()))))
@@ -107,9 +122,12 @@ func (fs *FileStream) GetMaster() string {
 
 		for _, quality := range Qualities {
 			sameCodec := fs.Info.Video.MimeCodec != nil && strings.HasPrefix(*fs.Info.Video.MimeCodec, transmuxPrefix)
-			includeLvl := quality.Height() < fs.Info.Video.Quality.Height() || (quality.Height() == fs.Info.Video.Quality.Height() && !sameCodec)
+			includeLvl := quality.Height() < fs.Info.Video.Quality.Height() || (quality.Height() == fs.Info.Video.Quality.Height() && (!sameCodec || opts.TranscodeOnly))
+			if opts.MaxHeight > 0 && quality.Height() > opts.MaxHeight {
+				includeLvl = false
+			}
 
-			if includeLvl {
+			if includeLvl && !opts.OriginalOnly {
 				master += "#EXT-X-STREAM-INF:"
 				master += fmt.Sprintf("AVERAGE-BANDWIDTH=%d,", quality.AverageBitrate())
 				master += fmt.Sprintf("BANDWIDTH=%d,", quality.MaxBitrate())
@@ -151,7 +169,11 @@ func (fs *FileStream) GetMaster() string {
 			master += "DEFAULT=YES,"
 		}
 		master += "CHANNELS=\"2\","
-		master += fmt.Sprintf("URI=\"./audio/%d/index.m3u8\"\n", audio.Index)
+		if opts.AudioCopy && CanCopyAudio(audio.Codec) {
+			master += fmt.Sprintf("URI=\"./audio/%d/index.m3u8\"\n", int32(audio.Index)+AudioCopyOffset)
+		} else {
+			master += fmt.Sprintf("URI=\"./audio/%d/index.m3u8\"\n", audio.Index)
+		}
 	}
 	return master
 }
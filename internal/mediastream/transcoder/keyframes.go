@@ -81,6 +81,9 @@ func GetKeyframes(
 		kf.info.ready.Add(1)
 		go func() {
 			keyframesPath := filepath.Join(settings.StreamDir, hash, "keyframes.json")
+			if settings.CacheDir != "" {
+				keyframesPath = filepath.Join(videofile.GetFileSegmentsCacheDir(settings.CacheDir, hash), "keyframes.json")
+			}
 			if err := getSavedInfo(keyframesPath, kf); err == nil {
 				logger.Trace().Msgf("transcoder: Keyframes Cache HIT")
 				kf.info.ready.Done()
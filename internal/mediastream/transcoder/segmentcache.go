@@ -0,0 +1,111 @@
+package transcoder
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"seanime/internal/mediastream/videofile"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SegmentCache keeps the finished segments of a file across sessions so that they are not transcoded again.
+//
+//	{cacheDir}/videofiles/{hash}/segments/keyframes.json
+//	{cacheDir}/videofiles/{hash}/segments/{stream}/segment-{n}.ts
+//
+// The modification time of the directory is updated when the file is streamed,
+// filecache.TrimMediastreamVideoFiles uses it to evict the least recently used files.
+type SegmentCache struct {
+	dir    string
+	logger *zerolog.Logger
+}
+
+// NewSegmentCache returns nil if the cache directory is not set.
+func NewSegmentCache(cacheDir string, hash string, logger *zerolog.Logger) *SegmentCache {
+	if cacheDir == "" {
+		return nil
+	}
+	ret := &SegmentCache{
+		dir:    videofile.GetFileSegmentsCacheDir(cacheDir, hash),
+		logger: logger,
+	}
+	_ = os.MkdirAll(ret.dir, 0755)
+	ret.touch()
+	return ret
+}
+
+// touch marks the file as recently used.
+func (sc *SegmentCache) touch() {
+	now := time.Now()
+	_ = os.Chtimes(sc.dir, now, now)
+}
+
+func (sc *SegmentCache) keyframesPath() string {
+	return filepath.Join(sc.dir, "keyframes.json")
+}
+
+func (sc *SegmentCache) segmentPath(stream string, segment int32) string {
+	return filepath.Join(sc.dir, stream, fmt.Sprintf("segment-%d.ts", segment))
+}
+
+// getSegments returns the segments of the stream that are cached.
+func (sc *SegmentCache) getSegments(stream string) map[int32]struct{} {
+	ret := make(map[int32]struct{})
+	entries, err := os.ReadDir(filepath.Join(sc.dir, stream))
+	if err != nil {
+		return ret
+	}
+	for _, entry := range entries {
+		segment, err := ParseSegment(entry.Name())
+		if err != nil {
+			continue
+		}
+		ret[segment] = struct{}{}
+	}
+	return ret
+}
+
+// store saves a finished segment.
+// The segment is hard linked when possible since the transcode directory is cleared after the session.
+func (sc *SegmentCache) store(stream string, segment int32, src string) {
+	dest := sc.segmentPath(stream, segment)
+	if _, err := os.Stat(dest); err == nil {
+		return
+	}
+	_ = os.MkdirAll(filepath.Dir(dest), 0755)
+
+	// Write to a temporary file first so that a partial segment is never served
+	tmp := dest + ".tmp"
+	if err := os.Link(src, tmp); err != nil {
+		if err = copyFile(src, tmp); err != nil {
+			sc.logger.Warn().Err(err).Str("stream", stream).Int32("segment", segment).Msg("transcoder: Failed to cache segment")
+			_ = os.Remove(tmp)
+			return
+		}
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+func copyFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -33,6 +33,8 @@ type StreamHandle interface {
 	getTranscodeArgs(segments string) []string
 	getOutPath(encoderId int) string
 	getFlags() Flags
+	// getCacheKey identifies the stream in the segment cache
+	getCacheKey() string
 }
 
 type Stream struct {
@@ -64,6 +66,8 @@ type Segment struct {
 	//  <-ts.segments[i]
 	channel chan struct{}
 	encoder int
+	// the segment was transcoded in a previous session and is served from the segment cache
+	cached bool
 }
 
 type Head struct {
@@ -104,6 +108,13 @@ func NewStream(
 		ret.segments[seg].channel = make(chan struct{})
 	}
 
+	// Segments transcoded in a previous session are ready
+	cached := make(map[int32]struct{})
+	if file.cache != nil {
+		cached = file.cache.getSegments(handle.getCacheKey())
+	}
+	ret.markCachedSegments(cached, 0)
+
 	if !isDone {
 		file.Keyframes.AddListener(func(keyframes []float64) {
 			ret.segmentsLock.Lock()
@@ -117,10 +128,22 @@ func NewStream(
 			for seg := oldLength; seg < len(keyframes); seg++ {
 				ret.segments[seg].channel = make(chan struct{})
 			}
+			ret.markCachedSegments(cached, oldLength)
 		})
 	}
 }
 
+// markCachedSegments marks the cached segments starting at the given index as ready.
+// Remember to lock before calling this.
+func (ts *Stream) markCachedSegments(cached map[int32]struct{}, from int) {
+	for seg := from; seg < len(ts.segments); seg++ {
+		if _, ok := cached[int32(seg)]; ok && !ts.segments[seg].cached {
+			ts.segments[seg].cached = true
+			close(ts.segments[seg].channel)
+		}
+	}
+}
+
 func (ts *Stream) GetIndex() (string, error) {
 	// playlist type is event since we can append to the list if Keyframe.IsDone is false.
 	// start time offset makes the stream start at 0s instead of ~3segments from the end (requires version 6 of hls)
@@ -204,6 +227,9 @@ func (ts *Stream) GetSegment(segment int32) (string, error) {
 	}
 	//go ts.prepareNextSegments(segment)
 	ts.prepareNextSegments(segment)
+	if ts.segments[segment].cached {
+		return filepath.ToSlash(ts.file.cache.segmentPath(ts.handle.getCacheKey(), segment)), nil
+	}
 	return fmt.Sprintf(filepath.ToSlash(ts.handle.getOutPath(ts.segments[segment].encoder)), segment), nil
 }
 
@@ -549,6 +575,10 @@ func (ts *Stream) run(start int32) error {
 				// Mark the segment as ready
 				ts.segments[segment].encoder = encoderId
 				close(ts.segments[segment].channel)
+				// Keep the segment for the next sessions
+				if ts.file.cache != nil {
+					go ts.file.cache.store(ts.handle.getCacheKey(), segment, fmt.Sprintf(outpath, segment))
+				}
 				if segment == end-1 {
 					// file finished, ffmpeg will finish soon on its own
 					shouldStop = true
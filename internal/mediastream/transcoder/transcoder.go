@@ -24,6 +24,7 @@ type (
 
 	Settings struct {
 		StreamDir   string
+		CacheDir    string // Where finished segments are kept across sessions, empty to disable
 		HwAccel     HwAccelSettings
 		FfmpegPath  string
 		FfprobePath string
@@ -34,6 +35,7 @@ type (
 		HwAccelKind           string
 		Preset                string
 		TempOutDir            string
+		CacheDir              string
 		FfmpegPath            string
 		FfprobePath           string
 		HwAccelCustomSettings string
@@ -61,6 +63,7 @@ func NewTranscoder(opts *NewTranscoderOptions) (*Transcoder, error) {
 		logger:     opts.Logger,
 		settings: Settings{
 			StreamDir: streamDir,
+			CacheDir:  opts.CacheDir,
 			HwAccel: GetHardwareAccelSettings(HwAccelOptions{
 				Kind:           opts.HwAccelKind,
 				Preset:         opts.Preset,
@@ -120,7 +123,7 @@ func (t *Transcoder) getFileStream(path string, hash string, mediaInfo *videofil
 	return ret, nil
 }
 
-func (t *Transcoder) GetMaster(path string, hash string, mediaInfo *videofile.MediaInfo, client string) (string, error) {
+func (t *Transcoder) GetMaster(path string, hash string, mediaInfo *videofile.MediaInfo, client string, opts MasterOptions) (string, error) {
 	if debugStream {
 		start := time.Now()
 		t.logger.Trace().Msgf("transcoder: Retrieving master file")
@@ -137,7 +140,7 @@ func (t *Transcoder) GetMaster(path string, hash string, mediaInfo *videofile.Me
 		audio:   -1,
 		head:    -1,
 	}
-	return stream.GetMaster(), nil
+	return stream.GetMaster(opts), nil
 }
 
 func (t *Transcoder) GetVideoIndex(
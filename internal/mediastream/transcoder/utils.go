@@ -56,3 +56,17 @@ func printExecTime(logger *zerolog.Logger, message string, args ...any) func() {
 		logger.Trace().Msgf("transcoder: %s finished in %s", msg, time.Since(start))
 	}
 }
+
+// CanCopyVideo returns true if a video stream with this codec can be copied into MPEG-TS segments.
+func CanCopyVideo(codec string) bool {
+	return codec == "h264" || codec == "hevc"
+}
+
+// CanCopyAudio returns true if an audio stream with this codec can be copied into MPEG-TS segments.
+func CanCopyAudio(codec string) bool {
+	switch codec {
+	case "aac", "mp3", "ac3", "eac3":
+		return true
+	}
+	return false
+}
@@ -35,6 +35,10 @@ func (vs *VideoStream) getOutPath(encoderId int) string {
 	return filepath.Join(vs.file.Out, fmt.Sprintf("segment-%s-%d-%%d.ts", vs.quality, encoderId))
 }
 
+func (vs *VideoStream) getCacheKey() string {
+	return fmt.Sprintf("video-%s", vs.quality)
+}
+
 func closestMultiple(n int32, x int32) int32 {
 	if x > n {
 		return x
@@ -0,0 +1,102 @@
+package mediastream
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"seanime/internal/mediastream/optimizer"
+	"seanime/internal/mediastream/trickplay"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ServeEchoTrickplay serves the seek-preview thumbnails of the current media.
+func (r *Repository) ServeEchoTrickplay(c echo.Context) error {
+	if !r.IsInitialized() {
+		return errors.New("module not initialized")
+	}
+
+	mediaContainer, found := r.playbackManager.currentMediaContainer.Get()
+	if !found {
+		return errors.New("no file has been loaded")
+	}
+
+	return r.serveTrickplayFile(c, mediaContainer, c.Param("*"))
+}
+
+func (r *Repository) serveTrickplayFile(c echo.Context, mediaContainer *MediaContainer, name string) error {
+	// The thumbnails are not generated yet
+	if !r.trickplay.IsGenerated(mediaContainer.Hash) {
+		return c.NoContent(http.StatusNotFound)
+	}
+
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name != trickplay.VTTFilename && !strings.HasSuffix(name, ".jpg") {
+		return errors.New("invalid path")
+	}
+
+	// The track is requested once per playback
+	if name == trickplay.VTTFilename {
+		r.trickplay.Touch(mediaContainer.Hash)
+	}
+
+	return c.File(filepath.Join(r.trickplay.GetDir(mediaContainer.Hash), name))
+}
+
+// GenerateTrickplayForFiles queues the generation of seek-preview thumbnails for the given files.
+// Files whose thumbnails are already generated are skipped.
+func (r *Repository) GenerateTrickplayForFiles(paths []string) error {
+	if !r.IsInitialized() {
+		return errors.New("module not initialized")
+	}
+
+	ffprobePath := r.settings.MustGet().FfprobePath
+
+	go func() {
+		defer util.HandlePanicInModuleThen("mediastream/GenerateTrickplayForFiles", func() {})
+
+		queued := 0
+		for _, path := range paths {
+			hash, err := videofile.GetHashFromPath(path)
+			if err != nil {
+				continue
+			}
+			if r.trickplay.IsGenerated(hash) {
+				continue
+			}
+			mediaInfo, err := r.mediaInfoExtractor.GetInfo(ffprobePath, path)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("filepath", path).Msg("mediastream: Failed to get media info for thumbnail generation")
+				continue
+			}
+			r.trickplay.Queue(path, hash, mediaInfo, false)
+			queued++
+		}
+
+		r.logger.Info().Int("count", queued).Msg("mediastream: Queued thumbnail generation")
+	}()
+
+	return nil
+}
+
+// EnqueueTrickplayGeneration adds the files to the pre-transcoding queue to generate their seek-preview thumbnails, e.g. the whole library.
+// The files are processed by the queue, during its time window and at its priority.
+func (r *Repository) EnqueueTrickplayGeneration(files []*optimizer.QueueFile) error {
+	settings, ok := r.settings.Get()
+	if !ok {
+		return errors.New("module not initialized")
+	}
+	if !settings.PreTranscodeEnabled || settings.PreTranscodeLibraryDir == "" {
+		return errors.New("the pre-transcoding queue is disabled")
+	}
+
+	return r.optimizer.EnqueueTrickplay(files)
+}
+
+// GetTrickplayQueueLength returns the number of files waiting for thumbnail generation.
+func (r *Repository) GetTrickplayQueueLength() int {
+	return r.trickplay.QueueLength()
+}
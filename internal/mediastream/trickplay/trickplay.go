@@ -0,0 +1,351 @@
+package trickplay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"seanime/internal/mediastream/videofile"
+	"seanime/internal/util"
+	"seanime/internal/util/crashlog"
+	"seanime/internal/util/filecache"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Interval is the number of seconds between two thumbnails
+	Interval = 10
+	// VTTFilename is the name of the WebVTT thumbnail track
+	VTTFilename = "thumbnails.vtt"
+
+	thumbnailWidth = 320
+	// Number of thumbnails per row and column of a sprite sheet
+	tileColumns = 10
+	tileRows    = 10
+	// Thumbnails are generated at the lowest priority so that they do not slow down playback
+	niceness = 19
+)
+
+type (
+	// Generator generates seek-preview thumbnails (sprite sheets and a WebVTT thumbnail track) for local files.
+	// Files are processed one at a time in the background using FFmpeg at low priority.
+	// The thumbnails are stored in the video file cache, next to the extracted subtitles and attachments.
+	// Their total size is bounded, the thumbnails of the least recently played files are removed first.
+	Generator struct {
+		logger     *zerolog.Logger
+		fileCacher *filecache.Cacher
+
+		mu         sync.Mutex
+		ffmpegPath string
+		cacheDir   string
+		queue      []*job
+		queued     map[string]struct{} // Hashes of the queued files and the file being processed
+		running    bool
+		cancel     context.CancelFunc // Cancels the file being processed
+	}
+
+	NewGeneratorOptions struct {
+		Logger *zerolog.Logger
+		// Optional, used to bound the size of the thumbnails
+		FileCacher *filecache.Cacher
+	}
+
+	job struct {
+		filepath  string
+		hash      string
+		mediaInfo *videofile.MediaInfo
+	}
+)
+
+func NewGenerator(opts *NewGeneratorOptions) *Generator {
+	return &Generator{
+		logger:     opts.Logger,
+		fileCacher: opts.FileCacher,
+		queue:      make([]*job, 0),
+		queued:     make(map[string]struct{}),
+	}
+}
+
+// SetSettings sets the FFmpeg path and the cache directory.
+func (g *Generator) SetSettings(ffmpegPath string, cacheDir string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ffmpegPath = ffmpegPath
+	g.cacheDir = cacheDir
+}
+
+// GetDir returns the directory of the thumbnails of a file.
+func (g *Generator) GetDir(hash string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return videofile.GetFileTrickplayCacheDir(g.cacheDir, hash)
+}
+
+// IsGenerated returns true if the thumbnails of the file have been generated.
+func (g *Generator) IsGenerated(hash string) bool {
+	_, err := os.Stat(filepath.Join(g.GetDir(hash), VTTFilename))
+	return err == nil
+}
+
+// Touch marks the thumbnails of a file as recently used so that they are removed last.
+func (g *Generator) Touch(hash string) {
+	now := time.Now()
+	_ = os.Chtimes(g.GetDir(hash), now, now)
+}
+
+// Generate generates the thumbnails of a file and returns once they are generated.
+// Unlike Queue, this is meant to be called by another queue, e.g. the pre-transcoding queue.
+// The file is skipped if its thumbnails are already generated or are being generated.
+func (g *Generator) Generate(ctx context.Context, path string, hash string, mediaInfo *videofile.MediaInfo) error {
+	if g.IsGenerated(hash) {
+		return nil
+	}
+
+	g.mu.Lock()
+	if g.cacheDir == "" || g.ffmpegPath == "" {
+		g.mu.Unlock()
+		return errors.New("trickplay: Not initialized")
+	}
+	if _, ok := g.queued[hash]; ok {
+		g.mu.Unlock()
+		return nil
+	}
+	g.queued[hash] = struct{}{}
+	ffmpegPath := g.ffmpegPath
+	outDir := videofile.GetFileTrickplayCacheDir(g.cacheDir, hash)
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.queued, hash)
+		g.mu.Unlock()
+	}()
+
+	return g.generate(ctx, ffmpegPath, outDir, &job{filepath: path, hash: hash, mediaInfo: mediaInfo})
+}
+
+// Queue adds a file to the queue if its thumbnails have not been generated yet.
+// If priority is true, the file is processed before the other queued files (e.g. the file is being played).
+func (g *Generator) Queue(path string, hash string, mediaInfo *videofile.MediaInfo, priority bool) {
+	if g.IsGenerated(hash) {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.cacheDir == "" || g.ffmpegPath == "" {
+		return
+	}
+
+	if _, ok := g.queued[hash]; ok {
+		if priority {
+			// Move the file to the front of the queue
+			for i, j := range g.queue {
+				if j.hash == hash {
+					g.queue = append(append([]*job{j}, g.queue[:i]...), g.queue[i+1:]...)
+					break
+				}
+			}
+		}
+		return
+	}
+
+	j := &job{filepath: path, hash: hash, mediaInfo: mediaInfo}
+	if priority {
+		g.queue = append([]*job{j}, g.queue...)
+	} else {
+		g.queue = append(g.queue, j)
+	}
+	g.queued[hash] = struct{}{}
+
+	if !g.running {
+		g.running = true
+		go g.run()
+	}
+}
+
+// QueueLength returns the number of files waiting to be processed.
+func (g *Generator) QueueLength() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.queue)
+}
+
+// Clear empties the queue and stops the file being processed.
+func (g *Generator) Clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.queue = make([]*job, 0)
+	g.queued = make(map[string]struct{})
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+func (g *Generator) run() {
+	defer util.HandlePanicInModuleThen("mediastream/trickplay/run", func() {
+		g.mu.Lock()
+		g.running = false
+		g.mu.Unlock()
+	})
+
+	for {
+		g.mu.Lock()
+		if len(g.queue) == 0 {
+			g.running = false
+			g.cancel = nil
+			g.mu.Unlock()
+			return
+		}
+		j := g.queue[0]
+		g.queue = g.queue[1:]
+		ctx, cancel := context.WithCancel(context.Background())
+		g.cancel = cancel
+		ffmpegPath := g.ffmpegPath
+		outDir := videofile.GetFileTrickplayCacheDir(g.cacheDir, j.hash)
+		g.mu.Unlock()
+
+		err := g.generate(ctx, ffmpegPath, outDir, j)
+		cancel()
+		if err != nil && !errors.Is(err, context.Canceled) {
+			g.logger.Warn().Err(err).Str("filepath", j.filepath).Msg("trickplay: Failed to generate thumbnails")
+		}
+
+		g.mu.Lock()
+		delete(g.queued, j.hash)
+		g.mu.Unlock()
+	}
+}
+
+func (g *Generator) generate(ctx context.Context, ffmpegPath string, outDir string, j *job) error {
+	if j.mediaInfo == nil || len(j.mediaInfo.Videos) == 0 || j.mediaInfo.Duration <= 0 {
+		return errors.New("no video stream")
+	}
+
+	video := j.mediaInfo.Videos[0]
+	width, height := thumbnailSize(video.Width, video.Height)
+	if width == 0 || height == 0 {
+		return errors.New("invalid video size")
+	}
+
+	g.logger.Debug().Str("filepath", j.filepath).Msg("trickplay: Generating thumbnails")
+
+	// The thumbnails are generated in a temporary directory so that incomplete thumbnails are never served
+	tmpDir := outDir + ".tmp"
+	_ = os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	crashLogger := crashlog.GlobalCrashLogger.InitArea("ffmpeg")
+	defer crashLogger.Close()
+
+	crashLogger.LogInfof("Generating thumbnails for %s", j.filepath)
+
+	cmd := util.NewCmdCtx(
+		ctx,
+		ffmpegPath,
+		"-hide_banner",
+		"-loglevel", "error",
+		"-nostdin",
+		// Only decode keyframes, this is much faster and precise enough for previews
+		"-skip_frame", "nokey",
+		"-i", j.filepath,
+		"-an", "-sn", "-dn",
+		"-vf", fmt.Sprintf("fps=1/%d,scale=%d:%d,tile=%dx%d", Interval, width, height, tileColumns, tileRows),
+		"-q:v", "5",
+		"-f", "image2",
+		"-y",
+		filepath.Join(tmpDir, "sprite-%d.jpg"),
+	)
+	cmd.Stdout = crashLogger.Stdout()
+	cmd.Stderr = crashLogger.Stdout()
+
+	if err := util.StartWithNiceness(cmd, niceness); err != nil {
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		crashlog.GlobalCrashLogger.WriteAreaLogToFile(crashLogger)
+		return err
+	}
+
+	sprites, err := filepath.Glob(filepath.Join(tmpDir, "sprite-*.jpg"))
+	if err != nil {
+		return err
+	}
+	if len(sprites) == 0 {
+		return errors.New("no thumbnails generated")
+	}
+
+	count := int(math.Ceil(float64(j.mediaInfo.Duration) / Interval))
+	count = min(count, len(sprites)*tileColumns*tileRows)
+
+	vtt := buildVTT(count, float64(j.mediaInfo.Duration), width, height)
+	if err := os.WriteFile(filepath.Join(tmpDir, VTTFilename), []byte(vtt), 0644); err != nil {
+		return err
+	}
+
+	_ = os.RemoveAll(outDir)
+	if err := os.MkdirAll(filepath.Dir(outDir), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpDir, outDir); err != nil {
+		return err
+	}
+
+	g.logger.Debug().Str("filepath", j.filepath).Int("sprites", len(sprites)).Msg("trickplay: Thumbnails generated")
+
+	if g.fileCacher != nil {
+		g.fileCacher.TrimMediastreamTrickplay()
+	}
+
+	return nil
+}
+
+// thumbnailSize returns the size of a thumbnail, keeping the aspect ratio of the video.
+// The height is rounded to an even number.
+func thumbnailSize(videoWidth uint32, videoHeight uint32) (int, int) {
+	if videoWidth == 0 || videoHeight == 0 {
+		return 0, 0
+	}
+	width := min(thumbnailWidth, int(videoWidth))
+	height := int(math.Round(float64(width)*float64(videoHeight)/float64(videoWidth)/2)) * 2
+	return width, height
+}
+
+// buildVTT returns the WebVTT thumbnail track.
+// Each cue points to a region of a sprite sheet using the media fragment syntax (e.g. "sprite-1.jpg#xywh=0,0,320,180").
+func buildVTT(count int, duration float64, width int, height int) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	perSheet := tileColumns * tileRows
+	for i := 0; i < count; i++ {
+		start := float64(i * Interval)
+		end := math.Min(float64((i+1)*Interval), duration)
+		if end <= start {
+			break
+		}
+		sheet := i/perSheet + 1
+		x := (i % perSheet % tileColumns) * width
+		y := (i % perSheet / tileColumns) * height
+		fmt.Fprintf(&sb, "%s --> %s\nsprite-%d.jpg#xywh=%d,%d,%d,%d\n\n", formatTimestamp(start), formatTimestamp(end), sheet, x, y, width, height)
+	}
+	return sb.String()
+}
+
+// formatTimestamp formats seconds as HH:MM:SS.mmm
+func formatTimestamp(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, (ms/60000)%60, (ms/1000)%60, ms%1000)
+}
@@ -0,0 +1,39 @@
+package trickplay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnailSize(t *testing.T) {
+	tests := []struct {
+		width, height  uint32
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{1920, 1080, 320, 180},
+		{1440, 1080, 320, 240},
+		{1920, 800, 320, 134},
+		{240, 160, 240, 160},
+		{0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		w, h := thumbnailSize(tt.width, tt.height)
+		assert.Equal(t, tt.expectedWidth, w)
+		assert.Equal(t, tt.expectedHeight, h)
+	}
+}
+
+func TestBuildVTT(t *testing.T) {
+	// 101 thumbnails, the last one is on the second sprite sheet
+	vtt := buildVTT(101, 1005, 320, 180)
+
+	assert.Contains(t, vtt, "WEBVTT\n\n00:00:00.000 --> 00:00:10.000\nsprite-1.jpg#xywh=0,0,320,180\n\n")
+	assert.Contains(t, vtt, "00:00:10.000 --> 00:00:20.000\nsprite-1.jpg#xywh=320,0,320,180\n\n")
+	assert.Contains(t, vtt, "00:01:40.000 --> 00:01:50.000\nsprite-1.jpg#xywh=0,180,320,180\n\n")
+	assert.Contains(t, vtt, "00:16:30.000 --> 00:16:40.000\nsprite-1.jpg#xywh=2880,1620,320,180\n\n")
+	// The last cue ends at the end of the video
+	assert.Contains(t, vtt, "00:16:40.000 --> 00:16:45.000\nsprite-2.jpg#xywh=0,0,320,180\n\n")
+}
@@ -12,6 +12,7 @@ import (
 	"seanime/internal/torrent_clients/transmission"
 	"seanime/internal/torrents/torrent"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -42,6 +43,14 @@ type (
 		MetadataProvider  metadata.Provider
 	}
 
+	// AddMagnetsOptions holds optional parameters passed to the torrent client when adding magnets.
+	AddMagnetsOptions struct {
+		// qBittorrent category, added as a label in Transmission
+		Category string
+		// qBittorrent tags (in addition to the global tags) or Transmission labels
+		Tags []string
+	}
+
 	ActiveCount struct {
 		Downloading int `json:"downloading"`
 		Seeding     int `json:"seeding"`
@@ -215,7 +224,9 @@ func (r *Repository) GetActiveTorrents() ([]*Torrent, error) {
 	return active, nil
 }
 
-func (r *Repository) AddMagnets(magnets []string, dest string) error {
+// AddMagnets adds the magnets to the torrent client.
+// opts can be nil.
+func (r *Repository) AddMagnets(magnets []string, dest string, opts *AddMagnetsOptions) error {
 	r.logger.Trace().Any("magnets", magnets).Msg("torrent client: Adding magnets")
 
 	if len(magnets) == 0 {
@@ -223,19 +234,33 @@ func (r *Repository) AddMagnets(magnets []string, dest string) error {
 		return nil
 	}
 
+	if opts == nil {
+		opts = &AddMagnetsOptions{}
+	}
+
 	var err error
 	switch r.provider {
 	case QbittorrentClient:
 		err = r.qBittorrentClient.Torrent.AddURLs(magnets, &qbittorrent_model.AddTorrentsOptions{
 			Savepath: dest,
-			Tags:     r.qBittorrentClient.Tags,
+			Category: opts.Category,
+			Tags:     mergeTags(r.qBittorrentClient.Tags, opts.Tags),
 		})
 	case TransmissionClient:
+		labels := make([]string, 0, len(opts.Tags)+1)
+		if opts.Category != "" {
+			labels = append(labels, opts.Category)
+		}
+		labels = append(labels, opts.Tags...)
 		for _, magnet := range magnets {
-			_, err = r.transmission.Client.TorrentAdd(context.Background(), transmissionrpc.TorrentAddPayload{
+			payload := transmissionrpc.TorrentAddPayload{
 				Filename:    &magnet,
 				DownloadDir: &dest,
-			})
+			}
+			if len(labels) > 0 {
+				payload.Labels = labels
+			}
+			_, err = r.transmission.Client.TorrentAdd(context.Background(), payload)
 			if err != nil {
 				r.logger.Err(err).Msg("torrent client: Error while adding magnets (Transmission)")
 				break
@@ -255,6 +280,24 @@ func (r *Repository) AddMagnets(magnets []string, dest string) error {
 	return nil
 }
 
+// mergeTags merges the global comma-separated tags with additional tags, removing duplicates.
+func mergeTags(globalTags string, tags []string) string {
+	ret := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, tag := range append(strings.Split(globalTags, ","), tags...) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		ret = append(ret, tag)
+	}
+	return strings.Join(ret, ",")
+}
+
 func (r *Repository) RemoveTorrents(hashes []string) error {
 	r.logger.Trace().Msg("torrent client: Removing torrents")
 
@@ -54,7 +54,7 @@ func (r *Repository) SmartSelect(p *SmartSelectParams) error {
 			return err
 		}
 		// Add the torrent
-		err = r.AddMagnets([]string{magnet}, p.Destination)
+		err = r.AddMagnets([]string{magnet}, p.Destination, nil)
 		if err != nil {
 			return err
 		}
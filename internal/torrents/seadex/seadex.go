@@ -65,6 +65,32 @@ func (s *SeaDex) FetchTorrents(mediaId int, title string) (ret []*Torrent, err e
 
 }
 
+// FetchReleaseStatuses returns the info hashes of the releases listed on SeaDex for the media.
+// The value is true if the release is marked as the best release, false if it is an alternative.
+func (s *SeaDex) FetchReleaseStatuses(mediaId int) (ret map[string]bool, err error) {
+
+	ret = make(map[string]bool)
+
+	records, err := s.fetchRecords(mediaId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return ret, nil
+	}
+
+	for _, tr := range records[0].Expand.Trs {
+		if tr.InfoHash == "" || tr.InfoHash == "<redacted>" {
+			continue
+		}
+		hash := strings.ToLower(tr.InfoHash)
+		ret[hash] = ret[hash] || tr.IsBest
+	}
+
+	return ret, nil
+}
+
 func (s *SeaDex) fetchRecords(mediaId int) (ret []*RecordItem, err error) {
 
 	uri := fmt.Sprintf("%s?page=1&perPage=1&filter=alID%%3D%%22%d%%22&skipTotal=1&expand=trs", s.uri, mediaId)
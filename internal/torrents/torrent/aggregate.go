@@ -0,0 +1,292 @@
+package torrent
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"seanime/internal/extension"
+	"seanime/internal/util"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	hibiketorrent "seanime/internal/extension/hibike/torrent"
+)
+
+const (
+	// aggregateProviderTimeout is the maximum time a provider can take to respond during an aggregate search.
+	aggregateProviderTimeout = 20 * time.Second
+	// aggregateSeaDexTimeout is the maximum time to wait for SeaDex when annotating results.
+	aggregateSeaDexTimeout = 10 * time.Second
+)
+
+const (
+	SeaDexStatusBest        SeaDexStatus = "best"
+	SeaDexStatusAlternative SeaDexStatus = "alternative"
+)
+
+type (
+	// SeaDexStatus is the status of a release on SeaDex.
+	SeaDexStatus string
+
+	// TorrentSource is a provider that returned a torrent during an aggregate search.
+	TorrentSource struct {
+		Provider string `json:"provider"`
+		Link     string `json:"link"`
+	}
+
+	// ProviderSearchError is returned for each provider that failed during an aggregate search.
+	ProviderSearchError struct {
+		Provider string `json:"provider"`
+		Error    string `json:"error"`
+	}
+
+	providerSearchResult struct {
+		provider string
+		data     *SearchData
+		err      error
+	}
+)
+
+// searchAnimeAggregate searches all anime torrent providers concurrently and merges the results.
+// A provider that fails or times out does not fail the whole search, the error is returned in SearchData.ProviderErrors.
+func (r *Repository) searchAnimeAggregate(ctx context.Context, opts AnimeSearchOptions) (ret *SearchData, err error) {
+	defer util.HandlePanicInModuleWithError("torrents/torrent/searchAnimeAggregate", &err)
+
+	providers := make([]string, 0)
+	extension.RangeExtensions(r.extensionBank, func(id string, ext extension.AnimeTorrentProviderExtension) bool {
+		if opts.Type == AnimeSearchTypeSmart && !ext.GetProvider().GetSettings().CanSmartSearch {
+			return true
+		}
+		providers = append(providers, id)
+		return true
+	})
+	slices.Sort(providers)
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no torrent provider available")
+	}
+
+	r.logger.Debug().Strs("providers", providers).Str("type", string(opts.Type)).Str("query", opts.Query).Msg("torrent repo: Searching all providers")
+
+	// Fetch SeaDex releases while the providers are being searched
+	seadexCh := make(chan map[string]bool, 1)
+	go func() {
+		defer util.HandlePanicInModuleThen("torrents/torrent/searchAnimeAggregate/seadex", func() {
+			seadexCh <- nil
+		})
+		seadexCh <- r.fetchSeaDexReleaseStatuses(ctx, opts.Media.GetID())
+	}()
+
+	results := make([]*providerSearchResult, len(providers))
+	wg := sync.WaitGroup{}
+	wg.Add(len(providers))
+	for i, provider := range providers {
+		go func(i int, provider string) {
+			defer wg.Done()
+			results[i] = r.searchProviderWithTimeout(ctx, provider, opts)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	// Check if the context was cancelled
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	ret = mergeSearchResults(results, <-seadexCh)
+
+	if len(ret.ProviderErrors) == len(providers) {
+		return nil, fmt.Errorf("all torrent providers failed: %s", ret.ProviderErrors[0].Error)
+	}
+
+	return ret, nil
+}
+
+// searchProviderWithTimeout searches a single provider and gives up after aggregateProviderTimeout.
+func (r *Repository) searchProviderWithTimeout(ctx context.Context, provider string, opts AnimeSearchOptions) *providerSearchResult {
+	ctx, cancel := context.WithTimeout(ctx, aggregateProviderTimeout)
+	defer cancel()
+
+	opts.Provider = provider
+	opts.Aggregate = false
+
+	resultCh := make(chan *providerSearchResult, 1)
+	go func() {
+		defer util.HandlePanicInModuleThen("torrents/torrent/searchProviderWithTimeout", func() {
+			resultCh <- &providerSearchResult{provider: provider, err: errors.New("provider panicked")}
+		})
+		data, err := r.SearchAnime(ctx, opts)
+		resultCh <- &providerSearchResult{provider: provider, data: data, err: err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			r.logger.Warn().Err(res.err).Str("provider", provider).Msg("torrent repo: Provider search failed")
+		}
+		return res
+	case <-ctx.Done():
+		r.logger.Warn().Str("provider", provider).Msg("torrent repo: Provider search timed out")
+		return &providerSearchResult{provider: provider, err: errors.New("search timed out")}
+	}
+}
+
+// fetchSeaDexReleaseStatuses returns the SeaDex releases of the media, or nil if they could not be fetched in time.
+func (r *Repository) fetchSeaDexReleaseStatuses(ctx context.Context, mediaId int) map[string]bool {
+	ctx, cancel := context.WithTimeout(ctx, aggregateSeaDexTimeout)
+	defer cancel()
+
+	resultCh := make(chan map[string]bool, 1)
+	go func() {
+		defer util.HandlePanicInModuleThen("torrents/torrent/fetchSeaDexReleaseStatuses", func() {
+			resultCh <- nil
+		})
+		statuses, err := r.seadex.FetchReleaseStatuses(mediaId)
+		if err != nil {
+			r.logger.Warn().Err(err).Int("mediaId", mediaId).Msg("torrent repo: Failed to fetch SeaDex releases")
+		}
+		resultCh <- statuses
+	}()
+
+	select {
+	case statuses := <-resultCh:
+		return statuses
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// mergeSearchResults merges the results of each provider.
+//
+//   - Torrents with the same info hash are merged, the highest seeder count is kept and every source link is listed.
+//   - Torrents without an info hash are kept as is.
+//   - Torrents listed on SeaDex are annotated with their status.
+func mergeSearchResults(results []*providerSearchResult, seadexStatuses map[string]bool) *SearchData {
+	ret := &SearchData{
+		Torrents:       make([]*hibiketorrent.AnimeTorrent, 0),
+		Previews:       make([]*Preview, 0),
+		Sources:        make(map[string][]*TorrentSource),
+		SeaDexStatuses: make(map[string]SeaDexStatus),
+		ProviderErrors: make([]*ProviderSearchError, 0),
+	}
+
+	torrentsByHash := make(map[string]*hibiketorrent.AnimeTorrent)
+	previewsByHash := make(map[string]*Preview)
+
+	// Returns the merged torrent and true if the torrent was seen for the first time
+	mergeTorrent := func(provider string, t *hibiketorrent.AnimeTorrent) (*hibiketorrent.AnimeTorrent, bool) {
+		// Copy the torrent, the original is stored in the provider's cache
+		cp := *t
+		if cp.Provider == "" {
+			cp.Provider = provider
+		}
+
+		hash := strings.ToLower(cp.InfoHash)
+		if hash == "" {
+			return &cp, true
+		}
+		cp.InfoHash = hash
+
+		source := &TorrentSource{Provider: cp.Provider, Link: cp.Link}
+		existing, found := torrentsByHash[hash]
+		if !found {
+			torrentsByHash[hash] = &cp
+			ret.Sources[hash] = []*TorrentSource{source}
+			return &cp, true
+		}
+
+		if !slices.ContainsFunc(ret.Sources[hash], func(s *TorrentSource) bool { return *s == *source }) {
+			ret.Sources[hash] = append(ret.Sources[hash], source)
+		}
+		if cp.Seeders > existing.Seeders {
+			existing.Seeders = cp.Seeders
+			existing.Leechers = cp.Leechers
+		}
+		existing.IsBestRelease = existing.IsBestRelease || cp.IsBestRelease
+		existing.Confirmed = existing.Confirmed || cp.Confirmed
+		return existing, false
+	}
+
+	for _, res := range results {
+		if res == nil {
+			continue
+		}
+		if res.err != nil {
+			ret.ProviderErrors = append(ret.ProviderErrors, &ProviderSearchError{
+				Provider: res.provider,
+				Error:    res.err.Error(),
+			})
+			continue
+		}
+		if res.data == nil {
+			continue
+		}
+
+		for _, t := range res.data.Torrents {
+			if t == nil {
+				continue
+			}
+			if merged, isNew := mergeTorrent(res.provider, t); isNew {
+				ret.Torrents = append(ret.Torrents, merged)
+			}
+		}
+
+		for _, p := range res.data.Previews {
+			if p == nil || p.Torrent == nil {
+				continue
+			}
+			hash := strings.ToLower(p.Torrent.InfoHash)
+			if hash == "" {
+				cp := *p.Torrent
+				if cp.Provider == "" {
+					cp.Provider = res.provider
+				}
+				ret.Previews = append(ret.Previews, &Preview{Episode: p.Episode, Torrent: &cp})
+				continue
+			}
+			if existing, found := previewsByHash[hash]; found {
+				// Prefer previews that have episode information
+				if existing.Episode == nil && p.Episode != nil {
+					existing.Episode = p.Episode
+				}
+				continue
+			}
+			preview := &Preview{Episode: p.Episode, Torrent: torrentsByHash[hash]}
+			if preview.Torrent == nil {
+				var isNew bool
+				if preview.Torrent, isNew = mergeTorrent(res.provider, p.Torrent); isNew {
+					ret.Torrents = append(ret.Torrents, preview.Torrent)
+				}
+			}
+			previewsByHash[hash] = preview
+			ret.Previews = append(ret.Previews, preview)
+		}
+	}
+
+	// Annotate SeaDex releases
+	for hash, isBest := range seadexStatuses {
+		if _, found := torrentsByHash[hash]; !found {
+			continue
+		}
+		if isBest {
+			ret.SeaDexStatuses[hash] = SeaDexStatusBest
+		} else {
+			ret.SeaDexStatuses[hash] = SeaDexStatusAlternative
+		}
+	}
+
+	// Sort by seeders
+	slices.SortStableFunc(ret.Torrents, func(i, j *hibiketorrent.AnimeTorrent) int {
+		return cmp.Compare(j.Seeders, i.Seeders)
+	})
+	slices.SortStableFunc(ret.Previews, func(i, j *Preview) int {
+		return cmp.Compare(j.Torrent.Seeders, i.Torrent.Seeders)
+	})
+
+	return ret
+}
@@ -0,0 +1,66 @@
+package torrent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	hibiketorrent "seanime/internal/extension/hibike/torrent"
+)
+
+func TestMergeSearchResults(t *testing.T) {
+	nyaaTorrent := &hibiketorrent.AnimeTorrent{Name: "[SubsPlease] Title - 01 (1080p)", InfoHash: "ABC", Seeders: 100, Link: "https://nyaa.si/view/1"}
+	animetoshoTorrent := &hibiketorrent.AnimeTorrent{Name: "[SubsPlease] Title - 01 (1080p)", InfoHash: "abc", Seeders: 150, Link: "https://animetosho.org/view/1"}
+	batchTorrent := &hibiketorrent.AnimeTorrent{Name: "[Group] Title (BD 1080p)", InfoHash: "def", Seeders: 20, Link: "https://nyaa.si/view/2"}
+	noHashTorrent := &hibiketorrent.AnimeTorrent{Name: "[Group] Title - 01", Seeders: 5, Link: "https://example.com/1"}
+
+	results := []*providerSearchResult{
+		{
+			provider: "nyaa",
+			data: &SearchData{
+				Torrents: []*hibiketorrent.AnimeTorrent{nyaaTorrent, batchTorrent},
+				Previews: []*Preview{{Torrent: nyaaTorrent}, {Torrent: batchTorrent}},
+			},
+		},
+		{
+			provider: "animetosho",
+			data: &SearchData{
+				Torrents: []*hibiketorrent.AnimeTorrent{animetoshoTorrent, noHashTorrent},
+				Previews: []*Preview{{Torrent: animetoshoTorrent}, {Torrent: noHashTorrent}},
+			},
+		},
+		{
+			provider: "broken",
+			err:      errors.New("request failed"),
+		},
+	}
+
+	ret := mergeSearchResults(results, map[string]bool{"abc": false, "def": true, "unknown": true})
+
+	require.Len(t, ret.Torrents, 3)
+	assert.Equal(t, "abc", ret.Torrents[0].InfoHash)
+	assert.Equal(t, 150, ret.Torrents[0].Seeders)
+	assert.Equal(t, "nyaa", ret.Torrents[0].Provider)
+	assert.Equal(t, "def", ret.Torrents[1].InfoHash)
+	assert.Equal(t, "", ret.Torrents[2].InfoHash)
+	assert.Equal(t, "animetosho", ret.Torrents[2].Provider)
+
+	require.Len(t, ret.Previews, 3)
+	assert.Same(t, ret.Torrents[0], ret.Previews[0].Torrent)
+
+	sources := lo.Map(ret.Sources["abc"], func(s *TorrentSource, _ int) string { return s.Provider })
+	assert.Equal(t, []string{"nyaa", "animetosho"}, sources)
+
+	assert.Equal(t, map[string]SeaDexStatus{"abc": SeaDexStatusAlternative, "def": SeaDexStatusBest}, ret.SeaDexStatuses)
+
+	require.Len(t, ret.ProviderErrors, 1)
+	assert.Equal(t, "broken", ret.ProviderErrors[0].Provider)
+
+	// The original torrents should not be modified
+	assert.Equal(t, 100, nyaaTorrent.Seeders)
+	assert.Equal(t, "ABC", nyaaTorrent.InfoHash)
+	assert.Equal(t, "", nyaaTorrent.Provider)
+}
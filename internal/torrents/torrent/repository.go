@@ -3,6 +3,7 @@ package torrent
 import (
 	"seanime/internal/api/metadata"
 	"seanime/internal/extension"
+	"seanime/internal/torrents/seadex"
 	"seanime/internal/util/result"
 	"sync"
 
@@ -17,6 +18,7 @@ type (
 		animeProviderSmartSearchCaches *result.Map[string, *result.Cache[string, *SearchData]]
 		settings                       RepositorySettings
 		metadataProvider               metadata.Provider
+		seadex                         *seadex.SeaDex
 		mu                             sync.Mutex
 	}
 
@@ -38,6 +40,7 @@ func NewRepository(opts *NewRepositoryOptions) *Repository {
 		animeProviderSearchCaches:      result.NewResultMap[string, *result.Cache[string, *SearchData]](),
 		animeProviderSmartSearchCaches: result.NewResultMap[string, *result.Cache[string, *SearchData]](),
 		settings:                       RepositorySettings{},
+		seadex:                         seadex.New(opts.Logger),
 		mu:                             sync.Mutex{},
 	}
 
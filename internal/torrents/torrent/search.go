@@ -34,8 +34,10 @@ type (
 	AnimeSearchOptions struct {
 		// Provider extension ID
 		Provider string
-		Type     AnimeSearchType
-		Media    *anilist.BaseAnime
+		// Search all providers and merge the results, Provider is ignored
+		Aggregate bool
+		Type      AnimeSearchType
+		Media     *anilist.BaseAnime
 		// Search options
 		Query string
 		// Filter options
@@ -56,12 +58,20 @@ type (
 		Torrents                  []*hibiketorrent.AnimeTorrent                    `json:"torrents"`                  // Torrents found
 		Previews                  []*Preview                                       `json:"previews"`                  // TorrentPreview for each torrent
 		DebridInstantAvailability map[string]debrid.TorrentItemInstantAvailability `json:"debridInstantAvailability"` // Debrid instant availability
+		// Aggregate search only
+		Sources        map[string][]*TorrentSource `json:"sources,omitempty"`        // Providers that returned each torrent, keyed by info hash
+		SeaDexStatuses map[string]SeaDexStatus     `json:"seadexStatuses,omitempty"` // SeaDex status of each torrent, keyed by info hash
+		ProviderErrors []*ProviderSearchError      `json:"providerErrors,omitempty"` // Providers that failed or timed out
 	}
 )
 
 func (r *Repository) SearchAnime(ctx context.Context, opts AnimeSearchOptions) (ret *SearchData, err error) {
 	defer util.HandlePanicInModuleWithError("torrents/torrent/SearchAnime", &err)
 
+	if opts.Aggregate {
+		return r.searchAnimeAggregate(ctx, opts)
+	}
+
 	r.logger.Debug().Str("provider", opts.Provider).Str("type", string(opts.Type)).Str("query", opts.Query).Msg("torrent repo: Searching for anime torrents")
 
 	// Find the provider by ID
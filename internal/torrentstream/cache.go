@@ -0,0 +1,317 @@
+package torrentstream
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"path/filepath"
+	"seanime/internal/util"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent"
+)
+
+const (
+	// cachePolicyInterval is how often the seeding goals and the retention policy are enforced.
+	cachePolicyInterval = 1 * time.Minute
+)
+
+var ErrCacheEntryNotFound = errors.New("torrentstream: cache entry not found")
+
+type (
+	// cacheEntry is a torrent that was streamed and kept after the stream stopped.
+	// Its files stay in the download directory and it is seeded until the seeding goal is reached.
+	cacheEntry struct {
+		torrent       *torrent.Torrent
+		mediaId       int
+		episodeNumber int
+		filename      string
+		lastUsedAt    time.Time
+		seeding       bool
+	}
+
+	// CacheEntryInfo is the public representation of a cache entry.
+	CacheEntryInfo struct {
+		InfoHash      string    `json:"infoHash"`
+		Name          string    `json:"name"`
+		MediaId       int       `json:"mediaId"`
+		EpisodeNumber int       `json:"episodeNumber"`
+		Filename      string    `json:"filename"`
+		Size          int64     `json:"size"`     // Bytes stored on disk
+		Uploaded      int64     `json:"uploaded"` // Bytes uploaded to peers
+		Ratio         float64   `json:"ratio"`
+		Seeding       bool      `json:"seeding"`
+		LastUsedAt    time.Time `json:"lastUsedAt"`
+		// True if the media is in the user's AniList collection
+		InLibrary bool `json:"inLibrary"`
+	}
+
+	// cachePolicy is the retention policy and seeding goals derived from the settings.
+	cachePolicy struct {
+		maxSize       int64         // 0 means no limit
+		maxAge        time.Duration // 0 means no limit
+		keepInLibrary bool
+		seedRatio     float64       // 0 means no ratio goal
+		seedDuration  time.Duration // 0 means no duration goal
+	}
+)
+
+func (c *Client) getCachePolicy() cachePolicy {
+	settings, ok := c.repository.settings.Get()
+	if !ok {
+		return cachePolicy{}
+	}
+	return cachePolicy{
+		maxSize:       int64(max(settings.CacheMaxSize, 0)) * 1024 * 1024 * 1024,
+		maxAge:        time.Duration(max(settings.CacheMaxAge, 0)) * time.Hour,
+		keepInLibrary: settings.CacheKeepInLibrary,
+		seedRatio:     max(settings.SeedRatio, 0),
+		seedDuration:  time.Duration(max(settings.SeedDuration, 0)) * time.Minute,
+	}
+}
+
+// hasSeedingGoal returns true if the torrents should stop seeding at some point.
+// Without a goal, the torrents are seeded until they are evicted.
+func (p cachePolicy) hasSeedingGoal() bool {
+	return p.seedRatio > 0 || p.seedDuration > 0
+}
+
+// seedingGoalReached returns true if the entry has reached one of the seeding goals.
+func (p cachePolicy) seedingGoalReached(info *CacheEntryInfo, now time.Time) bool {
+	if !p.hasSeedingGoal() {
+		return false
+	}
+	if p.seedRatio > 0 && info.Ratio >= p.seedRatio {
+		return true
+	}
+	if p.seedDuration > 0 && now.Sub(info.LastUsedAt) >= p.seedDuration {
+		return true
+	}
+	return false
+}
+
+// selectCacheEvictions returns the info hashes of the entries that should be evicted.
+//
+//   - Entries that have not been used for longer than the max age are evicted, unless they are kept because they are in the library.
+//   - If the total size exceeds the max size, the least recently used entries are evicted first.
+//     Entries kept because they are in the library are evicted last.
+func selectCacheEvictions(entries []*CacheEntryInfo, policy cachePolicy, now time.Time) []string {
+	ret := make([]string, 0)
+
+	isKept := func(e *CacheEntryInfo) bool {
+		return policy.keepInLibrary && e.InLibrary
+	}
+
+	remaining := make([]*CacheEntryInfo, 0, len(entries))
+	for _, e := range entries {
+		if policy.maxAge > 0 && now.Sub(e.LastUsedAt) > policy.maxAge && !isKept(e) {
+			ret = append(ret, e.InfoHash)
+			continue
+		}
+		remaining = append(remaining, e)
+	}
+
+	if policy.maxSize <= 0 {
+		return ret
+	}
+
+	var totalSize int64
+	for _, e := range remaining {
+		totalSize += e.Size
+	}
+
+	// Least recently used first, kept entries last
+	slices.SortStableFunc(remaining, func(a, b *CacheEntryInfo) int {
+		if isKept(a) != isKept(b) {
+			if isKept(a) {
+				return 1
+			}
+			return -1
+		}
+		return a.LastUsedAt.Compare(b.LastUsedAt)
+	})
+
+	for _, e := range remaining {
+		if totalSize <= policy.maxSize {
+			break
+		}
+		ret = append(ret, e.InfoHash)
+		totalSize -= e.Size
+	}
+
+	return ret
+}
+
+func (e *cacheEntry) toCacheEntryInfo() *CacheEntryInfo {
+	ret := &CacheEntryInfo{
+		InfoHash:      e.torrent.InfoHash().HexString(),
+		Name:          e.torrent.Name(),
+		MediaId:       e.mediaId,
+		EpisodeNumber: e.episodeNumber,
+		Filename:      e.filename,
+		Size:          e.torrent.BytesCompleted(),
+		Seeding:       e.seeding,
+		LastUsedAt:    e.lastUsedAt,
+	}
+	bytesWrittenData := e.torrent.Stats().BytesWrittenData
+	ret.Uploaded = (&bytesWrittenData).Int64()
+	if ret.Size > 0 {
+		ret.Ratio = float64(ret.Uploaded) / float64(ret.Size)
+	}
+	return ret
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// addCacheEntryLocked keeps the torrent of the session after the stream stops.
+func (c *Client) addCacheEntryLocked(session *streamSession) {
+	if session.torrent == nil || c.isTorrentUsedLocked(session.torrent.InfoHash().HexString()) {
+		return
+	}
+	entry := &cacheEntry{
+		torrent:       session.torrent,
+		mediaId:       session.mediaId,
+		episodeNumber: session.episodeNumber,
+		lastUsedAt:    time.Now(),
+		seeding:       true,
+	}
+	if session.file != nil {
+		entry.filename = filepath.Base(session.file.DisplayPath())
+	}
+	c.cache[session.torrent.InfoHash().HexString()] = entry
+}
+
+// removeCacheEntryLocked removes the torrent from the cache when it is streamed again.
+// The torrent is allowed to seed again if it stopped seeding.
+func (c *Client) removeCacheEntryLocked(t *torrent.Torrent) {
+	infoHash := t.InfoHash().HexString()
+	entry, ok := c.cache[infoHash]
+	if !ok {
+		return
+	}
+	if !entry.seeding {
+		t.AllowDataUpload()
+	}
+	delete(c.cache, infoHash)
+}
+
+// runCachePolicy enforces the seeding goals and the retention policy until the context is cancelled.
+func (c *Client) runCachePolicy(ctx context.Context) {
+	ticker := time.NewTicker(cachePolicyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.dropExpiredPrebufferedEpisodes()
+			c.enforceCachePolicy()
+		}
+	}
+}
+
+// enforceCachePolicy stops seeding the torrents that reached their seeding goal and evicts the entries selected by the retention policy.
+func (c *Client) enforceCachePolicy() {
+	defer util.HandlePanicInModuleThen("torrentstream/enforceCachePolicy", func() {})
+
+	policy := c.getCachePolicy()
+
+	c.mu.Lock()
+	isEmpty := len(c.cache) == 0
+	c.mu.Unlock()
+	if isEmpty {
+		return
+	}
+
+	inLibrary := c.repository.getLibraryMediaIds(policy.keepInLibrary)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	infos := make([]*CacheEntryInfo, 0, len(c.cache))
+	for infoHash, entry := range c.cache {
+		info := entry.toCacheEntryInfo()
+		info.InLibrary = inLibrary[entry.mediaId]
+		infos = append(infos, info)
+
+		if entry.seeding && policy.seedingGoalReached(info, now) {
+			entry.seeding = false
+			entry.torrent.DisallowDataUpload()
+			c.repository.logger.Debug().Str("infoHash", infoHash).Float64("ratio", info.Ratio).Msg("torrentstream: Seeding goal reached")
+		}
+	}
+
+	for _, infoHash := range selectCacheEvictions(infos, policy, now) {
+		entry, ok := c.cache[infoHash]
+		if !ok {
+			continue
+		}
+		c.repository.logger.Debug().Str("infoHash", infoHash).Msg("torrentstream: Evicting torrent from the cache")
+		c.dropTorrentLocked(entry.torrent)
+	}
+}
+
+// getLibraryMediaIds returns the IDs of the media in the user's AniList collection.
+func (r *Repository) getLibraryMediaIds(enabled bool) map[int]bool {
+	ret := make(map[int]bool)
+	if !enabled || r.platform == nil {
+		return ret
+	}
+	collection, err := r.platform.GetAnimeCollection(false)
+	if err != nil || collection == nil {
+		r.logger.Warn().Err(err).Msg("torrentstream: Could not get the anime collection")
+		return ret
+	}
+	for _, media := range collection.GetAllAnime() {
+		ret[media.GetID()] = true
+	}
+	return ret
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// GetCacheEntries returns the torrents kept in the stream cache, most recently used first.
+func (r *Repository) GetCacheEntries() []*CacheEntryInfo {
+	policy := r.client.getCachePolicy()
+	inLibrary := r.getLibraryMediaIds(policy.keepInLibrary)
+
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	ret := make([]*CacheEntryInfo, 0, len(r.client.cache))
+	for _, entry := range r.client.cache {
+		info := entry.toCacheEntryInfo()
+		info.InLibrary = inLibrary[entry.mediaId]
+		ret = append(ret, info)
+	}
+	slices.SortFunc(ret, func(a, b *CacheEntryInfo) int {
+		return cmp.Compare(b.LastUsedAt.UnixNano(), a.LastUsedAt.UnixNano())
+	})
+	return ret
+}
+
+// RemoveCacheEntry drops the cached torrent and deletes its files.
+// If infoHash is empty, the whole cache is cleared.
+func (r *Repository) RemoveCacheEntry(infoHash string) error {
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	if infoHash == "" {
+		for _, entry := range r.client.cache {
+			r.client.dropTorrentLocked(entry.torrent)
+		}
+		r.logger.Info().Msg("torrentstream: Cleared the stream cache")
+		return nil
+	}
+
+	entry, ok := r.client.cache[strings.ToLower(infoHash)]
+	if !ok {
+		return ErrCacheEntryNotFound
+	}
+	r.client.dropTorrentLocked(entry.torrent)
+	return nil
+}
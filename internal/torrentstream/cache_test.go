@@ -0,0 +1,69 @@
+package torrentstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectCacheEvictions(t *testing.T) {
+	now := time.Now()
+	gb := int64(1024 * 1024 * 1024)
+
+	entries := []*CacheEntryInfo{
+		{InfoHash: "old", Size: 1 * gb, LastUsedAt: now.Add(-48 * time.Hour)},
+		{InfoHash: "old-library", Size: 1 * gb, LastUsedAt: now.Add(-72 * time.Hour), InLibrary: true},
+		{InfoHash: "recent", Size: 2 * gb, LastUsedAt: now.Add(-1 * time.Hour)},
+		{InfoHash: "less-recent", Size: 2 * gb, LastUsedAt: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		policy   cachePolicy
+		expected []string
+	}{
+		{
+			name:     "no limit",
+			policy:   cachePolicy{},
+			expected: []string{},
+		},
+		{
+			name:     "max age",
+			policy:   cachePolicy{maxAge: 24 * time.Hour},
+			expected: []string{"old", "old-library"},
+		},
+		{
+			name:     "max age, keep in library",
+			policy:   cachePolicy{maxAge: 24 * time.Hour, keepInLibrary: true},
+			expected: []string{"old"},
+		},
+		{
+			name:     "max size",
+			policy:   cachePolicy{maxSize: 3 * gb},
+			expected: []string{"old-library", "old", "less-recent"},
+		},
+		{
+			name:     "max size, keep in library",
+			policy:   cachePolicy{maxSize: 3 * gb, keepInLibrary: true},
+			expected: []string{"old", "less-recent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, selectCacheEvictions(entries, tt.policy, now))
+		})
+	}
+}
+
+func TestSeedingGoalReached(t *testing.T) {
+	now := time.Now()
+	info := &CacheEntryInfo{Ratio: 1.2, LastUsedAt: now.Add(-30 * time.Minute)}
+
+	assert.False(t, cachePolicy{}.seedingGoalReached(info, now))
+	assert.True(t, cachePolicy{seedRatio: 1}.seedingGoalReached(info, now))
+	assert.False(t, cachePolicy{seedRatio: 2}.seedingGoalReached(info, now))
+	assert.True(t, cachePolicy{seedRatio: 2, seedDuration: 20 * time.Minute}.seedingGoalReached(info, now))
+	assert.False(t, cachePolicy{seedDuration: time.Hour}.seedingGoalReached(info, now))
+}
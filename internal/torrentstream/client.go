@@ -26,19 +26,19 @@ type (
 	Client struct {
 		repository *Repository
 
-		torrentClient        mo.Option[*torrent.Client]
-		currentTorrent       mo.Option[*torrent.Torrent]
-		currentFile          mo.Option[*torrent.File]
-		currentTorrentStatus TorrentStatus
-		cancelFunc           context.CancelFunc
+		torrentClient mo.Option[*torrent.Client]
+		sessions      map[string]*streamSession // Active stream sessions, keyed by session ID
+		// Torrents added but not bound to a session yet, keyed by info hash
+		pendingTorrents map[string]time.Time
+		// Next episodes prebuffered by sessions that have stopped, keyed by session ID
+		prebufferedEpisodes map[string]*prebufferedEpisode
+		// Torrents kept after their stream stopped, keyed by info hash
+		cache      map[string]*cacheEntry
+		cancelFunc context.CancelFunc
 
 		mu                          sync.Mutex
-		stopCh                      chan struct{}                    // Closed when the media player stops
 		mediaPlayerPlaybackStatusCh chan *mediaplayer.PlaybackStatus // Continuously receives playback status
 		timeSinceLoggedSeeding      time.Time
-		lastSpeedCheck              time.Time // Track the last time we checked speeds
-		lastBytesCompleted          int64     // Track the last bytes completed
-		lastBytesWrittenData        int64     // Track the last bytes written data
 	}
 
 	TorrentStatus struct {
@@ -60,9 +60,10 @@ func NewClient(repository *Repository) *Client {
 	ret := &Client{
 		repository:                  repository,
 		torrentClient:               mo.None[*torrent.Client](),
-		currentFile:                 mo.None[*torrent.File](),
-		currentTorrent:              mo.None[*torrent.Torrent](),
-		stopCh:                      make(chan struct{}),
+		sessions:                    make(map[string]*streamSession),
+		pendingTorrents:             make(map[string]time.Time),
+		prebufferedEpisodes:         make(map[string]*prebufferedEpisode),
+		cache:                       make(map[string]*cacheEntry),
 		mediaPlayerPlaybackStatusCh: make(chan *mediaplayer.PlaybackStatus, 1),
 	}
 
@@ -70,7 +71,7 @@ func NewClient(repository *Repository) *Client {
 }
 
 // initializeClient will create and torrent client.
-// The client is designed to support only one torrent at a time, and seed it.
+// The client can stream multiple torrents at a time, one per session.
 // Upon initialization, the client will drop all torrents.
 func (c *Client) initializeClient() error {
 	// Fail if no settings
@@ -130,8 +131,15 @@ func (c *Client) initializeClient() error {
 	c.repository.logger.Info().Msgf("torrentstream: Initialized torrent client on port %d", settings.TorrentClientPort)
 	c.torrentClient = mo.Some(client)
 	c.dropTorrents()
+	c.sessions = make(map[string]*streamSession)
+	c.pendingTorrents = make(map[string]time.Time)
+	c.prebufferedEpisodes = make(map[string]*prebufferedEpisode)
+	c.cache = make(map[string]*cacheEntry)
 	c.mu.Unlock()
 
+	go c.runCachePolicy(ctx)
+	go c.repository.runSessionPersistence(ctx)
+
 	go func(ctx context.Context) {
 
 		for {
@@ -139,103 +147,45 @@ func (c *Client) initializeClient() error {
 			case <-ctx.Done():
 				c.repository.logger.Debug().Msg("torrentstream: Context cancelled, stopping torrent client")
 				return
-			//case <-c.stopCh:
-			//	c.mu.Lock()
-			//	c.stopCh = make(chan struct{})
-			//	c.repository.logger.Debug().Msg("torrentstream: Handling media player stopped event")
-			//	// This is to prevent the client from downloading the whole torrent when the user stops watching
-			//	// Also, the torrent might be a batch - so we don't want to download the whole thing
-			//	if c.currentTorrent.IsPresent() {
-			//		if c.currentTorrentStatus.ProgressPercentage < 70 {
-			//			c.repository.logger.Debug().Msg("torrentstream: Dropping torrent, completion is less than 70%")
-			//			c.dropTorrents()
-			//		}
-			//		c.repository.logger.Debug().Msg("torrentstream: Resetting current torrent and status")
-			//	}
-			//	c.currentTorrent = mo.None[*torrent.Torrent]()                  // Reset the current torrent
-			//	c.currentFile = mo.None[*torrent.File]()                        // Reset the current file
-			//	c.currentTorrentStatus = TorrentStatus{}                        // Reset the torrent status
-			//	c.repository.serverManager.stopServer()                         // Stop streaming server
-			//	c.repository.wsEventManager.SendEvent(eventTorrentStopped, nil) // Send torrent stopped event
-			//	c.repository.mediaPlayerRepository.Stop()                       // Stop the media player gracefully if it's running
-			//	c.mu.Unlock()
-
 			case status := <-c.mediaPlayerPlaybackStatusCh:
 				// DEVNOTE: When this is received, "default" case is executed right after
-				if status != nil && c.currentFile.IsPresent() && c.repository.playback.currentVideoDuration == 0 {
+				session, ok := c.getMediaPlayerSession()
+				if status != nil && ok {
+					c.repository.updatePlaybackProgress(session, status.CompletionPercentage)
+					c.mu.Lock()
+					session.currentTime = status.CurrentTimeInSeconds
+					session.duration = status.DurationInSeconds
+					c.mu.Unlock()
+					c.repository.prefetchExtraction(session, status.CurrentTimeInSeconds)
+				}
+				if status != nil && ok && session.videoDuration == 0 {
 					// If the stored video duration is 0 but the media player status shows a duration that is not 0
 					// we know that the video has been loaded and is playing
-					if c.repository.playback.currentVideoDuration == 0 && status.Duration > 0 {
+					if status.Duration > 0 {
 						// The media player has started playing the video
-						c.repository.logger.Debug().Msg("torrentstream: Media player started playing the video, sending event")
-						c.repository.wsEventManager.SendEvent(eventTorrentStartedPlaying, nil)
+						c.repository.logger.Debug().Str("sessionId", session.id).Msg("torrentstream: Media player started playing the video, sending event")
+						c.repository.sendEventToClient(session.clientId, eventTorrentStartedPlaying, nil)
 						// Update the stored video duration
-						c.repository.playback.currentVideoDuration = status.Duration
+						c.mu.Lock()
+						session.videoDuration = status.Duration
+						c.mu.Unlock()
 					}
 				}
 			default:
 				c.mu.Lock()
-				if c.torrentClient.IsPresent() && c.currentTorrent.IsPresent() && c.currentFile.IsPresent() {
-					t := c.currentTorrent.MustGet()
-					f := c.currentFile.MustGet()
-
-					// Get the current time
-					now := time.Now()
-					elapsed := now.Sub(c.lastSpeedCheck).Seconds()
-
-					// downloadProgress is the number of bytes downloaded
-					downloadProgress := t.BytesCompleted()
-
-					downloadSpeed := ""
-					if elapsed > 0 {
-						bytesPerSecond := float64(downloadProgress-c.lastBytesCompleted) / elapsed
-						if bytesPerSecond > 0 {
-							downloadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
-						}
-					}
-					size := humanize.Bytes(uint64(f.Length()))
-
-					bytesWrittenData := t.Stats().BytesWrittenData
-					uploadSpeed := ""
-					if elapsed > 0 {
-						bytesPerSecond := float64((&bytesWrittenData).Int64()-c.lastBytesWrittenData) / elapsed
-						if bytesPerSecond > 0 {
-							uploadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
-						}
-					}
-
-					// Update the stored values for next calculation
-					c.lastBytesCompleted = downloadProgress
-					c.lastBytesWrittenData = (&bytesWrittenData).Int64()
-					c.lastSpeedCheck = now
-
-					if t.PeerConns() != nil {
-						c.currentTorrentStatus.Seeders = len(t.PeerConns())
-					}
-
-					//// If the torrent status went from 0% to > 0%, send an event, as the torrent has been loaded
-					//if c.currentTorrentStatus.ProgressPercentage == 0. && c.getTorrentPercentage(c.currentTorrent) > 0. {
-					//	c.repository.logger.Debug().Msg("torrentstream: Torrent loaded, sending event")
-					//	c.repository.wsEventManager.SendEvent(eventTorrentLoaded, nil)
-					//}
-
-					c.currentTorrentStatus = TorrentStatus{
-						Size:               size,
-						UploadProgress:     (&bytesWrittenData).Int64() - c.currentTorrentStatus.UploadProgress,
-						DownloadSpeed:      downloadSpeed,
-						UploadSpeed:        uploadSpeed,
-						DownloadProgress:   downloadProgress,
-						ProgressPercentage: c.getTorrentPercentage(c.currentTorrent, c.currentFile),
-						Seeders:            t.Stats().ConnectedSeeders,
+				if c.torrentClient.IsPresent() {
+					for _, session := range c.getSessionsLocked() {
+						c.updateSessionStatus(session)
+						c.repository.sendEventToClient(session.clientId, eventTorrentStatus, session.status)
+						// Always log the progress so the user knows what's happening
+						c.repository.logger.Trace().Msgf("torrentstream: [%s] Progress: %.2f%%, Download speed: %s, Upload speed: %s, Size: %s",
+							session.id,
+							session.status.ProgressPercentage,
+							session.status.DownloadSpeed,
+							session.status.UploadSpeed,
+							session.status.Size)
+						c.timeSinceLoggedSeeding = time.Now()
 					}
-					c.repository.wsEventManager.SendEvent(eventTorrentStatus, c.currentTorrentStatus)
-					// Always log the progress so the user knows what's happening
-					c.repository.logger.Trace().Msgf("torrentstream: Progress: %.2f%%, Download speed: %s, Upload speed: %s, Size: %s",
-						c.currentTorrentStatus.ProgressPercentage,
-						c.currentTorrentStatus.DownloadSpeed,
-						c.currentTorrentStatus.UploadSpeed,
-						c.currentTorrentStatus.Size)
-					c.timeSinceLoggedSeeding = time.Now()
 				}
 				c.mu.Unlock()
 				if c.torrentClient.IsPresent() {
@@ -256,13 +206,63 @@ func (c *Client) initializeClient() error {
 	return nil
 }
 
-func (c *Client) GetStreamingUrl() string {
+// updateSessionStatus computes the download status of the session.
+// The client's mutex should be locked.
+func (c *Client) updateSessionStatus(session *streamSession) {
+	t := session.torrent
+	f := session.file
+
+	// Get the current time
+	now := time.Now()
+	elapsed := now.Sub(session.lastSpeedCheck).Seconds()
+
+	// downloadProgress is the number of bytes downloaded
+	downloadProgress := t.BytesCompleted()
+
+	downloadSpeed := ""
+	if elapsed > 0 {
+		bytesPerSecond := float64(downloadProgress-session.lastBytesCompleted) / elapsed
+		if bytesPerSecond > 0 {
+			downloadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
+		}
+	}
+	size := humanize.Bytes(uint64(f.Length()))
+
+	bytesWrittenData := t.Stats().BytesWrittenData
+	uploadSpeed := ""
+	if elapsed > 0 {
+		bytesPerSecond := float64((&bytesWrittenData).Int64()-session.lastBytesWrittenData) / elapsed
+		if bytesPerSecond > 0 {
+			uploadSpeed = fmt.Sprintf("%s/s", humanize.Bytes(uint64(bytesPerSecond)))
+		}
+	}
+
+	// Update the stored values for next calculation
+	session.lastBytesCompleted = downloadProgress
+	session.lastBytesWrittenData = (&bytesWrittenData).Int64()
+	session.lastSpeedCheck = now
+
+	session.status = TorrentStatus{
+		Size:               size,
+		UploadProgress:     (&bytesWrittenData).Int64() - session.status.UploadProgress,
+		DownloadSpeed:      downloadSpeed,
+		UploadSpeed:        uploadSpeed,
+		DownloadProgress:   downloadProgress,
+		ProgressPercentage: c.getTorrentPercentage(f),
+		Seeders:            t.Stats().ConnectedSeeders,
+	}
+}
+
+// GetStreamingUrl returns the URL of the session's stream.
+// The session ID is part of the path so that the server can route the request.
+func (c *Client) GetStreamingUrl(session *streamSession) string {
 	if c.torrentClient.IsAbsent() {
 		return ""
 	}
-	if c.currentFile.IsAbsent() {
+	if session == nil || session.file == nil {
 		return ""
 	}
+	streamPath := url.PathEscape(session.id) + "/" + url.PathEscape(session.file.DisplayPath())
 	settings, ok := c.repository.settings.Get()
 	if !ok {
 		return ""
@@ -277,23 +277,20 @@ func (c *Client) GetStreamingUrl() string {
 		if settings.StreamUrlAddress != "" {
 			address = settings.StreamUrlAddress
 		}
-		_url := fmt.Sprintf("http://%s/api/v1/torrentstream/stream/%s", address, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+		_url := fmt.Sprintf("http://%s/api/v1/torrentstream/stream/%s", address, streamPath)
 		if strings.HasPrefix(_url, "http://http") {
 			_url = strings.Replace(_url, "http://http", "http", 1)
 		}
 		return _url
 	}
 
-	//if settings.StreamingServerHost == "0.0.0.0" {
-	//	return fmt.Sprintf("http://127.0.0.1:%d/stream/%s", settings.StreamingServerPort, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
-	//}
 	host := settings.StreamingServerHost
 	if host == "" {
 		host = "127.0.0.1"
 	}
-	_url := fmt.Sprintf("http://%s:%d/stream/%s", host, settings.StreamingServerPort, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+	_url := fmt.Sprintf("http://%s:%d/stream/%s", host, settings.StreamingServerPort, streamPath)
 	if settings.StreamUrlAddress != "" {
-		_url = fmt.Sprintf("http://%s/stream/%s", settings.StreamUrlAddress, url.PathEscape(c.currentFile.MustGet().DisplayPath()))
+		_url = fmt.Sprintf("http://%s/stream/%s", settings.StreamUrlAddress, streamPath)
 		if strings.HasPrefix(_url, "http://http") {
 			_url = strings.Replace(_url, "http://http", "http", 1)
 		}
@@ -306,20 +303,28 @@ func (c *Client) AddTorrent(id string) (*torrent.Torrent, error) {
 		return nil, errors.New("torrent client is not initialized")
 	}
 
-	// Drop all torrents
-	for _, t := range c.torrentClient.MustGet().Torrents() {
-		t.Drop()
+	var t *torrent.Torrent
+	var err error
+	switch {
+	case strings.HasPrefix(id, "magnet"):
+		t, err = c.addTorrentMagnet(id)
+	case strings.HasPrefix(id, "http"):
+		t, err = c.addTorrentFromDownloadURL(id)
+	default:
+		t, err = c.addTorrentFromFile(id)
 	}
-
-	if strings.HasPrefix(id, "magnet") {
-		return c.addTorrentMagnet(id)
+	if err != nil {
+		return nil, err
 	}
 
-	if strings.HasPrefix(id, "http") {
-		return c.addTorrentFromDownloadURL(id)
+	// Keep track of the torrent until it is bound to a session
+	c.mu.Lock()
+	if !c.isTorrentUsedLocked(t.InfoHash().HexString()) {
+		c.pendingTorrents[t.InfoHash().HexString()] = time.Now()
 	}
+	c.mu.Unlock()
 
-	return c.addTorrentFromFile(id)
+	return t, nil
 }
 
 func (c *Client) addTorrentMagnet(magnet string) (*torrent.Torrent, error) {
@@ -410,9 +415,16 @@ func (c *Client) Shutdown() (errs []error) {
 	if c.torrentClient.IsAbsent() {
 		return
 	}
+	c.mu.Lock()
 	c.dropTorrents()
-	c.currentTorrent = mo.None[*torrent.Torrent]()
-	c.currentTorrentStatus = TorrentStatus{}
+	for id := range c.sessions {
+		c.repository.stopExtraction(id)
+	}
+	c.sessions = make(map[string]*streamSession)
+	c.pendingTorrents = make(map[string]time.Time)
+	c.prebufferedEpisodes = make(map[string]*prebufferedEpisode)
+	c.cache = make(map[string]*cacheEntry)
+	c.mu.Unlock()
 	c.repository.logger.Debug().Msg("torrentstream: Closing torrent client")
 	return c.torrentClient.MustGet().Close()
 }
@@ -439,10 +451,19 @@ func (c *Client) RemoveTorrent(infoHash string) error {
 
 	c.repository.logger.Trace().Msgf("torrentstream: Removing torrent: %s", infoHash)
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	torrents := c.torrentClient.MustGet().Torrents()
 	for _, t := range torrents {
 		if t.InfoHash().AsString() == infoHash {
+			// Do not remove a torrent that is streamed by another session
+			if c.isTorrentUsedLocked(t.InfoHash().HexString()) {
+				return nil
+			}
 			t.Drop()
+			delete(c.pendingTorrents, t.InfoHash().HexString())
+			delete(c.cache, t.InfoHash().HexString())
 			c.repository.logger.Debug().Msgf("torrentstream: Removed torrent: %s", infoHash)
 			return nil
 		}
@@ -461,11 +482,12 @@ func (c *Client) dropTorrents() {
 	}
 
 	if c.repository.settings.IsPresent() {
-		// Delete all torrents
+		// Delete all torrents, except the ones of the persisted sessions so that they can be resumed
+		persisted := c.repository.getPersistedInfoHashes()
 		fe, err := os.ReadDir(c.repository.settings.MustGet().DownloadDir)
 		if err == nil {
 			for _, f := range fe {
-				if f.IsDir() {
+				if f.IsDir() && !persisted[f.Name()] {
 					_ = os.RemoveAll(path.Join(c.repository.settings.MustGet().DownloadDir, f.Name()))
 				}
 			}
@@ -477,28 +499,28 @@ func (c *Client) dropTorrents() {
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-// getTorrentPercentage returns the percentage of the current torrent file
-// If no torrent is selected, it returns -1
-func (c *Client) getTorrentPercentage(t mo.Option[*torrent.Torrent], f mo.Option[*torrent.File]) float64 {
-	if t.IsAbsent() || f.IsAbsent() {
+// getTorrentPercentage returns the percentage of the torrent file
+// If no file is selected, it returns -1
+func (c *Client) getTorrentPercentage(f *torrent.File) float64 {
+	if f == nil {
 		return -1
 	}
 
-	if f.MustGet().Length() == 0 {
+	if f.Length() == 0 {
 		return 0
 	}
 
-	return float64(f.MustGet().BytesCompleted()) / float64(f.MustGet().Length()) * 100
+	return float64(f.BytesCompleted()) / float64(f.Length()) * 100
 }
 
 // readyToStream determines if enough of the file has been downloaded to begin streaming
 // Uses both absolute size (minimum buffer) and a percentage-based approach
-func (c *Client) readyToStream() bool {
-	if c.currentTorrent.IsAbsent() || c.currentFile.IsAbsent() {
+func (c *Client) readyToStream(session *streamSession) bool {
+	if session == nil || session.torrent == nil || session.file == nil {
 		return false
 	}
 
-	file := c.currentFile.MustGet()
+	file := session.file
 
 	// Always need at least 1MB to start playback (typical header size for many formats)
 	const minimumBufferBytes int64 = 1 * 1024 * 1024 // 1MB
@@ -0,0 +1,32 @@
+package torrentstream
+
+import (
+	"seanime/internal/continuity"
+)
+
+// saveSessionWatchHistory saves the last position of the session to the watch history of its episode.
+// The position is kept per session so that it is not lost when the media player switches to the stream of another client.
+func (r *Repository) saveSessionWatchHistory(session *streamSession) {
+	if r.continuityManager == nil || !r.continuityManager.GetSettings().WatchContinuityEnabled {
+		return
+	}
+
+	r.client.mu.Lock()
+	currentTime, duration := session.currentTime, session.duration
+	r.client.mu.Unlock()
+
+	if duration <= 0 || session.mediaId == 0 {
+		return
+	}
+
+	err := r.continuityManager.UpdateWatchHistoryItem(&continuity.UpdateWatchHistoryItemOptions{
+		CurrentTime:   currentTime,
+		Duration:      duration,
+		MediaId:       session.mediaId,
+		EpisodeNumber: session.episodeNumber,
+		Kind:          continuity.ExternalPlayerKind,
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Str("sessionId", session.id).Msg("torrentstream: Failed to save the watch history of the stream")
+	}
+}
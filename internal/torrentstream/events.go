@@ -26,9 +26,33 @@ const (
 	TLSStateSendingStreamToMediaPlayer TorrentLoadingStatusState = "SENDING_STREAM_TO_MEDIA_PLAYER"
 )
 
-func (r *Repository) sendTorrentLoadingStatus(event TorrentLoadingStatusState, checking string) {
-	r.wsEventManager.SendEvent(eventTorrentLoadingStatus, &TorrentLoadingStatus{
-		TorrentBeingChecked: checking,
-		State:               event,
-	})
+// loadingStatusFunc sends the loading status of a stream.
+// A nil loadingStatusFunc does not send anything, e.g. when prebuffering the next episode.
+type loadingStatusFunc func(state TorrentLoadingStatusState, checking string)
+
+func (f loadingStatusFunc) send(state TorrentLoadingStatusState, checking string) {
+	if f == nil {
+		return
+	}
+	f(state, checking)
+}
+
+// loadingStatusTo returns a loadingStatusFunc that sends the loading status to the client.
+func (r *Repository) loadingStatusTo(clientId string) loadingStatusFunc {
+	return func(state TorrentLoadingStatusState, checking string) {
+		r.sendEventToClient(clientId, eventTorrentLoadingStatus, &TorrentLoadingStatus{
+			TorrentBeingChecked: checking,
+			State:               state,
+		})
+	}
+}
+
+// sendEventToClient sends the event to the client that started the stream.
+// If the stream was started without a client ID, the event is sent to all clients.
+func (r *Repository) sendEventToClient(clientId string, t string, payload interface{}) {
+	if clientId == "" {
+		r.wsEventManager.SendEvent(t, payload)
+		return
+	}
+	r.wsEventManager.SendEventTo(clientId, t, payload)
 }
@@ -0,0 +1,53 @@
+package torrentstream
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// extractionReadahead is the readahead of the reader used to extract subtitles.
+// It is kept small so that the extraction does not compete with the media player for pieces.
+const extractionReadahead = 1024 * 1024
+
+// getExtractionId returns the ID used to serve the subtitles and fonts extracted from the stream of a session.
+func getExtractionId(sessionId string) string {
+	return "torrentstream-" + sessionId
+}
+
+// isExtractable returns true if subtitles and fonts can be extracted from the file of the session.
+func (s *streamSession) isExtractable() bool {
+	return s.file != nil && strings.EqualFold(filepath.Ext(s.file.DisplayPath()), ".mkv")
+}
+
+// startExtraction starts extracting the subtitles and fonts of the session's file from the torrent.
+func (r *Repository) startExtraction(session *streamSession) {
+	if r.streamExtractor == nil || !session.isExtractable() {
+		return
+	}
+
+	file := session.file
+	r.streamExtractor.Start(getExtractionId(session.id), func() (io.ReadSeekCloser, error) {
+		reader := file.NewReader()
+		reader.SetReadahead(extractionReadahead)
+		reader.SetResponsive()
+		return reader, nil
+	})
+}
+
+// prefetchExtraction extracts the subtitle events around the playback position of the session.
+func (r *Repository) prefetchExtraction(session *streamSession, currentTime float64) {
+	if r.streamExtractor == nil || !session.isExtractable() {
+		return
+	}
+	r.streamExtractor.Prefetch(getExtractionId(session.id), time.Duration(currentTime*float64(time.Second)))
+}
+
+// stopExtraction stops the extraction of the session and removes the extracted files.
+func (r *Repository) stopExtraction(sessionId string) {
+	if r.streamExtractor == nil {
+		return
+	}
+	r.streamExtractor.Stop(getExtractionId(sessionId))
+}
@@ -26,6 +26,8 @@ type (
 	playbackTorrent struct {
 		Torrent *torrent.Torrent
 		File    *torrent.File
+		// Torrent returned by the provider, nil if unknown (e.g. prebuffered episode)
+		AnimeTorrent *hibiketorrent.AnimeTorrent
 	}
 )
 
@@ -80,7 +82,7 @@ func (r *Repository) setPriorityDownloadStrategy(t *torrent.Torrent, file *torre
 	// }
 }
 
-func (r *Repository) findBestTorrent(media *anilist.CompleteAnime, aniDbEpisode string, episodeNumber int) (ret *playbackTorrent, err error) {
+func (r *Repository) findBestTorrent(media *anilist.CompleteAnime, aniDbEpisode string, episodeNumber int, loadingStatus loadingStatusFunc) (ret *playbackTorrent, err error) {
 	defer util.HandlePanicInModuleWithError("torrentstream/findBestTorrent", &err)
 
 	r.logger.Debug().Msgf("torrentstream: Finding best torrent for %s, Episode %d", media.GetTitleSafe(), episodeNumber)
@@ -105,7 +107,7 @@ func (r *Repository) findBestTorrent(media *anilist.CompleteAnime, aniDbEpisode
 		searchBatch = true
 	}
 
-	r.sendTorrentLoadingStatus(TLSStateSearchingTorrents, "")
+	loadingStatus.send(TLSStateSearchingTorrents, "")
 
 	var data *itorrent.SearchData
 	var currentProvider string = providerId
@@ -213,13 +215,14 @@ searchLoop:
 	// - If it does, return the magnet link
 	var selectedTorrent *torrent.Torrent
 	var selectedFile *torrent.File
+	var selectedAnimeTorrent *hibiketorrent.AnimeTorrent
 	tries := 0
 
 	for _, searchT := range data.Torrents {
 		if tries >= 2 {
 			break
 		}
-		r.sendTorrentLoadingStatus(TLSStateAddingTorrent, searchT.Name)
+		loadingStatus.send(TLSStateAddingTorrent, searchT.Name)
 		r.logger.Trace().Msgf("torrentstream: Getting torrent magnet")
 		magnet, err := providerExtension.GetProvider().GetTorrentMagnetLink(searchT)
 		if err != nil {
@@ -236,7 +239,7 @@ searchLoop:
 			continue
 		}
 
-		r.sendTorrentLoadingStatus(TLSStateCheckingTorrent, searchT.Name)
+		loadingStatus.send(TLSStateCheckingTorrent, searchT.Name)
 
 		// If the torrent has only one file, return it
 		if len(t.Files()) == 1 {
@@ -246,12 +249,13 @@ searchLoop:
 			r.logger.Debug().Msgf("torrentstream: Found single file torrent: %s", tFile.DisplayPath())
 
 			return &playbackTorrent{
-				Torrent: t,
-				File:    tFile,
+				Torrent:      t,
+				File:         tFile,
+				AnimeTorrent: searchT,
 			}, nil
 		}
 
-		r.sendTorrentLoadingStatus(TLSStateSelectingFile, searchT.Name)
+		loadingStatus.send(TLSStateSelectingFile, searchT.Name)
 
 		// DEVNOTE: The gap between adding the torrent and file analysis causes some pieces to be downloaded
 		// We currently can't Pause/Resume torrents so :shrug:
@@ -315,6 +319,7 @@ searchLoop:
 
 		selectedTorrent = t
 		selectedFile = tFile
+		selectedAnimeTorrent = searchT
 		break
 	}
 
@@ -323,8 +328,9 @@ searchLoop:
 	}
 
 	ret = &playbackTorrent{
-		Torrent: selectedTorrent,
-		File:    selectedFile,
+		Torrent:      selectedTorrent,
+		File:         selectedFile,
+		AnimeTorrent: selectedAnimeTorrent,
 	}
 
 	return ret, nil
@@ -362,8 +368,9 @@ func (r *Repository) findBestTorrentFromManualSelection(t *hibiketorrent.AnimeTo
 		r.logger.Debug().Msgf("torrentstream: Found single file torrent: %s", tFile.DisplayPath())
 
 		return &playbackTorrent{
-			Torrent: selectedTorrent,
-			File:    tFile,
+			Torrent:      selectedTorrent,
+			File:         tFile,
+			AnimeTorrent: t,
 		}, nil
 	}
 
@@ -438,8 +445,9 @@ func (r *Repository) findBestTorrentFromManualSelection(t *hibiketorrent.AnimeTo
 	r.setPriorityDownloadStrategy(selectedTorrent, tFile)
 
 	ret := &playbackTorrent{
-		Torrent: selectedTorrent,
-		File:    selectedTorrent.Files()[fileIndex],
+		Torrent:      selectedTorrent,
+		File:         selectedTorrent.Files()[fileIndex],
+		AnimeTorrent: t,
 	}
 
 	return ret, nil
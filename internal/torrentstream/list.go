@@ -13,6 +13,7 @@ type (
 	EpisodeCollection struct {
 		Episodes        []*anime.Episode `json:"episodes"`
 		HasMappingError bool             `json:"hasMappingError"`
+		SkipFillers     bool             `json:"skipFillers"` // Whether filler episodes should be skipped when playing the next episode
 	}
 )
 
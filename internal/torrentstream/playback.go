@@ -7,9 +7,6 @@ import (
 type (
 	playback struct {
 		mediaPlayerCtxCancelFunc context.CancelFunc
-		// Stores the video duration returned by the media player
-		// When this is greater than 0, the video is considered to be playing
-		currentVideoDuration int
 	}
 )
 
@@ -35,12 +32,17 @@ func (r *Repository) listenToMediaPlayerEvents() {
 			case _ = <-r.mediaPlayerRepositorySubscriber.TrackingStoppedCh:
 			case _ = <-r.mediaPlayerRepositorySubscriber.PlaybackStatusCh:
 			case _ = <-r.mediaPlayerRepositorySubscriber.StreamingTrackingStartedCh:
-				// Reset the current video duration, as the video has stopped
+				// Reset the video duration of the session played by the media player
 				// DEVNOTE: This is changed in client.go as well when the duration is updated over 0
-				r.playback.currentVideoDuration = 0
+				if session, ok := r.client.getMediaPlayerSession(); ok {
+					r.client.mu.Lock()
+					session.videoDuration = 0
+					r.client.mu.Unlock()
+				}
 			case _ = <-r.mediaPlayerRepositorySubscriber.StreamingVideoCompletedCh:
 			case _ = <-r.mediaPlayerRepositorySubscriber.StreamingTrackingStoppedCh:
-				if r.client.currentTorrent.IsPresent() {
+				// Only the session played by the media player is stopped
+				if session, ok := r.client.getMediaPlayerSession(); ok {
 					go func() {
 						defer func() {
 							if r := recover(); r != nil {
@@ -48,7 +50,7 @@ func (r *Repository) listenToMediaPlayerEvents() {
 						}()
 						r.logger.Debug().Msg("torrentstream: Media player stopped event received")
 						// Stop the stream
-						_ = r.StopStream()
+						r.stopSession(session, true)
 						// Stop the server
 						//r.serverManager.stopServer()
 						//// Signal to client.go that the media player has stopped
@@ -57,7 +59,7 @@ func (r *Repository) listenToMediaPlayerEvents() {
 				}
 			case status := <-r.mediaPlayerRepositorySubscriber.StreamingPlaybackStatusCh:
 				go func() {
-					if status != nil && r.client.currentTorrent.IsPresent() {
+					if _, ok := r.client.getMediaPlayerSession(); status != nil && ok {
 						r.client.mediaPlayerPlaybackStatusCh <- status
 					}
 				}()
@@ -0,0 +1,277 @@
+package torrentstream
+
+import (
+	"fmt"
+	"seanime/internal/api/anilist"
+	torrentanalyzer "seanime/internal/torrents/analyzer"
+	"seanime/internal/util"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent"
+	"github.com/samber/lo"
+)
+
+const (
+	// DefaultPrebufferThreshold is the playback percentage after which the next episode is prebuffered.
+	DefaultPrebufferThreshold = 70
+	// prebufferPiecesPercent is the percentage of the file's pieces downloaded at the beginning and end of the file.
+	prebufferPiecesPercent = 2
+	// prebufferedEpisodeTTL is how long the prebuffered episode of a stopped session is kept.
+	// The web player's autoplay only starts the next episode after the media player is closed, which stops the session.
+	prebufferedEpisodeTTL = 10 * time.Minute
+)
+
+type (
+	// prebufferedEpisode is the next episode of a session, resolved and partially downloaded in advance.
+	prebufferedEpisode struct {
+		mediaId       int
+		episodeNumber int
+		torrent       *torrent.Torrent
+		file          *torrent.File
+		fileIndex     int
+		createdAt     time.Time
+	}
+)
+
+func (r *Repository) getPrebufferThreshold() (float64, bool) {
+	settings, ok := r.settings.Get()
+	if !ok || !settings.PrebufferNextEpisode {
+		return 0, false
+	}
+	if settings.PrebufferThreshold <= 0 || settings.PrebufferThreshold > 100 {
+		return DefaultPrebufferThreshold, true
+	}
+	return float64(settings.PrebufferThreshold), true
+}
+
+// updatePlaybackProgress stores the playback progress of the session and
+// starts prebuffering the next episode once the threshold is reached.
+func (r *Repository) updatePlaybackProgress(session *streamSession, progress float64) {
+	threshold, enabled := r.getPrebufferThreshold()
+
+	r.client.mu.Lock()
+	session.playbackProgress = progress
+	shouldPrebuffer := enabled && !session.stopped && !session.prebufferStarted && progress >= threshold
+	if shouldPrebuffer {
+		session.prebufferStarted = true
+	}
+	r.client.mu.Unlock()
+
+	if shouldPrebuffer {
+		go r.prebufferNextEpisode(session)
+	}
+}
+
+// prebufferNextEpisode resolves the torrent of the next episode and downloads its first and last pieces at low priority.
+// If the current torrent is a batch, the next episode's file is looked up in the same torrent.
+func (r *Repository) prebufferNextEpisode(session *streamSession) {
+	defer util.HandlePanicInModuleThen("torrentstream/prebufferNextEpisode", func() {})
+
+	media := session.media
+	if media == nil {
+		return
+	}
+
+	nextEpisodeNumber := session.episodeNumber + 1
+	// Filler episodes are skipped if the user chose to
+	for r.fillerManager.ShouldSkipEpisode(session.mediaId, nextEpisodeNumber) {
+		nextEpisodeNumber++
+	}
+	if media.IsMovieOrSingleEpisode() || (media.GetCurrentEpisodeCount() > 0 && nextEpisodeNumber > media.GetCurrentEpisodeCount()) {
+		r.logger.Debug().Str("sessionId", session.id).Msg("torrentstream: No next episode to prebuffer")
+		return
+	}
+	aniDbEpisode := strconv.Itoa(nextEpisodeNumber)
+
+	r.logger.Debug().Str("sessionId", session.id).Int("episode", nextEpisodeNumber).Msg("torrentstream: Prebuffering next episode")
+
+	var pb *prebufferedEpisode
+
+	// Look for the next episode in the current torrent if it's a batch
+	if len(session.torrent.Files()) > 1 {
+		fileIndex, err := r.findEpisodeFileIndex(session.torrent, media, aniDbEpisode)
+		if err == nil {
+			pb = &prebufferedEpisode{
+				torrent:   session.torrent,
+				file:      session.torrent.Files()[fileIndex],
+				fileIndex: fileIndex,
+			}
+		} else {
+			r.logger.Debug().Err(err).Msg("torrentstream: Next episode not found in the current torrent")
+		}
+	}
+
+	if pb == nil {
+		found, err := r.findBestTorrent(media, aniDbEpisode, nextEpisodeNumber, nil)
+		if err != nil {
+			r.logger.Warn().Err(err).Str("sessionId", session.id).Msg("torrentstream: Could not find the next episode to prebuffer")
+			return
+		}
+		pb = &prebufferedEpisode{
+			torrent:   found.Torrent,
+			file:      found.File,
+			fileIndex: slices.Index(found.Torrent.Files(), found.File),
+		}
+	}
+	pb.mediaId = session.mediaId
+	pb.episodeNumber = nextEpisodeNumber
+	pb.createdAt = time.Now()
+
+	r.setPrebufferPriorities(pb.torrent, pb.file)
+
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	delete(r.client.pendingTorrents, pb.torrent.InfoHash().HexString())
+
+	// The session might have been stopped while the next episode was being resolved
+	if session.stopped {
+		r.client.keepPrebufferedEpisodeLocked(session.id, pb)
+		return
+	}
+	session.prebuffer = pb
+
+	r.logger.Info().Str("sessionId", session.id).Str("file", pb.file.DisplayPath()).Msg("torrentstream: Prebuffering next episode")
+}
+
+// takePrebufferedEpisode returns the prebuffered episode of the client's session if it matches the stream options.
+// If the session was stopped, the prebuffered episode kept after it stopped is used.
+// The prebuffered episode is removed from the session.
+func (c *Client) takePrebufferedEpisode(sessionId string, opts *StartStreamOptions) (*playbackTorrent, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var pb *prebufferedEpisode
+	if session, ok := c.sessions[sessionId]; ok && session.prebuffer != nil {
+		pb = session.prebuffer
+		if !pb.matches(opts) {
+			return nil, false
+		}
+		session.prebuffer = nil
+	} else if kept, ok := c.prebufferedEpisodes[sessionId]; ok {
+		pb = kept
+		if !pb.matches(opts) {
+			return nil, false
+		}
+		delete(c.prebufferedEpisodes, sessionId)
+	} else {
+		return nil, false
+	}
+
+	// Protect the torrent until it is bound to the new session
+	c.pendingTorrents[pb.torrent.InfoHash().HexString()] = time.Now()
+
+	return &playbackTorrent{
+		Torrent: pb.torrent,
+		File:    pb.file,
+	}, true
+}
+
+// matches returns true if the prebuffered episode is the one requested by the stream options.
+func (pb *prebufferedEpisode) matches(opts *StartStreamOptions) bool {
+	if pb.mediaId != opts.MediaId || pb.episodeNumber != opts.EpisodeNumber {
+		return false
+	}
+
+	if !opts.AutoSelect {
+		// Manual selection, the torrent and file should be the same
+		if opts.Torrent == nil || !strings.EqualFold(opts.Torrent.InfoHash, pb.torrent.InfoHash().HexString()) {
+			return false
+		}
+		if opts.FileIndex != nil && *opts.FileIndex != pb.fileIndex {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keepPrebufferedEpisodeLocked keeps the prebuffered episode of a stopped session until it is used or expires.
+// The previously kept episode of the session is dropped.
+func (c *Client) keepPrebufferedEpisodeLocked(sessionId string, pb *prebufferedEpisode) {
+	previous, hasPrevious := c.prebufferedEpisodes[sessionId]
+	pb.createdAt = time.Now()
+	c.prebufferedEpisodes[sessionId] = pb
+	if hasPrevious && previous.torrent != pb.torrent {
+		c.dropTorrentLocked(previous.torrent)
+	}
+}
+
+// dropPrebufferedEpisodeLocked drops the prebuffered episode kept for the session.
+func (c *Client) dropPrebufferedEpisodeLocked(sessionId string) {
+	pb, ok := c.prebufferedEpisodes[sessionId]
+	if !ok {
+		return
+	}
+	delete(c.prebufferedEpisodes, sessionId)
+	c.dropTorrentLocked(pb.torrent)
+}
+
+// dropExpiredPrebufferedEpisodes drops the prebuffered episodes of stopped sessions that were not used in time.
+func (c *Client) dropExpiredPrebufferedEpisodes() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for sessionId, pb := range c.prebufferedEpisodes {
+		if time.Since(pb.createdAt) > prebufferedEpisodeTTL {
+			c.repository.logger.Debug().Str("sessionId", sessionId).Msg("torrentstream: Prebuffered episode expired")
+			c.dropPrebufferedEpisodeLocked(sessionId)
+		}
+	}
+}
+
+// setPrebufferPriorities downloads the first and last pieces of the file at normal priority, the rest of the file is not downloaded.
+// The first pieces allow the stream to start instantly, the last pieces contain the index of some containers (e.g. MKV cues).
+func (r *Repository) setPrebufferPriorities(t *torrent.Torrent, file *torrent.File) {
+	file.SetPriority(torrent.PiecePriorityNone)
+
+	firstPieceIdx := file.Offset() * int64(t.NumPieces()) / t.Length()
+	endPieceIdx := (file.Offset() + file.Length()) * int64(t.NumPieces()) / t.Length()
+	numPieces := max((endPieceIdx-firstPieceIdx+1)*prebufferPiecesPercent/100, 1)
+
+	for idx := firstPieceIdx; idx <= firstPieceIdx+numPieces; idx++ {
+		if idx >= 0 && int(idx) < t.NumPieces() {
+			t.Piece(int(idx)).SetPriority(torrent.PiecePriorityNormal)
+		}
+	}
+	for idx := endPieceIdx - numPieces; idx <= endPieceIdx; idx++ {
+		if idx >= 0 && int(idx) < t.NumPieces() {
+			t.Piece(int(idx)).SetPriority(torrent.PiecePriorityNormal)
+		}
+	}
+}
+
+// findEpisodeFileIndex returns the index of the file corresponding to the episode in a batch torrent.
+func (r *Repository) findEpisodeFileIndex(t *torrent.Torrent, media *anilist.CompleteAnime, aniDbEpisode string) (int, error) {
+	filepaths := lo.Map(t.Files(), func(f *torrent.File, _ int) string {
+		return f.DisplayPath()
+	})
+
+	if len(filepaths) == 0 {
+		return 0, fmt.Errorf("no files found in the torrent")
+	}
+
+	analyzer := torrentanalyzer.NewAnalyzer(&torrentanalyzer.NewAnalyzerOptions{
+		Logger:           r.logger,
+		Filepaths:        filepaths,
+		Media:            media,
+		Platform:         r.platform,
+		MetadataProvider: r.metadataProvider,
+		ForceMatch:       true,
+	})
+
+	analysis, err := analyzer.AnalyzeTorrentFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	analysisFile, found := analysis.GetFileByAniDBEpisode(aniDbEpisode)
+	if !found {
+		return 0, ErrNoEpisodeFound
+	}
+
+	return analysisFile.GetIndex(), nil
+}
@@ -9,12 +9,15 @@ import (
 	"path/filepath"
 	"seanime/internal/api/anilist"
 	"seanime/internal/api/metadata"
+	"seanime/internal/continuity"
 	"seanime/internal/database/db"
 	"seanime/internal/database/models"
 	"seanime/internal/events"
 	hibiketorrent "seanime/internal/extension/hibike/torrent"
+	"seanime/internal/library/fillermanager"
 	"seanime/internal/library/playbackmanager"
 	"seanime/internal/mediaplayers/mediaplayer"
+	"seanime/internal/mediastream/streamextract"
 	"seanime/internal/platforms/platform"
 	"seanime/internal/torrents/torrent"
 	"seanime/internal/util"
@@ -41,6 +44,9 @@ type (
 		playbackManager                 *playbackmanager.PlaybackManager
 		mediaPlayerRepository           *mediaplayer.Repository
 		mediaPlayerRepositorySubscriber *mediaplayer.RepositorySubscriber
+		streamExtractor                 *streamextract.Manager
+		fillerManager                   *fillermanager.FillerManager
+		continuityManager               *continuity.Manager
 		logger                          *zerolog.Logger
 		db                              *db.Database
 	}
@@ -62,6 +68,9 @@ type (
 		PlaybackManager    *playbackmanager.PlaybackManager
 		WSEventManager     events.WSEventManagerInterface
 		Database           *db.Database
+		StreamExtractor    *streamextract.Manager
+		FillerManager      *fillermanager.FillerManager // Optional, used to skip filler episodes when prebuffering
+		ContinuityManager  *continuity.Manager          // Optional, used to save the position of each stream when it stops
 	}
 )
 
@@ -82,6 +91,9 @@ func NewRepository(opts *NewRepositoryOptions) *Repository {
 		playbackManager:                 opts.PlaybackManager,
 		mediaPlayerRepository:           nil,
 		mediaPlayerRepositorySubscriber: nil,
+		streamExtractor:                 opts.StreamExtractor,
+		fillerManager:                   opts.FillerManager,
+		continuityManager:               opts.ContinuityManager,
 		logger:                          opts.Logger,
 		db:                              opts.Database,
 	}
@@ -179,6 +191,13 @@ func (r *Repository) InitModules(settings *models.TorrentstreamSettings, host st
 	}
 
 	r.logger.Info().Msg("torrentstream: Module initialized")
+
+	// Let the client know that streams can be resumed
+	if resumable := r.GetResumableSessions(); len(resumable) > 0 {
+		r.logger.Info().Int("count", len(resumable)).Msg("torrentstream: Found streams that can be resumed")
+		r.wsEventManager.SendEvent(events.InvalidateQueries, []string{events.GetTorrentstreamResumableSessionsEndpoint})
+	}
+
 	return nil
 }
 
@@ -0,0 +1,393 @@
+package torrentstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"seanime/internal/database/db_bridge"
+	hibiketorrent "seanime/internal/extension/hibike/torrent"
+	"seanime/internal/util"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent"
+	"github.com/goccy/go-json"
+)
+
+const (
+	// sessionPersistenceInterval is how often the active sessions are saved to the database.
+	sessionPersistenceInterval = 30 * time.Second
+	// metainfoFilename is the name of the torrent file saved in the torrent's download directory.
+	// It allows the torrent to be added again without fetching its metadata from peers.
+	metainfoFilename = ".metainfo.torrent"
+)
+
+var ErrNoPersistedSession = errors.New("torrentstream: no stream to resume")
+
+type (
+	// PersistedSession is a stream session saved to the database so that it can be resumed after a restart.
+	PersistedSession struct {
+		SessionId     string       `json:"sessionId"`
+		ClientId      string       `json:"clientId"`
+		MediaId       int          `json:"mediaId"`
+		EpisodeNumber int          `json:"episodeNumber"`
+		AniDBEpisode  string       `json:"aniDBEpisode"`
+		PlaybackType  PlaybackType `json:"playbackType"`
+		InfoHash      string       `json:"infoHash"`
+		FileIndex     int          `json:"fileIndex"`
+		Filename      string       `json:"filename"`
+		// Torrent selected from the provider, nil if unknown
+		Torrent *hibiketorrent.AnimeTorrent `json:"torrent,omitempty"`
+		// Last position requested by the player, in bytes
+		ReadOffset int64 `json:"readOffset"`
+		// Percentage of the file downloaded
+		ProgressPercentage float64   `json:"progressPercentage"`
+		PlaybackProgress   float64   `json:"playbackProgress"`
+		UpdatedAt          time.Time `json:"updatedAt"`
+	}
+
+	ResumeStreamOptions struct {
+		SessionId    string // ID of the persisted session
+		ClientId     string
+		PlaybackType PlaybackType
+		UserAgent    string
+	}
+)
+
+// ResumeStream starts the stream of a persisted session for the client.
+// The torrent is added from the metainfo saved in its download directory, so only the missing pieces are fetched.
+func (r *Repository) ResumeStream(opts *ResumeStreamOptions) (err error) {
+	defer util.HandlePanicInModuleWithError("torrentstream/ResumeStream", &err)
+
+	ps, err := r.getPersistedSession(opts.SessionId)
+	if err != nil {
+		return err
+	}
+
+	sessionId := getSessionId(opts.ClientId)
+
+	r.logger.Info().
+		Str("clientId", opts.ClientId).
+		Str("infoHash", ps.InfoHash).
+		Int("mediaId", ps.MediaId).Msgf("torrentstream: Resuming stream for episode %s", ps.AniDBEpisode)
+
+	if err := r.client.canAddSession(sessionId); err != nil {
+		return err
+	}
+
+	r.sendEventToClient(opts.ClientId, eventTorrentLoading, nil)
+	loadingStatus := r.loadingStatusTo(opts.ClientId)
+
+	media, _, err := r.getMediaInfo(ps.MediaId)
+	if err != nil {
+		r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
+		return err
+	}
+
+	loadingStatus.send(TLSStateAddingTorrent, ps.Filename)
+
+	torrentToStream, err := r.addPersistedTorrent(ps)
+	if err != nil {
+		r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
+		return err
+	}
+
+	if ps.Torrent != nil {
+		r.selectionHistoryMap.Set(ps.MediaId, ps.Torrent)
+	}
+
+	fileIndex := ps.FileIndex
+	err = r.startSession(&StartStreamOptions{
+		MediaId:       ps.MediaId,
+		EpisodeNumber: ps.EpisodeNumber,
+		AniDBEpisode:  ps.AniDBEpisode,
+		Torrent:       ps.Torrent,
+		FileIndex:     &fileIndex,
+		UserAgent:     opts.UserAgent,
+		ClientId:      opts.ClientId,
+		PlaybackType:  opts.PlaybackType,
+	}, media, ps.AniDBEpisode, torrentToStream, loadingStatus, ps.ReadOffset)
+	if err != nil {
+		return err
+	}
+
+	// The session was resumed by another client
+	if ps.SessionId != sessionId {
+		r.deletePersistedSession(ps.SessionId)
+	}
+
+	return nil
+}
+
+// addPersistedTorrent adds the torrent of the persisted session and prioritizes the pieces from the last read position.
+func (r *Repository) addPersistedTorrent(ps *PersistedSession) (*playbackTorrent, error) {
+	var t *torrent.Torrent
+	var err error
+
+	if metainfoPath, ok := r.getMetainfoPath(ps.InfoHash); ok {
+		t, err = r.client.AddTorrent(metainfoPath)
+	} else {
+		r.logger.Debug().Str("infoHash", ps.InfoHash).Msg("torrentstream: No saved metainfo, adding torrent from magnet")
+		t, err = r.client.AddTorrent(fmt.Sprintf("magnet:?xt=urn:btih:%s", ps.InfoHash))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ps.FileIndex < 0 || ps.FileIndex >= len(t.Files()) {
+		r.client.dropTorrent(t)
+		return nil, fmt.Errorf("torrentstream: file index %d out of range", ps.FileIndex)
+	}
+
+	for i, f := range t.Files() {
+		if i != ps.FileIndex {
+			f.SetPriority(torrent.PiecePriorityNone)
+		}
+	}
+	file := t.Files()[ps.FileIndex]
+	file.Download()
+	r.setPriorityDownloadStrategy(t, file)
+	if ps.ReadOffset > 0 {
+		r.serverManager.prioritizeRangeRequestPieces(fmt.Sprintf("bytes=%d-", ps.ReadOffset), file, t)
+	}
+
+	return &playbackTorrent{
+		Torrent:      t,
+		File:         file,
+		AnimeTorrent: ps.Torrent,
+	}, nil
+}
+
+// GetResumableSessions returns the persisted sessions that are not active, most recent first.
+func (r *Repository) GetResumableSessions() []*PersistedSession {
+	sessions, err := r.getPersistedSessions()
+	if err != nil {
+		r.logger.Error().Err(err).Msg("torrentstream: Failed to get persisted sessions")
+		return make([]*PersistedSession, 0)
+	}
+
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	ret := make([]*PersistedSession, 0, len(sessions))
+	for _, ps := range sessions {
+		if s, ok := r.client.sessions[ps.SessionId]; ok && s.torrent.InfoHash().HexString() == ps.InfoHash {
+			continue
+		}
+		ret = append(ret, ps)
+	}
+	return ret
+}
+
+// DismissResumableSession deletes the persisted session and the files of its torrent.
+func (r *Repository) DismissResumableSession(sessionId string) error {
+	ps, err := r.getPersistedSession(sessionId)
+	if err != nil {
+		return err
+	}
+
+	r.deletePersistedSession(ps.SessionId)
+
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	// Keep the files if the torrent is still in use
+	if r.client.torrentClient.IsPresent() {
+		for _, t := range r.client.torrentClient.MustGet().Torrents() {
+			if t.InfoHash().HexString() == ps.InfoHash {
+				return nil
+			}
+		}
+	}
+
+	if settings, ok := r.settings.Get(); ok && settings.DownloadDir != "" && ps.InfoHash != "" {
+		_ = os.RemoveAll(filepath.Join(settings.DownloadDir, ps.InfoHash))
+	}
+
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// toPersistedSession returns the state of the session to be saved.
+// The client's mutex should be locked.
+func (s *streamSession) toPersistedSession() *PersistedSession {
+	ret := &PersistedSession{
+		SessionId:        s.id,
+		ClientId:         s.clientId,
+		MediaId:          s.mediaId,
+		EpisodeNumber:    s.episodeNumber,
+		AniDBEpisode:     s.aniDbEpisode,
+		PlaybackType:     s.playbackType,
+		InfoHash:         s.torrent.InfoHash().HexString(),
+		FileIndex:        s.fileIndex,
+		Torrent:          s.selectedTorrent,
+		ReadOffset:       s.readOffset,
+		PlaybackProgress: s.playbackProgress,
+		UpdatedAt:        time.Now(),
+	}
+	if s.file != nil {
+		ret.Filename = filepath.Base(s.file.DisplayPath())
+		if s.file.Length() > 0 {
+			ret.ProgressPercentage = float64(s.file.BytesCompleted()) / float64(s.file.Length()) * 100
+		}
+	}
+	return ret
+}
+
+// getSelectedTorrent returns the torrent selected for the media if it corresponds to the streamed torrent.
+func (r *Repository) getSelectedTorrent(mediaId int, t *torrent.Torrent) *hibiketorrent.AnimeTorrent {
+	selected, ok := r.selectionHistoryMap.Get(mediaId)
+	if !ok || selected == nil || !strings.EqualFold(selected.InfoHash, t.InfoHash().HexString()) {
+		return nil
+	}
+	return selected
+}
+
+// setReadOffset stores the start of the range requested by the player.
+func (c *Client) setReadOffset(session *streamSession, rangeHeader string) {
+	var start int64
+	if _, err := fmt.Sscanf(rangeHeader, "bytes=%d-", &start); err != nil || start < 0 {
+		return
+	}
+	c.mu.Lock()
+	session.readOffset = start
+	c.mu.Unlock()
+}
+
+// runSessionPersistence saves the active sessions periodically until the context is cancelled.
+func (r *Repository) runSessionPersistence(ctx context.Context) {
+	ticker := time.NewTicker(sessionPersistenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, session := range r.client.getSessions() {
+				r.persistSession(session)
+			}
+		}
+	}
+}
+
+// persistSession saves the session to the database along with the metainfo of its torrent.
+func (r *Repository) persistSession(session *streamSession) {
+	defer util.HandlePanicInModuleThen("torrentstream/persistSession", func() {})
+
+	r.client.mu.Lock()
+	if session.stopped || session.torrent == nil {
+		r.client.mu.Unlock()
+		return
+	}
+	ps := session.toPersistedSession()
+	t := session.torrent
+	r.client.mu.Unlock()
+
+	bytes, err := json.Marshal(ps)
+	if err != nil {
+		return
+	}
+	if err := db_bridge.UpsertTorrentstreamSession(r.db, ps.SessionId, bytes); err != nil {
+		r.logger.Error().Err(err).Str("sessionId", ps.SessionId).Msg("torrentstream: Failed to persist session")
+		return
+	}
+
+	r.saveMetainfo(t)
+}
+
+func (r *Repository) deletePersistedSession(sessionId string) {
+	defer util.HandlePanicInModuleThen("torrentstream/deletePersistedSession", func() {})
+
+	if err := db_bridge.DeleteTorrentstreamSession(r.db, sessionId); err != nil {
+		r.logger.Error().Err(err).Str("sessionId", sessionId).Msg("torrentstream: Failed to delete persisted session")
+	}
+}
+
+func (r *Repository) getPersistedSession(sessionId string) (*PersistedSession, error) {
+	session, err := db_bridge.GetTorrentstreamSession(r.db, sessionId)
+	if err != nil {
+		return nil, ErrNoPersistedSession
+	}
+	var ps PersistedSession
+	if err := json.Unmarshal(session.Value, &ps); err != nil {
+		return nil, err
+	}
+	return &ps, nil
+}
+
+func (r *Repository) getPersistedSessions() ([]*PersistedSession, error) {
+	if r.db == nil {
+		return nil, errors.New("no database")
+	}
+	sessions, err := db_bridge.GetTorrentstreamSessions(r.db)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*PersistedSession, 0, len(sessions))
+	for _, session := range sessions {
+		var ps PersistedSession
+		if err := json.Unmarshal(session.Value, &ps); err != nil {
+			continue
+		}
+		ret = append(ret, &ps)
+	}
+	return ret, nil
+}
+
+// getPersistedInfoHashes returns the info hashes of the persisted sessions.
+// Their download directories are kept when the torrents are dropped on startup.
+func (r *Repository) getPersistedInfoHashes() map[string]bool {
+	ret := make(map[string]bool)
+	sessions, err := r.getPersistedSessions()
+	if err != nil {
+		return ret
+	}
+	for _, ps := range sessions {
+		ret[ps.InfoHash] = true
+	}
+	return ret
+}
+
+// getMetainfoPath returns the path of the saved metainfo of the torrent, if it exists.
+func (r *Repository) getMetainfoPath(infoHash string) (string, bool) {
+	settings, ok := r.settings.Get()
+	if !ok || settings.DownloadDir == "" || infoHash == "" {
+		return "", false
+	}
+	p := filepath.Join(settings.DownloadDir, infoHash, metainfoFilename)
+	if _, err := os.Stat(p); err != nil {
+		return "", false
+	}
+	return p, true
+}
+
+// saveMetainfo writes the metainfo of the torrent in its download directory if it does not exist yet.
+func (r *Repository) saveMetainfo(t *torrent.Torrent) {
+	settings, ok := r.settings.Get()
+	if !ok || settings.DownloadDir == "" || t.Info() == nil {
+		return
+	}
+	infoHash := t.InfoHash().HexString()
+	if _, ok := r.getMetainfoPath(infoHash); ok {
+		return
+	}
+
+	dir := filepath.Join(settings.DownloadDir, infoHash)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return
+	}
+	f, err := os.Create(filepath.Join(dir, metainfoFilename))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	mi := t.Metainfo()
+	if err := mi.Write(f); err != nil {
+		r.logger.Warn().Err(err).Str("infoHash", infoHash).Msg("torrentstream: Failed to save metainfo")
+	}
+}
@@ -3,6 +3,8 @@ package torrentstream
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/anacrolix/torrent"
@@ -67,17 +69,40 @@ func (s *serverManager) stopServer() {
 	// no-op
 }
 
+// getSessionIdFromPath returns the session ID from the request path.
+// e.g. "/api/v1/torrentstream/stream/{sessionId}/{filename}" or "/stream/{sessionId}/{filename}"
+func getSessionIdFromPath(p string) (string, bool) {
+	_, after, found := strings.Cut(p, "/stream/")
+	if !found {
+		return "", false
+	}
+	sessionId, _, _ := strings.Cut(after, "/")
+	sessionId, err := url.PathUnescape(sessionId)
+	if err != nil || sessionId == "" {
+		return "", false
+	}
+	return sessionId, true
+}
+
+// ServeHTTP streams the file of the session identified by the request path.
 func (s *serverManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.lastUsed = time.Now()
 	s.repository.logger.Trace().Str("range", r.Header.Get("Range")).Msg("torrentstream: Stream endpoint hit")
 
-	if s.repository.client.currentFile.IsAbsent() || s.repository.client.currentTorrent.IsAbsent() {
-		s.repository.logger.Error().Msg("torrentstream: No torrent to stream")
+	sessionId, ok := getSessionIdFromPath(r.URL.Path)
+	if !ok {
+		http.Error(w, "Invalid stream URL", http.StatusBadRequest)
+		return
+	}
+
+	session, ok := s.repository.client.getSession(sessionId)
+	if !ok || session.file == nil || session.torrent == nil {
+		s.repository.logger.Error().Str("sessionId", sessionId).Msg("torrentstream: No torrent to stream")
 		http.Error(w, "No torrent to stream", http.StatusNotFound)
 		return
 	}
 
-	file := s.repository.client.currentFile.MustGet()
+	file := session.file
 	s.repository.logger.Trace().Str("file", file.DisplayPath()).Msg("torrentstream: New reader")
 	tr := file.NewReader()
 	defer func(tr torrent.Reader) {
@@ -92,10 +117,12 @@ func (s *serverManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// If this is a range request for a later part of the file, prioritize those pieces
 	rangeHeader := r.Header.Get("Range")
-	if rangeHeader != "" && s.repository.client.currentTorrent.IsPresent() {
-		t := s.repository.client.currentTorrent.MustGet()
+	if rangeHeader != "" {
+		s.repository.client.setReadOffset(session, rangeHeader)
+	}
+	if rangeHeader != "" && session.priorityStrategy == PriorityStrategyStreaming {
 		// Attempt to prioritize the pieces requested in the range
-		s.prioritizeRangeRequestPieces(rangeHeader, file, t)
+		s.prioritizeRangeRequestPieces(rangeHeader, file, session.torrent)
 	}
 
 	s.repository.logger.Trace().Str("file", file.DisplayPath()).Msg("torrentstream: Serving file content")
@@ -0,0 +1,306 @@
+package torrentstream
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"seanime/internal/api/anilist"
+	hibiketorrent "seanime/internal/extension/hibike/torrent"
+	"slices"
+	"time"
+
+	"github.com/anacrolix/torrent"
+)
+
+const (
+	// DefaultMaxConcurrentStreams is used when the max number of concurrent streams is not set.
+	DefaultMaxConcurrentStreams = 2
+	// defaultSessionId is used for streams started without a client ID.
+	defaultSessionId = "default"
+	// unusedTorrentGracePeriod is how long a torrent that is not bound to a session is kept before it can be dropped.
+	// This prevents dropping torrents that are being checked by the finder of another client.
+	unusedTorrentGracePeriod = 5 * time.Minute
+)
+
+const (
+	// PriorityStrategyStreaming prioritizes the pieces requested by the player.
+	PriorityStrategyStreaming PriorityStrategy = "streaming"
+	// PriorityStrategyBackground downloads the pieces without reacting to the player's requests.
+	PriorityStrategyBackground PriorityStrategy = "background"
+)
+
+var ErrMaxConcurrentStreams = errors.New("torrentstream: maximum number of concurrent streams reached")
+
+type (
+	PriorityStrategy string
+
+	// streamSession is a torrent stream started by a client.
+	// Each client (identified by its websocket client ID) can have one session at a time.
+	streamSession struct {
+		id               string
+		clientId         string
+		mediaId          int
+		episodeNumber    int
+		aniDbEpisode     string
+		playbackType     PlaybackType
+		priorityStrategy PriorityStrategy
+		torrent          *torrent.Torrent
+		file             *torrent.File
+		fileIndex        int
+		status           TorrentStatus
+		startedAt        time.Time
+		stopped          bool
+		media            *anilist.CompleteAnime
+
+		// Playback progress reported by the media player, from 0 to 100
+		playbackProgress float64
+		// Last position reported by the media player, in seconds
+		// It is saved to the watch history of the session's episode when the session is released
+		currentTime float64
+		duration    float64
+		// Next episode, resolved in advance when prebuffering is enabled
+		prebuffer        *prebufferedEpisode
+		prebufferStarted bool
+
+		// Torrent selected from the provider, used to resume the stream after a restart
+		selectedTorrent *hibiketorrent.AnimeTorrent
+		// Last position requested by the player, in bytes
+		readOffset int64
+
+		// Stores the video duration returned by the media player
+		// When this is greater than 0, the video is considered to be playing
+		videoDuration int
+
+		lastSpeedCheck       time.Time // Track the last time we checked speeds
+		lastBytesCompleted   int64     // Track the last bytes completed
+		lastBytesWrittenData int64     // Track the last bytes written data
+	}
+
+	// SessionInfo is the public representation of a stream session.
+	SessionInfo struct {
+		Id               string           `json:"id"`
+		ClientId         string           `json:"clientId"`
+		MediaId          int              `json:"mediaId"`
+		EpisodeNumber    int              `json:"episodeNumber"`
+		AniDBEpisode     string           `json:"aniDBEpisode"`
+		PlaybackType     PlaybackType     `json:"playbackType"`
+		PriorityStrategy PriorityStrategy `json:"priorityStrategy"`
+		InfoHash         string           `json:"infoHash"`
+		TorrentName      string           `json:"torrentName"`
+		Filename         string           `json:"filename"`
+		Status           TorrentStatus    `json:"status"`
+		StartedAt        time.Time        `json:"startedAt"`
+		PlaybackProgress float64          `json:"playbackProgress"`
+		// Episode number of the prebuffered episode, 0 if none
+		PrebufferedEpisode int `json:"prebufferedEpisode"`
+		// ID used to fetch the subtitles and fonts extracted from the stream, empty if the file is not a Matroska file
+		ExtractionId string `json:"extractionId"`
+	}
+)
+
+// getSessionId returns the ID of the session of the client.
+func getSessionId(clientId string) string {
+	if clientId == "" {
+		return defaultSessionId
+	}
+	return clientId
+}
+
+func (s *streamSession) toSessionInfo() *SessionInfo {
+	ret := &SessionInfo{
+		Id:               s.id,
+		ClientId:         s.clientId,
+		MediaId:          s.mediaId,
+		EpisodeNumber:    s.episodeNumber,
+		AniDBEpisode:     s.aniDbEpisode,
+		PlaybackType:     s.playbackType,
+		PriorityStrategy: s.priorityStrategy,
+		Status:           s.status,
+		StartedAt:        s.startedAt,
+		PlaybackProgress: s.playbackProgress,
+	}
+	if s.prebuffer != nil {
+		ret.PrebufferedEpisode = s.prebuffer.episodeNumber
+	}
+	if s.isExtractable() {
+		ret.ExtractionId = getExtractionId(s.id)
+	}
+	if s.torrent != nil {
+		ret.InfoHash = s.torrent.InfoHash().HexString()
+		ret.TorrentName = s.torrent.Name()
+	}
+	if s.file != nil {
+		ret.Filename = filepath.Base(s.file.DisplayPath())
+	}
+	return ret
+}
+
+// GetSessions returns the active stream sessions.
+func (r *Repository) GetSessions() []*SessionInfo {
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+
+	sessions := r.client.getSessionsLocked()
+	ret := make([]*SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		ret = append(ret, s.toSessionInfo())
+	}
+	return ret
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (c *Client) getMaxConcurrentStreams() int {
+	settings, ok := c.repository.settings.Get()
+	if !ok || settings.MaxConcurrentStreams <= 0 {
+		return DefaultMaxConcurrentStreams
+	}
+	return settings.MaxConcurrentStreams
+}
+
+// getSession returns the session with the given ID.
+func (c *Client) getSession(id string) (*streamSession, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.sessions[id]
+	return s, ok
+}
+
+// getSessions returns all sessions, sorted by start time.
+func (c *Client) getSessions() []*streamSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.getSessionsLocked()
+}
+
+func (c *Client) getSessionsLocked() []*streamSession {
+	ret := make([]*streamSession, 0, len(c.sessions))
+	for _, s := range c.sessions {
+		ret = append(ret, s)
+	}
+	slices.SortFunc(ret, func(a, b *streamSession) int {
+		return a.startedAt.Compare(b.startedAt)
+	})
+	return ret
+}
+
+// canAddSession returns an error if a new session cannot be added for the given session ID.
+// A client that already has a session can always replace it.
+func (c *Client) canAddSession(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.sessions[id]; ok {
+		return nil
+	}
+	if len(c.sessions) >= c.getMaxConcurrentStreams() {
+		return ErrMaxConcurrentStreams
+	}
+	return nil
+}
+
+// addSession stores the session, replacing any previous session with the same ID.
+func (c *Client) addSession(s *streamSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[s.id] = s
+	delete(c.pendingTorrents, s.torrent.InfoHash().HexString())
+	c.removeCacheEntryLocked(s.torrent)
+}
+
+// removeSession removes the session if it has not been replaced by another session.
+// It returns false if the session was already removed.
+func (c *Client) removeSession(s *streamSession) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s.stopped {
+		return false
+	}
+	s.stopped = true
+	if current, ok := c.sessions[s.id]; ok && current == s {
+		delete(c.sessions, s.id)
+	}
+	return true
+}
+
+// getMediaPlayerSession returns the session that is being played by the media player.
+func (c *Client) getMediaPlayerSession() (*streamSession, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.sessions {
+		if s.playbackType == PlaybackTypeDefault {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// isTorrentUsedLocked returns true if a session is streaming or prebuffering the torrent.
+func (c *Client) isTorrentUsedLocked(infoHash string) bool {
+	for _, s := range c.sessions {
+		if s.torrent != nil && s.torrent.InfoHash().HexString() == infoHash {
+			return true
+		}
+		if s.prebuffer != nil && s.prebuffer.torrent.InfoHash().HexString() == infoHash {
+			return true
+		}
+	}
+	for _, pb := range c.prebufferedEpisodes {
+		if pb.torrent.InfoHash().HexString() == infoHash {
+			return true
+		}
+	}
+	return false
+}
+
+// dropTorrent drops the torrent and deletes its files if no session is streaming it.
+func (c *Client) dropTorrent(t *torrent.Torrent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropTorrentLocked(t)
+}
+
+func (c *Client) dropTorrentLocked(t *torrent.Torrent) {
+	if t == nil {
+		return
+	}
+	infoHash := t.InfoHash().HexString()
+	if c.isTorrentUsedLocked(infoHash) {
+		return
+	}
+
+	t.Drop()
+	delete(c.pendingTorrents, infoHash)
+	delete(c.cache, infoHash)
+
+	if settings, ok := c.repository.settings.Get(); ok && settings.DownloadDir != "" {
+		// Files are stored in {downloadDir}/{infohash}
+		_ = os.RemoveAll(filepath.Join(settings.DownloadDir, infoHash))
+	}
+
+	c.repository.logger.Debug().Str("infoHash", infoHash).Msg("torrentstream: Dropped torrent")
+}
+
+// dropUnusedTorrents drops the torrents that are not streamed by any session,
+// except the cached ones and the ones added recently since they might be checked by the finder.
+func (c *Client) dropUnusedTorrents() {
+	if c.torrentClient.IsAbsent() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, t := range c.torrentClient.MustGet().Torrents() {
+		infoHash := t.InfoHash().HexString()
+		if c.isTorrentUsedLocked(infoHash) {
+			continue
+		}
+		if _, ok := c.cache[infoHash]; ok {
+			continue
+		}
+		if addedAt, ok := c.pendingTorrents[infoHash]; ok && time.Since(addedAt) < unusedTorrentGracePeriod {
+			continue
+		}
+		c.dropTorrentLocked(t)
+	}
+}
@@ -0,0 +1,92 @@
+package torrentstream
+
+import (
+	"seanime/internal/database/models"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSessionIdFromPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		ok       bool
+	}{
+		{path: "/api/v1/torrentstream/stream/abc-123/[Group] Title - 01.mkv", expected: "abc-123", ok: true},
+		{path: "/stream/default/Title%20-%2001.mkv", expected: "default", ok: true},
+		{path: "/stream/client%2F1/file.mkv", expected: "client/1", ok: true},
+		{path: "/api/v1/torrentstream/stream/", ok: false},
+		{path: "/api/v1/torrentstream/other", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			sessionId, ok := getSessionIdFromPath(tt.path)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, sessionId)
+		})
+	}
+}
+
+func TestCanAddSession(t *testing.T) {
+	repo := &Repository{
+		settings: mo.Some(Settings{
+			TorrentstreamSettings: models.TorrentstreamSettings{MaxConcurrentStreams: 2},
+		}),
+	}
+	client := NewClient(repo)
+
+	client.sessions["client-1"] = &streamSession{id: "client-1"}
+	require.NoError(t, client.canAddSession("client-2"))
+
+	client.sessions["client-2"] = &streamSession{id: "client-2"}
+	assert.ErrorIs(t, client.canAddSession("client-3"), ErrMaxConcurrentStreams)
+
+	// A client can replace its own session
+	assert.NoError(t, client.canAddSession("client-1"))
+
+	// Default limit
+	repo.settings = mo.Some(Settings{})
+	assert.ErrorIs(t, client.canAddSession("client-3"), ErrMaxConcurrentStreams)
+}
+
+func TestGetPrebufferThreshold(t *testing.T) {
+	repo := &Repository{
+		settings: mo.Some(Settings{}),
+	}
+
+	_, enabled := repo.getPrebufferThreshold()
+	assert.False(t, enabled)
+
+	repo.settings = mo.Some(Settings{
+		TorrentstreamSettings: models.TorrentstreamSettings{PrebufferNextEpisode: true, PrebufferThreshold: 85},
+	})
+	threshold, enabled := repo.getPrebufferThreshold()
+	assert.True(t, enabled)
+	assert.Equal(t, 85.0, threshold)
+
+	// Invalid threshold
+	repo.settings = mo.Some(Settings{
+		TorrentstreamSettings: models.TorrentstreamSettings{PrebufferNextEpisode: true, PrebufferThreshold: 150},
+	})
+	threshold, _ = repo.getPrebufferThreshold()
+	assert.Equal(t, float64(DefaultPrebufferThreshold), threshold)
+}
+
+func TestSetReadOffset(t *testing.T) {
+	client := NewClient(&Repository{})
+	session := &streamSession{id: "client-1"}
+
+	client.setReadOffset(session, "bytes=1048576-")
+	assert.Equal(t, int64(1048576), session.readOffset)
+
+	client.setReadOffset(session, "bytes=2048-4096")
+	assert.Equal(t, int64(2048), session.readOffset)
+
+	// Invalid range
+	client.setReadOffset(session, "invalid")
+	assert.Equal(t, int64(2048), session.readOffset)
+}
@@ -8,12 +8,11 @@ import (
 	hibiketorrent "seanime/internal/extension/hibike/torrent"
 	"seanime/internal/hook"
 	"seanime/internal/library/playbackmanager"
+	"seanime/internal/library/viewingstats"
 	"seanime/internal/util"
+	"slices"
 	"strconv"
 	"time"
-
-	"github.com/anacrolix/torrent"
-	"github.com/samber/mo"
 )
 
 type PlaybackType string
@@ -35,18 +34,27 @@ type StartStreamOptions struct {
 	PlaybackType  PlaybackType
 }
 
-// StartStream is called by the client to start streaming a torrent
+// StartStream is called by the client to start streaming a torrent.
+// Each client has its own session, starting a new stream replaces the client's previous session.
 func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 	defer util.HandlePanicInModuleWithError("torrentstream/stream/StartStream", &err)
 	// DEVNOTE: Do not
 	//r.Shutdown()
 
+	sessionId := getSessionId(opts.ClientId)
+
 	r.logger.Info().
 		Str("clientId", opts.ClientId).
 		Any("playbackType", opts.PlaybackType).
 		Int("mediaId", opts.MediaId).Msgf("torrentstream: Starting stream for episode %s", opts.AniDBEpisode)
 
-	r.wsEventManager.SendEvent(eventTorrentLoading, nil)
+	// Fail early if the maximum number of concurrent streams is reached
+	if err := r.client.canAddSession(sessionId); err != nil {
+		return err
+	}
+
+	r.sendEventToClient(opts.ClientId, eventTorrentLoading, nil)
+	loadingStatus := r.loadingStatusTo(opts.ClientId)
 
 	//
 	// Get the media info
@@ -63,36 +71,116 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 	// Find the best torrent / Select the torrent
 	//
 	var torrentToStream *playbackTorrent
-	switch opts.AutoSelect {
-	case true:
-		torrentToStream, err = r.findBestTorrent(media, aniDbEpisode, episodeNumber)
+
+	// Use the prebuffered episode if the client is playing the next episode
+	if pb, ok := r.client.takePrebufferedEpisode(sessionId, opts); ok {
+		r.logger.Debug().Str("sessionId", sessionId).Msg("torrentstream: Using prebuffered episode")
+		pb.File.Download()
+		r.setPriorityDownloadStrategy(pb.Torrent, pb.File)
+		torrentToStream = pb
+	}
+
+	switch {
+	case torrentToStream != nil:
+		// Prebuffered
+	case opts.AutoSelect:
+		torrentToStream, err = r.findBestTorrent(media, aniDbEpisode, episodeNumber, loadingStatus)
 		if err != nil {
-			r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
+			r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
 			return err
 		}
-	case false:
+	default:
 		if opts.Torrent == nil {
 			return fmt.Errorf("torrentstream: No torrent provided")
 		}
 		torrentToStream, err = r.findBestTorrentFromManualSelection(opts.Torrent, media, aniDbEpisode, opts.FileIndex)
 		if err != nil {
-			r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
+			r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
 			return err
 		}
 	}
 
 	if torrentToStream == nil {
-		r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
+		r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
 		return fmt.Errorf("torrentstream: No torrent selected")
 	}
 
+	// Remember the selected torrent so that the stream can be resumed
+	if torrentToStream.AnimeTorrent != nil {
+		r.selectionHistoryMap.Set(opts.MediaId, torrentToStream.AnimeTorrent)
+	}
+
+	return r.startSession(opts, media, aniDbEpisode, torrentToStream, loadingStatus, 0)
+}
+
+// startSession creates the session of the client for the torrent and sends the stream to the player.
+// readOffset is the position in the file from which the pieces are prioritized, used when resuming a stream.
+func (r *Repository) startSession(
+	opts *StartStreamOptions,
+	media *anilist.CompleteAnime,
+	aniDbEpisode string,
+	torrentToStream *playbackTorrent,
+	loadingStatus loadingStatusFunc,
+	readOffset int64,
+) error {
+	sessionId := getSessionId(opts.ClientId)
+
+	// Check again, another client might have started a stream in the meantime
+	if err := r.client.canAddSession(sessionId); err != nil {
+		r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
+		r.client.dropTorrent(torrentToStream.Torrent)
+		return err
+	}
+
+	// The media player can only play one stream at a time
+	if opts.PlaybackType == PlaybackTypeDefault {
+		if other, ok := r.client.getMediaPlayerSession(); ok && other.id != sessionId {
+			r.logger.Debug().Str("sessionId", other.id).Msg("torrentstream: Stopping the stream using the media player")
+			r.stopSession(other, false)
+		}
+	}
+
 	//
-	// Set current file & torrent
+	// Create the session
 	//
-	r.client.currentFile = mo.Some(torrentToStream.File)
-	r.client.currentTorrent = mo.Some(torrentToStream.Torrent)
+	session := &streamSession{
+		id:               sessionId,
+		clientId:         opts.ClientId,
+		mediaId:          opts.MediaId,
+		episodeNumber:    opts.EpisodeNumber,
+		aniDbEpisode:     aniDbEpisode,
+		playbackType:     opts.PlaybackType,
+		priorityStrategy: PriorityStrategyStreaming,
+		torrent:          torrentToStream.Torrent,
+		file:             torrentToStream.File,
+		fileIndex:        slices.Index(torrentToStream.Torrent.Files(), torrentToStream.File),
+		startedAt:        time.Now(),
+		media:            media,
+		selectedTorrent:  r.getSelectedTorrent(opts.MediaId, torrentToStream.Torrent),
+		readOffset:       readOffset,
+	}
+	previous, hasPrevious := r.client.getSession(sessionId)
+	r.client.addSession(session)
+
+	// Persist the session so that it can be resumed after a restart
+	go r.persistSession(session)
 
-	r.sendTorrentLoadingStatus(TLSStateStartingServer, "")
+	// Extract the subtitles and fonts so that the web player can display styled subtitles
+	r.startExtraction(session)
+
+	// Release the previous stream of the client
+	// This is done after the new session is added so that a torrent used by both sessions is not dropped
+	if hasPrevious {
+		r.releaseSession(previous)
+		if previous.playbackType == PlaybackTypeDefault && opts.PlaybackType != PlaybackTypeDefault {
+			r.mediaPlayerRepository.Stop()
+		}
+	}
+
+	// Drop the torrents that are no longer needed (e.g. the last streamed torrent)
+	go r.client.dropUnusedTorrents()
+
+	loadingStatus.send(TLSStateStartingServer, "")
 
 	settings, ok := r.settings.Get()
 	if ok && settings.UseSeparateServer {
@@ -102,35 +190,35 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 		r.serverManager.startServer()
 	}
 
-	r.sendTorrentLoadingStatus(TLSStateSendingStreamToMediaPlayer, "")
+	loadingStatus.send(TLSStateSendingStreamToMediaPlayer, "")
 
 	go func() {
 		// Add the torrent to the history if it is a batch & manually selected
-		if len(r.client.currentTorrent.MustGet().Files()) > 1 && opts.Torrent != nil {
+		if len(session.torrent.Files()) > 1 && opts.Torrent != nil {
 			r.AddBatchHistory(opts.MediaId, opts.Torrent) // ran in goroutine
 		}
 
 		for {
 			// This is to make sure the client is ready to stream before we start the stream
-			if r.client.readyToStream() {
+			if r.client.readyToStream(session) {
 				break
 			}
-			// If for some reason the torrent is dropped, we kill the goroutine
-			if r.client.torrentClient.IsAbsent() || r.client.currentTorrent.IsAbsent() {
+			// If for some reason the session is stopped or replaced, we kill the goroutine
+			if current, ok := r.client.getSession(sessionId); r.client.torrentClient.IsAbsent() || !ok || current != session {
 				return
 			}
-			r.logger.Debug().Msg("torrentstream: Waiting for playable threshold to be reached")
+			r.logger.Debug().Str("sessionId", sessionId).Msg("torrentstream: Waiting for playable threshold to be reached")
 			time.Sleep(3 * time.Second) // Wait for 3 secs before checking again
 		}
 
 		event := &TorrentStreamSendStreamToMediaPlayerEvent{
 			WindowTitle:  "",
-			StreamURL:    r.client.GetStreamingUrl(),
+			StreamURL:    r.client.GetStreamingUrl(session),
 			Media:        media.ToBaseAnime(),
 			AniDbEpisode: aniDbEpisode,
 			PlaybackType: string(opts.PlaybackType),
 		}
-		err = hook.GlobalHookManager.OnTorrentStreamSendStreamToMediaPlayer().Trigger(event)
+		err := hook.GlobalHookManager.OnTorrentStreamSendStreamToMediaPlayer().Trigger(event)
 		if err != nil {
 			r.logger.Error().Err(err).Msg("torrentstream: Failed to trigger hook")
 			return
@@ -153,14 +241,15 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 			//
 			r.logger.Debug().Msg("torrentstream: Starting the media player")
 			err = r.playbackManager.StartStreamingUsingMediaPlayer(windowTitle, &playbackmanager.StartPlayingOptions{
-				Payload:   streamURL,
-				UserAgent: opts.UserAgent,
-				ClientId:  opts.ClientId,
+				Payload:      streamURL,
+				UserAgent:    opts.UserAgent,
+				ClientId:     opts.ClientId,
+				StreamSource: viewingstats.SourceTorrentStream,
 			}, media, aniDbEpisode)
 			if err != nil {
 				// Failed to start the stream, we'll drop the torrents and stop the server
-				r.wsEventManager.SendEvent(eventTorrentLoadingFailed, nil)
-				_ = r.StopStream()
+				r.sendEventToClient(opts.ClientId, eventTorrentLoadingFailed, nil)
+				_ = r.StopStream(opts.ClientId)
 				r.logger.Error().Err(err).Msg("torrentstream: Failed to start the stream")
 			}
 
@@ -171,59 +260,73 @@ func (r *Repository) StartStream(opts *StartStreamOptions) (err error) {
 				MediaId       int    `json:"mediaId"`
 				EpisodeNumber int    `json:"episodeNumber"`
 			}{
-				Url:           r.client.GetStreamingUrl(),
+				Url:           streamURL,
 				MediaId:       opts.MediaId,
 				EpisodeNumber: opts.EpisodeNumber,
 			})
 
 			// Signal to the client that the torrent has started playing (remove loading status)
 			// We can't know for sure
-			r.wsEventManager.SendEvent(eventTorrentStartedPlaying, nil)
+			r.sendEventToClient(opts.ClientId, eventTorrentStartedPlaying, nil)
 		}
 	}()
 
-	r.wsEventManager.SendEvent(eventTorrentLoaded, nil)
-	r.logger.Info().Msg("torrentstream: Stream started")
+	r.sendEventToClient(opts.ClientId, eventTorrentLoaded, nil)
+	r.logger.Info().Str("sessionId", sessionId).Msg("torrentstream: Stream started")
 
 	return nil
 }
 
-func (r *Repository) StopStream() error {
+// StopStream stops the stream of the client.
+func (r *Repository) StopStream(clientId string) error {
 	defer func() {
 		if r := recover(); r != nil {
 		}
 	}()
-	r.logger.Info().Msg("torrentstream: Stopping stream")
 
-	// Stop the client
-	// This will stop the stream and close the server
-	// This also sends the eventTorrentStopped event
-	r.client.mu.Lock()
-	//r.client.stopCh = make(chan struct{})
-	r.client.repository.logger.Debug().Msg("torrentstream: Handling media player stopped event")
-	// This is to prevent the client from downloading the whole torrent when the user stops watching
-	// Also, the torrent might be a batch - so we don't want to download the whole thing
-	if r.client.currentTorrent.IsPresent() {
-		if r.client.currentTorrentStatus.ProgressPercentage < 70 {
-			r.client.repository.logger.Debug().Msg("torrentstream: Dropping torrent, completion is less than 70%")
-			r.client.dropTorrents()
+	session, ok := r.client.getSession(getSessionId(clientId))
+	if !ok {
+		return nil
+	}
+
+	r.stopSession(session, true)
+
+	return nil
+}
+
+// StopAllStreams stops the streams of all the clients.
+func (r *Repository) StopAllStreams() error {
+	defer func() {
+		if r := recover(); r != nil {
 		}
-		r.client.repository.logger.Debug().Msg("torrentstream: Resetting current torrent and status")
+	}()
+
+	r.logger.Info().Msg("torrentstream: Stopping all streams")
+	for _, session := range r.client.getSessions() {
+		r.stopSession(session, true)
 	}
-	r.client.currentTorrent = mo.None[*torrent.Torrent]() // Reset the current torrent
-	r.client.currentFile = mo.None[*torrent.File]()       // Reset the current file
-	r.client.currentTorrentStatus = TorrentStatus{}       // Reset the torrent status
-	settings, ok := r.client.repository.settings.Get()
-	if ok && settings.UseSeparateServer {
-		r.client.repository.serverManager.stopServer() // Stop the server
+
+	return nil
+}
+
+// stopSession stops the stream of the session.
+func (r *Repository) stopSession(session *streamSession, stopMediaPlayer bool) {
+	r.logger.Info().Str("sessionId", session.id).Msg("torrentstream: Stopping stream")
+
+	if !r.releaseSession(session) {
+		return
 	}
-	r.client.repository.wsEventManager.SendEvent(eventTorrentStopped, nil) // Send torrent stopped event
-	r.client.repository.mediaPlayerRepository.Stop()                       // Stop the media player gracefully if it's running
-	r.client.mu.Unlock()
 
-	r.logger.Info().Msg("torrentstream: Stream stopped")
+	settings, ok := r.settings.Get()
+	if ok && settings.UseSeparateServer && len(r.client.getSessions()) == 0 {
+		r.serverManager.stopServer() // Stop the server
+	}
+	r.sendEventToClient(session.clientId, eventTorrentStopped, nil) // Send torrent stopped event
+	if stopMediaPlayer && session.playbackType == PlaybackTypeDefault {
+		r.mediaPlayerRepository.Stop() // Stop the media player gracefully if it's running
+	}
 
-	return nil
+	r.logger.Info().Str("sessionId", session.id).Msg("torrentstream: Stream stopped")
 }
 
 //func (r *Repository) StopStream() error {
@@ -243,25 +346,90 @@ func (r *Repository) StopStream() error {
 //	return nil
 //}
 
-func (r *Repository) DropTorrent() error {
-	r.logger.Info().Msg("torrentstream: Dropping last torrent")
+// releaseSession removes the session and drops its torrent if less than 70% of the file has been downloaded.
+// Otherwise, the torrent is kept in the cache until it is evicted by the retention policy.
+// It returns false if the session was already released.
+func (r *Repository) releaseSession(session *streamSession) bool {
+	if !r.client.removeSession(session) {
+		return false
+	}
+
+	r.saveSessionWatchHistory(session)
+
+	// Keep the prebuffered episode if the stream was stopped, the client might start it next
+	// If the stream was replaced by another one, the prebuffered episode was not used and is dropped
+	// This is done first so that a batch torrent containing the next episode is not dropped
+	r.client.mu.Lock()
+	_, replaced := r.client.sessions[session.id]
+	if pb := session.prebuffer; pb != nil {
+		session.prebuffer = nil
+		if replaced {
+			r.client.dropTorrentLocked(pb.torrent)
+		} else {
+			r.client.keepPrebufferedEpisodeLocked(session.id, pb)
+		}
+	}
+	r.client.mu.Unlock()
+
+	// This is to prevent the client from downloading the whole torrent when the user stops watching
+	// Also, the torrent might be a batch - so we don't want to download the whole thing
+	if r.client.getTorrentPercentage(session.file) < 70 {
+		r.logger.Debug().Str("sessionId", session.id).Msg("torrentstream: Dropping torrent, completion is less than 70%")
+		r.client.dropTorrent(session.torrent)
+	} else {
+		r.client.mu.Lock()
+		r.client.addCacheEntryLocked(session)
+		r.client.mu.Unlock()
+	}
+
+	// The stream was stopped, it should not be resumed
+	if !replaced {
+		go r.deletePersistedSession(session.id)
+		r.stopExtraction(session.id)
+	}
+
+	return true
+}
 
+// DropTorrent stops the stream of the client and drops its torrent completely.
+func (r *Repository) DropTorrent(clientId string) error {
 	if r.client.torrentClient.IsAbsent() {
 		return nil
 	}
 
-	for _, t := range r.client.torrentClient.MustGet().Torrents() {
-		t.Drop()
+	session, ok := r.client.getSession(getSessionId(clientId))
+	if !ok {
+		return nil
 	}
 
-	// Also stop the server, since it's dropped
-	settings, ok := r.settings.Get()
-	if ok && settings.UseSeparateServer {
-		r.serverManager.stopServer()
+	r.logger.Info().Str("sessionId", session.id).Msg("torrentstream: Dropping torrent")
+
+	r.stopSession(session, true)
+	r.client.dropTorrent(session.torrent)
+	r.client.mu.Lock()
+	r.client.dropPrebufferedEpisodeLocked(session.id)
+	r.client.mu.Unlock()
+
+	return nil
+}
+
+// DropAllTorrents stops all the streams and drops all the torrents, including the cached ones.
+func (r *Repository) DropAllTorrents() error {
+	if r.client.torrentClient.IsAbsent() {
+		return nil
 	}
-	r.mediaPlayerRepository.Stop()
 
-	r.logger.Info().Msg("torrentstream: Dropped last torrent")
+	r.logger.Info().Msg("torrentstream: Dropping all torrents")
+	for _, session := range r.client.getSessions() {
+		r.stopSession(session, true)
+	}
+	r.client.mu.Lock()
+	for _, t := range r.client.torrentClient.MustGet().Torrents() {
+		t.Drop()
+	}
+	r.client.cache = make(map[string]*cacheEntry)
+	r.client.mu.Unlock()
+	r.logger.Info().Msg("torrentstream: Dropped all torrents")
 
 	return nil
 }
@@ -5,6 +5,7 @@ package util
 import (
 	"context"
 	"os/exec"
+	"syscall"
 )
 
 func NewCmd(arg string, args ...string) *exec.Cmd {
@@ -20,3 +21,14 @@ func NewCmdCtx(ctx context.Context, arg string, args ...string) *exec.Cmd {
 	}
 	return exec.CommandContext(ctx, arg, args...)
 }
+
+// StartWithNiceness starts the command with the given niceness, from 0 (normal) to 19 (lowest priority).
+func StartWithNiceness(cmd *exec.Cmd, niceness int) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if niceness > 0 {
+		_ = syscall.Setpriority(syscall.PRIO_PROCESS, cmd.Process.Pid, min(niceness, 19))
+	}
+	return nil
+}
@@ -35,3 +35,19 @@ func NewCmdCtx(ctx context.Context, arg string, args ...string) *exec.Cmd {
 	}
 	return cmd
 }
+
+// StartWithNiceness starts the command with the given niceness, from 0 (normal) to 19 (lowest priority).
+// Windows only has priority classes, high niceness values map to the idle class.
+func StartWithNiceness(cmd *exec.Cmd, niceness int) error {
+	if niceness > 0 {
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+		if niceness >= 15 {
+			cmd.SysProcAttr.CreationFlags |= 0x00000040 // IDLE_PRIORITY_CLASS
+		} else {
+			cmd.SysProcAttr.CreationFlags |= 0x00004000 // BELOW_NORMAL_PRIORITY_CLASS
+		}
+	}
+	return cmd.Start()
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/samber/lo"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -403,7 +404,17 @@ func (c *Cacher) ClearMediastreamVideoFiles() error {
 	return err
 }
 
+const (
+	// MediastreamSegmentsMaxSize is the maximum size of the transcoded segments kept across sessions, in bytes.
+	MediastreamSegmentsMaxSize int64 = 10 * 1024 * 1024 * 1024
+	// MediastreamTrickplayMaxSize is the maximum size of the seek-preview thumbnails, in bytes.
+	MediastreamTrickplayMaxSize int64 = 2 * 1024 * 1024 * 1024
+)
+
 // TrimMediastreamVideoFiles clears all mediastream video file caches if the number of files exceeds the given limit.
+// Seek-preview thumbnails and transcoded segments are kept since they are expensive to generate,
+// they are evicted separately, starting from the least recently played files,
+// once they exceed MediastreamTrickplayMaxSize and MediastreamSegmentsMaxSize.
 func (c *Cacher) TrimMediastreamVideoFiles() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -417,14 +428,97 @@ func (c *Cacher) TrimMediastreamVideoFiles() error {
 	// If the number of files exceeds 10, remove all files
 	if len(files) > 10 {
 		for _, file := range files {
-			_ = os.RemoveAll(filepath.Join(c.dir, "videofiles", file.Name()))
+			fileDir := filepath.Join(c.dir, "videofiles", file.Name())
+			entries, err := os.ReadDir(fileDir)
+			if err != nil {
+				_ = os.RemoveAll(fileDir)
+				continue
+			}
+			kept := 0
+			for _, entry := range entries {
+				if entry.Name() == "trickplay" || entry.Name() == "segments" {
+					kept++
+					continue
+				}
+				_ = os.RemoveAll(filepath.Join(fileDir, entry.Name()))
+			}
+			if kept == 0 {
+				_ = os.RemoveAll(fileDir)
+			}
 		}
 	}
 
+	c.trimMediastreamDirs("segments", MediastreamSegmentsMaxSize)
+	c.trimMediastreamDirs("trickplay", MediastreamTrickplayMaxSize)
+
 	c.stores = make(map[string]*CacheStore)
 	return err
 }
 
+// TrimMediastreamSegments removes the least recently used transcoded segments once they exceed MediastreamSegmentsMaxSize.
+func (c *Cacher) TrimMediastreamSegments() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.trimMediastreamDirs("segments", MediastreamSegmentsMaxSize)
+}
+
+// TrimMediastreamTrickplay removes the least recently used seek-preview thumbnails once they exceed MediastreamTrickplayMaxSize.
+func (c *Cacher) TrimMediastreamTrickplay() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.trimMediastreamDirs("trickplay", MediastreamTrickplayMaxSize)
+}
+
+// trimMediastreamDirs removes the least recently used directories with the given name (e.g. "segments")
+// until their total size is under maxSize.
+// The modification time of these directories is updated each time the file is played.
+func (c *Cacher) trimMediastreamDirs(name string, maxSize int64) {
+	type cacheDir struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	files, err := os.ReadDir(filepath.Join(c.dir, "videofiles"))
+	if err != nil {
+		return
+	}
+
+	dirs := make([]cacheDir, 0)
+	var totalSize int64
+	for _, file := range files {
+		dirPath := filepath.Join(c.dir, "videofiles", file.Name(), name)
+		info, err := os.Stat(dirPath)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		dir := cacheDir{path: dirPath, modTime: info.ModTime()}
+		_ = filepath.Walk(dirPath, func(_ string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				dir.size += info.Size()
+			}
+			return nil
+		})
+		totalSize += dir.size
+		dirs = append(dirs, dir)
+	}
+
+	// Oldest first
+	slices.SortFunc(dirs, func(a, b cacheDir) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	for _, dir := range dirs {
+		if totalSize <= maxSize {
+			break
+		}
+		_ = os.RemoveAll(dir.path)
+		totalSize -= dir.size
+	}
+}
+
 func (c *Cacher) GetMediastreamVideoFilesTotalSize() (int64, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
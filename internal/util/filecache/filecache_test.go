@@ -4,6 +4,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"os"
 	"path/filepath"
 	"seanime/internal/test_utils"
 	"sync"
@@ -160,3 +161,41 @@ func TestCacherSetAndGet(t *testing.T) {
 	wg.Wait()
 
 }
+
+func TestTrimMediastreamDirs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cacher, err := NewCacher(tempDir)
+	require.NoError(t, err)
+
+	// 3 files with 100 bytes of segments and thumbnails each
+	// "a" is the least recently streamed, "c" has the least recently shown thumbnails
+	now := time.Now()
+	for i, hash := range []string{"a", "b", "c"} {
+		dir := filepath.Join(tempDir, "videofiles", hash, "segments")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "video-720p"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "video-720p", "segment-0.ts"), make([]byte, 100), 0644))
+		modTime := now.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(dir, modTime, modTime))
+
+		trickplayDir := filepath.Join(tempDir, "videofiles", hash, "trickplay")
+		require.NoError(t, os.MkdirAll(trickplayDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(trickplayDir, "sprite-0.jpg"), make([]byte, 100), 0644))
+		modTime = now.Add(-time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(trickplayDir, modTime, modTime))
+	}
+
+	cacher.trimMediastreamDirs("segments", 250)
+
+	assert.NoDirExists(t, filepath.Join(tempDir, "videofiles", "a", "segments"))
+	assert.DirExists(t, filepath.Join(tempDir, "videofiles", "a", "trickplay"))
+	assert.DirExists(t, filepath.Join(tempDir, "videofiles", "b", "segments"))
+	assert.DirExists(t, filepath.Join(tempDir, "videofiles", "c", "segments"))
+
+	cacher.trimMediastreamDirs("trickplay", 150)
+
+	assert.DirExists(t, filepath.Join(tempDir, "videofiles", "a", "trickplay"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "videofiles", "b", "trickplay"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "videofiles", "c", "trickplay"))
+	assert.DirExists(t, filepath.Join(tempDir, "videofiles", "c", "segments"))
+}
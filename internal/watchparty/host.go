@@ -0,0 +1,282 @@
+package watchparty
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// joinTimeout is the time given to a peer to send its join message
+	joinTimeout = 10 * time.Second
+	// peerTimeout is the time after which a silent peer is disconnected, peers send their status every syncInterval
+	peerTimeout = 30 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	// The peers are Seanime instances, which do not send an origin.
+	// Requests with an origin come from web pages and are rejected so that a page cannot join the party from the browser.
+	CheckOrigin: func(r *http.Request) bool {
+		return r.Header.Get("Origin") == ""
+	},
+}
+
+type (
+	// host holds the connections of the peers when this instance hosts the party.
+	host struct {
+		conns map[string]*wsConn // By member ID
+	}
+)
+
+// startHosting makes this instance the host of the session.
+func (m *Manager) startHosting(sessionId string, state *PlaybackState) {
+	m.host = &host{conns: make(map[string]*wsConn)}
+	m.clockOffset = 0
+	m.session = &Session{
+		Id:       sessionId,
+		Role:     RoleHost,
+		MemberId: m.memberId,
+		Members: []*Member{{
+			Id:       m.memberId,
+			Name:     m.name,
+			IsHost:   true,
+			LastSeen: time.Now().UnixMilli(),
+		}},
+		State: state,
+	}
+
+	if m.syncer == nil {
+		m.startSyncer()
+	} else {
+		m.syncer.trigger()
+	}
+	m.sendSessionToClient()
+}
+
+// stopHosting disconnects the peers.
+func (m *Manager) stopHosting() {
+	for _, c := range m.host.conns {
+		c.close()
+	}
+	m.host = nil
+}
+
+func (h *host) broadcast(t string, payload interface{}) {
+	for _, c := range h.conns {
+		_ = c.send(t, payload)
+	}
+}
+
+// broadcastSession sends the members and the playback state to the peers and the local client.
+func (m *Manager) broadcastSession() {
+	m.host.broadcast(msgSession, m.sessionPayload())
+	m.sendSessionToClient()
+}
+
+func (m *Manager) sessionPayload() *sessionPayload {
+	return &sessionPayload{
+		Id:       m.session.Id,
+		Members:  m.session.Members,
+		State:    m.session.State,
+		HostTime: m.hostNow(),
+	}
+}
+
+// ServeHTTP accepts the websocket connections of the peers.
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	hosting := m.host != nil
+	m.mu.Unlock()
+	if !hosting {
+		http.Error(w, "no watch party is hosted", http.StatusNotFound)
+		return
+	}
+
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := newWsConn(ws)
+	defer c.close()
+
+	// The first message identifies the member
+	_ = ws.SetReadDeadline(time.Now().Add(joinTimeout))
+	msg, err := c.read()
+	if err != nil || msg.Type != msgJoin {
+		return
+	}
+	var join joinPayload
+	if err := json.Unmarshal(msg.Payload, &join); err != nil || join.Id == "" {
+		return
+	}
+
+	member, err := m.addMember(c, &join, peerAddress(r, join.Port))
+	if err != nil {
+		_ = c.send(msgError, &errorPayload{Message: err.Error()})
+		return
+	}
+	defer m.removeMember(member.Id, c)
+
+	for {
+		_ = ws.SetReadDeadline(time.Now().Add(peerTimeout))
+		msg, err := c.read()
+		if err != nil {
+			return
+		}
+		m.handlePeerMessage(member.Id, c, msg)
+	}
+}
+
+func (m *Manager) addMember(c *wsConn, join *joinPayload, address string) (*Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.host == nil {
+		return nil, errors.New("the watch party has ended")
+	}
+	if subtle.ConstantTimeCompare([]byte(join.Passcode), []byte(m.passcode)) != 1 {
+		return nil, errors.New("wrong passcode")
+	}
+	if join.Id == m.memberId {
+		return nil, errors.New("cannot join a watch party hosted by the same instance")
+	}
+
+	// A member that reconnects replaces its previous connection
+	if previous, ok := m.host.conns[join.Id]; ok {
+		previous.close()
+	}
+	m.host.conns[join.Id] = c
+
+	member := &Member{
+		Id:       join.Id,
+		Name:     defaultName(join.Name),
+		Address:  address,
+		LastSeen: time.Now().UnixMilli(),
+	}
+	m.session.Members = slices.DeleteFunc(m.session.Members, func(other *Member) bool { return other.Id == join.Id })
+	m.session.Members = append(m.session.Members, member)
+
+	m.logger.Info().Str("name", member.Name).Str("address", address).Msg("watch party: Member joined")
+
+	m.broadcastSession()
+	m.notifyClient("info-toast", fmt.Sprintf("%s joined the watch party", member.Name))
+
+	return member, nil
+}
+
+func (m *Manager) removeMember(id string, c *wsConn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.host == nil || m.host.conns[id] != c {
+		return
+	}
+	delete(m.host.conns, id)
+
+	idx := slices.IndexFunc(m.session.Members, func(member *Member) bool { return member.Id == id })
+	if idx == -1 {
+		return
+	}
+	name := m.session.Members[idx].Name
+	m.session.Members = slices.Delete(m.session.Members, idx, idx+1)
+
+	m.logger.Info().Str("name", name).Msg("watch party: Member left")
+
+	m.broadcastSession()
+	m.notifyClient("info-toast", fmt.Sprintf("%s left the watch party", name))
+}
+
+func (m *Manager) handlePeerMessage(memberId string, c *wsConn, msg *message) {
+	switch msg.Type {
+	case msgPing:
+		var ping pingPayload
+		if err := json.Unmarshal(msg.Payload, &ping); err == nil {
+			_ = c.send(msgPong, &pongPayload{ClientTime: ping.ClientTime, HostTime: time.Now().UnixMilli()})
+		}
+	case msgStatus:
+		var status statusPayload
+		if err := json.Unmarshal(msg.Payload, &status); err != nil {
+			return
+		}
+		m.mu.Lock()
+		if m.session != nil {
+			for _, member := range m.session.Members {
+				if member.Id == memberId {
+					member.Loaded = status.Loaded
+					member.Position = status.Position
+					member.Paused = status.Paused
+					member.LastSeen = time.Now().UnixMilli()
+				}
+			}
+		}
+		m.mu.Unlock()
+	case msgControl:
+		var ctrl controlPayload
+		if err := json.Unmarshal(msg.Payload, &ctrl); err != nil {
+			return
+		}
+		m.mu.Lock()
+		var err error
+		if m.host != nil {
+			err = m.applyControl(memberId, &ctrl)
+		}
+		m.mu.Unlock()
+		if err != nil {
+			_ = c.send(msgError, &errorPayload{Message: err.Error()})
+		}
+	}
+}
+
+// applyControl updates the playback state with the action of a member and sends it to the party.
+func (m *Manager) applyControl(memberId string, ctrl *controlPayload) error {
+	state := *m.session.State
+
+	switch ctrl.Action {
+	case actionPlay:
+		if ctrl.Media == nil {
+			return ErrBadRequest
+		}
+		media := *ctrl.Media
+		// The torrent of a peer is not trusted, the host and the members select one automatically
+		if memberId != m.memberId {
+			media.Torrent = nil
+			media.FileIndex = nil
+		}
+		state = PlaybackState{Media: &media}
+	case actionPause, actionResume, actionSeek:
+		if state.Media == nil {
+			return ErrNoMedia
+		}
+		state.Position = max(ctrl.Position, 0)
+		if ctrl.Action != actionSeek {
+			state.Paused = ctrl.Action == actionPause
+		}
+	default:
+		return ErrBadRequest
+	}
+	state.UpdatedAt = m.hostNow()
+	m.session.State = &state
+
+	m.logger.Debug().Str("memberId", memberId).Str("action", ctrl.Action).Float64("position", state.Position).Msg("watch party: Playback updated")
+
+	m.broadcastSession()
+	m.syncer.trigger()
+
+	return nil
+}
+
+// peerAddress returns the URL of the main server of a peer, from the address of the request and the port reported by the peer.
+func peerAddress(r *http.Request, port int) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || port == 0 {
+		return ""
+	}
+	return fmt.Sprintf("http://%s", net.JoinHostPort(ip, fmt.Sprint(port)))
+}
@@ -0,0 +1,212 @@
+package watchparty
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"seanime/internal/database/db"
+	"seanime/internal/database/db_bridge"
+	"seanime/internal/events"
+	"seanime/internal/library/playbackmanager"
+	"seanime/internal/torrentstream"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// clientId is the torrent stream client ID used by the watch party
+	clientId = "watch-party"
+	// onlineStreamStatusTimeout is the age from which the status reported by the online stream player is ignored
+	onlineStreamStatusTimeout = 5 * time.Second
+)
+
+type (
+	// LocalPlayer plays the media of the party on this instance.
+	// Local files and torrent streams are played with the external media player through the PlaybackManager,
+	// online streams are played by the client, which reports the status of its player.
+	LocalPlayer struct {
+		logger                  *zerolog.Logger
+		wsEventManager          events.WSEventManagerInterface
+		database                *db.Database
+		playbackManager         *playbackmanager.PlaybackManager
+		torrentstreamRepository *torrentstream.Repository
+
+		mu                   sync.Mutex
+		source               Source
+		onlineStreamStatus   *PlayerStatus
+		onlineStreamStatusAt time.Time
+	}
+
+	NewLocalPlayerOptions struct {
+		Logger                  *zerolog.Logger
+		WSEventManager          events.WSEventManagerInterface
+		Database                *db.Database
+		PlaybackManager         *playbackmanager.PlaybackManager
+		TorrentstreamRepository *torrentstream.Repository
+	}
+
+	// OnlineStreamCommand is sent to the client to control the online stream player.
+	OnlineStreamCommand struct {
+		Action   string  `json:"action"` // "pause", "resume" or "seek"
+		Position float64 `json:"position"`
+	}
+
+	// OnlineStreamStatus is sent by the client with the status of the online stream player.
+	OnlineStreamStatus struct {
+		Position float64 `json:"position"`
+		Paused   bool    `json:"paused"`
+	}
+)
+
+func NewLocalPlayer(opts *NewLocalPlayerOptions) *LocalPlayer {
+	ret := &LocalPlayer{
+		logger:                  opts.Logger,
+		wsEventManager:          opts.WSEventManager,
+		database:                opts.Database,
+		playbackManager:         opts.PlaybackManager,
+		torrentstreamRepository: opts.TorrentstreamRepository,
+	}
+	go ret.listenToClientEvents()
+	return ret
+}
+
+func (p *LocalPlayer) listenToClientEvents() {
+	subscriber := p.wsEventManager.SubscribeToClientEvents("watch-party")
+	for event := range subscriber.Channel {
+		if event.Type != events.WatchPartyPlayerStatusEvent {
+			continue
+		}
+		data, err := json.Marshal(event.Payload)
+		if err != nil {
+			continue
+		}
+		var status OnlineStreamStatus
+		if err := json.Unmarshal(data, &status); err != nil {
+			continue
+		}
+		p.mu.Lock()
+		p.onlineStreamStatus = &PlayerStatus{Position: status.Position, Paused: status.Paused}
+		p.onlineStreamStatusAt = time.Now()
+		p.mu.Unlock()
+	}
+}
+
+func (p *LocalPlayer) PlayMedia(media *Media) error {
+	p.mu.Lock()
+	p.source = media.Source
+	p.onlineStreamStatus = nil
+	p.mu.Unlock()
+
+	switch media.Source {
+	case SourceLocalFile:
+		path, err := p.findLocalFile(media.MediaId, media.EpisodeNumber)
+		if err != nil {
+			return err
+		}
+		return p.playbackManager.StartPlayingUsingMediaPlayer(&playbackmanager.StartPlayingOptions{
+			Payload:  path,
+			ClientId: clientId,
+		})
+
+	case SourceTorrentStream:
+		if p.torrentstreamRepository == nil {
+			return errors.New("torrent streaming is not available")
+		}
+		// Started in the background, finding and buffering the torrent can take a while
+		go func() {
+			err := p.torrentstreamRepository.StartStream(&torrentstream.StartStreamOptions{
+				MediaId:       media.MediaId,
+				EpisodeNumber: media.EpisodeNumber,
+				AniDBEpisode:  media.AniDBEpisode,
+				AutoSelect:    media.Torrent == nil,
+				Torrent:       media.Torrent,
+				FileIndex:     media.FileIndex,
+				ClientId:      clientId,
+				PlaybackType:  torrentstream.PlaybackTypeDefault,
+			})
+			if err != nil {
+				p.logger.Error().Err(err).Msg("watch party: Could not start the torrent stream")
+				p.wsEventManager.SendEvent(events.ErrorToast, "Watch party: "+err.Error())
+			}
+		}()
+		return nil
+
+	case SourceOnlineStream:
+		p.wsEventManager.SendEvent(events.WatchPartyPlayOnlineStream, media)
+		return nil
+	}
+
+	return fmt.Errorf("unknown source %q", media.Source)
+}
+
+// findLocalFile returns the path of the main local file of an episode.
+func (p *LocalPlayer) findLocalFile(mediaId int, episodeNumber int) (string, error) {
+	lfs, _, err := db_bridge.GetLocalFiles(p.database)
+	if err != nil {
+		return "", err
+	}
+	for _, lf := range lfs {
+		if lf.MediaId == mediaId && lf.IsMain() && lf.GetEpisodeNumber() == episodeNumber {
+			return lf.Path, nil
+		}
+	}
+	return "", fmt.Errorf("episode %d is not in the library", episodeNumber)
+}
+
+func (p *LocalPlayer) isOnlineStream() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.source == SourceOnlineStream
+}
+
+func (p *LocalPlayer) Pause() error {
+	if p.isOnlineStream() {
+		p.wsEventManager.SendEvent(events.WatchPartyPlayerCommand, &OnlineStreamCommand{Action: "pause"})
+		return nil
+	}
+	return p.playbackManager.Pause()
+}
+
+func (p *LocalPlayer) Resume() error {
+	if p.isOnlineStream() {
+		p.wsEventManager.SendEvent(events.WatchPartyPlayerCommand, &OnlineStreamCommand{Action: "resume"})
+		return nil
+	}
+	return p.playbackManager.Resume()
+}
+
+func (p *LocalPlayer) Seek(seconds float64) error {
+	if p.isOnlineStream() {
+		p.wsEventManager.SendEvent(events.WatchPartyPlayerCommand, &OnlineStreamCommand{Action: "seek", Position: seconds})
+		return nil
+	}
+	return p.playbackManager.Seek(seconds)
+}
+
+func (p *LocalPlayer) GetStatus() *PlayerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.source == SourceOnlineStream {
+		if p.onlineStreamStatus == nil || time.Since(p.onlineStreamStatusAt) > onlineStreamStatusTimeout {
+			return nil
+		}
+		ret := *p.onlineStreamStatus
+		return &ret
+	}
+
+	repo := p.playbackManager.MediaPlayerRepository
+	if repo == nil || !repo.IsRunning() {
+		return nil
+	}
+	status := repo.GetStatus()
+	if status == nil {
+		return nil
+	}
+	return &PlayerStatus{
+		Position: status.CurrentTimeInSeconds,
+		Paused:   !status.Playing,
+	}
+}
@@ -0,0 +1,270 @@
+package watchparty
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	handshakeTimeout = 10 * time.Second
+	// reconnectAttempts and reconnectDelay are used when the host role is transferred
+	reconnectAttempts = 10
+	reconnectDelay    = 500 * time.Millisecond
+)
+
+type (
+	// peer is the connection to the host when this instance is a member of a party hosted by another instance.
+	peer struct {
+		conn    *wsConn
+		address string
+	}
+)
+
+func (p *peer) send(t string, payload interface{}) error {
+	return p.conn.send(t, payload)
+}
+
+func (p *peer) close() {
+	p.conn.close()
+}
+
+// websocketUrl returns the URL of the websocket endpoint of an instance.
+func websocketUrl(address string) (string, error) {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	u.Path = WebsocketPath
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+// connect joins the party hosted at address.
+func (m *Manager) connect(address string) error {
+	wsUrl, err := websocketUrl(address)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	join := &joinPayload{
+		Id:       m.memberId,
+		Name:     m.name,
+		Passcode: m.passcode,
+		Port:     m.port,
+	}
+	m.mu.Unlock()
+
+	dialer := websocket.Dialer{HandshakeTimeout: handshakeTimeout}
+	ws, _, err := dialer.Dial(wsUrl, nil)
+	if err != nil {
+		return fmt.Errorf("could not connect to the host: %w", err)
+	}
+	c := newWsConn(ws)
+
+	sentAt := time.Now().UnixMilli()
+	if err := c.send(msgJoin, join); err != nil {
+		c.close()
+		return err
+	}
+
+	_ = ws.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	msg, err := c.read()
+	if err != nil {
+		c.close()
+		return fmt.Errorf("could not join the watch party: %w", err)
+	}
+	switch msg.Type {
+	case msgError:
+		c.close()
+		var payload errorPayload
+		_ = json.Unmarshal(msg.Payload, &payload)
+		return errors.New(payload.Message)
+	case msgSession:
+	default:
+		c.close()
+		return fmt.Errorf("unexpected message %q", msg.Type)
+	}
+
+	var session sessionPayload
+	if err := json.Unmarshal(msg.Payload, &session); err != nil || session.State == nil {
+		c.close()
+		return errors.New("invalid session received from the host")
+	}
+	now := time.Now().UnixMilli()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.host != nil {
+		c.close()
+		return ErrInSession
+	}
+	if m.peer != nil {
+		m.peer.close()
+	}
+
+	p := &peer{conn: c, address: address}
+	m.peer = p
+	// The offset is refined with the pings
+	m.clockOffset = session.HostTime + (now-sentAt)/2 - now
+	m.session = &Session{
+		Id:          session.Id,
+		Role:        RolePeer,
+		MemberId:    m.memberId,
+		HostAddress: address,
+		Members:     session.Members,
+		State:       session.State,
+	}
+
+	if m.syncer == nil {
+		m.startSyncer()
+	} else {
+		m.syncer.trigger()
+	}
+	m.sendSessionToClient()
+
+	go m.readFromHost(p)
+
+	return nil
+}
+
+func (m *Manager) readFromHost(p *peer) {
+	for {
+		_ = p.conn.ws.SetReadDeadline(time.Now().Add(peerTimeout))
+		msg, err := p.conn.read()
+		if err != nil {
+			m.mu.Lock()
+			// The connection is expected to be closed if the party was left or the host role was transferred
+			if m.peer == p {
+				m.peer = nil
+				m.reset()
+				m.logger.Warn().Err(err).Msg("watch party: Lost connection to the host")
+				m.notifyClient("error-toast", "Lost connection to the watch party host")
+			}
+			m.mu.Unlock()
+			return
+		}
+
+		if !m.handleHostMessage(p, msg) {
+			return
+		}
+	}
+}
+
+// handleHostMessage returns false if the connection should no longer be read.
+func (m *Manager) handleHostMessage(p *peer, msg *message) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.peer != p {
+		p.close()
+		return false
+	}
+
+	switch msg.Type {
+	case msgSession:
+		var session sessionPayload
+		if err := json.Unmarshal(msg.Payload, &session); err != nil || session.State == nil {
+			return true
+		}
+		m.session.Members = session.Members
+		m.session.State = session.State
+		m.sendSessionToClient()
+		m.syncer.trigger()
+
+	case msgPong:
+		var pong pongPayload
+		if err := json.Unmarshal(msg.Payload, &pong); err != nil {
+			return true
+		}
+		now := time.Now().UnixMilli()
+		m.clockOffset = pong.HostTime + (now-pong.ClientTime)/2 - now
+
+	case msgError:
+		var payload errorPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err == nil {
+			m.notifyClient("error-toast", payload.Message)
+		}
+
+	case msgEnded:
+		m.peer = nil
+		p.close()
+		m.reset()
+		m.logger.Info().Msg("watch party: The host ended the watch party")
+		m.notifyClient("info-toast", "The watch party has ended")
+		return false
+
+	case msgTransfer:
+		var transfer transferPayload
+		if err := json.Unmarshal(msg.Payload, &transfer); err != nil || transfer.State == nil {
+			return true
+		}
+		m.peer = nil
+		p.close()
+
+		if transfer.HostId == m.memberId {
+			// The state is converted to the local clock, which becomes the clock of the party
+			state := *transfer.State
+			state.UpdatedAt -= m.clockOffset
+			m.passcode = transfer.Passcode
+			m.startHosting(transfer.SessionId, &state)
+			m.logger.Info().Msg("watch party: This instance is now the host")
+			m.notifyClient("info-toast", "You are now the host of the watch party")
+			return false
+		}
+
+		m.session.HostAddress = transfer.Address
+		go m.reconnect(transfer.Address)
+		return false
+	}
+
+	return true
+}
+
+// reconnect connects to the new host after a transfer of the host role.
+// The new host may not have received the transfer yet, so the connection is retried.
+func (m *Manager) reconnect(address string) {
+	var err error
+	for i := 0; i < reconnectAttempts; i++ {
+		time.Sleep(reconnectDelay)
+
+		m.mu.Lock()
+		inSession := m.session != nil && m.host == nil && m.peer == nil
+		m.mu.Unlock()
+		if !inSession {
+			return
+		}
+
+		if err = m.connect(address); err == nil {
+			m.logger.Info().Str("address", address).Msg("watch party: Connected to the new host")
+			return
+		}
+	}
+
+	m.logger.Error().Err(err).Str("address", address).Msg("watch party: Could not connect to the new host")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session != nil && m.host == nil && m.peer == nil {
+		m.reset()
+		m.notifyClient("error-toast", "Could not connect to the new watch party host")
+	}
+}
@@ -0,0 +1,120 @@
+package watchparty
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Messages exchanged between the host and the peers
+const (
+	msgJoin     = "join"     // Peer → host, first message of the connection
+	msgSession  = "session"  // Host → peers, members and playback state
+	msgControl  = "control"  // Peer → host, action of a user
+	msgStatus   = "status"   // Peer → host, status of the local player
+	msgPing     = "ping"     // Peer → host, used to estimate the clock offset
+	msgPong     = "pong"     // Host → peer
+	msgTransfer = "transfer" // Host → peers, the host role is given to another member
+	msgEnded    = "ended"    // Host → peers
+	msgError    = "error"    // Host → peer
+)
+
+const (
+	actionPlay   = "play"
+	actionPause  = "pause"
+	actionResume = "resume"
+	actionSeek   = "seek"
+)
+
+const writeTimeout = 5 * time.Second
+
+type (
+	message struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+
+	joinPayload struct {
+		Id       string `json:"id"`
+		Name     string `json:"name"`
+		Passcode string `json:"passcode"`
+		Port     int    `json:"port"` // Port of the main server of the peer
+	}
+
+	sessionPayload struct {
+		Id       string         `json:"id"`
+		Members  []*Member      `json:"members"`
+		State    *PlaybackState `json:"state"`
+		HostTime int64          `json:"hostTime"`
+	}
+
+	controlPayload struct {
+		Action   string  `json:"action"`
+		Media    *Media  `json:"media,omitempty"` // Play
+		Position float64 `json:"position"`        // Pause, resume and seek
+	}
+
+	statusPayload struct {
+		Loaded   bool    `json:"loaded"`
+		Position float64 `json:"position"`
+		Paused   bool    `json:"paused"`
+	}
+
+	pingPayload struct {
+		ClientTime int64 `json:"clientTime"`
+	}
+
+	pongPayload struct {
+		ClientTime int64 `json:"clientTime"`
+		HostTime   int64 `json:"hostTime"`
+	}
+
+	transferPayload struct {
+		SessionId string         `json:"sessionId"`
+		HostId    string         `json:"hostId"`
+		Address   string         `json:"address"`
+		Passcode  string         `json:"passcode"`
+		State     *PlaybackState `json:"state"`
+		SentAt    int64          `json:"sentAt"`
+	}
+
+	errorPayload struct {
+		Message string `json:"message"`
+	}
+
+	// wsConn serializes the writes to a websocket connection.
+	wsConn struct {
+		ws *websocket.Conn
+		mu sync.Mutex
+	}
+)
+
+func newWsConn(ws *websocket.Conn) *wsConn {
+	return &wsConn{ws: ws}
+}
+
+func (c *wsConn) send(t string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.ws.WriteJSON(&message{Type: t, Payload: data})
+}
+
+func (c *wsConn) read() (*message, error) {
+	var msg message
+	if err := c.ws.ReadJSON(&msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (c *wsConn) close() {
+	_ = c.ws.Close()
+}
@@ -0,0 +1,228 @@
+package watchparty
+
+import (
+	"math"
+	"time"
+)
+
+// pingEvery is the number of sync ticks between two pings of a peer
+const pingEvery = 5
+
+type (
+	// syncer keeps the local player in sync with the playback state of the party,
+	// and sends the changes made by the user in the local player to the party.
+	syncer struct {
+		m         *Manager
+		triggerCh chan struct{}
+		done      chan struct{}
+
+		loadedMedia  string // Key of the media played by the local player
+		loading      bool   // Whether the local player has not reported a status since the media was played
+		lastStatus   *PlayerStatus
+		lastStatusAt time.Time
+		ignoreUntil  time.Time // Changes of the local player are not sent to the party before this time
+		ticks        int
+	}
+)
+
+// startSyncer is called under lock.
+func (m *Manager) startSyncer() {
+	m.syncer = &syncer{
+		m:         m,
+		triggerCh: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	go m.syncer.run()
+}
+
+func (s *syncer) run() {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	s.tick()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.triggerCh:
+		}
+		select {
+		case <-s.done:
+			return
+		default:
+			s.tick()
+		}
+	}
+}
+
+// trigger synchronizes the local player without waiting for the next tick.
+func (s *syncer) trigger() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *syncer) stop() {
+	close(s.done)
+}
+
+func (s *syncer) tick() {
+	m := s.m
+
+	m.mu.Lock()
+	if m.session == nil || m.syncer != s {
+		m.mu.Unlock()
+		return
+	}
+	state := *m.session.State
+	hostNow := m.hostNow()
+	m.mu.Unlock()
+
+	if state.Media == nil {
+		s.report(nil)
+		return
+	}
+
+	// Play the episode of the party
+	if key := state.Media.key(); key != s.loadedMedia {
+		s.loadedMedia = key
+		s.loading = true
+		s.lastStatus = nil
+		s.ignoreUntil = time.Now().Add(commandGracePeriod)
+		m.logger.Debug().Str("media", key).Msg("watch party: Playing the episode of the party")
+		if err := m.player.PlayMedia(state.Media); err != nil {
+			m.logger.Error().Err(err).Msg("watch party: Could not play the episode")
+			m.notifyClient("error-toast", "Watch party: "+err.Error())
+		}
+		s.report(nil)
+		return
+	}
+
+	status := m.player.GetStatus()
+	if status == nil {
+		s.lastStatus = nil
+		s.report(nil)
+		return
+	}
+	expected := state.positionAt(hostNow)
+
+	switch {
+	case s.loading:
+		// Apply the position of the party once the episode is loaded
+		s.loading = false
+		s.correct(&state, status, expected)
+
+	case time.Now().Before(s.ignoreUntil):
+		// Wait for the local player to apply the last command
+
+	case s.userAction(&state, status):
+
+	default:
+		s.correct(&state, status, expected)
+	}
+
+	s.lastStatus = status
+	s.lastStatusAt = time.Now()
+	s.report(status)
+}
+
+// userAction sends the pause, resume or seek made in the local player to the party.
+// Returns true if an action was sent.
+func (s *syncer) userAction(state *PlaybackState, status *PlayerStatus) bool {
+	last := s.lastStatus
+	if last == nil {
+		return false
+	}
+
+	var ctrl *controlPayload
+	switch {
+	case status.Paused != last.Paused && status.Paused != state.Paused:
+		ctrl = &controlPayload{Action: actionResume, Position: status.Position}
+		if status.Paused {
+			ctrl.Action = actionPause
+		}
+	default:
+		// Position the local player would have reached without a seek
+		expected := last.Position
+		if !last.Paused {
+			expected += time.Since(s.lastStatusAt).Seconds()
+		}
+		if math.Abs(status.Position-expected) > seekThreshold {
+			ctrl = &controlPayload{Action: actionSeek, Position: status.Position}
+		}
+	}
+	if ctrl == nil {
+		return false
+	}
+
+	s.ignoreUntil = time.Now().Add(commandGracePeriod)
+	s.m.logger.Debug().Str("action", ctrl.Action).Float64("position", ctrl.Position).Msg("watch party: Sending local action")
+	if err := s.m.control(ctrl); err != nil {
+		s.m.logger.Warn().Err(err).Msg("watch party: Could not send local action")
+	}
+	return true
+}
+
+// correct applies the pause state and the position of the party to the local player.
+func (s *syncer) correct(state *PlaybackState, status *PlayerStatus, expected float64) {
+	p := s.m.player
+	sent := false
+
+	if math.Abs(status.Position-expected) > driftThreshold {
+		s.m.logger.Trace().Float64("position", status.Position).Float64("expected", expected).Msg("watch party: Correcting drift")
+		_ = p.Seek(expected)
+		sent = true
+	}
+	if state.Paused && !status.Paused {
+		_ = p.Pause()
+		sent = true
+	} else if !state.Paused && status.Paused {
+		_ = p.Resume()
+		sent = true
+	}
+
+	if sent {
+		s.ignoreUntil = time.Now().Add(commandGracePeriod)
+	}
+}
+
+// report sends the status of the local player to the party.
+func (s *syncer) report(status *PlayerStatus) {
+	m := s.m
+	st := statusPayload{Loaded: status != nil && !s.loading}
+	if status != nil {
+		st.Position = status.Position
+		st.Paused = status.Paused
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session == nil {
+		return
+	}
+
+	switch {
+	case m.host != nil:
+		for _, member := range m.session.Members {
+			if member.Id == m.memberId {
+				member.Loaded = st.Loaded
+				member.Position = st.Position
+				member.Paused = st.Paused
+				member.LastSeen = time.Now().UnixMilli()
+			}
+		}
+		m.broadcastSession()
+	case m.peer != nil:
+		_ = m.peer.send(msgStatus, &st)
+		if s.ticks%pingEvery == 0 {
+			_ = m.peer.send(msgPing, &pingPayload{ClientTime: time.Now().UnixMilli()})
+		}
+		s.ticks++
+	}
+}
@@ -0,0 +1,407 @@
+package watchparty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"seanime/internal/events"
+	hibiketorrent "seanime/internal/extension/hibike/torrent"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// WebsocketPath is the path of the endpoint the members connect to, on the main server of the host.
+	WebsocketPath = "/api/v1/watch-party/ws"
+
+	SourceLocalFile     Source = "localfile"
+	SourceTorrentStream Source = "torrentstream"
+	SourceOnlineStream  Source = "onlinestream"
+
+	RoleHost Role = "host"
+	RolePeer Role = "peer"
+)
+
+var (
+	// syncInterval is the interval at which the local player is synchronized and the members report their status
+	syncInterval = time.Second
+	// driftThreshold is the difference with the position of the party, in seconds, from which the local player seeks
+	driftThreshold = 1.5
+	// seekThreshold is the jump of the local position between two statuses, in seconds, from which it counts as a seek by the user
+	seekThreshold = 3.0
+	// commandGracePeriod is the duration after a command is sent to the local player during which its changes are not sent to the party
+	commandGracePeriod = 2 * time.Second
+)
+
+var (
+	ErrNoSession  = errors.New("not in a watch party")
+	ErrNotHost    = errors.New("only the host can do this")
+	ErrInSession  = errors.New("already in a watch party")
+	ErrNoMedia    = errors.New("no episode is playing")
+	ErrBadRequest = errors.New("invalid request")
+	// ErrNoPasscode is returned when hosting without a passcode, anyone who can reach the instance could join the party otherwise
+	ErrNoPasscode = errors.New("a passcode is required to host a watch party")
+)
+
+type (
+	Source string
+	Role   string
+
+	// Media is the episode watched by the party.
+	// Local files are matched by media ID and episode number, so members can have different releases.
+	Media struct {
+		Source        Source `json:"source"`
+		MediaId       int    `json:"mediaId"`
+		EpisodeNumber int    `json:"episodeNumber"`
+		AniDBEpisode  string `json:"aniDbEpisode"`
+		Title         string `json:"title"` // Shown to the members
+		// Torrent streams, the torrent chosen by the host, nil to select one automatically.
+		// The torrents chosen by the other members are not used, the host selects one instead.
+		Torrent   *hibiketorrent.AnimeTorrent `json:"torrent,omitempty"`
+		FileIndex *int                        `json:"fileIndex,omitempty"`
+		// Online streams
+		Provider string `json:"provider,omitempty"`
+		Server   string `json:"server,omitempty"`
+		Dubbed   bool   `json:"dubbed,omitempty"`
+	}
+
+	// PlaybackState is the playback state shared by the party, it is set by the host.
+	PlaybackState struct {
+		Media     *Media  `json:"media"` // Nil until an episode is played
+		Paused    bool    `json:"paused"`
+		Position  float64 `json:"position"`  // In seconds, at UpdatedAt
+		UpdatedAt int64   `json:"updatedAt"` // Unix milliseconds, on the clock of the host
+	}
+
+	Member struct {
+		Id       string  `json:"id"`
+		Name     string  `json:"name"`
+		IsHost   bool    `json:"isHost"`
+		Address  string  `json:"-"`        // URL of the instance of the member, used when it becomes the host
+		Loaded   bool    `json:"loaded"`   // Whether the player of the member plays the episode of the party
+		Position float64 `json:"position"` // Last position reported by the member, in seconds
+		Paused   bool    `json:"paused"`
+		LastSeen int64   `json:"lastSeen"` // Unix milliseconds
+	}
+
+	Session struct {
+		Id          string         `json:"id"`
+		Role        Role           `json:"role"`        // Role of this instance
+		MemberId    string         `json:"memberId"`    // ID of this instance
+		HostAddress string         `json:"hostAddress"` // Address the peers are connected to, empty for the host
+		Members     []*Member      `json:"members"`
+		State       *PlaybackState `json:"state"`
+	}
+
+	// Player controls the playback of the party media on this instance.
+	Player interface {
+		// PlayMedia starts playing the episode from the start, the position of the party is applied once it is loaded.
+		PlayMedia(media *Media) error
+		Pause() error
+		Resume() error
+		Seek(seconds float64) error
+		// GetStatus returns the status of the local playback, nil if nothing is playing.
+		GetStatus() *PlayerStatus
+	}
+
+	PlayerStatus struct {
+		Position float64 // In seconds
+		Paused   bool
+	}
+
+	// Manager hosts or joins a watch party.
+	// The host keeps the playback state of the party. The other members, peers, connect to it over a websocket,
+	// send it the actions of their users and apply the state it broadcasts to their local player.
+	Manager struct {
+		logger         *zerolog.Logger
+		wsEventManager events.WSEventManagerInterface
+		player         Player
+		memberId       string
+		port           int // Port of the main server, given to the host so that it can transfer the session to this instance
+
+		mu          sync.Mutex
+		session     *Session
+		passcode    string
+		name        string
+		host        *host // Set when hosting
+		peer        *peer // Set when connected to a host
+		clockOffset int64 // Clock of the host minus the local clock, in milliseconds
+		syncer      *syncer
+	}
+
+	NewManagerOptions struct {
+		Logger         *zerolog.Logger
+		WSEventManager events.WSEventManagerInterface
+		Player         Player
+		Port           int
+	}
+
+	HostOptions struct {
+		Name     string // Name of the member, the hostname by default
+		Passcode string // Required from the members that join
+	}
+
+	JoinOptions struct {
+		Address  string // URL of the Seanime instance of the host, e.g. "http://192.168.1.10:43211"
+		Name     string
+		Passcode string
+	}
+)
+
+func NewManager(opts *NewManagerOptions) *Manager {
+	return &Manager{
+		logger:         opts.Logger,
+		wsEventManager: opts.WSEventManager,
+		player:         opts.Player,
+		memberId:       uuid.NewString(),
+		port:           opts.Port,
+	}
+}
+
+// key identifies the episode, the same episode played from another source is a different media.
+func (m *Media) key() string {
+	return fmt.Sprintf("%s/%d/%d/%s", m.Source, m.MediaId, m.EpisodeNumber, m.AniDBEpisode)
+}
+
+// positionAt returns the position of the party at a time of the clock of the host.
+func (s *PlaybackState) positionAt(hostTime int64) float64 {
+	if s.Paused {
+		return s.Position
+	}
+	return s.Position + float64(hostTime-s.UpdatedAt)/1000
+}
+
+// GetSession returns the current session, or nil if not in a watch party.
+func (m *Manager) GetSession() *Session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.copySession()
+}
+
+func (m *Manager) copySession() *Session {
+	if m.session == nil {
+		return nil
+	}
+	ret := *m.session
+	ret.Members = make([]*Member, 0, len(m.session.Members))
+	for _, member := range m.session.Members {
+		cp := *member
+		ret.Members = append(ret.Members, &cp)
+	}
+	return &ret
+}
+
+// Host starts a watch party hosted by this instance.
+func (m *Manager) Host(opts *HostOptions) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session != nil {
+		return nil, ErrInSession
+	}
+	if opts.Passcode == "" {
+		return nil, ErrNoPasscode
+	}
+
+	m.name = defaultName(opts.Name)
+	m.passcode = opts.Passcode
+	m.startHosting(uuid.NewString(), &PlaybackState{UpdatedAt: time.Now().UnixMilli()})
+
+	m.logger.Info().Str("sessionId", m.session.Id).Msg("watch party: Hosting")
+
+	return m.copySession(), nil
+}
+
+// Join connects to the watch party hosted by another instance.
+func (m *Manager) Join(opts *JoinOptions) (*Session, error) {
+	m.mu.Lock()
+	if m.session != nil {
+		m.mu.Unlock()
+		return nil, ErrInSession
+	}
+	m.name = defaultName(opts.Name)
+	m.passcode = opts.Passcode
+	m.mu.Unlock()
+
+	if err := m.connect(opts.Address); err != nil {
+		return nil, err
+	}
+
+	m.logger.Info().Str("address", opts.Address).Msg("watch party: Joined")
+
+	return m.GetSession(), nil
+}
+
+// Leave leaves the watch party, the party ends for everyone if this instance is the host.
+func (m *Manager) Leave() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session == nil {
+		return ErrNoSession
+	}
+
+	if m.host != nil {
+		m.host.broadcast(msgEnded, nil)
+		m.stopHosting()
+	}
+	if m.peer != nil {
+		m.peer.close()
+		m.peer = nil
+	}
+	m.reset()
+
+	m.logger.Info().Msg("watch party: Left")
+
+	return nil
+}
+
+// Shutdown leaves the watch party, it is called when the app exits.
+func (m *Manager) Shutdown() {
+	if m == nil {
+		return
+	}
+	_ = m.Leave()
+}
+
+func (m *Manager) reset() {
+	if m.syncer != nil {
+		m.syncer.stop()
+		m.syncer = nil
+	}
+	m.session = nil
+	m.clockOffset = 0
+	m.sendSessionToClient()
+}
+
+// PlayMedia starts an episode for the whole party.
+func (m *Manager) PlayMedia(media *Media) error {
+	if media == nil || media.MediaId == 0 {
+		return ErrBadRequest
+	}
+	switch media.Source {
+	case SourceLocalFile, SourceTorrentStream, SourceOnlineStream:
+	default:
+		return fmt.Errorf("unknown source %q", media.Source)
+	}
+	return m.control(&controlPayload{Action: actionPlay, Media: media})
+}
+
+// Pause pauses the playback of the party at the position of the local player.
+func (m *Manager) Pause() error {
+	return m.control(&controlPayload{Action: actionPause, Position: m.currentPosition()})
+}
+
+// Resume resumes the playback of the party from the position of the local player.
+func (m *Manager) Resume() error {
+	return m.control(&controlPayload{Action: actionResume, Position: m.currentPosition()})
+}
+
+// Seek seeks the playback of the party.
+func (m *Manager) Seek(position float64) error {
+	if position < 0 {
+		return ErrBadRequest
+	}
+	return m.control(&controlPayload{Action: actionSeek, Position: position})
+}
+
+// currentPosition returns the position of the local player, or the position of the party if nothing is playing.
+func (m *Manager) currentPosition() float64 {
+	if st := m.player.GetStatus(); st != nil {
+		return st.Position
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.session == nil {
+		return 0
+	}
+	return m.session.State.positionAt(m.hostNow())
+}
+
+// control applies an action if this instance is the host, and sends it to the host otherwise.
+func (m *Manager) control(ctrl *controlPayload) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case m.host != nil:
+		return m.applyControl(m.memberId, ctrl)
+	case m.peer != nil:
+		return m.peer.send(msgControl, ctrl)
+	default:
+		return ErrNoSession
+	}
+}
+
+// TransferHost makes another member the host of the party.
+// The members reconnect to the new host, including this instance.
+func (m *Manager) TransferHost(memberId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.host == nil {
+		return ErrNotHost
+	}
+
+	idx := slices.IndexFunc(m.session.Members, func(member *Member) bool { return member.Id == memberId })
+	if idx == -1 || memberId == m.memberId {
+		return fmt.Errorf("member not found")
+	}
+	target := m.session.Members[idx]
+
+	// The state is rebased so that the new host does not need the clock of this instance
+	state := *m.session.State
+	state.Position = state.positionAt(m.hostNow())
+	state.UpdatedAt = m.hostNow()
+
+	m.host.broadcast(msgTransfer, &transferPayload{
+		SessionId: m.session.Id,
+		HostId:    target.Id,
+		Address:   target.Address,
+		Passcode:  m.passcode,
+		State:     &state,
+		SentAt:    m.hostNow(),
+	})
+	m.stopHosting()
+	m.session.Role = RolePeer
+	m.session.HostAddress = target.Address
+
+	m.logger.Info().Str("memberId", target.Id).Str("address", target.Address).Msg("watch party: Transferring the host role")
+
+	go m.reconnect(target.Address)
+
+	return nil
+}
+
+// hostNow returns the current time on the clock of the host, in milliseconds.
+func (m *Manager) hostNow() int64 {
+	return time.Now().UnixMilli() + m.clockOffset
+}
+
+func (m *Manager) sendSessionToClient() {
+	if m.wsEventManager == nil {
+		return
+	}
+	m.wsEventManager.SendEvent(events.WatchPartyUpdated, m.copySession())
+}
+
+func (m *Manager) notifyClient(t string, msg string) {
+	if m.wsEventManager == nil {
+		return
+	}
+	m.wsEventManager.SendEvent(t, msg)
+}
+
+func defaultName(name string) string {
+	if name != "" {
+		return name
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "Seanime"
+}
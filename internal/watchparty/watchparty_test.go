@@ -0,0 +1,260 @@
+package watchparty
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"seanime/internal/events"
+	hibiketorrent "seanime/internal/extension/hibike/torrent"
+	"seanime/internal/util"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlayer simulates a media player, the position advances with the time when playing.
+type fakePlayer struct {
+	mu       sync.Mutex
+	media    *Media
+	position float64
+	paused   bool
+	at       time.Time
+}
+
+func (p *fakePlayer) PlayMedia(media *Media) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.media = media
+	p.position = 0
+	p.paused = false
+	p.at = time.Now()
+	return nil
+}
+
+func (p *fakePlayer) current() float64 {
+	if p.paused {
+		return p.position
+	}
+	return p.position + time.Since(p.at).Seconds()
+}
+
+func (p *fakePlayer) Pause() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.position = p.current()
+	p.paused = true
+	p.at = time.Now()
+	return nil
+}
+
+func (p *fakePlayer) Resume() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.position = p.current()
+	p.paused = false
+	p.at = time.Now()
+	return nil
+}
+
+func (p *fakePlayer) Seek(seconds float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.position = seconds
+	p.at = time.Now()
+	return nil
+}
+
+func (p *fakePlayer) GetStatus() *PlayerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.media == nil {
+		return nil
+	}
+	return &PlayerStatus{Position: p.current(), Paused: p.paused}
+}
+
+func (p *fakePlayer) getMedia() *Media {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.media
+}
+
+type testInstance struct {
+	manager *Manager
+	player  *fakePlayer
+	address string
+}
+
+// newTestInstance starts a manager behind an HTTP server, like the main server of an instance.
+func newTestInstance(t *testing.T) *testInstance {
+	logger := util.NewLogger()
+	player := &fakePlayer{}
+	m := NewManager(&NewManagerOptions{
+		Logger:         logger,
+		WSEventManager: events.NewMockWSEventManager(logger),
+		Player:         player,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(WebsocketPath, m)
+	srv := httptest.NewServer(mux)
+
+	u, _ := url.Parse(srv.URL)
+	m.port, _ = strconv.Atoi(u.Port())
+
+	t.Cleanup(func() {
+		m.Shutdown()
+		srv.Close()
+	})
+
+	return &testInstance{manager: m, player: player, address: srv.URL}
+}
+
+func setupTestTimings(t *testing.T) {
+	prevSyncInterval, prevGracePeriod := syncInterval, commandGracePeriod
+	syncInterval = 50 * time.Millisecond
+	commandGracePeriod = 200 * time.Millisecond
+	t.Cleanup(func() {
+		syncInterval, commandGracePeriod = prevSyncInterval, prevGracePeriod
+	})
+}
+
+func TestWatchParty(t *testing.T) {
+	setupTestTimings(t)
+
+	host := newTestInstance(t)
+	guest := newTestInstance(t)
+
+	_, err := host.manager.Host(&HostOptions{Name: "host"})
+	assert.ErrorIs(t, err, ErrNoPasscode)
+
+	_, err = host.manager.Host(&HostOptions{Name: "host", Passcode: "secret"})
+	require.NoError(t, err)
+
+	// Web pages cannot connect to the party
+	wsUrl, err := websocketUrl(host.address)
+	require.NoError(t, err)
+	_, _, err = websocket.DefaultDialer.Dial(wsUrl, http.Header{"Origin": []string{"http://example.com"}})
+	require.Error(t, err)
+
+	// Wrong passcode
+	_, err = guest.manager.Join(&JoinOptions{Address: host.address, Name: "guest", Passcode: "wrong"})
+	require.Error(t, err)
+	assert.Nil(t, guest.manager.GetSession())
+
+	session, err := guest.manager.Join(&JoinOptions{Address: host.address, Name: "guest", Passcode: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, RolePeer, session.Role)
+	assert.Len(t, session.Members, 2)
+	assert.Len(t, host.manager.GetSession().Members, 2)
+
+	_, err = guest.manager.Host(&HostOptions{})
+	assert.ErrorIs(t, err, ErrInSession)
+
+	// Nothing is playing yet
+	assert.ErrorIs(t, host.manager.Pause(), ErrNoMedia)
+
+	// The episode started by the host is played by the guest
+	require.NoError(t, host.manager.PlayMedia(&Media{Source: SourceLocalFile, MediaId: 21, EpisodeNumber: 3}))
+	require.Eventually(t, func() bool {
+		media := guest.player.getMedia()
+		return media != nil && media.MediaId == 21 && media.EpisodeNumber == 3
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// The torrent chosen by a peer is not used
+	require.NoError(t, guest.manager.PlayMedia(&Media{Source: SourceTorrentStream, MediaId: 21, EpisodeNumber: 4, Torrent: &hibiketorrent.AnimeTorrent{InfoHash: "abc"}}))
+	require.Eventually(t, func() bool {
+		media := host.player.getMedia()
+		return media != nil && media.EpisodeNumber == 4 && media.Torrent == nil
+	}, 2*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, host.manager.PlayMedia(&Media{Source: SourceLocalFile, MediaId: 21, EpisodeNumber: 3}))
+	require.Eventually(t, func() bool {
+		media := guest.player.getMedia()
+		return media != nil && media.EpisodeNumber == 3
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// The seek of the host is applied to the guest
+	require.NoError(t, host.manager.Seek(120))
+	require.Eventually(t, func() bool {
+		st := guest.player.GetStatus()
+		return st != nil && math.Abs(st.Position-120) < driftThreshold
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// A pause made in the player of the guest is applied to the host
+	time.Sleep(2 * commandGracePeriod)
+	require.NoError(t, guest.player.Pause())
+	require.Eventually(t, func() bool {
+		st := host.player.GetStatus()
+		return st != nil && st.Paused && host.manager.GetSession().State.Paused
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// Drift below the seek threshold is corrected
+	pausedAt := host.manager.GetSession().State.Position
+	time.Sleep(2 * commandGracePeriod)
+	require.NoError(t, guest.player.Seek(pausedAt+2))
+	require.Eventually(t, func() bool {
+		st := guest.player.GetStatus()
+		return st != nil && st.Paused && math.Abs(st.Position-pausedAt) < 0.1
+	}, 2*time.Second, 20*time.Millisecond)
+	assert.InDelta(t, pausedAt, host.manager.GetSession().State.Position, 0.1)
+
+	// Host transfer
+	require.NoError(t, host.manager.TransferHost(guest.manager.memberId))
+	require.Eventually(t, func() bool {
+		guestSession := guest.manager.GetSession()
+		hostSession := host.manager.GetSession()
+		return guestSession != nil && guestSession.Role == RoleHost && len(guestSession.Members) == 2 &&
+			hostSession != nil && hostSession.Role == RolePeer
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.True(t, guest.manager.GetSession().State.Paused)
+	assert.InDelta(t, pausedAt, guest.manager.GetSession().State.Position, 0.1)
+
+	// The previous host cannot control the party directly anymore, its actions go through the new host
+	assert.ErrorIs(t, host.manager.TransferHost(guest.manager.memberId), ErrNotHost)
+	time.Sleep(2 * commandGracePeriod)
+	require.NoError(t, host.manager.Resume())
+	require.Eventually(t, func() bool {
+		st := guest.player.GetStatus()
+		return st != nil && !st.Paused && !guest.manager.GetSession().State.Paused
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// The party ends for everyone when the host leaves
+	require.NoError(t, guest.manager.Leave())
+	require.Eventually(t, func() bool {
+		return host.manager.GetSession() == nil
+	}, 2*time.Second, 20*time.Millisecond)
+	assert.ErrorIs(t, host.manager.Leave(), ErrNoSession)
+}
+
+func TestWebsocketUrl(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+		err      bool
+	}{
+		{address: "http://192.168.1.10:43211", expected: "ws://192.168.1.10:43211" + WebsocketPath},
+		{address: "https://seanime.example.com/", expected: "wss://seanime.example.com" + WebsocketPath},
+		{address: "127.0.0.1:43211", expected: "ws://127.0.0.1:43211" + WebsocketPath},
+		{address: "ftp://127.0.0.1", err: true},
+		{address: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			ret, err := websocketUrl(tt.address)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ret)
+		})
+	}
+}
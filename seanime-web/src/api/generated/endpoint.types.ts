@@ -11,14 +11,22 @@ import type {
     AL_MediaStatus,
     Anime_AutoDownloaderRule,
     Anime_AutoDownloaderRuleEpisodeType,
+    Anime_AutoDownloaderRuleTemplate,
     Anime_AutoDownloaderRuleTitleComparisonType,
     Anime_LocalFileMetadata,
+    Anime_PlaylistItem,
+    Anime_SmartPlaylistRules,
     ChapterDownloader_DownloadID,
+    Continuity_GetWatchLogOptions,
+    Continuity_LogWatchProgressOptions,
     Continuity_UpdateWatchHistoryItemOptions,
+    Criteria,
     DebridClient_CancelStreamOptions,
     DebridClient_StreamPlaybackType,
     Debrid_TorrentItem,
     HibikeTorrent_AnimeTorrent,
+    Manga_AutoDownloaderRule,
+    Mediastream_ClientCapabilities,
     Mediastream_StreamType,
     Models_AnilistSettings,
     Models_DebridSettings,
@@ -31,6 +39,7 @@ import type {
     Models_Theme,
     Models_TorrentSettings,
     Models_TorrentstreamSettings,
+    Quality,
     Report_ClickLog,
     Report_ConsoleLog,
     Report_NetworkLog,
@@ -333,6 +342,8 @@ export type CreateAutoDownloaderRule_Variables = {
     episodeType: Anime_AutoDownloaderRuleEpisodeType
     episodeNumbers?: Array<number>
     destination: string
+    torrentCategory?: string
+    torrentTags?: Array<string>
 }
 
 /**
@@ -360,6 +371,42 @@ export type DeleteAutoDownloaderRule_Variables = {
     id: number
 }
 
+/**
+ * - Filepath: internal/handlers/auto_downloader.go
+ * - Filename: auto_downloader.go
+ * - Endpoint: /api/v1/auto-downloader/template
+ * @description
+ * Route creates a new rule template.
+ */
+export type CreateAutoDownloaderRuleTemplate_Variables = {
+    template?: Anime_AutoDownloaderRuleTemplate
+}
+
+/**
+ * - Filepath: internal/handlers/auto_downloader.go
+ * - Filename: auto_downloader.go
+ * - Endpoint: /api/v1/auto-downloader/template
+ * @description
+ * Route updates a rule template.
+ */
+export type UpdateAutoDownloaderRuleTemplate_Variables = {
+    template?: Anime_AutoDownloaderRuleTemplate
+}
+
+/**
+ * - Filepath: internal/handlers/auto_downloader.go
+ * - Filename: auto_downloader.go
+ * - Endpoint: /api/v1/auto-downloader/template/{id}
+ * @description
+ * Route deletes a rule template.
+ */
+export type DeleteAutoDownloaderRuleTemplate_Variables = {
+    /**
+     *  The DB id of the template
+     */
+    id: number
+}
+
 /**
  * - Filepath: internal/handlers/auto_downloader.go
  * - Filename: auto_downloader.go
@@ -400,6 +447,35 @@ export type GetContinuityWatchHistoryItem_Variables = {
     id: number
 }
 
+/**
+ * - Filepath: internal/handlers/continuity.go
+ * - Filename: continuity.go
+ * - Endpoint: /api/v1/continuity/item/{id}/episode/{episode}
+ * @description
+ * Route Returns the resume position of an episode.
+ */
+export type GetContinuityEpisodeWatchHistoryItem_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+    /**
+     *  Episode number
+     */
+    episode: number
+}
+
+/**
+ * - Filepath: internal/handlers/continuity.go
+ * - Filename: continuity.go
+ * - Endpoint: /api/v1/continuity/log
+ * @description
+ * Route Returns the viewing sessions.
+ */
+export type GetContinuityWatchLog_Variables = {
+    options: Continuity_GetWatchLogOptions
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // debrid
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1027,6 +1103,64 @@ export type RemoveMangaMapping_Variables = {
     mediaId: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// manga_auto_downloader
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/manga_auto_downloader.go
+ * - Filename: manga_auto_downloader.go
+ * - Endpoint: /api/v1/manga/auto-downloader/rule/manga/{id}
+ * @description
+ * Route returns the manga auto downloader rules with the given media id.
+ */
+export type GetMangaAutoDownloaderRulesByManga_Variables = {
+    /**
+     *  The AniList manga id of the rules
+     */
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/manga_auto_downloader.go
+ * - Filename: manga_auto_downloader.go
+ * - Endpoint: /api/v1/manga/auto-downloader/rule
+ * @description
+ * Route creates a new manga auto downloader rule.
+ */
+export type CreateMangaAutoDownloaderRule_Variables = {
+    enabled: boolean
+    mediaId: number
+    provider: string
+    scanlators: Array<string>
+    language: string
+}
+
+/**
+ * - Filepath: internal/handlers/manga_auto_downloader.go
+ * - Filename: manga_auto_downloader.go
+ * - Endpoint: /api/v1/manga/auto-downloader/rule
+ * @description
+ * Route updates a manga auto downloader rule.
+ */
+export type UpdateMangaAutoDownloaderRule_Variables = {
+    rule?: Manga_AutoDownloaderRule
+}
+
+/**
+ * - Filepath: internal/handlers/manga_auto_downloader.go
+ * - Filename: manga_auto_downloader.go
+ * - Endpoint: /api/v1/manga/auto-downloader/rule/{id}
+ * @description
+ * Route deletes a manga auto downloader rule.
+ */
+export type DeleteMangaAutoDownloaderRule_Variables = {
+    /**
+     *  The DB id of the rule
+     */
+    id: number
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // manga_download
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1103,6 +1237,12 @@ export type RequestMediastreamMediaContainer_Variables = {
     streamType: Mediastream_StreamType
     audioStreamIndex: number
     clientId: string
+    /**
+     *  What the client can decode, when set the stream type is chosen from it.
+     *  
+     *  What the client can decode, when set the stream type is chosen from it.
+     */
+    capabilities?: Mediastream_ClientCapabilities
 }
 
 /**
@@ -1118,6 +1258,110 @@ export type PreloadMediastreamMediaContainer_Variables = {
     audioStreamIndex: number
 }
 
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/stream-tracks/{id}
+ * @description
+ * Route returns the subtitle tracks and fonts extracted from a torrent or debrid stream.
+ */
+export type GetMediastreamStreamTracks_Variables = {
+    /**
+     *  The extraction ID
+     */
+    id: string
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/stream-tracks/{id}/load
+ * @description
+ * Route extracts the subtitle events around a position of a torrent or debrid stream.
+ */
+export type LoadMediastreamStreamTracks_Variables = {
+    /**
+     *  Playback position in seconds
+     *  
+     *  Playback position in seconds
+     */
+    position: number
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/trickplay/generate
+ * @description
+ * Route queues the generation of seek-preview thumbnails.
+ */
+export type MediastreamGenerateTrickplay_Variables = {
+    paths: Array<string>
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/skip-markers
+ * @description
+ * Route returns the intro and outro of a local file.
+ */
+export type GetSkipMarkers_Variables = {
+    path: string
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/skip-markers/{id}
+ * @description
+ * Route returns the intro and outro of the analyzed episodes of a media.
+ */
+export type GetMediaSkipMarkers_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/skip-markers/analyze
+ * @description
+ * Route detects the intro and outro of the episodes of a media.
+ */
+export type AnalyzeSkipMarkers_Variables = {
+    mediaId: number
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/optimizer/queue
+ * @description
+ * Route adds files to the pre-transcoding queue.
+ */
+export type MediastreamEnqueueOptimization_Variables = {
+    target: string
+    paths: Array<string>
+    mediaId: number
+    criteria?: Criteria
+    quality: Quality
+}
+
+/**
+ * - Filepath: internal/handlers/mediastream.go
+ * - Filename: mediastream.go
+ * - Endpoint: /api/v1/mediastream/optimizer/queue
+ * @description
+ * Route removes an item from the pre-transcoding queue.
+ */
+export type MediastreamRemoveOptimizationItem_Variables = {
+    id: number
+    finished: boolean
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // metadata
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1166,6 +1410,47 @@ export type RemoveFillerData_Variables = {
     mediaId: number
 }
 
+/**
+ * - Filepath: internal/handlers/metadata.go
+ * - Filename: metadata.go
+ * - Endpoint: /api/v1/metadata-provider/filler/preference/{id}
+ * @description
+ * Route returns whether filler episodes of a media are skipped.
+ */
+export type GetFillerPreference_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/metadata.go
+ * - Filename: metadata.go
+ * - Endpoint: /api/v1/metadata-provider/filler/preference
+ * @description
+ * Route overrides the "skip filler" library settings for a media.
+ */
+export type SaveFillerPreference_Variables = {
+    mediaId: number
+    skipFiller: boolean
+    markAsWatched: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/metadata.go
+ * - Filename: metadata.go
+ * - Endpoint: /api/v1/metadata-provider/filler/preference/{id}
+ * @description
+ * Route removes the "skip filler" preference of a media.
+ */
+export type DeleteFillerPreference_Variables = {
+    /**
+     *  AniList anime media ID
+     */
+    id: number
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // onlinestream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1311,6 +1596,8 @@ export type PlaybackStartManualTracking_Variables = {
 export type CreatePlaylist_Variables = {
     name: string
     paths: Array<string>
+    items: Array<Anime_PlaylistItem>
+    rules?: Anime_SmartPlaylistRules
 }
 
 /**
@@ -1324,6 +1611,8 @@ export type UpdatePlaylist_Variables = {
     dbId: number
     name: string
     paths: Array<string>
+    items: Array<Anime_PlaylistItem>
+    rules?: Anime_SmartPlaylistRules
 }
 
 /**
@@ -1337,6 +1626,17 @@ export type DeletePlaylist_Variables = {
     dbId: number
 }
 
+/**
+ * - Filepath: internal/handlers/playlist.go
+ * - Filename: playlist.go
+ * - Endpoint: /api/v1/playlist/smart/resolve
+ * @description
+ * Route returns the episodes matching the rules of a smart playlist.
+ */
+export type ResolveSmartPlaylist_Variables = {
+    rules?: Anime_SmartPlaylistRules
+}
+
 /**
  * - Filepath: internal/handlers/playlist.go
  * - Filename: playlist.go
@@ -1389,6 +1689,10 @@ export type SaveIssueReport_Variables = {
     isAnimeLibraryIssue: boolean
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// routes
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // scan
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1466,6 +1770,11 @@ export type SaveAutoDownloaderSettings_Variables = {
     enableEnhancedQueries: boolean
     enableSeasonCheck: boolean
     useDebrid: boolean
+    useAiringSchedule: boolean
+    burstInterval: number
+    burstWindow: number
+    syncRules: boolean
+    syncRulesTemplateId: number
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1624,6 +1933,12 @@ export type SearchTorrent_Variables = {
     absoluteOffset?: number
     resolution?: string
     bestRelease?: boolean
+    /**
+     *  Search all providers and merge the results
+     *  
+     *  Search all providers and merge the results
+     */
+    aggregate?: boolean
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1686,6 +2001,63 @@ export type TorrentstreamStartStream_Variables = {
     clientId: string
 }
 
+/**
+ * - Filepath: internal/handlers/torrentstream.go
+ * - Filename: torrentstream.go
+ * - Endpoint: /api/v1/torrentstream/resume
+ * @description
+ * Route resumes a persisted torrent stream.
+ */
+export type TorrentstreamResumeStream_Variables = {
+    sessionId: string
+    playbackType: Torrentstream_PlaybackType
+    clientId: string
+}
+
+/**
+ * - Filepath: internal/handlers/torrentstream.go
+ * - Filename: torrentstream.go
+ * - Endpoint: /api/v1/torrentstream/resumable
+ * @description
+ * Route dismisses a torrent stream that can be resumed.
+ */
+export type DismissTorrentstreamResumableSession_Variables = {
+    sessionId: string
+}
+
+/**
+ * - Filepath: internal/handlers/torrentstream.go
+ * - Filename: torrentstream.go
+ * - Endpoint: /api/v1/torrentstream/stop
+ * @description
+ * Route stop a torrent stream.
+ */
+export type TorrentstreamStopStream_Variables = {
+    all: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/torrentstream.go
+ * - Filename: torrentstream.go
+ * - Endpoint: /api/v1/torrentstream/drop
+ * @description
+ * Route drops a torrent stream.
+ */
+export type TorrentstreamDropTorrent_Variables = {
+    all: boolean
+}
+
+/**
+ * - Filepath: internal/handlers/torrentstream.go
+ * - Filename: torrentstream.go
+ * - Endpoint: /api/v1/torrentstream/cache
+ * @description
+ * Route removes a torrent from the stream cache.
+ */
+export type RemoveTorrentstreamCacheEntry_Variables = {
+    infoHash: string
+}
+
 /**
  * - Filepath: internal/handlers/torrentstream.go
  * - Filename: torrentstream.go
@@ -1697,6 +2069,159 @@ export type GetTorrentstreamBatchHistory_Variables = {
     mediaId: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// track_preferences
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/track_preferences.go
+ * - Filename: track_preferences.go
+ * - Endpoint: /api/v1/track-preferences/{id}
+ * @description
+ * Route returns the track preferences of a media.
+ */
+export type GetTrackPreferences_Variables = {
+    /**
+     *  AniList anime media ID, 0 for the defaults
+     */
+    id: number
+}
+
+/**
+ * - Filepath: internal/handlers/track_preferences.go
+ * - Filename: track_preferences.go
+ * - Endpoint: /api/v1/track-preferences
+ * @description
+ * Route saves the track preferences of a media.
+ */
+export type SaveTrackPreferences_Variables = {
+    mediaId: number
+    audioLanguage: string
+    subtitleLanguage: string
+    subtitleTitlePattern: string
+    subtitleDelay: number
+}
+
+/**
+ * - Filepath: internal/handlers/track_preferences.go
+ * - Filename: track_preferences.go
+ * - Endpoint: /api/v1/track-preferences/{id}
+ * @description
+ * Route deletes the track preferences of a media.
+ */
+export type DeleteTrackPreferences_Variables = {
+    /**
+     *  AniList anime media ID, 0 for the defaults
+     */
+    id: number
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// viewing_stats
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/viewing_stats.go
+ * - Filename: viewing_stats.go
+ * - Endpoint: /api/v1/viewing-stats
+ * @description
+ * Route returns the local viewing statistics.
+ */
+export type GetViewingStats_Variables = {
+    from?: string
+    to?: string
+}
+
+/**
+ * - Filepath: internal/handlers/viewing_stats.go
+ * - Filename: viewing_stats.go
+ * - Endpoint: /api/v1/viewing-stats/recap/{year}
+ * @description
+ * Route returns the viewing recap of a year.
+ */
+export type GetViewingStatsYearlyRecap_Variables = {
+    /**
+     *  The year of the recap
+     */
+    year: number
+}
+
+/**
+ * - Filepath: internal/handlers/viewing_stats.go
+ * - Filename: viewing_stats.go
+ * - Endpoint: /api/v1/viewing-stats/recap/{year}/export
+ * @description
+ * Route exports the viewing recap of a year as a JSON file.
+ */
+export type ExportViewingStatsYearlyRecap_Variables = {
+    /**
+     *  The year of the recap
+     */
+    year: number
+}
+
+/**
+ * - Filepath: internal/handlers/viewing_stats.go
+ * - Filename: viewing_stats.go
+ * - Endpoint: /api/v1/viewing-stats/progress
+ * @description
+ * Route records the progress of an episode played in the client.
+ */
+export type RecordViewingProgress_Variables = {
+    options: Continuity_LogWatchProgressOptions
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// watch_party
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/handlers/watch_party.go
+ * - Filename: watch_party.go
+ * - Endpoint: /api/v1/watch-party/host
+ * @description
+ * Route starts a watch party hosted by this instance.
+ */
+export type HostWatchParty_Variables = {
+    name: string
+    passcode: string
+}
+
+/**
+ * - Filepath: internal/handlers/watch_party.go
+ * - Filename: watch_party.go
+ * - Endpoint: /api/v1/watch-party/join
+ * @description
+ * Route joins the watch party hosted by another instance.
+ */
+export type JoinWatchParty_Variables = {
+    address: string
+    name: string
+    passcode: string
+}
+
+/**
+ * - Filepath: internal/handlers/watch_party.go
+ * - Filename: watch_party.go
+ * - Endpoint: /api/v1/watch-party/seek
+ * @description
+ * Route seeks the playback of the watch party.
+ */
+export type WatchPartySeek_Variables = {
+    position: number
+}
+
+/**
+ * - Filepath: internal/handlers/watch_party.go
+ * - Filename: watch_party.go
+ * - Endpoint: /api/v1/watch-party/transfer
+ * @description
+ * Route makes another member the host of the watch party.
+ */
+export type TransferWatchPartyHost_Variables = {
+    memberId: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // websocket
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
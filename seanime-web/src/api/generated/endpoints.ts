@@ -314,6 +314,7 @@ export const API_ENDPOINTS = {
          *  @description
          *  Route creates a new rule.
          *  The body should contain the same fields as entities.AutoDownloaderRule.
+         *  The destination can contain placeholders (e.g. "{title}", "{year}") that are resolved when a torrent is downloaded.
          *  It returns the created rule.
          */
         CreateAutoDownloaderRule: {
@@ -342,6 +343,58 @@ export const API_ENDPOINTS = {
             methods: ["DELETE"],
             endpoint: "/api/v1/auto-downloader/rule/{id}",
         },
+        /**
+         *  @description
+         *  Route returns the computed next check time of each enabled rule.
+         *  When the airing schedule is used, rules whose episode aired recently are checked more frequently.
+         */
+        GetAutoDownloaderRuleSchedules: {
+            key: "AUTO-DOWNLOADER-get-auto-downloader-rule-schedules",
+            methods: ["GET"],
+            endpoint: "/api/v1/auto-downloader/rules/schedule",
+        },
+        /**
+         *  @description
+         *  Route returns all rule templates.
+         *  Templates are used to create rules automatically when syncing with the collection.
+         */
+        GetAutoDownloaderRuleTemplates: {
+            key: "AUTO-DOWNLOADER-get-auto-downloader-rule-templates",
+            methods: ["GET"],
+            endpoint: "/api/v1/auto-downloader/templates",
+        },
+        /**
+         *  @description
+         *  Route creates a new rule template.
+         *  The destination can contain placeholders (e.g. "{title}", "{year}").
+         *  It returns the created template.
+         */
+        CreateAutoDownloaderRuleTemplate: {
+            key: "AUTO-DOWNLOADER-create-auto-downloader-rule-template",
+            methods: ["POST"],
+            endpoint: "/api/v1/auto-downloader/template",
+        },
+        /**
+         *  @description
+         *  Route updates a rule template.
+         *  Existing rules created from the template are not updated.
+         *  It returns the updated template.
+         */
+        UpdateAutoDownloaderRuleTemplate: {
+            key: "AUTO-DOWNLOADER-update-auto-downloader-rule-template",
+            methods: ["PATCH"],
+            endpoint: "/api/v1/auto-downloader/template",
+        },
+        /**
+         *  @description
+         *  Route deletes a rule template.
+         *  Rules created from the template are not deleted.
+         */
+        DeleteAutoDownloaderRuleTemplate: {
+            key: "AUTO-DOWNLOADER-delete-auto-downloader-rule-template",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/auto-downloader/template/{id}",
+        },
         /**
          *  @description
          *  Route returns all queued items.
@@ -397,6 +450,47 @@ export const API_ENDPOINTS = {
             methods: ["GET"],
             endpoint: "/api/v1/continuity/history",
         },
+        /**
+         *  @description
+         *  Route Returns the resume position of an episode.
+         *  Nothing is returned if the episode was not stopped midway.
+         */
+        GetContinuityEpisodeWatchHistoryItem: {
+            key: "CONTINUITY-get-continuity-episode-watch-history-item",
+            methods: ["GET"],
+            endpoint: "/api/v1/continuity/item/{id}/episode/{episode}",
+        },
+        /**
+         *  @description
+         *  Route Returns the recently watched episodes.
+         *  The episodes are sorted by the time they were last watched, most recent first.
+         */
+        GetContinuityRecentlyWatched: {
+            key: "CONTINUITY-get-continuity-recently-watched",
+            methods: ["GET"],
+            endpoint: "/api/v1/continuity/recent",
+        },
+        /**
+         *  @description
+         *  Route Returns the episodes that can be resumed.
+         *  The episodes are sorted by the time they were last watched, most recent first.
+         */
+        GetContinuityResumeList: {
+            key: "CONTINUITY-get-continuity-resume-list",
+            methods: ["GET"],
+            endpoint: "/api/v1/continuity/resume",
+        },
+        /**
+         *  @description
+         *  Route Returns the viewing sessions.
+         *  A session is created every time an episode is watched, the sessions are sorted from most recent to oldest.
+         *  The sessions can be filtered by media and by time range.
+         */
+        GetContinuityWatchLog: {
+            key: "CONTINUITY-get-continuity-watch-log",
+            methods: ["POST"],
+            endpoint: "/api/v1/continuity/log",
+        },
     },
     DEBRID: {
         /**
@@ -978,6 +1072,65 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/manga/remove-mapping",
         },
     },
+    MANGA_AUTO_DOWNLOADER: {
+        /**
+         *  @description
+         *  Route tells the manga auto downloader to check for new chapters if enabled.
+         *  It does nothing if the manga auto downloader is disabled.
+         */
+        RunMangaAutoDownloader: {
+            key: "MANGA-AUTO-DOWNLOADER-run-manga-auto-downloader",
+            methods: ["POST"],
+            endpoint: "/api/v1/manga/auto-downloader/run",
+        },
+        /**
+         *  @description
+         *  Route returns all manga auto downloader rules.
+         *  It returns an empty slice if there are no rules.
+         */
+        GetMangaAutoDownloaderRules: {
+            key: "MANGA-AUTO-DOWNLOADER-get-manga-auto-downloader-rules",
+            methods: ["GET"],
+            endpoint: "/api/v1/manga/auto-downloader/rules",
+        },
+        GetMangaAutoDownloaderRulesByManga: {
+            key: "MANGA-AUTO-DOWNLOADER-get-manga-auto-downloader-rules-by-manga",
+            methods: ["GET"],
+            endpoint: "/api/v1/manga/auto-downloader/rule/manga/{id}",
+        },
+        /**
+         *  @description
+         *  Route creates a new manga auto downloader rule.
+         *  The body should contain the same fields as manga.AutoDownloaderRule.
+         *  It returns the created rule.
+         */
+        CreateMangaAutoDownloaderRule: {
+            key: "MANGA-AUTO-DOWNLOADER-create-manga-auto-downloader-rule",
+            methods: ["POST"],
+            endpoint: "/api/v1/manga/auto-downloader/rule",
+        },
+        /**
+         *  @description
+         *  Route updates a manga auto downloader rule.
+         *  The body should contain the same fields as manga.AutoDownloaderRule.
+         *  It returns the updated rule.
+         */
+        UpdateMangaAutoDownloaderRule: {
+            key: "MANGA-AUTO-DOWNLOADER-update-manga-auto-downloader-rule",
+            methods: ["PATCH"],
+            endpoint: "/api/v1/manga/auto-downloader/rule",
+        },
+        /**
+         *  @description
+         *  Route deletes a manga auto downloader rule.
+         *  It returns 'true' if the rule was deleted.
+         */
+        DeleteMangaAutoDownloaderRule: {
+            key: "MANGA-AUTO-DOWNLOADER-delete-manga-auto-downloader-rule",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/manga/auto-downloader/rule/{id}",
+        },
+    },
     MANGA_DOWNLOAD: {
         DownloadMangaChapters: {
             key: "MANGA-DOWNLOAD-download-manga-chapters",
@@ -1078,11 +1231,27 @@ export const API_ENDPOINTS = {
         },
     },
     MEDIAPLAYER: {
+        /**
+         *  @description
+         *  Route launches the default media player (vlc or mpc-hc).
+         *  For Kodi and DLNA renderers, this checks that they can be reached.
+         */
         StartDefaultMediaPlayer: {
             key: "MEDIAPLAYER-start-default-media-player",
             methods: ["POST"],
             endpoint: "/api/v1/media-player/start",
         },
+        /**
+         *  @description
+         *  Route searches the local network for DLNA renderers.
+         *  This returns the renderers that can be controlled, e.g. smart TVs.
+         *  The location of the selected renderer is saved in the media player settings.
+         */
+        DiscoverDlnaRenderers: {
+            key: "MEDIAPLAYER-discover-dlna-renderers",
+            methods: ["GET"],
+            endpoint: "/api/v1/media-player/dlna/renderers",
+        },
     },
     MEDIASTREAM: {
         /**
@@ -1125,6 +1294,101 @@ export const API_ENDPOINTS = {
             methods: ["POST"],
             endpoint: "/api/v1/mediastream/preload",
         },
+        /**
+         *  @description
+         *  Route returns the subtitle tracks and fonts extracted from a torrent or debrid stream.
+         *  The ID is the extraction ID of the torrent stream session or the debrid stream.
+         *  The subtitle files are served by /mediastream/stream-subs/{id}/{link} and the fonts by /mediastream/stream-att/{id}/{font}.
+         *  Only the subtitle events of the time ranges in 'ranges' are in the files, use /mediastream/stream-tracks/{id}/load to extract the events around the playback position.
+         */
+        GetMediastreamStreamTracks: {
+            key: "MEDIASTREAM-get-mediastream-stream-tracks",
+            methods: ["GET"],
+            endpoint: "/api/v1/mediastream/stream-tracks/{id}",
+        },
+        /**
+         *  @description
+         *  Route extracts the subtitle events around a position of a torrent or debrid stream.
+         *  Events are extracted by chunks of a few minutes, only the parts of the file containing the chunks are read.
+         *  The subtitle files are updated before the request returns.
+         */
+        LoadMediastreamStreamTracks: {
+            key: "MEDIASTREAM-load-mediastream-stream-tracks",
+            methods: ["POST"],
+            endpoint: "/api/v1/mediastream/stream-tracks/{id}/load",
+        },
+        /**
+         *  @description
+         *  Route queues the generation of seek-preview thumbnails.
+         *  If paths are provided, the files are processed one at a time in the background.
+         *  If no paths are provided, all local files of the library are added to the pre-transcoding queue,
+         *  which processes them during its time window. An error is returned if the queue is disabled.
+         *  Files that already have thumbnails are skipped.
+         */
+        MediastreamGenerateTrickplay: {
+            key: "MEDIASTREAM-mediastream-generate-trickplay",
+            methods: ["POST"],
+            endpoint: "/api/v1/mediastream/trickplay/generate",
+        },
+        /**
+         *  @description
+         *  Route returns the intro and outro of a local file.
+         *  Returns null if the file has not been analyzed.
+         */
+        GetSkipMarkers: {
+            key: "MEDIASTREAM-get-skip-markers",
+            methods: ["POST"],
+            endpoint: "/api/v1/mediastream/skip-markers",
+        },
+        GetMediaSkipMarkers: {
+            key: "MEDIASTREAM-get-media-skip-markers",
+            methods: ["GET"],
+            endpoint: "/api/v1/mediastream/skip-markers/{id}",
+        },
+        /**
+         *  @description
+         *  Route detects the intro and outro of the episodes of a media.
+         *  The local files of the media are analyzed in the background, previous markers are replaced.
+         *  The events.SkipMarkersAnalyzed event is sent when the analysis is done.
+         */
+        AnalyzeSkipMarkers: {
+            key: "MEDIASTREAM-analyze-skip-markers",
+            methods: ["POST"],
+            endpoint: "/api/v1/mediastream/skip-markers/analyze",
+        },
+        /**
+         *  @description
+         *  Route returns the pre-transcoding queue.
+         *  The progress of the running item is sent by the events.MediastreamOptimizationProgress event.
+         */
+        GetMediastreamOptimizationQueue: {
+            key: "MEDIASTREAM-get-mediastream-optimization-queue",
+            methods: ["GET"],
+            endpoint: "/api/v1/mediastream/optimizer/queue",
+        },
+        /**
+         *  @description
+         *  Route adds files to the pre-transcoding queue.
+         *  'target' is "files" (the given paths), "series" (the local files of the media) or "library" (all local files).
+         *  If criteria is set, the files are analyzed in the background and only the ones matching the criteria are added.
+         *  Files that are already optimized are skipped. If quality is empty, the quality from the settings is used.
+         *  This only adds the files to the queue, they are optimized one at a time when the queue is enabled and during its time window.
+         */
+        MediastreamEnqueueOptimization: {
+            key: "MEDIASTREAM-mediastream-enqueue-optimization",
+            methods: ["POST"],
+            endpoint: "/api/v1/mediastream/optimizer/queue",
+        },
+        /**
+         *  @description
+         *  Route removes an item from the pre-transcoding queue.
+         *  The item is stopped if it is running. If 'finished' is true, all the items that are done or failed are removed instead.
+         */
+        MediastreamRemoveOptimizationItem: {
+            key: "MEDIASTREAM-mediastream-remove-optimization-item",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/mediastream/optimizer/queue",
+        },
         /**
          *  @description
          *  Route shuts down the transcode stream
@@ -1179,6 +1443,37 @@ export const API_ENDPOINTS = {
             methods: ["DELETE"],
             endpoint: "/api/v1/metadata-provider/filler",
         },
+        /**
+         *  @description
+         *  Route returns whether filler episodes of a media are skipped.
+         *  This returns the preference of the media, or the library settings if the media has none.
+         */
+        GetFillerPreference: {
+            key: "METADATA-get-filler-preference",
+            methods: ["GET"],
+            endpoint: "/api/v1/metadata-provider/filler/preference/{id}",
+        },
+        /**
+         *  @description
+         *  Route overrides the "skip filler" library settings for a media.
+         *  Skipped fillers are not played by autoplay, playlists, random play and the next episode of streams.
+         *  If markAsWatched is true, the progress is updated on the platform when fillers are skipped.
+         */
+        SaveFillerPreference: {
+            key: "METADATA-save-filler-preference",
+            methods: ["POST"],
+            endpoint: "/api/v1/metadata-provider/filler/preference",
+        },
+        /**
+         *  @description
+         *  Route removes the "skip filler" preference of a media.
+         *  The library settings apply to the media again.
+         */
+        DeleteFillerPreference: {
+            key: "METADATA-delete-filler-preference",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/metadata-provider/filler/preference/{id}",
+        },
     },
     ONLINESTREAM: {
         /**
@@ -1372,6 +1667,8 @@ export const API_ENDPOINTS = {
          *  @description
          *  Route creates a new playlist.
          *  This will create a new playlist with the given name and local file paths.
+         *  If rules are given, a smart playlist is created and the paths are ignored. The episodes of smart playlists are resolved when they are played.
+         *  If items are given, the playlist can mix episodes from the library and streamed episodes, the paths are ignored.
          *  The response is ignored, the client should re-fetch the playlists after this.
          */
         CreatePlaylist: {
@@ -1388,6 +1685,8 @@ export const API_ENDPOINTS = {
          *  @description
          *  Route updates a playlist.
          *  The response is ignored, the client should re-fetch the playlists after this.
+         *  If rules are given, the playlist becomes a smart playlist and the paths are ignored.
+         *  If items are given, the playlist can mix episodes from the library and streamed episodes, the paths are ignored.
          */
         UpdatePlaylist: {
             key: "PLAYLIST-update-playlist",
@@ -1399,6 +1698,17 @@ export const API_ENDPOINTS = {
             methods: ["DELETE"],
             endpoint: "/api/v1/playlist",
         },
+        /**
+         *  @description
+         *  Route returns the episodes matching the rules of a smart playlist.
+         *  This is used to preview a smart playlist before saving it.
+         *  The episodes are resolved against the current AniList collection and local files.
+         */
+        ResolveSmartPlaylist: {
+            key: "PLAYLIST-resolve-smart-playlist",
+            methods: ["POST"],
+            endpoint: "/api/v1/playlist/smart/resolve",
+        },
         GetPlaylistEpisodes: {
             key: "PLAYLIST-get-playlist-episodes",
             methods: ["GET"],
@@ -1674,6 +1984,8 @@ export const API_ENDPOINTS = {
          *  Route searches torrents and returns a list of torrents and their previews.
          *  This will search for torrents and return a list of torrents with previews.
          *  If smart search is enabled, it will filter the torrents based on search parameters.
+         *  If aggregate is true, all providers are searched and the results are merged by info hash.
+         *  Providers that fail are listed in 'providerErrors' instead of failing the whole search.
          */
         SearchTorrent: {
             key: "TORRENT-SEARCH-search-torrent",
@@ -1733,11 +2045,42 @@ export const API_ENDPOINTS = {
             methods: ["POST"],
             endpoint: "/api/v1/torrentstream/start",
         },
+        /**
+         *  @description
+         *  Route returns the torrent streams that can be resumed.
+         *  Active streams are persisted so that they can be resumed after a restart.
+         */
+        GetTorrentstreamResumableSessions: {
+            key: "TORRENTSTREAM-get-torrentstream-resumable-sessions",
+            methods: ["GET"],
+            endpoint: "/api/v1/torrentstream/resumable",
+        },
+        /**
+         *  @description
+         *  Route resumes a persisted torrent stream.
+         *  The torrent is added again using the data already on disk, only the missing pieces are downloaded.
+         */
+        TorrentstreamResumeStream: {
+            key: "TORRENTSTREAM-torrentstream-resume-stream",
+            methods: ["POST"],
+            endpoint: "/api/v1/torrentstream/resume",
+        },
+        /**
+         *  @description
+         *  Route dismisses a torrent stream that can be resumed.
+         *  This deletes the persisted stream and the downloaded files of its torrent.
+         */
+        DismissTorrentstreamResumableSession: {
+            key: "TORRENTSTREAM-dismiss-torrentstream-resumable-session",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/torrentstream/resumable",
+        },
         /**
          *  @description
          *  Route stop a torrent stream.
          *  This stops the entire streaming process and drops the torrent if it's below a threshold.
          *  This is made to be used while the stream is running.
+         *  Only the stream of the client making the request is stopped, unless 'all' is true.
          */
         TorrentstreamStopStream: {
             key: "TORRENTSTREAM-torrentstream-stop-stream",
@@ -1749,12 +2092,44 @@ export const API_ENDPOINTS = {
          *  Route drops a torrent stream.
          *  This stops the entire streaming process and drops the torrent completely.
          *  This is made to be used to force drop a torrent.
+         *  Only the torrent of the client making the request is dropped, unless 'all' is true, in which case all streams are stopped and all torrents are dropped.
          */
         TorrentstreamDropTorrent: {
             key: "TORRENTSTREAM-torrentstream-drop-torrent",
             methods: ["POST"],
             endpoint: "/api/v1/torrentstream/drop",
         },
+        /**
+         *  @description
+         *  Route returns the active torrent streams.
+         *  Each client can stream one torrent at a time, up to the maximum number of concurrent streams.
+         */
+        GetTorrentstreamSessions: {
+            key: "TORRENTSTREAM-get-torrentstream-sessions",
+            methods: ["GET"],
+            endpoint: "/api/v1/torrentstream/sessions",
+        },
+        /**
+         *  @description
+         *  Route returns the torrents kept after their stream stopped.
+         *  The torrents are seeded until the seeding goal is reached and evicted according to the retention policy.
+         */
+        GetTorrentstreamCache: {
+            key: "TORRENTSTREAM-get-torrentstream-cache",
+            methods: ["GET"],
+            endpoint: "/api/v1/torrentstream/cache",
+        },
+        /**
+         *  @description
+         *  Route removes a torrent from the stream cache.
+         *  This drops the torrent and deletes its files.
+         *  If no info hash is provided, the whole cache is cleared.
+         */
+        RemoveTorrentstreamCacheEntry: {
+            key: "TORRENTSTREAM-remove-torrentstream-cache-entry",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/torrentstream/cache",
+        },
         /**
          *  @description
          *  Route returns the most recent batch selected.
@@ -1766,5 +2141,165 @@ export const API_ENDPOINTS = {
             endpoint: "/api/v1/torrentstream/batch-history",
         },
     },
+    TRACK_PREFERENCES: {
+        /**
+         *  @description
+         *  Route returns the default track preferences and those of all the media.
+         *  The defaults have a mediaId of 0.
+         */
+        GetAllTrackPreferences: {
+            key: "TRACK-PREFERENCES-get-all-track-preferences",
+            methods: ["GET"],
+            endpoint: "/api/v1/track-preferences",
+        },
+        /**
+         *  @description
+         *  Route returns the track preferences of a media.
+         *  Use 0 as ID to get the defaults. Returns null if no preferences are set.
+         */
+        GetTrackPreferences: {
+            key: "TRACK-PREFERENCES-get-track-preferences",
+            methods: ["GET"],
+            endpoint: "/api/v1/track-preferences/{id}",
+        },
+        /**
+         *  @description
+         *  Route saves the track preferences of a media.
+         *  Use 0 as mediaId to save the defaults. Languages are comma-separated codes by priority, e.g. "ja,en".
+         *  The subtitle title pattern is a case-insensitive regular expression matched against the names of the tracks.
+         */
+        SaveTrackPreferences: {
+            key: "TRACK-PREFERENCES-save-track-preferences",
+            methods: ["POST"],
+            endpoint: "/api/v1/track-preferences",
+        },
+        /**
+         *  @description
+         *  Route deletes the track preferences of a media.
+         *  Use 0 as ID to delete the defaults.
+         */
+        DeleteTrackPreferences: {
+            key: "TRACK-PREFERENCES-delete-track-preferences",
+            methods: ["DELETE"],
+            endpoint: "/api/v1/track-preferences/{id}",
+        },
+    },
+    VIEWING_STATS: {
+        /**
+         *  @description
+         *  Route returns the local viewing statistics.
+         *  The statistics are computed from the watch log recorded by Seanime, unlike the AniList statistics.
+         *  Only the sessions that started in the time range are counted, omit the times to get the statistics of all time.
+         */
+        GetViewingStats: {
+            key: "VIEWING-STATS-get-viewing-stats",
+            methods: ["POST"],
+            endpoint: "/api/v1/viewing-stats",
+        },
+        GetViewingStatsYearlyRecap: {
+            key: "VIEWING-STATS-get-viewing-stats-yearly-recap",
+            methods: ["GET"],
+            endpoint: "/api/v1/viewing-stats/recap/{year}",
+        },
+        ExportViewingStatsYearlyRecap: {
+            key: "VIEWING-STATS-export-viewing-stats-yearly-recap",
+            methods: ["GET"],
+            endpoint: "/api/v1/viewing-stats/recap/{year}/export",
+        },
+        /**
+         *  @description
+         *  Route records the progress of an episode played in the client.
+         *  This is used by the built-in players (online streaming and media streaming), the other players are tracked by the server.
+         *  The time played is added to the watch log entry of the episode, it should be called periodically while the episode is playing.
+         */
+        RecordViewingProgress: {
+            key: "VIEWING-STATS-record-viewing-progress",
+            methods: ["POST"],
+            endpoint: "/api/v1/viewing-stats/progress",
+        },
+    },
+    WATCH_PARTY: {
+        /**
+         *  @description
+         *  Route returns the current watch party.
+         *  Returns null if this instance is not in a watch party.
+         */
+        GetWatchParty: {
+            key: "WATCH-PARTY-get-watch-party",
+            methods: ["GET"],
+            endpoint: "/api/v1/watch-party",
+        },
+        /**
+         *  @description
+         *  Route starts a watch party hosted by this instance.
+         *  Other instances join it with the address of this instance and the passcode, which is required.
+         */
+        HostWatchParty: {
+            key: "WATCH-PARTY-host-watch-party",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/host",
+        },
+        /**
+         *  @description
+         *  Route joins the watch party hosted by another instance.
+         *  The address is the URL of the Seanime instance of the host, e.g. "http://192.168.1.10:43211".
+         */
+        JoinWatchParty: {
+            key: "WATCH-PARTY-join-watch-party",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/join",
+        },
+        /**
+         *  @description
+         *  Route leaves the current watch party.
+         *  The watch party ends for everyone if this instance is the host.
+         */
+        LeaveWatchParty: {
+            key: "WATCH-PARTY-leave-watch-party",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/leave",
+        },
+        /**
+         *  @description
+         *  Route plays an episode for the whole watch party.
+         *  Local files are matched by media ID and episode number on each instance.
+         *  Torrent streams use the selected torrent if this instance is the host, a torrent is selected automatically otherwise.
+         */
+        WatchPartyPlay: {
+            key: "WATCH-PARTY-watch-party-play",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/play",
+        },
+        WatchPartyPause: {
+            key: "WATCH-PARTY-watch-party-pause",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/pause",
+        },
+        WatchPartyResume: {
+            key: "WATCH-PARTY-watch-party-resume",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/resume",
+        },
+        /**
+         *  @description
+         *  Route seeks the playback of the watch party.
+         *  The position is in seconds.
+         */
+        WatchPartySeek: {
+            key: "WATCH-PARTY-watch-party-seek",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/seek",
+        },
+        /**
+         *  @description
+         *  Route makes another member the host of the watch party.
+         *  Only the host can transfer its role. The members reconnect to the instance of the new host.
+         */
+        TransferWatchPartyHost: {
+            key: "WATCH-PARTY-transfer-watch-party-host",
+            methods: ["POST"],
+            endpoint: "/api/v1/watch-party/transfer",
+        },
+    },
 } satisfies ApiEndpoints
 
@@ -366,6 +366,57 @@
 //     })
 // }
 
+// export function useGetAutoDownloaderRuleSchedules() {
+//     return useServerQuery<Array<AutoDownloader_RuleSchedule>>({
+//         endpoint: API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderRuleSchedules.endpoint,
+//         method: API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderRuleSchedules.methods[0],
+//         queryKey: [API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderRuleSchedules.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetAutoDownloaderRuleTemplates() {
+//     return useServerQuery<Array<Anime_AutoDownloaderRuleTemplate>>({
+//         endpoint: API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderRuleTemplates.endpoint,
+//         method: API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderRuleTemplates.methods[0],
+//         queryKey: [API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderRuleTemplates.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useCreateAutoDownloaderRuleTemplate() {
+//     return useServerMutation<Anime_AutoDownloaderRuleTemplate, CreateAutoDownloaderRuleTemplate_Variables>({
+//         endpoint: API_ENDPOINTS.AUTO_DOWNLOADER.CreateAutoDownloaderRuleTemplate.endpoint,
+//         method: API_ENDPOINTS.AUTO_DOWNLOADER.CreateAutoDownloaderRuleTemplate.methods[0],
+//         mutationKey: [API_ENDPOINTS.AUTO_DOWNLOADER.CreateAutoDownloaderRuleTemplate.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useUpdateAutoDownloaderRuleTemplate() {
+//     return useServerMutation<Anime_AutoDownloaderRuleTemplate, UpdateAutoDownloaderRuleTemplate_Variables>({
+//         endpoint: API_ENDPOINTS.AUTO_DOWNLOADER.UpdateAutoDownloaderRuleTemplate.endpoint,
+//         method: API_ENDPOINTS.AUTO_DOWNLOADER.UpdateAutoDownloaderRuleTemplate.methods[0],
+//         mutationKey: [API_ENDPOINTS.AUTO_DOWNLOADER.UpdateAutoDownloaderRuleTemplate.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteAutoDownloaderRuleTemplate(id: number) {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.AUTO_DOWNLOADER.DeleteAutoDownloaderRuleTemplate.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.AUTO_DOWNLOADER.DeleteAutoDownloaderRuleTemplate.methods[0],
+//         mutationKey: [API_ENDPOINTS.AUTO_DOWNLOADER.DeleteAutoDownloaderRuleTemplate.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useGetAutoDownloaderItems() {
 //     return useServerQuery<Array<Models_AutoDownloaderItem>>({
 //         endpoint: API_ENDPOINTS.AUTO_DOWNLOADER.GetAutoDownloaderItems.endpoint,
@@ -419,6 +470,44 @@
 //     })
 // }
 
+// export function useGetContinuityEpisodeWatchHistoryItem(id: number, episode: number) {
+//     return useServerQuery<Continuity_EpisodeWatchHistoryItemResponse>({
+//         endpoint: API_ENDPOINTS.CONTINUITY.GetContinuityEpisodeWatchHistoryItem.endpoint.replace("{id}", String(id)).replace("{episode}", String(episode)),
+//         method: API_ENDPOINTS.CONTINUITY.GetContinuityEpisodeWatchHistoryItem.methods[0],
+//         queryKey: [API_ENDPOINTS.CONTINUITY.GetContinuityEpisodeWatchHistoryItem.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetContinuityRecentlyWatched() {
+//     return useServerQuery<Array<Continuity_EpisodeWatchHistoryItem>>({
+//         endpoint: API_ENDPOINTS.CONTINUITY.GetContinuityRecentlyWatched.endpoint,
+//         method: API_ENDPOINTS.CONTINUITY.GetContinuityRecentlyWatched.methods[0],
+//         queryKey: [API_ENDPOINTS.CONTINUITY.GetContinuityRecentlyWatched.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetContinuityResumeList() {
+//     return useServerQuery<Array<Continuity_EpisodeWatchHistoryItem>>({
+//         endpoint: API_ENDPOINTS.CONTINUITY.GetContinuityResumeList.endpoint,
+//         method: API_ENDPOINTS.CONTINUITY.GetContinuityResumeList.methods[0],
+//         queryKey: [API_ENDPOINTS.CONTINUITY.GetContinuityResumeList.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetContinuityWatchLog() {
+//     return useServerMutation<Array<Models_WatchLogEntry>, GetContinuityWatchLog_Variables>({
+//         endpoint: API_ENDPOINTS.CONTINUITY.GetContinuityWatchLog.endpoint,
+//         method: API_ENDPOINTS.CONTINUITY.GetContinuityWatchLog.methods[0],
+//         mutationKey: [API_ENDPOINTS.CONTINUITY.GetContinuityWatchLog.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // debrid
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1191,6 +1280,72 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// manga_auto_downloader
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useRunMangaAutoDownloader() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.RunMangaAutoDownloader.endpoint,
+//         method: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.RunMangaAutoDownloader.methods[0],
+//         mutationKey: [API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.RunMangaAutoDownloader.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetMangaAutoDownloaderRules() {
+//     return useServerQuery<Array<Manga_AutoDownloaderRule>>({
+//         endpoint: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.GetMangaAutoDownloaderRules.endpoint,
+//         method: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.GetMangaAutoDownloaderRules.methods[0],
+//         queryKey: [API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.GetMangaAutoDownloaderRules.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetMangaAutoDownloaderRulesByManga(id: number) {
+//     return useServerQuery<Array<Manga_AutoDownloaderRule>>({
+//         endpoint: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.GetMangaAutoDownloaderRulesByManga.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.GetMangaAutoDownloaderRulesByManga.methods[0],
+//         queryKey: [API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.GetMangaAutoDownloaderRulesByManga.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useCreateMangaAutoDownloaderRule() {
+//     return useServerMutation<Manga_AutoDownloaderRule, CreateMangaAutoDownloaderRule_Variables>({
+//         endpoint: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.CreateMangaAutoDownloaderRule.endpoint,
+//         method: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.CreateMangaAutoDownloaderRule.methods[0],
+//         mutationKey: [API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.CreateMangaAutoDownloaderRule.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useUpdateMangaAutoDownloaderRule() {
+//     return useServerMutation<Manga_AutoDownloaderRule, UpdateMangaAutoDownloaderRule_Variables>({
+//         endpoint: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.UpdateMangaAutoDownloaderRule.endpoint,
+//         method: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.UpdateMangaAutoDownloaderRule.methods[0],
+//         mutationKey: [API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.UpdateMangaAutoDownloaderRule.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteMangaAutoDownloaderRule(id: number) {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.DeleteMangaAutoDownloaderRule.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.DeleteMangaAutoDownloaderRule.methods[0],
+//         mutationKey: [API_ENDPOINTS.MANGA_AUTO_DOWNLOADER.DeleteMangaAutoDownloaderRule.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // manga_download
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1320,6 +1475,15 @@
 //     })
 // }
 
+// export function useDiscoverDlnaRenderers() {
+//     return useServerQuery<Array<Device>>({
+//         endpoint: API_ENDPOINTS.MEDIAPLAYER.DiscoverDlnaRenderers.endpoint,
+//         method: API_ENDPOINTS.MEDIAPLAYER.DiscoverDlnaRenderers.methods[0],
+//         queryKey: [API_ENDPOINTS.MEDIAPLAYER.DiscoverDlnaRenderers.key],
+//         enabled: true,
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // mediastream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1366,6 +1530,99 @@
 //     })
 // }
 
+// export function useGetMediastreamStreamTracks(id: string) {
+//     return useServerQuery<Tracks>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.GetMediastreamStreamTracks.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.MEDIASTREAM.GetMediastreamStreamTracks.methods[0],
+//         queryKey: [API_ENDPOINTS.MEDIASTREAM.GetMediastreamStreamTracks.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useLoadMediastreamStreamTracks(id: string) {
+//     return useServerMutation<Tracks, LoadMediastreamStreamTracks_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.LoadMediastreamStreamTracks.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.MEDIASTREAM.LoadMediastreamStreamTracks.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.LoadMediastreamStreamTracks.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useMediastreamGenerateTrickplay() {
+//     return useServerMutation<boolean, MediastreamGenerateTrickplay_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.MediastreamGenerateTrickplay.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.MediastreamGenerateTrickplay.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.MediastreamGenerateTrickplay.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetSkipMarkers() {
+//     return useServerMutation<Models_EpisodeSkipMarkers, GetSkipMarkers_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.GetSkipMarkers.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.GetSkipMarkers.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.GetSkipMarkers.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetMediaSkipMarkers(id: number) {
+//     return useServerQuery<Array<Models_EpisodeSkipMarkers>>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.GetMediaSkipMarkers.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.MEDIASTREAM.GetMediaSkipMarkers.methods[0],
+//         queryKey: [API_ENDPOINTS.MEDIASTREAM.GetMediaSkipMarkers.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useAnalyzeSkipMarkers() {
+//     return useServerMutation<boolean, AnalyzeSkipMarkers_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.AnalyzeSkipMarkers.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.AnalyzeSkipMarkers.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.AnalyzeSkipMarkers.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetMediastreamOptimizationQueue() {
+//     return useServerQuery<Array<Models_MediastreamOptimizationItem>>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.GetMediastreamOptimizationQueue.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.GetMediastreamOptimizationQueue.methods[0],
+//         queryKey: [API_ENDPOINTS.MEDIASTREAM.GetMediastreamOptimizationQueue.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useMediastreamEnqueueOptimization() {
+//     return useServerMutation<boolean, MediastreamEnqueueOptimization_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.MediastreamEnqueueOptimization.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.MediastreamEnqueueOptimization.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.MediastreamEnqueueOptimization.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useMediastreamRemoveOptimizationItem() {
+//     return useServerMutation<boolean, MediastreamRemoveOptimizationItem_Variables>({
+//         endpoint: API_ENDPOINTS.MEDIASTREAM.MediastreamRemoveOptimizationItem.endpoint,
+//         method: API_ENDPOINTS.MEDIASTREAM.MediastreamRemoveOptimizationItem.methods[0],
+//         mutationKey: [API_ENDPOINTS.MEDIASTREAM.MediastreamRemoveOptimizationItem.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useMediastreamShutdownTranscodeStream() {
 //     return useServerMutation<boolean>({
 //         endpoint: API_ENDPOINTS.MEDIASTREAM.MediastreamShutdownTranscodeStream.endpoint,
@@ -1425,6 +1682,37 @@
 //     })
 // }
 
+// export function useGetFillerPreference(id: number) {
+//     return useServerQuery<SkipFillerPreference>({
+//         endpoint: API_ENDPOINTS.METADATA.GetFillerPreference.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.METADATA.GetFillerPreference.methods[0],
+//         queryKey: [API_ENDPOINTS.METADATA.GetFillerPreference.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveFillerPreference() {
+//     return useServerMutation<SkipFillerPreference, SaveFillerPreference_Variables>({
+//         endpoint: API_ENDPOINTS.METADATA.SaveFillerPreference.endpoint,
+//         method: API_ENDPOINTS.METADATA.SaveFillerPreference.methods[0],
+//         mutationKey: [API_ENDPOINTS.METADATA.SaveFillerPreference.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteFillerPreference(id: number) {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.METADATA.DeleteFillerPreference.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.METADATA.DeleteFillerPreference.methods[0],
+//         mutationKey: [API_ENDPOINTS.METADATA.DeleteFillerPreference.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // onlinestream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1675,6 +1963,17 @@
 //     })
 // }
 
+// export function useResolveSmartPlaylist() {
+//     return useServerMutation<Array<Anime_LocalFile>, ResolveSmartPlaylist_Variables>({
+//         endpoint: API_ENDPOINTS.PLAYLIST.ResolveSmartPlaylist.endpoint,
+//         method: API_ENDPOINTS.PLAYLIST.ResolveSmartPlaylist.methods[0],
+//         mutationKey: [API_ENDPOINTS.PLAYLIST.ResolveSmartPlaylist.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useGetPlaylistEpisodes(id: number, progress: number) {
 //     return useServerQuery<Array<Anime_LocalFile>>({
 //         endpoint: API_ENDPOINTS.PLAYLIST.GetPlaylistEpisodes.endpoint.replace("{id}", String(id)).replace("{progress}", String(progress)),
@@ -2101,8 +2400,39 @@
 //     })
 // }
 
+// export function useGetTorrentstreamResumableSessions() {
+//     return useServerQuery<Array<Torrentstream_PersistedSession>>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamResumableSessions.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamResumableSessions.methods[0],
+//         queryKey: [API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamResumableSessions.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useTorrentstreamResumeStream() {
+//     return useServerMutation<boolean, TorrentstreamResumeStream_Variables>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamResumeStream.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamResumeStream.methods[0],
+//         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.TorrentstreamResumeStream.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDismissTorrentstreamResumableSession() {
+//     return useServerMutation<boolean, DismissTorrentstreamResumableSession_Variables>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.DismissTorrentstreamResumableSession.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.DismissTorrentstreamResumableSession.methods[0],
+//         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.DismissTorrentstreamResumableSession.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useTorrentstreamStopStream() {
-//     return useServerMutation<boolean>({
+//     return useServerMutation<boolean, TorrentstreamStopStream_Variables>({
 //         endpoint: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamStopStream.endpoint,
 //         method: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamStopStream.methods[0],
 //         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.TorrentstreamStopStream.key],
@@ -2113,7 +2443,7 @@
 // }
 
 // export function useTorrentstreamDropTorrent() {
-//     return useServerMutation<boolean>({
+//     return useServerMutation<boolean, TorrentstreamDropTorrent_Variables>({
 //         endpoint: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamDropTorrent.endpoint,
 //         method: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamDropTorrent.methods[0],
 //         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.TorrentstreamDropTorrent.key],
@@ -2123,6 +2453,35 @@
 //     })
 // }
 
+// export function useGetTorrentstreamSessions() {
+//     return useServerQuery<Array<Torrentstream_SessionInfo>>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamSessions.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamSessions.methods[0],
+//         queryKey: [API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamSessions.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetTorrentstreamCache() {
+//     return useServerQuery<Array<Torrentstream_CacheEntryInfo>>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamCache.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamCache.methods[0],
+//         queryKey: [API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamCache.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useRemoveTorrentstreamCacheEntry() {
+//     return useServerMutation<boolean, RemoveTorrentstreamCacheEntry_Variables>({
+//         endpoint: API_ENDPOINTS.TORRENTSTREAM.RemoveTorrentstreamCacheEntry.endpoint,
+//         method: API_ENDPOINTS.TORRENTSTREAM.RemoveTorrentstreamCacheEntry.methods[0],
+//         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.RemoveTorrentstreamCacheEntry.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
 // export function useGetTorrentstreamBatchHistory() {
 //     return useServerMutation<Torrentstream_BatchHistoryResponse, GetTorrentstreamBatchHistory_Variables>({
 //         endpoint: API_ENDPOINTS.TORRENTSTREAM.GetTorrentstreamBatchHistory.endpoint,
@@ -2134,3 +2493,192 @@
 //     })
 // }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// track_preferences
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetAllTrackPreferences() {
+//     return useServerQuery<Array<Models_TrackPreferences>>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCES.GetAllTrackPreferences.endpoint,
+//         method: API_ENDPOINTS.TRACK_PREFERENCES.GetAllTrackPreferences.methods[0],
+//         queryKey: [API_ENDPOINTS.TRACK_PREFERENCES.GetAllTrackPreferences.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useGetTrackPreferences(id: number) {
+//     return useServerQuery<Models_TrackPreferences>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCES.GetTrackPreferences.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.TRACK_PREFERENCES.GetTrackPreferences.methods[0],
+//         queryKey: [API_ENDPOINTS.TRACK_PREFERENCES.GetTrackPreferences.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useSaveTrackPreferences() {
+//     return useServerMutation<Models_TrackPreferences, SaveTrackPreferences_Variables>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCES.SaveTrackPreferences.endpoint,
+//         method: API_ENDPOINTS.TRACK_PREFERENCES.SaveTrackPreferences.methods[0],
+//         mutationKey: [API_ENDPOINTS.TRACK_PREFERENCES.SaveTrackPreferences.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useDeleteTrackPreferences(id: number) {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.TRACK_PREFERENCES.DeleteTrackPreferences.endpoint.replace("{id}", String(id)),
+//         method: API_ENDPOINTS.TRACK_PREFERENCES.DeleteTrackPreferences.methods[0],
+//         mutationKey: [API_ENDPOINTS.TRACK_PREFERENCES.DeleteTrackPreferences.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// viewing_stats
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetViewingStats() {
+//     return useServerMutation<ViewingStats_ViewingStats, GetViewingStats_Variables>({
+//         endpoint: API_ENDPOINTS.VIEWING_STATS.GetViewingStats.endpoint,
+//         method: API_ENDPOINTS.VIEWING_STATS.GetViewingStats.methods[0],
+//         mutationKey: [API_ENDPOINTS.VIEWING_STATS.GetViewingStats.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useGetViewingStatsYearlyRecap(year: number) {
+//     return useServerQuery<ViewingStats_YearlyRecap>({
+//         endpoint: API_ENDPOINTS.VIEWING_STATS.GetViewingStatsYearlyRecap.endpoint.replace("{year}", String(year)),
+//         method: API_ENDPOINTS.VIEWING_STATS.GetViewingStatsYearlyRecap.methods[0],
+//         queryKey: [API_ENDPOINTS.VIEWING_STATS.GetViewingStatsYearlyRecap.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useExportViewingStatsYearlyRecap(year: number) {
+//     return useServerQuery<boolean>({
+//         endpoint: API_ENDPOINTS.VIEWING_STATS.ExportViewingStatsYearlyRecap.endpoint.replace("{year}", String(year)),
+//         method: API_ENDPOINTS.VIEWING_STATS.ExportViewingStatsYearlyRecap.methods[0],
+//         queryKey: [API_ENDPOINTS.VIEWING_STATS.ExportViewingStatsYearlyRecap.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useRecordViewingProgress() {
+//     return useServerMutation<boolean, RecordViewingProgress_Variables>({
+//         endpoint: API_ENDPOINTS.VIEWING_STATS.RecordViewingProgress.endpoint,
+//         method: API_ENDPOINTS.VIEWING_STATS.RecordViewingProgress.methods[0],
+//         mutationKey: [API_ENDPOINTS.VIEWING_STATS.RecordViewingProgress.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// watch_party
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// export function useGetWatchParty() {
+//     return useServerQuery<WatchParty_Session>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.GetWatchParty.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.GetWatchParty.methods[0],
+//         queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key],
+//         enabled: true,
+//     })
+// }
+
+// export function useHostWatchParty() {
+//     return useServerMutation<WatchParty_Session, HostWatchParty_Variables>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.HostWatchParty.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.HostWatchParty.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.HostWatchParty.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useJoinWatchParty() {
+//     return useServerMutation<WatchParty_Session, JoinWatchParty_Variables>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.JoinWatchParty.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.JoinWatchParty.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.JoinWatchParty.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useLeaveWatchParty() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.LeaveWatchParty.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.LeaveWatchParty.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.LeaveWatchParty.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useWatchPartyPlay() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartyPlay.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.WatchPartyPlay.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartyPlay.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useWatchPartyPause() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartyPause.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.WatchPartyPause.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartyPause.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useWatchPartyResume() {
+//     return useServerMutation<boolean>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartyResume.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.WatchPartyResume.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartyResume.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useWatchPartySeek() {
+//     return useServerMutation<boolean, WatchPartySeek_Variables>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartySeek.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.WatchPartySeek.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartySeek.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
+// export function useTransferWatchPartyHost() {
+//     return useServerMutation<boolean, TransferWatchPartyHost_Variables>({
+//         endpoint: API_ENDPOINTS.WATCH_PARTY.TransferWatchPartyHost.endpoint,
+//         method: API_ENDPOINTS.WATCH_PARTY.TransferWatchPartyHost.methods[0],
+//         mutationKey: [API_ENDPOINTS.WATCH_PARTY.TransferWatchPartyHost.key],
+//         onSuccess: async () => {
+// 
+//         },
+//     })
+// }
+
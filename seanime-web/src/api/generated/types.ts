@@ -1340,6 +1340,11 @@ export type Anime_AutoDownloaderRule = {
     episodeNumbers?: Array<number>
     destination: string
     additionalTerms?: Array<string>
+    torrentCategory?: string
+    torrentTags?: Array<string>
+    managed?: boolean
+    disabledBySync?: boolean
+    templateId?: number
 }
 
 /**
@@ -1349,6 +1354,29 @@ export type Anime_AutoDownloaderRule = {
  */
 export type Anime_AutoDownloaderRuleEpisodeType = "recent" | "selected"
 
+/**
+ * - Filepath: internal/library/anime/autodownloader_rule.go
+ * - Filename: autodownloader_rule.go
+ * - Package: anime
+ */
+export type Anime_AutoDownloaderRuleTemplate = {
+    /**
+     * Will be set when fetched from the database
+     */
+    dbId: number
+    name: string
+    releaseGroups?: Array<string>
+    resolutions?: Array<string>
+    titleComparisonType: Anime_AutoDownloaderRuleTitleComparisonType
+    /**
+     * Can contain placeholders, e.g. "/anime/{title}"
+     */
+    destination: string
+    additionalTerms?: Array<string>
+    torrentCategory?: string
+    torrentTags?: Array<string>
+}
+
 /**
  * - Filepath: internal/library/anime/autodownloader_rule.go
  * - Filename: autodownloader_rule.go
@@ -1635,8 +1663,56 @@ export type Anime_Playlist = {
      * LocalFiles is a list of local files in the playlist, in order
      */
     localFiles?: Array<Anime_LocalFile>
+    items?: Array<Anime_PlaylistItem>
+    rules?: Anime_SmartPlaylistRules
+}
+
+/**
+ * - Filepath: internal/library/anime/playlist.go
+ * - Filename: playlist.go
+ * - Package: anime
+ */
+export type Anime_PlaylistItem = {
+    mediaId: number
+    episodeNumber: number
+    /**
+     * Preferred source
+     */
+    source: Anime_PlaylistItemSource
 }
 
+/**
+ * - Filepath: internal/library/anime/playlist.go
+ * - Filename: playlist.go
+ * - Package: anime
+ */
+export type Anime_PlaylistItemSource = "" | "localfile" | "torrentstream" | "debridstream" | "onlinestream"
+
+/**
+ * - Filepath: internal/library/anime/smart_playlist.go
+ * - Filename: smart_playlist.go
+ * - Package: anime
+ */
+export type Anime_SmartPlaylistRules = {
+    statuses?: Array<AL_MediaListStatus>
+    genres?: Array<string>
+    tags?: Array<string>
+    season?: AL_MediaSeason
+    seasonYear?: number
+    unwatchedOnly: boolean
+    nextEpisodeOnly: boolean
+    maxEpisodes: number
+    sortBy: Anime_SmartPlaylistSort
+    sortDesc: boolean
+}
+
+/**
+ * - Filepath: internal/library/anime/smart_playlist.go
+ * - Filename: smart_playlist.go
+ * - Package: anime
+ */
+export type Anime_SmartPlaylistSort = "" | "score" | "air_date"
+
 /**
  * - Filepath: internal/library/anime/collection.go
  * - Filename: collection.go
@@ -1679,6 +1755,34 @@ export type Anime_User = {
     token: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Autodownloader
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/library/autodownloader/schedule.go
+ * - Filename: schedule.go
+ * - Package: autodownloader
+ */
+export type AutoDownloader_RuleSchedule = {
+    ruleId: number
+    mediaId: number
+    nextCheckAt?: string
+    lastCheckAt?: string
+    reason: AutoDownloader_RuleScheduleReason
+    lastAiredEpisode?: number
+    lastAiredAt?: string
+    nextAiringEpisode?: number
+    nextAiringAt?: string
+}
+
+/**
+ * - Filepath: internal/library/autodownloader/schedule.go
+ * - Filename: schedule.go
+ * - Package: autodownloader
+ */
+export type AutoDownloader_RuleScheduleReason = "default" | "burst" | "airing"
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // ChapterDownloader
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1699,6 +1803,45 @@ export type ChapterDownloader_DownloadID = {
 // Continuity
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/continuity/episode_history.go
+ * - Filename: episode_history.go
+ * - Package: continuity
+ */
+export type Continuity_EpisodeWatchHistoryItem = {
+    kind: Continuity_Kind
+    filepath: string
+    mediaId: number
+    episodeNumber: number
+    currentTime: number
+    duration: number
+    completed: boolean
+    timeAdded?: string
+    lastWatched?: string
+}
+
+/**
+ * - Filepath: internal/continuity/episode_history.go
+ * - Filename: episode_history.go
+ * - Package: continuity
+ */
+export type Continuity_EpisodeWatchHistoryItemResponse = {
+    item?: Continuity_EpisodeWatchHistoryItem
+    found: boolean
+}
+
+/**
+ * - Filepath: internal/continuity/episode_history.go
+ * - Filename: episode_history.go
+ * - Package: continuity
+ */
+export type Continuity_GetWatchLogOptions = {
+    mediaId: number
+    from?: string
+    to?: string
+    limit: number
+}
+
 /**
  * - Filepath: internal/continuity/manager.go
  * - Filename: manager.go
@@ -1706,6 +1849,26 @@ export type ChapterDownloader_DownloadID = {
  */
 export type Continuity_Kind = "onlinestream" | "mediastream" | "external_player"
 
+/**
+ * - Filepath: internal/continuity/watch_log.go
+ * - Filename: watch_log.go
+ * - Package: continuity
+ */
+export type Continuity_LogWatchProgressOptions = {
+    mediaId: number
+    episodeNumber: number
+    kind?: Continuity_Kind
+    source: string
+    /**
+     * In seconds
+     */
+    currentTime: number
+    /**
+     * In seconds
+     */
+    duration: number
+}
+
 /**
  * - Filepath: internal/continuity/history.go
  * - Filename: history.go
@@ -1939,6 +2102,7 @@ export type DebridClient_StreamState = {
     status: DebridClient_StreamStatus
     torrentName: string
     message: string
+    extractionId?: string
 }
 
 /**
@@ -1948,6 +2112,23 @@ export type DebridClient_StreamState = {
  */
 export type DebridClient_StreamStatus = "downloading" | "ready" | "failed" | "started"
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Dlna
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/mediaplayers/dlna/dlna.go
+ * - Filename: dlna.go
+ * - Package: dlna
+ */
+export type Device = {
+    friendlyName: string
+    manufacturer: string
+    modelName: string
+    udn: string
+    location: string
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Extension
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -2290,6 +2471,25 @@ export type ExtensionRepo_UpdateData = {
     version: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Fillermanager
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/library/fillermanager/fillermanager.go
+ * - Filename: fillermanager.go
+ * - Package: fillermanager
+ */
+export type SkipFillerPreference = {
+    mediaId: number
+    skipFiller: boolean
+    markAsWatched: boolean
+    /**
+     * Whether the media has its own preference instead of the library settings
+     */
+    isOverridden: boolean
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Handlers
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -2583,6 +2783,26 @@ export type HibikeTorrent_AnimeTorrent = {
 // Manga
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/manga/auto_downloader_rule.go
+ * - Filename: auto_downloader_rule.go
+ * - Package: manga
+ */
+export type Manga_AutoDownloaderRule = {
+    /**
+     * Will be set when fetched from the database
+     */
+    dbId: number
+    enabled: boolean
+    mediaId: number
+    /**
+     * Manga provider extension ID
+     */
+    provider: string
+    scanlators?: Array<string>
+    language: string
+}
+
 /**
  * - Filepath: internal/manga/chapter_container.go
  * - Filename: chapter_container.go
@@ -2746,6 +2966,19 @@ export type Manga_ProviderDownloadMapChapterInfo = {
 // Mediastream
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+/**
+ * - Filepath: internal/mediastream/capabilities.go
+ * - Filename: capabilities.go
+ * - Package: mediastream
+ */
+export type Mediastream_ClientCapabilities = {
+    containers?: Array<string>
+    videoCodecs?: Array<string>
+    audioCodecs?: Array<string>
+    maxHeight: number
+    hdr: boolean
+}
+
 /**
  * - Filepath: internal/mediastream/playback.go
  * - Filename: playback.go
@@ -2763,8 +2996,19 @@ export type Mediastream_MediaContainer = {
      */
     streamUrl: string
     mediaInfo?: MediaInfo
+    trickplayUrl: string
+    playbackPlan?: Mediastream_PlaybackPlan
+    playbackPlanReason?: string
+    trackSelection?: Trackprefs_Selection
 }
 
+/**
+ * - Filepath: internal/mediastream/capabilities.go
+ * - Filename: capabilities.go
+ * - Package: mediastream
+ */
+export type Mediastream_PlaybackPlan = "directPlay" | "remux" | "audioTranscode" | "transcode"
+
 /**
  * - Filepath: internal/mediastream/playback.go
  * - Filename: playback.go
@@ -2819,6 +3063,11 @@ export type Models_AutoDownloaderSettings = {
     enableEnhancedQueries: boolean
     enableSeasonCheck: boolean
     useDebrid: boolean
+    useAiringSchedule: boolean
+    burstInterval: number
+    burstWindow: number
+    syncRules: boolean
+    syncRulesTemplateId: number
 }
 
 /**
@@ -2872,6 +3121,32 @@ export type Models_DiscordSettings = {
     richPresenceShowAniListProfileButton: boolean
 }
 
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  EpisodeSkipMarkers stores the intro and outro timestamps of a local file, in seconds.
+ *  A segment is absent if its end is 0.
+ */
+export type Models_EpisodeSkipMarkers = {
+    filepath: string
+    mediaId: number
+    episodeNumber: number
+    introStart: number
+    introEnd: number
+    outroStart: number
+    outroEnd: number
+    /**
+     * "fingerprint", "chapters" or empty
+     */
+    introSource: string
+    outroSource: string
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
 /**
  * - Filepath: internal/database/models/models.go
  * - Filename: models.go
@@ -2904,6 +3179,21 @@ export type Models_LibrarySettings = {
     autoSyncOfflineLocalData: boolean
     scannerMatchingThreshold: number
     scannerMatchingAlgorithm: string
+    autoSkipIntro: boolean
+    autoSkipOutro: boolean
+    enableDlnaServer: boolean
+    /**
+     * Name shown by the devices, "Seanime" if empty
+     */
+    dlnaServerName: string
+    /**
+     * Skip filler episodes when playing the next episode, in playlists and random play
+     */
+    skipFillerEpisodes: boolean
+    /**
+     * Update the progress on the platform when fillers are skipped
+     */
+    markSkippedFillerAsWatched: boolean
 }
 
 /**
@@ -2924,6 +3214,8 @@ export type Models_ListSyncSettings = {
 export type Models_MangaSettings = {
     defaultMangaProvider: string
     mangaAutoUpdateProgress: boolean
+    mangaAutoDownloaderEnabled: boolean
+    mangaAutoDownloaderInterval: number
 }
 
 /**
@@ -2933,7 +3225,7 @@ export type Models_MangaSettings = {
  */
 export type Models_MediaPlayerSettings = {
     /**
-     * "vlc" or "mpc-hc"
+     * "vlc", "mpc-hc", "mpv", "kodi" or "dlna"
      */
     defaultPlayer: string
     host: string
@@ -2945,6 +3237,59 @@ export type Models_MediaPlayerSettings = {
     mpcPath: string
     mpvSocket: string
     mpvPath: string
+    kodiHost: string
+    /**
+     * Port of the web server
+     */
+    kodiPort: number
+    /**
+     * Port of the JSON-RPC notifications
+     */
+    kodiWsPort: number
+    kodiUsername: string
+    kodiPassword: string
+    /**
+     * One "local=remote" directory mapping per line
+     */
+    kodiPathMappings: string
+    /**
+     * URL of the device description of the renderer
+     */
+    dlnaRendererLocation: string
+    /**
+     * Address of Seanime as seen by the renderer, detected if empty
+     */
+    dlnaServerAddress: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  MediastreamOptimizationItem is a file in the pre-transcoding queue.
+ *  A file can be queued once per task.
+ */
+export type Models_MediastreamOptimizationItem = {
+    filepath: string
+    /**
+     * "optimize" or "trickplay"
+     */
+    task: string
+    mediaId: number
+    quality: string
+    /**
+     * "pending", "running", "done" or "failed"
+     */
+    status: string
+    /**
+     * 0 to 1
+     */
+    progress: number
+    error: string
+    id: number
+    createdAt?: string
+    updatedAt?: string
 }
 
 /**
@@ -2964,6 +3309,26 @@ export type Models_MediastreamSettings = {
     ffmpegPath: string
     ffprobePath: string
     transcodeHwAccelCustomSettings: string
+    /**
+     * "low", "medium", "high" or "max"
+     */
+    preTranscodeQuality: string
+    /**
+     * Scheduling priority of ffmpeg, 0 (normal) to 19 (lowest)
+     */
+    preTranscodeNiceness: number
+    /**
+     * "HH:MM", the queue only runs during the window, empty for no restriction
+     */
+    preTranscodeWindowStart: string
+    /**
+     * "HH:MM"
+     */
+    preTranscodeWindowEnd: string
+    /**
+     * Queue the files added to the library by a scan
+     */
+    preTranscodeNewDownloads: boolean
     id: number
     createdAt?: string
     updatedAt?: string
@@ -3104,6 +3469,95 @@ export type Models_TorrentstreamSettings = {
     includeInLibrary: boolean
     streamUrlAddress: string
     slowSeeding: boolean
+    maxConcurrentStreams: number
+    prebufferNextEpisode: boolean
+    /**
+     * Percentage
+     */
+    prebufferThreshold: number
+    /**
+     * In GB, 0 means no limit
+     */
+    cacheMaxSize: number
+    /**
+     * In hours, 0 means no limit
+     */
+    cacheMaxAge: number
+    cacheKeepInLibrary: boolean
+    /**
+     * 0 means no ratio goal
+     */
+    seedRatio: number
+    /**
+     * In minutes, 0 means no duration goal
+     */
+    seedDuration: number
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  TrackPreferences stores the audio and subtitle tracks to select when playing the episodes of a media.
+ *  The preferences with a MediaId of 0 are the defaults, used for the fields a media does not set.
+ */
+export type Models_TrackPreferences = {
+    mediaId: number
+    /**
+     * Comma-separated language codes, by priority, e.g. "ja,en"
+     */
+    audioLanguage: string
+    /**
+     * Comma-separated language codes, by priority
+     */
+    subtitleLanguage: string
+    /**
+     * Case-insensitive regular expression matched against the track names, e.g. "full|dialogue"
+     */
+    subtitleTitlePattern: string
+    /**
+     * In seconds
+     */
+    subtitleDelay: number
+    id: number
+    createdAt?: string
+    updatedAt?: string
+}
+
+/**
+ * - Filepath: internal/database/models/models.go
+ * - Filename: models.go
+ * - Package: models
+ * @description
+ *  WatchLogEntry is a viewing session of an episode.
+ *  The entry is updated while the episode is being watched, a new entry is created when the episode is watched again later.
+ *  It is also used for the local viewing statistics, CreatedAt is the start of the session.
+ */
+export type Models_WatchLogEntry = {
+    mediaId: number
+    episodeNumber: number
+    kind: string
+    /**
+     * e.g. "localfile", "torrentstream", empty if unknown
+     */
+    source: string
+    /**
+     * Last known playback position, in seconds
+     */
+    position: number
+    /**
+     * In seconds
+     */
+    duration: number
+    /**
+     * Time spent playing, seeking is not counted
+     */
+    watchedSeconds: number
+    completed: boolean
     id: number
     createdAt?: string
     updatedAt?: string
@@ -3178,6 +3632,34 @@ export type Onlinestream_VideoSource = {
     quality: string
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Optimizer
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/mediastream/optimizer/queue.go
+ * - Filename: queue.go
+ * - Package: optimizer
+ */
+export type Criteria = {
+    /**
+     * FFmpeg names, e.g. "hevc"
+     */
+    videoCodecs?: Array<string>
+    /**
+     * Matches if any audio track has one of these codecs
+     */
+    audioCodecs?: Array<string>
+    minHeight: number
+}
+
+/**
+ * - Filepath: internal/mediastream/optimizer/optimizer.go
+ * - Filename: optimizer.go
+ * - Package: optimizer
+ */
+export type Quality = "low" | "medium" | "high" | "max"
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Report
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3271,6 +3753,32 @@ export type Report_UnlockedLocalFile = {
     mediaId: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Streamextract
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/mediastream/streamextract/streamextract.go
+ * - Filename: streamextract.go
+ * - Package: streamextract
+ */
+export type TimeRange = {
+    start: number
+    end: number
+}
+
+/**
+ * - Filepath: internal/mediastream/streamextract/streamextract.go
+ * - Filename: streamextract.go
+ * - Package: streamextract
+ */
+export type Tracks = {
+    subtitles?: Array<Subtitle>
+    fonts?: Array<string>
+    ranges?: Array<TimeRange>
+    done: boolean
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Summary
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3391,6 +3899,23 @@ export type Torrent_Preview = {
     torrent?: HibikeTorrent_AnimeTorrent
 }
 
+/**
+ * - Filepath: internal/torrents/torrent/aggregate.go
+ * - Filename: aggregate.go
+ * - Package: torrent
+ */
+export type Torrent_ProviderSearchError = {
+    provider: string
+    error: string
+}
+
+/**
+ * - Filepath: internal/torrents/torrent/aggregate.go
+ * - Filename: aggregate.go
+ * - Package: torrent
+ */
+export type Torrent_SeaDexStatus = "best" | "alternative"
+
 /**
  * - Filepath: internal/torrents/torrent/search.go
  * - Filename: search.go
@@ -3409,6 +3934,28 @@ export type Torrent_SearchData = {
      * Debrid instant availability
      */
     debridInstantAvailability?: Record<string, Debrid_TorrentItemInstantAvailability>
+    /**
+     * Providers that returned each torrent, keyed by info hash
+     */
+    sources?: Record<string, Array<Torrent_TorrentSource>>
+    /**
+     * SeaDex status of each torrent, keyed by info hash
+     */
+    seadexStatuses?: Record<string, Torrent_SeaDexStatus>
+    /**
+     * Providers that failed or timed out
+     */
+    providerErrors?: Array<Torrent_ProviderSearchError>
+}
+
+/**
+ * - Filepath: internal/torrents/torrent/aggregate.go
+ * - Filename: aggregate.go
+ * - Package: torrent
+ */
+export type Torrent_TorrentSource = {
+    provider: string
+    link: string
 }
 
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3453,6 +4000,31 @@ export type Torrentstream_BatchHistoryResponse = {
     torrent?: HibikeTorrent_AnimeTorrent
 }
 
+/**
+ * - Filepath: internal/torrentstream/cache.go
+ * - Filename: cache.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_CacheEntryInfo = {
+    infoHash: string
+    name: string
+    mediaId: number
+    episodeNumber: number
+    filename: string
+    /**
+     * Bytes stored on disk
+     */
+    size: number
+    /**
+     * Bytes uploaded to peers
+     */
+    uploaded: number
+    ratio: number
+    seeding: boolean
+    lastUsedAt?: string
+    inLibrary: boolean
+}
+
 /**
  * - Filepath: internal/torrentstream/list.go
  * - Filename: list.go
@@ -3461,6 +4033,10 @@ export type Torrentstream_BatchHistoryResponse = {
 export type Torrentstream_EpisodeCollection = {
     episodes?: Array<Anime_Episode>
     hasMappingError: boolean
+    /**
+     * Whether filler episodes should be skipped when playing the next episode
+     */
+    skipFillers: boolean
 }
 
 /**
@@ -3478,6 +4054,28 @@ export type Torrentstream_FilePreview = {
     index: number
 }
 
+/**
+ * - Filepath: internal/torrentstream/resume.go
+ * - Filename: resume.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_PersistedSession = {
+    sessionId: string
+    clientId: string
+    mediaId: number
+    episodeNumber: number
+    aniDBEpisode: string
+    playbackType: Torrentstream_PlaybackType
+    infoHash: string
+    fileIndex: number
+    filename: string
+    torrent?: HibikeTorrent_AnimeTorrent
+    readOffset: number
+    progressPercentage: number
+    playbackProgress: number
+    updatedAt?: string
+}
+
 /**
  * - Filepath: internal/torrentstream/stream.go
  * - Filename: stream.go
@@ -3485,6 +4083,36 @@ export type Torrentstream_FilePreview = {
  */
 export type Torrentstream_PlaybackType = "default" | "externalPlayerLink"
 
+/**
+ * - Filepath: internal/torrentstream/session.go
+ * - Filename: session.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_PriorityStrategy = "streaming" | "background"
+
+/**
+ * - Filepath: internal/torrentstream/session.go
+ * - Filename: session.go
+ * - Package: torrentstream
+ */
+export type Torrentstream_SessionInfo = {
+    id: string
+    clientId: string
+    mediaId: number
+    episodeNumber: number
+    aniDBEpisode: string
+    playbackType: Torrentstream_PlaybackType
+    priorityStrategy: Torrentstream_PriorityStrategy
+    infoHash: string
+    torrentName: string
+    filename: string
+    status: Torrentstream_TorrentStatus
+    startedAt?: string
+    playbackProgress: number
+    prebufferedEpisode: number
+    extractionId: string
+}
+
 /**
  * - Filepath: internal/torrentstream/events.go
  * - Filename: events.go
@@ -3522,6 +4150,26 @@ export type Torrentstream_TorrentStatus = {
     seeders: number
 }
 
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Trackprefs
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/mediastream/trackprefs/select.go
+ * - Filename: select.go
+ * - Package: trackprefs
+ */
+export type Trackprefs_Selection = {
+    audioLanguages?: Array<string>
+    subtitleLanguages?: Array<string>
+    audioIndex?: number
+    subtitleIndex?: number
+    /**
+     * In seconds
+     */
+    subtitleDelay: number
+}
+
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Tvdb
 //////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -3685,5 +4333,251 @@ export type Video = {
     width: number
     height: number
     bitrate: number
+    isHdr: boolean
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Viewingstats
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_Binge = {
+    mediaId: number
+    title: string
+    date: string
+    episodes: number
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_GenreStats = {
+    genre: string
+    seconds: number
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_PeriodStats = {
+    /**
+     * "2006-01-02" for days, "2006-W01" for weeks
+     */
+    period: string
+    seconds: number
+    episodesCompleted: number
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_RewatchedEpisode = {
+    mediaId: number
+    title: string
+    episodeNumber: number
+    views: number
 }
 
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_SeriesStats = {
+    mediaId: number
+    /**
+     * Empty if the media is not in the collection
+     */
+    title: string
+    seconds: number
+    episodesCompleted: number
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/manager.go
+ * - Filename: manager.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_Source = "localfile" |
+    "torrentstream" |
+    "debridstream" |
+    "onlinestream" |
+    "mediastream" |
+    "other"
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_SourceStats = {
+    source: ViewingStats_Source
+    seconds: number
+    sessions: number
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_Streak = {
+    /**
+     * "2006-01-02"
+     */
+    start: string
+    end: string
+    days: number
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_ViewingStats = {
+    totalSeconds: number
+    sessions: number
+    episodesCompleted: number
+    days?: Array<ViewingStats_PeriodStats>
+    weeks?: Array<ViewingStats_PeriodStats>
+    series?: Array<ViewingStats_SeriesStats>
+    genres?: Array<ViewingStats_GenreStats>
+    sources?: Array<ViewingStats_SourceStats>
+    longestStreak?: ViewingStats_Streak
+    currentStreak?: ViewingStats_Streak
+    longestBinge?: ViewingStats_Binge
+    mostRewatched?: Array<ViewingStats_RewatchedEpisode>
+}
+
+/**
+ * - Filepath: internal/library/viewingstats/stats.go
+ * - Filename: stats.go
+ * - Package: viewingstats
+ */
+export type ViewingStats_YearlyRecap = {
+    year: number
+    generatedAt?: string
+    busiestDay?: ViewingStats_PeriodStats
+    topSeries?: Array<ViewingStats_SeriesStats>
+    topGenres?: Array<ViewingStats_GenreStats>
+    stats?: ViewingStats_ViewingStats
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+// Watchparty
+//////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+/**
+ * - Filepath: internal/watchparty/watchparty.go
+ * - Filename: watchparty.go
+ * - Package: watchparty
+ */
+export type WatchParty_Media = {
+    source: WatchParty_Source
+    mediaId: number
+    episodeNumber: number
+    aniDbEpisode: string
+    /**
+     * Shown to the members
+     */
+    title: string
+    torrent?: HibikeTorrent_AnimeTorrent
+    fileIndex?: number
+    provider?: string
+    server?: string
+    dubbed?: boolean
+}
+
+/**
+ * - Filepath: internal/watchparty/watchparty.go
+ * - Filename: watchparty.go
+ * - Package: watchparty
+ */
+export type WatchParty_Member = {
+    id: string
+    name: string
+    isHost: boolean
+    /**
+     * Whether the player of the member plays the episode of the party
+     */
+    loaded: boolean
+    /**
+     * Last position reported by the member, in seconds
+     */
+    position: number
+    paused: boolean
+    /**
+     * Unix milliseconds
+     */
+    lastSeen: number
+}
+
+/**
+ * - Filepath: internal/watchparty/watchparty.go
+ * - Filename: watchparty.go
+ * - Package: watchparty
+ */
+export type WatchParty_PlaybackState = {
+    /**
+     * Nil until an episode is played
+     */
+    media?: WatchParty_Media
+    paused: boolean
+    /**
+     * In seconds, at UpdatedAt
+     */
+    position: number
+    /**
+     * Unix milliseconds, on the clock of the host
+     */
+    updatedAt: number
+}
+
+/**
+ * - Filepath: internal/watchparty/watchparty.go
+ * - Filename: watchparty.go
+ * - Package: watchparty
+ */
+export type WatchParty_Role = "host" | "peer"
+
+/**
+ * - Filepath: internal/watchparty/watchparty.go
+ * - Filename: watchparty.go
+ * - Package: watchparty
+ */
+export type WatchParty_Session = {
+    id: string
+    /**
+     * Role of this instance
+     */
+    role: WatchParty_Role
+    /**
+     * ID of this instance
+     */
+    memberId: string
+    /**
+     * Address the peers are connected to, empty for the host
+     */
+    hostAddress: string
+    members?: Array<WatchParty_Member>
+    state?: WatchParty_PlaybackState
+}
+
+/**
+ * - Filepath: internal/watchparty/watchparty.go
+ * - Filename: watchparty.go
+ * - Package: watchparty
+ */
+export type WatchParty_Source = "localfile" | "torrentstream" | "onlinestream"
+
@@ -1,11 +1,12 @@
 import { useServerMutation, useServerQuery } from "@/api/client/requests"
 import {
+    GetSkipMarkers_Variables,
     PreloadMediastreamMediaContainer_Variables,
     RequestMediastreamMediaContainer_Variables,
     SaveMediastreamSettings_Variables,
 } from "@/api/generated/endpoint.types"
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
-import { Mediastream_MediaContainer, Models_MediastreamSettings } from "@/api/generated/types"
+import { Mediastream_MediaContainer, Models_EpisodeSkipMarkers, Models_MediastreamSettings } from "@/api/generated/types"
 import { logger } from "@/lib/helpers/debug"
 import { useQueryClient } from "@tanstack/react-query"
 import { toast } from "sonner"
@@ -64,3 +65,13 @@ export function useMediastreamShutdownTranscodeStream() {
         },
     })
 }
+
+export function useGetSkipMarkers(path: string | null | undefined) {
+    return useServerQuery<Models_EpisodeSkipMarkers | null, GetSkipMarkers_Variables>({
+        endpoint: API_ENDPOINTS.MEDIASTREAM.GetSkipMarkers.endpoint,
+        method: API_ENDPOINTS.MEDIASTREAM.GetSkipMarkers.methods[0],
+        queryKey: [API_ENDPOINTS.MEDIASTREAM.GetSkipMarkers.key, path],
+        data: { path: path! },
+        enabled: !!path,
+    })
+}
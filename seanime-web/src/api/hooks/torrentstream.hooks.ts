@@ -3,7 +3,9 @@ import {
     GetTorrentstreamBatchHistory_Variables,
     GetTorrentstreamTorrentFilePreviews_Variables,
     SaveTorrentstreamSettings_Variables,
+    TorrentstreamDropTorrent_Variables,
     TorrentstreamStartStream_Variables,
+    TorrentstreamStopStream_Variables,
 } from "@/api/generated/endpoint.types"
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
 import {
@@ -59,7 +61,7 @@ export function useTorrentstreamStartStream() {
 }
 
 export function useTorrentstreamStopStream() {
-    return useServerMutation<boolean>({
+    return useServerMutation<boolean, TorrentstreamStopStream_Variables>({
         endpoint: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamStopStream.endpoint,
         method: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamStopStream.methods[0],
         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.TorrentstreamStopStream.key],
@@ -70,7 +72,7 @@ export function useTorrentstreamStopStream() {
 }
 
 export function useTorrentstreamDropTorrent() {
-    return useServerMutation<boolean>({
+    return useServerMutation<boolean, TorrentstreamDropTorrent_Variables>({
         endpoint: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamDropTorrent.endpoint,
         method: API_ENDPOINTS.TORRENTSTREAM.TorrentstreamDropTorrent.methods[0],
         mutationKey: [API_ENDPOINTS.TORRENTSTREAM.TorrentstreamDropTorrent.key],
@@ -0,0 +1,31 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import { GetViewingStats_Variables, RecordViewingProgress_Variables } from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { ViewingStats_ViewingStats, ViewingStats_YearlyRecap } from "@/api/generated/types"
+
+export function useGetViewingStats(variables: GetViewingStats_Variables) {
+    return useServerQuery<ViewingStats_ViewingStats, GetViewingStats_Variables>({
+        endpoint: API_ENDPOINTS.VIEWING_STATS.GetViewingStats.endpoint,
+        method: API_ENDPOINTS.VIEWING_STATS.GetViewingStats.methods[0],
+        queryKey: [API_ENDPOINTS.VIEWING_STATS.GetViewingStats.key, JSON.stringify(variables)],
+        data: variables,
+        enabled: true,
+    })
+}
+
+export function useGetViewingStatsYearlyRecap(year: number) {
+    return useServerQuery<ViewingStats_YearlyRecap>({
+        endpoint: API_ENDPOINTS.VIEWING_STATS.GetViewingStatsYearlyRecap.endpoint.replace("{year}", String(year)),
+        method: API_ENDPOINTS.VIEWING_STATS.GetViewingStatsYearlyRecap.methods[0],
+        queryKey: [API_ENDPOINTS.VIEWING_STATS.GetViewingStatsYearlyRecap.key, String(year)],
+        enabled: true,
+    })
+}
+
+export function useRecordViewingProgress() {
+    return useServerMutation<boolean, RecordViewingProgress_Variables>({
+        endpoint: API_ENDPOINTS.VIEWING_STATS.RecordViewingProgress.endpoint,
+        method: API_ENDPOINTS.VIEWING_STATS.RecordViewingProgress.methods[0],
+        mutationKey: [API_ENDPOINTS.VIEWING_STATS.RecordViewingProgress.key],
+    })
+}
@@ -0,0 +1,120 @@
+import { useServerMutation, useServerQuery } from "@/api/client/requests"
+import {
+    HostWatchParty_Variables,
+    JoinWatchParty_Variables,
+    TransferWatchPartyHost_Variables,
+    WatchPartySeek_Variables,
+} from "@/api/generated/endpoint.types"
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { WatchParty_Media, WatchParty_Session } from "@/api/generated/types"
+import { useQueryClient } from "@tanstack/react-query"
+import { toast } from "sonner"
+
+export function useGetWatchParty() {
+    return useServerQuery<WatchParty_Session | null>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.GetWatchParty.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.GetWatchParty.methods[0],
+        queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key],
+        enabled: true,
+    })
+}
+
+export function useHostWatchParty() {
+    const qc = useQueryClient()
+
+    return useServerMutation<WatchParty_Session, HostWatchParty_Variables>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.HostWatchParty.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.HostWatchParty.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.HostWatchParty.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key] })
+            toast.success("Watch party started")
+        },
+    })
+}
+
+export function useJoinWatchParty() {
+    const qc = useQueryClient()
+
+    return useServerMutation<WatchParty_Session, JoinWatchParty_Variables>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.JoinWatchParty.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.JoinWatchParty.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.JoinWatchParty.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key] })
+            toast.success("Joined the watch party")
+        },
+    })
+}
+
+export function useLeaveWatchParty() {
+    const qc = useQueryClient()
+
+    return useServerMutation<boolean>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.LeaveWatchParty.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.LeaveWatchParty.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.LeaveWatchParty.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key] })
+            toast.info("Left the watch party")
+        },
+    })
+}
+
+export function useWatchPartyPlay() {
+    return useServerMutation<boolean, WatchParty_Media>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartyPlay.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.WatchPartyPlay.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartyPlay.key],
+        onSuccess: async () => {
+
+        },
+    })
+}
+
+export function useWatchPartyPause() {
+    return useServerMutation<boolean>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartyPause.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.WatchPartyPause.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartyPause.key],
+        onSuccess: async () => {
+
+        },
+    })
+}
+
+export function useWatchPartyResume() {
+    return useServerMutation<boolean>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartyResume.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.WatchPartyResume.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartyResume.key],
+        onSuccess: async () => {
+
+        },
+    })
+}
+
+export function useWatchPartySeek() {
+    return useServerMutation<boolean, WatchPartySeek_Variables>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.WatchPartySeek.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.WatchPartySeek.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.WatchPartySeek.key],
+        onSuccess: async () => {
+
+        },
+    })
+}
+
+export function useTransferWatchPartyHost() {
+    const qc = useQueryClient()
+
+    return useServerMutation<boolean, TransferWatchPartyHost_Variables>({
+        endpoint: API_ENDPOINTS.WATCH_PARTY.TransferWatchPartyHost.endpoint,
+        method: API_ENDPOINTS.WATCH_PARTY.TransferWatchPartyHost.methods[0],
+        mutationKey: [API_ENDPOINTS.WATCH_PARTY.TransferWatchPartyHost.key],
+        onSuccess: async () => {
+            await qc.invalidateQueries({ queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key] })
+            toast.success("Host transferred")
+        },
+    })
+}
@@ -22,6 +22,7 @@ import { useExternalPlayerLinkListener } from "@/app/(main)/_listeners/external-
 import { useMangaListener } from "@/app/(main)/_listeners/manga.listeners"
 import { useMiscEventListeners } from "@/app/(main)/_listeners/misc-events.listeners"
 import { useSyncListener } from "@/app/(main)/_listeners/sync.listeners"
+import { useWatchPartyListener } from "@/app/(main)/_listeners/watch-party.listeners"
 import { DebridStreamOverlay } from "@/app/(main)/entry/_containers/debrid-stream/debrid-stream-overlay"
 import { TorrentStreamOverlay } from "@/app/(main)/entry/_containers/torrent-stream/torrent-stream-overlay"
 import { ChapterDownloadsDrawer } from "@/app/(main)/manga/_containers/chapter-downloads/chapter-downloads-drawer"
@@ -49,6 +50,7 @@ export const MainLayout = ({ children }: { children: React.ReactNode }) => {
     useExternalPlayerLinkListener()
     useSyncListener()
     useInvalidateQueriesListener()
+    useWatchPartyListener()
 
     return (
         <>
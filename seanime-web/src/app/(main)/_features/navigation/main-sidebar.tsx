@@ -34,6 +34,7 @@ import { FaBookReader } from "react-icons/fa"
 import { FiLogIn, FiSearch, FiSettings } from "react-icons/fi"
 import { HiOutlineServerStack } from "react-icons/hi2"
 import { IoCloudOfflineOutline, IoLibrary } from "react-icons/io5"
+import { LuPartyPopper } from "react-icons/lu"
 import { PiArrowCircleLeftDuotone, PiArrowCircleRightDuotone, PiClockCounterClockwiseFill } from "react-icons/pi"
 import { SiAnilist } from "react-icons/si"
 import { TbWorldDownload } from "react-icons/tb"
@@ -212,6 +213,12 @@ export function MainSidebar() {
                                 href: "/debrid",
                                 isCurrent: pathname === "/debrid",
                             }],
+                            {
+                                iconType: LuPartyPopper,
+                                name: "Watch party",
+                                href: "/watch-party",
+                                isCurrent: pathname === "/watch-party",
+                            },
                             {
                                 iconType: PiClockCounterClockwiseFill,
                                 name: "Scan summaries",
@@ -1,4 +1,5 @@
 import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { Anime_PlaylistItem } from "@/api/generated/types"
 import {
     usePlaybackAutoPlayNextEpisode,
     usePlaybackCancelCurrentPlaylist,
@@ -11,6 +12,7 @@ import { PlaybackManager_PlaybackState, PlaybackManager_PlaylistState } from "@/
 import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
 import { useServerStatus } from "@/app/(main)/_hooks/use-server-status"
 import { useDebridStreamAutoplay, useTorrentStreamAutoplay } from "@/app/(main)/entry/_containers/torrent-stream/_lib/handle-torrent-stream"
+import { __onlinestream_playlistItemAtom } from "@/app/(main)/onlinestream/_lib/onlinestream.atoms"
 import { ConfirmationDialog, useConfirmationDialog } from "@/components/shared/confirmation-dialog"
 import { imageShimmer } from "@/components/shared/image-helpers"
 import { Button, IconButton } from "@/components/ui/button"
@@ -20,10 +22,11 @@ import { ProgressBar } from "@/components/ui/progress-bar"
 import { logger } from "@/lib/helpers/debug"
 import { WSEvents } from "@/lib/server/ws-events"
 import { useQueryClient } from "@tanstack/react-query"
-import { atom, useAtomValue } from "jotai"
+import { atom, useAtomValue, useSetAtom } from "jotai"
 import { useAtom } from "jotai/react"
 import mousetrap from "mousetrap"
 import Image from "next/image"
+import { useRouter } from "next/navigation"
 import React from "react"
 import { BiSolidSkipNextCircle } from "react-icons/bi"
 import { MdCancel } from "react-icons/md"
@@ -272,6 +275,8 @@ export function PlaybackManagerProgressTracking() {
     })
 
     const queryClient = useQueryClient()
+    const router = useRouter()
+    const setPlaylistOnlinestreamItem = useSetAtom(__onlinestream_playlistItemAtom)
 
     // Progress has been updated
     useWebsocketMessageListener<PlaybackManager_PlaybackState | null>({
@@ -292,6 +297,19 @@ export function PlaybackManagerProgressTracking() {
         type: WSEvents.PLAYBACK_MANAGER_PLAYLIST_STATE,
         onMessage: data => {
             setPlaylistState(data)
+            if (!data) {
+                setPlaylistOnlinestreamItem(null)
+            }
+        },
+    })
+
+    // The playlist item is streamed online, the online streaming page plays it and requests the next item when it ends
+    useWebsocketMessageListener<Anime_PlaylistItem>({
+        type: WSEvents.PLAYBACK_MANAGER_PLAYLIST_PLAY_ONLINE_STREAM,
+        onMessage: data => {
+            logger("PlaybackManagerProgressTracking").info("Playing playlist item with online streaming", data)
+            setPlaylistOnlinestreamItem(data)
+            router.push(`/onlinestream?id=${data.mediaId}&episode=${data.episodeNumber}`)
         },
     })
 
@@ -1,3 +1,4 @@
+import { Models_EpisodeSkipMarkers } from "@/api/generated/types"
 import { useQuery } from "@tanstack/react-query"
 
 /* -------------------------------------------------------------------------------------------------
@@ -32,8 +33,37 @@ export type AniSkipTime = {
     episodeLength: number
 }
 
+export type SkipData = {
+    op: AniSkipTime | null
+    ed: AniSkipTime | null
+}
+
+/**
+ * Fills the intro and outro missing from AniSkip with the ones detected by the server.
+ * The markers are only detected for local files.
+ */
+export function withLocalSkipMarkers(data: SkipData | undefined, markers: Models_EpisodeSkipMarkers | null | undefined): SkipData | undefined {
+    if (!markers) return data
+
+    const toSkipTime = (skipType: AniSkipType, startTime: number, endTime: number): AniSkipTime | null => {
+        if (endTime <= startTime) return null
+        return {
+            interval: { startTime, endTime },
+            skipType,
+            skipId: `local-${skipType}`,
+            // The ending is only shown if it starts close to the end of the episode
+            episodeLength: markers.outroEnd || endTime,
+        }
+    }
+
+    return {
+        op: data?.op ?? toSkipTime("op", markers.introStart, markers.introEnd),
+        ed: data?.ed ?? toSkipTime("ed", markers.outroStart, markers.outroEnd),
+    }
+}
+
 export function useSkipData(mediaMalId: number | null | undefined, episodeNumber: number) {
-    const res = useQuery({
+    const res = useQuery<SkipData>({
         queryKey: ["skip-data", mediaMalId, episodeNumber],
         queryFn: async () => {
             const result = await fetch(
@@ -1,9 +1,11 @@
+import { Models_EpisodeSkipMarkers } from "@/api/generated/types"
 import { useUpdateAnimeEntryProgress } from "@/api/hooks/anime_entries.hooks"
 import { useHandleContinuityWithMediaPlayer, useHandleCurrentMediaContinuity } from "@/api/hooks/continuity.hooks"
 import { useCancelDiscordActivity, useSetDiscordAnimeActivity } from "@/api/hooks/discord.hooks"
+import { useRecordViewingProgress } from "@/api/hooks/viewing_stats.hooks"
 
 import { useSeaCommandInject } from "@/app/(main)/_features/sea-command/use-inject"
-import { useSkipData } from "@/app/(main)/_features/sea-media-player/aniskip"
+import { useSkipData, withLocalSkipMarkers } from "@/app/(main)/_features/sea-media-player/aniskip"
 import { useFullscreenHandler } from "@/app/(main)/_features/sea-media-player/macos-tauri-fullscreen"
 import { SeaMediaPlayerPlaybackSubmenu } from "@/app/(main)/_features/sea-media-player/sea-media-player-components"
 import {
@@ -71,6 +73,10 @@ export type SeaMediaPlayerProps = {
     onGoToNextEpisode: () => void
     onGoToPreviousEpisode?: () => void
     mediaInfoDuration?: number
+    // Where the episode is played from, used for the viewing statistics
+    viewingStatsSource?: "onlinestream" | "mediastream"
+    // Intro and outro detected by the server, used when AniSkip has no data for the episode
+    localSkipMarkers?: Models_EpisodeSkipMarkers | null
 }
 
 type ChapterProps = {
@@ -100,6 +106,8 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
         onGoToPreviousEpisode,
         settingsItems,
         mediaInfoDuration,
+        viewingStatsSource,
+        localSkipMarkers,
     } = props
 
     const serverStatus = useServerStatus()
@@ -136,6 +144,7 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
     const [showSkipEndingButton, setShowSkipEndingButton] = React.useState(false)
 
     const watchHistoryRef = React.useRef<number>(0)
+    const viewingStatsRef = React.useRef<number>(0)
     const checkTimeRef = React.useRef<number>(0)
 
     // Track last focused element
@@ -143,6 +152,8 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
 
     /** AniSkip **/
     const { data: aniSkipData } = useSkipData(media?.idMal, progress.currentEpisodeNumber ?? -1)
+    // Fall back to the intro and outro detected by the server when AniSkip has no data for the episode
+    const skipData = React.useMemo(() => withLocalSkipMarkers(aniSkipData, localSkipMarkers), [aniSkipData, localSkipMarkers])
 
     /** Progress update **/
     const { mutate: updateProgress, isPending: isUpdatingProgress, isSuccess: hasUpdatedProgress } = useUpdateAnimeEntryProgress(
@@ -161,6 +172,11 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
      */
     const { handleUpdateWatchHistory } = useHandleContinuityWithMediaPlayer(playerRef, progress.currentEpisodeNumber, media?.id)
 
+    /**
+     * Viewing stats
+     */
+    const { mutate: recordViewingProgress } = useRecordViewingProgress()
+
     /**
      * Discord Rich Presence
      */
@@ -175,28 +191,28 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
          * AniSkip
          */
         if (
-            aniSkipData?.op?.interval &&
+            skipData?.op?.interval &&
             !!detail?.currentTime &&
-            detail?.currentTime >= aniSkipData.op.interval.startTime &&
-            detail?.currentTime <= aniSkipData.op.interval.endTime
+            detail?.currentTime >= skipData.op.interval.startTime &&
+            detail?.currentTime <= skipData.op.interval.endTime
         ) {
             setShowSkipIntroButton(true)
             if (autoSkipIntroOutro) {
-                seekTo(aniSkipData?.op?.interval?.endTime || 0)
+                seekTo(skipData?.op?.interval?.endTime || 0)
             }
         } else {
             setShowSkipIntroButton(false)
         }
         if (
-            aniSkipData?.ed?.interval &&
-            Math.abs(aniSkipData.ed.interval.startTime - (aniSkipData?.ed?.episodeLength)) < 500 &&
+            skipData?.ed?.interval &&
+            Math.abs(skipData.ed.interval.startTime - (skipData?.ed?.episodeLength)) < 500 &&
             !!detail?.currentTime &&
-            detail?.currentTime >= aniSkipData.ed.interval.startTime &&
-            detail?.currentTime <= aniSkipData.ed.interval.endTime
+            detail?.currentTime >= skipData.ed.interval.startTime &&
+            detail?.currentTime <= skipData.ed.interval.endTime
         ) {
             setShowSkipEndingButton(true)
             if (autoSkipIntroOutro) {
-                seekTo(aniSkipData?.ed?.interval?.endTime || 0)
+                seekTo(skipData?.ed?.interval?.endTime || 0)
             }
         } else {
             setShowSkipEndingButton(false)
@@ -209,6 +225,26 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
 
         watchHistoryRef.current++
 
+        /**
+         * Viewing stats
+         */
+        if (viewingStatsSource && viewingStatsRef.current > 120) {
+            viewingStatsRef.current = 0
+            if (media?.id && progress.currentEpisodeNumber && playerRef.current?.duration) {
+                recordViewingProgress({
+                    options: {
+                        mediaId: media.id,
+                        episodeNumber: progress.currentEpisodeNumber,
+                        source: viewingStatsSource,
+                        currentTime: detail?.currentTime ?? 0,
+                        duration: playerRef.current.duration,
+                    },
+                })
+            }
+        }
+
+        viewingStatsRef.current++
+
         /**
          * Progress
          */
@@ -319,20 +355,20 @@ export function SeaMediaPlayer(props: SeaMediaPlayerProps) {
     }
 
     function onSkipIntro() {
-        if (!aniSkipData?.op?.interval?.endTime) return
-        seekTo(aniSkipData?.op?.interval?.endTime || 0)
+        if (!skipData?.op?.interval?.endTime) return
+        seekTo(skipData?.op?.interval?.endTime || 0)
     }
 
     function onSkipOutro() {
-        if (!aniSkipData?.ed?.interval?.endTime) return
-        seekTo(aniSkipData?.ed?.interval?.endTime || 0)
+        if (!skipData?.ed?.interval?.endTime) return
+        seekTo(skipData?.ed?.interval?.endTime || 0)
     }
 
     const cues = React.useMemo(() => {
-        const introStart = aniSkipData?.op?.interval?.startTime ?? 0
-        const introEnd = aniSkipData?.op?.interval?.endTime ?? 0
-        const outroStart = aniSkipData?.ed?.interval?.startTime ?? 0
-        const outroEnd = aniSkipData?.ed?.interval?.endTime ?? 0
+        const introStart = skipData?.op?.interval?.startTime ?? 0
+        const introEnd = skipData?.op?.interval?.endTime ?? 0
+        const outroStart = skipData?.ed?.interval?.startTime ?? 0
+        const outroEnd = skipData?.ed?.interval?.endTime ?? 0
         const ret = []
         if (introEnd > introStart) {
             ret.push({
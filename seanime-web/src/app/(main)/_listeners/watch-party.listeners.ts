@@ -0,0 +1,46 @@
+import { API_ENDPOINTS } from "@/api/generated/endpoints"
+import { WatchParty_Media } from "@/api/generated/types"
+import { useWebsocketMessageListener } from "@/app/(main)/_hooks/handle-websockets"
+import {
+    __onlinestream_selectedDubbedAtom,
+    __onlinestream_selectedProviderAtom,
+    __onlinestream_selectedServerAtom,
+} from "@/app/(main)/onlinestream/_lib/onlinestream.atoms"
+import { logger } from "@/lib/helpers/debug"
+import { WSEvents } from "@/lib/server/ws-events"
+import { useQueryClient } from "@tanstack/react-query"
+import { useSetAtom } from "jotai"
+import { useRouter } from "next/navigation"
+
+export function useWatchPartyListener() {
+
+    const queryClient = useQueryClient()
+    const router = useRouter()
+
+    const setProvider = useSetAtom(__onlinestream_selectedProviderAtom)
+    const setServer = useSetAtom(__onlinestream_selectedServerAtom)
+    const setDubbed = useSetAtom(__onlinestream_selectedDubbedAtom)
+
+    useWebsocketMessageListener({
+        type: WSEvents.WATCH_PARTY_UPDATED,
+        onMessage: () => {
+            queryClient.invalidateQueries({ queryKey: [API_ENDPOINTS.WATCH_PARTY.GetWatchParty.key] })
+        },
+    })
+
+    // The party plays an online stream episode, open it in the online streaming page
+    useWebsocketMessageListener<WatchParty_Media>({
+        type: WSEvents.WATCH_PARTY_PLAY_ONLINE_STREAM,
+        onMessage: data => {
+            logger("WATCH_PARTY").info("Playing online stream episode", data)
+
+            // Use the same provider and server as the host when they are known
+            if (data.provider) setProvider(data.provider)
+            if (data.server) setServer(data.server)
+            if (data.dubbed !== undefined) setDubbed(data.dubbed)
+
+            router.push(`/onlinestream?id=${data.mediaId}&episode=${data.episodeNumber}`)
+        },
+    })
+
+}
@@ -85,7 +85,12 @@ export function DebridStreamPage(props: DebridStreamPageProps) {
     // Otherwise, it resets the autoplay info
     function handleSetDebridstreamAutoplayInfo(episode: Anime_Episode | undefined) {
         if (!episode || !episode.aniDBEpisode || !episodeCollection?.episodes) return
-        const nextEpisode = episodeCollection?.episodes?.find(e => e.episodeNumber === episode.episodeNumber + 1)
+        // Filler episodes are skipped if the user chose to
+        let nextEpisodeNumber = episode.episodeNumber + 1
+        while (episodeCollection.skipFillers && episodeCollection.episodes.find(e => e.episodeNumber === nextEpisodeNumber)?.episodeMetadata?.isFiller) {
+            nextEpisodeNumber++
+        }
+        const nextEpisode = episodeCollection?.episodes?.find(e => e.episodeNumber === nextEpisodeNumber)
         logger("TORRENTSTREAM").info("Auto select, Next episode", nextEpisode)
         if (nextEpisode && !!nextEpisode.aniDBEpisode) {
             setDebridstreamAutoplayInfo({
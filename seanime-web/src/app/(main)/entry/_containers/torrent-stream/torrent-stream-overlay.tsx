@@ -158,7 +158,7 @@ export function TorrentStreamOverlay() {
 
                         <Tooltip
                             trigger={<IconButton
-                                onClick={() => stop()}
+                                onClick={() => stop({ all: false })}
                                 loading={isPending}
                                 intent="alert-basic"
                                 icon={<BiStop />}
@@ -75,7 +75,12 @@ export function TorrentStreamPage(props: TorrentStreamPageProps) {
     // Otherwise, it resets the autoplay info
     function handleSetTorrentstreamAutoplayInfo(episode: Anime_Episode | undefined) {
         if (!episode || !episode.aniDBEpisode || !episodeCollection?.episodes) return
-        const nextEpisode = episodeCollection?.episodes?.find(e => e.episodeNumber === episode.episodeNumber + 1)
+        // Filler episodes are skipped if the user chose to
+        let nextEpisodeNumber = episode.episodeNumber + 1
+        while (episodeCollection.skipFillers && episodeCollection.episodes.find(e => e.episodeNumber === nextEpisodeNumber)?.episodeMetadata?.isFiller) {
+            nextEpisodeNumber++
+        }
+        const nextEpisode = episodeCollection?.episodes?.find(e => e.episodeNumber === nextEpisodeNumber)
         logger("TORRENTSTREAM").info("Auto select, Next episode", nextEpisode)
         if (nextEpisode && !!nextEpisode.aniDBEpisode) {
             setTorrentstreamAutoplayInfo({
@@ -286,6 +286,8 @@ export function useHandleMediastream(props: HandleMediastreamProps) {
                 fonts: fonts,
                 availableFonts: availableFonts,
                 fallbackFont: firstFont,
+                // A positive delay shows the subtitles later
+                timeOffset: -(mediaContainer?.trackSelection?.subtitleDelay ?? 0),
             })
             playerRef.current!.textRenderers.add(renderer)
 
@@ -299,6 +301,7 @@ export function useHandleMediastream(props: HandleMediastreamProps) {
         playerRef.current,
         mediaContainer?.streamUrl,
         mediaContainer?.mediaInfo?.fonts,
+        mediaContainer?.trackSelection?.subtitleDelay,
         jassubOffscreenRender,
     ])
 
@@ -436,6 +439,23 @@ export function useHandleMediastream(props: HandleMediastreamProps) {
         logger("MEDIASTREAM").info("[onCanPlay] called", e)
         preloadedNextFileForRef.current = undefined
         setDuration(e.duration)
+        selectPreferredAudioTrack()
+    }
+
+    /**
+     * Selects the audio track preferred by the user
+     * - The tracks of the player are in the same order as the audio streams of the file
+     */
+    function selectPreferredAudioTrack() {
+        const audioIndex = mediaContainer?.trackSelection?.audioIndex
+        if (audioIndex === undefined || audioIndex === null) return
+
+        const position = mediaContainer?.mediaInfo?.audios?.findIndex(audio => audio.index === audioIndex) ?? -1
+        const track = playerRef.current?.audioTracks?.toArray()?.[position]
+        if (track && !track.selected) {
+            logger("MEDIASTREAM").info("Selecting preferred audio track", track.label)
+            track.selected = true
+        }
     }
 
     const playNextEpisode = () => {
@@ -1,5 +1,6 @@
 "use client"
 import { useGetAnimeEntry } from "@/api/hooks/anime_entries.hooks"
+import { useGetSkipMarkers } from "@/api/hooks/mediastream.hooks"
 import { EpisodeGridItem } from "@/app/(main)/_features/anime/_components/episode-grid-item"
 import { MediaEntryPageSmallBanner } from "@/app/(main)/_features/media/_components/media-entry-page-small-banner"
 import { MediaEpisodeInfoModal } from "@/app/(main)/_features/media/_components/media-episode-info-modal"
@@ -74,6 +75,9 @@ export default function Page() {
 
     const { jassubOffscreenRender, setJassubOffscreenRender } = useMediastreamJassubOffscreenRender()
 
+    // Intro and outro detected by the server, used when AniSkip has no data for the episode
+    const { data: skipMarkers } = useGetSkipMarkers(filePath)
+
     /**
      * The episode number of the current file
      */
@@ -229,10 +233,16 @@ export default function Page() {
                                 src: url || "",
                                 type: mediaContainer?.mediaInfo?.extension === "mp4" ? "video/mp4" :
                                     mediaContainer?.mediaInfo?.extension === "avi" ? "video/x-msvideo" : "video/webm",
+                            } : mediaContainer?.streamType === "optimized" ? {
+                                // The pre-transcoded version of the file is always an MP4 file
+                                src: url || "",
+                                type: "video/mp4",
                             } : url}
                             isPlaybackError={isError}
                             isLoading={isMediaContainerLoading}
                             playerRef={playerRef}
+                            viewingStatsSource="mediastream"
+                            localSkipMarkers={skipMarkers}
                             poster={episodes?.find(n => n.localFile?.path === mediaContainer?.filePath)?.episodeMetadata?.image ||
                                 animeEntry?.media?.bannerImage || animeEntry?.media?.coverImage?.extraLarge}
                             onProviderChange={onProviderChange}
@@ -245,7 +255,10 @@ export default function Page() {
                                 lang: sub.language,
                                 type: (sub.extension?.replace(".", "") || "ass") as CaptionsFileFormat,
                                 kind: "subtitles",
-                                default: sub.isDefault || (!subtitles.some(n => n.isDefault) && sub.language?.startsWith("en")),
+                                // The track preferred by the user, or the default track of the file
+                                default: (mediaContainer?.trackSelection?.subtitleIndex ?? null) !== null
+                                    ? sub.index === mediaContainer?.trackSelection?.subtitleIndex
+                                    : sub.isDefault || (!subtitles.some(n => n.isDefault) && sub.language?.startsWith("en")),
                             }))}
                             mediaInfoDuration={mediaContainer?.mediaInfo?.duration}
                             loadingText={<>
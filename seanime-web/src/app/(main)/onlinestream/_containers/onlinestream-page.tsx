@@ -1,4 +1,5 @@
 import { Anime_Entry } from "@/api/generated/types"
+import { usePlaybackPlaylistNext } from "@/api/hooks/playback_manager.hooks"
 import { serverStatusAtom } from "@/app/(main)/_atoms/server-status.atoms"
 import { EpisodeGridItem } from "@/app/(main)/_features/anime/_components/episode-grid-item"
 import { MediaEpisodeInfoModal } from "@/app/(main)/_features/media/_components/media-episode-info-modal"
@@ -13,7 +14,9 @@ import {
 } from "@/app/(main)/onlinestream/_components/onlinestream-video-addons"
 import { OnlinestreamManualMappingModal } from "@/app/(main)/onlinestream/_containers/onlinestream-manual-matching"
 import { useHandleOnlinestream } from "@/app/(main)/onlinestream/_lib/handle-onlinestream"
+import { useOnlinestreamWatchParty } from "@/app/(main)/onlinestream/_lib/handle-onlinestream-watch-party"
 import { OnlinestreamManagerProvider } from "@/app/(main)/onlinestream/_lib/onlinestream-manager"
+import { __onlinestream_playlistItemAtom } from "@/app/(main)/onlinestream/_lib/onlinestream.atoms"
 import { LuffyError } from "@/components/shared/luffy-error"
 import { IconButton } from "@/components/ui/button"
 import { Skeleton } from "@/components/ui/skeleton"
@@ -21,7 +24,7 @@ import { logger } from "@/lib/helpers/debug"
 import { isHLSProvider, MediaPlayerInstance, MediaProviderAdapter, MediaProviderChangeEvent, MediaProviderSetupEvent } from "@vidstack/react"
 import HLS from "hls.js"
 import { atom } from "jotai/index"
-import { useAtomValue } from "jotai/react"
+import { useAtom, useAtomValue } from "jotai/react"
 import { usePathname, useRouter, useSearchParams } from "next/navigation"
 import React from "react"
 import { FaSearch } from "react-icons/fa"
@@ -72,6 +75,8 @@ export function OnlinestreamPage({ animeEntry, animeEntryLoading, hideBackButton
         ref,
     })
 
+    useOnlinestreamWatchParty(ref)
+
     const maxEp = media?.nextAiringEpisode?.episode ? (media?.nextAiringEpisode?.episode - 1) : media?.episodes || 0
     const progress = animeEntry?.listData?.progress ?? 0
 
@@ -94,6 +99,23 @@ export function OnlinestreamPage({ animeEntry, animeEntryLoading, hideBackButton
         }
     }, [episodes])
 
+    /**
+     * Set the episode number again when another media is opened
+     */
+    useUpdateEffect(() => {
+        firstRenderRef.current = true
+    }, [mediaId])
+
+    /**
+     * Set the episode number when the page is opened again with another episode, e.g. by a watch party
+     */
+    useUpdateEffect(() => {
+        if (!firstRenderRef.current && !!urlEpNumber && episodes?.find(e => e.number === Number(urlEpNumber))) {
+            handleChangeEpisodeNumber(Number(urlEpNumber))
+            logger("ONLINESTREAM").info("Setting episode number to", Number(urlEpNumber))
+        }
+    }, [urlEpNumber])
+
     React.useEffect(() => {
         const t = setTimeout(() => {
             if (urlEpNumber) {
@@ -102,9 +124,43 @@ export function OnlinestreamPage({ animeEntry, animeEntryLoading, hideBackButton
         }, 500)
 
         return () => clearTimeout(t)
-    }, [mediaId])
+    }, [mediaId, urlEpNumber])
+
+    /**
+     * Playlist
+     */
+    const [playlistItem, setPlaylistItem] = useAtom(__onlinestream_playlistItemAtom)
+    const { mutate: playlistNext } = usePlaybackPlaylistNext()
+    const requestedPlaylistNextRef = React.useRef(false)
+
+    React.useEffect(() => {
+        requestedPlaylistNextRef.current = false
+    }, [playlistItem])
+
+    function isPlayingPlaylistItem() {
+        return !!playlistItem && playlistItem.mediaId === Number(mediaId) && playlistItem.episodeNumber === currentEpisodeNumber
+    }
+
+    // The next item of the playlist is played by the server, it can be a local file or a stream
+    function requestPlaylistNext() {
+        if (requestedPlaylistNextRef.current) return
+        requestedPlaylistNextRef.current = true
+        setPlaylistItem(null)
+        playlistNext()
+    }
+
+    function onEnded() {
+        if (isPlayingPlaylistItem()) {
+            requestPlaylistNext()
+        }
+    }
 
     function goToNextEpisode() {
+        if (isPlayingPlaylistItem()) {
+            requestPlaylistNext()
+            return
+        }
+
         if (currentEpisodeNumber < maxEp) {
             // check if the episode exists
             if (episodes?.find(e => e.number === currentEpisodeNumber + 1)) {
@@ -219,9 +275,11 @@ export function OnlinestreamPage({ animeEntry, animeEntryLoading, hideBackButton
                             isLoading={episodeLoading}
                             isPlaybackError={isErrorEpisodeSource}
                             playerRef={ref}
+                            viewingStatsSource="onlinestream"
                             onProviderChange={onProviderChange}
                             onProviderSetup={onProviderSetup}
                             onCanPlay={_onCanPlay}
+                            onEnded={onEnded}
                             onGoToNextEpisode={goToNextEpisode}
                             onGoToPreviousEpisode={goToPreviousEpisode}
                             tracks={episodeSource?.subtitles?.map((sub) => ({
@@ -0,0 +1,62 @@
+import { useGetWatchParty } from "@/api/hooks/watch_party.hooks"
+import { useWebsocketMessageListener, useWebsocketSender } from "@/app/(main)/_hooks/handle-websockets"
+import { logger } from "@/lib/helpers/debug"
+import { WSEvents } from "@/lib/server/ws-events"
+import { MediaPlayerInstance } from "@vidstack/react"
+import React from "react"
+
+type WatchPartyPlayerCommand = {
+    action: "pause" | "resume" | "seek"
+    position: number
+}
+
+// Client event read by the watch party to sync the members
+const WATCH_PARTY_PLAYER_STATUS = "watch-party-player-status"
+
+/**
+ * Lets the watch party control the online stream player and reports its status while this instance is in a party.
+ */
+export function useOnlinestreamWatchParty(playerRef: React.RefObject<MediaPlayerInstance>) {
+    const { data: watchParty } = useGetWatchParty()
+    const { sendMessage } = useWebsocketSender()
+
+    useWebsocketMessageListener<WatchPartyPlayerCommand>({
+        type: WSEvents.WATCH_PARTY_PLAYER_COMMAND,
+        onMessage: data => {
+            const player = playerRef.current
+            if (!player) return
+
+            logger("ONLINESTREAM").info("Watch party command", data)
+            switch (data.action) {
+                case "pause":
+                    player.pause()
+                    break
+                case "resume":
+                    player.play()
+                    break
+                case "seek":
+                    player.currentTime = data.position
+                    break
+            }
+        },
+    })
+
+    React.useEffect(() => {
+        if (!watchParty) return
+
+        const interval = setInterval(() => {
+            const player = playerRef.current
+            if (!player || !player.state.canPlay) return
+
+            sendMessage({
+                type: WATCH_PARTY_PLAYER_STATUS,
+                payload: {
+                    position: player.currentTime,
+                    paused: player.paused,
+                },
+            })
+        }, 1000)
+
+        return () => clearInterval(interval)
+    }, [!!watchParty])
+}
@@ -1,3 +1,4 @@
+import { Anime_PlaylistItem } from "@/api/generated/types"
 import { atom } from "jotai/index"
 import { atomWithStorage } from "jotai/utils"
 
@@ -11,3 +12,6 @@ export const __onlinestream_selectedEpisodeNumberAtom = atom<number | undefined>
 export const __onlinestream_selectedServerAtom = atomWithStorage<string | undefined>("sea-onlinestream-server", undefined)
 
 export const __onlinestream_qualityAtom = atomWithStorage<string | undefined>("sea-onlinestream-quality", undefined)
+
+// Playlist item played by the online streaming page, the next item is requested from the server when it ends
+export const __onlinestream_playlistItemAtom = atom<Anime_PlaylistItem | null>(null)
@@ -220,8 +220,8 @@ export function TorrentstreamSettings(props: TorrentstreamSettingsProps) {
                 <div className="flex w-full items-center">
                     <SettingsSubmitButton isPending={isPending} />
                     <div className="flex flex-1"></div>
-                    <Button leftIcon={<SiBittorrent />} intent="alert-subtle" onClick={() => dropTorrent()} disabled={droppingTorrent}>
-                        Drop torrent
+                    <Button leftIcon={<SiBittorrent />} intent="alert-subtle" onClick={() => dropTorrent({ all: true })} disabled={droppingTorrent}>
+                        Drop all torrents
                     </Button>
                 </div>
             </Form>
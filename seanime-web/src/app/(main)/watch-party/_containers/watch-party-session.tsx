@@ -0,0 +1,279 @@
+import { WatchParty_Member, WatchParty_Session, WatchParty_Source } from "@/api/generated/types"
+import { useGetAnimeCollection } from "@/api/hooks/anilist.hooks"
+import {
+    useLeaveWatchParty,
+    useTransferWatchPartyHost,
+    useWatchPartyPause,
+    useWatchPartyPlay,
+    useWatchPartyResume,
+    useWatchPartySeek,
+} from "@/api/hooks/watch_party.hooks"
+import {
+    __onlinestream_selectedDubbedAtom,
+    __onlinestream_selectedProviderAtom,
+    __onlinestream_selectedServerAtom,
+} from "@/app/(main)/onlinestream/_lib/onlinestream.atoms"
+import { ConfirmationDialog, useConfirmationDialog } from "@/components/shared/confirmation-dialog"
+import { Badge } from "@/components/ui/badge"
+import { Button } from "@/components/ui/button"
+import { Card } from "@/components/ui/card"
+import { cn } from "@/components/ui/core/styling"
+import { NumberInput } from "@/components/ui/number-input"
+import { Select } from "@/components/ui/select"
+import { Separator } from "@/components/ui/separator"
+import { useAtomValue } from "jotai"
+import React from "react"
+import { LuLogOut, LuPause, LuPlay } from "react-icons/lu"
+
+// A member is shown as away when the host has not heard from it for this long.
+// LastSeen values are compared with each other so that the clock of the host does not matter.
+const AWAY_AFTER_MS = 5000
+
+function formatPosition(seconds: number) {
+    const s = Math.max(0, Math.floor(seconds))
+    const h = Math.floor(s / 3600)
+    const m = Math.floor((s % 3600) / 60)
+    const pad = (n: number) => String(n).padStart(2, "0")
+    return h > 0 ? `${h}:${pad(m)}:${pad(s % 60)}` : `${m}:${pad(s % 60)}`
+}
+
+type WatchPartySessionProps = {
+    session: WatchParty_Session
+}
+
+export function WatchPartySession(props: WatchPartySessionProps) {
+
+    const {
+        session,
+    } = props
+
+    const isHost = session.role === "host"
+    const media = session.state?.media
+
+    const { mutate: leave, isPending: isLeaving } = useLeaveWatchParty()
+    const { mutate: pause, isPending: isPausing } = useWatchPartyPause()
+    const { mutate: resume, isPending: isResuming } = useWatchPartyResume()
+    const { mutate: seek, isPending: isSeeking } = useWatchPartySeek()
+
+    const [seekPosition, setSeekPosition] = React.useState(0)
+
+    const confirmLeave = useConfirmationDialog({
+        title: "Leave the watch party",
+        description: isHost
+            ? "The watch party will end for everyone."
+            : "You can join again with the address of the host and the passcode.",
+        onConfirm: () => leave(),
+    })
+
+    return (
+        <div className="space-y-4">
+            <div className="flex flex-wrap items-center gap-2">
+                <Badge intent={isHost ? "primary" : "gray"} size="lg">{isHost ? "Host" : "Member"}</Badge>
+                {!isHost && !!session.hostAddress && <p className="text-[--muted] text-sm">Connected to {session.hostAddress}</p>}
+
+                <div className="flex flex-1"></div>
+
+                <Button
+                    intent="alert-subtle"
+                    rounded
+                    leftIcon={<LuLogOut className="text-xl" />}
+                    loading={isLeaving}
+                    onClick={() => confirmLeave.open()}
+                >
+                    Leave
+                </Button>
+            </div>
+
+            <Card className="p-4 space-y-4">
+                <div>
+                    <h3>Now playing</h3>
+                    {media ? <p>
+                        <span>{media.title || `Media ${media.mediaId}`}</span>
+                        <span className="text-[--muted]"> - Episode {media.episodeNumber}</span>
+                    </p> : <p className="text-[--muted]">Nothing is playing.</p>}
+                    {!!media && !!session.state && <p className="text-[--muted] text-sm">
+                        {session.state.paused ? "Paused" : "Playing"} at {formatPosition(session.state.position)}
+                    </p>}
+                </div>
+
+                {!!media && <div className="flex flex-wrap items-end gap-2">
+                    <Button
+                        intent="gray-subtle"
+                        leftIcon={<LuPause />}
+                        loading={isPausing}
+                        disabled={session.state?.paused}
+                        onClick={() => pause()}
+                    >
+                        Pause
+                    </Button>
+                    <Button
+                        intent="gray-subtle"
+                        leftIcon={<LuPlay />}
+                        loading={isResuming}
+                        disabled={!session.state?.paused}
+                        onClick={() => resume()}
+                    >
+                        Resume
+                    </Button>
+                    <NumberInput
+                        label="Position (seconds)"
+                        min={0}
+                        value={seekPosition}
+                        onValueChange={v => setSeekPosition(v)}
+                        fieldClass="w-fit"
+                        formatOptions={{ useGrouping: false }}
+                    />
+                    <Button
+                        intent="gray-subtle"
+                        loading={isSeeking}
+                        onClick={() => seek({ position: seekPosition })}
+                    >
+                        Seek
+                    </Button>
+                </div>}
+
+                <Separator />
+
+                <WatchPartyPlayForm />
+            </Card>
+
+            <WatchPartyMembers session={session} />
+
+            <ConfirmationDialog {...confirmLeave} />
+        </div>
+    )
+}
+
+function WatchPartyPlayForm() {
+
+    const { data: animeCollection } = useGetAnimeCollection()
+    const { mutate: play, isPending } = useWatchPartyPlay()
+
+    const provider = useAtomValue(__onlinestream_selectedProviderAtom)
+    const server = useAtomValue(__onlinestream_selectedServerAtom)
+    const dubbed = useAtomValue(__onlinestream_selectedDubbedAtom)
+
+    const [mediaId, setMediaId] = React.useState<number>(0)
+    const [episodeNumber, setEpisodeNumber] = React.useState(1)
+    const [source, setSource] = React.useState<WatchParty_Source>("localfile")
+
+    const media = React.useMemo(() => {
+        const allMedia = animeCollection?.MediaListCollection?.lists?.flatMap(n => n?.entries)?.filter(Boolean)?.map(n => n.media)?.filter(Boolean) ?? []
+        // The same media can be in several lists
+        return allMedia.filter((m, i) => allMedia.findIndex(n => n?.id === m?.id) === i)
+    }, [animeCollection])
+
+    function handlePlay() {
+        const selected = media.find(n => n?.id === mediaId)
+        if (!selected) return
+
+        play({
+            source,
+            mediaId,
+            episodeNumber,
+            // Regular episodes have the same number on AniDB
+            aniDbEpisode: String(episodeNumber),
+            title: selected.title?.userPreferred || selected.title?.romaji || "",
+            ...(source === "onlinestream" && {
+                provider: provider ?? undefined,
+                server,
+                dubbed,
+            }),
+        })
+    }
+
+    return (
+        <div className="space-y-2">
+            <h4>Play an episode</h4>
+            <p className="text-[--muted] text-sm">
+                The episode is played on every instance of the party. Online streams use the provider and server selected on this instance.
+            </p>
+            <div className="flex flex-wrap items-end gap-2">
+                <Select
+                    label="Anime"
+                    placeholder="Select an anime"
+                    value={mediaId ? String(mediaId) : ""}
+                    onValueChange={v => setMediaId(Number(v))}
+                    options={media.map(n => ({ value: String(n?.id), label: n?.title?.userPreferred || String(n?.id) }))}
+                    fieldClass="w-full max-w-md"
+                />
+                <NumberInput
+                    label="Episode"
+                    min={0}
+                    value={episodeNumber}
+                    onValueChange={v => setEpisodeNumber(v)}
+                    fieldClass="w-fit"
+                    formatOptions={{ useGrouping: false }}
+                />
+                <Select
+                    label="Source"
+                    value={source}
+                    onValueChange={v => setSource(v as WatchParty_Source)}
+                    options={[
+                        { value: "localfile", label: "Local files" },
+                        { value: "torrentstream", label: "Torrent streaming" },
+                        { value: "onlinestream", label: "Online streaming" },
+                    ]}
+                    fieldClass="w-fit"
+                />
+                <Button
+                    intent="white"
+                    leftIcon={<LuPlay />}
+                    loading={isPending}
+                    disabled={!mediaId}
+                    onClick={handlePlay}
+                >
+                    Play
+                </Button>
+            </div>
+        </div>
+    )
+}
+
+function WatchPartyMembers({ session }: { session: WatchParty_Session }) {
+
+    const { mutate: transferHost, isPending: isTransferring } = useTransferWatchPartyHost()
+
+    const members = session.members ?? []
+    const latestSeen = Math.max(0, ...members.map(n => n.lastSeen))
+
+    function isAway(member: WatchParty_Member) {
+        return latestSeen - member.lastSeen > AWAY_AFTER_MS
+    }
+
+    return (
+        <Card className="p-4 space-y-2">
+            <h3>Members ({members.length})</h3>
+            <div className="divide-y divide-[--border]">
+                {members.map(member => (
+                    <div key={member.id} className="flex flex-wrap items-center gap-2 py-2">
+                        <div
+                            className={cn("size-2 rounded-full", isAway(member) ? "bg-gray-500" : "bg-green-500")}
+                            title={isAway(member) ? "Away" : "Online"}
+                        />
+                        <p className="font-medium">{member.name}</p>
+                        {member.id === session.memberId && <span className="text-[--muted] text-sm">(you)</span>}
+                        {member.isHost && <Badge intent="primary" size="sm">Host</Badge>}
+
+                        <div className="flex flex-1"></div>
+
+                        <p className="text-[--muted] text-sm">
+                            {!member.loaded
+                                ? "Not playing"
+                                : `${member.paused ? "Paused" : "Playing"} at ${formatPosition(member.position)}`}
+                        </p>
+
+                        {session.role === "host" && !member.isHost && <Button
+                            intent="gray-subtle"
+                            size="sm"
+                            disabled={isTransferring || isAway(member)}
+                            onClick={() => transferHost({ memberId: member.id })}
+                        >
+                            Make host
+                        </Button>}
+                    </div>
+                ))}
+            </div>
+        </Card>
+    )
+}
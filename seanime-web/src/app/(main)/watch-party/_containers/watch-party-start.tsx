@@ -0,0 +1,90 @@
+import { useHostWatchParty, useJoinWatchParty } from "@/api/hooks/watch_party.hooks"
+import { Button } from "@/components/ui/button"
+import { Card } from "@/components/ui/card"
+import { TextInput } from "@/components/ui/text-input"
+import React from "react"
+import { LuLogIn, LuPartyPopper } from "react-icons/lu"
+
+/**
+ * Forms to host a watch party or join the one hosted by another instance.
+ */
+export function WatchPartyStart() {
+
+    const { mutate: hostWatchParty, isPending: isHosting } = useHostWatchParty()
+    const { mutate: joinWatchParty, isPending: isJoining } = useJoinWatchParty()
+
+    const [name, setName] = React.useState("")
+    const [hostPasscode, setHostPasscode] = React.useState("")
+    const [address, setAddress] = React.useState("")
+    const [joinPasscode, setJoinPasscode] = React.useState("")
+
+    return (
+        <div className="space-y-4">
+            <TextInput
+                label="Your name"
+                help="Shown to the other members of the party."
+                value={name}
+                onValueChange={setName}
+                className="max-w-md"
+            />
+
+            <div className="grid grid-cols-1 lg:grid-cols-2 gap-4">
+                <Card className="p-4 space-y-4">
+                    <div>
+                        <h3>Host</h3>
+                        <p className="text-[--muted] text-sm">
+                            Start a watch party on this instance. Other instances join it with the address of this server and the passcode.
+                        </p>
+                    </div>
+                    <TextInput
+                        label="Passcode"
+                        type="password"
+                        value={hostPasscode}
+                        onValueChange={setHostPasscode}
+                    />
+                    <Button
+                        intent="white"
+                        rounded
+                        leftIcon={<LuPartyPopper className="text-xl" />}
+                        loading={isHosting}
+                        disabled={isJoining || !name || !hostPasscode}
+                        onClick={() => hostWatchParty({ name, passcode: hostPasscode })}
+                    >
+                        Host
+                    </Button>
+                </Card>
+
+                <Card className="p-4 space-y-4">
+                    <div>
+                        <h3>Join</h3>
+                        <p className="text-[--muted] text-sm">
+                            Join the watch party hosted by another instance.
+                        </p>
+                    </div>
+                    <TextInput
+                        label="Address"
+                        placeholder="http://192.168.1.10:43211"
+                        value={address}
+                        onValueChange={setAddress}
+                    />
+                    <TextInput
+                        label="Passcode"
+                        type="password"
+                        value={joinPasscode}
+                        onValueChange={setJoinPasscode}
+                    />
+                    <Button
+                        intent="primary-subtle"
+                        rounded
+                        leftIcon={<LuLogIn className="text-xl" />}
+                        loading={isJoining}
+                        disabled={isHosting || !name || !address || !joinPasscode}
+                        onClick={() => joinWatchParty({ address, name, passcode: joinPasscode })}
+                    >
+                        Join
+                    </Button>
+                </Card>
+            </div>
+        </div>
+    )
+}
@@ -0,0 +1,31 @@
+"use client"
+import { useGetWatchParty } from "@/api/hooks/watch_party.hooks"
+import { WatchPartySession } from "@/app/(main)/watch-party/_containers/watch-party-session"
+import { WatchPartyStart } from "@/app/(main)/watch-party/_containers/watch-party-start"
+import { PageWrapper } from "@/components/shared/page-wrapper"
+import { LoadingSpinner } from "@/components/ui/loading-spinner"
+import React from "react"
+
+export const dynamic = "force-static"
+
+export default function Page() {
+
+    const { data: session, isLoading } = useGetWatchParty()
+
+    if (isLoading) return <LoadingSpinner />
+
+    return (
+        <PageWrapper
+            className="p-4 sm:p-8 pt-4 relative space-y-8"
+        >
+            <div>
+                <h2>Watch party</h2>
+                <p className="text-[--muted]">
+                    Watch episodes in sync with other Seanime instances.
+                </p>
+            </div>
+
+            {!session ? <WatchPartyStart /> : <WatchPartySession session={session} />}
+        </PageWrapper>
+    )
+}
@@ -15,6 +15,7 @@ export const enum WSEvents {
     PLAYBACK_MANAGER_PROGRESS_PLAYBACK_STATE = "playback-manager-progress-playback-state",
     PLAYBACK_MANAGER_PROGRESS_UPDATED = "playback-manager-progress-updated",
     PLAYBACK_MANAGER_PLAYLIST_STATE = "playback-manager-playlist-state",
+    PLAYBACK_MANAGER_PLAYLIST_PLAY_ONLINE_STREAM = "playback-manager-playlist-play-online-stream",
     PLAYBACK_MANAGER_MANUAL_TRACKING_PLAYBACK_STATE = "playback-manager-manual-tracking-playback-state",
     EXTERNAL_PLAYER_OPEN_URL = "external-player-open-url",
     PLAYBACK_MANAGER_MANUAL_TRACKING_STOPPED = "playback-manager-manual-tracking-stopped",
@@ -37,6 +38,9 @@ export const enum WSEvents {
     CHECK_FOR_UPDATES = "check-for-updates",
     INVALIDATE_QUERIES = "invalidate-queries",
     CONSOLE_LOG = "console-log",
+    WATCH_PARTY_UPDATED = "watch-party-updated",
+    WATCH_PARTY_PLAY_ONLINE_STREAM = "watch-party-play-online-stream",
+    WATCH_PARTY_PLAYER_COMMAND = "watch-party-player-command",
 }
 
 export const enum WebviewEvents {